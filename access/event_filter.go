@@ -0,0 +1,127 @@
+package access
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// EventFilter selects the events streamed alongside blocks by block
+// subscriptions. Events are retrieved by type, so only events with one of the
+// given EventTypes are ever considered. The optional Contracts and Accounts
+// constraints further narrow down the selection, and are therefore only valid
+// together with at least one event type.
+type EventFilter struct {
+	// EventTypes lists the event types to retrieve. If empty, blocks are streamed without events.
+	EventTypes []flow.EventType
+	// Contracts restricts events to those emitted by contracts deployed to one of the given addresses.
+	Contracts []flow.Address
+	// Accounts restricts events to those with a field referencing one of the given accounts.
+	Accounts []flow.Address
+}
+
+// Validate checks that the filter can be applied. Contract and account
+// constraints narrow down the retrieved event types, so a filter setting them
+// without any event type would silently stream all blocks without events.
+func (f EventFilter) Validate() error {
+	if len(f.EventTypes) == 0 && (len(f.Contracts) > 0 || len(f.Accounts) > 0) {
+		return errors.New("contract and account filters require at least one event type")
+	}
+	return nil
+}
+
+// Match returns true if the event satisfies all constraints of the filter.
+func (f EventFilter) Match(event flow.Event) bool {
+	if !f.matchType(event.Type) {
+		return false
+	}
+
+	if len(f.Contracts) > 0 {
+		address, ok := ContractAddress(event.Type)
+		if !ok || !containsAddress(f.Contracts, address) {
+			return false
+		}
+	}
+
+	if len(f.Accounts) > 0 {
+		value, err := jsoncdc.Decode(event.Payload)
+		if err != nil {
+			return false
+		}
+		if !referencesAddress(value, f.Accounts) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f EventFilter) matchType(eventType flow.EventType) bool {
+	for _, t := range f.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// ContractAddress returns the address of the contract which declares the given
+// event type. Event types declared by contracts have the form
+// A.<address>.<contract>.<event>; built-in event types have no address.
+func ContractAddress(eventType flow.EventType) (flow.Address, bool) {
+	parts := strings.Split(string(eventType), ".")
+	if len(parts) < 4 || parts[0] != "A" {
+		return flow.EmptyAddress, false
+	}
+	return flow.HexToAddress(parts[1]), true
+}
+
+func containsAddress(addresses []flow.Address, address flow.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+// referencesAddress walks the given Cadence value and returns true if any
+// (nested) address value equals one of the given addresses.
+func referencesAddress(value cadence.Value, addresses []flow.Address) bool {
+	switch v := value.(type) {
+	case cadence.Address:
+		return containsAddress(addresses, flow.Address(v))
+	case cadence.Optional:
+		return v.Value != nil && referencesAddress(v.Value, addresses)
+	case cadence.Array:
+		return anyReferencesAddress(v.Values, addresses)
+	case cadence.Dictionary:
+		for _, pair := range v.Pairs {
+			if referencesAddress(pair.Key, addresses) || referencesAddress(pair.Value, addresses) {
+				return true
+			}
+		}
+		return false
+	case cadence.Struct:
+		return anyReferencesAddress(v.Fields, addresses)
+	case cadence.Resource:
+		return anyReferencesAddress(v.Fields, addresses)
+	case cadence.Event:
+		return anyReferencesAddress(v.Fields, addresses)
+	default:
+		return false
+	}
+}
+
+func anyReferencesAddress(values []cadence.Value, addresses []flow.Address) bool {
+	for _, value := range values {
+		if referencesAddress(value, addresses) {
+			return true
+		}
+	}
+	return false
+}
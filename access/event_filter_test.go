@@ -0,0 +1,102 @@
+package access
+
+import (
+	"testing"
+
+	"github.com/onflow/cadence"
+	jsoncdc "github.com/onflow/cadence/encoding/json"
+	"github.com/onflow/cadence/runtime/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func depositEvent(t *testing.T, contract flow.Address, to *flow.Address) flow.Event {
+	var recipient cadence.Optional
+	if to != nil {
+		recipient = cadence.NewOptional(cadence.NewAddress(*to))
+	}
+
+	value := cadence.NewEvent([]cadence.Value{
+		cadence.NewUInt64(10),
+		recipient,
+	}).WithType(&cadence.EventType{
+		Location: common.AddressLocation{
+			Address: common.Address(contract),
+			Name:    "FungibleToken",
+		},
+		QualifiedIdentifier: "FungibleToken.Deposit",
+		Fields: []cadence.Field{
+			{Identifier: "amount", Type: cadence.UInt64Type{}},
+			{Identifier: "to", Type: cadence.OptionalType{Type: cadence.AddressType{}}},
+		},
+	})
+
+	payload, err := jsoncdc.Encode(value)
+	require.NoError(t, err)
+
+	return flow.Event{
+		Type:    flow.EventType("A." + contract.Hex() + ".FungibleToken.Deposit"),
+		Payload: payload,
+	}
+}
+
+func TestEventFilter(t *testing.T) {
+	contract := flow.HexToAddress("f233dcee88fe0abe")
+	other := flow.HexToAddress("1654653399040a61")
+	account := flow.HexToAddress("01cf0e2f2f715450")
+
+	event := depositEvent(t, contract, &account)
+
+	t.Run("type", func(t *testing.T) {
+		assert.True(t, EventFilter{EventTypes: []flow.EventType{event.Type}}.Match(event))
+		assert.False(t, EventFilter{EventTypes: []flow.EventType{flow.EventAccountCreated}}.Match(event))
+		assert.False(t, EventFilter{}.Match(event))
+	})
+
+	t.Run("contract", func(t *testing.T) {
+		filter := EventFilter{EventTypes: []flow.EventType{event.Type}, Contracts: []flow.Address{other, contract}}
+		assert.True(t, filter.Match(event))
+
+		filter.Contracts = []flow.Address{other}
+		assert.False(t, filter.Match(event))
+
+		// built-in events are not declared by any contract
+		builtin := flow.Event{Type: flow.EventAccountCreated}
+		filter = EventFilter{EventTypes: []flow.EventType{flow.EventAccountCreated}, Contracts: []flow.Address{contract}}
+		assert.False(t, filter.Match(builtin))
+	})
+
+	t.Run("account", func(t *testing.T) {
+		filter := EventFilter{EventTypes: []flow.EventType{event.Type}, Accounts: []flow.Address{account}}
+		assert.True(t, filter.Match(event))
+
+		filter.Accounts = []flow.Address{other}
+		assert.False(t, filter.Match(event))
+
+		// an empty optional does not reference any account
+		filter.Accounts = []flow.Address{account}
+		assert.False(t, filter.Match(depositEvent(t, contract, nil)))
+	})
+}
+
+func TestEventFilter_Validate(t *testing.T) {
+	address := flow.HexToAddress("f233dcee88fe0abe")
+
+	assert.NoError(t, EventFilter{}.Validate())
+	assert.NoError(t, EventFilter{EventTypes: []flow.EventType{flow.EventAccountCreated}, Accounts: []flow.Address{address}}.Validate())
+
+	// contract and account constraints only apply to retrieved event types
+	assert.Error(t, EventFilter{Contracts: []flow.Address{address}}.Validate())
+	assert.Error(t, EventFilter{Accounts: []flow.Address{address}}.Validate())
+}
+
+func TestContractAddress(t *testing.T) {
+	address, ok := ContractAddress("A.f233dcee88fe0abe.FungibleToken.Deposit")
+	require.True(t, ok)
+	assert.Equal(t, flow.HexToAddress("f233dcee88fe0abe"), address)
+
+	_, ok = ContractAddress(flow.EventAccountCreated)
+	assert.False(t, ok)
+}
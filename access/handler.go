@@ -223,6 +223,44 @@ func (h *Handler) GetTransactionResult(
 	return TransactionResultToMessage(result), nil
 }
 
+// SubscribeTransactionStatus streams the status transitions of a transaction
+// beyond the status given in the request, until the transaction is sealed or
+// expired. If the client falls behind, the stream is terminated with a
+// ResourceExhausted error and the client should resubscribe using the last
+// status it received as cursor.
+func (h *Handler) SubscribeTransactionStatus(
+	req *SubscribeTransactionStatusRequest,
+	stream AccessStreamAPI_SubscribeTransactionStatusServer,
+) error {
+	id, err := convert.TransactionID(req.GetId())
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	last := flow.TransactionStatus(req.GetLastStatus())
+	results, err := h.api.SubscribeTransactionStatus(ctx, id, last)
+	if err != nil {
+		return err
+	}
+
+	for result := range results {
+		err = stream.Send(TransactionResultToMessage(result))
+		if err != nil {
+			return err
+		}
+		last = result.Status
+	}
+
+	if last == flow.TransactionStatusSealed || last == flow.TransactionStatusExpired {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return status.Errorf(codes.ResourceExhausted, "subscription for transaction %x dropped, resubscribe from last received status", id)
+}
+
 // GetAccount returns an account by address at the latest sealed block.
 func (h *Handler) GetAccount(
 	ctx context.Context,
@@ -407,6 +445,65 @@ func (h *Handler) GetEventsForBlockIDs(
 	}, nil
 }
 
+// SubscribeBlocks streams finalized or sealed blocks starting at the requested
+// height, together with their events matching the requested filter. Streaming
+// continues with newly finalized or sealed blocks until the client cancels. If
+// the stream is terminated by the server, the client should resubscribe from
+// the height following the last block it received. Subscriptions from heights
+// which are not available, as they are below the root block or were pruned, are
+// rejected with codes.OutOfRange.
+func (h *Handler) SubscribeBlocks(
+	req *SubscribeBlocksRequest,
+	stream AccessStreamAPI_SubscribeBlocksServer,
+) error {
+	filter := EventFilter{}
+	for _, eventType := range req.GetEventTypes() {
+		filter.EventTypes = append(filter.EventTypes, flow.EventType(eventType))
+	}
+	for _, address := range req.GetContracts() {
+		contract, err := convert.Address(address, h.chain)
+		if err != nil {
+			return err
+		}
+		filter.Contracts = append(filter.Contracts, contract)
+	}
+	for _, address := range req.GetAccounts() {
+		account, err := convert.Address(address, h.chain)
+		if err != nil {
+			return err
+		}
+		filter.Accounts = append(filter.Accounts, account)
+	}
+
+	ctx := stream.Context()
+	blocks, err := h.api.SubscribeBlocks(ctx, req.GetStartHeight(), req.GetIsSealed(), filter)
+	if err != nil {
+		return err
+	}
+
+	next := req.GetStartHeight()
+	for block := range blocks {
+		msg, err := convert.BlockToMessage(block.Block)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+
+		err = stream.Send(&SubscribeBlocksResponse{
+			Block:  msg,
+			Events: convert.EventsToMessages(block.Events),
+		})
+		if err != nil {
+			return err
+		}
+		next = block.Block.Header.Height + 1
+	}
+
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	return status.Errorf(codes.Unavailable, "block subscription terminated, resubscribe from height %d", next)
+}
+
 // GetLatestProtocolStateSnapshot returns the latest serializable Snapshot
 func (h *Handler) GetLatestProtocolStateSnapshot(ctx context.Context, req *access.GetLatestProtocolStateSnapshotRequest) (*access.ProtocolStateSnapshotResponse, error) {
 	snapshot, err := h.api.GetLatestProtocolStateSnapshot(ctx)
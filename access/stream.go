@@ -0,0 +1,302 @@
+package access
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/onflow/flow/protobuf/go/flow/entities"
+	"google.golang.org/grpc"
+)
+
+// The streaming extension of the Access API is not (yet) part of the upstream
+// protobuf definitions, so the messages and the service descriptor below are
+// declared by hand. They follow the layout produced by protoc-gen-go and
+// protoc-gen-go-grpc, and are wire compatible with the following definition:
+//
+//	service AccessStreamAPI {
+//	  rpc SubscribeTransactionStatus(SubscribeTransactionStatusRequest)
+//	      returns (stream flow.access.TransactionResultResponse);
+//	  rpc SubscribeBlocks(SubscribeBlocksRequest)
+//	      returns (stream SubscribeBlocksResponse);
+//	}
+//
+//	message SubscribeTransactionStatusRequest {
+//	  bytes id = 1;
+//	  flow.entities.TransactionStatus last_status = 2;
+//	}
+//
+//	message SubscribeBlocksRequest {
+//	  uint64 start_height = 1;
+//	  bool is_sealed = 2;
+//	  repeated string event_types = 3;
+//	  repeated bytes contracts = 4;
+//	  repeated bytes accounts = 5;
+//	}
+//
+//	message SubscribeBlocksResponse {
+//	  flow.entities.Block block = 1;
+//	  repeated flow.entities.Event events = 2;
+//	}
+
+// SubscribeTransactionStatusRequest is the request for a transaction status
+// subscription. LastStatus is the resume cursor: only status transitions
+// strictly beyond it are streamed back to the client.
+type SubscribeTransactionStatusRequest struct {
+	Id         []byte                     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	LastStatus entities.TransactionStatus `protobuf:"varint,2,opt,name=last_status,json=lastStatus,proto3,enum=flow.entities.TransactionStatus" json:"last_status,omitempty"`
+}
+
+func (m *SubscribeTransactionStatusRequest) Reset()         { *m = SubscribeTransactionStatusRequest{} }
+func (m *SubscribeTransactionStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeTransactionStatusRequest) ProtoMessage()    {}
+
+func (m *SubscribeTransactionStatusRequest) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *SubscribeTransactionStatusRequest) GetLastStatus() entities.TransactionStatus {
+	if m != nil {
+		return m.LastStatus
+	}
+	return entities.TransactionStatus_UNKNOWN
+}
+
+// SubscribeBlocksRequest is the request for a block subscription. Blocks are
+// streamed starting at StartHeight, which also serves as resume cursor. If
+// IsSealed is set, only sealed blocks are streamed; otherwise finalized blocks
+// are streamed as soon as their events are available.
+type SubscribeBlocksRequest struct {
+	StartHeight uint64   `protobuf:"varint,1,opt,name=start_height,json=startHeight,proto3" json:"start_height,omitempty"`
+	IsSealed    bool     `protobuf:"varint,2,opt,name=is_sealed,json=isSealed,proto3" json:"is_sealed,omitempty"`
+	EventTypes  []string `protobuf:"bytes,3,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	Contracts   [][]byte `protobuf:"bytes,4,rep,name=contracts,proto3" json:"contracts,omitempty"`
+	Accounts    [][]byte `protobuf:"bytes,5,rep,name=accounts,proto3" json:"accounts,omitempty"`
+}
+
+func (m *SubscribeBlocksRequest) Reset()         { *m = SubscribeBlocksRequest{} }
+func (m *SubscribeBlocksRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeBlocksRequest) ProtoMessage()    {}
+
+func (m *SubscribeBlocksRequest) GetStartHeight() uint64 {
+	if m != nil {
+		return m.StartHeight
+	}
+	return 0
+}
+
+func (m *SubscribeBlocksRequest) GetIsSealed() bool {
+	if m != nil {
+		return m.IsSealed
+	}
+	return false
+}
+
+func (m *SubscribeBlocksRequest) GetEventTypes() []string {
+	if m != nil {
+		return m.EventTypes
+	}
+	return nil
+}
+
+func (m *SubscribeBlocksRequest) GetContracts() [][]byte {
+	if m != nil {
+		return m.Contracts
+	}
+	return nil
+}
+
+func (m *SubscribeBlocksRequest) GetAccounts() [][]byte {
+	if m != nil {
+		return m.Accounts
+	}
+	return nil
+}
+
+// SubscribeBlocksResponse is a single block streamed by a block subscription,
+// together with its events matching the subscription's filter.
+type SubscribeBlocksResponse struct {
+	Block  *entities.Block   `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	Events []*entities.Event `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
+}
+
+func (m *SubscribeBlocksResponse) Reset()         { *m = SubscribeBlocksResponse{} }
+func (m *SubscribeBlocksResponse) String() string { return proto.CompactTextString(m) }
+func (*SubscribeBlocksResponse) ProtoMessage()    {}
+
+func (m *SubscribeBlocksResponse) GetBlock() *entities.Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *SubscribeBlocksResponse) GetEvents() []*entities.Event {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+// AccessStreamAPIClient is the client API for AccessStreamAPI service.
+type AccessStreamAPIClient interface {
+	// SubscribeTransactionStatus streams the status transitions of a transaction
+	// until it is sealed or expired.
+	SubscribeTransactionStatus(ctx context.Context, in *SubscribeTransactionStatusRequest, opts ...grpc.CallOption) (AccessStreamAPI_SubscribeTransactionStatusClient, error)
+	// SubscribeBlocks streams finalized or sealed blocks together with their
+	// events matching the filter, starting at the given height.
+	SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (AccessStreamAPI_SubscribeBlocksClient, error)
+}
+
+type accessStreamAPIClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAccessStreamAPIClient(cc grpc.ClientConnInterface) AccessStreamAPIClient {
+	return &accessStreamAPIClient{cc}
+}
+
+func (c *accessStreamAPIClient) SubscribeTransactionStatus(ctx context.Context, in *SubscribeTransactionStatusRequest, opts ...grpc.CallOption) (AccessStreamAPI_SubscribeTransactionStatusClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AccessStreamAPI_ServiceDesc.Streams[0], "/flow.access.AccessStreamAPI/SubscribeTransactionStatus", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &accessStreamAPISubscribeTransactionStatusClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AccessStreamAPI_SubscribeTransactionStatusClient interface {
+	Recv() (*access.TransactionResultResponse, error)
+	grpc.ClientStream
+}
+
+type accessStreamAPISubscribeTransactionStatusClient struct {
+	grpc.ClientStream
+}
+
+func (x *accessStreamAPISubscribeTransactionStatusClient) Recv() (*access.TransactionResultResponse, error) {
+	m := new(access.TransactionResultResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *accessStreamAPIClient) SubscribeBlocks(ctx context.Context, in *SubscribeBlocksRequest, opts ...grpc.CallOption) (AccessStreamAPI_SubscribeBlocksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AccessStreamAPI_ServiceDesc.Streams[1], "/flow.access.AccessStreamAPI/SubscribeBlocks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &accessStreamAPISubscribeBlocksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AccessStreamAPI_SubscribeBlocksClient interface {
+	Recv() (*SubscribeBlocksResponse, error)
+	grpc.ClientStream
+}
+
+type accessStreamAPISubscribeBlocksClient struct {
+	grpc.ClientStream
+}
+
+func (x *accessStreamAPISubscribeBlocksClient) Recv() (*SubscribeBlocksResponse, error) {
+	m := new(SubscribeBlocksResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// AccessStreamAPIServer is the server API for AccessStreamAPI service.
+type AccessStreamAPIServer interface {
+	// SubscribeTransactionStatus streams the status transitions of a transaction
+	// until it is sealed or expired.
+	SubscribeTransactionStatus(*SubscribeTransactionStatusRequest, AccessStreamAPI_SubscribeTransactionStatusServer) error
+	// SubscribeBlocks streams finalized or sealed blocks together with their
+	// events matching the filter, starting at the given height.
+	SubscribeBlocks(*SubscribeBlocksRequest, AccessStreamAPI_SubscribeBlocksServer) error
+}
+
+func RegisterAccessStreamAPIServer(s grpc.ServiceRegistrar, srv AccessStreamAPIServer) {
+	s.RegisterService(&AccessStreamAPI_ServiceDesc, srv)
+}
+
+func _AccessStreamAPI_SubscribeTransactionStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeTransactionStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AccessStreamAPIServer).SubscribeTransactionStatus(m, &accessStreamAPISubscribeTransactionStatusServer{stream})
+}
+
+type AccessStreamAPI_SubscribeTransactionStatusServer interface {
+	Send(*access.TransactionResultResponse) error
+	grpc.ServerStream
+}
+
+type accessStreamAPISubscribeTransactionStatusServer struct {
+	grpc.ServerStream
+}
+
+func (x *accessStreamAPISubscribeTransactionStatusServer) Send(m *access.TransactionResultResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AccessStreamAPI_SubscribeBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AccessStreamAPIServer).SubscribeBlocks(m, &accessStreamAPISubscribeBlocksServer{stream})
+}
+
+type AccessStreamAPI_SubscribeBlocksServer interface {
+	Send(*SubscribeBlocksResponse) error
+	grpc.ServerStream
+}
+
+type accessStreamAPISubscribeBlocksServer struct {
+	grpc.ServerStream
+}
+
+func (x *accessStreamAPISubscribeBlocksServer) Send(m *SubscribeBlocksResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// AccessStreamAPI_ServiceDesc is the grpc.ServiceDesc for AccessStreamAPI service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AccessStreamAPI_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "flow.access.AccessStreamAPI",
+	HandlerType: (*AccessStreamAPIServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeTransactionStatus",
+			Handler:       _AccessStreamAPI_SubscribeTransactionStatus_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeBlocks",
+			Handler:       _AccessStreamAPI_SubscribeBlocks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "flow/access/access_stream.proto",
+}
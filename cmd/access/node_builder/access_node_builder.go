@@ -38,10 +38,10 @@ import (
 	"github.com/onflow/flow-go/module/id"
 	"github.com/onflow/flow-go/module/mempool/stdmap"
 	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/pruner"
 	"github.com/onflow/flow-go/module/signature"
 	"github.com/onflow/flow-go/module/synchronization"
 	"github.com/onflow/flow-go/network"
-	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
 	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/network/validator"
 	"github.com/onflow/flow-go/state/protocol"
@@ -101,6 +101,9 @@ type AccessNodeConfig struct {
 	logTxTimeToFinalizedExecuted bool
 	retryEnabled                 bool
 	rpcMetricsEnabled            bool
+	protocolPruningRetention     uint64 // number of finalized blocks to keep, 0 disables pruning
+	protocolPruningBatchSize     uint
+	protocolPruningInterval      time.Duration
 	baseOptions                  []cmd.Option
 }
 
@@ -135,6 +138,9 @@ func DefaultAccessNodeConfig() *AccessNodeConfig {
 		bootstrapNodeAddresses:       []string{},
 		bootstrapNodePublicKeys:      []string{},
 		supportsUnstakedFollower:     false,
+		protocolPruningRetention:     0,
+		protocolPruningBatchSize:     pruner.DefaultBatchSize,
+		protocolPruningInterval:      pruner.DefaultInterval,
 	}
 }
 
@@ -248,7 +254,7 @@ func (builder *FlowAccessNodeBuilder) buildFollowerCore() *FlowAccessNodeBuilder
 		merger := signature.NewCombiner(encodable.ConsensusVoteSigLen, encodable.RandomBeaconSigLen)
 
 		// initialize the verifier for the protocol consensus
-		verifier := verification.NewCombinedVerifier(builder.Committee, staking, beacon, merger)
+		verifier := verification.NewCombinedVerifier(builder.Committee, staking, beacon, merger, builder.RootChainID)
 
 		followerCore, err := consensus.NewFollower(node.Logger, builder.Committee, node.Storage.Headers, final, verifier,
 			builder.FinalizationDistributor, node.RootBlock.Header, node.RootQC, builder.Finalized, builder.Pending)
@@ -332,6 +338,31 @@ func (builder *FlowAccessNodeBuilder) buildSyncEngine() *FlowAccessNodeBuilder {
 	return builder
 }
 
+func (builder *FlowAccessNodeBuilder) buildProtocolPruner() *FlowAccessNodeBuilder {
+	builder.Component("protocol state pruner", func(_ cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+		if builder.protocolPruningRetention == 0 {
+			return &module.NoopReadDoneAware{}, nil
+		}
+
+		headers, ok := node.Storage.Headers.(pruner.Headers)
+		if !ok {
+			return nil, fmt.Errorf("header storage does not support pruning")
+		}
+
+		return pruner.New(
+			node.Logger,
+			node.DB,
+			node.State,
+			headers,
+			builder.protocolPruningRetention,
+			builder.protocolPruningBatchSize,
+			builder.protocolPruningInterval,
+		), nil
+	})
+
+	return builder
+}
+
 func (builder *FlowAccessNodeBuilder) BuildConsensusFollower() AccessNodeBuilder {
 	builder.
 		buildFollowerState().
@@ -341,7 +372,8 @@ func (builder *FlowAccessNodeBuilder) BuildConsensusFollower() AccessNodeBuilder
 		buildFollowerCore().
 		buildFollowerEngine().
 		buildFinalizedHeader().
-		buildSyncEngine()
+		buildSyncEngine().
+		buildProtocolPruner()
 
 	return builder
 }
@@ -516,6 +548,14 @@ func WithBaseOptions(baseOptions []cmd.Option) Option {
 	}
 }
 
+// WithProtocolPruning enables pruning of the finalized blocks which are more
+// than the given number of blocks below the latest finalized block.
+func WithProtocolPruning(retention uint64) Option {
+	return func(config *AccessNodeConfig) {
+		config.protocolPruningRetention = retention
+	}
+}
+
 func FlowAccessNode(opts ...Option) *FlowAccessNodeBuilder {
 	config := DefaultAccessNodeConfig()
 	for _, opt := range opts {
@@ -572,6 +612,9 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 		flags.StringSliceVar(&builder.bootstrapNodeAddresses, "bootstrap-node-addresses", defaultConfig.bootstrapNodeAddresses, "the network addresses of the bootstrap access node if this is an unstaked access node e.g. access-001.mainnet.flow.org:9653,access-002.mainnet.flow.org:9653")
 		flags.StringSliceVar(&builder.bootstrapNodePublicKeys, "bootstrap-node-public-keys", defaultConfig.bootstrapNodePublicKeys, "the networking public key of the bootstrap access node if this is an unstaked access node (in the same order as the bootstrap node addresses) e.g. \"d57a5e9c5.....\",\"44ded42d....\"")
 		flags.BoolVar(&builder.supportsUnstakedFollower, "supports-unstaked-node", defaultConfig.supportsUnstakedFollower, "true if this staked access node supports unstaked node")
+		flags.Uint64Var(&builder.protocolPruningRetention, "protocol-pruning-retention", defaultConfig.protocolPruningRetention, "number of finalized blocks to keep when pruning the protocol state, 0 disables pruning")
+		flags.UintVar(&builder.protocolPruningBatchSize, "protocol-pruning-batch-size", defaultConfig.protocolPruningBatchSize, "maximum number of blocks to prune in one run of the protocol state pruner")
+		flags.DurationVar(&builder.protocolPruningInterval, "protocol-pruning-interval", defaultConfig.protocolPruningInterval, "interval between runs of the protocol state pruner")
 	})
 }
 
@@ -584,7 +627,10 @@ func (builder *FlowAccessNodeBuilder) initNetwork(nodeID module.Local,
 	topology network.Topology,
 ) (*p2p.Network, error) {
 
-	codec := cborcodec.NewCodec()
+	codec, err := builder.NetworkCodec(networkMetrics)
+	if err != nil {
+		return nil, fmt.Errorf("could not create network codec: %w", err)
+	}
 
 	// creates network instance
 	net, err := p2p.NewNetwork(
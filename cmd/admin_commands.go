@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/admin"
+	"github.com/onflow/flow-go/module/backup"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/trace"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/storage"
+)
+
+// setLogLevelCommand changes the level of the logs of the node, with the new level
+// passed as `level` in the input data.
+func setLogLevelCommand(_ context.Context, data map[string]interface{}) (interface{}, error) {
+	level, ok := data["level"].(string)
+	if !ok {
+		return nil, fmt.Errorf("the new log level must be passed as a string with key level")
+	}
+
+	lvl, err := zerolog.ParseLevel(strings.ToLower(level))
+	if err != nil {
+		return nil, fmt.Errorf("invalid log level: %w", err)
+	}
+
+	previous := zerolog.GlobalLevel()
+	zerolog.SetGlobalLevel(lvl)
+
+	return fmt.Sprintf("log level changed from %s to %s", previous, lvl), nil
+}
+
+// mempoolSizesCommand returns the command listing the number of entries of the
+// mempools registered with the given collector.
+func mempoolSizesCommand(mempools *metrics.MempoolCollector) admin.CommandHandler {
+	return func(context.Context, map[string]interface{}) (interface{}, error) {
+		return mempools.Entries(), nil
+	}
+}
+
+// setTracingCommand returns the command pausing or resuming the given tracer, as
+// passed as boolean `enabled` in the input data.
+func setTracingCommand(tracer *trace.OpenTracer) admin.CommandHandler {
+	return func(_ context.Context, data map[string]interface{}) (interface{}, error) {
+		enabled, ok := data["enabled"].(bool)
+		if !ok {
+			return nil, fmt.Errorf("whether to enable tracing must be passed as a boolean with key enabled")
+		}
+
+		tracer.SetEnabled(enabled)
+
+		return fmt.Sprintf("tracing enabled: %v", enabled), nil
+	}
+}
+
+// connectedPeersCommand returns the command listing the peers the given middleware
+// is connected to, by peer ID, with the IDs of the Flow nodes they belong to.
+func connectedPeersCommand(mw *p2p.Middleware) admin.CommandHandler {
+	return func(context.Context, map[string]interface{}) (interface{}, error) {
+		peers := make(map[string]string)
+		for pid, fid := range mw.ConnectedPeers() {
+			peers[pid.String()] = fid.String()
+		}
+		return peers, nil
+	}
+}
+
+// BackupCommand returns the command backing up the given database, and the given
+// ledger if not nil, into the directory passed as `dir` in the input data. It outputs
+// the manifest of the backup.
+func BackupCommand(db *badger.DB, ledger backup.Ledger) admin.CommandHandler {
+	return func(_ context.Context, data map[string]interface{}) (interface{}, error) {
+		dir, ok := data["dir"].(string)
+		if !ok || dir == "" {
+			return nil, fmt.Errorf("the backup directory must be passed as a string with key dir")
+		}
+
+		manifest, err := backup.Create(db, ledger, dir)
+		if err != nil {
+			return nil, fmt.Errorf("could not create backup: %w", err)
+		}
+
+		return manifest, nil
+	}
+}
+
+// SlashingEvidenceCommand returns the command listing the stored evidence of
+// slashable protocol violations, optionally only of the offender passed as
+// hex string `offender_id` in the input data.
+func SlashingEvidenceCommand(evidence storage.SlashingEvidence) admin.CommandHandler {
+	return func(_ context.Context, data map[string]interface{}) (interface{}, error) {
+		id, ok := data["offender_id"]
+		if !ok {
+			return evidence.All()
+		}
+
+		hex, ok := id.(string)
+		if !ok {
+			return nil, fmt.Errorf("the ID of the offender must be passed as a hex string with key offender_id")
+		}
+		offenderID, err := flow.HexStringToIdentifier(hex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid offender ID: %w", err)
+		}
+		return evidence.ByOffender(offenderID)
+	}
+}
@@ -19,7 +19,7 @@ import (
 
 func GenerateClusterRootQC(participants []bootstrap.NodeInfo, clusterBlock *cluster.Block) (*flow.QuorumCertificate, error) {
 
-	validators, signers, err := createClusterValidators(participants)
+	validators, signers, err := createClusterValidators(participants, clusterBlock.Header.ChainID)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +54,7 @@ func GenerateClusterRootQC(participants []bootstrap.NodeInfo, clusterBlock *clus
 	return qc, err
 }
 
-func createClusterValidators(participants []bootstrap.NodeInfo) ([]hotstuff.Validator, []hotstuff.SignerVerifier, error) {
+func createClusterValidators(participants []bootstrap.NodeInfo, chainID flow.ChainID) ([]hotstuff.Validator, []hotstuff.SignerVerifier, error) {
 
 	n := len(participants)
 	identities := bootstrap.ToIdentityList(participants)
@@ -84,7 +84,7 @@ func createClusterValidators(participants []bootstrap.NodeInfo) ([]hotstuff.Vali
 
 		// create signer for participant
 		provider := signature.NewAggregationProvider(encoding.CollectorVoteTag, me)
-		signer := verification.NewSingleSignerVerifier(committee, provider, participant.NodeID)
+		signer := verification.NewSingleSignerVerifier(committee, provider, participant.NodeID, chainID)
 		signers[i] = signer
 
 		// create validator
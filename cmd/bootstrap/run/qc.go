@@ -40,7 +40,7 @@ func (pd *ParticipantData) Identities() flow.IdentityList {
 
 func GenerateRootQC(block *flow.Block, participantData *ParticipantData) (*flow.QuorumCertificate, error) {
 
-	validators, signers, err := createValidators(participantData)
+	validators, signers, err := createValidators(participantData, block.Header.ChainID)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +68,7 @@ func GenerateRootQC(block *flow.Block, participantData *ParticipantData) (*flow.
 	return qc, err
 }
 
-func createValidators(participantData *ParticipantData) ([]hotstuff.Validator, []hotstuff.SignerVerifier, error) {
+func createValidators(participantData *ParticipantData, chainID flow.ChainID) ([]hotstuff.Validator, []hotstuff.SignerVerifier, error) {
 	n := len(participantData.Participants)
 	identities := participantData.Identities()
 
@@ -106,7 +106,7 @@ func createValidators(participantData *ParticipantData) ([]hotstuff.Validator, [
 		beaconVerifier := signature.NewThresholdVerifier(encoding.RandomBeaconTag)
 		beaconSigner := signature.NewThresholdProvider(encoding.RandomBeaconTag, participant.RandomBeaconPrivKey)
 		beaconStore := signature.NewSingleSignerStore(beaconSigner)
-		signer := verification.NewCombinedSigner(committee, stakingSigner, beaconVerifier, merger, beaconStore, participant.NodeID)
+		signer := verification.NewCombinedSigner(committee, stakingSigner, beaconVerifier, merger, beaconStore, participant.NodeID, chainID)
 		signers[i] = signer
 
 		// create validator
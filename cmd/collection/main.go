@@ -32,6 +32,7 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/admin"
 	"github.com/onflow/flow-go/module/buffer"
 	builder "github.com/onflow/flow-go/module/builder/collection"
 	"github.com/onflow/flow-go/module/epochs"
@@ -47,6 +48,7 @@ import (
 	badgerState "github.com/onflow/flow-go/state/protocol/badger"
 	"github.com/onflow/flow-go/state/protocol/blocktimer"
 	"github.com/onflow/flow-go/state/protocol/events/gadgets"
+	"github.com/onflow/flow-go/storage"
 	storagekv "github.com/onflow/flow-go/storage/badger"
 )
 
@@ -54,6 +56,7 @@ func main() {
 
 	var (
 		txLimit                                uint
+		txAgingPeriod                          time.Duration
 		maxCollectionSize                      uint
 		maxCollectionByteSize                  uint64
 		maxCollectionTotalGas                  uint64
@@ -65,6 +68,9 @@ func main() {
 		hotstuffTimeoutIncreaseFactor          float64
 		hotstuffTimeoutDecreaseFactor          float64
 		hotstuffTimeoutVoteAggregationFraction float64
+		hotstuffAdaptiveTimeout                bool
+		hotstuffAdaptiveMinTimeout             time.Duration
+		hotstuffTCActivationView               uint64
 		blockRateDelay                         time.Duration
 
 		followerState protocol.MutableState
@@ -72,6 +78,7 @@ func main() {
 		ingressConf   ingress.Config
 
 		pools                   *epochpool.TransactionPools // epoch-scoped transaction pools
+		txJournal               storage.TransactionJournal  // journal of the transactions in the pools
 		followerBuffer          *buffer.PendingBlocks       // pending block cache for follower
 		finalizationDistributor *pubsub.FinalizationDistributor
 		finalizedHeader         *consync.FinalizedHeaderCache
@@ -94,6 +101,8 @@ func main() {
 		ExtraFlags(func(flags *pflag.FlagSet) {
 			flags.UintVar(&txLimit, "tx-limit", 50000,
 				"maximum number of transactions in the memory pool")
+			flags.DurationVar(&txAgingPeriod, "tx-aging-period", stdmap.DefaultTransactionAgingPeriod,
+				"period over which the priority of a transaction in the memory pool grows by the priority of a transaction with the maximum gas limit, 0 disables aging")
 			flags.StringVarP(&ingressConf.ListenAddr, "ingress-addr", "i", "localhost:9000",
 				"the address the ingress server listens on")
 			flags.BoolVar(&ingressConf.RpcMetricsEnabled, "rpc-metrics-enabled", false,
@@ -137,6 +146,13 @@ func main() {
 			flags.Float64Var(&hotstuffTimeoutVoteAggregationFraction, "hotstuff-timeout-vote-aggregation-fraction",
 				timeout.DefaultConfig.VoteAggregationTimeoutFraction,
 				"additional fraction of replica timeout that the primary will wait for votes")
+			flags.BoolVar(&hotstuffAdaptiveTimeout, "hotstuff-adaptive-timeout", false,
+				"derive the hotstuff pacemaker timeout from the observed latency of proposals and QCs")
+			flags.DurationVar(&hotstuffAdaptiveMinTimeout, "hotstuff-adaptive-min-timeout",
+				time.Duration(timeout.DefaultAdaptiveConfig.MinReplicaTimeout)*time.Millisecond,
+				"the lower timeout bound for the adaptive hotstuff pacemaker timeout")
+			flags.Uint64Var(&hotstuffTCActivationView, "hotstuff-tc-activation-view", consensus.TCActivationDisabled,
+				"the first view in which replicas leave a view only on a QC or timeout certificate; must be the same for all collection nodes")
 			flags.DurationVar(&blockRateDelay, "block-rate-delay", 250*time.Millisecond,
 				"the delay to broadcast block proposal in order to control block production rate")
 
@@ -164,7 +180,11 @@ func main() {
 			return err
 		}).
 		Module("transactions mempool", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) error {
-			create := func() mempool.Transactions { return stdmap.NewTransactions(txLimit) }
+			txJournal = storagekv.NewTransactionJournal(node.DB)
+			create := func(epoch uint64) mempool.Transactions {
+				pool := stdmap.NewPriorityTransactions(txLimit, stdmap.WithAgingPeriod(txAgingPeriod))
+				return epochpool.NewJournaledTransactions(node.Logger, epoch, pool, txJournal)
+			}
 			pools = epochpool.NewTransactionPools(create)
 			err := node.Metrics.Mempool.Register(metrics.ResourceTransaction, pools.CombinedSize)
 			return err
@@ -204,7 +224,7 @@ func main() {
 			}
 
 			// initialize the verifier for the protocol consensus
-			verifier := verification.NewCombinedVerifier(mainConsensusCommittee, staking, beacon, merger)
+			verifier := verification.NewCombinedVerifier(mainConsensusCommittee, staking, beacon, merger, node.RootChainID)
 
 			finalizationDistributor = pubsub.NewFinalizationDistributor()
 
@@ -288,6 +308,7 @@ func main() {
 				node.Me,
 				node.RootChainID.Chain(),
 				pools,
+				txJournal,
 				ingestConf,
 			)
 			return ing, err
@@ -380,6 +401,30 @@ func main() {
 			createMetrics := func(chainID flow.ChainID) module.HotstuffMetrics {
 				return metrics.NewHotstuffCollector(chainID)
 			}
+			opts := []consensus.Option{
+				consensus.WithBlockRateDelay(blockRateDelay),
+				consensus.WithInitialTimeout(hotstuffTimeout),
+				consensus.WithMinTimeout(hotstuffMinTimeout),
+				consensus.WithVoteAggregationTimeoutFraction(hotstuffTimeoutVoteAggregationFraction),
+				consensus.WithTimeoutIncreaseFactor(hotstuffTimeoutIncreaseFactor),
+				consensus.WithTimeoutDecreaseFactor(hotstuffTimeoutDecreaseFactor),
+				consensus.WithTCActivationView(hotstuffTCActivationView),
+			}
+			if hotstuffAdaptiveTimeout {
+				adaptive, err := timeout.NewAdaptiveConfig(
+					timeout.DefaultAdaptiveConfig.Percentile,
+					timeout.DefaultAdaptiveConfig.Multiplier,
+					timeout.DefaultAdaptiveConfig.WindowSize,
+					timeout.DefaultAdaptiveConfig.MinSamples,
+					hotstuffAdaptiveMinTimeout,
+					hotstuffTimeout,
+				)
+				if err != nil {
+					return nil, fmt.Errorf("invalid adaptive timeout config: %w", err)
+				}
+				opts = append(opts, consensus.WithAdaptiveTimeout(adaptive))
+			}
+
 			staking := signature.NewAggregationProvider(encoding.CollectorVoteTag, node.Me)
 			hotstuffFactory, err := factories.NewHotStuffFactory(
 				node.Logger,
@@ -387,19 +432,17 @@ func main() {
 				staking,
 				node.DB,
 				node.State,
+				storagekv.NewSlashingEvidence(node.DB),
 				createMetrics,
-				consensus.WithBlockRateDelay(blockRateDelay),
-				consensus.WithInitialTimeout(hotstuffTimeout),
-				consensus.WithMinTimeout(hotstuffMinTimeout),
-				consensus.WithVoteAggregationTimeoutFraction(hotstuffTimeoutVoteAggregationFraction),
-				consensus.WithTimeoutIncreaseFactor(hotstuffTimeoutIncreaseFactor),
-				consensus.WithTimeoutDecreaseFactor(hotstuffTimeoutDecreaseFactor),
+				opts...,
 			)
 			if err != nil {
 				return nil, err
 			}
 
-			signer := verification.NewSingleSigner(staking, node.Me.NodeID())
+			// the signer only votes for the root blocks of the cluster chains, and
+			// never times out, so the chain ID of its timeouts is irrelevant
+			signer := verification.NewSingleSigner(staking, node.Me.NodeID(), node.RootChainID)
 
 			// create flow client with correct GRPC configuration for QC contract client
 			var flowClient *client.Client
@@ -478,6 +521,9 @@ func main() {
 
 			return manager, err
 		}).
+		AdminCommand("list-slashing-evidence", func(node *cmd.NodeConfig) admin.CommandHandler {
+			return cmd.SlashingEvidenceCommand(storagekv.NewSlashingEvidence(node.DB))
+		}).
 		Run()
 }
 
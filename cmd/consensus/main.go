@@ -3,6 +3,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -21,6 +22,7 @@ import (
 	"github.com/onflow/flow-go/consensus/hotstuff"
 	"github.com/onflow/flow-go/consensus/hotstuff/blockproducer"
 	"github.com/onflow/flow-go/consensus/hotstuff/committees"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
 	"github.com/onflow/flow-go/consensus/hotstuff/notifications/pubsub"
 	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
 	"github.com/onflow/flow-go/consensus/hotstuff/persister"
@@ -30,6 +32,7 @@ import (
 	"github.com/onflow/flow-go/engine/common/requester"
 	synceng "github.com/onflow/flow-go/engine/common/synchronization"
 	"github.com/onflow/flow-go/engine/consensus/approvals/tracker"
+	"github.com/onflow/flow-go/engine/consensus/challenges"
 	"github.com/onflow/flow-go/engine/consensus/compliance"
 	dkgeng "github.com/onflow/flow-go/engine/consensus/dkg"
 	"github.com/onflow/flow-go/engine/consensus/ingestion"
@@ -45,6 +48,7 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/admin"
 	"github.com/onflow/flow-go/module/buffer"
 	builder "github.com/onflow/flow-go/module/builder/consensus"
 	chmodule "github.com/onflow/flow-go/module/chunks"
@@ -85,11 +89,15 @@ func main() {
 		hotstuffTimeoutIncreaseFactor          float64
 		hotstuffTimeoutDecreaseFactor          float64
 		hotstuffTimeoutVoteAggregationFraction float64
+		hotstuffAdaptiveTimeout                bool
+		hotstuffAdaptiveMinTimeout             time.Duration
+		hotstuffTCActivationView               uint64
 		blockRateDelay                         time.Duration
 		chunkAlpha                             uint
 		requiredApprovalsForSealVerification   uint
 		requiredApprovalsForSealConstruction   uint
 		emergencySealing                       bool
+		chunkChallengeExpiry                   uint64
 
 		// DKG contract client
 		accessAddress      string
@@ -102,6 +110,9 @@ func main() {
 		guarantees              mempool.Guarantees
 		receipts                mempool.ExecutionTree
 		seals                   mempool.IncorporatedResultSeals
+		chunkChallenges         storage.ChunkChallenges
+		challengeSuppressor     *consensusMempools.ChallengeSuppressor
+		challengesEng           *challenges.Engine
 		pendingReceipts         mempool.PendingReceipts
 		prov                    *provider.Engine
 		receiptRequester        *requester.Engine
@@ -135,11 +146,15 @@ func main() {
 			flags.Float64Var(&hotstuffTimeoutIncreaseFactor, "hotstuff-timeout-increase-factor", timeout.DefaultConfig.TimeoutIncrease, "multiplicative increase of timeout value in case of time out event")
 			flags.Float64Var(&hotstuffTimeoutDecreaseFactor, "hotstuff-timeout-decrease-factor", timeout.DefaultConfig.TimeoutDecrease, "multiplicative decrease of timeout value in case of progress")
 			flags.Float64Var(&hotstuffTimeoutVoteAggregationFraction, "hotstuff-timeout-vote-aggregation-fraction", 0.6, "additional fraction of replica timeout that the primary will wait for votes")
+			flags.BoolVar(&hotstuffAdaptiveTimeout, "hotstuff-adaptive-timeout", false, "derive the hotstuff pacemaker timeout from the observed latency of proposals and QCs")
+			flags.DurationVar(&hotstuffAdaptiveMinTimeout, "hotstuff-adaptive-min-timeout", time.Duration(timeout.DefaultAdaptiveConfig.MinReplicaTimeout)*time.Millisecond, "the lower timeout bound for the adaptive hotstuff pacemaker timeout")
+			flags.Uint64Var(&hotstuffTCActivationView, "hotstuff-tc-activation-view", consensus.TCActivationDisabled, "the first view in which replicas leave a view only on a QC or timeout certificate; must be the same for all consensus nodes")
 			flags.DurationVar(&blockRateDelay, "block-rate-delay", 500*time.Millisecond, "the delay to broadcast block proposal in order to control block production rate")
 			flags.UintVar(&chunkAlpha, "chunk-alpha", chmodule.DefaultChunkAssignmentAlpha, "number of verifiers that should be assigned to each chunk")
 			flags.UintVar(&requiredApprovalsForSealVerification, "required-verification-seal-approvals", validation.DefaultRequiredApprovalsForSealValidation, "minimum number of approvals that are required to verify a seal")
 			flags.UintVar(&requiredApprovalsForSealConstruction, "required-construction-seal-approvals", sealing.DefaultRequiredApprovalsForSealConstruction, "minimum number of approvals that are required to construct a seal")
 			flags.BoolVar(&emergencySealing, "emergency-sealing-active", sealing.DefaultEmergencySealingActive, "(de)activation of emergency sealing")
+			flags.Uint64Var(&chunkChallengeExpiry, "chunk-challenge-expiry", challenges.DefaultChallengeExpiry, "number of finalized blocks above a challenged block after which unresolved chunk challenges expire and sealing resumes")
 			flags.StringVar(&accessAddress, "access-address", "", "the address of an access node")
 			flags.StringVar(&secureAccessNodeID, "secure-access-node-id", "", "the node ID of the secure access GRPC server")
 			flags.BoolVar(&insecureAccessAPI, "insecure-access-api", true, "required if insecure GRPC connection should be used")
@@ -316,6 +331,13 @@ func main() {
 			if err != nil {
 				return fmt.Errorf("failed to wrap seals mempool into ExecStateForkSuppressor: %w", err)
 			}
+			// withhold the seals of results challenged by verification nodes
+			chunkChallenges = bstorage.NewChunkChallenges(node.DB)
+			challengeSuppressor, err = consensusMempools.NewChallengeSuppressor(seals, chunkChallenges, node.Logger)
+			if err != nil {
+				return fmt.Errorf("failed to wrap seals mempool into ChallengeSuppressor: %w", err)
+			}
+			seals = challengeSuppressor
 			err = node.Metrics.Mempool.Register(metrics.ResourcePendingIncorporatedSeal, seals.Size)
 			return nil
 		}).
@@ -437,6 +459,54 @@ func main() {
 			)
 			return prov, err
 		}).
+		Component("challenges engine", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			challengeSigVerifier := signature.NewAggregationVerifier(encoding.ChunkChallengeTag)
+			challengesEng, err = challenges.New(
+				node.Logger,
+				node.Network,
+				node.State,
+				node.Storage.Headers,
+				node.Storage.Index,
+				node.Me,
+				challengeSigVerifier,
+				chunkAssigner,
+				chunkChallenges,
+				challengeSuppressor,
+				chunkChallengeExpiry,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			finalizationDistributor.AddOnBlockFinalizedConsumer(challengesEng.OnFinalizedBlock)
+
+			return challengesEng, nil
+		}).
+		AdminCommand("list-chunk-challenges", func(node *cmd.NodeConfig) admin.CommandHandler {
+			return func(context.Context, map[string]interface{}) (interface{}, error) {
+				return challenges.Summarize(challengesEng.Unresolved()), nil
+			}
+		}).
+		AdminCommand("resolve-chunk-challenge", func(node *cmd.NodeConfig) admin.CommandHandler {
+			return func(_ context.Context, data map[string]interface{}) (interface{}, error) {
+				id, ok := data["challenge_id"].(string)
+				if !ok {
+					return nil, fmt.Errorf("the ID of the chunk challenge must be passed as a hex string with key challenge_id")
+				}
+				challengeID, err := flow.HexStringToIdentifier(id)
+				if err != nil {
+					return nil, fmt.Errorf("invalid chunk challenge ID: %w", err)
+				}
+				err = challengesEng.Resolve(challengeID)
+				if err != nil {
+					return nil, err
+				}
+				return fmt.Sprintf("chunk challenge %x resolved", challengeID), nil
+			}
+		}).
+		AdminCommand("list-slashing-evidence", func(node *cmd.NodeConfig) admin.CommandHandler {
+			return cmd.SlashingEvidenceCommand(bstorage.NewSlashingEvidence(node.DB))
+		}).
 		Component("ingestion engine", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			ing, err := ingestion.New(
 				node.Logger,
@@ -553,6 +623,7 @@ func main() {
 				merger,
 				thresholdSignerStore,
 				node.NodeID,
+				node.RootChainID,
 			)
 			signer = verification.NewMetricsWrapper(signer, mainMetrics) // wrapper for measuring time spent with crypto-related operations
 
@@ -567,6 +638,10 @@ func main() {
 
 			notifier.AddConsumer(finalizationDistributor)
 
+			// persist the evidence of slashable protocol violations
+			slashingEvidence := bstorage.NewSlashingEvidence(node.DB)
+			notifier.AddConsumer(notifications.NewSlashingViolationsConsumer(node.Logger, node.RootChainID, node.State, node.Storage.Headers, slashingEvidence))
+
 			// initialize the persister
 			persist := persister.New(node.DB, node.RootChainID)
 
@@ -576,6 +651,31 @@ func main() {
 				return nil, fmt.Errorf("could not find latest finalized block and pending blocks: %w", err)
 			}
 
+			// initialize the hotstuff options
+			opts := []consensus.Option{
+				consensus.WithInitialTimeout(hotstuffTimeout),
+				consensus.WithMinTimeout(hotstuffMinTimeout),
+				consensus.WithVoteAggregationTimeoutFraction(hotstuffTimeoutVoteAggregationFraction),
+				consensus.WithTimeoutIncreaseFactor(hotstuffTimeoutIncreaseFactor),
+				consensus.WithTimeoutDecreaseFactor(hotstuffTimeoutDecreaseFactor),
+				consensus.WithTCActivationView(hotstuffTCActivationView),
+				consensus.WithBlockRateDelay(blockRateDelay),
+			}
+			if hotstuffAdaptiveTimeout {
+				adaptive, err := timeout.NewAdaptiveConfig(
+					timeout.DefaultAdaptiveConfig.Percentile,
+					timeout.DefaultAdaptiveConfig.Multiplier,
+					timeout.DefaultAdaptiveConfig.WindowSize,
+					timeout.DefaultAdaptiveConfig.MinSamples,
+					hotstuffAdaptiveMinTimeout,
+					hotstuffTimeout,
+				)
+				if err != nil {
+					return nil, fmt.Errorf("invalid adaptive timeout config: %w", err)
+				}
+				opts = append(opts, consensus.WithAdaptiveTimeout(adaptive))
+			}
+
 			// initialize hotstuff consensus algorithm
 			hot, err := consensus.NewParticipant(
 				node.Logger,
@@ -592,12 +692,7 @@ func main() {
 				node.RootQC,
 				finalized,
 				pending,
-				consensus.WithInitialTimeout(hotstuffTimeout),
-				consensus.WithMinTimeout(hotstuffMinTimeout),
-				consensus.WithVoteAggregationTimeoutFraction(hotstuffTimeoutVoteAggregationFraction),
-				consensus.WithTimeoutIncreaseFactor(hotstuffTimeoutIncreaseFactor),
-				consensus.WithTimeoutDecreaseFactor(hotstuffTimeoutDecreaseFactor),
-				consensus.WithBlockRateDelay(blockRateDelay),
+				opts...,
 			)
 			if err != nil {
 				return nil, fmt.Errorf("could not initialize hotstuff engine: %w", err)
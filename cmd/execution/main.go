@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -25,17 +26,23 @@ import (
 	"github.com/onflow/flow-go/engine/common/requester"
 	"github.com/onflow/flow-go/engine/common/synchronization"
 	"github.com/onflow/flow-go/engine/execution/checker"
+	"github.com/onflow/flow-go/engine/execution/checkpoint"
 	"github.com/onflow/flow-go/engine/execution/computation"
 	"github.com/onflow/flow-go/engine/execution/computation/committer"
+	"github.com/onflow/flow-go/engine/execution/computation/computer"
+	"github.com/onflow/flow-go/engine/execution/eviction"
 	"github.com/onflow/flow-go/engine/execution/ingestion"
 	exeprovider "github.com/onflow/flow-go/engine/execution/provider"
+	"github.com/onflow/flow-go/engine/execution/pruner"
 	"github.com/onflow/flow-go/engine/execution/rpc"
 	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/engine/execution/state/bootstrap"
 	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/fvm/extralog"
+	ledgerpkg "github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/pathfinder"
 	ledger "github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
 	"github.com/onflow/flow-go/ledger/complete/wal"
 	bootstrapFilenames "github.com/onflow/flow-go/model/bootstrap"
 	"github.com/onflow/flow-go/model/encodable"
@@ -43,15 +50,19 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/admin"
 	"github.com/onflow/flow-go/module/buffer"
 	finalizer "github.com/onflow/flow-go/module/finalizer/consensus"
 	"github.com/onflow/flow-go/module/metrics"
+	modulepruner "github.com/onflow/flow-go/module/pruner"
 	"github.com/onflow/flow-go/module/signature"
 	chainsync "github.com/onflow/flow-go/module/synchronization"
 	"github.com/onflow/flow-go/state/protocol"
 	badgerState "github.com/onflow/flow-go/state/protocol/badger"
 	"github.com/onflow/flow-go/state/protocol/blocktimer"
+	storagepkg "github.com/onflow/flow-go/storage"
 	storage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
 )
 
 func main() {
@@ -63,9 +74,11 @@ func main() {
 		serviceEvents                 *storage.ServiceEvents
 		txResults                     *storage.TransactionResults
 		results                       *storage.ExecutionResults
+		chunkDataPacks                *storage.ChunkDataPacks
 		myReceipts                    *storage.MyExecutionReceipts
 		providerEngine                *exeprovider.Engine
 		checkerEng                    *checker.Engine
+		evictionPolicy                *eviction.Policy
 		syncCore                      *chainsync.Core
 		pendingBlocks                 *buffer.PendingBlocks // used in follower engine
 		deltas                        *ingestion.Deltas
@@ -82,6 +95,7 @@ func main() {
 		triedir                       string
 		collector                     module.ExecutionMetrics
 		mTrieCacheSize                uint32
+		mTrieStoreEvicted             bool
 		transactionResultsCacheSize   uint
 		checkpointDistance            uint
 		checkpointsToKeep             uint
@@ -97,12 +111,22 @@ func main() {
 		pauseExecution                bool
 		checkStakedAtBlock            func(blockID flow.Identifier) (bool, error)
 		diskWAL                       *wal.DiskWAL
+		compactor                     *wal.Compactor
 		scriptLogThreshold            time.Duration
+		parallelExecutionWorkers      uint
+		pruningRetention              uint64
+		pruningBatchSize              uint
+		pruningInterval               time.Duration
+		registerHistoryRetention      uint64
 		chdpQueryTimeout              uint
 		chdpDeliveryTimeout           uint
 		enableBlockDataUpload         bool
 		gcpBucketName                 string
 		blockDataUploader             uploader.Uploader
+		checkpointSync                bool
+		checkpointSyncDepth           uint
+		checkpointSyncTimeout         time.Duration
+		checkpointSyncEng             *checkpoint.Engine
 		blockDataUploaderMaxRetry     uint64 = 5
 		blockdataUploaderRetryTimeout        = 1 * time.Second
 	)
@@ -116,6 +140,7 @@ func main() {
 			flags.BoolVar(&rpcConf.RpcMetricsEnabled, "rpc-metrics-enabled", false, "whether to enable the rpc metrics")
 			flags.StringVar(&triedir, "triedir", datadir, "directory to store the execution State")
 			flags.Uint32Var(&mTrieCacheSize, "mtrie-cache-size", 500, "cache size for MTrie")
+			flags.BoolVar(&mTrieStoreEvicted, "mtrie-store-evicted", false, "whether to persist tries evicted from the MTrie cache to disk and reload them on demand, until the checkpoints covering them are removed")
 			flags.UintVar(&checkpointDistance, "checkpoint-distance", 40, "number of WAL segments between checkpoints")
 			flags.UintVar(&checkpointsToKeep, "checkpoints-to-keep", 5, "number of recent checkpoints to keep (0 to keep all)")
 			flags.UintVar(&stateDeltasLimit, "state-deltas-limit", 100, "maximum number of state deltas in the memory pool")
@@ -123,6 +148,11 @@ func main() {
 			flags.UintVar(&chdpCacheSize, "chdp-cache", storage.DefaultCacheSize, "cache size for Chunk Data Packs")
 			flags.DurationVar(&requestInterval, "request-interval", 60*time.Second, "the interval between requests for the requester engine")
 			flags.DurationVar(&scriptLogThreshold, "script-log-threshold", computation.DefaultScriptLogThreshold, "threshold for logging script execution")
+			flags.UintVar(&parallelExecutionWorkers, "parallel-execution-workers", 0, "number of workers executing the transactions of a collection optimistically in parallel (0 or 1 to execute them sequentially)")
+			flags.Uint64Var(&pruningRetention, "pruning-retention", 0, "number of blocks below the latest sealed block to keep the execution data of (0 to disable pruning)")
+			flags.UintVar(&pruningBatchSize, "pruning-batch-size", modulepruner.DefaultBatchSize, "maximum number of blocks whose execution data is pruned per run")
+			flags.DurationVar(&pruningInterval, "pruning-interval", modulepruner.DefaultInterval, "the interval between runs of the execution data pruner")
+			flags.Uint64Var(&registerHistoryRetention, "register-history-retention", pruner.DefaultRegisterHistoryRetention, "number of blocks below the latest sealed block to keep the register history of (0 to keep the full history)")
 			flags.StringVar(&preferredExeNodeIDStr, "preferred-exe-node-id", "", "node ID for preferred execution node used for state sync")
 			flags.UintVar(&transactionResultsCacheSize, "transaction-results-cache-size", 10000, "number of transaction results to be cached")
 			flags.BoolVar(&syncByBlocks, "sync-by-blocks", true, "deprecated, sync by blocks instead of execution state deltas")
@@ -134,6 +164,9 @@ func main() {
 			flags.BoolVar(&pauseExecution, "pause-execution", false, "pause the execution. when set to true, no block will be executed, but still be able to serve queries")
 			flags.BoolVar(&enableBlockDataUpload, "enable-blockdata-upload", false, "enable uploading block data to GCP Bucket")
 			flags.StringVar(&gcpBucketName, "gcp-bucket-name", "", "GCP Bucket name for block data uploader")
+			flags.BoolVar(&checkpointSync, "checkpoint-sync", false, "bootstrap the execution state of the latest sealed block from the other execution nodes instead of the root checkpoint in the bootstrap folder")
+			flags.UintVar(&checkpointSyncDepth, "checkpoint-sync-depth", checkpoint.DefaultDepth, "depth of the subtries the execution state is fetched in, when bootstrapping it from the other execution nodes")
+			flags.DurationVar(&checkpointSyncTimeout, "checkpoint-sync-timeout", checkpoint.DefaultRequestTimeout, "timeout for fetching a subtrie of the execution state from another execution node")
 		}).
 		ValidateFlags(func() error {
 			if enableBlockDataUpload {
@@ -144,6 +177,20 @@ func main() {
 			return nil
 		}).
 		Initialize().
+		AdminCommand("trigger-checkpoint", func(node *cmd.NodeConfig) admin.CommandHandler {
+			return func(context.Context, map[string]interface{}) (interface{}, error) {
+				checkpoint, err := compactor.TriggerCheckpoint()
+				if err != nil {
+					return nil, fmt.Errorf("could not trigger checkpoint: %w", err)
+				}
+				return fmt.Sprintf("checkpoint %d created", checkpoint), nil
+			}
+		}).
+		AdminCommand("create-execution-backup", func(node *cmd.NodeConfig) admin.CommandHandler {
+			// the execution state is backed up along with the protocol state, the
+			// compactor makes sure checkpoints are not removed while copying them
+			return cmd.BackupCommand(node.DB, compactor)
+		}).
 		Module("mutable follower state", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) error {
 			// For now, we only support state implementations from package badger.
 			// If we ever support different implementations, the following can be replaced by a type-aware factory
@@ -222,7 +269,120 @@ func main() {
 		}).
 		Component("Write-Ahead Log", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			diskWAL, err = wal.NewDiskWAL(node.Logger.With().Str("subcomponent", "wal").Logger(), node.MetricsRegisterer, collector, triedir, int(mTrieCacheSize), pathfinder.PathByteSize, wal.SegmentSize)
-			return diskWAL, err
+			if err != nil {
+				return nil, err
+			}
+
+			if mTrieStoreEvicted {
+				trieStore, err := wal.NewDiskTrieStore(node.Logger, filepath.Join(triedir, wal.DefaultTrieStoreDir))
+				if err != nil {
+					return nil, fmt.Errorf("could not create trie store: %w", err)
+				}
+				diskWAL.SetTrieStore(trieStore)
+			}
+
+			return diskWAL, nil
+		}).
+		Component("checkpoint sync engine", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			checkpointSyncEng, err = checkpoint.New(
+				node.Logger,
+				node.Network,
+				node.State,
+				node.Me,
+				checkpointSyncDepth,
+				checkpointSyncTimeout,
+			)
+			return checkpointSyncEng, err
+		}).
+		// the follower and synchronization engines are started before the execution state ledger,
+		// so that the protocol state catches up with the chain while the execution state is synced
+		// from other execution nodes, which only serve the states of recently sealed blocks
+		Component("follower engine", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+
+			// initialize cleaner for DB
+			cleaner := storage.NewCleaner(node.Logger, node.DB, node.Metrics.CleanCollector, flow.DefaultValueLogGCFrequency)
+
+			// create a finalizer that handles updating the protocol
+			// state when the follower detects newly finalized blocks
+			final := finalizer.NewFinalizer(node.DB, node.Storage.Headers, followerState)
+
+			// initialize the staking & beacon verifiers, signature joiner
+			staking := signature.NewAggregationVerifier(encoding.ConsensusVoteTag)
+			beacon := signature.NewThresholdVerifier(encoding.RandomBeaconTag)
+			merger := signature.NewCombiner(encodable.ConsensusVoteSigLen, encodable.RandomBeaconSigLen)
+
+			// initialize consensus committee's membership state
+			// This committee state is for the HotStuff follower, which follows the MAIN CONSENSUS Committee
+			// Note: node.Me.NodeID() is not part of the consensus committee
+			committee, err := committees.NewConsensusCommittee(node.State, node.Me.NodeID())
+			if err != nil {
+				return nil, fmt.Errorf("could not create Committee state for main consensus: %w", err)
+			}
+
+			// initialize the verifier for the protocol consensus
+			verifier := verification.NewCombinedVerifier(committee, staking, beacon, merger, node.RootChainID)
+
+			finalized, pending, err := recovery.FindLatest(node.State, node.Storage.Headers)
+			if err != nil {
+				return nil, fmt.Errorf("could not find latest finalized block and pending blocks to recover consensus follower: %w", err)
+			}
+
+			// the checker engine and the ledger eviction policy subscribe once they are created
+			finalizationDistributor = pubsub.NewFinalizationDistributor()
+
+			// creates a consensus follower with ingestEngine as the notifier
+			// so that it gets notified upon each new finalized block
+			followerCore, err := consensus.NewFollower(node.Logger, committee, node.Storage.Headers, final, verifier, finalizationDistributor, node.RootBlock.Header, node.RootQC, finalized, pending)
+			if err != nil {
+				return nil, fmt.Errorf("could not create follower core logic: %w", err)
+			}
+
+			followerEng, err = followereng.New(
+				node.Logger,
+				node.Network,
+				node.Me,
+				node.Metrics.Engine,
+				node.Metrics.Mempool,
+				cleaner,
+				node.Storage.Headers,
+				node.Storage.Payloads,
+				followerState,
+				pendingBlocks,
+				followerCore,
+				syncCore,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("could not create follower engine: %w", err)
+			}
+
+			return followerEng, nil
+		}).
+		Component("finalized snapshot", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			finalizedHeader, err = synchronization.NewFinalizedHeaderCache(node.Logger, node.State, finalizationDistributor)
+			if err != nil {
+				return nil, fmt.Errorf("could not create finalized snapshot cache: %w", err)
+			}
+
+			return finalizedHeader, nil
+		}).
+		Component("synchronization engine", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			// initialize the synchronization engine
+			syncEngine, err = synchronization.New(
+				node.Logger,
+				node.Metrics.Engine,
+				node.Network,
+				node.Me,
+				node.Storage.Blocks,
+				followerEng,
+				syncCore,
+				finalizedHeader,
+				node.SyncEngineIdentifierProvider,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("could not initialize synchronization engine: %w", err)
+			}
+
+			return syncEngine, nil
 		}).
 		Component("execution state ledger", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 
@@ -235,7 +395,20 @@ func main() {
 			}
 
 			// if the execution database does not exist, then we need to bootstrap the execution database.
-			if !bootstrapped {
+			if !bootstrapped && checkpointSync {
+				// the execution state of the latest sealed block, which other execution nodes still
+				// serve, is fetched from them once the protocol state has caught up with its seal,
+				// and execution resumes from that sealed block
+				sealed, result, err := syncBootstrapState(checkpointSyncEng, triedir)
+				if err != nil {
+					return nil, fmt.Errorf("could not sync bootstrap state from execution nodes: %w", err)
+				}
+
+				err = bootstrapper.BootstrapExecutionDatabaseAtSealed(node.DB, result, sealed)
+				if err != nil {
+					return nil, fmt.Errorf("could not bootstrap execution database: %w", err)
+				}
+			} else if !bootstrapped {
 				// when bootstrapping, the bootstrap folder must have a checkpoint file
 				// we need to cover this file to the trie folder to restore the trie to restore the execution state.
 				err = copyBootstrapState(node.BootstrapDir, triedir)
@@ -249,9 +422,10 @@ func main() {
 				if err != nil {
 					return nil, fmt.Errorf("could not bootstrap execution database: %w", err)
 				}
-			} else {
+			} else if !checkpointSync {
 				// if execution database has been bootstrapped, then the root statecommit must equal to the one
-				// in the bootstrap folder
+				// in the bootstrap folder. With checkpoint sync, the database is bootstrapped at a later sealed
+				// block, whose state has been verified against its seal while syncing.
 				if commit != node.RootSeal.FinalState {
 					return nil, fmt.Errorf("mismatching root statecommitment. database has state commitment: %x, "+
 						"bootstap has statecommitment: %x",
@@ -260,7 +434,18 @@ func main() {
 			}
 
 			ledgerStorage, err = ledger.NewLedger(diskWAL, int(mTrieCacheSize), collector, node.Logger.With().Str("subcomponent", "ledger").Logger(), ledger.DefaultPathFinderVersion)
-			return ledgerStorage, err
+			if err != nil {
+				return nil, err
+			}
+
+			// without a register history, scripts and accounts are only served at
+			// the states still held by the ledger
+			err = startRegisterHistory(node, bootstrapper, ledgerStorage)
+			if err != nil {
+				node.Logger.Warn().Err(err).Msg("could not start register history, will retry on next start")
+			}
+
+			return ledgerStorage, nil
 		}).
 		Component("execution state ledger WAL compactor", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 
@@ -268,7 +453,7 @@ func main() {
 			if err != nil {
 				return nil, fmt.Errorf("cannot create checkpointer: %w", err)
 			}
-			compactor := wal.NewCompactor(checkpointer, 10*time.Second, checkpointDistance, checkpointsToKeep)
+			compactor = wal.NewCompactor(checkpointer, 10*time.Second, checkpointDistance, checkpointsToKeep)
 
 			return compactor, nil
 		}).
@@ -299,20 +484,25 @@ func main() {
 				committer,
 				scriptLogThreshold,
 				blockDataUploader,
+				computer.WithParallelExecution(parallelExecutionWorkers),
 			)
 			if err != nil {
 				return nil, err
 			}
 			computationManager = manager
 
-			chunkDataPacks := storage.NewChunkDataPacks(node.Metrics.Cache, node.DB, node.Storage.Collections, chdpCacheSize)
+			chunkDataPacks = storage.NewChunkDataPacks(node.Metrics.Cache, node.DB, node.Storage.Collections, chdpCacheSize)
 			stateCommitments := storage.NewCommits(node.Metrics.Cache, node.DB)
+			registers := storage.NewRegisters(node.DB)
 
 			// Needed for gRPC server, make sure to assign to main scoped vars
 			events = storage.NewEvents(node.Metrics.Cache, node.DB)
 			serviceEvents = storage.NewServiceEvents(node.Metrics.Cache, node.DB)
 			txResults = storage.NewTransactionResults(node.Metrics.Cache, node.DB, transactionResultsCacheSize)
 
+			// serve the execution state to execution nodes bootstrapping from it
+			checkpointSyncEng.Serve(ledgerStorage, stateCommitments)
+
 			executionState = state.NewExecutionState(
 				ledgerStorage,
 				stateCommitments,
@@ -326,6 +516,7 @@ func main() {
 				events,
 				serviceEvents,
 				txResults,
+				registers,
 				node.DB,
 				node.Tracer,
 			)
@@ -352,8 +543,54 @@ func main() {
 				executionState,
 				node.Storage.Seals,
 			)
+			finalizationDistributor.AddConsumer(checkerEng)
 			return checkerEng, nil
 		}).
+		Component("ledger eviction policy", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			evictionPolicy = eviction.NewPolicy(
+				node.Logger,
+				node.State,
+				executionState,
+				ledgerStorage,
+			)
+			finalizationDistributor.AddConsumer(evictionPolicy)
+			return evictionPolicy, nil
+		}).
+		Component("execution data pruner", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			if pruningRetention == 0 {
+				return &module.NoopReadDoneAware{}, nil
+			}
+			return pruner.NewPruner(
+				node.Logger,
+				node.DB,
+				node.State,
+				node.Storage.Headers,
+				executionState,
+				results,
+				events,
+				serviceEvents,
+				txResults,
+				chunkDataPacks,
+				collector,
+				pruningRetention,
+				pruningBatchSize,
+				pruningInterval,
+			), nil
+		}).
+		Component("register history pruner", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			if registerHistoryRetention == 0 {
+				return &module.NoopReadDoneAware{}, nil
+			}
+			return pruner.NewRegisterHistoryPruner(
+				node.Logger,
+				node.DB,
+				node.State,
+				executionState,
+				registerHistoryRetention,
+				pruningBatchSize,
+				pruningInterval,
+			), nil
+		}).
 		Component("ingestion engine", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			collectionRequester, err = requester.New(node.Logger, node.Metrics.Engine, node.Network, node.Me, node.State,
 				engine.RequestCollections,
@@ -403,71 +640,12 @@ func main() {
 			// TODO: we should solve these mutual dependencies better
 			// => https://github.com/dapperlabs/flow-go/issues/4360
 			collectionRequester = collectionRequester.WithHandle(ingestionEng.OnCollection)
+			ingestionEng.AddExecutionConsumer(evictionPolicy)
 
 			node.ProtocolEvents.AddConsumer(ingestionEng)
 
 			return ingestionEng, err
 		}).
-		Component("follower engine", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
-
-			// initialize cleaner for DB
-			cleaner := storage.NewCleaner(node.Logger, node.DB, node.Metrics.CleanCollector, flow.DefaultValueLogGCFrequency)
-
-			// create a finalizer that handles updating the protocol
-			// state when the follower detects newly finalized blocks
-			final := finalizer.NewFinalizer(node.DB, node.Storage.Headers, followerState)
-
-			// initialize the staking & beacon verifiers, signature joiner
-			staking := signature.NewAggregationVerifier(encoding.ConsensusVoteTag)
-			beacon := signature.NewThresholdVerifier(encoding.RandomBeaconTag)
-			merger := signature.NewCombiner(encodable.ConsensusVoteSigLen, encodable.RandomBeaconSigLen)
-
-			// initialize consensus committee's membership state
-			// This committee state is for the HotStuff follower, which follows the MAIN CONSENSUS Committee
-			// Note: node.Me.NodeID() is not part of the consensus committee
-			committee, err := committees.NewConsensusCommittee(node.State, node.Me.NodeID())
-			if err != nil {
-				return nil, fmt.Errorf("could not create Committee state for main consensus: %w", err)
-			}
-
-			// initialize the verifier for the protocol consensus
-			verifier := verification.NewCombinedVerifier(committee, staking, beacon, merger)
-
-			finalized, pending, err := recovery.FindLatest(node.State, node.Storage.Headers)
-			if err != nil {
-				return nil, fmt.Errorf("could not find latest finalized block and pending blocks to recover consensus follower: %w", err)
-			}
-
-			finalizationDistributor = pubsub.NewFinalizationDistributor()
-			finalizationDistributor.AddConsumer(checkerEng)
-
-			// creates a consensus follower with ingestEngine as the notifier
-			// so that it gets notified upon each new finalized block
-			followerCore, err := consensus.NewFollower(node.Logger, committee, node.Storage.Headers, final, verifier, finalizationDistributor, node.RootBlock.Header, node.RootQC, finalized, pending)
-			if err != nil {
-				return nil, fmt.Errorf("could not create follower core logic: %w", err)
-			}
-
-			followerEng, err = followereng.New(
-				node.Logger,
-				node.Network,
-				node.Me,
-				node.Metrics.Engine,
-				node.Metrics.Mempool,
-				cleaner,
-				node.Storage.Headers,
-				node.Storage.Payloads,
-				followerState,
-				pendingBlocks,
-				followerCore,
-				syncCore,
-			)
-			if err != nil {
-				return nil, fmt.Errorf("could not create follower engine: %w", err)
-			}
-
-			return followerEng, nil
-		}).
 		Component("collection requester engine", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			// We initialize the requester engine inside the ingestion engine due to the mutual dependency. However, in
 			// order for it to properly start and shut down, we should still return it as its own engine here, so it can
@@ -488,35 +666,8 @@ func main() {
 			)
 			return eng, err
 		}).
-		Component("finalized snapshot", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
-			finalizedHeader, err = synchronization.NewFinalizedHeaderCache(node.Logger, node.State, finalizationDistributor)
-			if err != nil {
-				return nil, fmt.Errorf("could not create finalized snapshot cache: %w", err)
-			}
-
-			return finalizedHeader, nil
-		}).
-		Component("synchronization engine", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
-			// initialize the synchronization engine
-			syncEngine, err = synchronization.New(
-				node.Logger,
-				node.Metrics.Engine,
-				node.Network,
-				node.Me,
-				node.Storage.Blocks,
-				followerEng,
-				syncCore,
-				finalizedHeader,
-				node.SyncEngineIdentifierProvider,
-			)
-			if err != nil {
-				return nil, fmt.Errorf("could not initialize synchronization engine: %w", err)
-			}
-
-			return syncEngine, nil
-		}).
 		Component("grpc server", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
-			rpcEng := rpc.New(node.Logger, rpcConf, ingestionEng, node.Storage.Blocks, events, results, txResults, node.RootChainID)
+			rpcEng := rpc.New(node.Logger, rpcConf, ingestionEng, node.Storage.Blocks, node.Storage.Headers, events, results, txResults, chunkDataPacks, node.RootChainID)
 			return rpcEng, nil
 		}).Run()
 }
@@ -589,3 +740,158 @@ func copyBootstrapState(dir, trie string) error {
 
 	return out.Close()
 }
+
+// startRegisterHistory starts the register history on the first start of the
+// node, at the highest executed and finalized block. Nodes bootstrapped before
+// the register history was introduced thus start indexing registers too. The
+// descendants of that block which are executed already are indexed from the
+// differences between their states and the states of their parents.
+func startRegisterHistory(node *cmd.NodeConfig, bootstrapper *bootstrap.Bootstrapper, ledgerStorage *ledger.Ledger) error {
+	_, started, err := bootstrapper.IsRegisterHistoryStarted(node.DB)
+	if err != nil {
+		return err
+	}
+	if started {
+		return nil
+	}
+
+	var executedID flow.Identifier
+	err = node.DB.View(operation.RetrieveExecutedBlock(&executedID))
+	if err != nil {
+		return fmt.Errorf("could not get highest executed block: %w", err)
+	}
+	executed, err := node.Storage.Headers.ByBlockID(executedID)
+	if err != nil {
+		return fmt.Errorf("could not get highest executed block %v: %w", executedID, err)
+	}
+	final, err := node.State.Final().Head()
+	if err != nil {
+		return fmt.Errorf("could not get finalized block: %w", err)
+	}
+
+	// the highest executed block may be on a fork, so the finalized blocks are
+	// searched downwards from its height for the highest executed one
+	height := final.Height
+	if executed.Height < height {
+		height = executed.Height
+	}
+	var header *flow.Header
+	var commit flow.StateCommitment
+	for {
+		header, err = node.State.AtHeight(height).Head()
+		if err != nil {
+			return fmt.Errorf("could not get finalized block at height %d: %w", height, err)
+		}
+		err = node.DB.View(operation.LookupStateCommitment(header.ID(), &commit))
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, storagepkg.ErrNotFound) || height == 0 {
+			return fmt.Errorf("could not get state commitment of block at height %d: %w", height, err)
+		}
+		height--
+	}
+
+	// blocks are executed after their parents, so executed descendants can only
+	// exist if the latest finalized block is executed
+	if header.Height == final.Height {
+		descendants, err := node.State.Final().Descendants()
+		if err != nil {
+			return fmt.Errorf("could not get descendants of finalized block: %w", err)
+		}
+		for _, blockID := range descendants {
+			err = indexExecutedBlock(node, bootstrapper, ledgerStorage, blockID)
+			if err != nil {
+				return fmt.Errorf("could not index executed block %v: %w", blockID, err)
+			}
+		}
+	}
+
+	stateTrie, err := ledgerStorage.Trie(ledgerpkg.State(commit))
+	if err != nil {
+		return fmt.Errorf("could not get state of block at height %d: %w", height, err)
+	}
+
+	// the history is started last, such that an interrupted start is repeated
+	// entirely on the next start
+	return bootstrapper.BootstrapRegisterHistory(node.DB, header, stateTrie)
+}
+
+// indexExecutedBlock indexes the registers written by the given block, if it is
+// executed.
+func indexExecutedBlock(node *cmd.NodeConfig, bootstrapper *bootstrap.Bootstrapper, ledgerStorage *ledger.Ledger, blockID flow.Identifier) error {
+	var commit flow.StateCommitment
+	err := node.DB.View(operation.LookupStateCommitment(blockID, &commit))
+	if errors.Is(err, storagepkg.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not get state commitment: %w", err)
+	}
+	header, err := node.Storage.Headers.ByBlockID(blockID)
+	if err != nil {
+		return fmt.Errorf("could not get block: %w", err)
+	}
+	var parentCommit flow.StateCommitment
+	err = node.DB.View(operation.LookupStateCommitment(header.ParentID, &parentCommit))
+	if err != nil {
+		return fmt.Errorf("could not get state commitment of parent: %w", err)
+	}
+
+	stateTrie, err := ledgerStorage.Trie(ledgerpkg.State(commit))
+	if err != nil {
+		return fmt.Errorf("could not get state: %w", err)
+	}
+	parentTrie, err := ledgerStorage.Trie(ledgerpkg.State(parentCommit))
+	if err != nil {
+		return fmt.Errorf("could not get state of parent: %w", err)
+	}
+
+	return bootstrapper.IndexRegisterChanges(node.DB, header, parentTrie, stateTrie)
+}
+
+// syncBootstrapState fetches the execution state of the latest sealed block, which
+// other execution nodes still serve, and stores it as the root checkpoint in the
+// trie folder. It returns the sealed block and its sealed execution result.
+func syncBootstrapState(eng *checkpoint.Engine, trie string) (*flow.Header, *flow.ExecutionResult, error) {
+	sealed, result, mTrie, err := eng.SyncLatestSealed(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	flattenedTrie, err := flattener.FlattenTrie(mTrie)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not flatten synced trie: %w", err)
+	}
+
+	// It's possible that the trie dir does not yet exist. If not this will create the the required path
+	err = os.MkdirAll(trie, 0700)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// a root checkpoint left behind by an interrupted bootstrap is replaced
+	err = os.Remove(filepath.Join(trie, bootstrapFilenames.FilenameWALRootCheckpoint))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+
+	writer, err := wal.CreateCheckpointWriterForFile(trie, bootstrapFilenames.FilenameWALRootCheckpoint)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create root checkpoint writer: %w", err)
+	}
+
+	err = wal.StoreCheckpoint(flattenedTrie.ToFlattenedForestWithASingleTrie(), writer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not store root checkpoint: %w", err)
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not close root checkpoint writer: %w", err)
+	}
+
+	fmt.Printf("synced bootstrap state of sealed block %x (height: %d) to: %v\n", sealed.ID(), sealed.Height, trie)
+
+	return sealed, result, nil
+}
@@ -15,10 +15,13 @@ import (
 	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/admin"
 	"github.com/onflow/flow-go/module/id"
 	"github.com/onflow/flow-go/module/local"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/codec/compression"
 	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/recorder"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/state/protocol/events"
 	bstorage "github.com/onflow/flow-go/storage/badger"
@@ -93,6 +96,11 @@ type NodeBuilder interface {
 	// ValidateFlags is an extra method called after parsing flags, intended for extra check of flag validity
 	// for example where certain combinations aren't allowed
 	ValidateFlags(func() error) NodeBuilder
+
+	// AdminCommand registers a new command of the admin server of the node.
+	// The handler of the command is created once all components are initialized,
+	// right before the admin server starts, so that it can use any of them.
+	AdminCommand(command string, f func(config *NodeConfig) admin.CommandHandler) NodeBuilder
 }
 
 // BaseConfig is the general config for the NodeBuilder and the command line params
@@ -106,10 +114,19 @@ type BaseConfig struct {
 	datadir               string
 	level                 string
 	metricsPort           uint
+	adminSocket           string
 	BootstrapDir          string
 	PeerUpdateInterval    time.Duration
 	UnicastMessageTimeout time.Duration
 	DNSCacheTTL           time.Duration
+	MessageRateLimit      float64
+	MessageRateBurst      int
+	ChannelRateLimits     map[string]string
+	PeerScoringEnabled    bool
+	NetworkRecording      string
+	NetworkRecordingSize  int64
+	NetworkCompression    string
+	CompressionThreshold  int
 	profilerEnabled       bool
 	profilerDir           string
 	profilerInterval      time.Duration
@@ -171,6 +188,13 @@ func DefaultBaseConfig() *BaseConfig {
 		level:                 "info",
 		PeerUpdateInterval:    p2p.DefaultPeerUpdateInterval,
 		UnicastMessageTimeout: p2p.DefaultUnicastTimeout,
+		MessageRateLimit:      0,
+		MessageRateBurst:      100,
+		PeerScoringEnabled:    false,
+		NetworkRecording:      "",
+		NetworkRecordingSize:  recorder.DefaultMaxSize,
+		NetworkCompression:    "",
+		CompressionThreshold:  compression.DefaultThreshold,
 		metricsPort:           8080,
 		profilerEnabled:       false,
 		profilerDir:           "profiler",
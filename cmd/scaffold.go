@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -16,21 +17,27 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
 	"github.com/spf13/pflag"
+	"golang.org/x/time/rate"
 
 	"github.com/onflow/flow-go/cmd/build"
+	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/model/bootstrap"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/admin"
 	"github.com/onflow/flow-go/module/id"
 	"github.com/onflow/flow-go/module/lifecycle"
 	"github.com/onflow/flow-go/module/local"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/trace"
+	"github.com/onflow/flow-go/network"
 	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/codec/compression"
 	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/network/p2p/dns"
+	"github.com/onflow/flow-go/network/recorder"
 	"github.com/onflow/flow-go/network/topology"
 	badgerState "github.com/onflow/flow-go/state/protocol/badger"
 	"github.com/onflow/flow-go/state/protocol/events"
@@ -82,6 +89,11 @@ type namedComponentFunc struct {
 	name string
 }
 
+type namedAdminCommand struct {
+	fn      func(nodeConfig *NodeConfig) admin.CommandHandler
+	command string
+}
+
 type namedDoneObject struct {
 	ob   module.ReadyDoneAware
 	name string
@@ -101,6 +113,7 @@ type FlowNodeBuilder struct {
 	flags          *pflag.FlagSet
 	modules        []namedModuleFunc
 	components     []namedComponentFunc
+	adminCommands  []namedAdminCommand
 	doneObject     []namedDoneObject
 	sig            chan os.Signal
 	preInitFns     []func(NodeBuilder, *NodeConfig)
@@ -122,6 +135,7 @@ func (fnb *FlowNodeBuilder) BaseFlags() {
 	fnb.flags.DurationVar(&fnb.BaseConfig.PeerUpdateInterval, "peerupdate-interval", defaultConfig.PeerUpdateInterval, "how often to refresh the peer connections for the node")
 	fnb.flags.DurationVar(&fnb.BaseConfig.UnicastMessageTimeout, "unicast-timeout", defaultConfig.UnicastMessageTimeout, "how long a unicast transmission can take to complete")
 	fnb.flags.UintVarP(&fnb.BaseConfig.metricsPort, "metricport", "m", defaultConfig.metricsPort, "port for /metrics endpoint")
+	fnb.flags.StringVar(&fnb.BaseConfig.adminSocket, "admin-socket", defaultConfig.adminSocket, "path of the unix socket the admin server listens on, empty disables the admin server")
 	fnb.flags.BoolVar(&fnb.BaseConfig.profilerEnabled, "profiler-enabled", defaultConfig.profilerEnabled, "whether to enable the auto-profiler")
 	fnb.flags.StringVar(&fnb.BaseConfig.profilerDir, "profiler-dir", defaultConfig.profilerDir, "directory to create auto-profiler profiles")
 	fnb.flags.DurationVar(&fnb.BaseConfig.profilerInterval, "profiler-interval", defaultConfig.profilerInterval,
@@ -131,15 +145,74 @@ func (fnb *FlowNodeBuilder) BaseFlags() {
 	fnb.flags.BoolVar(&fnb.BaseConfig.tracerEnabled, "tracer-enabled", defaultConfig.tracerEnabled,
 		"whether to enable tracer")
 	fnb.flags.DurationVar(&fnb.BaseConfig.DNSCacheTTL, "dns-cache-ttl", dns.DefaultTimeToLive, "time-to-live for dns cache")
+	fnb.flags.Float64Var(&fnb.BaseConfig.MessageRateLimit, "msg-rate-limit", defaultConfig.MessageRateLimit, "maximum sustained number of inbound messages per second of each peer on each channel, 0 disables rate limiting")
+	fnb.flags.IntVar(&fnb.BaseConfig.MessageRateBurst, "msg-rate-burst", defaultConfig.MessageRateBurst, "maximum number of inbound messages of each peer on each channel accepted at once")
+	fnb.flags.StringToStringVar(&fnb.BaseConfig.ChannelRateLimits, "channel-rate-limits", defaultConfig.ChannelRateLimits, "rate limits of individual channels overriding the default, as channel=rate:burst")
+	fnb.flags.StringVar(&fnb.BaseConfig.NetworkCompression, "network-compression", defaultConfig.NetworkCompression, "compression algorithm of outbound network payloads (snappy or deflate), compressed payloads are decoded regardless, empty disables compression")
+	fnb.flags.IntVar(&fnb.BaseConfig.CompressionThreshold, "network-compression-threshold", defaultConfig.CompressionThreshold, "size in bytes from which outbound network payloads are compressed")
+	fnb.flags.BoolVar(&fnb.BaseConfig.PeerScoringEnabled, "peer-scoring-enabled", defaultConfig.PeerScoringEnabled, "whether to disconnect and blocklist peers for misbehaviour")
+	fnb.flags.StringVar(&fnb.BaseConfig.NetworkRecording, "network-recording", defaultConfig.NetworkRecording, "path of a file to append all inbound network messages to, for replaying them offline, empty disables recording")
+	fnb.flags.Int64Var(&fnb.BaseConfig.NetworkRecordingSize, "network-recording-max-size", defaultConfig.NetworkRecordingSize, "size in bytes up to which inbound network messages are appended to the network recording, 0 disables the limit")
 
 	fnb.flags.UintVar(&fnb.BaseConfig.guaranteesCacheSize, "guarantees-cache-size", bstorage.DefaultCacheSize, "collection guarantees cache size")
 	fnb.flags.UintVar(&fnb.BaseConfig.receiptsCacheSize, "receipts-cache-size", bstorage.DefaultCacheSize, "receipts cache size")
 }
 
+// rateLimiterConfig returns the configuration of the inbound message rate limits
+// from the command line parameters.
+func (fnb *FlowNodeBuilder) rateLimiterConfig() (p2p.RateLimiterConfig, error) {
+	config := p2p.RateLimiterConfig{
+		Default: p2p.RateLimit{
+			Rate:  rate.Limit(fnb.MessageRateLimit),
+			Burst: fnb.MessageRateBurst,
+		},
+		Channels: make(map[network.Channel]p2p.RateLimit, len(fnb.ChannelRateLimits)),
+	}
+
+	for channel, limit := range fnb.ChannelRateLimits {
+		if !engine.Exists(network.Channel(channel)) {
+			return p2p.RateLimiterConfig{}, fmt.Errorf("unknown channel: %s", channel)
+		}
+		parts := strings.Split(limit, ":")
+		if len(parts) != 2 {
+			return p2p.RateLimiterConfig{}, fmt.Errorf("rate limit of channel %s is not formatted as rate:burst: %s", channel, limit)
+		}
+		r, err := strconv.ParseFloat(parts[0], 64)
+		if err != nil {
+			return p2p.RateLimiterConfig{}, fmt.Errorf("invalid rate of channel %s: %w", channel, err)
+		}
+		burst, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return p2p.RateLimiterConfig{}, fmt.Errorf("invalid burst of channel %s: %w", channel, err)
+		}
+		config.Channels[network.Channel(channel)] = p2p.RateLimit{Rate: rate.Limit(r), Burst: burst}
+	}
+
+	return config, nil
+}
+
+// NetworkCodec returns the codec of the network payloads, which compresses outbound
+// payloads with the configured algorithm.
+func (fnb *FlowNodeBuilder) NetworkCodec(networkMetrics module.NetworkMetrics) (network.Codec, error) {
+	opts := []compression.Option{compression.WithThreshold(fnb.CompressionThreshold)}
+	if fnb.NetworkCompression != "" {
+		compressor, err := compression.CompressorByName(fnb.NetworkCompression)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, compression.WithCompressor(compressor))
+	}
+
+	return compression.NewCodec(cborcodec.NewCodec(), networkMetrics, opts...), nil
+}
+
 func (fnb *FlowNodeBuilder) EnqueueNetworkInit(ctx context.Context) {
 	fnb.Component("network", func(builder NodeBuilder, node *NodeConfig) (module.ReadyDoneAware, error) {
 
-		codec := cborcodec.NewCodec()
+		codec, err := fnb.NetworkCodec(fnb.Metrics.Network)
+		if err != nil {
+			return nil, fmt.Errorf("could not create network codec: %w", err)
+		}
 
 		myAddr := fnb.NodeConfig.Me.Address()
 		if fnb.BaseConfig.BindAddr != NotSet {
@@ -186,7 +259,16 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit(ctx context.Context) {
 		peerManagerFactory := p2p.PeerManagerFactory([]p2p.Option{p2p.WithInterval(fnb.PeerUpdateInterval)})
 		mwOpts = append(mwOpts, p2p.WithPeerManager(peerManagerFactory))
 
-		fnb.Middleware = p2p.NewMiddleware(
+		rateLimiterConfig, err := fnb.rateLimiterConfig()
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate limits: %w", err)
+		}
+		mwOpts = append(mwOpts, p2p.WithRateLimiter(rateLimiterConfig))
+		if fnb.PeerScoringEnabled {
+			mwOpts = append(mwOpts, p2p.WithPeerScoring(p2p.DefaultPeerScoringConfig()))
+		}
+
+		mw := p2p.NewMiddleware(
 			fnb.Logger.Level(zerolog.ErrorLevel),
 			libP2PNodeFactory,
 			fnb.Me.NodeID(),
@@ -197,6 +279,11 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit(ctx context.Context) {
 			fnb.IDTranslator,
 			mwOpts...,
 		)
+		fnb.Middleware = mw
+
+		fnb.AdminCommand("list-connected-peers", func(*NodeConfig) admin.CommandHandler {
+			return connectedPeersCommand(mw)
+		})
 
 		subscriptionManager := p2p.NewChannelSubscriptionManager(fnb.Middleware)
 
@@ -227,13 +314,22 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit(ctx context.Context) {
 
 		fnb.Network = net
 
+		if fnb.NetworkRecording != "" {
+			rec, err := recorder.NewRecorder(cborcodec.NewCodec(), fnb.NetworkRecording, recorder.WithMaxSize(fnb.NetworkRecordingSize))
+			if err != nil {
+				return nil, fmt.Errorf("could not create network recorder: %w", err)
+			}
+			fnb.Network = recorder.NewNetwork(fnb.Logger, net, rec)
+			fnb.Logger.Info().Str("path", fnb.NetworkRecording).Msg("recording inbound network messages")
+		}
+
 		idEvents := gadgets.NewIdentityDeltas(func() {
 			fnb.Middleware.UpdateNodeAddresses()
 			fnb.Middleware.UpdateAllowList()
 		})
 		fnb.ProtocolEvents.AddConsumer(idEvents)
 
-		return net, err
+		return fnb.Network, nil
 	})
 }
 
@@ -308,12 +404,12 @@ func (fnb *FlowNodeBuilder) initLogger() {
 
 	log.Info().Msgf("flow %s node starting up", fnb.BaseConfig.NodeRole)
 
-	// parse config log level and apply to logger
+	// parse config log level and apply it globally, so that it can be changed at runtime
 	lvl, err := zerolog.ParseLevel(strings.ToLower(fnb.BaseConfig.level))
 	if err != nil {
 		log.Fatal().Err(err).Msg("invalid log level")
 	}
-	log = log.Level(lvl)
+	zerolog.SetGlobalLevel(lvl)
 
 	fnb.Logger = log
 }
@@ -326,6 +422,10 @@ func (fnb *FlowNodeBuilder) initMetrics() {
 		fnb.MustNot(err).Msg("could not initialize tracer")
 		fnb.Logger.Info().Msg("Tracer Started")
 		fnb.Tracer = tracer
+
+		fnb.AdminCommand("set-tracing", func(*NodeConfig) admin.CommandHandler {
+			return setTracingCommand(tracer)
+		})
 	}
 
 	fnb.Metrics = Metrics{
@@ -354,6 +454,10 @@ func (fnb *FlowNodeBuilder) initMetrics() {
 		fnb.Component("mempools metrics", func(builder NodeBuilder, node *NodeConfig) (module.ReadyDoneAware, error) {
 			return mempools, nil
 		})
+
+		fnb.AdminCommand("list-mempool-sizes", func(*NodeConfig) admin.CommandHandler {
+			return mempoolSizesCommand(mempools)
+		})
 	}
 }
 
@@ -715,6 +819,30 @@ func (fnb *FlowNodeBuilder) Component(name string, f func(builder NodeBuilder, n
 	return fnb
 }
 
+// AdminCommand registers a new command of the admin server of the node.
+// The handler of the command is created once all components are initialized,
+// right before the admin server starts, so that it can use any of them.
+func (fnb *FlowNodeBuilder) AdminCommand(command string, f func(config *NodeConfig) admin.CommandHandler) NodeBuilder {
+	fnb.adminCommands = append(fnb.adminCommands, namedAdminCommand{
+		fn:      f,
+		command: command,
+	})
+	return fnb
+}
+
+// initAdminServer creates the admin server of the node with all registered admin commands.
+func (fnb *FlowNodeBuilder) initAdminServer(builder NodeBuilder, node *NodeConfig) (module.ReadyDoneAware, error) {
+	runner := admin.NewCommandRunner()
+	for _, c := range fnb.adminCommands {
+		err := runner.RegisterHandler(c.command, c.fn(node))
+		if err != nil {
+			return nil, fmt.Errorf("could not register admin command: %w", err)
+		}
+	}
+
+	return admin.NewServer(fnb.Logger, runner, fnb.BaseConfig.adminSocket), nil
+}
+
 func (fnb *FlowNodeBuilder) PreInit(f func(builder NodeBuilder, node *NodeConfig)) NodeBuilder {
 	fnb.preInitFns = append(fnb.preInitFns, f)
 	return fnb
@@ -811,6 +939,13 @@ func (fnb *FlowNodeBuilder) Initialize() NodeBuilder {
 
 	fnb.EnqueueTracer()
 
+	fnb.AdminCommand("set-log-level", func(*NodeConfig) admin.CommandHandler {
+		return setLogLevelCommand
+	})
+	fnb.AdminCommand("create-backup", func(node *NodeConfig) admin.CommandHandler {
+		return BackupCommand(node.DB, nil)
+	})
+
 	return fnb
 }
 
@@ -894,6 +1029,14 @@ func (fnb *FlowNodeBuilder) Ready() <-chan struct{} {
 		for _, f := range fnb.components {
 			fnb.handleComponent(f)
 		}
+
+		// the admin server starts last, so that admin commands can use all components
+		if fnb.BaseConfig.adminSocket != "" {
+			fnb.handleComponent(namedComponentFunc{
+				fn:   fnb.initAdminServer,
+				name: "admin server",
+			})
+		}
 	})
 	return fnb.lm.Started()
 }
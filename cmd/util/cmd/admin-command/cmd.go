@@ -0,0 +1,78 @@
+package admin_command
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/module/admin"
+)
+
+var (
+	flagSocket  string
+	flagTimeout time.Duration
+)
+
+var Cmd = &cobra.Command{
+	Use:   "admin-command <command> [json-data]",
+	Short: "Runs a command on the admin server of a node running on this host",
+	Long: `Runs a command on the admin server of a node running on this host, e.g.
+
+  util admin-command --socket /data/admin.sock list-commands
+  util admin-command --socket /data/admin.sock set-log-level '{"level": "debug"}'`,
+	Args: cobra.RangeArgs(1, 2),
+	Run:  run,
+}
+
+func init() {
+
+	Cmd.Flags().StringVar(&flagSocket, "socket", "",
+		"path of the unix socket the admin server of the node listens on")
+	_ = Cmd.MarkFlagRequired("socket")
+
+	Cmd.Flags().DurationVar(&flagTimeout, "timeout", 30*time.Second,
+		"maximum duration to wait for the command to complete")
+}
+
+func run(_ *cobra.Command, args []string) {
+
+	var data map[string]interface{}
+	if len(args) > 1 {
+		err := json.Unmarshal([]byte(args[1]), &data)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not parse command data, it must be a JSON object")
+		}
+	}
+
+	client, err := admin.NewClient(flagSocket)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create admin client")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), flagTimeout)
+	defer cancel()
+
+	output, err := client.RunCommand(ctx, args[0], data)
+	if err != nil {
+		log.Fatal().Err(err).Str("command", args[0]).Msg("could not run admin command")
+	}
+
+	// commands without output, e.g. returning nil, have no output in the response
+	if len(output) == 0 {
+		log.Info().Str("command", args[0]).Msg("admin command completed")
+		return
+	}
+
+	var indented bytes.Buffer
+	err = json.Indent(&indented, output, "", "  ")
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not format command output")
+	}
+
+	fmt.Println(indented.String())
+}
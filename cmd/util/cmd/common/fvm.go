@@ -0,0 +1,27 @@
+package common
+
+import (
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// FvmOptions returns the options the virtual machine of an execution node runs
+// with on the given chain, see FlowNodeBuilder.initFvmOptions. Transactions can
+// only look up blocks if headers is not nil.
+func FvmOptions(chainID flow.ChainID, headers storage.Headers) []fvm.Option {
+	vmOpts := []fvm.Option{
+		fvm.WithChain(chainID.Chain()),
+		fvm.WithAccountStorageLimit(true),
+	}
+	if headers != nil {
+		vmOpts = append(vmOpts, fvm.WithBlocks(fvm.NewBlockFinder(headers)))
+	}
+	if chainID == flow.Testnet || chainID == flow.Canary {
+		vmOpts = append(vmOpts,
+			fvm.WithRestrictedDeployment(false),
+			fvm.WithTransactionFeesEnabled(true),
+		)
+	}
+	return vmOpts
+}
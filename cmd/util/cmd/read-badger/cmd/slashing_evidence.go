@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+	"github.com/onflow/flow-go/model/encodable"
+	"github.com/onflow/flow-go/model/encoding"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+var (
+	flagOffenderID string
+	flagExportPath string
+)
+
+func init() {
+	rootCmd.AddCommand(slashingEvidenceCmd)
+
+	slashingEvidenceCmd.Flags().StringVarP(&flagOffenderID, "offender-id", "o", "", "the node id of the offender, all evidence is returned if empty")
+	slashingEvidenceCmd.Flags().StringVar(&flagExportPath, "export", "", "path of the file to export the evidence bundle to")
+}
+
+// evidenceBundle is a self-contained export of slashing evidence. The evidence
+// of the main consensus and of collection clusters is signed differently, the
+// signing parameters are hence given with each evidence. The signature of a vote
+// (SigData) or proposal (ProposerSig) of the main consensus is not a plain
+// staking signature, but the concatenation of the staking signature and the
+// random beacon signature share of the offender. It is split into both with
+//
+//	signature.NewCombiner(StakingSigLength, BeaconSigLength).Split(sig)
+//
+// The staking signature is verified with the staking key of the offender, the
+// signing tag and the signed message. The beacon share is verified with the
+// beacon key share of the offender from the DKG of the epoch, which isn't part
+// of the bundle, the beacon signing tag and the same message. The signatures of
+// collection clusters are plain staking signatures, without beacon share.
+type evidenceBundle struct {
+	ChainID  flow.ChainID // chain ID of the main consensus
+	Evidence []*bundledEvidence
+}
+
+type bundledEvidence struct {
+	*flow.SlashingEvidence
+	SigningTag       string   // domain separation tag of the staking signatures
+	BeaconSigningTag string   // domain separation tag of the random beacon signature shares, empty for clusters
+	StakingSigLength uint     // length of the staking signature, which comes first
+	BeaconSigLength  uint     // length of the random beacon signature share, which comes second, 0 for clusters
+	VoteMessages     [][]byte // messages signed by the votes, in the order of the votes
+	ProposalMessages [][]byte // messages signed by the proposers, in the order of the proposals
+}
+
+var slashingEvidenceCmd = &cobra.Command{
+	Use:   "slashing-evidence",
+	Short: "get the evidence of slashable protocol violations, optionally exported as a verifiable bundle",
+	Run: func(cmd *cobra.Command, args []string) {
+		storages, db := InitStorages()
+		defer db.Close()
+
+		store := badger.NewSlashingEvidence(db)
+
+		var evidence []*flow.SlashingEvidence
+		var err error
+		if flagOffenderID != "" {
+			log.Info().Msgf("got flag offender id: %s", flagOffenderID)
+			offenderID, err := flow.HexStringToIdentifier(flagOffenderID)
+			if err != nil {
+				log.Error().Err(err).Msg("malformed offender id")
+				return
+			}
+
+			log.Info().Msgf("getting slashing evidence by offender id: %v", offenderID)
+			evidence, err = store.ByOffender(offenderID)
+			if err != nil {
+				log.Error().Err(err).Msgf("could not get slashing evidence for offender id: %v", offenderID)
+				return
+			}
+		} else {
+			log.Info().Msg("getting all slashing evidence")
+			evidence, err = store.All()
+			if err != nil {
+				log.Error().Err(err).Msg("could not get slashing evidence")
+				return
+			}
+		}
+
+		if flagExportPath == "" {
+			common.PrettyPrint(evidence)
+			return
+		}
+
+		var rootHeight uint64
+		err = db.View(operation.RetrieveRootHeight(&rootHeight))
+		if err != nil {
+			log.Error().Err(err).Msg("could not get root height")
+			return
+		}
+		root, err := storages.Headers.ByHeight(rootHeight)
+		if err != nil {
+			log.Error().Err(err).Msg("could not get root block")
+			return
+		}
+
+		bundle := evidenceBundle{
+			ChainID: root.ChainID,
+		}
+		for _, e := range evidence {
+			bundled := &bundledEvidence{
+				SlashingEvidence: e,
+				SigningTag:       encoding.CollectorVoteTag,
+				StakingSigLength: encodable.ConsensusVoteSigLen,
+			}
+			if e.ChainID == root.ChainID {
+				bundled.SigningTag = encoding.ConsensusVoteTag
+				bundled.BeaconSigningTag = encoding.RandomBeaconTag
+				bundled.BeaconSigLength = encodable.RandomBeaconSigLen
+			}
+			for _, vote := range e.Votes {
+				bundled.VoteMessages = append(bundled.VoteMessages, verification.MakeVoteMessage(vote.View, vote.BlockID))
+			}
+			for _, proposal := range e.Proposals {
+				bundled.ProposalMessages = append(bundled.ProposalMessages, verification.MakeVoteMessage(proposal.View, proposal.ID()))
+			}
+			bundle.Evidence = append(bundle.Evidence, bundled)
+		}
+
+		bytes, err := json.MarshalIndent(bundle, "", "  ")
+		if err != nil {
+			log.Error().Err(err).Msg("could not encode evidence bundle")
+			return
+		}
+		err = ioutil.WriteFile(flagExportPath, bytes, 0644)
+		if err != nil {
+			log.Error().Err(err).Msgf("could not write evidence bundle to %s", flagExportPath)
+			return
+		}
+
+		log.Info().Msgf("exported %d slashing evidence to %s", len(evidence), flagExportPath)
+	},
+}
@@ -0,0 +1,219 @@
+package reexecute
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+var (
+	flagDatadir           string
+	flagExecutionStateDir string
+	flagFromHeight        uint64
+	flagToHeight          uint64
+	flagMTrieCacheSize    int
+	flagReportFile        string
+)
+
+// run with `./util reexecute-blocks --datadir /var/flow/data/protocol --execution-state-dir /var/flow/data/execution --from-height 100 --to-height 200`
+// on a copy of the data of a stopped execution node
+var Cmd = &cobra.Command{
+	Use:   "reexecute-blocks",
+	Short: "Re-executes finalized blocks and reports the differences with the stored execution results",
+	Run:   run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().StringVar(&flagExecutionStateDir, "execution-state-dir", "",
+		"Execution Node state dir (where the checkpoints and WAL logs are written)")
+	_ = Cmd.MarkFlagRequired("execution-state-dir")
+
+	Cmd.Flags().Uint64Var(&flagFromHeight, "from-height", 0,
+		"height of the first block to re-execute")
+	_ = Cmd.MarkFlagRequired("from-height")
+
+	Cmd.Flags().Uint64Var(&flagToHeight, "to-height", 0,
+		"height of the last block to re-execute (defaults to the first one)")
+
+	Cmd.Flags().IntVar(&flagMTrieCacheSize, "mtrie-cache-size", complete.DefaultCacheSize,
+		"number of tries kept in memory, has to cover the states the blocks are re-executed on")
+
+	Cmd.Flags().StringVar(&flagReportFile, "report-file", "",
+		"file to write the reports of mismatching blocks to, as JSON lines")
+}
+
+func run(*cobra.Command, []string) {
+	toHeight := flagToHeight
+	if toHeight < flagFromHeight {
+		toHeight = flagFromHeight
+	}
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	storages := common.InitStorages(db)
+	state, err := common.InitProtocolState(db, storages)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not init protocol state")
+	}
+
+	chainID, err := state.Params().ChainID()
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not get chain ID")
+	}
+
+	diskWal, err := wal.NewDiskWAL(
+		zerolog.Nop(),
+		nil,
+		metrics.NewNoopCollector(),
+		flagExecutionStateDir,
+		flagMTrieCacheSize,
+		pathfinder.PathByteSize,
+		wal.SegmentSize,
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create disk WAL")
+	}
+	defer func() {
+		<-diskWal.Done()
+	}()
+
+	led, err := complete.NewLedger(diskWal, flagMTrieCacheSize, &metrics.NoopCollector{}, log.Logger, complete.DefaultPathFinderVersion)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not load ledger from checkpoint and WAL")
+	}
+
+	// the re-executed states must not be appended to the WAL of the execution node
+	diskWal.PauseRecord()
+
+	vm := fvm.NewVirtualMachine(fvm.NewInterpreterRuntime())
+	vmCtx := fvm.NewContext(log.Logger, common.FvmOptions(chainID, storages.Headers)...)
+
+	reexecutor, err := NewReexecutor(log.Logger, led, vm, vmCtx, db, storages)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create re-executor")
+	}
+
+	var reportFile *os.File
+	if flagReportFile != "" {
+		reportFile, err = os.Create(flagReportFile)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not create report file")
+		}
+		defer reportFile.Close()
+	}
+
+	mismatches := 0
+	for height := flagFromHeight; height <= toHeight; height++ {
+		report, err := reexecutor.ReexecuteBlock(context.Background(), height)
+		if err != nil {
+			log.Fatal().Err(err).Uint64("height", height).Msg("could not re-execute block")
+		}
+
+		if report.Matches() {
+			log.Info().
+				Uint64("height", height).
+				Hex("block_id", report.BlockID[:]).
+				Msg("re-execution matches the execution result")
+			continue
+		}
+
+		mismatches++
+		logReport(report)
+
+		if reportFile != nil {
+			err = json.NewEncoder(reportFile).Encode(report)
+			if err != nil {
+				log.Fatal().Err(err).Msg("could not write report")
+			}
+		}
+	}
+
+	log.Info().
+		Uint64("from_height", flagFromHeight).
+		Uint64("to_height", toHeight).
+		Int("mismatches", mismatches).
+		Msg("re-execution finished")
+}
+
+func logReport(report *BlockReport) {
+	lg := log.With().
+		Uint64("height", report.Height).
+		Hex("block_id", report.BlockID[:]).
+		Logger()
+
+	lg.Warn().
+		Hex("expected_final_state", report.ExpectedFinalState[:]).
+		Hex("actual_final_state", report.ActualFinalState[:]).
+		Int("mismatching_chunks", len(report.Chunks)).
+		Int("mismatching_transactions", len(report.Transactions)).
+		Int("mismatching_events", len(report.Events)).
+		Msg("re-execution does not match the execution result")
+
+	for _, chunk := range report.Chunks {
+		lg.Warn().
+			Uint64("chunk_index", chunk.Index).
+			Hex("expected_end_state", chunk.ExpectedEndState[:]).
+			Hex("actual_end_state", chunk.ActualEndState[:]).
+			Hex("expected_events_hash", chunk.ExpectedEventsHash[:]).
+			Hex("actual_events_hash", chunk.ActualEventsHash[:]).
+			Int("mismatching_registers", len(chunk.Registers)).
+			Msg("chunk mismatch")
+
+		for _, register := range chunk.Registers {
+			event := lg.Warn().
+				Uint64("chunk_index", chunk.Index).
+				Str("register", register.RegisterID.String()).
+				Hex("tx_id", register.TransactionID[:])
+			if register.Expected != nil {
+				event = event.Hex("expected_value", *register.Expected)
+			}
+			if register.Actual != nil {
+				event = event.Hex("actual_value", *register.Actual)
+			}
+			event.Msg("register mismatch")
+		}
+	}
+
+	for _, tx := range report.Transactions {
+		event := lg.Warn().
+			Uint32("tx_index", tx.Index).
+			Hex("tx_id", tx.Actual.TransactionID[:]).
+			Str("actual_error", tx.Actual.ErrorMessage).
+			Uint64("actual_computation_used", tx.Actual.ComputationUsed)
+		if tx.Expected != nil {
+			event = event.
+				Str("expected_error", tx.Expected.ErrorMessage).
+				Uint64("expected_computation_used", tx.Expected.ComputationUsed)
+		}
+		event.Msg("transaction result mismatch")
+	}
+
+	for _, e := range report.Events {
+		event := lg.Warn().
+			Hex("tx_id", e.TransactionID[:]).
+			Uint32("event_index", e.EventIndex)
+		if e.Expected != nil {
+			event = event.Str("expected_type", string(e.Expected.Type)).Hex("expected_payload", e.Expected.Payload)
+		}
+		if e.Actual != nil {
+			event = event.Str("actual_type", string(e.Actual.Type)).Hex("actual_payload", e.Actual.Payload)
+		}
+		event.Msg("event mismatch")
+	}
+}
@@ -0,0 +1,454 @@
+package reexecute
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/engine/execution/computation/committer"
+	"github.com/onflow/flow-go/engine/execution/computation/computer"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/programs"
+	fvmState "github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool/entity"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/trace"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// RegisterDiff is a register written differently by the re-execution of a chunk.
+// Expected or Actual is nil if the register was not written by the stored or by
+// the re-executed chunk respectively.
+type RegisterDiff struct {
+	RegisterID flow.RegisterID
+	Expected   *flow.RegisterValue
+	Actual     *flow.RegisterValue
+	// TransactionID is the last transaction of the re-executed chunk which wrote
+	// the register, or the zero ID if no transaction wrote it.
+	TransactionID flow.Identifier
+}
+
+// ChunkDiff is a chunk whose re-execution differs from the stored execution result.
+// Registers are only compared if the execution state interactions of the block
+// are stored, otherwise chunks only differ by their end states and events.
+type ChunkDiff struct {
+	Index              uint64
+	ExpectedEndState   flow.StateCommitment
+	ActualEndState     flow.StateCommitment
+	ExpectedEventsHash flow.Identifier
+	ActualEventsHash   flow.Identifier
+	Registers          []RegisterDiff
+}
+
+// TransactionDiff is a transaction whose re-execution result differs from the
+// stored one. Expected is nil if no result was stored for the transaction.
+type TransactionDiff struct {
+	Index    uint32
+	Expected *flow.TransactionResult
+	Actual   flow.TransactionResult
+}
+
+// EventDiff is an event emitted differently by the re-execution of a
+// transaction. Expected or Actual is nil if the event was not emitted by the
+// stored or by the re-executed transaction respectively.
+type EventDiff struct {
+	TransactionID flow.Identifier
+	EventIndex    uint32
+	Expected      *flow.Event
+	Actual        *flow.Event
+}
+
+func (d *EventDiff) transactionIndex() uint32 {
+	if d.Actual != nil {
+		return d.Actual.TransactionIndex
+	}
+	return d.Expected.TransactionIndex
+}
+
+// BlockReport is the result of re-executing a block.
+type BlockReport struct {
+	BlockID            flow.Identifier
+	Height             uint64
+	ExpectedFinalState flow.StateCommitment
+	ActualFinalState   flow.StateCommitment
+	Chunks             []ChunkDiff
+	Transactions       []TransactionDiff
+	Events             []EventDiff
+}
+
+// Matches returns whether the re-execution of the block matches the stored
+// execution result.
+func (r *BlockReport) Matches() bool {
+	return r.ExpectedFinalState == r.ActualFinalState &&
+		len(r.Chunks) == 0 &&
+		len(r.Transactions) == 0 &&
+		len(r.Events) == 0
+}
+
+// storedExecution is what the execution node stored when it executed a block.
+type storedExecution struct {
+	result *flow.ExecutionResult
+	// interactions is nil if the execution state interactions are not stored
+	interactions []*delta.Snapshot
+	// transactionResults are in the order of execution, with nil for
+	// transactions without a stored result
+	transactionResults []*flow.TransactionResult
+	events             []flow.Event
+}
+
+// recordingVM records the registers written by each transaction, so that
+// register mismatches of a chunk can be attributed to its transactions.
+type recordingVM struct {
+	computer.VirtualMachine
+	writes map[uint32]delta.Delta
+}
+
+func (vm *recordingVM) Run(ctx fvm.Context, proc fvm.Procedure, view fvmState.View, programs *programs.Programs) error {
+	err := vm.VirtualMachine.Run(ctx, proc, view, programs)
+
+	tx, ok := proc.(*fvm.TransactionProcedure)
+	if !ok {
+		return err
+	}
+	txView, ok := view.(*delta.View)
+	if ok {
+		vm.writes[tx.TxIndex] = txView.Delta()
+	}
+
+	return err
+}
+
+// Reexecutor re-executes finalized blocks on top of the stored state commitments
+// of their parents and compares the results with the stored execution results.
+type Reexecutor struct {
+	ledger             ledger.Ledger
+	vm                 *recordingVM
+	computer           computer.BlockComputer
+	db                 *badger.DB
+	headers            storage.Headers
+	blocks             storage.Blocks
+	collections        storage.Collections
+	commits            storage.Commits
+	results            storage.ExecutionResults
+	transactionResults storage.TransactionResults
+	events             storage.Events
+}
+
+// NewReexecutor creates a new re-executor, which executes blocks on top of the
+// given ledger.
+func NewReexecutor(
+	log zerolog.Logger,
+	ldg ledger.Ledger,
+	vm computer.VirtualMachine,
+	vmCtx fvm.Context,
+	db *badger.DB,
+	storages *storage.All,
+) (*Reexecutor, error) {
+	tracer := trace.NewNoopTracer()
+	recorder := &recordingVM{VirtualMachine: vm}
+
+	blockComputer, err := computer.NewBlockComputer(
+		recorder,
+		vmCtx,
+		metrics.NewNoopCollector(),
+		tracer,
+		log,
+		committer.NewLedgerViewCommitter(ldg, tracer),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create block computer: %w", err)
+	}
+
+	return &Reexecutor{
+		ledger:             ldg,
+		vm:                 recorder,
+		computer:           blockComputer,
+		db:                 db,
+		headers:            storages.Headers,
+		blocks:             storages.Blocks,
+		collections:        storages.Collections,
+		commits:            storages.Commits,
+		results:            storages.Results,
+		transactionResults: storages.TransactionResults,
+		events:             storages.Events,
+	}, nil
+}
+
+// ReexecuteBlock re-executes the finalized block at the given height.
+func (r *Reexecutor) ReexecuteBlock(ctx context.Context, height uint64) (*BlockReport, error) {
+	header, err := r.headers.ByHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("could not get finalized block at height %d: %w", height, err)
+	}
+	blockID := header.ID()
+
+	block, err := r.blocks.ByID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get block %v: %w", blockID, err)
+	}
+
+	completeCollections := make(map[flow.Identifier]*entity.CompleteCollection, len(block.Payload.Guarantees))
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := r.collections.ByID(guarantee.CollectionID)
+		if err != nil {
+			return nil, fmt.Errorf("could not get collection %v: %w", guarantee.CollectionID, err)
+		}
+		completeCollections[guarantee.ID()] = &entity.CompleteCollection{
+			Guarantee:    guarantee,
+			Transactions: collection.Transactions,
+		}
+	}
+
+	startState, err := r.commits.ByBlockID(header.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get state commitment of parent block %v: %w", header.ParentID, err)
+	}
+
+	executableBlock := &entity.ExecutableBlock{
+		Block:               block,
+		CompleteCollections: completeCollections,
+		StartState:          &startState,
+	}
+
+	r.vm.writes = make(map[uint32]delta.Delta)
+	view := delta.NewView(state.LedgerGetRegister(r.ledger, startState))
+	computationResult, err := r.computer.ExecuteBlock(ctx, executableBlock, view, programs.NewEmptyPrograms())
+	if err != nil {
+		return nil, fmt.Errorf("could not re-execute block %v: %w", blockID, err)
+	}
+
+	stored, err := r.storedExecution(blockID, computationResult.TransactionResults)
+	if err != nil {
+		return nil, fmt.Errorf("could not get stored execution of block %v: %w", blockID, err)
+	}
+
+	return compareExecution(stored, computationResult, r.vm.writes), nil
+}
+
+func (r *Reexecutor) storedExecution(blockID flow.Identifier, txResults []flow.TransactionResult) (*storedExecution, error) {
+	result, err := r.results.ByBlockID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get execution result: %w", err)
+	}
+
+	// execution nodes only store the interactions of the root block, so for
+	// other blocks the chunks are compared without their registers
+	var interactions []*delta.Snapshot
+	err = r.db.View(operation.RetrieveExecutionStateInteractions(blockID, &interactions))
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("could not get execution state interactions: %w", err)
+	}
+
+	transactionResults := make([]*flow.TransactionResult, 0, len(txResults))
+	for _, txResult := range txResults {
+		stored, err := r.transactionResults.ByBlockIDTransactionID(blockID, txResult.TransactionID)
+		if errors.Is(err, storage.ErrNotFound) {
+			transactionResults = append(transactionResults, nil)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not get result of transaction %v: %w", txResult.TransactionID, err)
+		}
+		transactionResults = append(transactionResults, stored)
+	}
+
+	events, err := r.events.ByBlockID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get events: %w", err)
+	}
+
+	return &storedExecution{
+		result:             result,
+		interactions:       interactions,
+		transactionResults: transactionResults,
+		events:             events,
+	}, nil
+}
+
+// compareExecution compares the re-execution of a block with its stored execution.
+// The registers written by each re-executed transaction are given by transaction
+// index.
+func compareExecution(stored *storedExecution, actual *execution.ComputationResult, writes map[uint32]delta.Delta) *BlockReport {
+	report := &BlockReport{
+		BlockID: actual.ExecutableBlock.ID(),
+		Height:  actual.ExecutableBlock.Height(),
+	}
+
+	report.ExpectedFinalState, _ = stored.result.FinalStateCommitment()
+	if len(actual.StateCommitments) > 0 {
+		report.ActualFinalState = actual.StateCommitments[len(actual.StateCommitments)-1]
+	}
+
+	// the transactions of each re-executed chunk, the last chunk being the system chunk
+	var chunkTransactions [][]flow.TransactionResult
+	offset := 0
+	for _, collection := range actual.ExecutableBlock.Collections() {
+		chunkTransactions = append(chunkTransactions, actual.TransactionResults[offset:offset+len(collection.Transactions)])
+		offset += len(collection.Transactions)
+	}
+	chunkTransactions = append(chunkTransactions, actual.TransactionResults[offset:])
+
+	chunks := len(stored.result.Chunks)
+	if len(actual.StateCommitments) > chunks {
+		chunks = len(actual.StateCommitments)
+	}
+
+	offset = 0
+	for i := 0; i < chunks; i++ {
+		diff := ChunkDiff{Index: uint64(i)}
+
+		var expectedDelta, actualDelta delta.Delta
+		if i < len(stored.result.Chunks) {
+			diff.ExpectedEndState = stored.result.Chunks[i].EndState
+			diff.ExpectedEventsHash = stored.result.Chunks[i].EventCollection
+		}
+		if i < len(stored.interactions) {
+			expectedDelta = stored.interactions[i].Delta
+		}
+		if i < len(actual.StateCommitments) {
+			diff.ActualEndState = actual.StateCommitments[i]
+		}
+		if i < len(actual.EventsHashes) {
+			diff.ActualEventsHash = actual.EventsHashes[i]
+		}
+		if i < len(actual.StateSnapshots) {
+			actualDelta = actual.StateSnapshots[i].Delta
+		}
+
+		var transactions []flow.TransactionResult
+		if i < len(chunkTransactions) {
+			transactions = chunkTransactions[i]
+		}
+		if stored.interactions != nil {
+			diff.Registers = compareRegisters(expectedDelta, actualDelta, transactions, uint32(offset), writes)
+		}
+		offset += len(transactions)
+
+		if diff.ExpectedEndState != diff.ActualEndState || diff.ExpectedEventsHash != diff.ActualEventsHash || len(diff.Registers) > 0 {
+			report.Chunks = append(report.Chunks, diff)
+		}
+	}
+
+	for i, txResult := range actual.TransactionResults {
+		var expected *flow.TransactionResult
+		if i < len(stored.transactionResults) {
+			expected = stored.transactionResults[i]
+		}
+		if expected != nil && *expected == txResult {
+			continue
+		}
+		report.Transactions = append(report.Transactions, TransactionDiff{
+			Index:    uint32(i),
+			Expected: expected,
+			Actual:   txResult,
+		})
+	}
+
+	var actualEvents []flow.Event
+	for _, events := range actual.Events {
+		actualEvents = append(actualEvents, events...)
+	}
+	report.Events = compareEvents(stored.events, actualEvents)
+
+	return report
+}
+
+// compareRegisters compares the registers written by a stored and a re-executed
+// chunk, attributing each mismatch to the last re-executed transaction of the
+// chunk which wrote the register.
+func compareRegisters(expected delta.Delta, actual delta.Delta, transactions []flow.TransactionResult, txIndex uint32, writes map[uint32]delta.Delta) []RegisterDiff {
+	keys := make(map[string]flow.RegisterID)
+	for key, entry := range expected.Data {
+		keys[key] = entry.Key
+	}
+	for key, entry := range actual.Data {
+		keys[key] = entry.Key
+	}
+
+	var diffs []RegisterDiff
+	for key, registerID := range keys {
+		expectedEntry, expectedWritten := expected.Data[key]
+		actualEntry, actualWritten := actual.Data[key]
+		if expectedWritten && actualWritten && bytes.Equal(expectedEntry.Value, actualEntry.Value) {
+			continue
+		}
+
+		diff := RegisterDiff{RegisterID: registerID}
+		if expectedWritten {
+			diff.Expected = &expectedEntry.Value
+		}
+		if actualWritten {
+			diff.Actual = &actualEntry.Value
+		}
+		for i := len(transactions) - 1; i >= 0; i-- {
+			if _, ok := writes[txIndex+uint32(i)].Data[key]; ok {
+				diff.TransactionID = transactions[i].TransactionID
+				break
+			}
+		}
+
+		diffs = append(diffs, diff)
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].RegisterID.String() < diffs[j].RegisterID.String()
+	})
+
+	return diffs
+}
+
+type eventKey struct {
+	transactionID flow.Identifier
+	eventIndex    uint32
+}
+
+// compareEvents compares the events emitted by the stored and the re-executed
+// transactions of a block.
+func compareEvents(expected []flow.Event, actual []flow.Event) []EventDiff {
+	diffs := make(map[eventKey]*EventDiff)
+	diff := func(event flow.Event) *EventDiff {
+		key := eventKey{transactionID: event.TransactionID, eventIndex: event.EventIndex}
+		if _, ok := diffs[key]; !ok {
+			diffs[key] = &EventDiff{TransactionID: event.TransactionID, EventIndex: event.EventIndex}
+		}
+		return diffs[key]
+	}
+
+	for i := range expected {
+		diff(expected[i]).Expected = &expected[i]
+	}
+	for i := range actual {
+		diff(actual[i]).Actual = &actual[i]
+	}
+
+	var result []EventDiff
+	for _, d := range diffs {
+		if d.Expected != nil && d.Actual != nil &&
+			d.Expected.Type == d.Actual.Type &&
+			d.Expected.TransactionIndex == d.Actual.TransactionIndex &&
+			bytes.Equal(d.Expected.Payload, d.Actual.Payload) {
+			continue
+		}
+		result = append(result, *d)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].transactionIndex() != result[j].transactionIndex() {
+			return result[i].transactionIndex() < result[j].transactionIndex()
+		}
+		return result[i].EventIndex < result[j].EventIndex
+	})
+
+	return result
+}
@@ -0,0 +1,328 @@
+package reexecute
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/engine/execution/computation/committer"
+	"github.com/onflow/flow-go/engine/execution/computation/computer"
+	"github.com/onflow/flow-go/engine/execution/state"
+	bootstrapexec "github.com/onflow/flow-go/engine/execution/state/bootstrap"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/engine/execution/testutil"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/blueprints"
+	"github.com/onflow/flow-go/fvm/programs"
+	completeLedger "github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool/entity"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/trace"
+	storagebadger "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestCompareExecution(t *testing.T) {
+
+	// a block with a collection of two transactions, and the system chunk
+	collection := unittest.CollectionFixture(2)
+	guarantee := &flow.CollectionGuarantee{CollectionID: collection.ID()}
+	block := unittest.BlockFixture()
+	block.Payload.Guarantees = []*flow.CollectionGuarantee{guarantee}
+	executableBlock := &entity.ExecutableBlock{
+		Block: &block,
+		CompleteCollections: map[flow.Identifier]*entity.CompleteCollection{
+			guarantee.ID(): {Guarantee: guarantee, Transactions: collection.Transactions},
+		},
+	}
+
+	txResults := []flow.TransactionResult{
+		{TransactionID: collection.Transactions[0].ID(), ComputationUsed: 1},
+		{TransactionID: collection.Transactions[1].ID(), ComputationUsed: 2},
+		{TransactionID: unittest.IdentifierFixture(), ComputationUsed: 3},
+	}
+
+	events := []flow.EventsList{
+		{
+			unittest.EventFixture(flow.EventAccountCreated, 0, 0, txResults[0].TransactionID, 10),
+			unittest.EventFixture(flow.EventAccountUpdated, 1, 0, txResults[1].TransactionID, 10),
+		},
+		{},
+	}
+
+	// the first transaction writes a register, which the second one overwrites
+	first := delta.NewDelta()
+	first.Set("owner", "", "key", flow.RegisterValue("first"))
+	first.Set("owner", "", "other", flow.RegisterValue("other"))
+	second := delta.NewDelta()
+	second.Set("owner", "", "key", flow.RegisterValue("second"))
+	chunkDelta := delta.NewDelta()
+	chunkDelta.MergeWith(first)
+	chunkDelta.MergeWith(second)
+
+	writes := map[uint32]delta.Delta{0: first, 1: second, 2: delta.NewDelta()}
+
+	actual := &execution.ComputationResult{
+		ExecutableBlock:  executableBlock,
+		StateCommitments: []flow.StateCommitment{unittest.StateCommitmentFixture(), unittest.StateCommitmentFixture()},
+		EventsHashes:     []flow.Identifier{unittest.IdentifierFixture(), unittest.IdentifierFixture()},
+		StateSnapshots: []*delta.SpockSnapshot{
+			{Snapshot: delta.Snapshot{Delta: chunkDelta}},
+			{Snapshot: delta.Snapshot{Delta: delta.NewDelta()}},
+		},
+		TransactionResults: txResults,
+		Events:             events,
+	}
+
+	// the stored execution, as it would have been stored by the execution node
+	stored := func() *storedExecution {
+		storedDelta := delta.NewDelta()
+		storedDelta.MergeWith(chunkDelta)
+
+		result := unittest.ExecutionResultFixture()
+		result.Chunks = flow.ChunkList{
+			{EndState: actual.StateCommitments[0], ChunkBody: flow.ChunkBody{EventCollection: actual.EventsHashes[0]}},
+			{EndState: actual.StateCommitments[1], ChunkBody: flow.ChunkBody{EventCollection: actual.EventsHashes[1]}},
+		}
+
+		storedResults := make([]*flow.TransactionResult, 0, len(txResults))
+		for i := range txResults {
+			txResult := txResults[i]
+			storedResults = append(storedResults, &txResult)
+		}
+
+		return &storedExecution{
+			result: result,
+			interactions: []*delta.Snapshot{
+				{Delta: storedDelta},
+				{Delta: delta.NewDelta()},
+			},
+			transactionResults: storedResults,
+			events:             []flow.Event{events[0][1], events[0][0]},
+		}
+	}
+
+	t.Run("matching execution", func(t *testing.T) {
+		report := compareExecution(stored(), actual, writes)
+		assert.True(t, report.Matches())
+		assert.Equal(t, block.ID(), report.BlockID)
+		assert.Equal(t, actual.StateCommitments[1], report.ActualFinalState)
+	})
+
+	t.Run("mismatching registers", func(t *testing.T) {
+		expected := stored()
+		expected.result.Chunks[0].EndState = unittest.StateCommitmentFixture()
+		expected.result.Chunks[1].EndState = unittest.StateCommitmentFixture()
+		expected.interactions[0].Delta.Set("owner", "", "key", flow.RegisterValue("expected"))
+		expected.interactions[0].Delta.Set("owner", "", "missing", flow.RegisterValue("missing"))
+
+		report := compareExecution(expected, actual, writes)
+		require.False(t, report.Matches())
+		assert.Equal(t, expected.result.Chunks[1].EndState, report.ExpectedFinalState)
+
+		require.Len(t, report.Chunks, 2)
+		chunk := report.Chunks[0]
+		assert.Equal(t, uint64(0), chunk.Index)
+		assert.Equal(t, expected.result.Chunks[0].EndState, chunk.ExpectedEndState)
+		assert.Equal(t, actual.StateCommitments[0], chunk.ActualEndState)
+
+		require.Len(t, chunk.Registers, 2)
+		assert.Equal(t, flow.NewRegisterID("owner", "", "key"), chunk.Registers[0].RegisterID)
+		assert.Equal(t, flow.RegisterValue("expected"), *chunk.Registers[0].Expected)
+		assert.Equal(t, flow.RegisterValue("second"), *chunk.Registers[0].Actual)
+		assert.Equal(t, txResults[1].TransactionID, chunk.Registers[0].TransactionID)
+
+		assert.Equal(t, flow.NewRegisterID("owner", "", "missing"), chunk.Registers[1].RegisterID)
+		assert.Equal(t, flow.RegisterValue("missing"), *chunk.Registers[1].Expected)
+		assert.Nil(t, chunk.Registers[1].Actual)
+		assert.Equal(t, flow.ZeroID, chunk.Registers[1].TransactionID)
+
+		assert.Empty(t, report.Chunks[1].Registers)
+		assert.Empty(t, report.Transactions)
+		assert.Empty(t, report.Events)
+	})
+
+	t.Run("without stored interactions", func(t *testing.T) {
+		expected := stored()
+		expected.interactions = nil
+		expected.result.Chunks[0].EndState = unittest.StateCommitmentFixture()
+
+		report := compareExecution(expected, actual, writes)
+		require.False(t, report.Matches())
+
+		require.Len(t, report.Chunks, 1)
+		assert.Equal(t, uint64(0), report.Chunks[0].Index)
+		assert.Empty(t, report.Chunks[0].Registers)
+	})
+
+	t.Run("mismatching transaction results", func(t *testing.T) {
+		expected := stored()
+		expected.transactionResults[0].ErrorMessage = "failed"
+		expected.transactionResults[2] = nil
+
+		report := compareExecution(expected, actual, writes)
+		require.False(t, report.Matches())
+
+		require.Len(t, report.Transactions, 2)
+		assert.Equal(t, uint32(0), report.Transactions[0].Index)
+		assert.Equal(t, "failed", report.Transactions[0].Expected.ErrorMessage)
+		assert.Equal(t, txResults[0], report.Transactions[0].Actual)
+		assert.Equal(t, uint32(2), report.Transactions[1].Index)
+		assert.Nil(t, report.Transactions[1].Expected)
+	})
+
+	t.Run("mismatching events", func(t *testing.T) {
+		expected := stored()
+		expected.events[0].Payload = []byte("expected")
+		expected.events = append(expected.events, unittest.EventFixture(flow.EventAccountCreated, 0, 1, txResults[0].TransactionID, 10))
+
+		report := compareExecution(expected, actual, writes)
+		require.False(t, report.Matches())
+
+		require.Len(t, report.Events, 2)
+		assert.Equal(t, txResults[0].TransactionID, report.Events[0].TransactionID)
+		assert.Equal(t, uint32(1), report.Events[0].EventIndex)
+		assert.Nil(t, report.Events[0].Actual)
+		assert.Equal(t, txResults[1].TransactionID, report.Events[1].TransactionID)
+		assert.Equal(t, []byte("expected"), report.Events[1].Expected.Payload)
+		assert.Equal(t, events[0][1].Payload, report.Events[1].Actual.Payload)
+	})
+}
+
+// TestReexecuteBlock executes a block like an execution node and persists its
+// execution, then checks that re-executing the block yields the same final state
+// commitment and events.
+func TestReexecuteBlock(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		chain := flow.Testnet.Chain()
+		logger := zerolog.Nop()
+		collector := metrics.NewNoopCollector()
+		tracer := trace.NewNoopTracer()
+
+		vm := fvm.NewVirtualMachine(fvm.NewInterpreterRuntime())
+		vmCtx := fvm.NewContext(logger, fvm.WithChain(chain))
+
+		ldg, err := completeLedger.NewLedger(&fixtures.NoopWAL{}, 100, collector, logger, completeLedger.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		initialCommit, err := bootstrapexec.NewBootstrapper(logger).BootstrapLedger(
+			ldg,
+			unittest.ServiceAccountPublicKey,
+			chain,
+			fvm.WithInitialTokenSupply(unittest.GenesisTokenSupply),
+		)
+		require.NoError(t, err)
+
+		deployTx := blueprints.DeployContractTransaction(chain.ServiceAddress(), []byte(""+
+			`pub contract Foo {
+				pub event FooEvent(x: Int, y: Int)
+
+				pub fun event() {
+					emit FooEvent(x: 2, y: 1)
+				}
+			}`), "Foo")
+		emitTx := &flow.TransactionBody{
+			Script: []byte(fmt.Sprintf(`
+			import Foo from 0x%s
+			transaction {
+				prepare() {}
+				execute {
+					Foo.event()
+				}
+			}`, chain.ServiceAddress())),
+		}
+		require.NoError(t, testutil.SignTransactionAsServiceAccount(deployTx, 0, chain))
+		require.NoError(t, testutil.SignTransactionAsServiceAccount(emitTx, 1, chain))
+
+		// the block is the child of the last executed block
+		parent := unittest.BlockHeaderFixture()
+		collection := unittest.CompleteCollectionFromTransactions([]*flow.TransactionBody{deployTx, emitTx})
+		block := unittest.BlockWithParentFixture(&parent)
+		block.SetPayload(flow.Payload{Guarantees: []*flow.CollectionGuarantee{collection.Guarantee}})
+		executableBlock := &entity.ExecutableBlock{
+			Block:               &block,
+			CompleteCollections: map[flow.Identifier]*entity.CompleteCollection{collection.Guarantee.ID(): collection},
+			StartState:          &initialCommit,
+		}
+
+		// store the executed parent, the finalized block and its collection
+		storages := storagebadger.InitAll(collector, db)
+		require.NoError(t, storages.Headers.Store(&parent))
+		require.NoError(t, storages.Commits.Store(parent.ID(), initialCommit))
+		require.NoError(t, db.Update(operation.InsertExecutedBlock(parent.ID())))
+		require.NoError(t, storages.Blocks.Store(&block))
+		require.NoError(t, db.Update(operation.IndexBlockHeight(block.Header.Height, block.ID())))
+		require.NoError(t, storages.Collections.Store(&flow.Collection{Transactions: collection.Transactions}))
+
+		// execute the block and persist its execution like an execution node
+		blockComputer, err := computer.NewBlockComputer(vm, vmCtx, collector, tracer, logger, committer.NewLedgerViewCommitter(ldg, tracer))
+		require.NoError(t, err)
+
+		view := delta.NewView(state.LedgerGetRegister(ldg, initialCommit))
+		result, err := blockComputer.ExecuteBlock(context.Background(), executableBlock, view, programs.NewEmptyPrograms())
+		require.NoError(t, err)
+		require.Empty(t, result.TransactionResults[0].ErrorMessage)
+		require.Empty(t, result.TransactionResults[1].ErrorMessage)
+
+		endState, chunkDataPacks, executionResult, err := execution.GenerateExecutionResultAndChunkDataPacks(unittest.IdentifierFixture(), initialCommit, result)
+		require.NoError(t, err)
+
+		results := storagebadger.NewExecutionResults(collector, db)
+		receipts := storagebadger.NewExecutionReceipts(collector, db, results, storagebadger.DefaultCacheSize)
+		execState := state.NewExecutionState(
+			ldg,
+			storages.Commits,
+			storages.Blocks,
+			storages.Headers,
+			storages.Collections,
+			storages.ChunkDataPacks,
+			results,
+			receipts,
+			storagebadger.NewMyExecutionReceipts(collector, db, receipts),
+			storages.Events,
+			storagebadger.NewServiceEvents(collector, db),
+			storages.TransactionResults,
+			storagebadger.NewRegisters(db),
+			db,
+			tracer,
+		)
+		err = execState.PersistExecutionState(context.Background(),
+			block.Header,
+			endState,
+			chunkDataPacks,
+			&flow.ExecutionReceipt{ExecutionResult: *executionResult, ExecutorID: unittest.IdentifierFixture()},
+			result.Events,
+			result.ServiceEvents,
+			result.TransactionResults,
+			result.TrieUpdates)
+		require.NoError(t, err)
+
+		// re-execute the block
+		reexecutor, err := NewReexecutor(logger, ldg, vm, vmCtx, db, storages)
+		require.NoError(t, err)
+
+		report, err := reexecutor.ReexecuteBlock(context.Background(), block.Header.Height)
+		require.NoError(t, err)
+
+		storedEvents, err := storages.Events.ByBlockID(block.ID())
+		require.NoError(t, err)
+		require.NotEmpty(t, storedEvents)
+
+		assert.True(t, report.Matches())
+		assert.Equal(t, endState, report.ExpectedFinalState)
+		assert.Equal(t, endState, report.ActualFinalState)
+		assert.Empty(t, report.Chunks)
+		assert.Empty(t, report.Transactions)
+		assert.Empty(t, report.Events)
+	})
+}
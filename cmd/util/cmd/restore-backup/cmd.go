@@ -0,0 +1,101 @@
+package restore_backup
+
+import (
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/module/backup"
+)
+
+var (
+	flagBackupDir      string
+	flagDatadir        string
+	flagTriedir        string
+	flagValidateOnly   bool
+	flagVerifyLedger   bool
+	flagMTrieCacheSize int
+)
+
+// Cmd restores the state of a node from a backup created by the create-backup or
+// create-execution-backup admin commands.
+var Cmd = &cobra.Command{
+	Use:   "restore-backup",
+	Short: "Validates a node backup and restores the protocol state, and the execution state if included, from it",
+	Run:   run,
+}
+
+func init() {
+
+	Cmd.Flags().StringVar(&flagBackupDir, "backup-dir", "",
+		"directory of the backup to restore")
+	_ = Cmd.MarkFlagRequired("backup-dir")
+
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory to restore the protocol state into, which must not exist or be empty")
+
+	Cmd.Flags().StringVar(&flagTriedir, "triedir", "",
+		"directory to restore the execution state into, which must not exist or be empty, required for backups including the execution state")
+
+	Cmd.Flags().BoolVar(&flagValidateOnly, "validate-only", false,
+		"only validate the backup, without restoring it")
+
+	Cmd.Flags().BoolVar(&flagVerifyLedger, "verify-ledger", false,
+		"replay the restored ledger to verify it contains the state commitment of the last executed block")
+
+	Cmd.Flags().IntVar(&flagMTrieCacheSize, "mtrie-cache-size", 500,
+		"cache size for MTrie when verifying the restored ledger")
+}
+
+func run(*cobra.Command, []string) {
+
+	if flagValidateOnly {
+		manifest, err := backup.Validate(flagBackupDir)
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid backup")
+		}
+		logManifest(manifest).Msg("backup is valid")
+		return
+	}
+
+	if flagDatadir == "" {
+		log.Fatal().Msg("--datadir is required to restore a backup")
+	}
+
+	log.Info().Str("backup_dir", flagBackupDir).Msg("restoring backup")
+
+	manifest, err := backup.Restore(flagBackupDir, flagDatadir, flagTriedir)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not restore backup")
+	}
+
+	if flagVerifyLedger && manifest.Execution != nil {
+		log.Info().Msg("replaying restored ledger")
+
+		err = backup.VerifyLedger(log.Logger, flagTriedir, manifest, flagMTrieCacheSize)
+		if err != nil {
+			log.Fatal().Err(err).Msg("restored ledger is invalid")
+		}
+	}
+
+	logManifest(manifest).Msg("backup restored")
+}
+
+func logManifest(manifest *backup.Manifest) *zerolog.Event {
+	event := log.Info().
+		Time("created_at", manifest.CreatedAt).
+		Str("chain_id", manifest.ChainID.String()).
+		Uint64("finalized_height", manifest.FinalizedHeight).
+		Hex("finalized_block_id", manifest.FinalizedBlockID[:]).
+		Uint64("sealed_height", manifest.SealedHeight).
+		Hex("sealed_block_id", manifest.SealedBlockID[:])
+
+	if manifest.Execution != nil {
+		event = event.
+			Uint64("last_executed_height", manifest.Execution.LastExecutedHeight).
+			Hex("last_executed_block_id", manifest.Execution.LastExecutedBlockID[:]).
+			Str("state_commitment", manifest.Execution.StateCommitment)
+	}
+
+	return event
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	admin_command "github.com/onflow/flow-go/cmd/util/cmd/admin-command"
 	checkpoint_list_tries "github.com/onflow/flow-go/cmd/util/cmd/checkpoint-list-tries"
 	epochs "github.com/onflow/flow-go/cmd/util/cmd/epochs/cmd"
 	export "github.com/onflow/flow-go/cmd/util/cmd/exec-data-json-export"
@@ -16,7 +17,10 @@ import (
 	ledger_json_exporter "github.com/onflow/flow-go/cmd/util/cmd/export-json-execution-state"
 	read_badger "github.com/onflow/flow-go/cmd/util/cmd/read-badger/cmd"
 	read_protocol_state "github.com/onflow/flow-go/cmd/util/cmd/read-protocol-state/cmd"
+	reexecute "github.com/onflow/flow-go/cmd/util/cmd/reexecute-blocks"
+	restore_backup "github.com/onflow/flow-go/cmd/util/cmd/restore-backup"
 	truncate_database "github.com/onflow/flow-go/cmd/util/cmd/truncate-database"
+	verify_chunk "github.com/onflow/flow-go/cmd/util/cmd/verify-chunk"
 )
 
 var (
@@ -58,6 +62,10 @@ func addCommands() {
 	rootCmd.AddCommand(read_protocol_state.RootCmd)
 	rootCmd.AddCommand(ledger_json_exporter.Cmd)
 	rootCmd.AddCommand(epochs.RootCmd)
+	rootCmd.AddCommand(reexecute.Cmd)
+	rootCmd.AddCommand(verify_chunk.Cmd)
+	rootCmd.AddCommand(admin_command.Cmd)
+	rootCmd.AddCommand(restore_backup.Cmd)
 }
 
 func initConfig() {
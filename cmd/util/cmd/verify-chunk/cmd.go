@@ -0,0 +1,285 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/engine/execution/rpc"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/verification"
+	"github.com/onflow/flow-go/module/chunks"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/grpcutils"
+)
+
+var (
+	flagDatadir       string
+	flagExecutionAddr string
+	flagTimeout       time.Duration
+	flagBlockID       string
+	flagResultID      string
+	flagChunkIndex    uint64
+	flagExportFile    string
+	flagInputFile     string
+	flagReportFile    string
+)
+
+// run with `./util verify-chunk --datadir /var/flow/data/protocol --block-id <id> --chunk-index 0`
+// on the data of an execution node, or with `--execution-address <host>:9000` instead of
+// `--datadir` to fetch the chunk from the gRPC API of a running execution node. Optionally,
+// `--export-file chunk.json` takes the chunk elsewhere, where it is verified with
+// `./util verify-chunk --input-file chunk.json`. Chunks fetched from an execution node or read
+// from a file must belong to the execution result given by `--result-id`, or otherwise to the
+// sealed result of the block in case `--datadir` is given as well.
+var Cmd = &cobra.Command{
+	Use:   "verify-chunk",
+	Short: "Verifies a chunk of an execution result the same way verification nodes do, and reports the verdict",
+	Run:   run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state of an execution node, which holds the chunk data packs")
+
+	Cmd.Flags().StringVar(&flagExecutionAddr, "execution-address", "",
+		"address of the gRPC API of an execution node to fetch the chunk and its chunk data pack from, instead of reading them from the datadir")
+
+	Cmd.Flags().DurationVar(&flagTimeout, "timeout", 30*time.Second,
+		"maximum duration to wait for the execution node to return the chunk")
+
+	Cmd.Flags().StringVar(&flagBlockID, "block-id", "",
+		"ID of the block the chunk belongs to")
+
+	Cmd.Flags().StringVar(&flagResultID, "result-id", "",
+		"ID of the execution result of the chunk (defaults to the execution result stored for the block)")
+
+	Cmd.Flags().Uint64Var(&flagChunkIndex, "chunk-index", 0,
+		"index of the chunk in the execution result (defaults to the index of the chunk in the input file)")
+
+	Cmd.Flags().StringVar(&flagExportFile, "export-file", "",
+		"file to write the verifiable chunk read from the datadir to, as JSON")
+
+	Cmd.Flags().StringVar(&flagInputFile, "input-file", "",
+		"file to read the verifiable chunk from, as exported with --export-file, instead of reading it from the datadir")
+
+	Cmd.Flags().StringVar(&flagReportFile, "report-file", "",
+		"file to write the report of the verification to, as JSON")
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	var headers storage.Headers
+	var state protocol.State
+	var storages *storage.All
+	var vc *verification.VerifiableChunkData
+
+	if flagDatadir != "" {
+		db := common.InitStorage(flagDatadir)
+		defer db.Close()
+		storages = common.InitStorages(db)
+		headers = storages.Headers
+
+		if flagInputFile == "" && flagExecutionAddr == "" {
+			vc = loadFromStorage(storages)
+		} else {
+			var err error
+			state, err = common.InitProtocolState(db, storages)
+			if err != nil {
+				log.Fatal().Err(err).Msg("could not init protocol state")
+			}
+		}
+	}
+
+	if flagExecutionAddr != "" && flagInputFile == "" {
+		vc = loadFromExecutionNode(flagExecutionAddr, state, storages)
+	}
+
+	if flagInputFile != "" {
+		vc = loadFromFile(flagInputFile, cmd.Flags().Changed("chunk-index"), state, storages)
+	}
+
+	if vc == nil {
+		log.Fatal().Msg("either --datadir, --execution-address or --input-file must be provided")
+	}
+
+	if flagExportFile != "" {
+		data, err := json.Marshal(vc)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not encode verifiable chunk")
+		}
+		err = ioutil.WriteFile(flagExportFile, data, 0644)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not write verifiable chunk")
+		}
+		log.Info().Str("export_file", flagExportFile).Msg("verifiable chunk exported")
+	}
+
+	// same virtual machine as the verification node, which runs with the options of execution nodes.
+	// Without a datadir transactions can not look up blocks, and chunks that do are reported faulty.
+	vm := fvm.NewVirtualMachine(fvm.NewInterpreterRuntime())
+	vmCtx := fvm.NewContext(log.Logger, common.FvmOptions(vc.Header.ChainID, headers)...)
+	chunkVerifier := chunks.NewChunkVerifier(vm, vmCtx, log.Logger)
+
+	report, err := VerifyChunk(chunkVerifier, vc)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not verify chunk")
+	}
+
+	logReport(report)
+
+	if flagReportFile != "" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not encode report")
+		}
+		err = ioutil.WriteFile(flagReportFile, data, 0644)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not write report")
+		}
+	}
+}
+
+func loadFromStorage(storages *storage.All) *verification.VerifiableChunkData {
+	blockID, resultID := parseIDs()
+
+	vc, err := LoadVerifiableChunk(storages, blockID, resultID, flagChunkIndex)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not load verifiable chunk")
+	}
+	return vc
+}
+
+func loadFromExecutionNode(addr string, state protocol.State, storages *storage.All) *verification.VerifiableChunkData {
+	blockID, resultID := parseIDs()
+	resultID = expectedResultID(state, storages, blockID, resultID)
+
+	conn, err := grpc.Dial(
+		addr,
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(grpcutils.DefaultMaxMsgSize)),
+		grpc.WithInsecure())
+	if err != nil {
+		log.Fatal().Err(err).Str("execution_address", addr).Msg("could not connect to execution node")
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), flagTimeout)
+	defer cancel()
+
+	vc, err := FetchVerifiableChunk(ctx, rpc.NewExecutionChunkAPIClient(conn), blockID, resultID, flagChunkIndex)
+	if err != nil {
+		log.Fatal().Err(err).Str("execution_address", addr).Msg("could not fetch verifiable chunk")
+	}
+	return vc
+}
+
+// parseIDs parses the block ID and the optional result ID given by the flags.
+func parseIDs() (flow.Identifier, flow.Identifier) {
+	blockID, err := flow.HexStringToIdentifier(flagBlockID)
+	if err != nil {
+		log.Fatal().Err(err).Msg("malformed block ID")
+	}
+
+	resultID := flow.ZeroID
+	if flagResultID != "" {
+		resultID, err = flow.HexStringToIdentifier(flagResultID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("malformed result ID")
+		}
+	}
+
+	return blockID, resultID
+}
+
+func loadFromFile(path string, chunkIndexGiven bool, state protocol.State, storages *storage.All) *verification.VerifiableChunkData {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not read verifiable chunk")
+	}
+
+	var vc verification.VerifiableChunkData
+	err = json.Unmarshal(data, &vc)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not decode verifiable chunk")
+	}
+	if vc.Header == nil || vc.Chunk == nil {
+		log.Fatal().Msg("incomplete verifiable chunk")
+	}
+
+	// the block and chunk default to the ones of the file
+	blockID := vc.Header.ID()
+	resultID := flow.ZeroID
+	if flagBlockID != "" {
+		blockID, resultID = parseIDs()
+	} else if flagResultID != "" {
+		_, resultID = parseIDs()
+	}
+	chunkIndex := vc.Chunk.Index
+	if chunkIndexGiven {
+		chunkIndex = flagChunkIndex
+	}
+	resultID = expectedResultID(state, storages, blockID, resultID)
+
+	err = CheckVerifiableChunk(&vc, blockID, resultID, chunkIndex)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid verifiable chunk")
+	}
+	return &vc
+}
+
+// expectedResultID returns the ID of the execution result that a chunk, which was
+// not read from the datadir, must belong to. This is the given result ID, or the
+// sealed result of the block if the protocol state of a datadir is available.
+func expectedResultID(state protocol.State, storages *storage.All, blockID flow.Identifier, resultID flow.Identifier) flow.Identifier {
+	if resultID != flow.ZeroID || state == nil {
+		return resultID
+	}
+
+	sealedID, err := SealedResultID(state, storages.Index, storages.Seals, blockID)
+	if errors.Is(err, storage.ErrNotFound) {
+		log.Warn().Hex("block_id", blockID[:]).Msg("block is not sealed yet, the chunk is verified for any execution result")
+		return flow.ZeroID
+	}
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not get sealed execution result")
+	}
+	return sealedID
+}
+
+func logReport(report *ChunkReport) {
+	lg := log.With().
+		Hex("block_id", report.BlockID[:]).
+		Hex("result_id", report.ResultID[:]).
+		Uint64("chunk_index", report.ChunkIndex).
+		Bool("system_chunk", report.IsSystemChunk).
+		Hex("expected_end_state", report.ExpectedEndState[:]).
+		Logger()
+
+	event := lg.Info()
+	if !report.Approved {
+		event = lg.Warn()
+	}
+	if report.ComputedEndState != nil {
+		event = event.Hex("computed_end_state", report.ComputedEndState[:])
+	}
+	if len(report.SpockSecretHash) > 0 {
+		event = event.Hex("spock_secret_hash", report.SpockSecretHash)
+	}
+	if report.Fault != "" {
+		event = event.Str("fault", report.Fault)
+	}
+
+	if report.Approved {
+		event.Msg("chunk is valid, verification nodes approve it")
+		return
+	}
+	event.Msg("chunk is faulty, verification nodes do not approve it")
+}
@@ -0,0 +1,263 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/engine/execution/rpc"
+	chmodels "github.com/onflow/flow-go/model/chunks"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/verification"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+)
+
+// ChunkReport is the outcome of verifying a chunk.
+type ChunkReport struct {
+	BlockID       flow.Identifier
+	ResultID      flow.Identifier
+	ChunkIndex    uint64
+	IsSystemChunk bool
+	// Approved is whether a verification node would approve the chunk. It does
+	// so if the chunk has no fault, or if the only fault is a missing register
+	// touch.
+	Approved         bool
+	ExpectedEndState flow.StateCommitment
+	// ComputedEndState is the end state computed by executing the chunk. It is
+	// nil if the verification failed before the end state was computed.
+	ComputedEndState *flow.StateCommitment
+	// SpockSecretHash is the SHA3-256 hash of the SPoCK secret of the chunk. It
+	// is empty if the chunk has a fault.
+	SpockSecretHash []byte
+	// Fault describes the fault found with the chunk, or is empty if there is
+	// none.
+	Fault string
+}
+
+// LoadVerifiableChunk reads the chunk with the given index of an execution
+// result for the block from storage, the same way execution nodes serve it over
+// their chunk API. If resultID is the zero ID, the execution result stored for
+// the block is used, which is the one of the execution node the storage belongs to.
+func LoadVerifiableChunk(storages *storage.All, blockID flow.Identifier, resultID flow.Identifier, chunkIndex uint64) (*verification.VerifiableChunkData, error) {
+	return rpc.LoadVerifiableChunk(storages.Headers, storages.Results, storages.ChunkDataPacks, blockID, resultID, chunkIndex)
+}
+
+// FetchVerifiableChunk requests the chunk with the given index of an execution
+// result for the block from the chunk API of an execution node. If resultID is
+// the zero ID, the execution result of the execution node is used. The returned
+// chunk is checked with CheckVerifiableChunk.
+func FetchVerifiableChunk(ctx context.Context, client rpc.ExecutionChunkAPIClient, blockID flow.Identifier, resultID flow.Identifier, chunkIndex uint64) (*verification.VerifiableChunkData, error) {
+	req := &rpc.GetVerifiableChunkRequest{
+		BlockId:    blockID[:],
+		ChunkIndex: chunkIndex,
+	}
+	if resultID != flow.ZeroID {
+		req.ResultId = resultID[:]
+	}
+
+	resp, err := client.GetVerifiableChunk(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("could not get verifiable chunk: %w", err)
+	}
+
+	var vc verification.VerifiableChunkData
+	err = json.Unmarshal(resp.GetVerifiableChunk(), &vc)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode verifiable chunk: %w", err)
+	}
+
+	err = CheckVerifiableChunk(&vc, blockID, resultID, chunkIndex)
+	if err != nil {
+		return nil, fmt.Errorf("execution node returned an invalid verifiable chunk: %w", err)
+	}
+
+	return &vc, nil
+}
+
+// CheckVerifiableChunk checks that a verifiable chunk, which was obtained from an
+// untrusted source, is the chunk with the given index of an execution result for
+// the block. If resultID is not the zero ID, the execution result must have that
+// ID. The fields that follow from the execution result are computed locally the
+// same way the fetcher engine of a verification node does, rather than trusted.
+func CheckVerifiableChunk(vc *verification.VerifiableChunkData, blockID flow.Identifier, resultID flow.Identifier, chunkIndex uint64) error {
+	if vc.Header == nil || vc.Chunk == nil || vc.Result == nil || vc.ChunkDataPack == nil {
+		return fmt.Errorf("incomplete verifiable chunk")
+	}
+	if vc.Header.ID() != blockID {
+		return fmt.Errorf("verifiable chunk is for block %x instead of block %x", vc.Header.ID(), blockID)
+	}
+	if vc.Result.BlockID != blockID {
+		return fmt.Errorf("execution result (id: %x) is for a different block (id: %x)", vc.Result.ID(), vc.Result.BlockID)
+	}
+	if resultID != flow.ZeroID && vc.Result.ID() != resultID {
+		return fmt.Errorf("verifiable chunk is for execution result %x instead of execution result %x", vc.Result.ID(), resultID)
+	}
+
+	chunk, ok := vc.Result.Chunks.ByIndex(chunkIndex)
+	if !ok {
+		return fmt.Errorf("chunk index %d out of range, execution result has %d chunks", chunkIndex, len(vc.Result.Chunks))
+	}
+	if vc.Chunk.ID() != chunk.ID() {
+		return fmt.Errorf("chunk (id: %x) is not chunk %d of the execution result (id: %x)", vc.Chunk.ID(), chunkIndex, chunk.ID())
+	}
+	if vc.ChunkDataPack.ChunkID != chunk.ID() {
+		return fmt.Errorf("chunk data pack is for chunk %x instead of chunk %x", vc.ChunkDataPack.ChunkID, chunk.ID())
+	}
+
+	isSystemChunk := verification.IsSystemChunk(chunkIndex, vc.Result)
+
+	endState, err := verification.EndStateCommitment(vc.Result, chunkIndex, isSystemChunk)
+	if err != nil {
+		return fmt.Errorf("could not compute end state of chunk: %w", err)
+	}
+
+	transactionOffset, err := verification.TransactionOffsetForChunk(vc.Result.Chunks, chunkIndex)
+	if err != nil {
+		return fmt.Errorf("could not compute transaction offset for chunk: %w", err)
+	}
+
+	vc.Chunk = chunk
+	vc.IsSystemChunk = isSystemChunk
+	vc.EndState = endState
+	vc.TransactionOffset = transactionOffset
+
+	return nil
+}
+
+// SealedResultID returns the ID of the execution result sealed for the given
+// finalized block. It returns storage.ErrNotFound if the block is not sealed as
+// of the latest finalized block.
+func SealedResultID(state protocol.State, index storage.Index, seals storage.Seals, blockID flow.Identifier) (flow.Identifier, error) {
+	header, err := state.AtBlockID(blockID).Head()
+	if err != nil {
+		return flow.ZeroID, fmt.Errorf("could not get block header: %w", err)
+	}
+	finalized, err := state.AtHeight(header.Height).Head()
+	if err != nil {
+		return flow.ZeroID, fmt.Errorf("could not get finalized block at height %d: %w", header.Height, err)
+	}
+	if finalized.ID() != blockID {
+		return flow.ZeroID, fmt.Errorf("block %x is not finalized", blockID)
+	}
+	final, err := state.Final().Head()
+	if err != nil {
+		return flow.ZeroID, fmt.Errorf("could not get finalized block: %w", err)
+	}
+
+	// sealedAt returns the latest seal as of the finalized block at the height,
+	// and whether the block is sealed as of it
+	sealedAt := func(height uint64) (*flow.Seal, bool, error) {
+		_, seal, err := state.AtHeight(height).SealedResult()
+		if err != nil {
+			return nil, false, fmt.Errorf("could not get sealed result at height %d: %w", height, err)
+		}
+		sealed, err := state.AtBlockID(seal.BlockID).Head()
+		if err != nil {
+			return nil, false, fmt.Errorf("could not get sealed block: %w", err)
+		}
+		return seal, sealed.Height >= header.Height, nil
+	}
+
+	_, sealed, err := sealedAt(final.Height)
+	if err != nil {
+		return flow.ZeroID, err
+	}
+	if !sealed {
+		return flow.ZeroID, storage.ErrNotFound
+	}
+
+	// the seal for the block is included in the lowest finalized block, as of
+	// which the block is sealed
+	low, high := header.Height, final.Height
+	for low < high {
+		mid := low + (high-low)/2
+		_, sealed, err := sealedAt(mid)
+		if err != nil {
+			return flow.ZeroID, err
+		}
+		if sealed {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+
+	seal, _, err := sealedAt(low)
+	if err != nil {
+		return flow.ZeroID, err
+	}
+	if seal.BlockID == blockID {
+		return seal.ResultID, nil
+	}
+
+	// the block sealing our block also sealed blocks above it
+	including, err := state.AtHeight(low).Head()
+	if err != nil {
+		return flow.ZeroID, fmt.Errorf("could not get finalized block at height %d: %w", low, err)
+	}
+	payload, err := index.ByBlockID(including.ID())
+	if err != nil {
+		return flow.ZeroID, fmt.Errorf("could not get payload index of block %x: %w", including.ID(), err)
+	}
+	for _, sealID := range payload.SealIDs {
+		seal, err := seals.ByID(sealID)
+		if err != nil {
+			return flow.ZeroID, fmt.Errorf("could not get seal %x: %w", sealID, err)
+		}
+		if seal.BlockID == blockID {
+			return seal.ResultID, nil
+		}
+	}
+	return flow.ZeroID, fmt.Errorf("seal for block %x is missing in the payload of block %x", blockID, including.ID())
+}
+
+// VerifyChunk verifies the chunk with the given chunk verifier, and reports the
+// verdict. It returns an error if the chunk could not be verified at all, which
+// does not tell anything about the validity of the chunk.
+func VerifyChunk(chVerif module.ChunkVerifier, vc *verification.VerifiableChunkData) (*ChunkReport, error) {
+	var spockSecret []byte
+	var chFault chmodels.ChunkFault
+	var err error
+	if vc.IsSystemChunk {
+		spockSecret, chFault, err = chVerif.SystemChunkVerify(vc)
+	} else {
+		spockSecret, chFault, err = chVerif.Verify(vc)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not verify chunk: %w", err)
+	}
+
+	report := &ChunkReport{
+		BlockID:          vc.Header.ID(),
+		ResultID:         vc.Result.ID(),
+		ChunkIndex:       vc.Chunk.Index,
+		IsSystemChunk:    vc.IsSystemChunk,
+		ExpectedEndState: vc.EndState,
+	}
+
+	switch fault := chFault.(type) {
+	case nil:
+		report.Approved = true
+		endState := vc.EndState
+		report.ComputedEndState = &endState
+	case *chmodels.CFMissingRegisterTouch:
+		// the verifier engine still approves chunks with missing register touches
+		report.Approved = true
+		report.Fault = fault.String()
+	case *chmodels.CFNonMatchingFinalState:
+		computed := fault.Computed()
+		report.ComputedEndState = &computed
+		report.Fault = fault.String()
+	default:
+		report.Fault = fault.String()
+	}
+
+	if len(spockSecret) > 0 {
+		report.SpockSecretHash = hash.NewSHA3_256().ComputeHash(spockSecret)
+	}
+
+	return report, nil
+}
@@ -0,0 +1,310 @@
+package verify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/onflow/flow-go/crypto/hash"
+	"github.com/onflow/flow-go/engine/execution/rpc"
+	chmodels "github.com/onflow/flow-go/model/chunks"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/verification"
+	mockprotocol "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	mockstorage "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestLoadVerifiableChunk(t *testing.T) {
+
+	// an execution result with two chunks, the last one being the system chunk
+	header := unittest.BlockHeaderFixture()
+	blockID := header.ID()
+	result := unittest.ExecutionResultFixture()
+	result.BlockID = blockID
+	result.Chunks = flow.ChunkList{
+		{ChunkBody: flow.ChunkBody{StartState: unittest.StateCommitmentFixture(), NumberOfTransactions: 3}, Index: 0, EndState: unittest.StateCommitmentFixture()},
+		{ChunkBody: flow.ChunkBody{StartState: unittest.StateCommitmentFixture(), NumberOfTransactions: 1}, Index: 1, EndState: unittest.StateCommitmentFixture()},
+	}
+	chunkDataPacks := map[flow.Identifier]*flow.ChunkDataPack{
+		result.Chunks[0].ID(): unittest.ChunkDataPackFixture(result.Chunks[0].ID()),
+		result.Chunks[1].ID(): unittest.ChunkDataPackFixture(result.Chunks[1].ID()),
+	}
+
+	headers := &mockstorage.Headers{}
+	headers.On("ByBlockID", blockID).Return(&header, nil)
+	results := &mockstorage.ExecutionResults{}
+	results.On("ByBlockID", blockID).Return(result, nil)
+	results.On("ByID", result.ID()).Return(result, nil)
+	packs := &mockstorage.ChunkDataPacks{}
+	for chunkID, pack := range chunkDataPacks {
+		packs.On("ByChunkID", chunkID).Return(pack, nil)
+	}
+	storages := &storage.All{
+		Headers:        headers,
+		Results:        results,
+		ChunkDataPacks: packs,
+	}
+
+	t.Run("chunk of stored result", func(t *testing.T) {
+		vc, err := LoadVerifiableChunk(storages, blockID, flow.ZeroID, 0)
+		require.NoError(t, err)
+
+		assert.False(t, vc.IsSystemChunk)
+		assert.Equal(t, result.Chunks[0], vc.Chunk)
+		assert.Equal(t, &header, vc.Header)
+		assert.Equal(t, result, vc.Result)
+		assert.Equal(t, chunkDataPacks[result.Chunks[0].ID()], vc.ChunkDataPack)
+		// the end state of a chunk is the start state of the next one
+		assert.Equal(t, result.Chunks[1].StartState, vc.EndState)
+		assert.Equal(t, uint32(0), vc.TransactionOffset)
+	})
+
+	t.Run("system chunk of given result", func(t *testing.T) {
+		vc, err := LoadVerifiableChunk(storages, blockID, result.ID(), 1)
+		require.NoError(t, err)
+
+		assert.True(t, vc.IsSystemChunk)
+		assert.Equal(t, result.Chunks[1].EndState, vc.EndState)
+		assert.Equal(t, uint32(3), vc.TransactionOffset)
+	})
+
+	t.Run("chunk index out of range", func(t *testing.T) {
+		_, err := LoadVerifiableChunk(storages, blockID, flow.ZeroID, 2)
+		require.Error(t, err)
+	})
+
+	t.Run("result of other block", func(t *testing.T) {
+		other := unittest.ExecutionResultFixture()
+		results.On("ByID", other.ID()).Return(other, nil)
+
+		_, err := LoadVerifiableChunk(storages, blockID, other.ID(), 0)
+		require.Error(t, err)
+	})
+}
+
+func TestFetchVerifiableChunk(t *testing.T) {
+	header := unittest.BlockHeaderFixture()
+	blockID := header.ID()
+	result := unittest.ExecutionResultFixture()
+	result.BlockID = blockID
+	chunk := result.Chunks[0]
+
+	// the execution node claims a wrong end state and transaction offset, and
+	// that the chunk is a system chunk
+	vc := &verification.VerifiableChunkData{
+		IsSystemChunk:     true,
+		Chunk:             chunk,
+		Header:            &header,
+		Result:            result,
+		ChunkDataPack:     unittest.ChunkDataPackFixture(chunk.ID()),
+		EndState:          unittest.StateCommitmentFixture(),
+		TransactionOffset: 42,
+	}
+	respond := func(vc *verification.VerifiableChunkData) *chunkClient {
+		data, err := json.Marshal(vc)
+		require.NoError(t, err)
+		return &chunkClient{response: &rpc.GetVerifiableChunkResponse{VerifiableChunk: data}}
+	}
+	client := respond(vc)
+
+	t.Run("chunk of the execution node's result", func(t *testing.T) {
+		fetched, err := FetchVerifiableChunk(context.Background(), client, blockID, flow.ZeroID, chunk.Index)
+		require.NoError(t, err)
+
+		// without a result ID, the execution node picks its own result
+		assert.Equal(t, blockID[:], client.request.GetBlockId())
+		assert.Empty(t, client.request.GetResultId())
+		assert.Equal(t, chunk.Index, client.request.GetChunkIndex())
+		assert.Equal(t, result.ID(), fetched.Result.ID())
+		assert.Equal(t, vc.ChunkDataPack.ID(), fetched.ChunkDataPack.ID())
+
+		// the fields that follow from the execution result are computed locally
+		assert.False(t, fetched.IsSystemChunk)
+		assert.Equal(t, result.Chunks[1].StartState, fetched.EndState)
+		assert.Equal(t, uint32(0), fetched.TransactionOffset)
+	})
+
+	t.Run("chunk of the requested result", func(t *testing.T) {
+		_, err := FetchVerifiableChunk(context.Background(), client, blockID, result.ID(), chunk.Index)
+		require.NoError(t, err)
+		assert.Equal(t, result.ID(), flow.HashToID(client.request.GetResultId()))
+	})
+
+	t.Run("chunk of another result", func(t *testing.T) {
+		_, err := FetchVerifiableChunk(context.Background(), client, blockID, unittest.IdentifierFixture(), chunk.Index)
+		require.Error(t, err)
+	})
+
+	t.Run("chunk of another block", func(t *testing.T) {
+		_, err := FetchVerifiableChunk(context.Background(), client, unittest.IdentifierFixture(), result.ID(), chunk.Index)
+		require.Error(t, err)
+	})
+
+	t.Run("chunk with another index", func(t *testing.T) {
+		_, err := FetchVerifiableChunk(context.Background(), client, blockID, result.ID(), 1)
+		require.Error(t, err)
+	})
+
+	t.Run("chunk data pack of another chunk", func(t *testing.T) {
+		other := *vc
+		other.ChunkDataPack = unittest.ChunkDataPackFixture(result.Chunks[1].ID())
+		_, err := FetchVerifiableChunk(context.Background(), respond(&other), blockID, result.ID(), chunk.Index)
+		require.Error(t, err)
+	})
+}
+
+func TestSealedResultID(t *testing.T) {
+
+	// a chain of finalized blocks at heights 0 to 5, where the block at height 3
+	// seals the blocks at heights 1 and 2, and the block at height 5 seals the
+	// block at height 3
+	blocks := make([]*flow.Header, 0, 6)
+	for height := uint64(0); height <= 5; height++ {
+		header := unittest.BlockHeaderFixture()
+		header.Height = height
+		blocks = append(blocks, &header)
+	}
+	seals := make(map[uint64]*flow.Seal)
+	for _, height := range []uint64{0, 1, 2, 3} {
+		seals[height] = unittest.Seal.Fixture(unittest.Seal.WithBlockID(blocks[height].ID()))
+	}
+	latestSeal := map[uint64]*flow.Seal{0: seals[0], 1: seals[0], 2: seals[0], 3: seals[2], 4: seals[2], 5: seals[3]}
+
+	state := &mockprotocol.State{}
+	for _, block := range blocks {
+		snapshot := &mockprotocol.Snapshot{}
+		snapshot.On("Head").Return(block, nil)
+		snapshot.On("SealedResult").Return(nil, latestSeal[block.Height], nil)
+		state.On("AtHeight", block.Height).Return(snapshot)
+		state.On("AtBlockID", block.ID()).Return(snapshot)
+	}
+	unfinalized := unittest.BlockHeaderFixture()
+	unfinalized.Height = 4
+	unfinalizedSnapshot := &mockprotocol.Snapshot{}
+	unfinalizedSnapshot.On("Head").Return(&unfinalized, nil)
+	state.On("AtBlockID", unfinalized.ID()).Return(unfinalizedSnapshot)
+	final := &mockprotocol.Snapshot{}
+	final.On("Head").Return(blocks[5], nil)
+	state.On("Final").Return(final)
+
+	index := &mockstorage.Index{}
+	index.On("ByBlockID", blocks[3].ID()).Return(&flow.Index{SealIDs: []flow.Identifier{seals[1].ID(), seals[2].ID()}}, nil)
+	sealStore := &mockstorage.Seals{}
+	for _, seal := range seals {
+		sealStore.On("ByID", seal.ID()).Return(seal, nil)
+	}
+
+	t.Run("root block", func(t *testing.T) {
+		resultID, err := SealedResultID(state, index, sealStore, blocks[0].ID())
+		require.NoError(t, err)
+		assert.Equal(t, seals[0].ResultID, resultID)
+	})
+
+	t.Run("block sealed together with a higher block", func(t *testing.T) {
+		resultID, err := SealedResultID(state, index, sealStore, blocks[1].ID())
+		require.NoError(t, err)
+		assert.Equal(t, seals[1].ResultID, resultID)
+	})
+
+	t.Run("highest block sealed by a block", func(t *testing.T) {
+		resultID, err := SealedResultID(state, index, sealStore, blocks[2].ID())
+		require.NoError(t, err)
+		assert.Equal(t, seals[2].ResultID, resultID)
+
+		resultID, err = SealedResultID(state, index, sealStore, blocks[3].ID())
+		require.NoError(t, err)
+		assert.Equal(t, seals[3].ResultID, resultID)
+	})
+
+	t.Run("unsealed block", func(t *testing.T) {
+		_, err := SealedResultID(state, index, sealStore, blocks[4].ID())
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("unfinalized block", func(t *testing.T) {
+		_, err := SealedResultID(state, index, sealStore, unfinalized.ID())
+		require.Error(t, err)
+		require.NotErrorIs(t, err, storage.ErrNotFound)
+	})
+}
+
+func TestVerifyChunk(t *testing.T) {
+	vc := &verification.VerifiableChunkData{
+		Chunk:    &flow.Chunk{Index: 0},
+		Header:   &flow.Header{},
+		Result:   unittest.ExecutionResultFixture(),
+		EndState: unittest.StateCommitmentFixture(),
+	}
+
+	t.Run("valid chunk", func(t *testing.T) {
+		spockSecret := []byte("spock secret")
+		report, err := VerifyChunk(&chunkVerifier{spockSecret: spockSecret}, vc)
+		require.NoError(t, err)
+
+		assert.True(t, report.Approved)
+		assert.Equal(t, vc.EndState, *report.ComputedEndState)
+		assert.Equal(t, []byte(hash.NewSHA3_256().ComputeHash(spockSecret)), report.SpockSecretHash)
+		assert.Empty(t, report.Fault)
+	})
+
+	t.Run("non matching end state", func(t *testing.T) {
+		computed := unittest.StateCommitmentFixture()
+		fault := chmodels.NewCFNonMatchingFinalState(vc.EndState, computed, 0, vc.Result.ID())
+		report, err := VerifyChunk(&chunkVerifier{fault: fault}, vc)
+		require.NoError(t, err)
+
+		assert.False(t, report.Approved)
+		assert.Equal(t, computed, *report.ComputedEndState)
+		assert.Empty(t, report.SpockSecretHash)
+		assert.Equal(t, fault.String(), report.Fault)
+	})
+
+	t.Run("missing register touch", func(t *testing.T) {
+		fault := chmodels.NewCFMissingRegisterTouch(nil, 0, vc.Result.ID(), unittest.IdentifierFixture())
+		report, err := VerifyChunk(&chunkVerifier{fault: fault}, vc)
+		require.NoError(t, err)
+
+		// verification nodes still approve chunks with missing register touches
+		assert.True(t, report.Approved)
+		assert.Nil(t, report.ComputedEndState)
+		assert.Equal(t, fault.String(), report.Fault)
+	})
+
+	t.Run("verification failure", func(t *testing.T) {
+		_, err := VerifyChunk(&chunkVerifier{err: fmt.Errorf("failure")}, vc)
+		require.Error(t, err)
+	})
+}
+
+// chunkClient is a chunk API client with a predefined response, which records the last request.
+type chunkClient struct {
+	request  *rpc.GetVerifiableChunkRequest
+	response *rpc.GetVerifiableChunkResponse
+}
+
+func (c *chunkClient) GetVerifiableChunk(_ context.Context, in *rpc.GetVerifiableChunkRequest, _ ...grpc.CallOption) (*rpc.GetVerifiableChunkResponse, error) {
+	c.request = in
+	return c.response, nil
+}
+
+// chunkVerifier is a chunk verifier with a predefined outcome.
+type chunkVerifier struct {
+	spockSecret []byte
+	fault       chmodels.ChunkFault
+	err         error
+}
+
+func (v *chunkVerifier) Verify(*verification.VerifiableChunkData) ([]byte, chmodels.ChunkFault, error) {
+	return v.spockSecret, v.fault, v.err
+}
+
+func (v *chunkVerifier) SystemChunkVerify(*verification.VerifiableChunkData) ([]byte, chmodels.ChunkFault, error) {
+	return v.spockSecret, v.fault, v.err
+}
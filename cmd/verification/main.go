@@ -56,6 +56,9 @@ func main() {
 		blockWorkers uint64 // number of blocks processed in parallel.
 		chunkWorkers uint64 // number of chunks processed in parallel.
 
+		verificationWorkers       uint // number of chunks verified in parallel by verifier engine.
+		verificationQueueCapacity uint // maximum number of chunks waiting for a worker at verifier engine.
+
 		chunkStatuses        *stdmap.ChunkStatuses     // used in fetcher engine
 		chunkRequests        *stdmap.ChunkRequests     // used in requester engine
 		processedChunkIndex  *storage.ConsumerProgress // used in chunk consumer
@@ -89,6 +92,8 @@ func main() {
 			flags.Uint64Var(&requestTargets, "request-targets", vereq.DefaultRequestTargets, "maximum number of execution nodes a chunk data pack request is dispatched to")
 			flags.Uint64Var(&blockWorkers, "block-workers", blockconsumer.DefaultBlockWorkers, "maximum number of blocks being processed in parallel")
 			flags.Uint64Var(&chunkWorkers, "chunk-workers", chunkconsumer.DefaultChunkWorkers, "maximum number of execution nodes a chunk data pack request is dispatched to")
+			flags.UintVar(&verificationWorkers, "verification-workers", verifier.DefaultChunkWorkers, "maximum number of chunks verified in parallel")
+			flags.UintVar(&verificationQueueCapacity, "verification-queue-capacity", verifier.DefaultChunkQueueCapacity, "maximum number of chunks waiting to be verified")
 
 		}).
 		Initialize().
@@ -173,6 +178,7 @@ func main() {
 			vmCtx := fvm.NewContext(node.Logger, node.FvmOptions...)
 			chunkVerifier := chunks.NewChunkVerifier(vm, vmCtx, node.Logger)
 			approvalStorage := storage.NewResultApprovals(node.Metrics.Cache, node.DB)
+			challengeStorage := storage.NewChunkChallenges(node.DB)
 			verifierEng, err = verifier.New(
 				node.Logger,
 				collector,
@@ -181,7 +187,11 @@ func main() {
 				node.State,
 				node.Me,
 				chunkVerifier,
-				approvalStorage)
+				approvalStorage,
+				node.Storage.Receipts,
+				challengeStorage,
+				verificationWorkers,
+				verificationQueueCapacity)
 			return verifierEng, err
 		}).
 		Component("chunk consumer, requester, and fetcher engines", func(builder cmd.NodeBuilder, node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
@@ -296,7 +306,7 @@ func main() {
 			}
 
 			// initialize the verifier for the protocol consensus
-			verifier := verification.NewCombinedVerifier(committee, staking, beacon, merger)
+			verifier := verification.NewCombinedVerifier(committee, staking, beacon, merger, node.RootChainID)
 
 			finalized, pending, err := recovery.FindLatest(node.State, node.Storage.Headers)
 			if err != nil {
@@ -1,18 +1,28 @@
 package consensus
 
 import (
+	"math"
 	"time"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
 )
 
 type ParticipantConfig struct {
-	TimeoutInitial             time.Duration // the initial timeout for the pacemaker
-	TimeoutMinimum             time.Duration // the minimum timeout for the pacemaker
-	TimeoutAggregationFraction float64       // the percentage part of the timeout period reserved for vote aggregation
-	TimeoutIncreaseFactor      float64       // the factor at which the timeout grows when timeouts occur
-	TimeoutDecreaseFactor      float64       // the factor at which the timeout grows when timeouts occur
-	BlockRateDelay             time.Duration // a delay to broadcast block proposal in order to control the block production rate
+	TimeoutInitial             time.Duration           // the initial timeout for the pacemaker
+	TimeoutMinimum             time.Duration           // the minimum timeout for the pacemaker
+	TimeoutAggregationFraction float64                 // the percentage part of the timeout period reserved for vote aggregation
+	TimeoutIncreaseFactor      float64                 // the factor at which the timeout grows when timeouts occur
+	TimeoutDecreaseFactor      float64                 // the factor at which the timeout grows when timeouts occur
+	BlockRateDelay             time.Duration           // a delay to broadcast block proposal in order to control the block production rate
+	AdaptiveTimeout            *timeout.AdaptiveConfig // if set, the timeout is derived from the observed latency instead
+	TCActivationView           uint64                  // the first view in which replicas leave a view only on a QC or TC, rather than on a local timeout
 }
 
+// TCActivationDisabled is the activation view, for which timeout certificates are never activated.
+// The activation view must be the same for all replicas, hence timeout certificates are disabled
+// until a coordinated activation view is configured, once all replicas support them.
+const TCActivationDisabled = math.MaxUint64
+
 type Option func(*ParticipantConfig)
 
 func WithInitialTimeout(timeout time.Duration) Option {
@@ -50,3 +60,15 @@ func WithBlockRateDelay(delay time.Duration) Option {
 		cfg.BlockRateDelay = delay
 	}
 }
+
+func WithAdaptiveTimeout(config timeout.AdaptiveConfig) Option {
+	return func(cfg *ParticipantConfig) {
+		cfg.AdaptiveTimeout = &config
+	}
+}
+
+func WithTCActivationView(view uint64) Option {
+	return func(cfg *ParticipantConfig) {
+		cfg.TCActivationView = view
+	}
+}
@@ -69,3 +69,11 @@ func ComputeStakeThresholdForBuildingQC(totalStake uint64) uint64 {
 	}
 	return res
 }
+
+// ComputeStakeThresholdForJoiningView returns the stake that is minimally required for a replica to
+// join a higher view based on timeouts, i.e. the stake which guarantees that at least one honest
+// replica has reached the view, assuming that byzantine replicas hold less than a third of the stake
+func ComputeStakeThresholdForJoiningView(totalStake uint64) uint64 {
+	// Given totalStake, we need smallest integer t such that totalStake / 3 < t
+	return totalStake/3 + 1
+}
@@ -19,3 +19,14 @@ func Test_ComputeStakeThreshold(t *testing.T) {
 		assert.False(t, boundaryValue < float64(threshold-1))
 	}
 }
+
+func Test_ComputeStakeThresholdForJoiningView(t *testing.T) {
+	// testing lowest values
+	for i := 1; i <= 302; i++ {
+		threshold := hotstuff.ComputeStakeThresholdForJoiningView(uint64(i))
+
+		boundaryValue := float64(i) / 3.0
+		assert.True(t, boundaryValue < float64(threshold))
+		assert.False(t, boundaryValue < float64(threshold-1))
+	}
+}
@@ -69,6 +69,12 @@ type Consumer interface {
 	// and must handle repetition of the same events (with some processing overhead).
 	OnReceiveProposal(currentView uint64, proposal *model.Proposal)
 
+	// OnReceiveTimeout notifications are produced by the EventHandler when it starts processing a timeout.
+	// Prerequisites:
+	// Implementation must be concurrency safe; Non-blocking;
+	// and must handle repetition of the same events (with some processing overhead).
+	OnReceiveTimeout(currentView uint64, timeout *model.TimeoutObject)
+
 	// OnEnteringView notifications are produced by the EventHandler when it enters a new view.
 	// Prerequisites:
 	// Implementation must be concurrency safe; Non-blocking;
@@ -83,6 +89,22 @@ type Consumer interface {
 	// and must handle repetition of the same events (with some processing overhead).
 	OnQcTriggeredViewChange(qc *flow.QuorumCertificate, newView uint64)
 
+	// OnTcTriggeredViewChange notifications are produced by PaceMaker when it moves to a new view
+	// based on processing a TC. The arguments specify the tc (first argument), which triggered
+	// the view change, and the newView to which the PaceMaker transitioned (second argument).
+	// Prerequisites:
+	// Implementation must be concurrency safe; Non-blocking;
+	// and must handle repetition of the same events (with some processing overhead).
+	OnTcTriggeredViewChange(tc *flow.TimeoutCertificate, newView uint64)
+
+	// OnTimeoutsTriggeredViewChange notifications are produced by PaceMaker when it joins a higher
+	// view, because replicas with more than a third of the stake have timed out in that view or
+	// higher views. The argument specifies the newView to which the PaceMaker transitioned.
+	// Prerequisites:
+	// Implementation must be concurrency safe; Non-blocking;
+	// and must handle repetition of the same events (with some processing overhead).
+	OnTimeoutsTriggeredViewChange(newView uint64)
+
 	// OnProposingBlock notifications are produced by the EventHandler when the replica, as
 	// leader for the respective view, proposing a block.
 	// Prerequisites:
@@ -103,6 +125,13 @@ type Consumer interface {
 	// and must handle repetition of the same events (with some processing overhead).
 	OnQcConstructedFromVotes(*flow.QuorumCertificate)
 
+	// OnTcConstructedFromTimeouts notifications are produced by the VoteAggregator
+	// component, whenever it constructs a TC from timeouts.
+	// Prerequisites:
+	// Implementation must be concurrency safe; Non-blocking;
+	// and must handle repetition of the same events (with some processing overhead).
+	OnTcConstructedFromTimeouts(*flow.TimeoutCertificate)
+
 	// OnStartingTimeout notifications are produced by PaceMaker. Such a notification indicates that the
 	// PaceMaker is now waiting for the system to (receive and) process blocks or votes.
 	// The specific timeout type is contained in the TimerInfo.
@@ -146,4 +175,11 @@ type Consumer interface {
 	// Implementation must be concurrency safe; Non-blocking;
 	// and must handle repetition of the same events (with some processing overhead).
 	OnInvalidVoteDetected(*model.Vote)
+
+	// OnInvalidTimeoutDetected notifications are produced by the Vote Aggregation logic
+	// whenever an invalid timeout was detected.
+	// Prerequisites:
+	// Implementation must be concurrency safe; Non-blocking;
+	// and must handle repetition of the same events (with some processing overhead).
+	OnInvalidTimeoutDetected(*model.TimeoutObject)
 }
@@ -27,8 +27,10 @@ type EventHandler struct {
 	voteAggregator hotstuff.VoteAggregator
 	voter          hotstuff.Voter
 	validator      hotstuff.Validator
+	signer         hotstuff.Signer
 	notifier       hotstuff.Consumer
 	ownProposal    flow.Identifier
+	highestTC      *flow.TimeoutCertificate
 }
 
 // New creates an EventHandler instance with initial components.
@@ -43,6 +45,7 @@ func New(
 	voteAggregator hotstuff.VoteAggregator,
 	voter hotstuff.Voter,
 	validator hotstuff.Validator,
+	signer hotstuff.Signer,
 	notifier hotstuff.Consumer,
 ) (*EventHandler, error) {
 	e := &EventHandler{
@@ -55,6 +58,7 @@ func New(
 		voteAggregator: voteAggregator,
 		voter:          voter,
 		validator:      validator,
+		signer:         signer,
 		committee:      committee,
 		notifier:       notifier,
 		ownProposal:    flow.ZeroID,
@@ -173,6 +177,54 @@ func (e *EventHandler) OnReceiveProposal(proposal *model.Proposal) error {
 	return nil
 }
 
+// OnReceiveTimeout processes the timeout when a timeout is received.
+// Timeouts for views below the current view are dropped, as they can't
+// help the replica to synchronize its view anymore.
+func (e *EventHandler) OnReceiveTimeout(timeout *model.TimeoutObject) error {
+	curView := e.paceMaker.CurView()
+	log := e.log.With().
+		Uint64("cur_view", curView).
+		Uint64("timeout_view", timeout.View).
+		Hex("signer", timeout.SignerID[:]).
+		Logger()
+
+	e.notifier.OnReceiveTimeout(curView, timeout)
+	defer e.notifier.OnEventProcessed()
+	log.Debug().Msg("timeout forwarded from compliance engine")
+
+	// timeouts for past views should be dropped:
+	if timeout.View < curView {
+		log.Debug().Msg("skipping timeout view below current view")
+		return nil
+	}
+
+	// validate the timeout, including the highest QC and TC it carries
+	_, err := e.validator.ValidateTimeout(timeout)
+	if model.IsInvalidTimeoutError(err) {
+		log.Warn().Err(err).Msg("invalid timeout")
+		e.notifier.OnInvalidTimeoutDetected(timeout)
+		return nil
+	}
+	var missingBlockErr model.MissingBlockError
+	if errors.As(err, &missingBlockErr) || errors.Is(err, model.ErrUnverifiableBlock) {
+		// We don't need to proactively fetch the missing block, because the chain compliance layer
+		// requests missing blocks. As the replica re-broadcasts its timeout, we can drop it here.
+		log.Debug().Err(err).Msg("skipping timeout with unknown highest QC")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot validate timeout (%x): %w", timeout.ID(), err)
+	}
+
+	err = e.processTimeout(timeout)
+	if err != nil {
+		return fmt.Errorf("failed processing timeout: %w", err)
+	}
+	log.Debug().Uint64("new_view", e.paceMaker.CurView()).Msg("timeout processed")
+
+	return nil
+}
+
 // TimeoutChannel returns the channel for subscribing the waiting timeout on receiving
 // block or votes for the current view.
 func (e *EventHandler) TimeoutChannel() <-chan time.Time {
@@ -184,34 +236,89 @@ func (e *EventHandler) TimeoutChannel() <-chan time.Time {
 func (e *EventHandler) OnLocalTimeout() error {
 
 	curView := e.paceMaker.CurView()
-	newView := e.paceMaker.OnTimeout()
+	timerInfo := e.paceMaker.OnTimeout()
 	defer e.notifier.OnEventProcessed()
 
 	log := e.log.With().
 		Uint64("cur_view", curView).
-		Uint64("new_view", newView.View).
+		Str("timeout_mode", timerInfo.Mode.String()).
 		Logger()
 	// 	notifications about time-outs and view-changes are generated by PaceMaker; no need to send a notification here
 	log.Debug().Msg("timeout received from event loop")
 
-	if curView == newView.View {
-		return fmt.Errorf("OnLocalTimeout should guarantee that the pacemaker should go to next view, but didn't: (curView: %v, newView: %v)", curView, newView.View)
+	if timerInfo.Mode == model.VoteCollectionTimeout || e.paceMaker.CurView() != curView {
+		// as the leader for the next view, we gave up collecting votes and the pacemaker moved on to
+		// the next view, like all replicas did that voted for the block of the current view. Before
+		// timeout certificates are active, the pacemaker also moves on after a replica timeout; no
+		// timeout is broadcast then, as replicas without support for timeouts can't process them.
+		newView := e.paceMaker.CurView()
+		if curView == newView {
+			return fmt.Errorf("OnLocalTimeout should guarantee that the pacemaker should go to next view on vote collection timeout, but didn't: (curView: %v, newView: %v)", curView, newView)
+		}
+
+		// current view has changed, go to new view
+		err := e.startNewView()
+		if err != nil {
+			return fmt.Errorf("could not start new view: %w", err)
+		}
+
+		log.Debug().Uint64("new_view", newView).Msg("local timeout processed")
+		return nil
 	}
 
-	// current view has changed, go to new view
-	err := e.startNewView()
+	// the replica gave up on the current view: broadcast the timeout, which includes the
+	// replica's highest QC and TC, such that lagging replicas can synchronize their view
+	qc, _, err := e.forks.MakeForkChoice(curView)
 	if err != nil {
-		return fmt.Errorf("could not start new view: %w", err)
+		return fmt.Errorf("can not make fork choice for view %v: %w", curView, err)
+	}
+	// the highest TC is only included if it is for the previous view, i.e. if the replica entered
+	// the current view through the TC. An older TC might reference a QC, which other replicas have
+	// already pruned, such that they could not validate the timeout anymore.
+	var tc *flow.TimeoutCertificate
+	if e.highestTC != nil && e.highestTC.View+1 == curView {
+		tc = e.highestTC
+	}
+	timeout, err := e.signer.CreateTimeout(curView, qc, tc)
+	if err != nil {
+		return fmt.Errorf("could not create timeout for view %v: %w", curView, err)
 	}
 
-	log.Debug().Msg("local timeout processed")
+	log.Debug().
+		Uint64("highest_qc_view", qc.View).
+		Msg("forwarding timeout to communicator for broadcasting")
+	err = e.communicator.BroadcastTimeout(timeout)
+	if err != nil {
+		log.Warn().Err(err).Msg("could not forward timeout")
+	}
+
+	// instead of sending the timeout through the network and receiving it back,
+	// it can be processed locally right away.
+	e.notifier.OnReceiveTimeout(curView, timeout)
+	err = e.processTimeout(timeout)
+	if err != nil {
+		return fmt.Errorf("failed processing own timeout: %w", err)
+	}
+
+	log.Debug().Uint64("new_view", e.paceMaker.CurView()).Msg("local timeout processed")
 
 	return nil
 }
 
 // Start will start the pacemaker's timer and start the new view
 func (e *EventHandler) Start() error {
+	// load the highest TC, so that the replica can include it in its timeouts after a restart
+	highestTC, err := e.persist.GetHighestTC()
+	if err != nil {
+		return fmt.Errorf("could not load highest timeout certificate: %w", err)
+	}
+	e.highestTC = highestTC
+
 	e.paceMaker.Start()
+	if highestTC != nil {
+		// in case we crashed after persisting the TC, but before entering the view it certifies
+		_, _ = e.paceMaker.UpdateCurViewWithTC(highestTC)
+	}
 	return e.startNewView()
 }
 
@@ -549,3 +656,101 @@ func (e *EventHandler) processQC(qc *flow.QuorumCertificate) error {
 	// current view has changed, go to new view
 	return e.startNewView()
 }
+
+// processTimeout stores the timeout and check whether a TC can be built.
+// Before, it processes the highest QC and TC included in the timeout, which
+// might allow the replica to catch up. If a TC is built, then process the TC.
+// It assumes the timeout has been validated.
+func (e *EventHandler) processTimeout(timeout *model.TimeoutObject) error {
+
+	log := e.log.With().
+		Uint64("timeout_view", timeout.View).
+		Hex("signer", timeout.SignerID[:]).
+		Logger()
+
+	// the timeout's highest TC has been certified by a super-majority; its highest QC
+	// is at least as high as the one of the timeout
+	if timeout.HighestTC != nil {
+		err := e.processTC(timeout.HighestTC)
+		if err != nil {
+			return fmt.Errorf("failed processing highest TC of timeout: %w", err)
+		}
+	} else if timeout.HighestQC.View >= e.paceMaker.CurView() {
+		err := e.processQC(timeout.HighestQC)
+		if err != nil {
+			return fmt.Errorf("failed processing highest QC of timeout: %w", err)
+		}
+	}
+
+	tc, built, err := e.voteAggregator.StoreTimeoutAndBuildTC(timeout, e.paceMaker.CurView())
+	if err != nil {
+		return fmt.Errorf("building tc for view %d failed: %w", timeout.View, err)
+	}
+	// if we don't have enough timeouts to build TC for this view:
+	// nothing more to do for processing timeout
+	if !built {
+		log.Debug().Msg("insufficient timeouts for TC, waiting for more")
+		return e.joinView()
+	}
+	log.Debug().Msg("enough timeouts for TC collected")
+
+	return e.processTC(tc)
+}
+
+// joinView checks whether replicas with more than a third of the stake have timed out in
+// higher views. If so, the replica joins the highest such view, as it would otherwise not
+// be able to contribute to a TC with the replicas ahead.
+func (e *EventHandler) joinView() error {
+	view, found := e.voteAggregator.ViewToJoin(e.paceMaker.CurView())
+	if !found {
+		return nil
+	}
+
+	_, viewChanged := e.paceMaker.JoinView(view)
+	if !viewChanged {
+		return nil
+	}
+	e.log.Debug().Uint64("joined_view", view).Msg("timeouts triggered view change, starting new view now")
+
+	// current view has changed, go to new view
+	return e.startNewView()
+}
+
+// processTC stores the TC and check whether the TC will trigger view change.
+// If triggered, then go to the new view.
+func (e *EventHandler) processTC(tc *flow.TimeoutCertificate) error {
+
+	log := e.log.With().
+		Uint64("tc_view", tc.View).
+		Uint64("highest_qc_view", tc.HighestQC.View).
+		Int("signers", len(tc.SignerIDs)).
+		Logger()
+
+	// the highest QC of the TC might be newer than the QCs known to the replica. We
+	// only process it if we know the block, as Forks requires the block of a QC.
+	_, found := e.forks.GetBlock(tc.HighestQC.BlockID)
+	if found && tc.HighestQC.View >= e.paceMaker.CurView() {
+		err := e.processQC(tc.HighestQC)
+		if err != nil {
+			return fmt.Errorf("failed processing highest QC of TC: %w", err)
+		}
+	}
+
+	if e.highestTC == nil || tc.View > e.highestTC.View {
+		err := e.persist.PutHighestTC(tc)
+		if err != nil {
+			return fmt.Errorf("could not persist highest timeout certificate: %w", err)
+		}
+		e.highestTC = tc
+	}
+
+	_, viewChanged := e.paceMaker.UpdateCurViewWithTC(tc)
+	if !viewChanged {
+		log.Debug().Msg("TC didn't trigger view change, nothing to do")
+		return nil
+	}
+	log.Debug().Msg("TC triggered view change, starting new view now")
+
+	// current view has changed, go to new view
+	return e.startNewView()
+}
@@ -35,8 +35,8 @@ type TestPaceMaker struct {
 	t *testing.T
 }
 
-func NewTestPaceMaker(t *testing.T, startView uint64, timeoutController *timeout.Controller, notifier hotstuff.Consumer) *TestPaceMaker {
-	p, err := pacemaker.New(startView, timeoutController, notifier)
+func NewTestPaceMaker(t *testing.T, startView uint64, activationView uint64, timeoutController *timeout.Controller, notifier hotstuff.Consumer) *TestPaceMaker {
+	p, err := pacemaker.New(startView, activationView, timeoutController, notifier)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -57,15 +57,29 @@ func (p *TestPaceMaker) UpdateCurViewWithBlock(block *model.Block, isLeaderForNe
 	return newView, changed
 }
 
-func (p *TestPaceMaker) OnTimeout() *model.NewViewEvent {
+func (p *TestPaceMaker) UpdateCurViewWithTC(tc *flow.TimeoutCertificate) (*model.NewViewEvent, bool) {
 	oldView := p.CurView()
-	newView := p.PaceMaker.OnTimeout()
+	newView, changed := p.PaceMaker.UpdateCurViewWithTC(tc)
+	p.t.Logf("pacemaker.UpdateCurViewWithTC old view: %v, new view: %v\n", oldView, p.CurView())
+	return newView, changed
+}
+
+func (p *TestPaceMaker) JoinView(view uint64) (*model.NewViewEvent, bool) {
+	oldView := p.CurView()
+	newView, changed := p.PaceMaker.JoinView(view)
+	p.t.Logf("pacemaker.JoinView old view: %v, new view: %v\n", oldView, p.CurView())
+	return newView, changed
+}
+
+func (p *TestPaceMaker) OnTimeout() *model.TimerInfo {
+	oldView := p.CurView()
+	timerInfo := p.PaceMaker.OnTimeout()
 	p.t.Logf("pacemaker.OnTimeout old view: %v, new view: %v\n", oldView, p.CurView())
-	return newView
+	return timerInfo
 }
 
 // using a real pacemaker for testing event handler
-func initPaceMaker(t *testing.T, view uint64) hotstuff.PaceMaker {
+func initPaceMaker(t *testing.T, view uint64, activationView uint64) hotstuff.PaceMaker {
 	notifier := &mocks.Consumer{}
 	tc, err := timeout.NewConfig(
 		time.Duration(startRepTimeout*1e6),
@@ -77,9 +91,11 @@ func initPaceMaker(t *testing.T, view uint64) hotstuff.PaceMaker {
 	if err != nil {
 		t.Fail()
 	}
-	pm := NewTestPaceMaker(t, view, timeout.NewController(tc), notifier)
+	pm := NewTestPaceMaker(t, view, activationView, timeout.NewController(tc), notifier)
 	notifier.On("OnStartingTimeout", mock.Anything).Return()
 	notifier.On("OnQcTriggeredViewChange", mock.Anything, mock.Anything).Return()
+	notifier.On("OnTcTriggeredViewChange", mock.Anything, mock.Anything).Return()
+	notifier.On("OnTimeoutsTriggeredViewChange", mock.Anything).Return()
 	notifier.On("OnReachedTimeout", mock.Anything).Return()
 	pm.Start()
 	return pm
@@ -89,12 +105,17 @@ func initPaceMaker(t *testing.T, view uint64) hotstuff.PaceMaker {
 type VoteAggregator struct {
 	// if a blockID exists in qcs field, then a vote can be made into a QC
 	qcs map[flow.Identifier]*flow.QuorumCertificate
-	t   *testing.T
+	// if a view exists in tcs field, then a timeout can be made into a TC
+	tcs map[uint64]*flow.TimeoutCertificate
+	// if set, replicas with sufficient stake have timed out in the view to join
+	joinView uint64
+	t        *testing.T
 }
 
 func NewVoteAggregator(t *testing.T) *VoteAggregator {
 	return &VoteAggregator{
 		qcs: make(map[flow.Identifier]*flow.QuorumCertificate),
+		tcs: make(map[uint64]*flow.TimeoutCertificate),
 		t:   t,
 	}
 }
@@ -121,6 +142,20 @@ func (v *VoteAggregator) BuildQCOnReceivedBlock(block *model.Block) (*flow.Quoru
 	return qc, ok, nil
 }
 
+func (v *VoteAggregator) StoreTimeoutAndBuildTC(timeout *model.TimeoutObject, curView uint64) (*flow.TimeoutCertificate, bool, error) {
+	tc, ok := v.tcs[timeout.View]
+	v.t.Logf("voteaggregator.StoreTimeoutAndBuildTC, tc built: %v, for view: %v\n", ok, timeout.View)
+
+	return tc, ok, nil
+}
+
+func (v *VoteAggregator) ViewToJoin(curView uint64) (uint64, bool) {
+	ok := v.joinView > curView
+	v.t.Logf("voteaggregator.ViewToJoin, view to join: %v, found: %v\n", v.joinView, ok)
+
+	return v.joinView, ok
+}
+
 func (v *VoteAggregator) PruneByView(view uint64) {
 	v.t.Logf("pruned at view:%v\n", view)
 }
@@ -321,6 +356,7 @@ type EventHandlerSuite struct {
 	voteAggregator *VoteAggregator
 	voter          *Voter
 	validator      *BlacklistValidator
+	signer         *mocks.Signer
 	notifier       hotstuff.Consumer
 
 	initView    uint64
@@ -334,35 +370,29 @@ type EventHandlerSuite struct {
 func (es *EventHandlerSuite) SetupTest() {
 	finalized, curView := uint64(3), uint64(6)
 
-	es.paceMaker = initPaceMaker(es.T(), curView)
+	es.paceMaker = initPaceMaker(es.T(), curView, 0)
 	es.forks = NewForks(es.T(), finalized)
 	es.persist = &mocks.Persister{}
 	es.persist.On("PutStarted", mock.Anything).Return(nil)
+	es.persist.On("PutHighestTC", mock.Anything).Return(nil)
 	es.blockProducer = &BlockProducer{}
 	es.communicator = &mocks.Communicator{}
 	es.communicator.On("BroadcastProposalWithDelay", mock.Anything, mock.Anything).Return(nil)
 	es.communicator.On("SendVote", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	es.communicator.On("BroadcastTimeout", mock.Anything).Return(nil)
 	es.committee = NewCommittee()
 	es.voteAggregator = NewVoteAggregator(es.T())
 	es.voter = NewVoter(es.T(), finalized)
 	es.validator = NewBlacklistValidator(es.T())
+	es.signer = &mocks.Signer{}
+	es.signer.On("CreateTimeout", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(view uint64, highestQC *flow.QuorumCertificate, highestTC *flow.TimeoutCertificate) *model.TimeoutObject {
+			return createTimeout(view, highestQC, highestTC)
+		},
+		nil,
+	)
 	es.notifier = &notifications.NoopConsumer{}
-
-	eventhandler, err := eventhandler.New(
-		zerolog.New(os.Stderr),
-		es.paceMaker,
-		es.blockProducer,
-		es.forks,
-		es.persist,
-		es.communicator,
-		es.committee,
-		es.voteAggregator,
-		es.voter,
-		es.validator,
-		es.notifier)
-	require.NoError(es.T(), err)
-
-	es.eventhandler = eventhandler
+	es.eventhandler = es.newEventHandler()
 
 	es.initView = curView
 	es.endView = curView
@@ -385,6 +415,24 @@ func (es *EventHandlerSuite) SetupTest() {
 	}
 }
 
+func (es *EventHandlerSuite) newEventHandler() *eventhandler.EventHandler {
+	eventhandler, err := eventhandler.New(
+		zerolog.New(os.Stderr),
+		es.paceMaker,
+		es.blockProducer,
+		es.forks,
+		es.persist,
+		es.communicator,
+		es.committee,
+		es.voteAggregator,
+		es.voter,
+		es.validator,
+		es.signer,
+		es.notifier)
+	require.NoError(es.T(), err)
+	return eventhandler
+}
+
 func (es *EventHandlerSuite) TestVoteLowerFinalView() {
 	es.vote.View = uint64(es.forks.finalized - 1)
 
@@ -727,21 +775,202 @@ func (es *EventHandlerSuite) TestOnReceiveProposal_Unverifiable() {
 	require.Equal(es.T(), es.endView, es.paceMaker.CurView(), "incorrect view change")
 }
 
+// a local timeout should broadcast a timeout, but not trigger a view change without a TC
 func (es *EventHandlerSuite) TestOnTimeout() {
+	es.addForkChoice()
 	err := es.eventhandler.OnLocalTimeout()
-	// timeout will trigger viewchange
-	es.endView++
 	require.NoError(es.T(), err)
 	require.Equal(es.T(), es.endView, es.paceMaker.CurView(), "incorrect view change")
+	es.communicator.AssertCalled(es.T(), "BroadcastTimeout", mock.MatchedBy(func(timeout *model.TimeoutObject) bool {
+		return timeout.View == es.initView && timeout.HighestQC.View == es.initView-1
+	}))
+}
+
+// before timeout certificates are activated, a local timeout should trigger a view change without
+// broadcasting a timeout, like replicas do that don't support timeout certificates
+func (es *EventHandlerSuite) TestOnTimeout_BeforeTCActivation_ViewChange() {
+	es.paceMaker = initPaceMaker(es.T(), es.initView, es.initView+1)
+	es.eventhandler = es.newEventHandler()
+	es.addForkChoice()
+
+	err := es.eventhandler.OnLocalTimeout()
+	require.NoError(es.T(), err)
+	require.Equal(es.T(), es.initView+1, es.paceMaker.CurView(), "incorrect view change")
+	es.communicator.AssertNotCalled(es.T(), "BroadcastTimeout", mock.Anything)
+
+	// from the activation view on, the replica waits for a QC or TC
+	err = es.eventhandler.OnLocalTimeout()
+	require.NoError(es.T(), err)
+	require.Equal(es.T(), es.initView+1, es.paceMaker.CurView(), "incorrect view change")
+	es.communicator.AssertNumberOfCalls(es.T(), "BroadcastTimeout", 1)
+}
+
+// a local timeout should only include the highest TC, if the replica entered the view through the TC
+func (es *EventHandlerSuite) TestOnTimeout_IncludesTCForPreviousView() {
+	es.addForkChoice()
+	tc := createTC(es.initView, es.forks.qc)
+	es.voteAggregator.tcs[es.initView] = tc
+	err := es.eventhandler.OnLocalTimeout()
+	require.NoError(es.T(), err)
+	require.Equal(es.T(), es.initView+1, es.paceMaker.CurView(), "incorrect view change")
+
+	// the replica entered the view through the TC
+	err = es.eventhandler.OnLocalTimeout()
+	require.NoError(es.T(), err)
+	es.communicator.AssertCalled(es.T(), "BroadcastTimeout", mock.MatchedBy(func(timeout *model.TimeoutObject) bool {
+		return timeout.View == es.initView+1 && timeout.HighestTC == tc
+	}))
+
+	// the replica entered the view without the TC
+	_, viewChanged := es.paceMaker.JoinView(es.initView + 3)
+	require.True(es.T(), viewChanged)
+	err = es.eventhandler.OnLocalTimeout()
+	require.NoError(es.T(), err)
+	es.communicator.AssertCalled(es.T(), "BroadcastTimeout", mock.MatchedBy(func(timeout *model.TimeoutObject) bool {
+		return timeout.View == es.initView+3 && timeout.HighestTC == nil
+	}))
 }
 
+// repeated local timeouts should re-broadcast the timeout for the same view
 func (es *EventHandlerSuite) Test100Timeout() {
+	es.addForkChoice()
 	for i := 0; i < 100; i++ {
 		err := es.eventhandler.OnLocalTimeout()
-		es.endView++
 		require.NoError(es.T(), err)
 	}
 	require.Equal(es.T(), es.endView, es.paceMaker.CurView(), "incorrect view change")
+	es.communicator.AssertNumberOfCalls(es.T(), "BroadcastTimeout", 100)
+}
+
+// a local timeout that completes a TC should trigger a view change and persist the TC
+func (es *EventHandlerSuite) TestOnTimeout_TCBuilt_ViewChange() {
+	es.addForkChoice()
+	tc := createTC(es.initView, es.forks.qc)
+	es.voteAggregator.tcs[es.initView] = tc
+
+	err := es.eventhandler.OnLocalTimeout()
+	es.endView++
+	require.NoError(es.T(), err)
+	require.Equal(es.T(), es.endView, es.paceMaker.CurView(), "incorrect view change")
+	es.persist.AssertCalled(es.T(), "PutHighestTC", tc)
+
+	// the next timeout should include the TC
+	err = es.eventhandler.OnLocalTimeout()
+	require.NoError(es.T(), err)
+	es.communicator.AssertCalled(es.T(), "BroadcastTimeout", mock.MatchedBy(func(timeout *model.TimeoutObject) bool {
+		return timeout.View == es.endView && timeout.HighestTC == tc
+	}))
+}
+
+// a vote collection timeout of the next leader should trigger a view change without broadcasting a timeout
+func (es *EventHandlerSuite) TestOnTimeout_VoteCollection_ViewChange() {
+	es.addForkChoice()
+	// I'm the next leader
+	es.committee.leaders[es.initView+1] = struct{}{}
+	proposal := createProposal(es.initView, es.initView-1)
+	err := es.eventhandler.OnReceiveProposal(proposal)
+	require.NoError(es.T(), err)
+	require.Equal(es.T(), es.endView, es.paceMaker.CurView(), "incorrect view change")
+
+	err = es.eventhandler.OnLocalTimeout()
+	es.endView++
+	require.NoError(es.T(), err)
+	require.Equal(es.T(), es.endView, es.paceMaker.CurView(), "incorrect view change")
+	es.communicator.AssertNotCalled(es.T(), "BroadcastTimeout", mock.Anything)
+}
+
+// a timeout for a view below the current view should be ignored
+func (es *EventHandlerSuite) TestOnReceiveTimeout_Stale_NoViewChange() {
+	timeout := createTimeout(es.initView-1, createQC(createBlock(es.initView-2)), nil)
+	err := es.eventhandler.OnReceiveTimeout(timeout)
+	require.NoError(es.T(), err)
+	require.Equal(es.T(), es.endView, es.paceMaker.CurView(), "incorrect view change")
+	es.validator.AssertNotCalled(es.T(), "ValidateTimeout", mock.Anything)
+}
+
+// an invalid timeout should be ignored
+func (es *EventHandlerSuite) TestOnReceiveTimeout_Invalid_NoViewChange() {
+	es.addForkChoice()
+	tc := createTC(es.initView+4, es.forks.qc)
+	timeout := createTimeout(es.initView+5, es.forks.qc, tc)
+	es.validator.On("ValidateTimeout", timeout).Return(nil, model.InvalidTimeoutError{Err: fmt.Errorf("some error")})
+
+	err := es.eventhandler.OnReceiveTimeout(timeout)
+	require.NoError(es.T(), err)
+	require.Equal(es.T(), es.endView, es.paceMaker.CurView(), "incorrect view change")
+}
+
+// a timeout with a TC for a higher view should make the replica catch up
+func (es *EventHandlerSuite) TestOnReceiveTimeout_HigherTC_ViewChange() {
+	es.addForkChoice()
+	tc := createTC(es.initView+4, es.forks.qc)
+	timeout := createTimeout(es.initView+5, es.forks.qc, tc)
+	es.validator.On("ValidateTimeout", timeout).Return(nil, nil)
+
+	err := es.eventhandler.OnReceiveTimeout(timeout)
+	require.NoError(es.T(), err)
+	require.Equal(es.T(), es.initView+5, es.paceMaker.CurView(), "incorrect view change")
+	es.persist.AssertCalled(es.T(), "PutHighestTC", tc)
+}
+
+// a timeout with a QC for a higher view should make the replica catch up
+func (es *EventHandlerSuite) TestOnReceiveTimeout_HigherQC_ViewChange() {
+	es.addForkChoice()
+	block := createBlockWithQC(es.initView+2, es.initView+1)
+	err := es.forks.AddBlock(block)
+	require.NoError(es.T(), err)
+	timeout := createTimeout(es.initView+5, createQC(block), nil)
+	es.validator.On("ValidateTimeout", timeout).Return(nil, nil)
+
+	err = es.eventhandler.OnReceiveTimeout(timeout)
+	require.NoError(es.T(), err)
+	require.Equal(es.T(), es.initView+3, es.paceMaker.CurView(), "incorrect view change")
+}
+
+// a timeout that completes a TC for the current view should trigger a view change
+func (es *EventHandlerSuite) TestOnReceiveTimeout_TCBuilt_ViewChange() {
+	es.addForkChoice()
+	timeout := createTimeout(es.initView, es.forks.qc, nil)
+	es.validator.On("ValidateTimeout", timeout).Return(nil, nil)
+	es.voteAggregator.tcs[es.initView] = createTC(es.initView, es.forks.qc)
+
+	err := es.eventhandler.OnReceiveTimeout(timeout)
+	es.endView++
+	require.NoError(es.T(), err)
+	require.Equal(es.T(), es.endView, es.paceMaker.CurView(), "incorrect view change")
+}
+
+// timeouts of replicas with sufficient stake for a higher view should make the replica join the view
+func (es *EventHandlerSuite) TestOnReceiveTimeout_JoinView_ViewChange() {
+	es.addForkChoice()
+	timeout := createTimeout(es.initView+5, es.forks.qc, nil)
+	es.validator.On("ValidateTimeout", timeout).Return(nil, nil)
+	es.voteAggregator.joinView = es.initView + 5
+
+	err := es.eventhandler.OnReceiveTimeout(timeout)
+	require.NoError(es.T(), err)
+	require.Equal(es.T(), es.initView+5, es.paceMaker.CurView(), "incorrect view change")
+}
+
+// timeouts of replicas with insufficient stake for a higher view should not trigger a view change
+func (es *EventHandlerSuite) TestOnReceiveTimeout_InsufficientTimeouts_NoViewChange() {
+	es.addForkChoice()
+	timeout := createTimeout(es.initView+5, es.forks.qc, nil)
+	es.validator.On("ValidateTimeout", timeout).Return(nil, nil)
+
+	err := es.eventhandler.OnReceiveTimeout(timeout)
+	require.NoError(es.T(), err)
+	require.Equal(es.T(), es.endView, es.paceMaker.CurView(), "incorrect view change")
+}
+
+// addForkChoice adds the block for the previous view, including its QC, to forks,
+// such that the replica can make a fork choice for the current view
+func (es *EventHandlerSuite) addForkChoice() {
+	parent := createBlockWithQC(es.initView-1, es.initView-2)
+	err := es.forks.AddBlock(parent)
+	require.NoError(es.T(), err)
+	err = es.forks.AddQC(createQC(parent))
+	require.NoError(es.T(), err)
 }
 
 // a leader builds 100 blocks one after another
@@ -846,3 +1075,22 @@ func createProposal(view uint64, qcview uint64) *model.Proposal {
 		SigData: nil,
 	}
 }
+
+func createTimeout(view uint64, highestQC *flow.QuorumCertificate, highestTC *flow.TimeoutCertificate) *model.TimeoutObject {
+	return &model.TimeoutObject{
+		View:      view,
+		HighestQC: highestQC,
+		HighestTC: highestTC,
+		SignerID:  flow.ZeroID,
+		SigData:   nil,
+	}
+}
+
+func createTC(view uint64, highestQC *flow.QuorumCertificate) *flow.TimeoutCertificate {
+	return &flow.TimeoutCertificate{
+		View:      view,
+		HighestQC: highestQC,
+		SignerIDs: nil,
+		SigData:   nil,
+	}
+}
@@ -0,0 +1,147 @@
+package integration
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
+	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestAdaptiveTimeoutLeaderFailure runs five instances, one of which has
+// crashed, such that every fifth view the leader fails. With static timeouts,
+// each failed leader costs the minimum replica timeout, which has to be chosen
+// conservatively. The adaptive timeout learns the much lower latency of the
+// (in-process) network, and hence recovers from failed leaders faster.
+// Recovery is measured by the replica timeouts the instances reach, rather than
+// by the wall-clock time of the runs, which depends on the machine's load.
+func TestAdaptiveTimeoutLeaderFailure(t *testing.T) {
+
+	// the conservative static timeout, which is also the upper bound for the
+	// adaptive timeout, such that both start from the same timeout
+	staticTimeout := 400 * time.Millisecond
+	timeouts, err := timeout.NewConfig(staticTimeout, staticTimeout, 0.5, 1.5, safeDecreaseFactor, 0)
+	require.NoError(t, err)
+	adaptive, err := timeout.NewAdaptiveConfig(0.9, 4, 20, 3, 20*time.Millisecond, staticTimeout)
+	require.NoError(t, err)
+
+	finalView := uint64(30)
+	static := runWithFailedLeader(t, finalView, WithTimeouts(timeouts))
+	adapted := runWithFailedLeader(t, finalView, WithTimeouts(timeouts), WithAdaptiveTimeouts(adaptive))
+
+	// with static timeouts, each failed leader costs exactly the static timeout
+	require.NotEmpty(t, static, "instances should time out on the failed leader")
+	for _, duration := range static {
+		require.Equal(t, staticTimeout, duration)
+	}
+
+	// with adaptive timeouts, failed leaders cost less in total, and once the
+	// latency is learned, less than the static timeout
+	require.NotEmpty(t, adapted, "instances should time out on the failed leader")
+	assert.Less(t, int64(sum(adapted)), int64(sum(static)),
+		"adaptive timeouts should recover from failed leaders faster than static timeouts")
+	assert.Less(t, int64(median(adapted)), int64(staticTimeout),
+		"adaptive timeouts should adapt to the observed latency")
+}
+
+// runWithFailedLeader runs four instances with the given options until they
+// reach the final view, while the fifth participant has crashed. It returns
+// the durations of the replica timeouts the instances reached, in the order
+// they were reached.
+func runWithFailedLeader(t *testing.T, finalView uint64, options ...Option) []time.Duration {
+
+	numPass := 4
+	numFail := 1
+	participants := unittest.IdentityListFixture(numPass + numFail)
+	root := DefaultRoot()
+	recorder := &timeoutRecorder{}
+
+	instances := make([]*Instance, 0, numPass+numFail)
+	for n := 0; n < numPass+numFail; n++ {
+		opts := append([]Option{
+			WithRoot(root),
+			WithParticipants(participants),
+			WithLocalID(participants[n].NodeID),
+			WithStopCondition(ViewReached(finalView)),
+			WithConsumer(recorder),
+		}, options...)
+		// the crashed instance neither votes, nor proposes
+		if n >= numPass {
+			opts = append(opts,
+				WithOutgoingVotes(BlockAllVotes),
+				WithIncomingVotes(BlockAllVotes),
+				WithOutgoingProposals(BlockAllProposals),
+				WithIncomingProposals(BlockAllProposals),
+			)
+		}
+		instances = append(instances, NewInstance(t, opts...))
+	}
+
+	// connect the communicators of the instances together
+	Connect(instances)
+
+	// only run the instances which have not crashed
+	var wg sync.WaitGroup
+	for _, in := range instances[:numPass] {
+		wg.Add(1)
+		go func(in *Instance) {
+			err := in.Run()
+			require.True(t, errors.Is(err, errStopCondition), "should run until stop condition")
+			wg.Done()
+		}(in)
+	}
+	// the bound is generous, as it only guards against the instances getting stuck
+	unittest.RequireReturnsBefore(t, wg.Wait, 60*time.Second, "instances should reach the final view")
+
+	// check that the instances made progress despite the failed leader
+	for i, in := range instances[:numPass] {
+		assert.Less(t, uint64(0), in.forks.FinalizedView(), "instance %d should have finalized blocks", i)
+	}
+
+	return recorder.durations()
+}
+
+// timeoutRecorder records the durations of the replica timeouts reached by
+// the instances it consumes the notifications of.
+type timeoutRecorder struct {
+	notifications.NoopConsumer
+	mu      sync.Mutex
+	reached []time.Duration
+}
+
+func (r *timeoutRecorder) OnReachedTimeout(timerInfo *model.TimerInfo) {
+	if timerInfo.Mode != model.ReplicaTimeout {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reached = append(r.reached, timerInfo.Duration)
+}
+
+func (r *timeoutRecorder) durations() []time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]time.Duration(nil), r.reached...)
+}
+
+func sum(durations []time.Duration) time.Duration {
+	var total time.Duration
+	for _, duration := range durations {
+		total += duration
+	}
+	return total
+}
+
+func median(durations []time.Duration) time.Duration {
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[len(sorted)/2]
+}
@@ -2,6 +2,7 @@ package integration
 
 import (
 	"math/rand"
+	"sync/atomic"
 
 	"github.com/onflow/flow-go/consensus/hotstuff/model"
 	"github.com/onflow/flow-go/model/flow"
@@ -50,3 +51,61 @@ func BlockProposalsBy(proposerID flow.Identifier) ProposalFilter {
 		return proposal.Block.ProposerID == proposerID
 	}
 }
+
+type TimeoutFilter func(*model.TimeoutObject) bool
+
+func BlockNoTimeouts(*model.TimeoutObject) bool {
+	return false
+}
+
+func BlockAllTimeouts(*model.TimeoutObject) bool {
+	return true
+}
+
+// Partition separates the instances into groups, which can't communicate with
+// each other until the partition is healed.
+type Partition struct {
+	groups map[flow.Identifier]int
+	healed uint32
+}
+
+func NewPartition(groups ...flow.IdentityList) *Partition {
+	p := &Partition{
+		groups: make(map[flow.Identifier]int),
+	}
+	for i, group := range groups {
+		for _, identity := range group {
+			p.groups[identity.NodeID] = i
+		}
+	}
+	return p
+}
+
+func (p *Partition) Heal() {
+	atomic.StoreUint32(&p.healed, 1)
+}
+
+func (p *Partition) separated(senderID flow.Identifier, receiverID flow.Identifier) bool {
+	if atomic.LoadUint32(&p.healed) == 1 {
+		return false
+	}
+	return p.groups[senderID] != p.groups[receiverID]
+}
+
+func (p *Partition) IncomingVotes(receiverID flow.Identifier) VoteFilter {
+	return func(vote *model.Vote) bool {
+		return p.separated(vote.SignerID, receiverID)
+	}
+}
+
+func (p *Partition) IncomingProposals(receiverID flow.Identifier) ProposalFilter {
+	return func(proposal *model.Proposal) bool {
+		return p.separated(proposal.Block.ProposerID, receiverID)
+	}
+}
+
+func (p *Partition) IncomingTimeouts(receiverID flow.Identifier) TimeoutFilter {
+	return func(timeout *model.TimeoutObject) bool {
+		return p.separated(timeout.SignerID, receiverID)
+	}
+}
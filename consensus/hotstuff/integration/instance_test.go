@@ -19,6 +19,7 @@ import (
 	"github.com/onflow/flow-go/consensus/hotstuff/mocks"
 	"github.com/onflow/flow-go/consensus/hotstuff/model"
 	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications/pubsub"
 	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker"
 	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
 	"github.com/onflow/flow-go/consensus/hotstuff/validator"
@@ -38,6 +39,8 @@ type Instance struct {
 	blockVoteOut VoteFilter
 	blockPropIn  ProposalFilter
 	blockPropOut ProposalFilter
+	timeoutIn    TimeoutFilter
+	timeoutOut   TimeoutFilter
 	stop         Condition
 
 	// instance data
@@ -80,6 +83,8 @@ func NewInstance(t require.TestingT, options ...Option) *Instance {
 		OutgoingVotes:     BlockNoVotes,
 		IncomingProposals: BlockNoProposals,
 		OutgoingProposals: BlockNoProposals,
+		IncomingTimeouts:  BlockNoTimeouts,
+		OutgoingTimeouts:  BlockNoTimeouts,
 		StopCondition:     RightAway,
 	}
 
@@ -110,6 +115,8 @@ func NewInstance(t require.TestingT, options ...Option) *Instance {
 		blockVoteOut: cfg.OutgoingVotes,
 		blockPropIn:  cfg.IncomingProposals,
 		blockPropOut: cfg.OutgoingProposals,
+		timeoutIn:    cfg.IncomingTimeouts,
+		timeoutOut:   cfg.OutgoingTimeouts,
 		stop:         cfg.StopCondition,
 
 		// instance data
@@ -175,6 +182,8 @@ func NewInstance(t require.TestingT, options ...Option) *Instance {
 	// check on stop condition, stop the tests as soon as entering a certain view
 	in.persist.On("PutStarted", mock.Anything).Return(nil)
 	in.persist.On("PutVoted", mock.Anything).Return(nil)
+	in.persist.On("GetHighestTC").Return(nil, nil)
+	in.persist.On("PutHighestTC", mock.Anything).Return(nil)
 
 	// program the hotstuff signer behaviour
 	in.signer.On("CreateProposal", mock.Anything).Return(
@@ -216,9 +225,45 @@ func NewInstance(t require.TestingT, options ...Option) *Instance {
 		nil,
 	)
 
+	in.signer.On("CreateTimeout", mock.Anything, mock.Anything, mock.Anything).Return(
+		func(view uint64, highestQC *flow.QuorumCertificate, highestTC *flow.TimeoutCertificate) *model.TimeoutObject {
+			timeout := &model.TimeoutObject{
+				View:      view,
+				HighestQC: highestQC,
+				HighestTC: highestTC,
+				SignerID:  in.localID,
+				SigData:   nil,
+			}
+			return timeout
+		},
+		nil,
+	)
+	in.signer.On("CreateTC", mock.Anything).Return(
+		func(timeouts []*model.TimeoutObject) *flow.TimeoutCertificate {
+			signerIDs := make([]flow.Identifier, 0, len(timeouts))
+			highestQC := timeouts[0].HighestQC
+			for _, timeout := range timeouts {
+				signerIDs = append(signerIDs, timeout.SignerID)
+				if timeout.HighestQC.View > highestQC.View {
+					highestQC = timeout.HighestQC
+				}
+			}
+			tc := &flow.TimeoutCertificate{
+				View:      timeouts[0].View,
+				HighestQC: highestQC,
+				SignerIDs: signerIDs,
+				SigData:   nil,
+			}
+			return tc
+		},
+		nil,
+	)
+
 	// program the hotstuff verifier behaviour
 	in.verifier.On("VerifyVote", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
 	in.verifier.On("VerifyQC", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	in.verifier.On("VerifyTimeout", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	in.verifier.On("VerifyTC", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
 
 	// program the hotstuff communicator behaviour
 	in.communicator.On("BroadcastProposalWithDelay", mock.Anything, mock.Anything).Return(
@@ -246,6 +291,7 @@ func NewInstance(t require.TestingT, options ...Option) *Instance {
 		},
 	)
 	in.communicator.On("SendVote", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	in.communicator.On("BroadcastTimeout", mock.Anything).Return(nil)
 
 	// program the finalizer module behaviour
 	in.finalizer.On("MakeFinal", mock.Anything).Return(
@@ -283,11 +329,20 @@ func NewInstance(t require.TestingT, options ...Option) *Instance {
 	var err error
 	zerolog.TimestampFunc = func() time.Time { return time.Now().UTC() }
 	log := zerolog.New(os.Stderr).Level(zerolog.DebugLevel).With().Timestamp().Uint("index", index).Hex("local_id", in.localID[:]).Logger()
-	notifier := notifications.NewLogConsumer(log)
+	var notifier hotstuff.Consumer = notifications.NewLogConsumer(log)
+	if cfg.Consumer != nil {
+		distributor := pubsub.NewDistributor()
+		distributor.AddConsumer(notifier)
+		distributor.AddConsumer(cfg.Consumer)
+		notifier = distributor
+	}
 
 	// initialize the pacemaker
 	controller := timeout.NewController(cfg.Timeouts)
-	in.pacemaker, err = pacemaker.New(DefaultStart(), controller, notifier)
+	if cfg.AdaptiveTimeouts != nil {
+		controller = timeout.NewAdaptiveController(cfg.Timeouts, *cfg.AdaptiveTimeouts)
+	}
+	in.pacemaker, err = pacemaker.New(DefaultStart(), 0, controller, notifier)
 	require.NoError(t, err)
 
 	// initialize the block producer
@@ -322,7 +377,7 @@ func NewInstance(t require.TestingT, options ...Option) *Instance {
 	in.voter = voter.New(in.signer, in.forks, in.persist, in.committee, DefaultVoted())
 
 	// initialize the event handler
-	in.handler, err = eventhandler.New(log, in.pacemaker, in.producer, in.forks, in.persist, in.communicator, in.committee, in.aggregator, in.voter, in.validator, notifier)
+	in.handler, err = eventhandler.New(log, in.pacemaker, in.producer, in.forks, in.persist, in.communicator, in.committee, in.aggregator, in.voter, in.validator, in.signer, notifier)
 	require.NoError(t, err)
 
 	return &in
@@ -378,6 +433,11 @@ func (in *Instance) Run() error {
 				if err != nil {
 					return fmt.Errorf("could not process vote: %w", err)
 				}
+			case *model.TimeoutObject:
+				err := in.handler.OnReceiveTimeout(m)
+				if err != nil {
+					return fmt.Errorf("could not process timeout: %w", err)
+				}
 			}
 		}
 
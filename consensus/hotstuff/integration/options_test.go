@@ -3,6 +3,7 @@ package integration
 import (
 	"errors"
 
+	"github.com/onflow/flow-go/consensus/hotstuff"
 	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
 	"github.com/onflow/flow-go/model/flow"
 )
@@ -16,11 +17,15 @@ type Config struct {
 	Participants      flow.IdentityList
 	LocalID           flow.Identifier
 	Timeouts          timeout.Config
+	AdaptiveTimeouts  *timeout.AdaptiveConfig
 	IncomingVotes     VoteFilter
 	OutgoingVotes     VoteFilter
 	IncomingProposals ProposalFilter
 	OutgoingProposals ProposalFilter
+	IncomingTimeouts  TimeoutFilter
+	OutgoingTimeouts  TimeoutFilter
 	StopCondition     Condition
+	Consumer          hotstuff.Consumer
 }
 
 func WithRoot(root *flow.Header) Option {
@@ -47,6 +52,20 @@ func WithTimeouts(timeouts timeout.Config) Option {
 	}
 }
 
+func WithAdaptiveTimeouts(adaptive timeout.AdaptiveConfig) Option {
+	return func(cfg *Config) {
+		cfg.AdaptiveTimeouts = &adaptive
+	}
+}
+
+// WithConsumer adds a consumer of the instance's hotstuff notifications, next
+// to the log consumer.
+func WithConsumer(consumer hotstuff.Consumer) Option {
+	return func(cfg *Config) {
+		cfg.Consumer = consumer
+	}
+}
+
 func WithIncomingVotes(Filter VoteFilter) Option {
 	return func(cfg *Config) {
 		cfg.IncomingVotes = Filter
@@ -71,6 +90,18 @@ func WithOutgoingProposals(Filter ProposalFilter) Option {
 	}
 }
 
+func WithIncomingTimeouts(Filter TimeoutFilter) Option {
+	return func(cfg *Config) {
+		cfg.IncomingTimeouts = Filter
+	}
+}
+
+func WithOutgoingTimeouts(Filter TimeoutFilter) Option {
+	return func(cfg *Config) {
+		cfg.OutgoingTimeouts = Filter
+	}
+}
+
 func WithStopCondition(stop Condition) Option {
 	return func(cfg *Config) {
 		cfg.StopCondition = stop
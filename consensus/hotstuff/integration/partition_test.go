@@ -0,0 +1,75 @@
+package integration
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestPartitionedInstances runs four instances, which are split into two groups
+// of two instances each. While partitioned, neither group has enough stake to
+// build QCs or TCs, and the views of the instances drift apart. Once the partition
+// is healed, the instances have to synchronize their views through timeouts,
+// before they can make progress again.
+func TestPartitionedInstances(t *testing.T) {
+
+	num := 4
+	finalView := uint64(30)
+	partitionDuration := time.Second
+
+	participants := unittest.IdentityListFixture(num)
+	root := DefaultRoot()
+	timeouts, err := timeout.NewConfig(100*time.Millisecond, 100*time.Millisecond, 0.5, 1.5, safeDecreaseFactor, 0)
+	require.NoError(t, err)
+
+	// split the participants into two groups
+	partition := NewPartition(participants[:num/2], participants[num/2:])
+
+	instances := make([]*Instance, 0, num)
+	for n := 0; n < num; n++ {
+		localID := participants[n].NodeID
+		in := NewInstance(t,
+			WithRoot(root),
+			WithParticipants(participants),
+			WithLocalID(localID),
+			WithTimeouts(timeouts),
+			WithStopCondition(ViewFinalized(finalView)),
+			WithIncomingVotes(partition.IncomingVotes(localID)),
+			WithIncomingProposals(partition.IncomingProposals(localID)),
+			WithIncomingTimeouts(partition.IncomingTimeouts(localID)),
+		)
+		instances = append(instances, in)
+	}
+
+	// connect the communicators of the instances together
+	Connect(instances)
+
+	// start the instances and heal the partition after a while
+	var wg sync.WaitGroup
+	for _, in := range instances {
+		wg.Add(1)
+		go func(in *Instance) {
+			err := in.Run()
+			require.True(t, errors.Is(err, errStopCondition), "should run until stop condition")
+			wg.Done()
+		}(in)
+	}
+	time.AfterFunc(partitionDuration, partition.Heal)
+	wg.Wait()
+
+	// check that all instances have the same finalized blocks
+	ref := instances[0]
+	assert.GreaterOrEqual(t, ref.forks.FinalizedBlock().View, finalView, "instance 0 should have made progress after the partition was healed")
+	finalizedViews := FinalizedViews(ref)
+	for i := 1; i < num; i++ {
+		assert.Equal(t, ref.forks.FinalizedBlock(), instances[i].forks.FinalizedBlock(), "instance %d should have same finalized block as first instance", i)
+		assert.Equal(t, finalizedViews, FinalizedViews(instances[i]), "instance %d should have same finalized views as first instance", i)
+	}
+}
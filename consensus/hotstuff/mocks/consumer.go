@@ -50,6 +50,11 @@ func (_m *Consumer) OnForkChoiceGenerated(_a0 uint64, _a1 *flow.QuorumCertificat
 	_m.Called(_a0, _a1)
 }
 
+// OnInvalidTimeoutDetected provides a mock function with given fields: _a0
+func (_m *Consumer) OnInvalidTimeoutDetected(_a0 *model.TimeoutObject) {
+	_m.Called(_a0)
+}
+
 // OnInvalidVoteDetected provides a mock function with given fields: _a0
 func (_m *Consumer) OnInvalidVoteDetected(_a0 *model.Vote) {
 	_m.Called(_a0)
@@ -85,6 +90,11 @@ func (_m *Consumer) OnReceiveProposal(currentView uint64, proposal *model.Propos
 	_m.Called(currentView, proposal)
 }
 
+// OnReceiveTimeout provides a mock function with given fields: currentView, timeout
+func (_m *Consumer) OnReceiveTimeout(currentView uint64, timeout *model.TimeoutObject) {
+	_m.Called(currentView, timeout)
+}
+
 // OnReceiveVote provides a mock function with given fields: currentView, vote
 func (_m *Consumer) OnReceiveVote(currentView uint64, vote *model.Vote) {
 	_m.Called(currentView, vote)
@@ -95,6 +105,21 @@ func (_m *Consumer) OnStartingTimeout(_a0 *model.TimerInfo) {
 	_m.Called(_a0)
 }
 
+// OnTcConstructedFromTimeouts provides a mock function with given fields: _a0
+func (_m *Consumer) OnTcConstructedFromTimeouts(_a0 *flow.TimeoutCertificate) {
+	_m.Called(_a0)
+}
+
+// OnTcTriggeredViewChange provides a mock function with given fields: tc, newView
+func (_m *Consumer) OnTcTriggeredViewChange(tc *flow.TimeoutCertificate, newView uint64) {
+	_m.Called(tc, newView)
+}
+
+// OnTimeoutsTriggeredViewChange provides a mock function with given fields: newView
+func (_m *Consumer) OnTimeoutsTriggeredViewChange(newView uint64) {
+	_m.Called(newView)
+}
+
 // OnVoting provides a mock function with given fields: vote
 func (_m *Consumer) OnVoting(vote *model.Vote) {
 	_m.Called(vote)
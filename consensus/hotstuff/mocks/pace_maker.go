@@ -45,16 +45,39 @@ func (_m *PaceMaker) CurView() uint64 {
 	return r0
 }
 
+// JoinView provides a mock function with given fields: view
+func (_m *PaceMaker) JoinView(view uint64) (*model.NewViewEvent, bool) {
+	ret := _m.Called(view)
+
+	var r0 *model.NewViewEvent
+	if rf, ok := ret.Get(0).(func(uint64) *model.NewViewEvent); ok {
+		r0 = rf(view)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.NewViewEvent)
+		}
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(uint64) bool); ok {
+		r1 = rf(view)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
 // OnTimeout provides a mock function with given fields:
-func (_m *PaceMaker) OnTimeout() *model.NewViewEvent {
+func (_m *PaceMaker) OnTimeout() *model.TimerInfo {
 	ret := _m.Called()
 
-	var r0 *model.NewViewEvent
-	if rf, ok := ret.Get(0).(func() *model.NewViewEvent); ok {
+	var r0 *model.TimerInfo
+	if rf, ok := ret.Get(0).(func() *model.TimerInfo); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*model.NewViewEvent)
+			r0 = ret.Get(0).(*model.TimerInfo)
 		}
 	}
 
@@ -127,3 +150,26 @@ func (_m *PaceMaker) UpdateCurViewWithQC(qc *flow.QuorumCertificate) (*model.New
 
 	return r0, r1
 }
+
+// UpdateCurViewWithTC provides a mock function with given fields: tc
+func (_m *PaceMaker) UpdateCurViewWithTC(tc *flow.TimeoutCertificate) (*model.NewViewEvent, bool) {
+	ret := _m.Called(tc)
+
+	var r0 *model.NewViewEvent
+	if rf, ok := ret.Get(0).(func(*flow.TimeoutCertificate) *model.NewViewEvent); ok {
+		r0 = rf(tc)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.NewViewEvent)
+		}
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(*flow.TimeoutCertificate) bool); ok {
+		r1 = rf(tc)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
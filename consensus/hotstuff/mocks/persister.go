@@ -2,13 +2,39 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	flow "github.com/onflow/flow-go/model/flow"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // Persister is an autogenerated mock type for the Persister type
 type Persister struct {
 	mock.Mock
 }
 
+// GetHighestTC provides a mock function with given fields:
+func (_m *Persister) GetHighestTC() (*flow.TimeoutCertificate, error) {
+	ret := _m.Called()
+
+	var r0 *flow.TimeoutCertificate
+	if rf, ok := ret.Get(0).(func() *flow.TimeoutCertificate); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.TimeoutCertificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStarted provides a mock function with given fields:
 func (_m *Persister) GetStarted() (uint64, error) {
 	ret := _m.Called()
@@ -51,6 +77,20 @@ func (_m *Persister) GetVoted() (uint64, error) {
 	return r0, r1
 }
 
+// PutHighestTC provides a mock function with given fields: tc
+func (_m *Persister) PutHighestTC(tc *flow.TimeoutCertificate) error {
+	ret := _m.Called(tc)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*flow.TimeoutCertificate) error); ok {
+		r0 = rf(tc)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // PutStarted provides a mock function with given fields: view
 func (_m *Persister) PutStarted(view uint64) error {
 	ret := _m.Called(view)
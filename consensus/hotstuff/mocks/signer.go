@@ -61,6 +61,52 @@ func (_m *Signer) CreateQC(votes []*model.Vote) (*flow.QuorumCertificate, error)
 	return r0, r1
 }
 
+// CreateTC provides a mock function with given fields: timeouts
+func (_m *Signer) CreateTC(timeouts []*model.TimeoutObject) (*flow.TimeoutCertificate, error) {
+	ret := _m.Called(timeouts)
+
+	var r0 *flow.TimeoutCertificate
+	if rf, ok := ret.Get(0).(func([]*model.TimeoutObject) *flow.TimeoutCertificate); ok {
+		r0 = rf(timeouts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.TimeoutCertificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]*model.TimeoutObject) error); ok {
+		r1 = rf(timeouts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTimeout provides a mock function with given fields: view, highestQC, highestTC
+func (_m *Signer) CreateTimeout(view uint64, highestQC *flow.QuorumCertificate, highestTC *flow.TimeoutCertificate) (*model.TimeoutObject, error) {
+	ret := _m.Called(view, highestQC, highestTC)
+
+	var r0 *model.TimeoutObject
+	if rf, ok := ret.Get(0).(func(uint64, *flow.QuorumCertificate, *flow.TimeoutCertificate) *model.TimeoutObject); ok {
+		r0 = rf(view, highestQC, highestTC)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TimeoutObject)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64, *flow.QuorumCertificate, *flow.TimeoutCertificate) error); ok {
+		r1 = rf(view, highestQC, highestTC)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateVote provides a mock function with given fields: block
 func (_m *Signer) CreateVote(block *model.Block) (*model.Vote, error) {
 	ret := _m.Called(block)
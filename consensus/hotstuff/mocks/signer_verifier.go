@@ -61,6 +61,52 @@ func (_m *SignerVerifier) CreateQC(votes []*model.Vote) (*flow.QuorumCertificate
 	return r0, r1
 }
 
+// CreateTC provides a mock function with given fields: timeouts
+func (_m *SignerVerifier) CreateTC(timeouts []*model.TimeoutObject) (*flow.TimeoutCertificate, error) {
+	ret := _m.Called(timeouts)
+
+	var r0 *flow.TimeoutCertificate
+	if rf, ok := ret.Get(0).(func([]*model.TimeoutObject) *flow.TimeoutCertificate); ok {
+		r0 = rf(timeouts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.TimeoutCertificate)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]*model.TimeoutObject) error); ok {
+		r1 = rf(timeouts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTimeout provides a mock function with given fields: view, highestQC, highestTC
+func (_m *SignerVerifier) CreateTimeout(view uint64, highestQC *flow.QuorumCertificate, highestTC *flow.TimeoutCertificate) (*model.TimeoutObject, error) {
+	ret := _m.Called(view, highestQC, highestTC)
+
+	var r0 *model.TimeoutObject
+	if rf, ok := ret.Get(0).(func(uint64, *flow.QuorumCertificate, *flow.TimeoutCertificate) *model.TimeoutObject); ok {
+		r0 = rf(view, highestQC, highestTC)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TimeoutObject)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(uint64, *flow.QuorumCertificate, *flow.TimeoutCertificate) error); ok {
+		r1 = rf(view, highestQC, highestTC)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CreateVote provides a mock function with given fields: block
 func (_m *SignerVerifier) CreateVote(block *model.Block) (*model.Vote, error) {
 	ret := _m.Called(block)
@@ -105,6 +151,48 @@ func (_m *SignerVerifier) VerifyQC(voters flow.IdentityList, sigData []byte, blo
 	return r0, r1
 }
 
+// VerifyTC provides a mock function with given fields: signers, sigData, view
+func (_m *SignerVerifier) VerifyTC(signers flow.IdentityList, sigData []byte, view uint64) (bool, error) {
+	ret := _m.Called(signers, sigData, view)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(flow.IdentityList, []byte, uint64) bool); ok {
+		r0 = rf(signers, sigData, view)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(flow.IdentityList, []byte, uint64) error); ok {
+		r1 = rf(signers, sigData, view)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyTimeout provides a mock function with given fields: signer, sigData, view
+func (_m *SignerVerifier) VerifyTimeout(signer *flow.Identity, sigData []byte, view uint64) (bool, error) {
+	ret := _m.Called(signer, sigData, view)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*flow.Identity, []byte, uint64) bool); ok {
+		r0 = rf(signer, sigData, view)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*flow.Identity, []byte, uint64) error); ok {
+		r1 = rf(signer, sigData, view)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // VerifyVote provides a mock function with given fields: voter, sigData, block
 func (_m *SignerVerifier) VerifyVote(voter *flow.Identity, sigData []byte, block *model.Block) (bool, error) {
 	ret := _m.Called(voter, sigData, block)
@@ -43,6 +43,29 @@ func (_m *Validator) ValidateQC(qc *flow.QuorumCertificate, block *model.Block)
 	return r0
 }
 
+// ValidateTimeout provides a mock function with given fields: timeout
+func (_m *Validator) ValidateTimeout(timeout *model.TimeoutObject) (*flow.Identity, error) {
+	ret := _m.Called(timeout)
+
+	var r0 *flow.Identity
+	if rf, ok := ret.Get(0).(func(*model.TimeoutObject) *flow.Identity); ok {
+		r0 = rf(timeout)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.Identity)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*model.TimeoutObject) error); ok {
+		r1 = rf(timeout)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ValidateVote provides a mock function with given fields: vote, block
 func (_m *Validator) ValidateVote(vote *model.Vote, block *model.Block) (*flow.Identity, error) {
 	ret := _m.Called(vote, block)
@@ -36,6 +36,48 @@ func (_m *Verifier) VerifyQC(voters flow.IdentityList, sigData []byte, block *mo
 	return r0, r1
 }
 
+// VerifyTC provides a mock function with given fields: signers, sigData, view
+func (_m *Verifier) VerifyTC(signers flow.IdentityList, sigData []byte, view uint64) (bool, error) {
+	ret := _m.Called(signers, sigData, view)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(flow.IdentityList, []byte, uint64) bool); ok {
+		r0 = rf(signers, sigData, view)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(flow.IdentityList, []byte, uint64) error); ok {
+		r1 = rf(signers, sigData, view)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyTimeout provides a mock function with given fields: signer, sigData, view
+func (_m *Verifier) VerifyTimeout(signer *flow.Identity, sigData []byte, view uint64) (bool, error) {
+	ret := _m.Called(signer, sigData, view)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*flow.Identity, []byte, uint64) bool); ok {
+		r0 = rf(signer, sigData, view)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(*flow.Identity, []byte, uint64) error); ok {
+		r1 = rf(signer, sigData, view)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // VerifyVote provides a mock function with given fields: voter, sigData, block
 func (_m *Verifier) VerifyVote(voter *flow.Identity, sigData []byte, block *model.Block) (bool, error) {
 	ret := _m.Called(voter, sigData, block)
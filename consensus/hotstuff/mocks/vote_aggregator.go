@@ -85,6 +85,36 @@ func (_m *VoteAggregator) StoreProposerVote(vote *model.Vote) bool {
 	return r0
 }
 
+// StoreTimeoutAndBuildTC provides a mock function with given fields: timeout, curView
+func (_m *VoteAggregator) StoreTimeoutAndBuildTC(timeout *model.TimeoutObject, curView uint64) (*flow.TimeoutCertificate, bool, error) {
+	ret := _m.Called(timeout, curView)
+
+	var r0 *flow.TimeoutCertificate
+	if rf, ok := ret.Get(0).(func(*model.TimeoutObject, uint64) *flow.TimeoutCertificate); ok {
+		r0 = rf(timeout, curView)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.TimeoutCertificate)
+		}
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(*model.TimeoutObject, uint64) bool); ok {
+		r1 = rf(timeout, curView)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(*model.TimeoutObject, uint64) error); ok {
+		r2 = rf(timeout, curView)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // StoreVoteAndBuildQC provides a mock function with given fields: vote, block
 func (_m *VoteAggregator) StoreVoteAndBuildQC(vote *model.Vote, block *model.Block) (*flow.QuorumCertificate, bool, error) {
 	ret := _m.Called(vote, block)
@@ -114,3 +144,24 @@ func (_m *VoteAggregator) StoreVoteAndBuildQC(vote *model.Vote, block *model.Blo
 
 	return r0, r1, r2
 }
+
+// ViewToJoin provides a mock function with given fields: curView
+func (_m *VoteAggregator) ViewToJoin(curView uint64) (uint64, bool) {
+	ret := _m.Called(curView)
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(uint64) uint64); ok {
+		r0 = rf(curView)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(uint64) bool); ok {
+		r1 = rf(curView)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// TimeoutObject is the HotStuff algorithm's concept of a timeout for a view. A replica
+// broadcasts a timeout when its local timer for the view expires, to signal that it gave up
+// on the view. It includes the replica's highest known QC and TC, which allows replicas that
+// are lagging behind to synchronize their view.
+type TimeoutObject struct {
+	View      uint64
+	HighestQC *flow.QuorumCertificate
+	HighestTC *flow.TimeoutCertificate
+	SignerID  flow.Identifier
+	SigData   []byte
+}
+
+// ID returns the identifier for the timeout.
+func (t *TimeoutObject) ID() flow.Identifier {
+	return flow.MakeID(t)
+}
+
+// TimeoutFromFlow turns the timeout parameters into a timeout struct.
+func TimeoutFromFlow(signerID flow.Identifier, view uint64, highestQC *flow.QuorumCertificate, highestTC *flow.TimeoutCertificate, sig crypto.Signature) *TimeoutObject {
+	timeout := TimeoutObject{
+		View:      view,
+		HighestQC: highestQC,
+		HighestTC: highestTC,
+		SignerID:  signerID,
+		SigData:   sig,
+	}
+	return &timeout
+}
@@ -59,6 +59,15 @@ func (lc *LogConsumer) OnReceiveProposal(currentView uint64, proposal *model.Pro
 		Msg("processing proposal")
 }
 
+func (lc *LogConsumer) OnReceiveTimeout(currentView uint64, timeout *model.TimeoutObject) {
+	lc.log.Debug().
+		Uint64("cur_view", currentView).
+		Uint64("timeout_view", timeout.View).
+		Uint64("highest_qc_view", timeout.HighestQC.View).
+		Hex("signer_id", timeout.SignerID[:]).
+		Msg("processing timeout")
+}
+
 func (lc *LogConsumer) OnEnteringView(view uint64, leader flow.Identifier) {
 	lc.log.Debug().
 		Uint64("view", view).
@@ -74,6 +83,19 @@ func (lc *LogConsumer) OnQcTriggeredViewChange(qc *flow.QuorumCertificate, newVi
 		Msg("QC triggered view change")
 }
 
+func (lc *LogConsumer) OnTcTriggeredViewChange(tc *flow.TimeoutCertificate, newView uint64) {
+	lc.log.Debug().
+		Uint64("tc_view", tc.View).
+		Uint64("new_view", newView).
+		Msg("TC triggered view change")
+}
+
+func (lc *LogConsumer) OnTimeoutsTriggeredViewChange(newView uint64) {
+	lc.log.Debug().
+		Uint64("new_view", newView).
+		Msg("timeouts triggered view change")
+}
+
 func (lc *LogConsumer) OnProposingBlock(block *model.Proposal) {
 	lc.logBasicBlockData(lc.log.Debug(), block.Block).
 		Msg("proposing block")
@@ -93,6 +115,13 @@ func (lc *LogConsumer) OnQcConstructedFromVotes(qc *flow.QuorumCertificate) {
 		Msg("QC constructed from votes")
 }
 
+func (lc *LogConsumer) OnTcConstructedFromTimeouts(tc *flow.TimeoutCertificate) {
+	lc.log.Debug().
+		Uint64("tc_view", tc.View).
+		Uint64("highest_qc_view", tc.HighestQC.View).
+		Msg("TC constructed from timeouts")
+}
+
 func (lc *LogConsumer) OnStartingTimeout(info *model.TimerInfo) {
 	lc.log.Debug().
 		Uint64("timeout_view", info.View).
@@ -141,6 +170,13 @@ func (lc *LogConsumer) OnInvalidVoteDetected(vote *model.Vote) {
 		Msg("invalid vote detected")
 }
 
+func (lc *LogConsumer) OnInvalidTimeoutDetected(timeout *model.TimeoutObject) {
+	lc.log.Warn().
+		Uint64("timeout_view", timeout.View).
+		Hex("signer_id", timeout.SignerID[:]).
+		Msg("invalid timeout detected")
+}
+
 func (lc *LogConsumer) logBasicBlockData(loggerEvent *zerolog.Event, block *model.Block) *zerolog.Event {
 	loggerEvent.
 		Uint64("block_view", block.View).
@@ -51,6 +51,14 @@ func (p *Distributor) OnReceiveProposal(currentView uint64, proposal *model.Prop
 	}
 }
 
+func (p *Distributor) OnReceiveTimeout(currentView uint64, timeout *model.TimeoutObject) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	for _, subscriber := range p.subscribers {
+		subscriber.OnReceiveTimeout(currentView, timeout)
+	}
+}
+
 func (p *Distributor) OnEnteringView(view uint64, leader flow.Identifier) {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
@@ -67,6 +75,22 @@ func (p *Distributor) OnQcTriggeredViewChange(qc *flow.QuorumCertificate, newVie
 	}
 }
 
+func (p *Distributor) OnTcTriggeredViewChange(tc *flow.TimeoutCertificate, newView uint64) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	for _, subscriber := range p.subscribers {
+		subscriber.OnTcTriggeredViewChange(tc, newView)
+	}
+}
+
+func (p *Distributor) OnTimeoutsTriggeredViewChange(newView uint64) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	for _, subscriber := range p.subscribers {
+		subscriber.OnTimeoutsTriggeredViewChange(newView)
+	}
+}
+
 func (p *Distributor) OnProposingBlock(proposal *model.Proposal) {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
@@ -91,6 +115,14 @@ func (p *Distributor) OnQcConstructedFromVotes(qc *flow.QuorumCertificate) {
 	}
 }
 
+func (p *Distributor) OnTcConstructedFromTimeouts(tc *flow.TimeoutCertificate) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	for _, subscriber := range p.subscribers {
+		subscriber.OnTcConstructedFromTimeouts(tc)
+	}
+}
+
 func (p *Distributor) OnStartingTimeout(timerInfo *model.TimerInfo) {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
@@ -162,3 +194,11 @@ func (p *Distributor) OnInvalidVoteDetected(vote *model.Vote) {
 		subscriber.OnInvalidVoteDetected(vote)
 	}
 }
+
+func (p *Distributor) OnInvalidTimeoutDetected(timeout *model.TimeoutObject) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	for _, subscriber := range p.subscribers {
+		subscriber.OnInvalidTimeoutDetected(timeout)
+	}
+}
@@ -81,16 +81,24 @@ func (p *FinalizationDistributor) OnReceiveVote(uint64, *model.Vote) {}
 
 func (p *FinalizationDistributor) OnReceiveProposal(uint64, *model.Proposal) {}
 
+func (p *FinalizationDistributor) OnReceiveTimeout(uint64, *model.TimeoutObject) {}
+
 func (p *FinalizationDistributor) OnEnteringView(uint64, flow.Identifier) {}
 
 func (p *FinalizationDistributor) OnQcTriggeredViewChange(*flow.QuorumCertificate, uint64) {}
 
+func (p *FinalizationDistributor) OnTcTriggeredViewChange(*flow.TimeoutCertificate, uint64) {}
+
+func (p *FinalizationDistributor) OnTimeoutsTriggeredViewChange(uint64) {}
+
 func (p *FinalizationDistributor) OnProposingBlock(*model.Proposal) {}
 
 func (p *FinalizationDistributor) OnVoting(*model.Vote) {}
 
 func (p *FinalizationDistributor) OnQcConstructedFromVotes(*flow.QuorumCertificate) {}
 
+func (p *FinalizationDistributor) OnTcConstructedFromTimeouts(*flow.TimeoutCertificate) {}
+
 func (p *FinalizationDistributor) OnStartingTimeout(*model.TimerInfo) {}
 
 func (p *FinalizationDistributor) OnReachedTimeout(*model.TimerInfo) {}
@@ -102,3 +110,5 @@ func (p *FinalizationDistributor) OnForkChoiceGenerated(uint64, *flow.QuorumCert
 func (p *FinalizationDistributor) OnDoubleVotingDetected(*model.Vote, *model.Vote) {}
 
 func (p *FinalizationDistributor) OnInvalidVoteDetected(*model.Vote) {}
+
+func (p *FinalizationDistributor) OnInvalidTimeoutDetected(*model.TimeoutObject) {}
@@ -4,18 +4,33 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
 )
 
 // SlashingViolationsConsumer is an implementation of the notifications consumer that logs a
-// message for any slashable offences.
+// message for any slashable offences, and persists the evidence of the offence.
+// The evidence contains the conflicting votes or proposals with their signatures,
+// as well as the identity of the offender, so it can be verified independently.
+// It is used by the main consensus as well as by the collection clusters, the
+// evidence records the chain of the HotStuff instance.
 type SlashingViolationsConsumer struct {
 	NoopConsumer
-	log zerolog.Logger
+	log      zerolog.Logger
+	chainID  flow.ChainID
+	state    protocol.State
+	headers  storage.Headers
+	evidence storage.SlashingEvidence
 }
 
-func NewSlashingViolationsConsumer(log zerolog.Logger) *SlashingViolationsConsumer {
+func NewSlashingViolationsConsumer(log zerolog.Logger, chainID flow.ChainID, state protocol.State, headers storage.Headers, evidence storage.SlashingEvidence) *SlashingViolationsConsumer {
 	return &SlashingViolationsConsumer{
-		log: log,
+		log:      log,
+		chainID:  chainID,
+		state:    state,
+		headers:  headers,
+		evidence: evidence,
 	}
 }
 
@@ -26,6 +41,15 @@ func (c *SlashingViolationsConsumer) OnDoubleVotingDetected(vote1 *model.Vote, v
 		Hex("voted_block_id1", vote1.BlockID[:]).
 		Hex("voted_block_id2", vote2.BlockID[:]).
 		Msg("OnDoubleVotingDetected")
+
+	c.store(&flow.SlashingEvidence{
+		Violation:  flow.SlashingViolationDoubleVote,
+		ChainID:    c.chainID,
+		View:       vote1.View,
+		OffenderID: vote1.SignerID,
+		Offender:   c.offender(vote1.SignerID, vote1.BlockID, vote2.BlockID),
+		Votes:      []*flow.SlashingVote{slashingVote(vote1), slashingVote(vote2)},
+	})
 }
 
 func (c *SlashingViolationsConsumer) OnInvalidVoteDetected(vote *model.Vote) {
@@ -34,6 +58,15 @@ func (c *SlashingViolationsConsumer) OnInvalidVoteDetected(vote *model.Vote) {
 		Hex("voted_block_id", vote.BlockID[:]).
 		Hex("voter_id", vote.SignerID[:]).
 		Msg("OnInvalidVoteDetected")
+
+	c.store(&flow.SlashingEvidence{
+		Violation:  flow.SlashingViolationInvalidVote,
+		ChainID:    c.chainID,
+		View:       vote.View,
+		OffenderID: vote.SignerID,
+		Offender:   c.offender(vote.SignerID, vote.BlockID),
+		Votes:      []*flow.SlashingVote{slashingVote(vote)},
+	})
 }
 
 func (c *SlashingViolationsConsumer) OnDoubleProposeDetected(block1 *model.Block, block2 *model.Block) {
@@ -43,4 +76,79 @@ func (c *SlashingViolationsConsumer) OnDoubleProposeDetected(block1 *model.Block
 		Hex("block_id1", block1.BlockID[:]).
 		Hex("block_id2", block2.BlockID[:]).
 		Msg("OnDoubleProposeDetected")
+
+	// the proposer signatures are only part of the full headers
+	var proposals []*flow.Header
+	for _, block := range []*model.Block{block1, block2} {
+		header, err := c.headers.ByBlockID(block.BlockID)
+		if err != nil {
+			c.log.Error().Err(err).
+				Hex("block_id", block.BlockID[:]).
+				Msg("could not retrieve double proposal for slashing evidence")
+			continue
+		}
+		proposals = append(proposals, header)
+	}
+
+	c.store(&flow.SlashingEvidence{
+		Violation:  flow.SlashingViolationDoubleProposal,
+		ChainID:    c.chainID,
+		View:       block1.View,
+		OffenderID: block1.ProposerID,
+		Offender:   c.offender(block1.ProposerID, block1.BlockID, block2.BlockID),
+		Proposals:  proposals,
+	})
+}
+
+// store persists the evidence. Only the first evidence of a violation by an
+// offender in a view is kept.
+func (c *SlashingViolationsConsumer) store(evidence *flow.SlashingEvidence) {
+	log := c.log.With().
+		Str("violation", string(evidence.Violation)).
+		Str("chain_id", evidence.ChainID.String()).
+		Uint64("view", evidence.View).
+		Hex("offender_id", evidence.OffenderID[:]).
+		Logger()
+
+	stored, err := c.evidence.Store(evidence)
+	if err != nil {
+		log.Error().Err(err).Msg("could not store slashing evidence")
+		return
+	}
+	if !stored {
+		log.Debug().Msg("slashing evidence already stored")
+		return
+	}
+	log.Info().Msg("slashing evidence stored")
+}
+
+// offender looks up the identity of the offender as of the first of the given
+// blocks which is known, falling back to the latest finalized state. Votes can
+// be for unknown blocks, in which case the offender might not be found. Blocks
+// of cluster chains are not known to the protocol state, the identities of
+// collectors are hence looked up in the latest finalized state.
+func (c *SlashingViolationsConsumer) offender(offenderID flow.Identifier, blockIDs ...flow.Identifier) *flow.Identity {
+	for _, blockID := range blockIDs {
+		identity, err := c.state.AtBlockID(blockID).Identity(offenderID)
+		if err == nil {
+			return identity
+		}
+	}
+	identity, err := c.state.Final().Identity(offenderID)
+	if err != nil {
+		c.log.Warn().Err(err).
+			Hex("offender_id", offenderID[:]).
+			Msg("could not find identity of offender for slashing evidence")
+		return nil
+	}
+	return identity
+}
+
+func slashingVote(vote *model.Vote) *flow.SlashingVote {
+	return &flow.SlashingVote{
+		View:     vote.View,
+		BlockID:  vote.BlockID,
+		SignerID: vote.SignerID,
+		SigData:  vote.SigData,
+	}
 }
@@ -0,0 +1,117 @@
+package notifications
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/helper"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	mockstorage "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// consumerFixture returns a consumer for which the given offender is known at
+// the given block, and which captures the evidence it stores.
+func consumerFixture(offender *flow.Identity, knownBlockID flow.Identifier) (*SlashingViolationsConsumer, *mockstorage.Headers, *[]*flow.SlashingEvidence) {
+	state := new(protocol.State)
+	known := new(protocol.Snapshot)
+	known.On("Identity", offender.NodeID).Return(offender, nil)
+	unknown := new(protocol.Snapshot)
+	unknown.On("Identity", mock.Anything).Return(nil, errors.New("unknown block"))
+	state.On("AtBlockID", knownBlockID).Return(known)
+	state.On("AtBlockID", mock.Anything).Return(unknown)
+	state.On("Final").Return(unknown)
+
+	headers := new(mockstorage.Headers)
+
+	var stored []*flow.SlashingEvidence
+	evidence := new(mockstorage.SlashingEvidence)
+	evidence.On("Store", mock.Anything).Return(
+		func(e *flow.SlashingEvidence) bool {
+			stored = append(stored, e)
+			return true
+		},
+		nil,
+	)
+
+	return NewSlashingViolationsConsumer(zerolog.Nop(), flow.Emulator, state, headers, evidence), headers, &stored
+}
+
+func voteFixture(view uint64, signerID flow.Identifier) *model.Vote {
+	return &model.Vote{
+		View:     view,
+		BlockID:  unittest.IdentifierFixture(),
+		SignerID: signerID,
+		SigData:  unittest.SignatureFixture(),
+	}
+}
+
+func TestSlashingViolationsConsumer_DoubleVote(t *testing.T) {
+	offender := unittest.IdentityFixture(unittest.WithRole(flow.RoleConsensus))
+	vote1 := voteFixture(10, offender.NodeID)
+	vote2 := voteFixture(10, offender.NodeID)
+
+	// the offender is looked up at the second voted block, as the first is unknown
+	consumer, _, stored := consumerFixture(offender, vote2.BlockID)
+	consumer.OnDoubleVotingDetected(vote1, vote2)
+
+	require.Len(t, *stored, 1)
+	evidence := (*stored)[0]
+	assert.Equal(t, flow.SlashingViolationDoubleVote, evidence.Violation)
+	assert.Equal(t, flow.Emulator, evidence.ChainID)
+	assert.Equal(t, uint64(10), evidence.View)
+	assert.Equal(t, offender.NodeID, evidence.OffenderID)
+	assert.Equal(t, offender, evidence.Offender)
+	require.Len(t, evidence.Votes, 2)
+	for i, vote := range []*model.Vote{vote1, vote2} {
+		assert.Equal(t, vote.BlockID, evidence.Votes[i].BlockID)
+		assert.Equal(t, vote.SigData, evidence.Votes[i].SigData)
+	}
+}
+
+func TestSlashingViolationsConsumer_InvalidVote(t *testing.T) {
+	offender := unittest.IdentityFixture(unittest.WithRole(flow.RoleConsensus))
+	vote := voteFixture(10, offender.NodeID)
+
+	// votes for unknown blocks are stored without the identity of the offender
+	consumer, _, stored := consumerFixture(offender, unittest.IdentifierFixture())
+	consumer.OnInvalidVoteDetected(vote)
+
+	require.Len(t, *stored, 1)
+	evidence := (*stored)[0]
+	assert.Equal(t, flow.SlashingViolationInvalidVote, evidence.Violation)
+	assert.Equal(t, offender.NodeID, evidence.OffenderID)
+	assert.Nil(t, evidence.Offender)
+	require.Len(t, evidence.Votes, 1)
+	assert.Equal(t, vote.SigData, evidence.Votes[0].SigData)
+}
+
+func TestSlashingViolationsConsumer_DoubleProposal(t *testing.T) {
+	offender := unittest.IdentityFixture(unittest.WithRole(flow.RoleConsensus))
+	block1 := helper.MakeBlock(t, helper.WithBlockView(10), helper.WithBlockProposer(offender.NodeID))
+	block2 := helper.MakeBlock(t, helper.WithBlockView(10), helper.WithBlockProposer(offender.NodeID))
+
+	consumer, headers, stored := consumerFixture(offender, block1.BlockID)
+
+	// only the known proposals are part of the evidence
+	header := unittest.BlockHeaderFixture()
+	headers.On("ByBlockID", block1.BlockID).Return(&header, nil)
+	headers.On("ByBlockID", block2.BlockID).Return(nil, storage.ErrNotFound)
+	consumer.OnDoubleProposeDetected(block1, block2)
+
+	require.Len(t, *stored, 1)
+	evidence := (*stored)[0]
+	assert.Equal(t, flow.SlashingViolationDoubleProposal, evidence.Violation)
+	assert.Equal(t, uint64(10), evidence.View)
+	assert.Equal(t, offender, evidence.Offender)
+	assert.Equal(t, []*flow.Header{&header}, evidence.Proposals)
+	assert.Empty(t, evidence.Votes)
+}
@@ -62,6 +62,15 @@ func (t *TelemetryConsumer) OnReceiveProposal(currentView uint64, proposal *mode
 	step.Msg("OnReceiveProposal")
 }
 
+func (t *TelemetryConsumer) OnReceiveTimeout(currentView uint64, timeout *model.TimeoutObject) {
+	t.pathHandler.StartNextPath(currentView)
+	t.pathHandler.NextStep().
+		Uint64("timeout_view", timeout.View).
+		Uint64("highest_qc_view", timeout.HighestQC.View).
+		Hex("signer_id", timeout.SignerID[:]).
+		Msg("OnReceiveTimeout")
+}
+
 func (t *TelemetryConsumer) OnEventProcessed() {
 	if t.pathHandler.IsCurrentPathClosed() {
 		return
@@ -73,7 +82,8 @@ func (t *TelemetryConsumer) OnEventProcessed() {
 
 func (t *TelemetryConsumer) OnStartingTimeout(info *model.TimerInfo) {
 	if info.Mode == model.ReplicaTimeout {
-		// the PaceMarker starts a new ReplicaTimeout if and only if it transitions to a higher view
+		// the PaceMarker starts a new ReplicaTimeout if and only if it transitions to a higher view,
+		// or if it restarts the timeout for the current view after reaching it
 		t.pathHandler.StartNextPath(info.View)
 	}
 	t.pathHandler.NextStep().
@@ -119,6 +129,19 @@ func (t *TelemetryConsumer) OnQcTriggeredViewChange(qc *flow.QuorumCertificate,
 		Msg("OnQcTriggeredViewChange")
 }
 
+func (t *TelemetryConsumer) OnTcTriggeredViewChange(tc *flow.TimeoutCertificate, newView uint64) {
+	t.pathHandler.NextStep().
+		Uint64("tc_view", tc.View).
+		Uint64("next_view", newView).
+		Msg("OnTcTriggeredViewChange")
+}
+
+func (t *TelemetryConsumer) OnTimeoutsTriggeredViewChange(newView uint64) {
+	t.pathHandler.NextStep().
+		Uint64("next_view", newView).
+		Msg("OnTimeoutsTriggeredViewChange")
+}
+
 func (t *TelemetryConsumer) OnProposingBlock(proposal *model.Proposal) {
 	block := proposal.Block
 	step := t.pathHandler.NextStep()
@@ -158,6 +181,13 @@ func (t *TelemetryConsumer) OnQcConstructedFromVotes(qc *flow.QuorumCertificate)
 		Msg("OnQcConstructedFromVotes")
 }
 
+func (t *TelemetryConsumer) OnTcConstructedFromTimeouts(tc *flow.TimeoutCertificate) {
+	t.pathHandler.NextStep().
+		Uint64("tc_view", tc.View).
+		Uint64("highest_qc_view", tc.HighestQC.View).
+		Msg("OnTcConstructedFromTimeouts")
+}
+
 func (t *TelemetryConsumer) OnQcIncorporated(qc *flow.QuorumCertificate) {
 	t.pathHandler.NextStep().
 		Uint64("qc_block_view", qc.View).
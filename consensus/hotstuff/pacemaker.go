@@ -44,6 +44,15 @@ type PaceMaker interface {
 	// forward to QC.view+1. If PaceMaker incremented the current View, a NewViewEvent will be returned.
 	UpdateCurViewWithQC(qc *flow.QuorumCertificate) (*model.NewViewEvent, bool)
 
+	// UpdateCurViewWithTC will check if the given TC will allow PaceMaker to fast
+	// forward to TC.view+1. If PaceMaker incremented the current View, a NewViewEvent will be returned.
+	UpdateCurViewWithTC(tc *flow.TimeoutCertificate) (*model.NewViewEvent, bool)
+
+	// JoinView will check if PaceMaker can fast forward to the given view, which replicas with more
+	// than a third of the stake have timed out in. If PaceMaker incremented the current View, a
+	// NewViewEvent will be returned.
+	JoinView(view uint64) (*model.NewViewEvent, bool)
+
 	// UpdateCurViewWithBlock will check if the given block will allow PaceMaker to fast forward
 	// to the BlockProposal's view. If yes, the PaceMaker will update it's internal value for
 	// CurView and return a NewViewEvent.
@@ -58,11 +67,15 @@ type PaceMaker interface {
 	TimeoutChannel() <-chan time.Time
 
 	// OnTimeout is called when a timeout, which was previously created by the PaceMaker, has
-	// looped through the event loop. When used correctly, OnTimeout will always return
-	// a NewViewEvent.
+	// looped through the event loop. It returns the information of the timeout that was reached.
+	// Once timeout certificates are active, the PaceMaker does NOT leave the current view on a
+	// replica timeout. Instead, it restarts the timeout for the current view, such that the
+	// replica keeps broadcasting its timeout until it observes a QC or TC, which allows it to
+	// move to a higher view. On a vote collection timeout, and on a replica timeout before timeout
+	// certificates are active, the PaceMaker moves to the next view.
 	// It is the responsibility of the calling code to ensure that NO STALE timeouts are
 	// delivered to the PaceMaker.
-	OnTimeout() *model.NewViewEvent
+	OnTimeout() *model.TimerInfo
 
 	// Start starts the PaceMaker (i.e. the timeout for the configured starting value for view).
 	Start()
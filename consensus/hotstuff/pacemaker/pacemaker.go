@@ -18,6 +18,7 @@ import (
 // for which the replica knows a QC with V = QC.view + 1
 type NitroPaceMaker struct {
 	currentView    uint64
+	activationView uint64
 	timeoutControl *timeout.Controller
 	notifier       hotstuff.Consumer
 	started        *atomic.Bool
@@ -25,14 +26,18 @@ type NitroPaceMaker struct {
 
 // New creates a new NitroPaceMaker instance
 // startView is the view for the pacemaker to start from
+// activationView is the first view, in which replicas leave the view only on a QC or TC. In
+// lower views, replicas move to the next view on their local timeout, like replicas that don't
+// support timeout certificates do. All replicas must be configured with the same activation view.
 // timeoutController controls the timeout trigger.
 // notifier provides callbacks for pacemaker events.
-func New(startView uint64, timeoutController *timeout.Controller, notifier hotstuff.Consumer) (*NitroPaceMaker, error) {
+func New(startView uint64, activationView uint64, timeoutController *timeout.Controller, notifier hotstuff.Consumer) (*NitroPaceMaker, error) {
 	if startView < 1 {
 		return nil, &model.ConfigurationError{Msg: "Please start PaceMaker with view > 0. (View 0 is reserved for genesis block, which has no proposer)"}
 	}
 	pm := NitroPaceMaker{
 		currentView:    startView,
+		activationView: activationView,
 		timeoutControl: timeoutController,
 		notifier:       notifier,
 		started:        atomic.NewBool(false),
@@ -81,6 +86,7 @@ func (p *NitroPaceMaker) UpdateCurViewWithQC(qc *flow.QuorumCertificate) (*model
 	// 2/3 of replicas have already voted for round p.currentView + k, hence proceeded past currentView
 	// => 2/3 of replicas are at least in view qc.view + 1.
 	// => replica can skip ahead to view qc.view + 1
+	p.timeoutControl.OnQCReceived(qc.View)
 	p.timeoutControl.OnProgressBeforeTimeout()
 
 	newView := qc.View + 1
@@ -97,6 +103,7 @@ func (p *NitroPaceMaker) UpdateCurViewWithBlock(block *model.Block, isLeaderForN
 		return newViewOnQc, newViewOccurredOnQc
 	}
 	// block is for current view
+	p.timeoutControl.OnProposalReceived(block.View)
 
 	if p.timeoutControl.TimerInfo().Mode != model.ReplicaTimeout {
 		// i.e. we are already on timeout.VoteCollectionTimeout.
@@ -130,12 +137,56 @@ func (p *NitroPaceMaker) actOnBlockForCurView(block *model.Block, isLeaderForNex
 	return p.gotoView(p.currentView + 1), true
 }
 
+// UpdateCurViewWithTC notifies the pacemaker with a new TC, which might allow pacemaker to
+// fast forward its view.
+func (p *NitroPaceMaker) UpdateCurViewWithTC(tc *flow.TimeoutCertificate) (*model.NewViewEvent, bool) {
+	if tc.View < p.currentView {
+		return nil, false
+	}
+	// tc.view = p.currentView + k for k ≥ 0
+	// 2/3 of replicas have already timed out in round p.currentView + k, and will only leave
+	// this view once they observed a QC or TC for it. Hence, the replica can skip ahead to
+	// view tc.view + 1. Note that a TC does not constitute progress: the committee gave up on
+	// the view, which is why we don't decrease the timeout here.
+	newView := tc.View + 1
+	p.notifier.OnTcTriggeredViewChange(tc, newView)
+	return p.gotoView(newView), true
+}
+
+// JoinView moves the pacemaker to the given view, in case it is higher than the current view.
+// The caller must ensure that replicas with more than a third of the stake have timed out in the
+// view or higher views.
+func (p *NitroPaceMaker) JoinView(view uint64) (*model.NewViewEvent, bool) {
+	if view <= p.currentView {
+		return nil, false
+	}
+	// At least one honest replica has reached the view. As replicas only leave a view once they
+	// observed a QC or TC for it, the replica would otherwise wait in its view forever, when the
+	// replicas have drifted apart, e.g. after a network partition. Joining the view allows the
+	// replicas to build a TC for it. Like a TC, this does not constitute progress.
+	p.notifier.OnTimeoutsTriggeredViewChange(view)
+	return p.gotoView(view), true
+}
+
 // OnTimeout notifies the pacemaker that the timeout event has looped through the event loop.
-// It always trigger a view change, and the new view will be returned as NewViewEvent
-func (p *NitroPaceMaker) OnTimeout() *model.NewViewEvent {
-	p.emitTimeoutNotifications(p.timeoutControl.TimerInfo())
+// It returns the information of the timeout that was reached:
+//   - on a replica timeout, the pacemaker stays in the current view and restarts the
+//     timeout, as the replica can only leave the view once it observes a QC or TC for it
+//   - on a replica timeout in a view below the activation view, the pacemaker moves to the
+//     next view, as replicas without support for timeout certificates would never build a TC
+//   - on a vote collection timeout, the leader gives up on collecting votes and moves to
+//     the next view, like all replicas do that voted for the block of the current view
+func (p *NitroPaceMaker) OnTimeout() *model.TimerInfo {
+	timerInfo := p.timeoutControl.TimerInfo()
+	p.emitTimeoutNotifications(timerInfo)
 	p.timeoutControl.OnTimeout()
-	return p.gotoView(p.currentView + 1)
+	if timerInfo.Mode == model.VoteCollectionTimeout || p.currentView < p.activationView {
+		p.gotoView(p.currentView + 1)
+		return timerInfo
+	}
+	restarted := p.timeoutControl.StartTimeout(model.ReplicaTimeout, p.currentView)
+	p.notifier.OnStartingTimeout(restarted)
+	return timerInfo
 }
 
 func (p *NitroPaceMaker) emitTimeoutNotifications(timeout *model.TimerInfo) {
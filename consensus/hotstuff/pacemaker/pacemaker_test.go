@@ -51,7 +51,7 @@ func initPaceMaker(t *testing.T, view uint64) (hotstuff.PaceMaker, *mocks.Consum
 	if err != nil {
 		t.Fail()
 	}
-	pm, err := New(view, timeout.NewController(tc), notifier)
+	pm, err := New(view, 0, timeout.NewController(tc), notifier)
 	if err != nil {
 		t.Fail()
 	}
@@ -64,6 +64,10 @@ func QC(view uint64) *flow.QuorumCertificate {
 	return &flow.QuorumCertificate{View: view}
 }
 
+func TC(view uint64) *flow.TimeoutCertificate {
+	return &flow.TimeoutCertificate{View: view, HighestQC: QC(view - 1)}
+}
+
 func makeBlock(qcView, blockView uint64) *model.Block {
 	return &model.Block{View: blockView, QC: QC(qcView)}
 }
@@ -305,15 +309,133 @@ func Test_ReplicaTimeout(t *testing.T) {
 	assert.Equal(t, uint64(3), pm.CurView())
 
 	// here the, the Event loop would now call EventHandler.OnTimeout() -> PaceMaker.OnTimeout()
+	// the PaceMaker should stay in the current view and restart the timeout
+	notifier.On("OnReachedTimeout", expectedTimeoutInfo(3, model.ReplicaTimeout)).Return().Once()
+	notifier.On("OnStartingTimeout", expectedTimerInfo(3, model.ReplicaTimeout)).Return().Once()
+	timerInfo := pm.OnTimeout()
+	require.NotNil(t, timerInfo)
+	assert.Equal(t, uint64(3), timerInfo.View)
+	assert.Equal(t, model.ReplicaTimeout, timerInfo.Mode)
+
+	notifier.AssertExpectations(t)
+	assert.Equal(t, uint64(3), pm.CurView())
+}
+
+// Test_ReplicaTimeoutBeforeActivation tests that the PaceMaker moves to the next view on a replica
+// timeout in views below the activation view of timeout certificates
+func Test_ReplicaTimeoutBeforeActivation(t *testing.T) {
+	notifier := &mocks.Consumer{}
+	tc, err := timeout.NewConfig(
+		time.Duration(startRepTimeout*1e6),
+		time.Duration(minRepTimeout*1e6),
+		voteTimeoutFraction,
+		multiplicativeIncrease,
+		multiplicativeDecrease,
+		0)
+	require.NoError(t, err)
+	pm, err := New(3, 4, timeout.NewController(tc), notifier)
+	require.NoError(t, err)
+	notifier.On("OnStartingTimeout", expectedTimerInfo(3, model.ReplicaTimeout)).Return().Once()
+	pm.Start()
+
 	notifier.On("OnReachedTimeout", expectedTimeoutInfo(3, model.ReplicaTimeout)).Return().Once()
 	notifier.On("OnStartingTimeout", expectedTimerInfo(4, model.ReplicaTimeout)).Return().Once()
-	viewChange := pm.OnTimeout()
-	if viewChange == nil {
-		assert.Fail(t, "Expecting ViewChange event as result of timeout")
-	}
+	timerInfo := pm.OnTimeout()
+	assert.Equal(t, uint64(3), timerInfo.View)
+	assert.Equal(t, uint64(4), pm.CurView())
 
+	// the replica stays in the activation view until it observes a QC or TC
+	notifier.On("OnReachedTimeout", expectedTimeoutInfo(4, model.ReplicaTimeout)).Return().Once()
+	notifier.On("OnStartingTimeout", expectedTimerInfo(4, model.ReplicaTimeout)).Return().Once()
+	timerInfo = pm.OnTimeout()
+	assert.Equal(t, uint64(4), timerInfo.View)
+	assert.Equal(t, uint64(4), pm.CurView())
 	notifier.AssertExpectations(t)
+}
+
+// Test_SkipIncreaseViewThroughTC tests that PaceMaker increases View when receiving TC,
+// if applicable, by skipping views
+func Test_SkipIncreaseViewThroughTC(t *testing.T) {
+	pm, notifier := initPaceMaker(t, 3)
+
+	tc := TC(3)
+	notifier.On("OnStartingTimeout", expectedTimerInfo(4, model.ReplicaTimeout)).Return().Once()
+	notifier.On("OnTcTriggeredViewChange", tc, uint64(4)).Return().Once()
+	nve, nveOccurred := pm.UpdateCurViewWithTC(tc)
+	notifier.AssertExpectations(t)
+	assert.True(t, nveOccurred)
+	assert.Equal(t, uint64(4), nve.View)
 	assert.Equal(t, uint64(4), pm.CurView())
+
+	tc = TC(12)
+	notifier.On("OnStartingTimeout", expectedTimerInfo(13, model.ReplicaTimeout)).Return().Once()
+	notifier.On("OnTcTriggeredViewChange", tc, uint64(13)).Return().Once()
+	nve, nveOccurred = pm.UpdateCurViewWithTC(tc)
+	notifier.AssertExpectations(t)
+	assert.True(t, nveOccurred)
+	assert.Equal(t, uint64(13), nve.View)
+	assert.Equal(t, uint64(13), pm.CurView())
+}
+
+// Test_IgnoreOldTC tests that PaceMaker ignores old TC and doesn't advance
+func Test_IgnoreOldTC(t *testing.T) {
+	pm, notifier := initPaceMaker(t, 3)
+	nve, nveOccurred := pm.UpdateCurViewWithTC(TC(2))
+	assert.False(t, nveOccurred)
+	assert.Nil(t, nve)
+	notifier.AssertExpectations(t)
+	assert.Equal(t, uint64(3), pm.CurView())
+}
+
+// Test_JoinView tests that PaceMaker joins a higher view, which replicas have timed out in
+func Test_JoinView(t *testing.T) {
+	pm, notifier := initPaceMaker(t, 3)
+
+	notifier.On("OnStartingTimeout", expectedTimerInfo(7, model.ReplicaTimeout)).Return().Once()
+	notifier.On("OnTimeoutsTriggeredViewChange", uint64(7)).Return().Once()
+	nve, nveOccurred := pm.JoinView(7)
+	notifier.AssertExpectations(t)
+	assert.True(t, nveOccurred)
+	assert.Equal(t, uint64(7), nve.View)
+	assert.Equal(t, uint64(7), pm.CurView())
+}
+
+// Test_IgnoreJoiningLowerView tests that PaceMaker doesn't join the current or a lower view
+func Test_IgnoreJoiningLowerView(t *testing.T) {
+	pm, notifier := initPaceMaker(t, 3)
+	nve, nveOccurred := pm.JoinView(3)
+	assert.False(t, nveOccurred)
+	assert.Nil(t, nve)
+	nve, nveOccurred = pm.JoinView(2)
+	assert.False(t, nveOccurred)
+	assert.Nil(t, nve)
+	notifier.AssertExpectations(t)
+	assert.Equal(t, uint64(3), pm.CurView())
+}
+
+// Test_ViewChangeThroughTCWithoutProgress tests that a view change through a TC does not
+// constitute progress, i.e. that the PaceMaker does NOT decrease the timeout.
+func Test_ViewChangeThroughTCWithoutProgress(t *testing.T) {
+	pm, notifier := initPaceMaker(t, 5) // initPaceMaker also calls Start() on PaceMaker
+
+	notifier.On("OnStartingTimeout", expectedTimerInfo(6, model.ReplicaTimeout)).Return().Once()
+	notifier.On("OnTcTriggeredViewChange", mock.Anything, uint64(6)).Return().Once()
+	start := time.Now()
+	nve, nveOccurred := pm.UpdateCurViewWithTC(TC(5))
+	assert.True(t, nveOccurred && nve.View == 6)
+	notifier.AssertExpectations(t)
+
+	select {
+	case <-pm.TimeoutChannel():
+		break // testing path: corresponds to EventLoop picking up timeout from channel
+	case <-time.After(time.Duration(2) * time.Duration(startRepTimeout) * time.Millisecond):
+		t.Fail() // to prevent test from hanging
+	}
+
+	actualTimeout := float64(time.Since(start).Milliseconds()) // in millisecond
+	expectedReplicaTimeout := startRepTimeout
+	assert.True(t, math.Abs(actualTimeout-expectedReplicaTimeout) < 0.1*expectedReplicaTimeout)
+	assert.Equal(t, uint64(6), pm.CurView())
 }
 
 // Test_ViewChangeWithProgress tests that the PaceMaker respects the definition of Progress:
@@ -392,7 +514,7 @@ func Test_ReplicaTimeoutAgain(t *testing.T) {
 	// reset timer
 	start = time.Now()
 
-	// trigger view change and restart the pacemaker timer. The next timeout should take 1.5 longer
+	// restart the pacemaker timer for the current view. The next timeout should take 1.5 longer
 	_ = pm.OnTimeout()
 
 	// wait until the timeout is hit again
@@ -401,7 +523,8 @@ func Test_ReplicaTimeoutAgain(t *testing.T) {
 	case <-time.After(time.Duration(3) * time.Duration(startRepTimeout) * time.Millisecond):
 	}
 
-	nv := pm.OnTimeout()
+	_ = pm.OnTimeout()
+	nv := &model.NewViewEvent{View: pm.CurView()}
 
 	// calculate the actual timeout duration that has been waited again
 	actualTimeout = float64(time.Since(start).Milliseconds()) // in millisecond
@@ -477,10 +600,9 @@ func Test_VoteTimeout(t *testing.T) {
 	// here the, the Event loop would now call EventHandler.OnTimeout() -> PaceMaker.OnTimeout()
 	notifier.On("OnReachedTimeout", expectedTimeoutInfo(3, model.VoteCollectionTimeout)).Return().Once()
 	notifier.On("OnStartingTimeout", expectedTimerInfo(4, model.ReplicaTimeout)).Return().Once()
-	viewChange := pm.OnTimeout()
-	if viewChange == nil {
-		assert.Fail(t, "Expecting ViewChange event as result of timeout")
-	}
+	timerInfo := pm.OnTimeout()
+	require.NotNil(t, timerInfo)
+	assert.Equal(t, model.VoteCollectionTimeout, timerInfo.Mode)
 	notifier.AssertExpectations(t)
 	assert.Equal(t, uint64(4), pm.CurView())
 }
@@ -0,0 +1,211 @@
+package timeout
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+)
+
+// AdaptiveConfig contains the configuration parameters for the adaptive
+// timeout.Controller, which derives the replica timeout from the observed
+// network latency instead of static factors:
+// - the base timeout is a multiple of a percentile of the recently observed
+//   latencies from the start of a view until receiving its proposal, and
+//   until observing its QC, bounded by the minimum and maximum replica timeout
+// - on timeout: increase timeout by the multiplicative factor `TimeoutIncrease`
+//   of the timeout Config, such that the committee re-synchronizes even if the
+//   latency suddenly increases
+// - on progress: undo one timeout increase, such that the timeout returns to
+//   the base timeout right after a failed leader
+type AdaptiveConfig struct {
+	// Percentile of the observed latencies the base timeout is derived from, in (0,1]
+	Percentile float64
+	// Multiplier applied to the latency percentile, to tolerate variations of the latency
+	Multiplier float64
+	// WindowSize is the number of most recent latency observations taken into account
+	WindowSize uint
+	// MinSamples is the number of latency observations required before the timeout adapts;
+	// until then, the static factors of the timeout Config are used
+	MinSamples uint
+	// MinReplicaTimeout is the lower safety bound of the adaptive timeout [MILLISECONDS]
+	MinReplicaTimeout float64
+	// MaxReplicaTimeout is the upper safety bound of the adaptive timeout [MILLISECONDS]
+	MaxReplicaTimeout float64
+}
+
+var DefaultAdaptiveConfig = NewDefaultAdaptiveConfig()
+
+// NewDefaultAdaptiveConfig returns a default adaptive timeout configuration.
+func NewDefaultAdaptiveConfig() AdaptiveConfig {
+	// we wait for three times the 90th percentile of the latency, which leaves
+	// enough buffer for occasional slow proposals without timing out
+	percentile := 0.9
+	multiplier := 3.0
+
+	// we take the latencies of the last 100 views into account, and
+	// require a few views to be observed before we trust the estimate
+	windowSize := uint(100)
+	minSamples := uint(10)
+
+	// the adaptive timeout never drops below 500ms, which protects against
+	// timing out on a temporarily very fast network, and never exceeds one
+	// minute, such that the committee re-synchronizes in reasonable time
+	minReplicaTimeout := 500 * time.Millisecond
+	maxReplicaTimeout := 60 * time.Second
+
+	conf, err := NewAdaptiveConfig(percentile, multiplier, windowSize, minSamples, minReplicaTimeout, maxReplicaTimeout)
+	if err != nil {
+		// we check in a unit test that this does not happen
+		panic("Default adaptive config is not compliant with adaptive timeout Config requirements")
+	}
+
+	return conf
+}
+
+// NewAdaptiveConfig creates a new AdaptiveConfig.
+// percentile: percentile of the observed latencies the timeout is derived from;
+// multiplier: multiplicative factor applied to the latency percentile;
+// windowSize: number of most recent latency observations taken into account;
+// minSamples: number of latency observations required before the timeout adapts;
+// minReplicaTimeout: lower bound of the adaptive timeout [Milliseconds];
+// maxReplicaTimeout: upper bound of the adaptive timeout [Milliseconds]
+func NewAdaptiveConfig(
+	percentile float64,
+	multiplier float64,
+	windowSize uint,
+	minSamples uint,
+	minReplicaTimeout time.Duration,
+	maxReplicaTimeout time.Duration,
+) (AdaptiveConfig, error) {
+	if percentile <= 0 || 1 < percentile {
+		return AdaptiveConfig{}, model.ConfigurationError{Msg: "percentile must be in range (0,1]"}
+	}
+	if multiplier < 1 {
+		return AdaptiveConfig{}, model.ConfigurationError{Msg: "multiplier must be at least 1"}
+	}
+	if windowSize == 0 {
+		return AdaptiveConfig{}, model.ConfigurationError{Msg: "windowSize must be positive"}
+	}
+	if minSamples == 0 || windowSize < minSamples {
+		return AdaptiveConfig{}, model.ConfigurationError{Msg: "minSamples must be in range [1,windowSize]"}
+	}
+	if minReplicaTimeout <= 0 {
+		return AdaptiveConfig{}, model.ConfigurationError{Msg: "minReplicaTimeout must be positive"}
+	}
+	if maxReplicaTimeout < minReplicaTimeout {
+		return AdaptiveConfig{}, model.ConfigurationError{Msg: "maxReplicaTimeout cannot be smaller than minReplicaTimeout"}
+	}
+
+	ac := AdaptiveConfig{
+		Percentile:        percentile,
+		Multiplier:        multiplier,
+		WindowSize:        windowSize,
+		MinSamples:        minSamples,
+		MinReplicaTimeout: float64(minReplicaTimeout.Milliseconds()),
+		MaxReplicaTimeout: float64(maxReplicaTimeout.Milliseconds()),
+	}
+	return ac, nil
+}
+
+// adaptiveStrategy derives the replica timeout from a moving percentile of the
+// observed proposal and QC latencies. Until enough latencies are observed, it
+// falls back to the static factors of the timeout Config.
+type adaptiveStrategy struct {
+	cfg       AdaptiveConfig
+	increase  float64
+	fallback  *exponentialStrategy
+	proposals *latencyWindow
+	qcs       *latencyWindow
+	// number of timeout increases on top of the base timeout
+	increases int
+}
+
+func newAdaptiveStrategy(timeoutConfig Config, adaptiveConfig AdaptiveConfig) *adaptiveStrategy {
+	return &adaptiveStrategy{
+		cfg:       adaptiveConfig,
+		increase:  timeoutConfig.TimeoutIncrease,
+		fallback:  newExponentialStrategy(timeoutConfig),
+		proposals: newLatencyWindow(adaptiveConfig.WindowSize),
+		qcs:       newLatencyWindow(adaptiveConfig.WindowSize),
+	}
+}
+
+func (s *adaptiveStrategy) replicaTimeout() float64 {
+	if s.proposals.size() < s.cfg.MinSamples {
+		return s.fallback.replicaTimeout()
+	}
+
+	// the base timeout has to cover the arrival of the proposal as well as the
+	// formation of the QC, if the latter is observed at all
+	latency := s.proposals.percentile(s.cfg.Percentile)
+	if s.qcs.size() >= s.cfg.MinSamples {
+		latency = math.Max(latency, s.qcs.percentile(s.cfg.Percentile))
+	}
+	timeout := latency * s.cfg.Multiplier * math.Pow(s.increase, float64(s.increases))
+
+	return math.Min(math.Max(timeout, s.cfg.MinReplicaTimeout), s.cfg.MaxReplicaTimeout)
+}
+
+func (s *adaptiveStrategy) onTimeout() {
+	s.fallback.onTimeout()
+	// stop counting once the upper bound is reached anyway, which avoids
+	// numerical overflows and lets the timeout decrease right after recovery
+	if s.replicaTimeout() < s.cfg.MaxReplicaTimeout {
+		s.increases++
+	}
+}
+
+func (s *adaptiveStrategy) onProgressBeforeTimeout() {
+	s.fallback.onProgressBeforeTimeout()
+	if s.increases > 0 {
+		s.increases--
+	}
+}
+
+func (s *adaptiveStrategy) onProposalLatency(latency time.Duration) {
+	s.proposals.add(float64(latency) / float64(time.Millisecond))
+}
+
+func (s *adaptiveStrategy) onQCLatency(latency time.Duration) {
+	s.qcs.add(float64(latency) / float64(time.Millisecond))
+}
+
+// latencyWindow holds the most recent latency observations [MILLISECONDS].
+type latencyWindow struct {
+	samples []float64
+	next    int
+}
+
+func newLatencyWindow(size uint) *latencyWindow {
+	return &latencyWindow{
+		samples: make([]float64, 0, size),
+	}
+}
+
+func (w *latencyWindow) add(latency float64) {
+	if len(w.samples) < cap(w.samples) {
+		w.samples = append(w.samples, latency)
+		return
+	}
+	w.samples[w.next] = latency
+	w.next = (w.next + 1) % len(w.samples)
+}
+
+func (w *latencyWindow) size() uint {
+	return uint(len(w.samples))
+}
+
+// percentile returns the smallest observed latency, which is at least as large
+// as the given fraction of the observed latencies.
+func (w *latencyWindow) percentile(p float64) float64 {
+	sorted := make([]float64, len(w.samples))
+	copy(sorted, w.samples)
+	sort.Float64s(sorted)
+	index := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if index < 0 {
+		index = 0
+	}
+	return sorted[index]
+}
@@ -0,0 +1,201 @@
+package timeout
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+)
+
+const (
+	adaptivePercentile float64 = 0.9
+	adaptiveMultiplier float64 = 2
+	adaptiveWindow     uint    = 10
+	adaptiveMinSamples uint    = 5
+	adaptiveMin        float64 = 10   // Milliseconds
+	adaptiveMax        float64 = 1000 // Milliseconds
+)
+
+func initAdaptiveController(t *testing.T) *Controller {
+	tc, err := NewConfig(
+		time.Duration(startRepTimeout*1e6),
+		time.Duration(minRepTimeout*1e6),
+		voteTimeoutFraction,
+		multiplicativeIncrease,
+		multiplicativeDecrease,
+		0)
+	require.NoError(t, err)
+	ac, err := NewAdaptiveConfig(
+		adaptivePercentile,
+		adaptiveMultiplier,
+		adaptiveWindow,
+		adaptiveMinSamples,
+		time.Duration(adaptiveMin*1e6),
+		time.Duration(adaptiveMax*1e6),
+	)
+	require.NoError(t, err)
+	return NewAdaptiveController(tc, ac)
+}
+
+// observeProposals feeds the given proposal latencies [MILLISECONDS] into the controller
+func observeProposals(tc *Controller, latencies ...float64) {
+	for _, latency := range latencies {
+		tc.strategy.onProposalLatency(time.Duration(latency * 1e6))
+	}
+}
+
+func TestAdaptiveConstructor(t *testing.T) {
+	c, err := NewAdaptiveConfig(0.9, 3, 100, 10, 500*time.Millisecond, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 0.9, c.Percentile)
+	require.Equal(t, float64(3), c.Multiplier)
+	require.Equal(t, uint(100), c.WindowSize)
+	require.Equal(t, uint(10), c.MinSamples)
+	require.Equal(t, float64(500), c.MinReplicaTimeout)
+	require.Equal(t, float64(60000), c.MaxReplicaTimeout)
+
+	// should not allow percentile to be 0 or larger than 1
+	_, err = NewAdaptiveConfig(0, 3, 100, 10, 500*time.Millisecond, time.Minute)
+	require.Error(t, err)
+	_, err = NewAdaptiveConfig(1.00001, 3, 100, 10, 500*time.Millisecond, time.Minute)
+	require.Error(t, err)
+
+	// should not allow multiplier smaller than 1
+	_, err = NewAdaptiveConfig(0.9, 0.99, 100, 10, 500*time.Millisecond, time.Minute)
+	require.Error(t, err)
+
+	// should not allow minSamples to be zero or larger than the window
+	_, err = NewAdaptiveConfig(0.9, 3, 100, 0, 500*time.Millisecond, time.Minute)
+	require.Error(t, err)
+	_, err = NewAdaptiveConfig(0.9, 3, 100, 101, 500*time.Millisecond, time.Minute)
+	require.Error(t, err)
+
+	// should not allow a non-positive minReplicaTimeout, or maxReplicaTimeout < minReplicaTimeout
+	_, err = NewAdaptiveConfig(0.9, 3, 100, 10, 0, time.Minute)
+	require.Error(t, err)
+	_, err = NewAdaptiveConfig(0.9, 3, 100, 10, time.Minute, time.Second)
+	require.Error(t, err)
+}
+
+func TestDefaultAdaptiveConfig(t *testing.T) {
+	c := NewDefaultAdaptiveConfig()
+	require.Equal(t, DefaultAdaptiveConfig, c)
+}
+
+// Test_AdaptiveFallback verifies that the static factors are used until
+// enough latencies are observed
+func Test_AdaptiveFallback(t *testing.T) {
+	tc := initAdaptiveController(t)
+	assert.Equal(t, int64(startRepTimeout), tc.ReplicaTimeout().Milliseconds())
+
+	tc.OnTimeout()
+	assert.Equal(t, int64(startRepTimeout*multiplicativeIncrease), tc.ReplicaTimeout().Milliseconds())
+
+	observeProposals(tc, 20, 20, 20, 20)
+	assert.Equal(t, int64(startRepTimeout*multiplicativeIncrease), tc.ReplicaTimeout().Milliseconds())
+}
+
+// Test_AdaptivePercentile verifies that the timeout is derived from the
+// percentile of the most recent latencies, within the safety bounds
+func Test_AdaptivePercentile(t *testing.T) {
+	tc := initAdaptiveController(t)
+
+	// 90th percentile of 10 samples is the 9th smallest sample
+	observeProposals(tc, 1, 2, 3, 4, 5, 6, 7, 8, 9, 100)
+	assert.Equal(t, int64(9*adaptiveMultiplier), tc.ReplicaTimeout().Milliseconds())
+	assert.Equal(t, int64(9*adaptiveMultiplier*voteTimeoutFraction), tc.VoteCollectionTimeout().Milliseconds())
+
+	// the oldest samples are replaced
+	observeProposals(tc, 50, 50)
+	assert.Equal(t, int64(50*adaptiveMultiplier), tc.ReplicaTimeout().Milliseconds())
+
+	// the QC latency is taken into account once enough QCs are observed
+	for i := 0; i < int(adaptiveMinSamples); i++ {
+		tc.strategy.onQCLatency(200 * time.Millisecond)
+	}
+	assert.Equal(t, int64(200*adaptiveMultiplier), tc.ReplicaTimeout().Milliseconds())
+
+	// the timeout is bounded
+	observeProposals(tc, 1000, 1000, 1000, 1000, 1000, 1000, 1000, 1000, 1000, 1000)
+	assert.Equal(t, int64(adaptiveMax), tc.ReplicaTimeout().Milliseconds())
+
+	tc = initAdaptiveController(t)
+	observeProposals(tc, 1, 1, 1, 1, 1)
+	assert.Equal(t, int64(adaptiveMin), tc.ReplicaTimeout().Milliseconds())
+}
+
+// Test_AdaptiveIncreaseDecrease verifies that the timeout increases on timeout,
+// and returns to the base timeout on progress after as many views as it timed out
+func Test_AdaptiveIncreaseDecrease(t *testing.T) {
+	tc := initAdaptiveController(t)
+	observeProposals(tc, 10, 10, 10, 10, 10)
+	base := 10 * adaptiveMultiplier
+
+	tc.OnTimeout()
+	tc.OnTimeout()
+	assert.Equal(t, int64(base*math.Pow(multiplicativeIncrease, 2)), tc.ReplicaTimeout().Milliseconds())
+
+	tc.OnProgressBeforeTimeout()
+	assert.Equal(t, int64(base*multiplicativeIncrease), tc.ReplicaTimeout().Milliseconds())
+	tc.OnProgressBeforeTimeout()
+	assert.Equal(t, int64(base), tc.ReplicaTimeout().Milliseconds())
+	tc.OnProgressBeforeTimeout()
+	assert.Equal(t, int64(base), tc.ReplicaTimeout().Milliseconds())
+
+	// the timeout stops increasing at the upper bound, such that it
+	// decreases right after progress is made
+	for i := 0; i < 100; i++ {
+		tc.OnTimeout()
+	}
+	assert.Equal(t, int64(adaptiveMax), tc.ReplicaTimeout().Milliseconds())
+	tc.OnProgressBeforeTimeout()
+	assert.Less(t, tc.ReplicaTimeout().Milliseconds(), int64(adaptiveMax))
+}
+
+// Test_LatencyObservation verifies that only the first proposal and QC for the
+// view of the current replica timeout are observed, if the view was entered
+// due to progress
+func Test_LatencyObservation(t *testing.T) {
+	tc := initAdaptiveController(t)
+	strategy := tc.strategy.(*adaptiveStrategy)
+
+	// no view started yet
+	tc.OnProposalReceived(1)
+	tc.OnQCReceived(1)
+	assert.Equal(t, uint(0), strategy.proposals.size())
+	assert.Equal(t, uint(0), strategy.qcs.size())
+
+	tc.OnProgressBeforeTimeout()
+	tc.StartTimeout(model.ReplicaTimeout, 5)
+	tc.OnProposalReceived(4)
+	tc.OnProposalReceived(5)
+	tc.OnProposalReceived(5)
+	tc.OnQCReceived(6)
+	tc.OnQCReceived(5)
+	assert.Equal(t, uint(1), strategy.proposals.size())
+	assert.Equal(t, uint(1), strategy.qcs.size())
+
+	// the vote collection timeout doesn't start a new view
+	tc.StartTimeout(model.VoteCollectionTimeout, 5)
+	tc.OnProposalReceived(5)
+	tc.OnQCReceived(5)
+	assert.Equal(t, uint(1), strategy.proposals.size())
+	assert.Equal(t, uint(1), strategy.qcs.size())
+
+	tc.OnProgressBeforeTimeout()
+	tc.StartTimeout(model.ReplicaTimeout, 6)
+	tc.OnProposalReceived(6)
+	assert.Equal(t, uint(2), strategy.proposals.size())
+
+	// views entered due to a timeout are not observed
+	tc.OnTimeout()
+	tc.StartTimeout(model.ReplicaTimeout, 7)
+	tc.OnProposalReceived(7)
+	tc.OnQCReceived(7)
+	assert.Equal(t, uint(2), strategy.proposals.size())
+	assert.Equal(t, uint(1), strategy.qcs.size())
+}
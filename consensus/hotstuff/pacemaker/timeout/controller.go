@@ -11,11 +11,45 @@ import (
 // - on timeout: increase timeout by multiplicative factor `timeoutIncrease` (user-specified)
 //   this results in exponential growing timeout duration on multiple subsequent timeouts
 // - on progress: decrease timeout by subtrahend `timeoutDecrease`
+//
+// The adjustment of the replica timeout is delegated to a strategy, which
+// either uses the static factors of the Config (see NewController), or derives
+// the timeout from the observed latency of proposals and QCs (see NewAdaptiveController).
 type Controller struct {
 	cfg            Config
+	strategy       strategy
 	timer          *time.Timer
 	timerInfo      *model.TimerInfo
 	timeoutChannel <-chan time.Time
+
+	// start of the most recent view for which a replica timeout was started,
+	// used to measure the latency of the proposal and the QC for the view
+	viewStart        time.Time
+	viewStartView    uint64
+	proposalObserved bool
+	qcObserved       bool
+	// whether progress was made since the last replica timeout was started.
+	// The latency in views entered without progress, e.g. after a timeout,
+	// depends on when the replicas timed out rather than on the network,
+	// hence it is not observed.
+	progressed bool
+}
+
+// strategy determines the replica timeout, depending on the outcome of the
+// previous views.
+type strategy interface {
+	// replicaTimeout returns the current replica timeout [MILLISECONDS]
+	replicaTimeout() float64
+	// onTimeout is called when a view timed out
+	onTimeout()
+	// onProgressBeforeTimeout is called when progress was made before the timeout
+	onProgressBeforeTimeout()
+	// onProposalLatency is called with the time from the start of a view until
+	// the proposal for the view was received
+	onProposalLatency(latency time.Duration)
+	// onQCLatency is called with the time from the start of a view until the
+	// QC for the view was observed
+	onQCLatency(latency time.Duration)
 }
 
 // timeoutCap this is an internal cap on the timeout to avoid numerical overflows.
@@ -32,11 +66,22 @@ func NewController(timeoutConfig Config) *Controller {
 
 	tc := Controller{
 		cfg:            timeoutConfig,
+		strategy:       newExponentialStrategy(timeoutConfig),
 		timeoutChannel: startChannel,
 	}
 	return &tc
 }
 
+// NewAdaptiveController creates a new Controller, which derives the replica
+// timeout from the observed latency of proposals and QCs. The timeout config
+// determines the start timeout, the timeout until enough latencies are
+// observed and the vote aggregation fraction.
+func NewAdaptiveController(timeoutConfig Config, adaptiveConfig AdaptiveConfig) *Controller {
+	tc := NewController(timeoutConfig)
+	tc.strategy = newAdaptiveStrategy(timeoutConfig, adaptiveConfig)
+	return tc
+}
+
 func DefaultController() *Controller {
 	return NewController(DefaultConfig)
 }
@@ -60,6 +105,13 @@ func (t *Controller) StartTimeout(mode model.TimeoutMode, view uint64) *model.Ti
 	duration := t.computeTimeoutDuration(mode)
 
 	startTime := time.Now().UTC()
+	if mode == model.ReplicaTimeout {
+		t.viewStart = startTime
+		t.viewStartView = view
+		t.proposalObserved = !t.progressed
+		t.qcObserved = !t.progressed
+		t.progressed = false
+	}
 	timer := time.NewTimer(duration)
 	timerInfo := model.TimerInfo{Mode: mode, View: view, StartTime: startTime, Duration: duration}
 	t.timer = timer
@@ -87,27 +139,87 @@ func (t *Controller) computeTimeoutDuration(mode model.TimeoutMode) time.Duratio
 
 // ReplicaTimeout returns the duration of the current view before we time out
 func (t *Controller) ReplicaTimeout() time.Duration {
-	return time.Duration(t.cfg.ReplicaTimeout * 1e6)
+	return time.Duration(t.strategy.replicaTimeout() * 1e6)
 }
 
 // VoteCollectionTimeout returns the duration of Vote aggregation _after_ receiving a block
 // during which the primary tries to aggregate votes for the view where it is leader
 func (t *Controller) VoteCollectionTimeout() time.Duration {
 	// time.Duration expects an int64 as input which specifies the duration in units of nanoseconds (1E-9)
-	return time.Duration(t.cfg.ReplicaTimeout * 1e6 * t.cfg.VoteAggregationTimeoutFraction)
+	return time.Duration(t.strategy.replicaTimeout() * 1e6 * t.cfg.VoteAggregationTimeoutFraction)
 }
 
 // OnTimeout indicates to the Controller that the timeout was reached
 func (t *Controller) OnTimeout() {
-	t.cfg.ReplicaTimeout = math.Min(t.cfg.ReplicaTimeout*t.cfg.TimeoutIncrease, timeoutCap)
+	t.strategy.onTimeout()
 }
 
 // OnProgressBeforeTimeout indicates to the Controller that progress was made _before_ the timeout was reached
 func (t *Controller) OnProgressBeforeTimeout() {
-	t.cfg.ReplicaTimeout = math.Max(t.cfg.ReplicaTimeout*t.cfg.TimeoutDecrease, t.cfg.MinReplicaTimeout)
+	t.progressed = true
+	t.strategy.onProgressBeforeTimeout()
+}
+
+// OnProposalReceived indicates to the Controller that the proposal for the
+// given view was received. Only the first proposal for the view, which the
+// replica timeout was last started for, is taken into account, and only if
+// the view was entered due to progress.
+func (t *Controller) OnProposalReceived(view uint64) {
+	if view != t.viewStartView || t.viewStart.IsZero() || t.proposalObserved {
+		return
+	}
+	t.proposalObserved = true
+	t.strategy.onProposalLatency(time.Since(t.viewStart))
+}
+
+// OnQCReceived indicates to the Controller that a QC for the given view was
+// observed. Only the first QC for the view, which the replica timeout was last
+// started for, is taken into account, and only if the view was entered due
+// to progress.
+func (t *Controller) OnQCReceived(view uint64) {
+	if view != t.viewStartView || t.viewStart.IsZero() || t.qcObserved {
+		return
+	}
+	t.qcObserved = true
+	t.strategy.onQCLatency(time.Since(t.viewStart))
 }
 
 // BlockRateDelay is a delay to broadcast the proposal in order to control block production rate
 func (t *Controller) BlockRateDelay() time.Duration {
 	return time.Duration(t.cfg.BlockRateDelayMS * float64(time.Millisecond))
 }
+
+// exponentialStrategy implements the static timeout adjustment of the Config:
+// the timeout increases by a multiplicative factor on timeout, and decreases by
+// a multiplicative factor on progress, down to the minimum replica timeout.
+type exponentialStrategy struct {
+	timeout  float64
+	min      float64
+	increase float64
+	decrease float64
+}
+
+func newExponentialStrategy(cfg Config) *exponentialStrategy {
+	return &exponentialStrategy{
+		timeout:  cfg.ReplicaTimeout,
+		min:      cfg.MinReplicaTimeout,
+		increase: cfg.TimeoutIncrease,
+		decrease: cfg.TimeoutDecrease,
+	}
+}
+
+func (s *exponentialStrategy) replicaTimeout() float64 {
+	return s.timeout
+}
+
+func (s *exponentialStrategy) onTimeout() {
+	s.timeout = math.Min(s.timeout*s.increase, timeoutCap)
+}
+
+func (s *exponentialStrategy) onProgressBeforeTimeout() {
+	s.timeout = math.Max(s.timeout*s.decrease, s.min)
+}
+
+func (s *exponentialStrategy) onProposalLatency(time.Duration) {}
+
+func (s *exponentialStrategy) onQCLatency(time.Duration) {}
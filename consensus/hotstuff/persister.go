@@ -1,5 +1,9 @@
 package hotstuff
 
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
 // Persister is responsible for persisting state we need to bootstrap after a
 // restart or crash.
 type Persister interface {
@@ -15,4 +19,11 @@ type Persister interface {
 
 	// PutVoted persists the last voted view.
 	PutVoted(view uint64) error
+
+	// GetHighestTC will retrieve the highest timeout certificate, or nil if
+	// no timeout certificate was persisted yet.
+	GetHighestTC() (*flow.TimeoutCertificate, error)
+
+	// PutHighestTC persists the highest timeout certificate.
+	PutHighestTC(tc *flow.TimeoutCertificate) error
 }
@@ -1,9 +1,12 @@
 package persister
 
 import (
+	"errors"
+
 	"github.com/dgraph-io/badger/v2"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
 )
 
@@ -46,3 +49,29 @@ func (p *Persister) PutStarted(view uint64) error {
 func (p *Persister) PutVoted(view uint64) error {
 	return operation.RetryOnConflict(p.db.Update, operation.UpdateVotedView(p.chainID, view))
 }
+
+// GetHighestTC returns the last persisted highest timeout certificate, or nil
+// if no timeout certificate was persisted yet.
+func (p *Persister) GetHighestTC() (*flow.TimeoutCertificate, error) {
+	var tc flow.TimeoutCertificate
+	err := p.db.View(operation.RetrieveHighestTimeoutCertificate(p.chainID, &tc))
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tc, nil
+}
+
+// PutHighestTC persists the highest timeout certificate hotstuff observed.
+func (p *Persister) PutHighestTC(tc *flow.TimeoutCertificate) error {
+	return operation.RetryOnConflict(p.db.Update, func(tx *badger.Txn) error {
+		err := operation.UpdateHighestTimeoutCertificate(p.chainID, tc)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			// the first timeout certificate is inserted, as none is bootstrapped
+			return operation.InsertHighestTimeoutCertificate(p.chainID, tc)(tx)
+		}
+		return err
+	})
+}
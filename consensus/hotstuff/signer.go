@@ -11,7 +11,8 @@ type SignerVerifier interface {
 	Verifier
 }
 
-// Signer is responsible for creating votes, proposals and QC's for a given block.
+// Signer is responsible for creating votes, proposals and QC's for a given block,
+// as well as timeouts and TC's for a given view.
 type Signer interface {
 	// CreateProposal creates a proposal for the given block.
 	CreateProposal(block *model.Block) (*model.Proposal, error)
@@ -21,4 +22,11 @@ type Signer interface {
 
 	// CreateQC creates a QC for the given block.
 	CreateQC(votes []*model.Vote) (*flow.QuorumCertificate, error)
+
+	// CreateTimeout creates a timeout for the given view, which includes the
+	// highest QC and TC known to the replica.
+	CreateTimeout(view uint64, highestQC *flow.QuorumCertificate, highestTC *flow.TimeoutCertificate) (*model.TimeoutObject, error)
+
+	// CreateTC creates a TC for the given timeouts.
+	CreateTC(timeouts []*model.TimeoutObject) (*flow.TimeoutCertificate, error)
 }
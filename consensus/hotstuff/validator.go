@@ -5,7 +5,7 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 )
 
-// Validator provides functions to validate QC, proposals and votes.
+// Validator provides functions to validate QC, proposals, votes and timeouts.
 type Validator interface {
 
 	// ValidateQC checks the validity of a QC for a given block.
@@ -16,4 +16,7 @@ type Validator interface {
 
 	// ValidateVote checks the validity of a vote for a given block.
 	ValidateVote(vote *model.Vote, block *model.Block) (*flow.Identity, error)
+
+	// ValidateTimeout checks the validity of a timeout, including its highest QC and TC.
+	ValidateTimeout(timeout *model.TimeoutObject) (*flow.Identity, error)
 }
@@ -162,6 +162,140 @@ func (v *Validator) ValidateVote(vote *model.Vote, block *model.Block) (*flow.Id
 	return voter, nil
 }
 
+// ValidateTimeout validates the timeout and returns the identity of the replica who signed it.
+// A timeout is valid if its signature is valid for the timed out view, and if it includes a valid
+// QC (and optionally a valid TC) for a lower view. The committee is determined at the block
+// referenced by the timeout's highest QC.
+func (v *Validator) ValidateTimeout(timeout *model.TimeoutObject) (*flow.Identity, error) {
+	qc := timeout.HighestQC
+	if qc == nil {
+		return nil, newInvalidTimeoutError(timeout, fmt.Errorf("timeout does not include highest QC"))
+	}
+	if qc.View >= timeout.View {
+		return nil, newInvalidTimeoutError(timeout, fmt.Errorf("timeout's highest QC view %d is not lower than timeout's view %d", qc.View, timeout.View))
+	}
+
+	// validate the highest QC
+	err := v.validateQCForTimeout(qc)
+	if model.IsInvalidBlockError(err) {
+		return nil, newInvalidTimeoutError(timeout, fmt.Errorf("invalid highest QC: %w", err))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not validate highest QC of timeout (%x): %w", timeout.ID(), err)
+	}
+
+	signer, err := v.committee.Identity(qc.BlockID, timeout.SignerID)
+	if errors.Is(err, model.ErrInvalidSigner) {
+		return nil, newInvalidTimeoutError(timeout, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving signer Identity at block %x: %w", qc.BlockID, err)
+	}
+
+	// check whether the signature data is valid for the timed out view
+	valid, err := v.verifier.VerifyTimeout(signer, timeout.SigData, timeout.View)
+	if err != nil {
+		switch {
+		case errors.Is(err, signature.ErrInvalidFormat):
+			return nil, newInvalidTimeoutError(timeout, err)
+		case errors.Is(err, model.ErrInvalidSigner):
+			return nil, newInvalidTimeoutError(timeout, err)
+		default:
+			return nil, fmt.Errorf("cannot verify signature for timeout (%x): %w", timeout.ID(), err)
+		}
+	}
+	if !valid {
+		return nil, newInvalidTimeoutError(timeout, model.ErrInvalidSignature)
+	}
+
+	// validate the highest TC, if the replica knows one
+	tc := timeout.HighestTC
+	if tc == nil {
+		return signer, nil
+	}
+	if tc.View >= timeout.View {
+		return nil, newInvalidTimeoutError(timeout, fmt.Errorf("timeout's highest TC view %d is not lower than timeout's view %d", tc.View, timeout.View))
+	}
+	if tc.HighestQC == nil {
+		return nil, newInvalidTimeoutError(timeout, fmt.Errorf("timeout's highest TC does not include highest QC"))
+	}
+	err = v.validateTC(tc)
+	if model.IsInvalidBlockError(err) {
+		return nil, newInvalidTimeoutError(timeout, fmt.Errorf("invalid highest TC: %w", err))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not validate highest TC of timeout (%x): %w", timeout.ID(), err)
+	}
+
+	return signer, nil
+}
+
+// validateTC validates the TC, including the highest QC it carries. The committee is determined
+// at the block referenced by the TC's highest QC, which must not be nil. An invalid TC is reported as an InvalidBlockError
+// for the block referenced by its highest QC.
+// The TC's signature only covers its view, the highest QC must hence be validated as a QC on its own.
+func (v *Validator) validateTC(tc *flow.TimeoutCertificate) error {
+	qc := tc.HighestQC
+	block, found := v.forks.GetBlock(qc.BlockID)
+	if !found {
+		return v.missingBlockError(qc)
+	}
+	if qc.View >= tc.View {
+		return newInvalidBlockError(block, fmt.Errorf("tc's highest QC view %d is not lower than tc's view %d", qc.View, tc.View))
+	}
+
+	// Retrieve full Identities of all legitimate consensus participants and the Identities of the tc's signers
+	allParticipants, err := v.committee.Identities(block.BlockID, filter.Any)
+	if err != nil {
+		return fmt.Errorf("could not get consensus participants for block %s: %w", block.BlockID, err)
+	}
+	signers := allParticipants.Filter(filter.HasNodeID(tc.SignerIDs...)) // resulting IdentityList contains no duplicates
+	if len(signers) != len(tc.SignerIDs) {
+		return newInvalidBlockError(block, fmt.Errorf("some tc signers are duplicated or invalid consensus participants at block %x: %w", block.BlockID, model.ErrInvalidSigner))
+	}
+
+	// a TC requires the same stake threshold as a QC
+	threshold := hotstuff.ComputeStakeThresholdForBuildingQC(allParticipants.TotalStake())
+	if signers.TotalStake() < threshold {
+		return newInvalidBlockError(block, fmt.Errorf("tc signers have insufficient stake of %d (required=%d)", signers.TotalStake(), threshold))
+	}
+
+	// verify whether the signature bytes are valid for the TC's view
+	valid, err := v.verifier.VerifyTC(signers, tc.SigData, tc.View)
+	if errors.Is(err, signature.ErrInvalidFormat) {
+		return newInvalidBlockError(block, fmt.Errorf("TC signature has bad format: %w", err))
+	}
+	if err != nil {
+		return fmt.Errorf("cannot verify tc's aggregated signature (tc.View: %d): %w", tc.View, err)
+	}
+	if !valid {
+		return newInvalidBlockError(block, fmt.Errorf("invalid tc: %w", model.ErrInvalidSignature))
+	}
+
+	// validate QC - keep the most expensive the last to check
+	return v.ValidateQC(qc, block)
+}
+
+// validateQCForTimeout validates a QC, which is included in a timeout.
+func (v *Validator) validateQCForTimeout(qc *flow.QuorumCertificate) error {
+	block, found := v.forks.GetBlock(qc.BlockID)
+	if !found {
+		return v.missingBlockError(qc)
+	}
+	return v.ValidateQC(qc, block)
+}
+
+// missingBlockError returns the error for a QC whose block is not known to Forks.
+func (v *Validator) missingBlockError(qc *flow.QuorumCertificate) error {
+	// Forks is _allowed_ to (but obliged to) prune blocks whose view is below the newest finalized block.
+	if qc.View >= v.forks.FinalizedView() {
+		// If the block is equal or above the finalized view, then Forks should have it. Otherwise, we are missing a block!
+		return model.MissingBlockError{View: qc.View, BlockID: qc.BlockID}
+	}
+	// Forks has already pruned the block. I.e., we can't validate the QC.
+	return model.ErrUnverifiableBlock
+}
+
 func newInvalidBlockError(block *model.Block, err error) error {
 	return model.InvalidBlockError{
 		BlockID: block.BlockID,
@@ -177,3 +311,11 @@ func newInvalidVoteError(vote *model.Vote, err error) error {
 		Err:    err,
 	}
 }
+
+func newInvalidTimeoutError(timeout *model.TimeoutObject, err error) error {
+	return model.InvalidTimeoutError{
+		TimeoutID: timeout.ID(),
+		View:      timeout.View,
+		Err:       err,
+	}
+}
@@ -448,3 +448,168 @@ func (qs *QCSuite) TestQCSignatureInvalidFormat() {
 	err := qs.validator.ValidateQC(qs.qc, qs.block)
 	assert.True(qs.T(), model.IsInvalidBlockError(err), "if the signature has an invalid format, an ErrorInvalidBlock error should be raised")
 }
+
+func TestValidateTimeout(t *testing.T) {
+	suite.Run(t, new(TimeoutSuite))
+}
+
+type TimeoutSuite struct {
+	suite.Suite
+	participants flow.IdentityList
+	signer       *flow.Identity
+	finalized    uint64
+	block        *model.Block
+	tcBlock      *model.Block
+	tc           *flow.TimeoutCertificate
+	timeout      *model.TimeoutObject
+	committee    *mocks.Committee
+	forks        *mocks.Forks
+	verifier     *mocks.Verifier
+	validator    *Validator
+}
+
+func (ts *TimeoutSuite) SetupTest() {
+	rand.Seed(time.Now().UnixNano())
+	ts.finalized = uint64(rand.Uint32() + 1)
+	ts.participants = unittest.IdentityListFixture(7, unittest.WithRole(flow.RoleConsensus))
+	ts.signer = ts.participants[0]
+
+	// the TC references the finalized block, the timeout references a child of it
+	ts.tcBlock = helper.MakeBlock(ts.T(), helper.WithBlockView(ts.finalized))
+	ts.block = helper.MakeBlock(ts.T(),
+		helper.WithBlockView(ts.finalized+1),
+		helper.WithParentBlock(ts.tcBlock),
+	)
+	ts.tc = &flow.TimeoutCertificate{
+		View:      ts.finalized + 2,
+		HighestQC: helper.MakeQC(ts.T(), helper.WithQCBlock(ts.tcBlock), helper.WithQCSigners(ts.participants.NodeIDs())),
+		SignerIDs: ts.participants.NodeIDs(),
+		SigData:   unittest.RandomBytes(32),
+	}
+	ts.timeout = &model.TimeoutObject{
+		View:      ts.finalized + 3,
+		HighestQC: helper.MakeQC(ts.T(), helper.WithQCBlock(ts.block), helper.WithQCSigners(ts.participants.NodeIDs())),
+		HighestTC: ts.tc,
+		SignerID:  ts.signer.NodeID,
+		SigData:   unittest.RandomBytes(32),
+	}
+
+	ts.committee = &mocks.Committee{}
+	ts.committee.On("Identities", mock.Anything, mock.Anything).Return(
+		func(blockID flow.Identifier, selector flow.IdentityFilter) flow.IdentityList {
+			return ts.participants.Filter(selector)
+		},
+		nil,
+	)
+	ts.committee.On("Identity", ts.block.BlockID, ts.signer.NodeID).Return(ts.signer, nil)
+
+	ts.forks = &mocks.Forks{}
+	ts.forks.On("FinalizedView").Return(ts.finalized)
+	ts.forks.On("GetBlock", ts.block.BlockID).Return(ts.block, true)
+	ts.forks.On("GetBlock", ts.tcBlock.BlockID).Return(ts.tcBlock, true)
+
+	ts.verifier = &mocks.Verifier{}
+	ts.verifier.On("VerifyQC", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	ts.verifier.On("VerifyTimeout", ts.signer, ts.timeout.SigData, ts.timeout.View).Return(true, nil)
+	ts.verifier.On("VerifyTC", ts.participants, ts.tc.SigData, ts.tc.View).Return(true, nil)
+
+	ts.validator = New(ts.committee, ts.forks, ts.verifier)
+}
+
+func (ts *TimeoutSuite) TestTimeoutOK() {
+	signer, err := ts.validator.ValidateTimeout(ts.timeout)
+	assert.NoError(ts.T(), err, "a valid timeout should be accepted")
+	assert.Equal(ts.T(), ts.signer, signer)
+}
+
+func (ts *TimeoutSuite) TestTimeoutWithoutTCOK() {
+	ts.timeout.HighestTC = nil
+	_, err := ts.validator.ValidateTimeout(ts.timeout)
+	assert.NoError(ts.T(), err, "a valid timeout without TC should be accepted")
+	ts.verifier.AssertNotCalled(ts.T(), "VerifyTC", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (ts *TimeoutSuite) TestTimeoutMissingQC() {
+	ts.timeout.HighestQC = nil
+	_, err := ts.validator.ValidateTimeout(ts.timeout)
+	assert.True(ts.T(), model.IsInvalidTimeoutError(err), "a timeout without QC should be rejected")
+}
+
+func (ts *TimeoutSuite) TestTimeoutQCNotLowerThanView() {
+	ts.timeout.View = ts.timeout.HighestQC.View
+	_, err := ts.validator.ValidateTimeout(ts.timeout)
+	assert.True(ts.T(), model.IsInvalidTimeoutError(err), "a timeout with a QC for its own view should be rejected")
+}
+
+func (ts *TimeoutSuite) TestTimeoutQCInvalid() {
+	*ts.verifier = mocks.Verifier{}
+	ts.verifier.On("VerifyQC", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+	_, err := ts.validator.ValidateTimeout(ts.timeout)
+	assert.True(ts.T(), model.IsInvalidTimeoutError(err), "a timeout with an invalid QC should be rejected")
+}
+
+func (ts *TimeoutSuite) TestTimeoutMissingQCBlock() {
+	*ts.forks = mocks.Forks{}
+	ts.forks.On("FinalizedView").Return(ts.finalized)
+	ts.forks.On("GetBlock", mock.Anything).Return(nil, false)
+	_, err := ts.validator.ValidateTimeout(ts.timeout)
+	assert.True(ts.T(), errors.As(err, &model.MissingBlockError{}), "a timeout with an unknown QC block should result in a missing block error")
+	assert.False(ts.T(), model.IsInvalidTimeoutError(err), "a timeout with an unknown QC block should not be considered invalid")
+}
+
+func (ts *TimeoutSuite) TestTimeoutInvalidSigner() {
+	*ts.committee = mocks.Committee{}
+	ts.committee.On("Identities", mock.Anything, mock.Anything).Return(ts.participants, nil)
+	ts.committee.On("Identity", ts.block.BlockID, ts.signer.NodeID).Return(nil, model.ErrInvalidSigner)
+	_, err := ts.validator.ValidateTimeout(ts.timeout)
+	assert.True(ts.T(), model.IsInvalidTimeoutError(err), "a timeout from an unauthorized signer should be rejected")
+}
+
+func (ts *TimeoutSuite) TestTimeoutSignatureInvalid() {
+	*ts.verifier = mocks.Verifier{}
+	ts.verifier.On("VerifyQC", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	ts.verifier.On("VerifyTimeout", ts.signer, ts.timeout.SigData, ts.timeout.View).Return(false, nil)
+	_, err := ts.validator.ValidateTimeout(ts.timeout)
+	assert.True(ts.T(), model.IsInvalidTimeoutError(err), "a timeout with an invalid signature should be rejected")
+}
+
+func (ts *TimeoutSuite) TestTimeoutSignatureError() {
+	*ts.verifier = mocks.Verifier{}
+	ts.verifier.On("VerifyQC", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	ts.verifier.On("VerifyTimeout", ts.signer, ts.timeout.SigData, ts.timeout.View).Return(false, errors.New("dummy error"))
+	_, err := ts.validator.ValidateTimeout(ts.timeout)
+	assert.Error(ts.T(), err, "unspecific sig verification error should be escalated to surrounding logic")
+	assert.False(ts.T(), model.IsInvalidTimeoutError(err), "unspecific internal errors should not result in InvalidTimeoutError")
+}
+
+func (ts *TimeoutSuite) TestTimeoutTCInsufficientStake() {
+	ts.tc.SignerIDs = ts.participants[:4].NodeIDs()
+	_, err := ts.validator.ValidateTimeout(ts.timeout)
+	assert.True(ts.T(), model.IsInvalidTimeoutError(err), "a timeout with a TC with insufficient stake should be rejected")
+}
+
+func (ts *TimeoutSuite) TestTimeoutTCSignatureInvalid() {
+	*ts.verifier = mocks.Verifier{}
+	ts.verifier.On("VerifyQC", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	ts.verifier.On("VerifyTimeout", ts.signer, ts.timeout.SigData, ts.timeout.View).Return(true, nil)
+	ts.verifier.On("VerifyTC", ts.participants, ts.tc.SigData, ts.tc.View).Return(false, nil)
+	_, err := ts.validator.ValidateTimeout(ts.timeout)
+	assert.True(ts.T(), model.IsInvalidTimeoutError(err), "a timeout with a TC with an invalid signature should be rejected")
+}
+
+// the TC's signature doesn't cover its highest QC, which therefore has to be validated on its own
+func (ts *TimeoutSuite) TestTimeoutTCQCInvalid() {
+	*ts.verifier = mocks.Verifier{}
+	ts.verifier.On("VerifyQC", mock.Anything, mock.Anything, ts.block).Return(true, nil)
+	ts.verifier.On("VerifyQC", mock.Anything, mock.Anything, ts.tcBlock).Return(false, nil)
+	ts.verifier.On("VerifyTimeout", ts.signer, ts.timeout.SigData, ts.timeout.View).Return(true, nil)
+	ts.verifier.On("VerifyTC", ts.participants, ts.tc.SigData, ts.tc.View).Return(true, nil)
+	_, err := ts.validator.ValidateTimeout(ts.timeout)
+	assert.True(ts.T(), model.IsInvalidTimeoutError(err), "a timeout with a TC with an invalid highest QC should be rejected")
+}
+
+func (ts *TimeoutSuite) TestTimeoutTCNotLowerThanView() {
+	ts.tc.View = ts.timeout.View
+	_, err := ts.validator.ValidateTimeout(ts.timeout)
+	assert.True(ts.T(), model.IsInvalidTimeoutError(err), "a timeout with a TC for its own view should be rejected")
+}
@@ -32,16 +32,18 @@ type CombinedSigner struct {
 // - the merger is used to join and split the two signature parts on our models;
 // - the thresholdSignerStore is used to get threshold-signers by epoch/view;
 // - the signer ID is used as the identity when creating signatures;
+// - the chain ID is the chain whose timeouts are signed and verified;
 func NewCombinedSigner(
 	committee hotstuff.Committee,
 	staking module.AggregatingSigner,
 	thresholdVerifier module.ThresholdVerifier,
 	merger module.Merger,
 	thresholdSignerStore module.ThresholdSignerStore,
-	signerID flow.Identifier) *CombinedSigner {
+	signerID flow.Identifier,
+	chainID flow.ChainID) *CombinedSigner {
 
 	sc := &CombinedSigner{
-		CombinedVerifier:     NewCombinedVerifier(committee, staking, thresholdVerifier, merger),
+		CombinedVerifier:     NewCombinedVerifier(committee, staking, thresholdVerifier, merger, chainID),
 		staking:              staking,
 		merger:               merger,
 		thresholdSignerStore: thresholdSignerStore,
@@ -180,6 +182,64 @@ func (c *CombinedSigner) CreateQC(votes []*model.Vote) (*flow.QuorumCertificate,
 	return qc, nil
 }
 
+// CreateTimeout will create a timeout for the given view. Timeouts only carry a
+// staking signature, as the random beacon is only used for certified blocks.
+func (c *CombinedSigner) CreateTimeout(view uint64, highestQC *flow.QuorumCertificate, highestTC *flow.TimeoutCertificate) (*model.TimeoutObject, error) {
+
+	// create the message to be signed and generate signature
+	msg := MakeTimeoutMessage(c.chainID, view)
+	sig, err := c.staking.Sign(msg)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate staking signature: %w", err)
+	}
+
+	// create the timeout
+	timeout := &model.TimeoutObject{
+		View:      view,
+		HighestQC: highestQC,
+		HighestTC: highestTC,
+		SignerID:  c.signerID,
+		SigData:   sig,
+	}
+
+	return timeout, nil
+}
+
+// CreateTC will create a timeout certificate with an aggregated staking signature
+// for the given timeouts.
+func (c *CombinedSigner) CreateTC(timeouts []*model.TimeoutObject) (*flow.TimeoutCertificate, error) {
+
+	// check the consistency of the timeouts
+	highestQC, err := checkTimeoutsValidity(timeouts)
+	if err != nil {
+		return nil, fmt.Errorf("timeouts are not valid: %w", err)
+	}
+
+	// collect signers and staking signatures
+	signerIDs := make([]flow.Identifier, 0, len(timeouts))
+	stakingSigs := make([]crypto.Signature, 0, len(timeouts))
+	for _, timeout := range timeouts {
+		signerIDs = append(signerIDs, timeout.SignerID)
+		stakingSigs = append(stakingSigs, timeout.SigData)
+	}
+
+	// aggregate all staking signatures into one aggregated signature
+	stakingAggSig, err := c.staking.Aggregate(stakingSigs)
+	if err != nil {
+		return nil, fmt.Errorf("could not aggregate staking signatures: %w", err)
+	}
+
+	// create the TC
+	tc := &flow.TimeoutCertificate{
+		View:      timeouts[0].View,
+		HighestQC: highestQC,
+		SignerIDs: signerIDs,
+		SigData:   stakingAggSig,
+	}
+
+	return tc, nil
+}
+
 // genSigData generates the signature data for our local node for the given block.
 func (c *CombinedSigner) genSigData(block *model.Block) ([]byte, error) {
 
@@ -21,6 +21,7 @@ type CombinedVerifier struct {
 	keysAggregator *stakingKeysAggregator
 	beacon         module.ThresholdVerifier
 	merger         module.Merger
+	chainID        flow.ChainID
 }
 
 // NewCombinedVerifier creates a new combined verifier with the given dependencies.
@@ -28,14 +29,16 @@ type CombinedVerifier struct {
 // - the DKG state is used to retrieve DKG data necessary to verify beacon signatures;
 // - the staking verifier is used to verify single & aggregated staking signatures;
 // - the beacon verifier is used to verify signature shares & threshold signatures;
-// - the merger is used to combined & split staking & random beacon signatures; and
-func NewCombinedVerifier(committee hotstuff.Committee, staking module.AggregatingVerifier, beacon module.ThresholdVerifier, merger module.Merger) *CombinedVerifier {
+// - the merger is used to combined & split staking & random beacon signatures;
+// - the chain ID is the chain whose timeouts are verified.
+func NewCombinedVerifier(committee hotstuff.Committee, staking module.AggregatingVerifier, beacon module.ThresholdVerifier, merger module.Merger, chainID flow.ChainID) *CombinedVerifier {
 	c := &CombinedVerifier{
 		committee:      committee,
 		staking:        staking,
 		keysAggregator: newStakingKeysAggregator(),
 		beacon:         beacon,
 		merger:         merger,
+		chainID:        chainID,
 	}
 	return c
 }
@@ -122,3 +125,33 @@ func (c *CombinedVerifier) VerifyQC(signers flow.IdentityList, sigData []byte, b
 	}
 	return stakingValid, nil
 }
+
+// VerifyTimeout verifies the validity of the staking signature from a timeout.
+func (c *CombinedVerifier) VerifyTimeout(signer *flow.Identity, sigData []byte, view uint64) (bool, error) {
+
+	// create the to-be-signed message and verify the staking signature
+	msg := MakeTimeoutMessage(c.chainID, view)
+	valid, err := c.staking.Verify(msg, sigData, signer.StakingPubKey)
+	if err != nil {
+		return false, fmt.Errorf("internal error while verifying staking signature: %w", err)
+	}
+
+	return valid, nil
+}
+
+// VerifyTC verifies the validity of the aggregated staking signature on a timeout certificate.
+func (c *CombinedVerifier) VerifyTC(signers flow.IdentityList, sigData []byte, view uint64) (bool, error) {
+
+	msg := MakeTimeoutMessage(c.chainID, view)
+
+	aggregatedKey, err := c.keysAggregator.aggregatedStakingKey(signers)
+	if err != nil {
+		return false, fmt.Errorf("could not compute aggregated key: %w", err)
+	}
+	valid, err := c.staking.Verify(msg, sigData, aggregatedKey)
+	if err != nil {
+		return false, fmt.Errorf("internal error while verifying staking signature: %w", err)
+	}
+
+	return valid, nil
+}
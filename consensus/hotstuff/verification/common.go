@@ -25,6 +25,35 @@ func MakeVoteMessage(view uint64, blockID flow.Identifier) []byte {
 	return msg[:]
 }
 
+// MakeTimeoutMessage generates the message we have to sign in order to time out
+// of a view. Votes are bound to their chain by the block ID, as the block header
+// includes the chain ID. Timeouts are not bound to a block, so the chain ID is
+// included explicitly; otherwise a timeout of one chain could be replayed on
+// another chain with an overlapping committee, e.g. on the cluster chain of the
+// following epoch, which again starts at view zero.
+//
+// Besides the chain ID, timeouts only sign the view, such that timeouts for the
+// same view can be aggregated into a timeout certificate, regardless of the highest QC
+// the individual replicas included in their timeouts. The highest QC of a timeout
+// or TC is hence not covered by the signature, and is verified separately as a QC
+// in its own right. This is sufficient, as the highest QC is only used to
+// synchronize views and to learn about certified blocks, which is safe for any
+// valid QC: the voting and finalization rules don't depend on it. A byzantine
+// replica could at most replace it by a lower valid QC, which only delays the
+// replicas in catching up with the certified blocks.
+func MakeTimeoutMessage(chainID flow.ChainID, view uint64) []byte {
+	msg := flow.MakeID(struct {
+		Timeout string
+		ChainID flow.ChainID
+		View    uint64
+	}{
+		Timeout: "timeout",
+		ChainID: chainID,
+		View:    view,
+	})
+	return msg[:]
+}
+
 // checkVotesValidity checks the validity of each vote by checking that they are
 // all for the same view number, the same block ID and that each vote is from a
 // different signer.
@@ -68,6 +97,51 @@ func checkVotesValidity(votes []*model.Vote) error {
 	return nil
 }
 
+// checkTimeoutsValidity checks the validity of each timeout by checking that they
+// are all for the same view number, that each includes a highest QC and that each
+// timeout is from a different signer. It returns the highest QC of all timeouts.
+func checkTimeoutsValidity(timeouts []*model.TimeoutObject) (*flow.QuorumCertificate, error) {
+
+	// first, we should be sure to have timeouts at all
+	if len(timeouts) == 0 {
+		return nil, fmt.Errorf("need at least one timeout")
+	}
+
+	// we use this map to check each timeout has a different signer
+	signerIDs := make(map[flow.Identifier]struct{}, len(timeouts))
+
+	// we use the view from the first timeout to check that all timeouts have the same view
+	view := timeouts[0].View
+	var highestQC *flow.QuorumCertificate
+
+	// go through all timeouts to check their validity
+	for _, timeout := range timeouts {
+
+		// if we have a view mismatch, bail
+		if timeout.View != view {
+			return nil, fmt.Errorf("view mismatch between timeouts (%d != %d)", timeout.View, view)
+		}
+
+		// if we have no highest QC, bail
+		if timeout.HighestQC == nil {
+			return nil, fmt.Errorf("timeout without highest QC (signer: %x)", timeout.SignerID)
+		}
+		if highestQC == nil || timeout.HighestQC.View > highestQC.View {
+			highestQC = timeout.HighestQC
+		}
+
+		// register the signer in our map
+		signerIDs[timeout.SignerID] = struct{}{}
+	}
+
+	// check that we have as many signers as timeouts
+	if len(signerIDs) != len(timeouts) {
+		return nil, fmt.Errorf("less signers than timeouts (signers: %d, timeouts: %d)", len(signerIDs), len(timeouts))
+	}
+
+	return highestQC, nil
+}
+
 // stakingKeysAggregator is a structure that aggregates the staking
 // public keys for QC verifications.
 type stakingKeysAggregator struct {
@@ -20,6 +20,8 @@ import (
 
 const epochCounter = uint64(42)
 
+const chainID = flow.Testnet
+
 func MakeSigners(t *testing.T,
 	committee hotstuff.Committee,
 	signerIDs []flow.Identifier,
@@ -32,12 +34,12 @@ func MakeSigners(t *testing.T,
 	var signers []hotstuff.SignerVerifier
 	if len(beaconKeys) != len(stakingKeys) {
 		for i, signerID := range signerIDs {
-			signer := MakeStakingSigner(t, committee, signerID, stakingKeys[i])
+			signer := MakeStakingSigner(t, committee, signerID, stakingKeys[i], chainID)
 			signers = append(signers, signer)
 		}
 	} else {
 		for i, signerID := range signerIDs {
-			signer := MakeBeaconSigner(t, committee, signerID, stakingKeys[i], beaconKeys[i])
+			signer := MakeBeaconSigner(t, committee, signerID, stakingKeys[i], beaconKeys[i], chainID)
 			signers = append(signers, signer)
 		}
 	}
@@ -45,11 +47,11 @@ func MakeSigners(t *testing.T,
 	return signers
 }
 
-func MakeStakingSigner(t *testing.T, committee hotstuff.Committee, signerID flow.Identifier, priv crypto.PrivateKey) *SingleSignerVerifier {
+func MakeStakingSigner(t *testing.T, committee hotstuff.Committee, signerID flow.Identifier, priv crypto.PrivateKey, chainID flow.ChainID) *SingleSignerVerifier {
 	local, err := local.New(nil, priv)
 	require.NoError(t, err)
 	staking := signature.NewAggregationProvider("test_staking", local)
-	signer := NewSingleSignerVerifier(committee, staking, signerID)
+	signer := NewSingleSignerVerifier(committee, staking, signerID, chainID)
 	return signer
 }
 
@@ -57,7 +59,8 @@ func MakeBeaconSigner(t *testing.T,
 	committee hotstuff.Committee,
 	signerID flow.Identifier,
 	stakingPriv crypto.PrivateKey,
-	beaconPriv crypto.PrivateKey) *CombinedSigner {
+	beaconPriv crypto.PrivateKey,
+	chainID flow.ChainID) *CombinedSigner {
 
 	local, err := local.New(nil, stakingPriv)
 	require.NoError(t, err)
@@ -69,7 +72,7 @@ func MakeBeaconSigner(t *testing.T,
 	thresholdSignerStore := &module_mock.ThresholdSignerStore{}
 	thresholdSignerStore.On("GetThresholdSigner", mock.Anything).Return(thresholdSigner, nil)
 
-	signer := NewCombinedSigner(committee, staking, thresholdVerifier, combiner, thresholdSignerStore, signerID)
+	signer := NewCombinedSigner(committee, staking, thresholdVerifier, combiner, thresholdSignerStore, signerID, chainID)
 
 	return signer
 }
@@ -60,3 +60,31 @@ func (w SignerMetricsWrapper) CreateQC(votes []*model.Vote) (*flow.QuorumCertifi
 	w.metrics.SignerProcessingDuration(time.Since(processStart))
 	return qc, err
 }
+
+func (w SignerMetricsWrapper) VerifyTimeout(signer *flow.Identity, sigData []byte, view uint64) (bool, error) {
+	processStart := time.Now()
+	valid, err := w.signer.VerifyTimeout(signer, sigData, view)
+	w.metrics.SignerProcessingDuration(time.Since(processStart))
+	return valid, err
+}
+
+func (w SignerMetricsWrapper) VerifyTC(signers flow.IdentityList, sigData []byte, view uint64) (bool, error) {
+	processStart := time.Now()
+	valid, err := w.signer.VerifyTC(signers, sigData, view)
+	w.metrics.SignerProcessingDuration(time.Since(processStart))
+	return valid, err
+}
+
+func (w SignerMetricsWrapper) CreateTimeout(view uint64, highestQC *flow.QuorumCertificate, highestTC *flow.TimeoutCertificate) (*model.TimeoutObject, error) {
+	processStart := time.Now()
+	timeout, err := w.signer.CreateTimeout(view, highestQC, highestTC)
+	w.metrics.SignerProcessingDuration(time.Since(processStart))
+	return timeout, err
+}
+
+func (w SignerMetricsWrapper) CreateTC(timeouts []*model.TimeoutObject) (*flow.TimeoutCertificate, error) {
+	processStart := time.Now()
+	tc, err := w.signer.CreateTC(timeouts)
+	w.metrics.SignerProcessingDuration(time.Since(processStart))
+	return tc, err
+}
@@ -19,11 +19,12 @@ type SingleSignerVerifier struct {
 // NewSingleSignerVerifier initializes a single signer with the given dependencies:
 // - the given hotstuff committee's state is used to retrieve public keys for the verifier;
 // - the given signer is used to generate signatures for the local node;
-// - the given signer ID is used as identifier for our signatures.
-func NewSingleSignerVerifier(committee hotstuff.Committee, signer module.AggregatingSigner, signerID flow.Identifier) *SingleSignerVerifier {
+// - the given signer ID is used as identifier for our signatures;
+// - the given chain ID is the chain whose timeouts are signed and verified.
+func NewSingleSignerVerifier(committee hotstuff.Committee, signer module.AggregatingSigner, signerID flow.Identifier, chainID flow.ChainID) *SingleSignerVerifier {
 	sc := &SingleSignerVerifier{
-		SingleVerifier: NewSingleVerifier(committee, signer),
-		SingleSigner:   NewSingleSigner(signer, signerID),
+		SingleVerifier: NewSingleVerifier(committee, signer, chainID),
+		SingleSigner:   NewSingleSigner(signer, signerID, chainID),
 	}
 	return sc
 }
@@ -33,12 +34,14 @@ func NewSingleSignerVerifier(committee hotstuff.Committee, signer module.Aggrega
 type SingleSigner struct {
 	signer   module.AggregatingSigner
 	signerID flow.Identifier
+	chainID  flow.ChainID
 }
 
-func NewSingleSigner(signer module.AggregatingSigner, signerID flow.Identifier) *SingleSigner {
+func NewSingleSigner(signer module.AggregatingSigner, signerID flow.Identifier, chainID flow.ChainID) *SingleSigner {
 	return &SingleSigner{
 		signer:   signer,
 		signerID: signerID,
+		chainID:  chainID,
 	}
 }
 
@@ -122,3 +125,60 @@ func (s *SingleSigner) CreateQC(votes []*model.Vote) (*flow.QuorumCertificate, e
 
 	return qc, nil
 }
+
+// CreateTimeout creates a timeout with a single signature for the given view.
+func (s *SingleSigner) CreateTimeout(view uint64, highestQC *flow.QuorumCertificate, highestTC *flow.TimeoutCertificate) (*model.TimeoutObject, error) {
+
+	// create the message to be signed and generate signature
+	msg := MakeTimeoutMessage(s.chainID, view)
+	sig, err := s.signer.Sign(msg)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate staking signature: %w", err)
+	}
+
+	// create the timeout
+	timeout := &model.TimeoutObject{
+		View:      view,
+		HighestQC: highestQC,
+		HighestTC: highestTC,
+		SignerID:  s.signerID,
+		SigData:   sig,
+	}
+
+	return timeout, nil
+}
+
+// CreateTC generates a timeout certificate with a single aggregated signature for the
+// given timeouts.
+func (s *SingleSigner) CreateTC(timeouts []*model.TimeoutObject) (*flow.TimeoutCertificate, error) {
+
+	// check the consistency of the timeouts
+	highestQC, err := checkTimeoutsValidity(timeouts)
+	if err != nil {
+		return nil, fmt.Errorf("timeouts are not valid: %w", err)
+	}
+
+	// collect all the timeout signatures
+	signerIDs := make([]flow.Identifier, 0, len(timeouts))
+	sigs := make([]crypto.Signature, 0, len(timeouts))
+	for _, timeout := range timeouts {
+		signerIDs = append(signerIDs, timeout.SignerID)
+		sigs = append(sigs, timeout.SigData)
+	}
+
+	// aggregate the signatures
+	aggSig, err := s.signer.Aggregate(sigs)
+	if err != nil {
+		return nil, fmt.Errorf("could not aggregate signatures: %w", err)
+	}
+
+	// create the TC
+	tc := &flow.TimeoutCertificate{
+		View:      timeouts[0].View,
+		HighestQC: highestQC,
+		SignerIDs: signerIDs,
+		SigData:   aggSig,
+	}
+
+	return tc, nil
+}
@@ -157,3 +157,100 @@ func TestSingleQC(t *testing.T) {
 	assert.False(t, valid, "QC with changed block view data should be invalid")
 	block.View--
 }
+
+func TestSingleTC(t *testing.T) {
+
+	identities := unittest.IdentityListFixture(4, unittest.WithRole(flow.RoleConsensus))
+	minShares := (len(identities)-1)/2 + 1
+	committeeState, stakingKeys, _ := MakeHotstuffCommitteeState(t, identities, false, epochCounter)
+	signers := MakeSigners(t, committeeState, identities.NodeIDs(), stakingKeys, nil)
+
+	// create timeouts, each replica knows a different QC
+	view := uint64(20)
+	var timeouts []*model.TimeoutObject
+	for i, signer := range signers {
+		highestQC := helper.MakeQC(t, helper.WithQCView(view-uint64(i)-1))
+		timeout, err := signer.CreateTimeout(view, highestQC, nil)
+		require.NoError(t, err)
+		timeouts = append(timeouts, timeout)
+	}
+
+	// should be able to verify a valid timeout
+	valid, err := signers[0].VerifyTimeout(identities[1], timeouts[1].SigData, view)
+	require.NoError(t, err)
+	assert.True(t, valid, "timeout should be valid")
+
+	// verification for a different view should fail
+	valid, err = signers[0].VerifyTimeout(identities[1], timeouts[1].SigData, view+1)
+	require.NoError(t, err)
+	assert.False(t, valid, "timeout with changed view should be invalid")
+
+	// should be able to create TC from timeouts and verify
+	tc, err := signers[0].CreateTC(timeouts[:minShares])
+	require.NoError(t, err, "should be able to create TC from valid timeouts")
+	assert.Equal(t, view, tc.View)
+	assert.Equal(t, timeouts[0].HighestQC, tc.HighestQC, "TC should include the highest QC of the timeouts")
+
+	// creation from different views should fail
+	timeouts[0].View++
+	_, err = signers[0].CreateTC(timeouts[:minShares])
+	assert.Error(t, err, "creating TC with mismatching view should fail")
+	timeouts[0].View--
+
+	// should be able to verify valid TC
+	valid, err = signers[0].VerifyTC(identities[:minShares], tc.SigData, view)
+	require.NoError(t, err)
+	assert.True(t, valid, "original TC should be valid")
+
+	// verification with with not enough signers is invalid
+	valid, err = signers[0].VerifyTC(identities[:minShares-1], tc.SigData, view)
+	require.NoError(t, err)
+	assert.False(t, valid, "verification with missing signer ID should not work")
+
+	// verification with changed view should fail
+	valid, err = signers[0].VerifyTC(identities[:minShares], tc.SigData, view+1)
+	require.NoError(t, err)
+	assert.False(t, valid, "TC with changed view should be invalid")
+}
+
+func TestSingleTimeoutChainID(t *testing.T) {
+
+	identities := unittest.IdentityListFixture(4, unittest.WithRole(flow.RoleConsensus))
+	minShares := (len(identities)-1)/2 + 1
+	committeeState, stakingKeys, _ := MakeHotstuffCommitteeState(t, identities, false, epochCounter)
+	signers := MakeSigners(t, committeeState, identities.NodeIDs(), stakingKeys, nil)
+
+	// the same committee on another chain, e.g. the cluster chain of the next epoch
+	other := MakeStakingSigner(t, committeeState, identities[0].NodeID, stakingKeys[0], flow.Localnet)
+
+	view := uint64(20)
+	var timeouts []*model.TimeoutObject
+	for _, signer := range signers {
+		highestQC := helper.MakeQC(t, helper.WithQCView(view-1))
+		timeout, err := signer.CreateTimeout(view, highestQC, nil)
+		require.NoError(t, err)
+		timeouts = append(timeouts, timeout)
+	}
+
+	// timeouts for the same view can't be replayed on another chain
+	valid, err := other.VerifyTimeout(identities[1], timeouts[1].SigData, view)
+	require.NoError(t, err)
+	assert.False(t, valid, "timeout from another chain should be invalid")
+
+	// neither can TCs
+	tc, err := signers[0].CreateTC(timeouts[:minShares])
+	require.NoError(t, err)
+	valid, err = other.VerifyTC(identities[:minShares], tc.SigData, view)
+	require.NoError(t, err)
+	assert.False(t, valid, "TC from another chain should be invalid")
+
+	// a timeout created on the other chain is only valid there
+	timeout, err := other.CreateTimeout(view, helper.MakeQC(t, helper.WithQCView(view-1)), nil)
+	require.NoError(t, err)
+	valid, err = other.VerifyTimeout(identities[0], timeout.SigData, view)
+	require.NoError(t, err)
+	assert.True(t, valid, "timeout should be valid on its own chain")
+	valid, err = signers[1].VerifyTimeout(identities[0], timeout.SigData, view)
+	require.NoError(t, err)
+	assert.False(t, valid, "timeout from another chain should be invalid")
+}
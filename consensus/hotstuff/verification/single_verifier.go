@@ -15,16 +15,19 @@ type SingleVerifier struct {
 	committee      hotstuff.Committee
 	verifier       module.AggregatingVerifier
 	keysAggregator *stakingKeysAggregator
+	chainID        flow.ChainID
 }
 
 // NewSingleVerifier creates a new single verifier with the given dependencies:
 // - the hotstuff committee's state is used to get the public staking key for signers;
 // - the verifier is used to verify the signatures against the message;
-func NewSingleVerifier(committee hotstuff.Committee, verifier module.AggregatingVerifier) *SingleVerifier {
+// - the chain ID is the chain whose timeouts are verified.
+func NewSingleVerifier(committee hotstuff.Committee, verifier module.AggregatingVerifier, chainID flow.ChainID) *SingleVerifier {
 	s := &SingleVerifier{
 		committee:      committee,
 		verifier:       verifier,
 		keysAggregator: newStakingKeysAggregator(),
+		chainID:        chainID,
 	}
 	return s
 }
@@ -61,3 +64,36 @@ func (s *SingleVerifier) VerifyQC(signers flow.IdentityList, sigData []byte, blo
 
 	return valid, nil
 }
+
+// VerifyTimeout verifies a timeout with a single signature as signature data.
+func (s *SingleVerifier) VerifyTimeout(signer *flow.Identity, sigData []byte, view uint64) (bool, error) {
+
+	// create the message we verify against and check signature
+	msg := MakeTimeoutMessage(s.chainID, view)
+	valid, err := s.verifier.Verify(msg, sigData, signer.StakingPubKey)
+	if err != nil {
+		return false, fmt.Errorf("could not verify signature: %w", err)
+	}
+
+	return valid, nil
+}
+
+// VerifyTC verifies a TC with a single aggregated signature as signature data.
+func (s *SingleVerifier) VerifyTC(signers flow.IdentityList, sigData []byte, view uint64) (bool, error) {
+
+	// create the message we verify against and check signature
+	msg := MakeTimeoutMessage(s.chainID, view)
+
+	// compute the aggregated key of signers
+	aggregatedKey, err := s.keysAggregator.aggregatedStakingKey(signers)
+	if err != nil {
+		return false, fmt.Errorf("could not compute BLS key: %w", err)
+	}
+
+	valid, err := s.verifier.Verify(msg, sigData, aggregatedKey)
+	if err != nil {
+		return false, fmt.Errorf("could not verify signature: %w", err)
+	}
+
+	return valid, nil
+}
@@ -6,7 +6,8 @@ import (
 )
 
 // Verifier is the component responsible for the cryptographic integrity of
-// votes, proposals and QC's against w.r.t. the block they are signing.
+// votes, proposals and QC's against w.r.t. the block they are signing, as well
+// as timeouts and TC's w.r.t. the view they are signing.
 // Overall, there are two criteria for the validity of a vote and QC:
 //  (1) the signer ID(s) must correspond to authorized consensus participants
 //  (2) the signature must be cryptographically valid.
@@ -41,4 +42,25 @@ type Verifier interface {
 	// * unexpected errors should be treated as symptoms of bugs or uncovered
 	//   edge cases in the logic (i.e. as fatal)
 	VerifyQC(voters flow.IdentityList, sigData []byte, block *model.Block) (bool, error)
+
+	// VerifyTimeout checks the validity of a timeout for the given view.
+	// The first return value indicates whether `sigData` is a valid signature
+	// from the provided signer identity. It is the responsibility of the
+	// calling code to ensure that `signer` is authorized to time out.
+	// The implementation returns the following sentinel errors:
+	// * verification.ErrInvalidFormat if the signature has an incompatible format.
+	// * unexpected errors should be treated as symptoms of bugs or uncovered
+	//   edge cases in the logic (i.e. as fatal)
+	VerifyTimeout(signer *flow.Identity, sigData []byte, view uint64) (bool, error)
+
+	// VerifyTC checks the validity of a TC for the given view.
+	// The first return value indicates whether `sigData` is a valid signature
+	// from the provided signer identities. It is the responsibility of the
+	// calling code to ensure that `signers` only contains authorized nodes
+	// (without duplicates).
+	// The implementation returns the following sentinel errors:
+	// * verification.ErrInvalidFormat if the signature has an incompatible format.
+	// * unexpected errors should be treated as symptoms of bugs or uncovered
+	//   edge cases in the logic (i.e. as fatal)
+	VerifyTC(signers flow.IdentityList, sigData []byte, view uint64) (bool, error)
 }
@@ -5,7 +5,8 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 )
 
-// VoteAggregator aggregates votes and produces quorum certificates.
+// VoteAggregator aggregates votes and produces quorum certificates. It also
+// aggregates timeouts and produces timeout certificates.
 type VoteAggregator interface {
 
 	// StorePendingVote is used to store a vote for a block for which we don't
@@ -24,6 +25,15 @@ type VoteAggregator interface {
 	// case enough votes can be accumulated for it.
 	BuildQCOnReceivedBlock(block *model.Block) (*flow.QuorumCertificate, bool, error)
 
+	// StoreTimeoutAndBuildTC will store a timeout and build the TC for the
+	// timed out view if enough timeouts can be accumulated. Timeouts for views
+	// far ahead of the current view are not collected for building TCs.
+	StoreTimeoutAndBuildTC(timeout *model.TimeoutObject, curView uint64) (*flow.TimeoutCertificate, bool, error)
+
+	// ViewToJoin returns the highest view above the given view, which replicas with more than
+	// a third of the stake have timed out in or have timed out in higher views.
+	ViewToJoin(curView uint64) (uint64, bool)
+
 	// PruneByView will remove any data held for the provided view.
 	PruneByView(view uint64)
 }
@@ -0,0 +1,103 @@
+package voteaggregator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/module/signature"
+)
+
+// signerTimeout is the highest view a signer has timed out in, together with the signer's stake
+type signerTimeout struct {
+	view       uint64
+	stake      uint64
+	totalStake uint64 // total stake of the committee the timeout refers to
+}
+
+// TimeoutStatus keeps track of the timeouts for the same view
+type TimeoutStatus struct {
+	committee hotstuff.Committee
+	signer    hotstuff.SignerVerifier
+	view      uint64
+	// assume timeouts are all valid to build TC
+	timeouts map[flow.Identifier]*model.TimeoutObject
+}
+
+// NewTimeoutStatus creates a new Timeout Status instance
+func NewTimeoutStatus(view uint64, committee hotstuff.Committee, signer hotstuff.SignerVerifier) *TimeoutStatus {
+	return &TimeoutStatus{
+		committee: committee,
+		signer:    signer,
+		view:      view,
+		timeouts:  make(map[flow.Identifier]*model.TimeoutObject),
+	}
+}
+
+// AddTimeout adds the timeout to the list, assuming it is valid.
+// A replica re-broadcasts its timeout while it is waiting for a QC or TC. Hence, only
+// one timeout is kept per signer, and we keep the timeout with the highest QC.
+func (ts *TimeoutStatus) AddTimeout(timeout *model.TimeoutObject) {
+	existing, exists := ts.timeouts[timeout.SignerID]
+	if exists && timeout.HighestQC.View <= existing.HighestQC.View {
+		return
+	}
+	ts.timeouts[timeout.SignerID] = timeout
+}
+
+// TryBuildTC returns a TC if the existing timeouts are enough to build a TC.
+// The TC references the highest QC of the timeouts, and the validator checks the TC
+// against the committee at the block of that QC. Hence, the same committee determines
+// here which timeouts are counted and how much stake is required.
+func (ts *TimeoutStatus) TryBuildTC() (*flow.TimeoutCertificate, bool, error) {
+	if len(ts.timeouts) == 0 {
+		return nil, false, nil
+	}
+
+	// find the highest QC, which will be included in the TC
+	var highestQC *flow.QuorumCertificate
+	for _, timeout := range ts.timeouts {
+		if highestQC == nil || timeout.HighestQC.View > highestQC.View {
+			highestQC = timeout.HighestQC
+		}
+	}
+
+	// retrieve the committee at the block of the highest QC
+	identities, err := ts.committee.Identities(highestQC.BlockID, filter.Any)
+	if err != nil {
+		return nil, false, fmt.Errorf("error retrieving consensus participants at block %x: %w", highestQC.BlockID, err)
+	}
+	participants := identities.Lookup()
+
+	// only the timeouts of participants at the reference block count towards the TC
+	timeouts := make([]*model.TimeoutObject, 0, len(ts.timeouts))
+	accumulatedStake := uint64(0)
+	for signerID, timeout := range ts.timeouts {
+		participant, ok := participants[signerID]
+		if !ok {
+			continue
+		}
+		timeouts = append(timeouts, timeout)
+		accumulatedStake += participant.Stake
+	}
+
+	// check if there are enough timeouts to build TC, a TC requires the same stake threshold as a QC
+	stakeThreshold := hotstuff.ComputeStakeThresholdForBuildingQC(identities.TotalStake())
+	if accumulatedStake < stakeThreshold {
+		return nil, false, nil
+	}
+
+	// build the aggregated signature
+	tc, err := ts.signer.CreateTC(timeouts)
+	if errors.Is(err, signature.ErrInsufficientShares) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("could not create TC from timeouts: %w", err)
+	}
+
+	return tc, true, nil
+}
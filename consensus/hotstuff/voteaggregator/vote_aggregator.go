@@ -1,7 +1,9 @@
 package voteaggregator
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/onflow/flow-go/consensus/hotstuff"
 	"github.com/onflow/flow-go/consensus/hotstuff/model"
@@ -9,6 +11,10 @@ import (
 	"github.com/onflow/flow-go/model/flow/filter"
 )
 
+// MaxTimeoutViewsAhead is the number of views above the current view, for which timeouts
+// are collected to build TCs.
+const MaxTimeoutViewsAhead = 10
+
 // VoteAggregator stores the votes and aggregates them into a QC when enough votes have been collected
 type VoteAggregator struct {
 	notifier              hotstuff.Consumer
@@ -22,6 +28,9 @@ type VoteAggregator struct {
 	createdQC             map[flow.Identifier]*flow.QuorumCertificate // keeps track of QCs that have been made for blocks
 	blockIDToVotingStatus map[flow.Identifier]*VotingStatus           // keeps track of accumulated votes and stakes for blocks
 	proposerVotes         map[flow.Identifier]*model.Vote             // holds the votes of block proposers, so we can avoid passing around proposals everywhere
+	viewToTimeoutStatus   map[uint64]*TimeoutStatus                   // keeps track of accumulated timeouts and stakes for views
+	createdTC             map[uint64]*flow.TimeoutCertificate         // keeps track of TCs that have been made for views
+	signerToTimeout       map[flow.Identifier]*signerTimeout          // keeps track of the highest timed out view of each signer, for joining views
 }
 
 // New creates an instance of vote aggregator
@@ -38,6 +47,9 @@ func New(notifier hotstuff.Consumer, highestPrunedView uint64, committee hotstuf
 		createdQC:             make(map[flow.Identifier]*flow.QuorumCertificate),
 		blockIDToVotingStatus: make(map[flow.Identifier]*VotingStatus),
 		proposerVotes:         make(map[flow.Identifier]*model.Vote),
+		viewToTimeoutStatus:   make(map[uint64]*TimeoutStatus),
+		createdTC:             make(map[uint64]*flow.TimeoutCertificate),
+		signerToTimeout:       make(map[flow.Identifier]*signerTimeout),
 	}
 }
 
@@ -216,7 +228,108 @@ func (va *VoteAggregator) BuildQCOnReceivedBlock(block *model.Block) (*flow.Quor
 	return qc, built, nil
 }
 
-// PruneByView will delete all votes equal or below to the given view, as well as related indexes.
+// StoreTimeoutAndBuildTC stores the timeout assuming the caller has validated it, and returns a TC
+// if there are timeouts with enough stakes for the timed out view.
+// It's idempotent. Meaning, calling it again with the same timeout returns the same result.
+// The VoteAggregator builds a TC as soon as the number of timeouts allow this.
+// While subsequent timeouts (past the required threshold) are not included in the TC anymore,
+// VoteAggregator ALWAYS returns the same TC as the one returned before.
+// Timeouts for views more than MaxTimeoutViewsAhead above the given current view are only
+// taken into account for joining views, as the timeouts stored per view are only pruned by
+// finalization and a byzantine replica could otherwise fill them with arbitrary views.
+func (va *VoteAggregator) StoreTimeoutAndBuildTC(timeout *model.TimeoutObject, curView uint64) (*flow.TimeoutCertificate, bool, error) {
+	// if the TC for the view has been created before, return the TC
+	oldTC, built := va.createdTC[timeout.View]
+	if built {
+		return oldTC, true, nil
+	}
+
+	// ignore stale timeouts
+	if va.isTimeoutStale(timeout) {
+		return nil, false, nil
+	}
+
+	// the signer is determined at the block referenced by the timeout's highest QC
+	blockID := timeout.HighestQC.BlockID
+	signer, err := va.committee.Identity(blockID, timeout.SignerID)
+	if errors.Is(err, model.ErrInvalidSigner) {
+		// does not report invalid timeout as an error, notify consumers instead
+		va.notifier.OnInvalidTimeoutDetected(timeout)
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("error retrieving signer Identity at block %x: %w", blockID, err)
+	}
+
+	err = va.updateSignerTimeout(timeout, signer)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not update timeout of signer %x: %w", timeout.SignerID, err)
+	}
+
+	// do not keep track of timeouts for views far ahead of the current view
+	if timeout.View > curView+MaxTimeoutViewsAhead {
+		return nil, false, nil
+	}
+
+	// update existing timeout status or create a new one
+	timeoutStatus, exists := va.viewToTimeoutStatus[timeout.View]
+	if !exists {
+		timeoutStatus = NewTimeoutStatus(timeout.View, va.committee, va.signer)
+		va.viewToTimeoutStatus[timeout.View] = timeoutStatus
+	}
+	timeoutStatus.AddTimeout(timeout)
+
+	// try to build the TC with existing timeouts
+	tc, built, err := timeoutStatus.TryBuildTC()
+	if err != nil {
+		return nil, false, fmt.Errorf("could not build TC: %w", err)
+	}
+	if !built {
+		return nil, false, nil
+	}
+
+	va.createdTC[timeout.View] = tc
+	va.notifier.OnTcConstructedFromTimeouts(tc)
+	return tc, true, nil
+}
+
+// ViewToJoin returns the highest view above the given view, for which replicas with more than a third
+// of the stake have timed out in that view or in higher views. As at least one honest replica has reached
+// the returned view, a replica lagging behind can join it, even though there is no QC or TC for the views
+// in between. Only the highest view, which each signer has timed out in, is taken into account.
+// The committee can change between views, the stake threshold for a view is hence determined by
+// the committees the timeouts in that view and in higher views refer to. The largest of them is
+// used, so that a byzantine signer can't lower the threshold by referring to an old committee.
+func (va *VoteAggregator) ViewToJoin(curView uint64) (uint64, bool) {
+
+	// collect the timeouts of the signers that are ahead of us, in descending order of views
+	ahead := make([]*signerTimeout, 0, len(va.signerToTimeout))
+	for _, st := range va.signerToTimeout {
+		if st.view > curView {
+			ahead = append(ahead, st)
+		}
+	}
+	sort.Slice(ahead, func(i int, j int) bool {
+		return ahead[i].view > ahead[j].view
+	})
+
+	// accumulate the stake until the signers, which have timed out in the view
+	// or higher views, have sufficient stake
+	accumulatedStake := uint64(0)
+	totalStake := uint64(0)
+	for _, st := range ahead {
+		accumulatedStake += st.stake
+		if st.totalStake > totalStake {
+			totalStake = st.totalStake
+		}
+		if accumulatedStake >= hotstuff.ComputeStakeThresholdForJoiningView(totalStake) {
+			return st.view, true
+		}
+	}
+	return 0, false
+}
+
+// PruneByView will delete all votes and timeouts equal or below to the given view, as well as related indexes.
 func (va *VoteAggregator) PruneByView(view uint64) {
 	if view <= va.highestPrunedView {
 		return
@@ -231,6 +344,13 @@ func (va *VoteAggregator) PruneByView(view uint64) {
 		}
 		delete(va.viewToBlockIDSet, i)
 		delete(va.viewToVoteID, i)
+		delete(va.viewToTimeoutStatus, i)
+		delete(va.createdTC, i)
+	}
+	for signerID, st := range va.signerToTimeout {
+		if st.view <= view {
+			delete(va.signerToTimeout, signerID)
+		}
 	}
 	va.highestPrunedView = view
 }
@@ -389,3 +509,26 @@ func (va *VoteAggregator) isVoteStale(vote *model.Vote) bool {
 func (va *VoteAggregator) isBlockStale(block *model.Block) bool {
 	return block.View <= va.highestPrunedView
 }
+
+// updateSignerTimeout keeps track of the highest view the signer has timed out in, together with
+// the total stake of the committee at the block referenced by the timeout's highest QC
+func (va *VoteAggregator) updateSignerTimeout(timeout *model.TimeoutObject, signer *flow.Identity) error {
+	st, exists := va.signerToTimeout[timeout.SignerID]
+	if exists && st.view >= timeout.View {
+		return nil
+	}
+	identities, err := va.committee.Identities(timeout.HighestQC.BlockID, filter.Any)
+	if err != nil {
+		return fmt.Errorf("error retrieving consensus participants at block %x: %w", timeout.HighestQC.BlockID, err)
+	}
+	va.signerToTimeout[timeout.SignerID] = &signerTimeout{
+		view:       timeout.View,
+		stake:      signer.Stake,
+		totalStake: identities.TotalStake(),
+	}
+	return nil
+}
+
+func (va *VoteAggregator) isTimeoutStale(timeout *model.TimeoutObject) bool {
+	return timeout.View <= va.highestPrunedView
+}
@@ -127,6 +127,23 @@ func (as *AggregatorSuite) SetupTest() {
 		},
 	)
 
+	as.signer.On("CreateTC", mock.AnythingOfType("[]*model.TimeoutObject")).Return(
+		func(timeouts []*model.TimeoutObject) *flow.TimeoutCertificate {
+			tc := &flow.TimeoutCertificate{
+				View:    timeouts[0].View,
+				SigData: []byte{},
+			}
+			for _, t := range timeouts {
+				tc.SignerIDs = append(tc.SignerIDs, t.SignerID)
+				if tc.HighestQC == nil || t.HighestQC.View > tc.HighestQC.View {
+					tc.HighestQC = t.HighestQC
+				}
+			}
+			return tc
+		},
+		nil,
+	)
+
 	as.validator = validator.New(as.committee, as.forks, as.signer) // create a real validator
 	as.notifier = &mocks.Consumer{}                                 // create a mock notification Consumer
 	// create the aggregator
@@ -919,6 +936,279 @@ func (as *AggregatorSuite) TestSufficientRBSig() {
 	as.notifier.AssertExpectations(as.T())
 }
 
+// TIMEOUTS
+// assume there are 7 nodes, meaning that the threshold is 5
+// a TC should not be built with insufficient timeouts
+func (as *AggregatorSuite) TestInsufficientTimeouts() {
+	testView := uint64(5)
+	qcBlock := newMockBlock(as, testView-1, as.participants[0].NodeID)
+	for i := 0; i < 4; i++ {
+		timeout := as.newMockTimeout(testView, qcBlock.Block, as.participants[i].NodeID)
+		tc, built, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, testView)
+		require.NoError(as.T(), err)
+		require.False(as.T(), built)
+		require.Nil(as.T(), tc)
+	}
+	as.notifier.AssertNotCalled(as.T(), "OnTcConstructedFromTimeouts", mock.Anything)
+}
+
+// a TC should be built as soon as the timeouts reach the threshold, and the same TC
+// should be returned for subsequent timeouts
+func (as *AggregatorSuite) TestSufficientTimeouts() {
+	testView := uint64(5)
+	qcBlock := newMockBlock(as, testView-1, as.participants[0].NodeID)
+	for i := 0; i < 4; i++ {
+		timeout := as.newMockTimeout(testView, qcBlock.Block, as.participants[i].NodeID)
+		_, built, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, testView)
+		require.NoError(as.T(), err)
+		require.False(as.T(), built)
+	}
+
+	as.notifier.On("OnTcConstructedFromTimeouts", mock.Anything).Return().Once()
+	timeout := as.newMockTimeout(testView, qcBlock.Block, as.participants[4].NodeID)
+	tc, built, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, testView)
+	require.NoError(as.T(), err)
+	require.True(as.T(), built)
+	require.Equal(as.T(), testView, tc.View)
+	require.Len(as.T(), tc.SignerIDs, 5)
+
+	timeout = as.newMockTimeout(testView, qcBlock.Block, as.participants[5].NodeID)
+	sameTC, built, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, testView)
+	require.NoError(as.T(), err)
+	require.True(as.T(), built)
+	require.Equal(as.T(), tc, sameTC)
+	as.notifier.AssertExpectations(as.T())
+}
+
+// repeated timeouts from the same signer should only be counted once, but the timeout
+// with the highest QC should be included in the TC
+func (as *AggregatorSuite) TestDuplicateTimeouts() {
+	testView := uint64(5)
+	lowBlock := newMockBlock(as, testView-2, as.participants[0].NodeID)
+	highBlock := newMockBlock(as, testView-1, as.participants[0].NodeID)
+	for i := 0; i < 5; i++ {
+		timeout := as.newMockTimeout(testView, lowBlock.Block, as.participants[0].NodeID)
+		_, built, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, testView)
+		require.NoError(as.T(), err)
+		require.False(as.T(), built)
+	}
+	timeout := as.newMockTimeout(testView, highBlock.Block, as.participants[0].NodeID)
+	_, built, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, testView)
+	require.NoError(as.T(), err)
+	require.False(as.T(), built)
+
+	for i := 1; i < 4; i++ {
+		timeout := as.newMockTimeout(testView, lowBlock.Block, as.participants[i].NodeID)
+		_, built, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, testView)
+		require.NoError(as.T(), err)
+		require.False(as.T(), built)
+	}
+
+	as.notifier.On("OnTcConstructedFromTimeouts", mock.Anything).Return().Once()
+	timeout = as.newMockTimeout(testView, lowBlock.Block, as.participants[4].NodeID)
+	tc, built, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, testView)
+	require.NoError(as.T(), err)
+	require.True(as.T(), built)
+	require.Equal(as.T(), highBlock.Block.BlockID, tc.HighestQC.BlockID)
+	as.notifier.AssertExpectations(as.T())
+}
+
+// timeouts for pruned views should be ignored, and pruning should remove timeouts and TCs
+func (as *AggregatorSuite) TestPruneTimeouts() {
+	testView := uint64(5)
+	qcBlock := newMockBlock(as, testView-1, as.participants[0].NodeID)
+	as.notifier.On("OnTcConstructedFromTimeouts", mock.Anything).Return().Once()
+	for i := 0; i < 5; i++ {
+		timeout := as.newMockTimeout(testView, qcBlock.Block, as.participants[i].NodeID)
+		_, _, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, testView)
+		require.NoError(as.T(), err)
+	}
+	require.Len(as.T(), as.aggregator.createdTC, 1)
+	require.Len(as.T(), as.aggregator.viewToTimeoutStatus, 1)
+
+	as.aggregator.PruneByView(testView)
+	require.Len(as.T(), as.aggregator.createdTC, 0)
+	require.Len(as.T(), as.aggregator.viewToTimeoutStatus, 0)
+
+	timeout := as.newMockTimeout(testView, qcBlock.Block, as.participants[5].NodeID)
+	tc, built, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, testView)
+	require.NoError(as.T(), err)
+	require.False(as.T(), built)
+	require.Nil(as.T(), tc)
+	require.Len(as.T(), as.aggregator.viewToTimeoutStatus, 0)
+	as.notifier.AssertExpectations(as.T())
+}
+
+// the view to join is the highest view, for which signers with more than a third of the stake
+// have timed out in that view or higher views
+func (as *AggregatorSuite) TestViewToJoin() {
+	curView := uint64(3)
+	qcBlock := newMockBlock(as, curView-1, as.participants[0].NodeID)
+
+	// no timeouts, nothing to join
+	_, found := as.aggregator.ViewToJoin(curView)
+	require.False(as.T(), found)
+
+	// two out of seven signers have insufficient stake
+	for i, view := range []uint64{8, 6} {
+		timeout := as.newMockTimeout(view, qcBlock.Block, as.participants[i].NodeID)
+		_, _, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, curView)
+		require.NoError(as.T(), err)
+	}
+	_, found = as.aggregator.ViewToJoin(curView)
+	require.False(as.T(), found)
+
+	// three out of seven signers have sufficient stake, all of them have reached view 6
+	timeout := as.newMockTimeout(7, qcBlock.Block, as.participants[2].NodeID)
+	_, _, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, curView)
+	require.NoError(as.T(), err)
+	view, found := as.aggregator.ViewToJoin(curView)
+	require.True(as.T(), found)
+	require.Equal(as.T(), uint64(6), view)
+
+	// only timeouts for views above the current view are taken into account
+	_, found = as.aggregator.ViewToJoin(6)
+	require.False(as.T(), found)
+
+	// a lower timeout of a signer does not replace its higher timeout
+	timeout = as.newMockTimeout(5, qcBlock.Block, as.participants[0].NodeID)
+	_, _, err = as.aggregator.StoreTimeoutAndBuildTC(timeout, curView)
+	require.NoError(as.T(), err)
+	view, found = as.aggregator.ViewToJoin(curView)
+	require.True(as.T(), found)
+	require.Equal(as.T(), uint64(6), view)
+
+	// pruning removes the timeouts of the signers
+	as.aggregator.PruneByView(6)
+	require.Len(as.T(), as.aggregator.signerToTimeout, 2)
+	_, found = as.aggregator.ViewToJoin(curView)
+	require.False(as.T(), found)
+}
+
+// the stake threshold for joining a view should be determined by the committees the timeouts
+// refer to, rather than by the committee of the first timeout received
+func (as *AggregatorSuite) TestViewToJoinCommitteeChange() {
+	curView := uint64(3)
+	lowBlock := newMockBlock(as, curView-2, as.participants[0].NodeID)
+
+	// the committee at the high block has four additional participants
+	highCommittee := append(as.participants.Copy(), unittest.IdentityListFixture(4, unittest.WithRole(flow.RoleConsensus))...)
+	highSnapshot := &protomock.Snapshot{}
+	highSnapshot.On("Identities", mock.Anything).Return(
+		func(selector flow.IdentityFilter) flow.IdentityList {
+			return highCommittee.Filter(selector)
+		},
+		nil,
+	)
+	for _, participant := range highCommittee {
+		highSnapshot.On("Identity", participant.NodeID).Return(participant, nil)
+	}
+	highBlock := &model.Block{
+		View:       curView - 1,
+		BlockID:    unittest.IdentifierFixture(),
+		ProposerID: highCommittee[0].NodeID,
+	}
+	as.protocol.On("AtBlockID", highBlock.BlockID).Return(highSnapshot)
+
+	// three signers would be sufficient for the committee at the low block, which the
+	// first timeout refers to, but four out of eleven signers are required at the high block
+	timeout := as.newMockTimeout(8, lowBlock.Block, as.participants[0].NodeID)
+	_, _, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, curView)
+	require.NoError(as.T(), err)
+	for i := 1; i < 3; i++ {
+		timeout := as.newMockTimeout(7, highBlock, as.participants[i].NodeID)
+		_, _, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, curView)
+		require.NoError(as.T(), err)
+	}
+	_, found := as.aggregator.ViewToJoin(curView)
+	require.False(as.T(), found)
+
+	timeout = as.newMockTimeout(6, lowBlock.Block, as.participants[3].NodeID)
+	_, _, err = as.aggregator.StoreTimeoutAndBuildTC(timeout, curView)
+	require.NoError(as.T(), err)
+	view, found := as.aggregator.ViewToJoin(curView)
+	require.True(as.T(), found)
+	require.Equal(as.T(), uint64(6), view)
+}
+
+// timeouts for views far ahead of the current view should not be collected for building TCs,
+// but should still be taken into account for joining views
+func (as *AggregatorSuite) TestFarFutureTimeouts() {
+	curView := uint64(3)
+	testView := curView + MaxTimeoutViewsAhead + 1
+	qcBlock := newMockBlock(as, curView-1, as.participants[0].NodeID)
+	for i := 0; i < 5; i++ {
+		timeout := as.newMockTimeout(testView, qcBlock.Block, as.participants[i].NodeID)
+		tc, built, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, curView)
+		require.NoError(as.T(), err)
+		require.False(as.T(), built)
+		require.Nil(as.T(), tc)
+	}
+	require.Len(as.T(), as.aggregator.viewToTimeoutStatus, 0)
+	as.notifier.AssertNotCalled(as.T(), "OnTcConstructedFromTimeouts", mock.Anything)
+
+	view, found := as.aggregator.ViewToJoin(curView)
+	require.True(as.T(), found)
+	require.Equal(as.T(), testView, view)
+}
+
+// the stake threshold for building a TC should be determined by the committee at the block
+// of the TC's highest QC, which is the committee the TC is validated against
+func (as *AggregatorSuite) TestTimeoutsReferenceHighestQC() {
+	testView := uint64(5)
+	lowBlock := newMockBlock(as, testView-2, as.participants[0].NodeID)
+
+	// the committee at the high block has replaced the first two participants by four new ones
+	highCommittee := append(as.participants[2:].Copy(), unittest.IdentityListFixture(4, unittest.WithRole(flow.RoleConsensus))...)
+	highSnapshot := &protomock.Snapshot{}
+	highSnapshot.On("Identities", mock.Anything).Return(
+		func(selector flow.IdentityFilter) flow.IdentityList {
+			return highCommittee.Filter(selector)
+		},
+		nil,
+	)
+	for _, participant := range highCommittee {
+		highSnapshot.On("Identity", participant.NodeID).Return(participant, nil)
+	}
+	highBlock := &model.Block{
+		View:       testView - 1,
+		BlockID:    unittest.IdentifierFixture(),
+		ProposerID: highCommittee[0].NodeID,
+	}
+	as.protocol.On("AtBlockID", highBlock.BlockID).Return(highSnapshot)
+
+	// five out of seven participants at the low block have timed out, but only three of
+	// them are participants at the high block, which is referenced by the highest QC
+	timeout := as.newMockTimeout(testView, highBlock, as.participants[2].NodeID)
+	_, built, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, testView)
+	require.NoError(as.T(), err)
+	require.False(as.T(), built)
+	for _, i := range []int{0, 1, 3, 4} {
+		timeout := as.newMockTimeout(testView, lowBlock.Block, as.participants[i].NodeID)
+		_, built, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, testView)
+		require.NoError(as.T(), err)
+		require.False(as.T(), built)
+	}
+
+	// seven out of nine participants at the high block are required
+	for _, participant := range highCommittee[5:8] {
+		timeout := as.newMockTimeout(testView, highBlock, participant.NodeID)
+		_, built, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, testView)
+		require.NoError(as.T(), err)
+		require.False(as.T(), built)
+	}
+	as.notifier.On("OnTcConstructedFromTimeouts", mock.Anything).Return().Once()
+	timeout = as.newMockTimeout(testView, highBlock, highCommittee[8].NodeID)
+	tc, built, err := as.aggregator.StoreTimeoutAndBuildTC(timeout, testView)
+	require.NoError(as.T(), err)
+	require.True(as.T(), built)
+	require.Equal(as.T(), highBlock.BlockID, tc.HighestQC.BlockID)
+	require.Len(as.T(), tc.SignerIDs, 7)
+	require.NotContains(as.T(), tc.SignerIDs, as.participants[0].NodeID)
+	require.NotContains(as.T(), tc.SignerIDs, as.participants[1].NodeID)
+	as.notifier.AssertExpectations(as.T())
+}
+
 func newMockBlock(as *AggregatorSuite, view uint64, proposerID flow.Identifier) *model.Proposal {
 	block := &model.Block{
 		View:       view,
@@ -942,6 +1232,15 @@ func (as *AggregatorSuite) newMockVote(view uint64, blockID flow.Identifier, sig
 	}
 }
 
+func (as *AggregatorSuite) newMockTimeout(view uint64, qcBlock *model.Block, signerID flow.Identifier) *model.TimeoutObject {
+	return &model.TimeoutObject{
+		View:      view,
+		HighestQC: &flow.QuorumCertificate{View: qcBlock.View, BlockID: qcBlock.BlockID},
+		SignerID:  signerID,
+		SigData:   []byte{},
+	}
+}
+
 func getStateLength(aggregator *VoteAggregator) (uint64, int, int, int, int) {
 	return aggregator.highestPrunedView, len(aggregator.viewToBlockIDSet), len(aggregator.viewToVoteID), len(aggregator.pendingVotes.votes), len(aggregator.blockIDToVotingStatus)
 }
@@ -188,6 +188,8 @@ func (c *Conduit) Multicast(event interface{}, num uint, targetIDs ...flow.Ident
 	return c.net.multicast(event, c.channel, num, targetIDs...)
 }
 
+func (c *Conduit) ReportInvalidInput(flow.Identifier) {}
+
 func (c *Conduit) Close() error {
 	if c.ctx.Err() != nil {
 		return fmt.Errorf("conduit closed")
@@ -38,6 +38,33 @@ func (*Signer) CreateQC(votes []*model.Vote) (*flow.QuorumCertificate, error) {
 	}
 	return qc, nil
 }
+func (s *Signer) CreateTimeout(view uint64, highestQC *flow.QuorumCertificate, highestTC *flow.TimeoutCertificate) (*model.TimeoutObject, error) {
+	timeout := &model.TimeoutObject{
+		View:      view,
+		HighestQC: highestQC,
+		HighestTC: highestTC,
+		SignerID:  s.localID,
+		SigData:   nil,
+	}
+	return timeout, nil
+}
+func (*Signer) CreateTC(timeouts []*model.TimeoutObject) (*flow.TimeoutCertificate, error) {
+	signerIDs := make([]flow.Identifier, 0, len(timeouts))
+	highestQC := timeouts[0].HighestQC
+	for _, timeout := range timeouts {
+		signerIDs = append(signerIDs, timeout.SignerID)
+		if timeout.HighestQC.View > highestQC.View {
+			highestQC = timeout.HighestQC
+		}
+	}
+	tc := &flow.TimeoutCertificate{
+		View:      timeouts[0].View,
+		HighestQC: highestQC,
+		SignerIDs: signerIDs,
+		SigData:   nil,
+	}
+	return tc, nil
+}
 
 func (*Signer) VerifyVote(voterID *flow.Identity, sigData []byte, block *model.Block) (bool, error) {
 	return true, nil
@@ -46,3 +73,11 @@ func (*Signer) VerifyVote(voterID *flow.Identity, sigData []byte, block *model.B
 func (*Signer) VerifyQC(voters flow.IdentityList, sigData []byte, block *model.Block) (bool, error) {
 	return true, nil
 }
+
+func (*Signer) VerifyTimeout(signer *flow.Identity, sigData []byte, view uint64) (bool, error) {
+	return true, nil
+}
+
+func (*Signer) VerifyTC(signers flow.IdentityList, sigData []byte, view uint64) (bool, error) {
+	return true, nil
+}
@@ -52,6 +52,7 @@ func NewParticipant(
 		TimeoutIncreaseFactor:      defTimeout.TimeoutIncrease,
 		TimeoutDecreaseFactor:      defTimeout.TimeoutDecrease,
 		BlockRateDelay:             time.Duration(defTimeout.BlockRateDelayMS) * time.Millisecond,
+		TCActivationView:           TCActivationDisabled,
 	}
 
 	// apply the configuration options
@@ -108,7 +109,10 @@ func NewParticipant(
 
 	// initialize the pacemaker
 	controller := timeout.NewController(timeoutConfig)
-	pacemaker, err := pacemaker.New(started+1, controller, notifier)
+	if cfg.AdaptiveTimeout != nil {
+		controller = timeout.NewAdaptiveController(timeoutConfig, *cfg.AdaptiveTimeout)
+	}
+	pacemaker, err := pacemaker.New(started+1, cfg.TCActivationView, controller, notifier)
 	if err != nil {
 		return nil, fmt.Errorf("could not initialize flow pacemaker: %w", err)
 	}
@@ -123,7 +127,7 @@ func NewParticipant(
 	voter := voter.New(signer, forks, persist, committee, voted)
 
 	// initialize the event handler
-	handler, err := eventhandler.New(log, pacemaker, producer, forks, persist, communicator, committee, aggregator, voter, validator, notifier)
+	handler, err := eventhandler.New(log, pacemaker, producer, forks, persist, communicator, committee, aggregator, voter, validator, signer, notifier)
 	if err != nil {
 		return nil, fmt.Errorf("could not initialize event handler: %w", err)
 	}
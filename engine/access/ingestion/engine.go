@@ -279,6 +279,9 @@ func (e *Engine) handleExecutionReceipt(originID flow.Identifier, r *flow.Execut
 		return fmt.Errorf("failed to index execution result: %w", err)
 	}
 
+	// Notify rpc handler of the new execution receipt
+	e.rpcEngine.SubmitLocal(r)
+
 	e.trackExecutedMetricForReceipt(r)
 	return nil
 }
@@ -586,6 +589,10 @@ func (e *Engine) updateLastFullBlockReceivedIndex() {
 // missingCollectionsAtHeight returns all missing collection guarantees at a given height
 func (e *Engine) missingCollectionsAtHeight(h uint64) ([]*flow.CollectionGuarantee, error) {
 	blk, err := e.blocks.ByHeight(h)
+	if errors.Is(err, storage.ErrPruned) {
+		// the collections of pruned blocks are not needed anymore
+		return nil, nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to retreive block by height %d: %w", h, err)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"testing"
 	"time"
@@ -18,6 +19,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	accessmock "github.com/onflow/flow-go/engine/access/mock"
+	"github.com/onflow/flow-go/engine/access/rest"
 	"github.com/onflow/flow-go/engine/access/rpc"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/metrics"
@@ -101,11 +103,13 @@ func (suite *RateLimitTestSuite) SetupTest() {
 	suite.burstLimit = 2
 
 	apiRateLimt := map[string]int{
-		"Ping": suite.rateLimit,
+		"Ping":                 suite.rateLimit,
+		"GetNetworkParameters": suite.rateLimit,
 	}
 
 	apiBurstLimt := map[string]int{
-		"Ping": suite.rateLimit,
+		"Ping":                 suite.rateLimit,
+		"GetNetworkParameters": suite.rateLimit,
 	}
 
 	suite.rpcEng = rpc.New(suite.log, suite.state, config, suite.collClient, nil, suite.blocks, suite.headers, suite.collections, suite.transactions,
@@ -114,7 +118,7 @@ func (suite *RateLimitTestSuite) SetupTest() {
 
 	// wait for the server to startup
 	assert.Eventually(suite.T(), func() bool {
-		return suite.rpcEng.UnsecureGRPCAddress() != nil
+		return suite.rpcEng.UnsecureGRPCAddress() != nil && suite.rpcEng.HTTPAddress() != nil
 	}, 5*time.Second, 10*time.Millisecond)
 
 	// create the access api client
@@ -188,6 +192,28 @@ func (suite *RateLimitTestSuite) TestRatelimitingWithBurst() {
 	suite.assertRateLimitError(err)
 }
 
+// TestRatelimitingREST tests that the rate limit of an Access API method is applied to the REST API as well
+func (suite *RateLimitTestSuite) TestRatelimitingREST() {
+
+	url := fmt.Sprintf("http://%s%s/network_parameters", suite.rpcEng.HTTPAddress(), rest.Prefix)
+
+	requestCnt := 0
+	// generate a permissible burst of request and assert that they succeed
+	for requestCnt < suite.burstLimit {
+		resp, err := http.Get(url)
+		suite.Require().NoError(err)
+		resp.Body.Close()
+		assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+		requestCnt++
+	}
+
+	// request more than the permissible burst and assert that it fails
+	resp, err := http.Get(url)
+	suite.Require().NoError(err)
+	resp.Body.Close()
+	assert.Equal(suite.T(), http.StatusTooManyRequests, resp.StatusCode)
+}
+
 func (suite *RateLimitTestSuite) assertRateLimitError(err error) {
 	assert.Error(suite.T(), err)
 	status, ok := status.FromError(err)
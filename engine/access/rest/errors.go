@@ -0,0 +1,74 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Error is the response body of all failed requests.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// requestError is returned by handlers for requests which failed with a
+// specific HTTP status code.
+type requestError struct {
+	code    int
+	message string
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &requestError{code: http.StatusBadRequest, message: fmt.Sprintf(format, args...)}
+}
+
+func requestTooLarge(format string, args ...interface{}) error {
+	return &requestError{code: http.StatusRequestEntityTooLarge, message: fmt.Sprintf(format, args...)}
+}
+
+// toError converts an error returned by a handler to the response body and
+// HTTP status code. Errors returned by the Access API are gRPC status errors,
+// which are mapped to the closest HTTP status code.
+func toError(err error) Error {
+	var reqErr *requestError
+	if errors.As(err, &reqErr) {
+		return Error{Code: reqErr.code, Message: reqErr.message}
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return Error{Code: http.StatusInternalServerError, Message: err.Error()}
+	}
+
+	var code int
+	switch st.Code() {
+	case codes.NotFound:
+		code = http.StatusNotFound
+	case codes.InvalidArgument, codes.OutOfRange:
+		code = http.StatusBadRequest
+	case codes.FailedPrecondition:
+		code = http.StatusPreconditionFailed
+	case codes.ResourceExhausted:
+		code = http.StatusTooManyRequests
+	case codes.Unavailable:
+		code = http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		code = http.StatusGatewayTimeout
+	case codes.Canceled:
+		code = http.StatusRequestTimeout
+	case codes.Unimplemented:
+		code = http.StatusNotImplemented
+	default:
+		code = http.StatusInternalServerError
+	}
+
+	return Error{Code: code, Message: st.Message()}
+}
@@ -0,0 +1,374 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func parseID(raw string) (flow.Identifier, error) {
+	id, err := flow.HexStringToIdentifier(raw)
+	if err != nil {
+		return flow.ZeroID, badRequest("invalid ID %q: %v", raw, err)
+	}
+	return id, nil
+}
+
+func parseIDs(raw string) ([]flow.Identifier, error) {
+	parts := strings.Split(raw, ",")
+	ids := make([]flow.Identifier, 0, len(parts))
+	for _, part := range parts {
+		id, err := parseID(part)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func parseAddress(raw string, chain flow.Chain) (flow.Address, error) {
+	trimmed := strings.TrimPrefix(raw, "0x")
+	if len(trimmed)%2 == 1 {
+		trimmed = "0" + trimmed
+	}
+	b, err := hex.DecodeString(trimmed)
+	if err != nil || len(b) == 0 || len(b) > flow.AddressLength {
+		return flow.EmptyAddress, badRequest("invalid address %q", raw)
+	}
+
+	address := flow.BytesToAddress(b)
+	if !chain.IsValid(address) {
+		return flow.EmptyAddress, badRequest("address %s is invalid for chain %s", address, chain)
+	}
+	return address, nil
+}
+
+func parseHeight(raw string, name string) (uint64, error) {
+	height, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, badRequest("invalid %s %q", name, raw)
+	}
+	return height, nil
+}
+
+func parseBool(raw string, name string) (bool, error) {
+	if raw == "" {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, badRequest("invalid %s %q", name, raw)
+	}
+	return value, nil
+}
+
+// decodeBody decodes the JSON request body. The body is limited to
+// MaxRequestBodySize bytes by the handler, so that clients can not stream
+// arbitrarily large bodies into memory.
+func decodeBody(r *request, body interface{}) error {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		// the body reader fails once the limit is exceeded, after reading up to the limit
+		if int64(len(data)) >= MaxRequestBodySize {
+			return requestTooLarge("request body exceeds %d bytes", MaxRequestBodySize)
+		}
+		return badRequest("could not read request body: %v", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(body); err != nil {
+		return badRequest("invalid request body: %v", err)
+	}
+	return nil
+}
+
+// GET /v1/blocks?height=N
+func (h *Handler) getBlocksByHeight(r *request) (interface{}, error) {
+	raw := r.URL.Query().Get("height")
+	if raw == "" {
+		return nil, badRequest("height is required")
+	}
+	height, err := parseHeight(raw, "height")
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := h.api.GetBlockByHeight(r.Context(), height)
+	if err != nil {
+		return nil, err
+	}
+	return blockFromFlow(block), nil
+}
+
+// GET /v1/blocks/latest?sealed=true
+func (h *Handler) getLatestBlock(r *request) (interface{}, error) {
+	sealed, err := parseBool(r.URL.Query().Get("sealed"), "sealed")
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := h.api.GetLatestBlock(r.Context(), sealed)
+	if err != nil {
+		return nil, err
+	}
+	return blockFromFlow(block), nil
+}
+
+// GET /v1/blocks/{id}
+func (h *Handler) getBlockByID(r *request) (interface{}, error) {
+	id, err := parseID(r.param("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := h.api.GetBlockByID(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return blockFromFlow(block), nil
+}
+
+// GET /v1/collections/{id}
+func (h *Handler) getCollectionByID(r *request) (interface{}, error) {
+	id, err := parseID(r.param("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := h.api.GetCollectionByID(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return collectionFromFlow(collection), nil
+}
+
+// POST /v1/transactions
+func (h *Handler) sendTransaction(r *request) (interface{}, error) {
+	var body Transaction
+	err := decodeBody(r, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := body.toFlow(h.chain)
+	if err != nil {
+		return nil, badRequest("invalid transaction: %v", err)
+	}
+
+	err = h.api.SendTransaction(r.Context(), tx)
+	if err != nil {
+		return nil, err
+	}
+	return transactionFromFlow(tx), nil
+}
+
+// GET /v1/transactions/{id}
+func (h *Handler) getTransactionByID(r *request) (interface{}, error) {
+	id, err := parseID(r.param("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := h.api.GetTransaction(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return transactionFromFlow(tx), nil
+}
+
+// GET /v1/transaction_results/{id}
+func (h *Handler) getTransactionResultByID(r *request) (interface{}, error) {
+	id, err := parseID(r.param("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.api.GetTransactionResult(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return transactionResultFromAccess(result), nil
+}
+
+// GET /v1/accounts/{address}?block_height=N
+func (h *Handler) getAccount(r *request) (interface{}, error) {
+	address, err := parseAddress(r.param("address"), h.chain)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := r.URL.Query().Get("block_height")
+	if raw == "" {
+		account, err := h.api.GetAccountAtLatestBlock(r.Context(), address)
+		if err != nil {
+			return nil, err
+		}
+		return accountFromFlow(account), nil
+	}
+
+	height, err := parseHeight(raw, "block_height")
+	if err != nil {
+		return nil, err
+	}
+	account, err := h.api.GetAccountAtBlockHeight(r.Context(), address, height)
+	if err != nil {
+		return nil, err
+	}
+	return accountFromFlow(account), nil
+}
+
+// POST /v1/scripts?block_height=N or ?block_id=ID
+func (h *Handler) executeScript(r *request) (interface{}, error) {
+	var body ScriptRequest
+	err := decodeBody(r, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := make([][]byte, len(body.Arguments))
+	for i, arg := range body.Arguments {
+		arguments[i] = arg
+	}
+
+	query := r.URL.Query()
+	rawHeight := query.Get("block_height")
+	rawID := query.Get("block_id")
+
+	var value []byte
+	switch {
+	case rawHeight != "" && rawID != "":
+		return nil, badRequest("only one of block_height and block_id can be specified")
+	case rawHeight != "":
+		height, err := parseHeight(rawHeight, "block_height")
+		if err != nil {
+			return nil, err
+		}
+		value, err = h.api.ExecuteScriptAtBlockHeight(r.Context(), height, body.Script, arguments)
+		if err != nil {
+			return nil, err
+		}
+	case rawID != "":
+		id, err := parseID(rawID)
+		if err != nil {
+			return nil, err
+		}
+		value, err = h.api.ExecuteScriptAtBlockID(r.Context(), id, body.Script, arguments)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		value, err = h.api.ExecuteScriptAtLatestBlock(r.Context(), body.Script, arguments)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ScriptResult{Value: cadenceJSON(value)}, nil
+}
+
+// GET /v1/events?type=T&start_height=N&end_height=M or ?type=T&block_ids=ID,ID
+//
+// Height range queries are paginated: at most maxPageSize blocks are returned,
+// and if the range extends beyond the returned blocks, the response contains
+// the start height of the next page.
+func (h *Handler) getEvents(r *request) (interface{}, error) {
+	query := r.URL.Query()
+
+	eventType := query.Get("type")
+	if eventType == "" {
+		return nil, badRequest("type is required")
+	}
+
+	rawIDs := query.Get("block_ids")
+	rawStart := query.Get("start_height")
+	rawEnd := query.Get("end_height")
+
+	if rawIDs != "" {
+		if rawStart != "" || rawEnd != "" {
+			return nil, badRequest("block_ids can not be combined with a height range")
+		}
+		ids, err := parseIDs(rawIDs)
+		if err != nil {
+			return nil, err
+		}
+		if uint(len(ids)) > h.maxPageSize {
+			return nil, badRequest("at most %d block IDs can be requested", h.maxPageSize)
+		}
+
+		events, err := h.api.GetEventsForBlockIDs(r.Context(), eventType, ids)
+		if err != nil {
+			return nil, err
+		}
+		return EventsPage{Results: blockEventsFromFlow(events)}, nil
+	}
+
+	if rawStart == "" || rawEnd == "" {
+		return nil, badRequest("either block_ids or start_height and end_height are required")
+	}
+	start, err := parseHeight(rawStart, "start_height")
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseHeight(rawEnd, "end_height")
+	if err != nil {
+		return nil, err
+	}
+	if end < start {
+		return nil, badRequest("end_height %d is lower than start_height %d", end, start)
+	}
+
+	pageEnd := end
+	if pageEnd-start+1 > uint64(h.maxPageSize) {
+		pageEnd = start + uint64(h.maxPageSize) - 1
+	}
+
+	events, err := h.api.GetEventsForHeightRange(r.Context(), eventType, start, pageEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	page := EventsPage{Results: blockEventsFromFlow(events)}
+
+	// the range may be cut short at the latest sealed block, continue after the
+	// last returned block
+	if len(events) > 0 {
+		last := events[len(events)-1].BlockHeight
+		if last < end {
+			next := last + 1
+			page.NextStartHeight = &next
+		}
+	}
+
+	return page, nil
+}
+
+// GET /v1/execution_results?block_id=ID
+func (h *Handler) getExecutionResultByBlockID(r *request) (interface{}, error) {
+	raw := r.URL.Query().Get("block_id")
+	if raw == "" {
+		return nil, badRequest("block_id is required")
+	}
+	id, err := parseID(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := h.api.GetExecutionResultForBlockID(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	return executionResultFromFlow(result), nil
+}
+
+// GET /v1/network_parameters
+func (h *Handler) getNetworkParameters(r *request) (interface{}, error) {
+	params := h.api.GetNetworkParameters(r.Context())
+	return NetworkParameters{ChainID: params.ChainID.String()}, nil
+}
@@ -0,0 +1,281 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	accessmock "github.com/onflow/flow-go/access/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+var _ access.API = (*accessmock.API)(nil)
+
+func newTestHandler(api *accessmock.API, maxPageSize uint) *Handler {
+	return NewHandler(zerolog.Nop(), api, flow.Testnet.Chain(), maxPageSize)
+}
+
+func serve(t *testing.T, h *Handler, method string, url string, body interface{}) *httptest.ResponseRecorder {
+	var reader *bytes.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(encoded)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, url, reader)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func requireError(t *testing.T, rec *httptest.ResponseRecorder, code int) {
+	require.Equal(t, code, rec.Code, rec.Body.String())
+
+	var body Error
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, code, body.Code)
+	assert.NotEmpty(t, body.Message)
+}
+
+func TestGetBlock(t *testing.T) {
+	api := new(accessmock.API)
+	h := newTestHandler(api, 0)
+
+	block := unittest.BlockFixture()
+	api.On("GetBlockByID", mock.Anything, block.ID()).Return(&block, nil)
+	api.On("GetBlockByHeight", mock.Anything, block.Header.Height).Return(&block, nil)
+	api.On("GetLatestBlock", mock.Anything, true).Return(&block, nil)
+
+	for _, url := range []string{
+		fmt.Sprintf("/v1/blocks/%s", block.ID()),
+		fmt.Sprintf("/v1/blocks?height=%d", block.Header.Height),
+		"/v1/blocks/latest?sealed=true",
+	} {
+		rec := serve(t, h, http.MethodGet, url, nil)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var response Block
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, block.ID().String(), response.Header.ID)
+		assert.Equal(t, block.Header.Height, response.Header.Height)
+		assert.Len(t, response.Payload.CollectionGuarantees, len(block.Payload.Guarantees))
+	}
+
+	// heights are encoded as strings
+	rec := serve(t, h, http.MethodGet, "/v1/blocks/latest?sealed=true", nil)
+	var raw map[string]map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &raw))
+	assert.Equal(t, fmt.Sprint(block.Header.Height), raw["header"]["height"])
+}
+
+func TestErrors(t *testing.T) {
+	api := new(accessmock.API)
+	h := newTestHandler(api, 0)
+
+	missing := unittest.IdentifierFixture()
+	api.On("GetTransaction", mock.Anything, missing).Return(nil, status.Error(codes.NotFound, "not found"))
+
+	t.Run("not found", func(t *testing.T) {
+		rec := serve(t, h, http.MethodGet, fmt.Sprintf("/v1/transactions/%s", missing), nil)
+		requireError(t, rec, http.StatusNotFound)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		rec := serve(t, h, http.MethodGet, "/v1/transactions/abc", nil)
+		requireError(t, rec, http.StatusBadRequest)
+	})
+
+	t.Run("unknown route", func(t *testing.T) {
+		rec := serve(t, h, http.MethodGet, "/v1/unknown", nil)
+		requireError(t, rec, http.StatusNotFound)
+	})
+
+	t.Run("method not allowed", func(t *testing.T) {
+		rec := serve(t, h, http.MethodDelete, "/v1/transactions", nil)
+		requireError(t, rec, http.StatusMethodNotAllowed)
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		rec := serve(t, h, http.MethodGet, "/v1/accounts/0x1234", nil)
+		requireError(t, rec, http.StatusBadRequest)
+	})
+}
+
+func TestSendTransaction(t *testing.T) {
+	api := new(accessmock.API)
+	h := newTestHandler(api, 0)
+
+	chain := flow.Testnet.Chain()
+	tx := unittest.TransactionBodyFixture()
+	tx.Payer = chain.ServiceAddress()
+	tx.ProposalKey.Address = chain.ServiceAddress()
+	tx.Authorizers = []flow.Address{chain.ServiceAddress()}
+	tx.Arguments = [][]byte{[]byte(`{"type":"UInt64","value":"1"}`)}
+	tx.PayloadSignatures = nil
+	tx.EnvelopeSignatures = []flow.TransactionSignature{
+		{Address: chain.ServiceAddress(), KeyIndex: 0, Signature: unittest.SignatureFixture()},
+	}
+
+	api.On("SendTransaction", mock.Anything, mock.MatchedBy(func(sent *flow.TransactionBody) bool {
+		return sent.ID() == tx.ID()
+	})).Return(nil)
+
+	body := transactionFromFlow(&tx)
+	body.ID = ""
+	rec := serve(t, h, http.MethodPost, "/v1/transactions", body)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var response Transaction
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, tx.ID().String(), response.ID)
+	api.AssertExpectations(t)
+}
+
+func TestExecuteScript(t *testing.T) {
+	api := new(accessmock.API)
+	h := newTestHandler(api, 0)
+
+	script := []byte("pub fun main(a: UInt64): UInt64 { return a }")
+	argument := []byte(`{"type":"UInt64","value":"1"}`)
+	result := []byte(`{"type":"UInt64","value":"1"}`)
+	blockID := unittest.IdentifierFixture()
+
+	api.On("ExecuteScriptAtLatestBlock", mock.Anything, script, [][]byte{argument}).Return(result, nil)
+	api.On("ExecuteScriptAtBlockHeight", mock.Anything, uint64(5), script, [][]byte{argument}).Return(result, nil)
+	api.On("ExecuteScriptAtBlockID", mock.Anything, blockID, script, [][]byte{argument}).Return(result, nil)
+
+	body := ScriptRequest{Script: script, Arguments: []json.RawMessage{argument}}
+	for _, url := range []string{
+		"/v1/scripts",
+		"/v1/scripts?block_height=5",
+		fmt.Sprintf("/v1/scripts?block_id=%s", blockID),
+	} {
+		rec := serve(t, h, http.MethodPost, url, body)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var response ScriptResult
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.JSONEq(t, string(result), string(response.Value))
+	}
+
+	rec := serve(t, h, http.MethodPost, fmt.Sprintf("/v1/scripts?block_height=5&block_id=%s", blockID), body)
+	requireError(t, rec, http.StatusBadRequest)
+}
+
+// TestRequestBodyLimit tests that request bodies exceeding the maximum size are rejected.
+func TestRequestBodyLimit(t *testing.T) {
+	api := new(accessmock.API)
+	h := newTestHandler(api, 0)
+
+	body := ScriptRequest{Script: bytes.Repeat([]byte("a"), MaxRequestBodySize)}
+	rec := serve(t, h, http.MethodPost, "/v1/scripts", body)
+	requireError(t, rec, http.StatusRequestEntityTooLarge)
+	api.AssertNotCalled(t, "ExecuteScriptAtLatestBlock", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestGetEvents_Pagination tests that height range queries are split into pages
+// of at most the maximum page size.
+func TestGetEvents_Pagination(t *testing.T) {
+	api := new(accessmock.API)
+	h := newTestHandler(api, 3)
+
+	eventType := string(flow.EventAccountCreated)
+	blockEvents := func(start, end uint64) []flow.BlockEvents {
+		var results []flow.BlockEvents
+		for height := start; height <= end; height++ {
+			results = append(results, flow.BlockEvents{
+				BlockID:     unittest.IdentifierFixture(),
+				BlockHeight: height,
+				Events: []flow.Event{
+					unittest.EventFixture(flow.EventAccountCreated, 0, 0, unittest.IdentifierFixture(), 0),
+				},
+			})
+		}
+		return results
+	}
+
+	api.On("GetEventsForHeightRange", mock.Anything, eventType, uint64(10), uint64(12)).Return(blockEvents(10, 12), nil)
+	api.On("GetEventsForHeightRange", mock.Anything, eventType, uint64(13), uint64(14)).Return(blockEvents(13, 14), nil)
+
+	var page EventsPage
+	rec := serve(t, h, http.MethodGet, fmt.Sprintf("/v1/events?type=%s&start_height=10&end_height=14", eventType), nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	require.Len(t, page.Results, 3)
+	require.NotNil(t, page.NextStartHeight)
+	assert.Equal(t, uint64(13), *page.NextStartHeight)
+
+	page = EventsPage{}
+	rec = serve(t, h, http.MethodGet, fmt.Sprintf("/v1/events?type=%s&start_height=13&end_height=14", eventType), nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	require.Len(t, page.Results, 2)
+	assert.Nil(t, page.NextStartHeight)
+
+	// block ID queries are limited to the page size
+	ids := unittest.IdentifierListFixture(4)
+	url := fmt.Sprintf("/v1/events?type=%s&block_ids=%s,%s,%s,%s", eventType, ids[0], ids[1], ids[2], ids[3])
+	requireError(t, serve(t, h, http.MethodGet, url, nil), http.StatusBadRequest)
+}
+
+// TestInterceptor tests that the interceptor is called with the Access API
+// method serving the request, and that rejected requests are not handled.
+func TestInterceptor(t *testing.T) {
+	api := new(accessmock.API)
+
+	var called []string
+	h := NewHandler(zerolog.Nop(), api, flow.Testnet.Chain(), 0, WithInterceptor(func(_ *http.Request, apiMethod string) error {
+		called = append(called, apiMethod)
+		return status.Errorf(codes.ResourceExhausted, "%s rate limit reached", apiMethod)
+	}))
+
+	id := unittest.IdentifierFixture()
+	requests := []struct {
+		method    string
+		url       string
+		apiMethod string
+	}{
+		{http.MethodGet, "/v1/blocks/latest", "GetLatestBlock"},
+		{http.MethodGet, fmt.Sprintf("/v1/blocks/%s", id), "GetBlockByID"},
+		{http.MethodGet, "/v1/accounts/0x01", "GetAccountAtLatestBlock"},
+		{http.MethodGet, "/v1/accounts/0x01?block_height=10", "GetAccountAtBlockHeight"},
+		{http.MethodPost, "/v1/scripts?block_id=" + id.String(), "ExecuteScriptAtBlockID"},
+		{http.MethodGet, "/v1/events?type=A&block_ids=" + id.String(), "GetEventsForBlockIDs"},
+		{http.MethodGet, "/v1/network_parameters", "GetNetworkParameters"},
+	}
+	// the API mock has no expectations, so handling any of the requests fails the test
+	for _, r := range requests {
+		rec := serve(t, h, r.method, r.url, nil)
+		requireError(t, rec, http.StatusTooManyRequests)
+		assert.Equal(t, r.apiMethod, called[len(called)-1])
+	}
+	assert.Len(t, called, len(requests))
+
+	// the specification is not intercepted
+	rec := serve(t, h, http.MethodGet, "/v1/openapi.yaml", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, called, len(requests))
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	h := newTestHandler(new(accessmock.API), 0)
+
+	rec := serve(t, h, http.MethodGet, "/v1/openapi.yaml", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, OpenAPISpec, rec.Body.String())
+}
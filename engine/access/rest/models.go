@@ -0,0 +1,424 @@
+package rest
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow/protobuf/go/flow/entities"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// The JSON representations of the Access API entities. Identifiers, state
+// commitments and public keys are hex encoded, addresses are hex encoded with
+// 0x prefix and other binary data is base64 encoded. 64-bit integers are encoded
+// as strings, as they exceed the precision of JSON numbers in most decoders.
+// Cadence values (script arguments and results, event payloads) are embedded
+// as JSON-Cadence documents.
+
+type Block struct {
+	Header  BlockHeader  `json:"header"`
+	Payload BlockPayload `json:"payload"`
+}
+
+type BlockHeader struct {
+	ID                 string    `json:"id"`
+	ParentID           string    `json:"parent_id"`
+	Height             uint64    `json:"height,string"`
+	View               uint64    `json:"view,string"`
+	Timestamp          time.Time `json:"timestamp"`
+	PayloadHash        string    `json:"payload_hash"`
+	ProposerID         string    `json:"proposer_id"`
+	ParentVoterIDs     []string  `json:"parent_voter_ids"`
+	ParentVoterSigData []byte    `json:"parent_voter_sig_data"`
+	ProposerSigData    []byte    `json:"proposer_sig_data"`
+}
+
+type BlockPayload struct {
+	CollectionGuarantees []CollectionGuarantee `json:"collection_guarantees"`
+	BlockSeals           []BlockSeal           `json:"block_seals"`
+}
+
+type CollectionGuarantee struct {
+	CollectionID     string   `json:"collection_id"`
+	ReferenceBlockID string   `json:"reference_block_id"`
+	SignerIDs        []string `json:"signer_ids"`
+	Signature        []byte   `json:"signature"`
+}
+
+type BlockSeal struct {
+	BlockID    string `json:"block_id"`
+	ResultID   string `json:"result_id"`
+	FinalState string `json:"final_state"`
+}
+
+type Collection struct {
+	ID             string   `json:"id"`
+	TransactionIDs []string `json:"transaction_ids"`
+}
+
+type Transaction struct {
+	ID                 string                 `json:"id,omitempty"`
+	Script             []byte                 `json:"script"`
+	Arguments          []json.RawMessage      `json:"arguments"`
+	ReferenceBlockID   string                 `json:"reference_block_id"`
+	GasLimit           uint64                 `json:"gas_limit,string"`
+	Payer              string                 `json:"payer"`
+	ProposalKey        ProposalKey            `json:"proposal_key"`
+	Authorizers        []string               `json:"authorizers"`
+	PayloadSignatures  []TransactionSignature `json:"payload_signatures"`
+	EnvelopeSignatures []TransactionSignature `json:"envelope_signatures"`
+}
+
+type ProposalKey struct {
+	Address        string `json:"address"`
+	KeyIndex       uint64 `json:"key_index,string"`
+	SequenceNumber uint64 `json:"sequence_number,string"`
+}
+
+type TransactionSignature struct {
+	Address   string `json:"address"`
+	KeyIndex  uint64 `json:"key_index,string"`
+	Signature []byte `json:"signature"`
+}
+
+type TransactionResult struct {
+	BlockID      string  `json:"block_id"`
+	Status       string  `json:"status"`
+	StatusCode   uint    `json:"status_code"`
+	ErrorMessage string  `json:"error_message"`
+	Events       []Event `json:"events"`
+}
+
+type Event struct {
+	Type             string          `json:"type"`
+	TransactionID    string          `json:"transaction_id"`
+	TransactionIndex uint32          `json:"transaction_index"`
+	EventIndex       uint32          `json:"event_index"`
+	Payload          json.RawMessage `json:"payload"`
+}
+
+type BlockEvents struct {
+	BlockID        string    `json:"block_id"`
+	BlockHeight    uint64    `json:"block_height,string"`
+	BlockTimestamp time.Time `json:"block_timestamp"`
+	Events         []Event   `json:"events"`
+}
+
+// EventsPage is a page of events for a height range. If the requested range
+// exceeds the page size, NextStartHeight holds the start height of the next page.
+type EventsPage struct {
+	Results         []BlockEvents `json:"results"`
+	NextStartHeight *uint64       `json:"next_start_height,string,omitempty"`
+}
+
+type Account struct {
+	Address   string            `json:"address"`
+	Balance   uint64            `json:"balance,string"`
+	Keys      []AccountKey      `json:"keys"`
+	Contracts map[string][]byte `json:"contracts"`
+}
+
+type AccountKey struct {
+	Index            int    `json:"index"`
+	PublicKey        string `json:"public_key"`
+	SigningAlgorithm string `json:"signing_algorithm"`
+	HashingAlgorithm string `json:"hashing_algorithm"`
+	SequenceNumber   uint64 `json:"sequence_number,string"`
+	Weight           int    `json:"weight"`
+	Revoked          bool   `json:"revoked"`
+}
+
+type ScriptRequest struct {
+	Script    []byte            `json:"script"`
+	Arguments []json.RawMessage `json:"arguments"`
+}
+
+type ScriptResult struct {
+	Value json.RawMessage `json:"value"`
+}
+
+type ExecutionResult struct {
+	ID               string         `json:"id"`
+	BlockID          string         `json:"block_id"`
+	PreviousResultID string         `json:"previous_result_id"`
+	Chunks           []Chunk        `json:"chunks"`
+	ServiceEvents    []ServiceEvent `json:"service_events"`
+}
+
+type Chunk struct {
+	Index                uint64 `json:"index,string"`
+	CollectionIndex      uint   `json:"collection_index"`
+	StartState           string `json:"start_state"`
+	EndState             string `json:"end_state"`
+	EventCollection      string `json:"event_collection"`
+	NumberOfTransactions uint64 `json:"number_of_transactions,string"`
+	TotalComputationUsed uint64 `json:"total_computation_used,string"`
+}
+
+type ServiceEvent struct {
+	Type string `json:"type"`
+}
+
+type NetworkParameters struct {
+	ChainID string `json:"chain_id"`
+}
+
+func identifiers(ids []flow.Identifier) []string {
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = id.String()
+	}
+	return result
+}
+
+func blockHeaderFromFlow(h *flow.Header) BlockHeader {
+	return BlockHeader{
+		ID:                 h.ID().String(),
+		ParentID:           h.ParentID.String(),
+		Height:             h.Height,
+		View:               h.View,
+		Timestamp:          h.Timestamp,
+		PayloadHash:        h.PayloadHash.String(),
+		ProposerID:         h.ProposerID.String(),
+		ParentVoterIDs:     identifiers(h.ParentVoterIDs),
+		ParentVoterSigData: h.ParentVoterSigData,
+		ProposerSigData:    h.ProposerSigData,
+	}
+}
+
+func blockFromFlow(b *flow.Block) Block {
+	guarantees := make([]CollectionGuarantee, len(b.Payload.Guarantees))
+	for i, g := range b.Payload.Guarantees {
+		guarantees[i] = CollectionGuarantee{
+			CollectionID:     g.CollectionID.String(),
+			ReferenceBlockID: g.ReferenceBlockID.String(),
+			SignerIDs:        identifiers(g.SignerIDs),
+			Signature:        g.Signature,
+		}
+	}
+
+	seals := make([]BlockSeal, len(b.Payload.Seals))
+	for i, s := range b.Payload.Seals {
+		seals[i] = BlockSeal{
+			BlockID:    s.BlockID.String(),
+			ResultID:   s.ResultID.String(),
+			FinalState: hex.EncodeToString(s.FinalState[:]),
+		}
+	}
+
+	return Block{
+		Header: blockHeaderFromFlow(b.Header),
+		Payload: BlockPayload{
+			CollectionGuarantees: guarantees,
+			BlockSeals:           seals,
+		},
+	}
+}
+
+func collectionFromFlow(c *flow.LightCollection) Collection {
+	return Collection{
+		ID:             c.ID().String(),
+		TransactionIDs: identifiers(c.Transactions),
+	}
+}
+
+// cadenceJSON embeds an encoded Cadence value. Values which are not valid JSON
+// are embedded as base64 encoded strings instead.
+func cadenceJSON(value []byte) json.RawMessage {
+	if json.Valid(value) {
+		return value
+	}
+	encoded, _ := json.Marshal(value)
+	return encoded
+}
+
+func transactionFromFlow(tx *flow.TransactionBody) Transaction {
+	arguments := make([]json.RawMessage, len(tx.Arguments))
+	for i, arg := range tx.Arguments {
+		arguments[i] = cadenceJSON(arg)
+	}
+
+	authorizers := make([]string, len(tx.Authorizers))
+	for i, a := range tx.Authorizers {
+		authorizers[i] = a.HexWithPrefix()
+	}
+
+	return Transaction{
+		ID:               tx.ID().String(),
+		Script:           tx.Script,
+		Arguments:        arguments,
+		ReferenceBlockID: tx.ReferenceBlockID.String(),
+		GasLimit:         tx.GasLimit,
+		Payer:            tx.Payer.HexWithPrefix(),
+		ProposalKey: ProposalKey{
+			Address:        tx.ProposalKey.Address.HexWithPrefix(),
+			KeyIndex:       tx.ProposalKey.KeyIndex,
+			SequenceNumber: tx.ProposalKey.SequenceNumber,
+		},
+		Authorizers:        authorizers,
+		PayloadSignatures:  signaturesFromFlow(tx.PayloadSignatures),
+		EnvelopeSignatures: signaturesFromFlow(tx.EnvelopeSignatures),
+	}
+}
+
+func signaturesFromFlow(sigs []flow.TransactionSignature) []TransactionSignature {
+	result := make([]TransactionSignature, len(sigs))
+	for i, sig := range sigs {
+		result[i] = TransactionSignature{
+			Address:   sig.Address.HexWithPrefix(),
+			KeyIndex:  sig.KeyIndex,
+			Signature: sig.Signature,
+		}
+	}
+	return result
+}
+
+// toFlow converts the transaction to a transaction body, validating all
+// addresses against the given chain.
+func (t Transaction) toFlow(chain flow.Chain) (*flow.TransactionBody, error) {
+	tx := flow.NewTransactionBody()
+
+	referenceBlockID, err := flow.HexStringToIdentifier(t.ReferenceBlockID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference block ID: %w", err)
+	}
+
+	payer, err := parseAddress(t.Payer, chain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payer: %w", err)
+	}
+
+	proposer, err := parseAddress(t.ProposalKey.Address, chain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proposal key: %w", err)
+	}
+
+	tx.SetScript(t.Script).
+		SetReferenceBlockID(referenceBlockID).
+		SetGasLimit(t.GasLimit).
+		SetPayer(payer).
+		SetProposalKey(proposer, t.ProposalKey.KeyIndex, t.ProposalKey.SequenceNumber)
+
+	arguments := make([][]byte, len(t.Arguments))
+	for i, arg := range t.Arguments {
+		arguments[i] = arg
+	}
+	tx.SetArguments(arguments)
+
+	for _, a := range t.Authorizers {
+		authorizer, err := parseAddress(a, chain)
+		if err != nil {
+			return nil, fmt.Errorf("invalid authorizer: %w", err)
+		}
+		tx.AddAuthorizer(authorizer)
+	}
+
+	for _, sig := range t.PayloadSignatures {
+		address, err := parseAddress(sig.Address, chain)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload signature: %w", err)
+		}
+		tx.AddPayloadSignature(address, sig.KeyIndex, sig.Signature)
+	}
+
+	for _, sig := range t.EnvelopeSignatures {
+		address, err := parseAddress(sig.Address, chain)
+		if err != nil {
+			return nil, fmt.Errorf("invalid envelope signature: %w", err)
+		}
+		tx.AddEnvelopeSignature(address, sig.KeyIndex, sig.Signature)
+	}
+
+	return tx, nil
+}
+
+func eventsFromFlow(events []flow.Event) []Event {
+	result := make([]Event, len(events))
+	for i, e := range events {
+		result[i] = Event{
+			Type:             string(e.Type),
+			TransactionID:    e.TransactionID.String(),
+			TransactionIndex: e.TransactionIndex,
+			EventIndex:       e.EventIndex,
+			Payload:          cadenceJSON(e.Payload),
+		}
+	}
+	return result
+}
+
+func blockEventsFromFlow(blocks []flow.BlockEvents) []BlockEvents {
+	result := make([]BlockEvents, len(blocks))
+	for i, b := range blocks {
+		result[i] = BlockEvents{
+			BlockID:        b.BlockID.String(),
+			BlockHeight:    b.BlockHeight,
+			BlockTimestamp: b.BlockTimestamp,
+			Events:         eventsFromFlow(b.Events),
+		}
+	}
+	return result
+}
+
+func transactionResultFromAccess(r *access.TransactionResult) TransactionResult {
+	return TransactionResult{
+		BlockID:      r.BlockID.String(),
+		Status:       entities.TransactionStatus(r.Status).String(),
+		StatusCode:   r.StatusCode,
+		ErrorMessage: r.ErrorMessage,
+		Events:       eventsFromFlow(r.Events),
+	}
+}
+
+func accountFromFlow(a *flow.Account) Account {
+	keys := make([]AccountKey, len(a.Keys))
+	for i, k := range a.Keys {
+		keys[i] = AccountKey{
+			Index:            k.Index,
+			PublicKey:        hex.EncodeToString(k.PublicKey.Encode()),
+			SigningAlgorithm: k.SignAlgo.String(),
+			HashingAlgorithm: k.HashAlgo.String(),
+			SequenceNumber:   k.SeqNumber,
+			Weight:           k.Weight,
+			Revoked:          k.Revoked,
+		}
+	}
+
+	return Account{
+		Address:   a.Address.HexWithPrefix(),
+		Balance:   a.Balance,
+		Keys:      keys,
+		Contracts: a.Contracts,
+	}
+}
+
+func executionResultFromFlow(r *flow.ExecutionResult) ExecutionResult {
+	chunks := make([]Chunk, len(r.Chunks))
+	for i, c := range r.Chunks {
+		chunks[i] = Chunk{
+			Index:                c.Index,
+			CollectionIndex:      c.CollectionIndex,
+			StartState:           hex.EncodeToString(c.StartState[:]),
+			EndState:             hex.EncodeToString(c.EndState[:]),
+			EventCollection:      c.EventCollection.String(),
+			NumberOfTransactions: c.NumberOfTransactions,
+			TotalComputationUsed: c.TotalComputationUsed,
+		}
+	}
+
+	serviceEvents := make([]ServiceEvent, len(r.ServiceEvents))
+	for i, e := range r.ServiceEvents {
+		serviceEvents[i] = ServiceEvent{Type: e.Type}
+	}
+
+	return ExecutionResult{
+		ID:               r.ID().String(),
+		BlockID:          r.BlockID.String(),
+		PreviousResultID: r.PreviousResultID.String(),
+		Chunks:           chunks,
+		ServiceEvents:    serviceEvents,
+	}
+}
@@ -0,0 +1,281 @@
+package rest
+
+// OpenAPISpec is the OpenAPI specification of the REST API, served at /v1/openapi.yaml.
+const OpenAPISpec = `openapi: 3.0.3
+info:
+  title: Flow Access API
+  description: >
+    REST API of the Flow Access node. Identifiers are hex encoded, addresses are
+    hex encoded with 0x prefix, other binary data is base64 encoded, and 64-bit
+    integers are encoded as strings. Cadence values are JSON-Cadence documents.
+  version: 1.0.0
+servers:
+  - url: /v1
+paths:
+  /blocks:
+    get:
+      summary: Get a finalized block by height
+      parameters:
+        - { name: height, in: query, required: true, schema: { type: string } }
+      responses:
+        '200': { description: OK, content: { application/json: { schema: { $ref: '#/components/schemas/Block' } } } }
+        default: { $ref: '#/components/responses/Error' }
+  /blocks/latest:
+    get:
+      summary: Get the latest finalized or sealed block
+      parameters:
+        - { name: sealed, in: query, schema: { type: boolean, default: false } }
+      responses:
+        '200': { description: OK, content: { application/json: { schema: { $ref: '#/components/schemas/Block' } } } }
+        default: { $ref: '#/components/responses/Error' }
+  /blocks/{id}:
+    get:
+      summary: Get a block by ID
+      parameters:
+        - { $ref: '#/components/parameters/ID' }
+      responses:
+        '200': { description: OK, content: { application/json: { schema: { $ref: '#/components/schemas/Block' } } } }
+        default: { $ref: '#/components/responses/Error' }
+  /collections/{id}:
+    get:
+      summary: Get a collection by ID
+      parameters:
+        - { $ref: '#/components/parameters/ID' }
+      responses:
+        '200': { description: OK, content: { application/json: { schema: { $ref: '#/components/schemas/Collection' } } } }
+        default: { $ref: '#/components/responses/Error' }
+  /transactions:
+    post:
+      summary: Submit a signed transaction
+      requestBody:
+        required: true
+        content: { application/json: { schema: { $ref: '#/components/schemas/Transaction' } } }
+      responses:
+        '200': { description: OK, content: { application/json: { schema: { $ref: '#/components/schemas/Transaction' } } } }
+        default: { $ref: '#/components/responses/Error' }
+  /transactions/{id}:
+    get:
+      summary: Get a transaction by ID
+      parameters:
+        - { $ref: '#/components/parameters/ID' }
+      responses:
+        '200': { description: OK, content: { application/json: { schema: { $ref: '#/components/schemas/Transaction' } } } }
+        default: { $ref: '#/components/responses/Error' }
+  /transaction_results/{id}:
+    get:
+      summary: Get the result of a transaction by transaction ID
+      parameters:
+        - { $ref: '#/components/parameters/ID' }
+      responses:
+        '200': { description: OK, content: { application/json: { schema: { $ref: '#/components/schemas/TransactionResult' } } } }
+        default: { $ref: '#/components/responses/Error' }
+  /accounts/{address}:
+    get:
+      summary: Get an account at the latest sealed block or at the given height
+      parameters:
+        - { name: address, in: path, required: true, schema: { type: string } }
+        - { name: block_height, in: query, schema: { type: string } }
+      responses:
+        '200': { description: OK, content: { application/json: { schema: { $ref: '#/components/schemas/Account' } } } }
+        default: { $ref: '#/components/responses/Error' }
+  /scripts:
+    post:
+      summary: Execute a script at the latest sealed block, or at the given block
+      parameters:
+        - { name: block_height, in: query, schema: { type: string } }
+        - { name: block_id, in: query, schema: { type: string } }
+      requestBody:
+        required: true
+        content: { application/json: { schema: { $ref: '#/components/schemas/ScriptRequest' } } }
+      responses:
+        '200': { description: OK, content: { application/json: { schema: { $ref: '#/components/schemas/ScriptResult' } } } }
+        default: { $ref: '#/components/responses/Error' }
+  /events:
+    get:
+      summary: Get events of a type for a height range or for a list of blocks
+      description: >
+        Height range queries are paginated. If the range extends beyond the
+        returned blocks, next_start_height is the start height of the next page.
+      parameters:
+        - { name: type, in: query, required: true, schema: { type: string } }
+        - { name: start_height, in: query, schema: { type: string } }
+        - { name: end_height, in: query, schema: { type: string } }
+        - { name: block_ids, in: query, description: comma separated block IDs, schema: { type: string } }
+      responses:
+        '200': { description: OK, content: { application/json: { schema: { $ref: '#/components/schemas/EventsPage' } } } }
+        default: { $ref: '#/components/responses/Error' }
+  /execution_results:
+    get:
+      summary: Get the execution result for a block
+      parameters:
+        - { name: block_id, in: query, required: true, schema: { type: string } }
+      responses:
+        '200': { description: OK, content: { application/json: { schema: { $ref: '#/components/schemas/ExecutionResult' } } } }
+        default: { $ref: '#/components/responses/Error' }
+  /network_parameters:
+    get:
+      summary: Get the network parameters
+      responses:
+        '200': { description: OK, content: { application/json: { schema: { $ref: '#/components/schemas/NetworkParameters' } } } }
+        default: { $ref: '#/components/responses/Error' }
+components:
+  parameters:
+    ID: { name: id, in: path, required: true, schema: { type: string } }
+  responses:
+    Error:
+      description: Error
+      content: { application/json: { schema: { $ref: '#/components/schemas/Error' } } }
+  schemas:
+    Error:
+      type: object
+      properties:
+        code: { type: integer }
+        message: { type: string }
+    Block:
+      type: object
+      properties:
+        header: { $ref: '#/components/schemas/BlockHeader' }
+        payload:
+          type: object
+          properties:
+            collection_guarantees: { type: array, items: { $ref: '#/components/schemas/CollectionGuarantee' } }
+            block_seals: { type: array, items: { $ref: '#/components/schemas/BlockSeal' } }
+    BlockHeader:
+      type: object
+      properties:
+        id: { type: string }
+        parent_id: { type: string }
+        height: { type: string }
+        view: { type: string }
+        timestamp: { type: string, format: date-time }
+        payload_hash: { type: string }
+        proposer_id: { type: string }
+        parent_voter_ids: { type: array, items: { type: string } }
+        parent_voter_sig_data: { type: string, format: byte }
+        proposer_sig_data: { type: string, format: byte }
+    CollectionGuarantee:
+      type: object
+      properties:
+        collection_id: { type: string }
+        reference_block_id: { type: string }
+        signer_ids: { type: array, items: { type: string } }
+        signature: { type: string, format: byte }
+    BlockSeal:
+      type: object
+      properties:
+        block_id: { type: string }
+        result_id: { type: string }
+        final_state: { type: string }
+    Collection:
+      type: object
+      properties:
+        id: { type: string }
+        transaction_ids: { type: array, items: { type: string } }
+    Transaction:
+      type: object
+      properties:
+        id: { type: string, readOnly: true }
+        script: { type: string, format: byte }
+        arguments: { type: array, items: { type: object } }
+        reference_block_id: { type: string }
+        gas_limit: { type: string }
+        payer: { type: string }
+        proposal_key:
+          type: object
+          properties:
+            address: { type: string }
+            key_index: { type: string }
+            sequence_number: { type: string }
+        authorizers: { type: array, items: { type: string } }
+        payload_signatures: { type: array, items: { $ref: '#/components/schemas/TransactionSignature' } }
+        envelope_signatures: { type: array, items: { $ref: '#/components/schemas/TransactionSignature' } }
+    TransactionSignature:
+      type: object
+      properties:
+        address: { type: string }
+        key_index: { type: string }
+        signature: { type: string, format: byte }
+    TransactionResult:
+      type: object
+      properties:
+        block_id: { type: string }
+        status: { type: string, enum: [UNKNOWN, PENDING, FINALIZED, EXECUTED, SEALED, EXPIRED] }
+        status_code: { type: integer }
+        error_message: { type: string }
+        events: { type: array, items: { $ref: '#/components/schemas/Event' } }
+    Event:
+      type: object
+      properties:
+        type: { type: string }
+        transaction_id: { type: string }
+        transaction_index: { type: integer }
+        event_index: { type: integer }
+        payload: { type: object }
+    BlockEvents:
+      type: object
+      properties:
+        block_id: { type: string }
+        block_height: { type: string }
+        block_timestamp: { type: string, format: date-time }
+        events: { type: array, items: { $ref: '#/components/schemas/Event' } }
+    EventsPage:
+      type: object
+      properties:
+        results: { type: array, items: { $ref: '#/components/schemas/BlockEvents' } }
+        next_start_height: { type: string }
+    Account:
+      type: object
+      properties:
+        address: { type: string }
+        balance: { type: string }
+        keys:
+          type: array
+          items:
+            type: object
+            properties:
+              index: { type: integer }
+              public_key: { type: string }
+              signing_algorithm: { type: string }
+              hashing_algorithm: { type: string }
+              sequence_number: { type: string }
+              weight: { type: integer }
+              revoked: { type: boolean }
+        contracts: { type: object, additionalProperties: { type: string, format: byte } }
+    ScriptRequest:
+      type: object
+      properties:
+        script: { type: string, format: byte }
+        arguments: { type: array, items: { type: object } }
+    ScriptResult:
+      type: object
+      properties:
+        value: { type: object }
+    ExecutionResult:
+      type: object
+      properties:
+        id: { type: string }
+        block_id: { type: string }
+        previous_result_id: { type: string }
+        chunks:
+          type: array
+          items:
+            type: object
+            properties:
+              index: { type: string }
+              collection_index: { type: integer }
+              start_state: { type: string }
+              end_state: { type: string }
+              event_collection: { type: string }
+              number_of_transactions: { type: string }
+              total_computation_used: { type: string }
+        service_events:
+          type: array
+          items:
+            type: object
+            properties:
+              type: { type: string }
+    NetworkParameters:
+      type: object
+      properties:
+        chain_id: { type: string }
+`
@@ -0,0 +1,281 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Prefix is the path prefix under which the REST API is served.
+const Prefix = "/v1"
+
+// DefaultMaxPageSize is the default maximum number of blocks returned in a
+// single page of events.
+const DefaultMaxPageSize = 50
+
+// MaxRequestBodySize is the maximum size of request bodies in bytes. It leaves
+// room for the JSON and base64 encoding of transactions of the maximum size.
+const MaxRequestBodySize = 2 * flow.DefaultMaxTransactionByteSize
+
+// request is the parsed HTTP request passed to the route handlers.
+type request struct {
+	*http.Request
+	params map[string]string
+}
+
+// param returns the value of a path parameter.
+func (r *request) param(name string) string {
+	return r.params[name]
+}
+
+type handlerFunc func(r *request) (interface{}, error)
+
+// Interceptor is called before a request is handled, with the name of the
+// Access API method serving it as named by the gRPC API, e.g. GetBlockByID.
+// A returned error rejects the request. Status errors are mapped to HTTP
+// status codes like the errors of the Access API, so that a rate limiter
+// rejecting requests with codes.ResourceExhausted responds with 429.
+type Interceptor func(r *http.Request, apiMethod string) error
+
+// Option configures the REST API handler.
+type Option func(*Handler)
+
+// WithInterceptor sets the interceptor called before each request is handled.
+func WithInterceptor(interceptor Interceptor) Option {
+	return func(h *Handler) {
+		h.interceptor = interceptor
+	}
+}
+
+// apiMethodFunc returns the name of the Access API method serving a request.
+type apiMethodFunc func(query url.Values) string
+
+// apiMethod names the Access API method serving all requests of a route.
+func apiMethod(name string) apiMethodFunc {
+	return func(url.Values) string {
+		return name
+	}
+}
+
+// apiMethodByParam names the Access API method serving the requests of a
+// route, which depends on the query parameters. The variants are pairs of a
+// query parameter and the method serving the requests which specify it.
+func apiMethodByParam(name string, variants ...string) apiMethodFunc {
+	return func(query url.Values) string {
+		for i := 0; i+1 < len(variants); i += 2 {
+			if query.Get(variants[i]) != "" {
+				return variants[i+1]
+			}
+		}
+		return name
+	}
+}
+
+// route is an API endpoint. Path segments in curly braces are parameters.
+type route struct {
+	method    string
+	segments  []string
+	apiMethod apiMethodFunc
+	handler   handlerFunc
+}
+
+// match returns the path parameters if the route matches the given path segments.
+func (rt route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+
+	params := make(map[string]string)
+	for i, segment := range rt.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params[segment[1:len(segment)-1]] = segments[i]
+			continue
+		}
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// Handler serves the REST API, translating JSON requests to calls to the Access API.
+type Handler struct {
+	log         zerolog.Logger
+	api         access.API
+	chain       flow.Chain
+	maxPageSize uint
+	interceptor Interceptor
+	routes      []route
+}
+
+// NewHandler creates a new REST API handler. Event queries over height ranges
+// are paginated with pages of at most maxPageSize blocks.
+func NewHandler(log zerolog.Logger, api access.API, chain flow.Chain, maxPageSize uint, opts ...Option) *Handler {
+	if maxPageSize == 0 {
+		maxPageSize = DefaultMaxPageSize
+	}
+
+	h := &Handler{
+		log:         log.With().Str("component", "rest").Logger(),
+		api:         api,
+		chain:       chain,
+		maxPageSize: maxPageSize,
+	}
+	for _, apply := range opts {
+		apply(h)
+	}
+
+	h.handle(http.MethodGet, "/blocks", apiMethod("GetBlockByHeight"), h.getBlocksByHeight)
+	h.handle(http.MethodGet, "/blocks/latest", apiMethod("GetLatestBlock"), h.getLatestBlock)
+	h.handle(http.MethodGet, "/blocks/{id}", apiMethod("GetBlockByID"), h.getBlockByID)
+	h.handle(http.MethodGet, "/collections/{id}", apiMethod("GetCollectionByID"), h.getCollectionByID)
+	h.handle(http.MethodPost, "/transactions", apiMethod("SendTransaction"), h.sendTransaction)
+	h.handle(http.MethodGet, "/transactions/{id}", apiMethod("GetTransaction"), h.getTransactionByID)
+	h.handle(http.MethodGet, "/transaction_results/{id}", apiMethod("GetTransactionResult"), h.getTransactionResultByID)
+	h.handle(http.MethodGet, "/accounts/{address}", apiMethodByParam("GetAccountAtLatestBlock",
+		"block_height", "GetAccountAtBlockHeight"), h.getAccount)
+	h.handle(http.MethodPost, "/scripts", apiMethodByParam("ExecuteScriptAtLatestBlock",
+		"block_height", "ExecuteScriptAtBlockHeight",
+		"block_id", "ExecuteScriptAtBlockID"), h.executeScript)
+	h.handle(http.MethodGet, "/events", apiMethodByParam("GetEventsForHeightRange",
+		"block_ids", "GetEventsForBlockIDs"), h.getEvents)
+	h.handle(http.MethodGet, "/execution_results", apiMethod("GetExecutionResultForBlockID"), h.getExecutionResultByBlockID)
+	h.handle(http.MethodGet, "/network_parameters", apiMethod("GetNetworkParameters"), h.getNetworkParameters)
+	h.handle(http.MethodGet, "/openapi.yaml", nil, nil)
+
+	return h
+}
+
+func (h *Handler) handle(method string, path string, apiMethod apiMethodFunc, handler handlerFunc) {
+	h.routes = append(h.routes, route{
+		method:    method,
+		segments:  strings.Split(strings.Trim(path, "/"), "/"),
+		apiMethod: apiMethod,
+		handler:   handler,
+	})
+}
+
+// statusRecorder records the status code of a response for logging.
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	s.code = code
+	s.ResponseWriter.WriteHeader(code)
+}
+
+// ServeHTTP logs the request like the gRPC API logs its requests, with
+// successful requests logged at debug level, and serves it.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+	apiMethod := h.serve(rec, r)
+
+	var event *zerolog.Event
+	switch {
+	case rec.code >= http.StatusInternalServerError:
+		event = h.log.Error()
+	case rec.code == http.StatusTooManyRequests:
+		event = h.log.Warn()
+	case rec.code >= http.StatusBadRequest:
+		event = h.log.Info()
+	default:
+		event = h.log.Debug()
+	}
+	event.
+		Str("http.method", r.Method).
+		Str("http.path", r.URL.Path).
+		Str("api.method", apiMethod).
+		Int("http.code", rec.code).
+		Dur("duration", time.Since(start)).
+		Msg("finished REST call")
+}
+
+// serve dispatches the request to the matching route and writes the JSON
+// response. Routes are matched in the order they were registered. It returns
+// the name of the Access API method serving the request, if any.
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request) string {
+	path := strings.TrimPrefix(r.URL.Path, Prefix)
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	methodAllowed := false
+	for _, rt := range h.routes {
+		params, ok := rt.match(segments)
+		if !ok {
+			continue
+		}
+		if rt.method != r.Method {
+			methodAllowed = true
+			continue
+		}
+
+		// the specification is served as is
+		if rt.handler == nil {
+			w.Header().Set("Content-Type", "application/yaml")
+			_, _ = w.Write([]byte(OpenAPISpec))
+			return ""
+		}
+
+		apiMethod := rt.apiMethod(r.URL.Query())
+		if h.interceptor != nil {
+			err := h.interceptor(r, apiMethod)
+			if err != nil {
+				h.writeError(w, r, err)
+				return apiMethod
+			}
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
+		response, err := rt.handler(&request{Request: r, params: params})
+		if err != nil {
+			h.writeError(w, r, err)
+			return apiMethod
+		}
+		h.writeJSON(w, http.StatusOK, response)
+		return apiMethod
+	}
+
+	if methodAllowed {
+		h.writeJSON(w, http.StatusMethodNotAllowed, Error{
+			Code:    http.StatusMethodNotAllowed,
+			Message: "method not allowed",
+		})
+		return ""
+	}
+
+	h.writeJSON(w, http.StatusNotFound, Error{
+		Code:    http.StatusNotFound,
+		Message: "resource not found",
+	})
+	return ""
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	body := toError(err)
+	if body.Code == http.StatusInternalServerError {
+		h.log.Error().Err(err).Str("path", r.URL.Path).Msg("failed to handle request")
+	}
+	h.writeJSON(w, body.Code, body)
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		h.log.Error().Err(err).Msg("failed to encode response")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(encoded)
+}
@@ -45,6 +45,7 @@ var fixedENIdentifiers flow.IdentifierList
 // Block details related calls are handled by backendBlockDetails.
 // Event related calls are handled by backendEvents.
 // Account related calls are handled by backendAccounts.
+// Block subscriptions are handled by backendBlockStream.
 //
 // All remaining calls are handled by the base Backend in this file.
 type Backend struct {
@@ -55,6 +56,7 @@ type Backend struct {
 	backendBlockDetails
 	backendAccounts
 	backendExecutionResults
+	backendBlockStream
 
 	state             protocol.State
 	chainID           flow.ChainID
@@ -138,6 +140,14 @@ func New(
 		backendExecutionResults: backendExecutionResults{
 			executionResults: executionResults,
 		},
+		backendBlockStream: backendBlockStream{
+			state:             state,
+			headers:           headers,
+			blocks:            blocks,
+			executionReceipts: executionReceipts,
+			maxHeightRange:    maxHeightRange,
+			log:               log,
+		},
 		collections:       collections,
 		executionReceipts: executionReceipts,
 		connFactory:       connFactory,
@@ -146,6 +156,14 @@ func New(
 
 	retry.SetBackend(b)
 
+	b.backendBlockStream.getEvents = b.backendEvents.getBlockEventsFromExecutionNode
+
+	b.backendTransactions.subscriptions = newTransactionSubscriptions(
+		log,
+		b.backendTransactions.lookupTransactionStatus,
+		DefaultSubscriptionBufferSize,
+	)
+
 	var err error
 	preferredENIdentifiers, err = identifierList(preferredExecutionNodeIDs)
 	if err != nil {
@@ -189,6 +207,20 @@ func configureTransactionValidator(state protocol.State, chainID flow.ChainID) *
 	)
 }
 
+// NotifyFinalizedBlockHeight informs the backend about a newly finalized block,
+// which may advance pending transactions and block subscriptions.
+func (b *Backend) NotifyFinalizedBlockHeight(height uint64) {
+	b.backendTransactions.NotifyFinalizedBlockHeight(height)
+	b.backendBlockStream.notifyNewData()
+}
+
+// NotifyExecutionReceipt informs the backend about a newly received execution
+// receipt, which may make the events of a block available.
+func (b *Backend) NotifyExecutionReceipt(receipt *flow.ExecutionReceipt) {
+	b.backendTransactions.NotifyExecutionReceipt(receipt)
+	b.backendBlockStream.notifyNewData()
+}
+
 // Ping responds to requests when the server is up.
 func (b *Backend) Ping(ctx context.Context) error {
 
@@ -237,13 +269,16 @@ func convertStorageError(err error) error {
 	if err == nil {
 		return nil
 	}
-	if status.Code(err) == codes.NotFound {
+	if code := status.Code(err); code == codes.NotFound || code == codes.OutOfRange {
 		// Already converted
 		return err
 	}
 	if errors.Is(err, storage.ErrNotFound) {
 		return status.Errorf(codes.NotFound, "not found: %v", err)
 	}
+	if errors.Is(err, storage.ErrPruned) {
+		return status.Errorf(codes.OutOfRange, "pruned: %v", err)
+	}
 
 	return status.Errorf(codes.Internal, "failed to find: %v", err)
 }
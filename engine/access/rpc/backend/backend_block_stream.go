@@ -0,0 +1,292 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+)
+
+// DefaultBlockStreamBufferSize is the default number of blocks buffered for a
+// single block subscriber before the stream waits for the subscriber to catch up.
+const DefaultBlockStreamBufferSize = 16
+
+// blockEventsLookup retrieves the events of the given type for the given blocks.
+type blockEventsLookup func(ctx context.Context, blockHeaders []*flow.Header, eventType string) ([]flow.BlockEvents, error)
+
+// backendBlockStream streams finalized or sealed blocks together with their
+// events to subscribers. Each subscriber first backfills from storage starting
+// at the requested height, and then follows the chain as it is notified about
+// newly finalized blocks and execution receipts.
+type backendBlockStream struct {
+	state             protocol.State
+	headers           storage.Headers
+	blocks            storage.Blocks
+	executionReceipts storage.ExecutionReceipts
+	getEvents         blockEventsLookup
+	maxHeightRange    uint
+	log               zerolog.Logger
+
+	mu        sync.Mutex
+	nextID    uint64
+	listeners map[uint64]engine.Notifier
+}
+
+// SubscribeBlocks streams blocks starting at the given height. If sealed is
+// true, only sealed blocks are streamed. Otherwise finalized blocks are
+// streamed; if events are requested, a block is streamed once it is executed
+// and its events are available.
+//
+// Subscriptions starting below the root block, or at a block which was pruned,
+// are rejected with codes.OutOfRange.
+//
+// The returned channel is closed once the context is cancelled, or if the
+// stream fails, in which case the subscriber can resume from the height
+// following the last block it received. If the stream fails as that block was
+// pruned meanwhile, resuming is rejected as well.
+func (b *backendBlockStream) SubscribeBlocks(
+	ctx context.Context,
+	startHeight uint64,
+	sealed bool,
+	filter access.EventFilter,
+) (<-chan *access.BlockWithEvents, error) {
+
+	err := filter.Validate()
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid event filter: %v", err)
+	}
+
+	root, err := b.state.Params().Root()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get root block: %v", err)
+	}
+	if startHeight < root.Height {
+		return nil, status.Errorf(codes.OutOfRange,
+			"start height %d is lower than the root block height %d", startHeight, root.Height)
+	}
+	if startHeight > root.Height {
+		_, err = b.headers.ByHeight(startHeight)
+		if errors.Is(err, storage.ErrPruned) {
+			return nil, status.Errorf(codes.OutOfRange, "start height %d was pruned: %v", startHeight, err)
+		}
+		// blocks which are not finalized yet are streamed once they are
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return nil, status.Errorf(codes.Internal, "failed to check start height %d: %v", startHeight, err)
+		}
+	}
+
+	id, notifier := b.register()
+	results := make(chan *access.BlockWithEvents, DefaultBlockStreamBufferSize)
+
+	go func() {
+		defer close(results)
+		defer b.unregister(id)
+
+		err := b.stream(ctx, root, startHeight, sealed, filter, notifier, results)
+		if err != nil {
+			b.log.Warn().Err(err).Msg("block stream terminated")
+		}
+	}()
+
+	return results, nil
+}
+
+// notifyNewData wakes up all subscribers to check for newly available blocks.
+func (b *backendBlockStream) notifyNewData() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, notifier := range b.listeners {
+		notifier.Notify()
+	}
+}
+
+func (b *backendBlockStream) register() (uint64, engine.Notifier) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.listeners == nil {
+		b.listeners = make(map[uint64]engine.Notifier)
+	}
+	b.nextID++
+	notifier := engine.NewNotifier()
+	b.listeners[b.nextID] = notifier
+	return b.nextID, notifier
+}
+
+func (b *backendBlockStream) unregister(id uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.listeners, id)
+}
+
+// stream sends all available blocks starting at the given height to the results
+// channel, then waits for notifications to continue. It returns once the
+// context is cancelled or an error is encountered.
+func (b *backendBlockStream) stream(
+	ctx context.Context,
+	root *flow.Header,
+	next uint64,
+	sealed bool,
+	filter access.EventFilter,
+	notifier engine.Notifier,
+	results chan<- *access.BlockWithEvents,
+) error {
+	for {
+		for {
+			limit, err := b.highestHeight(sealed)
+			if err != nil {
+				return err
+			}
+			if next > limit {
+				break
+			}
+
+			end := limit
+			if b.maxHeightRange > 0 && end-next+1 > uint64(b.maxHeightRange) {
+				end = next + uint64(b.maxHeightRange) - 1
+			}
+
+			batch, err := b.nextBatch(ctx, root, next, end, filter)
+			if err != nil {
+				return err
+			}
+			if len(batch) == 0 {
+				// the next block has not been executed yet, wait for receipts
+				break
+			}
+
+			for _, block := range batch {
+				select {
+				case <-ctx.Done():
+					return nil
+				case results <- block:
+				}
+			}
+			next += uint64(len(batch))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-notifier.Channel():
+		}
+	}
+}
+
+// highestHeight returns the height of the latest sealed or finalized block.
+func (b *backendBlockStream) highestHeight(sealed bool) (uint64, error) {
+	var head *flow.Header
+	var err error
+	if sealed {
+		head, err = b.state.Sealed().Head()
+	} else {
+		head, err = b.state.Final().Head()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not get latest block: %w", err)
+	}
+	return head.Height, nil
+}
+
+// nextBatch assembles the blocks within the given height range (inclusive),
+// together with their events matching the filter. If events are requested, the
+// batch stops at the first block for which not enough execution receipts were
+// received yet.
+func (b *backendBlockStream) nextBatch(
+	ctx context.Context,
+	root *flow.Header,
+	start, end uint64,
+	filter access.EventFilter,
+) ([]*access.BlockWithEvents, error) {
+
+	withEvents := len(filter.EventTypes) > 0
+
+	headers := make([]*flow.Header, 0, end-start+1)
+	for height := start; height <= end; height++ {
+		header, err := b.headers.ByHeight(height)
+		if err != nil {
+			return nil, fmt.Errorf("could not get header at height %d: %w", height, err)
+		}
+
+		// the root block is never executed, but also has no events
+		if withEvents && header.Height != root.Height {
+			executorIDs, err := findAllExecutionNodes(header.ID(), b.executionReceipts, b.log)
+			if err != nil {
+				return nil, fmt.Errorf("could not get execution receipts for block %x: %w", header.ID(), err)
+			}
+			if len(executorIDs) < minExecutionNodesCnt {
+				break
+			}
+		}
+
+		headers = append(headers, header)
+	}
+
+	if len(headers) == 0 {
+		return nil, nil
+	}
+
+	events := make(map[flow.Identifier][]flow.Event, len(headers))
+	if withEvents {
+		// the root block can not be queried from execution nodes
+		queried := headers
+		if queried[0].Height == root.Height {
+			queried = queried[1:]
+		}
+
+		for _, eventType := range filter.EventTypes {
+			if len(queried) == 0 {
+				break
+			}
+			blockEvents, err := b.getEvents(ctx, queried, string(eventType))
+			if err != nil {
+				return nil, fmt.Errorf("could not get events of type %s: %w", eventType, err)
+			}
+			for _, be := range blockEvents {
+				for _, event := range be.Events {
+					if filter.Match(event) {
+						events[be.BlockID] = append(events[be.BlockID], event)
+					}
+				}
+			}
+		}
+	}
+
+	batch := make([]*access.BlockWithEvents, 0, len(headers))
+	for _, header := range headers {
+		blockID := header.ID()
+		block, err := b.blocks.ByID(blockID)
+		if err != nil {
+			return nil, fmt.Errorf("could not get block %x: %w", blockID, err)
+		}
+
+		// events of different types are retrieved separately, restore the execution order
+		blockEvents := events[blockID]
+		sort.Slice(blockEvents, func(i, j int) bool {
+			if blockEvents[i].TransactionIndex != blockEvents[j].TransactionIndex {
+				return blockEvents[i].TransactionIndex < blockEvents[j].TransactionIndex
+			}
+			return blockEvents[i].EventIndex < blockEvents[j].EventIndex
+		})
+
+		batch = append(batch, &access.BlockWithEvents{
+			Block:  block,
+			Events: blockEvents,
+		})
+	}
+
+	return batch, nil
+}
@@ -0,0 +1,319 @@
+package backend
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+type blockStreamSuite struct {
+	sync.Mutex
+	t        *testing.T
+	blocks   []*flow.Block
+	executed map[flow.Identifier]bool
+	final    uint64 // index of the latest finalized block
+	pruned   uint64 // index of the latest pruned block
+	stream   *backendBlockStream
+}
+
+// newBlockStreamSuite creates a chain of the given length, where the first block
+// is the root block and all blocks are finalized.
+func newBlockStreamSuite(t *testing.T, length int) *blockStreamSuite {
+	s := &blockStreamSuite{
+		t:        t,
+		executed: make(map[flow.Identifier]bool),
+		final:    uint64(length - 1),
+	}
+
+	root := unittest.BlockFixture()
+	s.blocks = append(s.blocks, &root)
+	for i := 1; i < length; i++ {
+		block := unittest.BlockWithParentFixture(s.blocks[i-1].Header)
+		s.blocks = append(s.blocks, &block)
+		s.executed[block.ID()] = true
+	}
+
+	state := new(protocol.State)
+	params := new(protocol.Params)
+	params.On("Root").Return(root.Header, nil)
+	state.On("Params").Return(params)
+	final := new(protocol.Snapshot)
+	final.On("Head").Return(func() *flow.Header {
+		s.Lock()
+		defer s.Unlock()
+		return s.blocks[s.final].Header
+	}, nil)
+	state.On("Final").Return(final)
+
+	headers := new(storagemock.Headers)
+	headers.On("ByHeight", mock.Anything).Return(
+		func(height uint64) *flow.Header {
+			block := s.byHeight(height)
+			if block == nil {
+				return nil
+			}
+			return block.Header
+		},
+		func(height uint64) error {
+			if s.isPruned(height) {
+				return storage.ErrPruned
+			}
+			if s.byHeight(height) == nil {
+				return storage.ErrNotFound
+			}
+			return nil
+		})
+
+	blocks := new(storagemock.Blocks)
+	blocks.On("ByID", mock.Anything).Return(
+		func(blockID flow.Identifier) *flow.Block {
+			return s.byID(blockID)
+		},
+		func(blockID flow.Identifier) error {
+			if s.byID(blockID) == nil {
+				return storage.ErrNotFound
+			}
+			return nil
+		})
+
+	receipts := new(storagemock.ExecutionReceipts)
+	receipts.On("ByBlockID", mock.Anything).Return(
+		func(blockID flow.Identifier) flow.ExecutionReceiptList {
+			s.Lock()
+			defer s.Unlock()
+			if !s.executed[blockID] {
+				return nil
+			}
+			block := s.byIDLocked(blockID)
+			receipt1 := unittest.ReceiptForBlockFixture(block)
+			receipt2 := unittest.ReceiptForBlockFixture(block)
+			receipt2.ExecutionResult = receipt1.ExecutionResult
+			return flow.ExecutionReceiptList{receipt1, receipt2}
+		},
+		nil)
+
+	s.stream = &backendBlockStream{
+		state:             state,
+		headers:           headers,
+		blocks:            blocks,
+		executionReceipts: receipts,
+		getEvents:         s.getEvents,
+		maxHeightRange:    2,
+		log:               zerolog.Nop(),
+	}
+
+	return s
+}
+
+func (s *blockStreamSuite) byHeight(height uint64) *flow.Block {
+	s.Lock()
+	defer s.Unlock()
+	for _, block := range s.blocks {
+		if block.Header.Height == height {
+			return block
+		}
+	}
+	return nil
+}
+
+func (s *blockStreamSuite) isPruned(height uint64) bool {
+	s.Lock()
+	defer s.Unlock()
+	root := s.blocks[0].Header.Height
+	return height > root && height <= root+s.pruned
+}
+
+func (s *blockStreamSuite) byID(blockID flow.Identifier) *flow.Block {
+	s.Lock()
+	defer s.Unlock()
+	return s.byIDLocked(blockID)
+}
+
+func (s *blockStreamSuite) byIDLocked(blockID flow.Identifier) *flow.Block {
+	for _, block := range s.blocks {
+		if block.ID() == blockID {
+			return block
+		}
+	}
+	return nil
+}
+
+// getEvents returns two events for every block: one of the requested type
+// emitted by the first transaction, and one of the requested type emitted by
+// the second transaction.
+func (s *blockStreamSuite) getEvents(_ context.Context, headers []*flow.Header, eventType string) ([]flow.BlockEvents, error) {
+	results := make([]flow.BlockEvents, 0, len(headers))
+	for _, header := range headers {
+		s.Lock()
+		executed := s.executed[header.ID()]
+		s.Unlock()
+		require.True(s.t, executed, "events requested for unexecuted block")
+
+		results = append(results, flow.BlockEvents{
+			BlockID:     header.ID(),
+			BlockHeight: header.Height,
+			Events: []flow.Event{
+				unittest.EventFixture(flow.EventType(eventType), 1, 0, unittest.IdentifierFixture(), 0),
+				unittest.EventFixture(flow.EventType(eventType), 0, 0, unittest.IdentifierFixture(), 0),
+			},
+		})
+	}
+	return results, nil
+}
+
+// extend appends a new finalized block to the chain.
+func (s *blockStreamSuite) extend(executed bool) *flow.Block {
+	s.Lock()
+	defer s.Unlock()
+	block := unittest.BlockWithParentFixture(s.blocks[len(s.blocks)-1].Header)
+	s.blocks = append(s.blocks, &block)
+	s.executed[block.ID()] = executed
+	s.final = uint64(len(s.blocks) - 1)
+	return &block
+}
+
+func (s *blockStreamSuite) receive(results <-chan *access.BlockWithEvents) *access.BlockWithEvents {
+	select {
+	case block, ok := <-results:
+		require.True(s.t, ok, "stream closed unexpectedly")
+		return block
+	case <-time.After(time.Second):
+		s.t.Fatal("timed out waiting for block")
+	}
+	return nil
+}
+
+func (s *blockStreamSuite) requireNoBlock(results <-chan *access.BlockWithEvents) {
+	select {
+	case block := <-results:
+		s.t.Fatalf("unexpected block at height %d", block.Block.Header.Height)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestBlockStream_BackfillAndFollow tests that subscribers first receive the
+// blocks from storage, and then newly finalized blocks once notified.
+func TestBlockStream_BackfillAndFollow(t *testing.T) {
+	s := newBlockStreamSuite(t, 5)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := s.blocks[1].Header.Height
+	results, err := s.stream.SubscribeBlocks(ctx, start, false, access.EventFilter{})
+	require.NoError(t, err)
+
+	for _, expected := range s.blocks[1:] {
+		block := s.receive(results)
+		assert.Equal(t, expected.ID(), block.Block.ID())
+		assert.Empty(t, block.Events)
+	}
+	s.requireNoBlock(results)
+
+	// without an event filter, blocks are streamed as soon as they are finalized
+	next := s.extend(false)
+	s.stream.notifyNewData()
+	assert.Equal(t, next.ID(), s.receive(results).Block.ID())
+
+	cancel()
+	select {
+	case _, ok := <-results:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("stream not closed after cancellation")
+	}
+}
+
+// TestBlockStream_Events tests that blocks are streamed with their events in
+// execution order, and only once they are executed.
+func TestBlockStream_Events(t *testing.T) {
+	s := newBlockStreamSuite(t, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	filter := access.EventFilter{EventTypes: []flow.EventType{flow.EventAccountCreated}}
+	root := s.blocks[0].Header.Height
+	results, err := s.stream.SubscribeBlocks(ctx, root, false, filter)
+	require.NoError(t, err)
+
+	// the root block carries no events
+	block := s.receive(results)
+	assert.Equal(t, s.blocks[0].ID(), block.Block.ID())
+	assert.Empty(t, block.Events)
+
+	for _, expected := range s.blocks[1:] {
+		block := s.receive(results)
+		assert.Equal(t, expected.ID(), block.Block.ID())
+		require.Len(t, block.Events, 2)
+		assert.Equal(t, uint32(0), block.Events[0].TransactionIndex)
+		assert.Equal(t, uint32(1), block.Events[1].TransactionIndex)
+	}
+
+	// a finalized but unexecuted block holds back the stream
+	pending := s.extend(false)
+	s.stream.notifyNewData()
+	s.requireNoBlock(results)
+
+	s.Lock()
+	s.executed[pending.ID()] = true
+	s.Unlock()
+	s.stream.notifyNewData()
+	assert.Equal(t, pending.ID(), s.receive(results).Block.ID())
+}
+
+// TestBlockStream_StartBelowRoot tests that subscriptions starting below the root block are rejected.
+func TestBlockStream_StartBelowRoot(t *testing.T) {
+	s := newBlockStreamSuite(t, 2)
+
+	_, err := s.stream.SubscribeBlocks(context.Background(), s.blocks[0].Header.Height-1, true, access.EventFilter{})
+	require.Error(t, err)
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
+}
+
+// TestBlockStream_StartPruned tests that subscriptions starting at a pruned block are rejected,
+// while subscriptions starting at the root block or after the pruned blocks are served.
+func TestBlockStream_StartPruned(t *testing.T) {
+	s := newBlockStreamSuite(t, 5)
+	s.pruned = 2
+
+	_, err := s.stream.SubscribeBlocks(context.Background(), s.blocks[2].Header.Height, false, access.EventFilter{})
+	require.Error(t, err)
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results, err := s.stream.SubscribeBlocks(ctx, s.blocks[3].Header.Height, false, access.EventFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, s.blocks[3].ID(), s.receive(results).Block.ID())
+
+	// the root block is never pruned
+	_, err = s.stream.SubscribeBlocks(ctx, s.blocks[0].Header.Height, false, access.EventFilter{})
+	require.NoError(t, err)
+}
+
+// TestBlockStream_InvalidFilter tests that account and contract filters without event types are rejected.
+func TestBlockStream_InvalidFilter(t *testing.T) {
+	s := newBlockStreamSuite(t, 2)
+
+	filter := access.EventFilter{Accounts: []flow.Address{unittest.AddressFixture()}}
+	_, err := s.stream.SubscribeBlocks(context.Background(), s.blocks[0].Header.Height, true, filter)
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -67,7 +67,7 @@ func (b *backendEvents) GetEventsForHeightRange(
 	for i := startHeight; i <= endHeight; i++ {
 		header, err := b.headers.ByHeight(i)
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to get events: %v", err)
+			return nil, convertStorageError(fmt.Errorf("failed to get events: %w", err))
 		}
 
 		blockHeaders = append(blockHeaders, header)
@@ -2,6 +2,7 @@ package backend
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"testing"
 	"time"
@@ -716,6 +717,65 @@ func (suite *Suite) TestGetLatestFinalizedBlock() {
 	suite.assertAllExpectations()
 }
 
+// TestGetBlockByHeightPruned tests that requesting a block which was pruned
+// results in an OutOfRange status, rather than NotFound, both by height and by ID
+func (suite *Suite) TestGetBlockByHeightPruned() {
+	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
+
+	height := uint64(5)
+	blockID := unittest.IdentifierFixture()
+	suite.blocks.
+		On("ByHeight", height).
+		Return(nil, fmt.Errorf("block at height %d was pruned: %w", height, storage.ErrPruned)).
+		Once()
+	suite.headers.
+		On("ByHeight", height).
+		Return(nil, fmt.Errorf("block at height %d was pruned: %w", height, storage.ErrPruned)).
+		Once()
+	suite.blocks.
+		On("ByID", blockID).
+		Return(nil, fmt.Errorf("block %x at height %d was pruned: %w", blockID, height, storage.ErrPruned)).
+		Once()
+	suite.headers.
+		On("ByBlockID", blockID).
+		Return(nil, fmt.Errorf("block %x at height %d was pruned: %w", blockID, height, storage.ErrPruned)).
+		Once()
+
+	backend := New(
+		suite.state,
+		nil, nil,
+		suite.blocks,
+		suite.headers,
+		nil, nil, nil, nil,
+		suite.chainID,
+		metrics.NewNoopCollector(),
+		nil,
+		false,
+		DefaultMaxHeightRange,
+		nil,
+		nil,
+		suite.log,
+	)
+
+	_, err := backend.GetBlockByHeight(context.Background(), height)
+	suite.Require().Error(err)
+	suite.Require().Equal(codes.OutOfRange, status.Code(err))
+
+	_, err = backend.GetBlockHeaderByHeight(context.Background(), height)
+	suite.Require().Error(err)
+	suite.Require().Equal(codes.OutOfRange, status.Code(err))
+
+	_, err = backend.GetBlockByID(context.Background(), blockID)
+	suite.Require().Error(err)
+	suite.Require().Equal(codes.OutOfRange, status.Code(err))
+
+	_, err = backend.GetBlockHeaderByID(context.Background(), blockID)
+	suite.Require().Error(err)
+	suite.Require().Equal(codes.OutOfRange, status.Code(err))
+
+	suite.assertAllExpectations()
+}
+
 type mockCloser struct{}
 
 func (mc *mockCloser) Close() error { return nil }
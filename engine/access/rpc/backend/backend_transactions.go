@@ -35,6 +35,7 @@ type backendTransactions struct {
 	transactionMetrics   module.TransactionMetrics
 	transactionValidator *access.TransactionValidator
 	retry                *Retry
+	subscriptions        *TransactionSubscriptions
 	connFactory          ConnectionFactory
 
 	previousAccessNodes []accessproto.AccessAPIClient
@@ -254,6 +255,40 @@ func (b *backendTransactions) GetTransactionResult(
 	}, nil
 }
 
+// SubscribeTransactionStatus subscribes to the status transitions of the given
+// transaction beyond the given last status. The returned channel is closed once
+// the transaction is sealed or expired, once the context is cancelled, or once
+// the subscriber falls behind, in which case it can resubscribe using the last
+// status it received.
+func (b *backendTransactions) SubscribeTransactionStatus(
+	ctx context.Context,
+	txID flow.Identifier,
+	lastStatus flow.TransactionStatus,
+) (<-chan *access.TransactionResult, error) {
+	return b.subscriptions.Subscribe(ctx, txID, lastStatus)
+}
+
+// lookupTransactionStatus resolves the current result of a subscribed transaction.
+// A finalized transaction can only transition to executed once enough execution
+// receipts were received for its block, so execution nodes are only queried then.
+func (b *backendTransactions) lookupTransactionStatus(
+	ctx context.Context,
+	txID flow.Identifier,
+	previous *access.TransactionResult,
+) (*access.TransactionResult, error) {
+	if previous != nil && previous.Status == flow.TransactionStatusFinalized {
+		executorIDs, err := findAllExecutionNodes(previous.BlockID, b.executionReceipts, b.log)
+		if err != nil {
+			return nil, convertStorageError(err)
+		}
+		if len(executorIDs) < minExecutionNodesCnt {
+			return previous, nil
+		}
+	}
+
+	return b.GetTransactionResult(ctx, txID)
+}
+
 // deriveTransactionStatus derives the transaction status based on current protocol state
 func (b *backendTransactions) deriveTransactionStatus(
 	tx *flow.TransactionBody,
@@ -465,6 +500,19 @@ func (b *backendTransactions) getTransactionResultFromExecutionNode(
 
 func (b *backendTransactions) NotifyFinalizedBlockHeight(height uint64) {
 	b.retry.Retry(height)
+	b.subscriptions.Notify()
+}
+
+// NotifyExecutionReceipt informs the backend that an execution receipt was
+// received, which may move subscribed transactions to the executed status.
+func (b *backendTransactions) NotifyExecutionReceipt(*flow.ExecutionReceipt) {
+	b.subscriptions.Notify()
+}
+
+// RunTransactionSubscriptions pushes status updates to transaction subscribers
+// until the context is cancelled.
+func (b *backendTransactions) RunTransactionSubscriptions(ctx context.Context) {
+	b.subscriptions.Run(ctx)
 }
 
 func (b *backendTransactions) getTransactionResultFromAnyExeNode(ctx context.Context, execNodes flow.IdentityList, req execproto.GetTransactionResultRequest) (*execproto.GetTransactionResultResponse, error) {
@@ -0,0 +1,237 @@
+package backend
+
+import (
+	"context"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// DefaultSubscriptionBufferSize is the default number of undelivered status
+// updates that are buffered for a single subscriber. A transaction goes through
+// at most four status transitions, so a subscriber only overflows its buffer if
+// it stops reading altogether.
+const DefaultSubscriptionBufferSize = 4
+
+// transactionStatusLookup resolves the current result of the given transaction.
+// The previously observed result, if any, is passed along so that the lookup
+// can skip expensive queries when no transition is possible yet.
+type transactionStatusLookup func(ctx context.Context, txID flow.Identifier, previous *access.TransactionResult) (*access.TransactionResult, error)
+
+// TransactionSubscriptions tracks clients subscribed to the status of
+// transactions and pushes status transitions to them. Transactions are
+// re-evaluated whenever the subscriptions are notified, which happens upon
+// block finalization and upon receiving execution receipts.
+//
+// Status transitions are monotonic (pending -> finalized -> executed -> sealed,
+// or pending -> expired), which lets subscribers resume a dropped subscription
+// by passing the last status they received as cursor.
+type TransactionSubscriptions struct {
+	mu         sync.Mutex
+	log        zerolog.Logger
+	lookup     transactionStatusLookup
+	bufferSize uint
+	nextID     uint64
+	byTxID     map[flow.Identifier]*transactionSubscription
+	notifier   engine.Notifier
+}
+
+// transactionSubscription holds all subscribers for a single transaction.
+type transactionSubscription struct {
+	latest      *access.TransactionResult // latest observed result, nil until the first lookup
+	subscribers map[uint64]*transactionSubscriber
+}
+
+// transactionSubscriber is a single client subscribed to a transaction.
+type transactionSubscriber struct {
+	cursor  flow.TransactionStatus // last status delivered to the subscriber
+	results chan *access.TransactionResult
+	done    chan struct{}
+}
+
+func newTransactionSubscriptions(log zerolog.Logger, lookup transactionStatusLookup, bufferSize uint) *TransactionSubscriptions {
+	return &TransactionSubscriptions{
+		log:        log.With().Str("component", "transaction_subscriptions").Logger(),
+		lookup:     lookup,
+		bufferSize: bufferSize,
+		byTxID:     make(map[flow.Identifier]*transactionSubscription),
+		notifier:   engine.NewNotifier(),
+	}
+}
+
+// Subscribe registers a subscriber for the status of the given transaction. The
+// returned channel receives every status transition strictly beyond the given
+// cursor and is closed once the transaction is sealed or expired, once the
+// context is cancelled, or once the subscriber falls behind by more than the
+// buffer size.
+func (s *TransactionSubscriptions) Subscribe(ctx context.Context, txID flow.Identifier, cursor flow.TransactionStatus) (<-chan *access.TransactionResult, error) {
+
+	// resolve the current status first, so lookup errors are reported to the caller
+	s.mu.Lock()
+	var previous *access.TransactionResult
+	if sub, ok := s.byTxID[txID]; ok {
+		previous = sub.latest
+	}
+	s.mu.Unlock()
+
+	result, err := s.lookup(ctx, txID, previous)
+	if err != nil {
+		return nil, err
+	}
+
+	subscriber := &transactionSubscriber{
+		cursor:  cursor,
+		results: make(chan *access.TransactionResult, s.bufferSize),
+		done:    make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	id := s.nextID
+	sub, ok := s.byTxID[txID]
+	if !ok {
+		sub = &transactionSubscription{
+			subscribers: make(map[uint64]*transactionSubscriber),
+		}
+		s.byTxID[txID] = sub
+	}
+	sub.subscribers[id] = subscriber
+	s.publish(txID, sub, result)
+	s.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.unsubscribe(txID, id)
+		case <-subscriber.done:
+		}
+	}()
+
+	return subscriber.results, nil
+}
+
+// Notify signals that the status of subscribed transactions may have changed.
+// It is non-blocking; the actual re-evaluation happens in Run.
+func (s *TransactionSubscriptions) Notify() {
+	s.notifier.Notify()
+}
+
+// Run re-evaluates the subscribed transactions whenever notified, until the
+// context is cancelled.
+func (s *TransactionSubscriptions) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.notifier.Channel():
+			s.update(ctx)
+		}
+	}
+}
+
+// Size returns the number of transactions with at least one subscriber.
+func (s *TransactionSubscriptions) Size() uint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return uint(len(s.byTxID))
+}
+
+// update looks up the current result of all subscribed transactions and
+// publishes any transitions to their subscribers.
+func (s *TransactionSubscriptions) update(ctx context.Context) {
+
+	// take a snapshot of the subscribed transactions, as the lookups are done
+	// without holding the lock
+	s.mu.Lock()
+	previous := make(map[flow.Identifier]*access.TransactionResult, len(s.byTxID))
+	for txID, sub := range s.byTxID {
+		previous[txID] = sub.latest
+	}
+	s.mu.Unlock()
+
+	for txID, prev := range previous {
+		if ctx.Err() != nil {
+			return
+		}
+
+		result, err := s.lookup(ctx, txID, prev)
+		if err != nil {
+			s.log.Debug().Err(err).Hex("tx_id", txID[:]).Msg("could not look up transaction status")
+			continue
+		}
+
+		s.mu.Lock()
+		sub, ok := s.byTxID[txID]
+		if ok {
+			s.publish(txID, sub, result)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// publish delivers the result to all subscribers of the transaction which have
+// not yet seen its status. Must be called with the lock held.
+func (s *TransactionSubscriptions) publish(txID flow.Identifier, sub *transactionSubscription, result *access.TransactionResult) {
+	if sub.latest != nil && result.Status < sub.latest.Status {
+		// never move backwards, e.g. when a lookup raced with a newer one
+		return
+	}
+	sub.latest = result
+
+	for id, subscriber := range sub.subscribers {
+		if result.Status <= subscriber.cursor {
+			continue
+		}
+		select {
+		case subscriber.results <- result:
+			subscriber.cursor = result.Status
+		default:
+			// the subscriber is not keeping up, drop it so it can resume from its cursor
+			s.log.Debug().Hex("tx_id", txID[:]).Msg("dropping slow transaction status subscriber")
+			s.remove(txID, sub, id)
+		}
+	}
+
+	if isFinalTransactionStatus(result.Status) {
+		for id := range sub.subscribers {
+			s.remove(txID, sub, id)
+		}
+	}
+}
+
+// unsubscribe removes the subscriber with the given ID, if it is still subscribed.
+func (s *TransactionSubscriptions) unsubscribe(txID flow.Identifier, id uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub, ok := s.byTxID[txID]
+	if !ok {
+		return
+	}
+	s.remove(txID, sub, id)
+}
+
+// remove closes the subscriber's channel and stops tracking the transaction
+// once it has no subscribers left. Must be called with the lock held.
+func (s *TransactionSubscriptions) remove(txID flow.Identifier, sub *transactionSubscription, id uint64) {
+	subscriber, ok := sub.subscribers[id]
+	if !ok {
+		return
+	}
+	delete(sub.subscribers, id)
+	close(subscriber.results)
+	close(subscriber.done)
+
+	if len(sub.subscribers) == 0 {
+		delete(s.byTxID, txID)
+	}
+}
+
+// isFinalTransactionStatus returns true if no further transitions can follow the given status.
+func isFinalTransactionStatus(status flow.TransactionStatus) bool {
+	return status == flow.TransactionStatusSealed || status == flow.TransactionStatusExpired
+}
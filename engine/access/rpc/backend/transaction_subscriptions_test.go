@@ -0,0 +1,173 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// statusSource is a stand-in for the backend, returning a configurable status per transaction.
+type statusSource struct {
+	sync.Mutex
+	statuses map[flow.Identifier]flow.TransactionStatus
+}
+
+func newStatusSource() *statusSource {
+	return &statusSource{statuses: make(map[flow.Identifier]flow.TransactionStatus)}
+}
+
+func (s *statusSource) set(txID flow.Identifier, status flow.TransactionStatus) {
+	s.Lock()
+	defer s.Unlock()
+	s.statuses[txID] = status
+}
+
+func (s *statusSource) lookup(_ context.Context, txID flow.Identifier, _ *access.TransactionResult) (*access.TransactionResult, error) {
+	s.Lock()
+	defer s.Unlock()
+	status, ok := s.statuses[txID]
+	if !ok {
+		return nil, fmt.Errorf("unknown transaction")
+	}
+	return &access.TransactionResult{Status: status}, nil
+}
+
+// receiveStatus waits for the next status on the channel.
+func receiveStatus(t *testing.T, results <-chan *access.TransactionResult) flow.TransactionStatus {
+	select {
+	case result, ok := <-results:
+		require.True(t, ok, "subscription closed unexpectedly")
+		return result.Status
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for status update")
+	}
+	return flow.TransactionStatusUnknown
+}
+
+// requireClosed asserts that the channel is closed without further results.
+func requireClosed(t *testing.T, results <-chan *access.TransactionResult) {
+	select {
+	case _, ok := <-results:
+		require.False(t, ok, "unexpected status update")
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for subscription to close")
+	}
+}
+
+// TestTransactionSubscriptions_Transitions tests that subscribers receive every
+// status transition in order, and that the subscription ends once sealed.
+func TestTransactionSubscriptions_Transitions(t *testing.T) {
+	source := newStatusSource()
+	subs := newTransactionSubscriptions(zerolog.Nop(), source.lookup, DefaultSubscriptionBufferSize)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go subs.Run(ctx)
+
+	txID := unittest.IdentifierFixture()
+	source.set(txID, flow.TransactionStatusPending)
+
+	results, err := subs.Subscribe(ctx, txID, flow.TransactionStatusUnknown)
+	require.NoError(t, err)
+	assert.Equal(t, flow.TransactionStatusPending, receiveStatus(t, results))
+
+	// notifications without a transition do not produce updates
+	subs.Notify()
+
+	for _, status := range []flow.TransactionStatus{
+		flow.TransactionStatusFinalized,
+		flow.TransactionStatusExecuted,
+		flow.TransactionStatusSealed,
+	} {
+		source.set(txID, status)
+		subs.Notify()
+		assert.Equal(t, status, receiveStatus(t, results))
+	}
+
+	requireClosed(t, results)
+	assert.Equal(t, uint(0), subs.Size())
+}
+
+// TestTransactionSubscriptions_Cursor tests that resuming a subscription only
+// delivers statuses beyond the cursor.
+func TestTransactionSubscriptions_Cursor(t *testing.T) {
+	source := newStatusSource()
+	subs := newTransactionSubscriptions(zerolog.Nop(), source.lookup, DefaultSubscriptionBufferSize)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go subs.Run(ctx)
+
+	txID := unittest.IdentifierFixture()
+	source.set(txID, flow.TransactionStatusFinalized)
+
+	// the cursor is already at the current status, nothing is delivered yet
+	results, err := subs.Subscribe(ctx, txID, flow.TransactionStatusFinalized)
+	require.NoError(t, err)
+
+	source.set(txID, flow.TransactionStatusExpired)
+	subs.Notify()
+	assert.Equal(t, flow.TransactionStatusExpired, receiveStatus(t, results))
+	requireClosed(t, results)
+
+	// resubscribing to a transaction in its final status closes the subscription right away
+	results, err = subs.Subscribe(ctx, txID, flow.TransactionStatusExpired)
+	require.NoError(t, err)
+	requireClosed(t, results)
+}
+
+// TestTransactionSubscriptions_SlowSubscriber tests that subscribers which do
+// not drain their buffer are dropped.
+func TestTransactionSubscriptions_SlowSubscriber(t *testing.T) {
+	source := newStatusSource()
+	subs := newTransactionSubscriptions(zerolog.Nop(), source.lookup, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	txID := unittest.IdentifierFixture()
+	source.set(txID, flow.TransactionStatusPending)
+
+	results, err := subs.Subscribe(ctx, txID, flow.TransactionStatusUnknown)
+	require.NoError(t, err)
+
+	// the buffer holds the pending status, the finalized status overflows it
+	source.set(txID, flow.TransactionStatusFinalized)
+	subs.update(ctx)
+
+	assert.Equal(t, flow.TransactionStatusPending, receiveStatus(t, results))
+	requireClosed(t, results)
+	assert.Equal(t, uint(0), subs.Size())
+}
+
+// TestTransactionSubscriptions_Cancel tests that cancelling the context ends the
+// subscription, and that lookup errors are returned to the subscriber.
+func TestTransactionSubscriptions_Cancel(t *testing.T) {
+	source := newStatusSource()
+	subs := newTransactionSubscriptions(zerolog.Nop(), source.lookup, DefaultSubscriptionBufferSize)
+
+	_, err := subs.Subscribe(context.Background(), unittest.IdentifierFixture(), flow.TransactionStatusUnknown)
+	require.Error(t, err)
+
+	txID := unittest.IdentifierFixture()
+	source.set(txID, flow.TransactionStatusUnknown)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results, err := subs.Subscribe(ctx, txID, flow.TransactionStatusUnknown)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), subs.Size())
+
+	cancel()
+	requireClosed(t, results)
+	assert.Equal(t, uint(0), subs.Size())
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/onflow/flow-go/access"
 	legacyaccess "github.com/onflow/flow-go/access/legacy"
 	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/access/rest"
 	"github.com/onflow/flow-go/engine/access/rpc/backend"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
@@ -57,6 +58,7 @@ type Engine struct {
 	config              Config
 	unsecureGrpcAddress net.Addr
 	secureGrpcAddress   net.Addr
+	httpAddress         net.Addr
 }
 
 // New returns a new RPC engine.
@@ -93,20 +95,27 @@ func New(log zerolog.Logger,
 		grpc.MaxSendMsgSize(config.MaxMsgSize),
 	}
 
-	var interceptors []grpc.UnaryServerInterceptor // ordered list of interceptors
+	var interceptors []grpc.UnaryServerInterceptor        // ordered list of interceptors
+	var streamInterceptors []grpc.StreamServerInterceptor // ordered list of stream interceptors
 	// if rpc metrics is enabled, first create the grpc metrics interceptor
 	if rpcMetricsEnabled {
 		interceptors = append(interceptors, grpc_prometheus.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, grpc_prometheus.StreamServerInterceptor)
 	}
 
 	// add the logging interceptor
 	interceptors = append(interceptors, loggingInterceptor(log)...)
+	streamInterceptors = append(streamInterceptors, loggingStreamInterceptor(log)...)
 
+	var restOpts []rest.Option
 	if len(apiRatelimits) > 0 {
 		// create a rate limit interceptor
-		rateLimitInterceptor := NewRateLimiterInterceptor(log, apiRatelimits, apiBurstLimits).unaryServerInterceptor
+		rateLimiter := NewRateLimiterInterceptor(log, apiRatelimits, apiBurstLimits)
+		// rate limit the REST API with the same limiters
+		restOpts = append(restOpts, rest.WithInterceptor(rateLimiter.restInterceptor))
 		// append the rate limit interceptor to the list of interceptors
-		interceptors = append(interceptors, rateLimitInterceptor)
+		interceptors = append(interceptors, rateLimiter.unaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, rateLimiter.streamServerInterceptor)
 	}
 
 	if len(interceptors) > 0 {
@@ -115,6 +124,12 @@ func New(log zerolog.Logger,
 		grpcOpts = append(grpcOpts, chainedInterceptors)
 	}
 
+	if len(streamInterceptors) > 0 {
+		// create a chained stream interceptor
+		chainedStreamInterceptors := grpc.ChainStreamInterceptor(streamInterceptors...)
+		grpcOpts = append(grpcOpts, chainedStreamInterceptors)
+	}
+
 	// create an unsecured grpc server
 	unsecureGrpcServer := grpc.NewServer(grpcOpts...)
 
@@ -122,9 +137,6 @@ func New(log zerolog.Logger,
 	grpcOpts = append(grpcOpts, grpc.Creds(config.TransportCredentials))
 	secureGrpcServer := grpc.NewServer(grpcOpts...)
 
-	// wrap the unsecured server with an HTTP proxy server to serve HTTP clients
-	httpServer := NewHTTPServer(unsecureGrpcServer, config.HTTPListenAddr)
-
 	connectionFactory := &backend.ConnectionFactoryImpl{
 		CollectionGRPCPort:        collectionGRPCPort,
 		ExecutionGRPCPort:         executionGRPCPort,
@@ -152,6 +164,11 @@ func New(log zerolog.Logger,
 		log,
 	)
 
+	// wrap the unsecured server with an HTTP proxy server to serve HTTP clients,
+	// and serve the REST API from the same server, rate limited like the GRPC API
+	restHandler := rest.NewHandler(log, backend, chainID.Chain(), config.MaxHeightRange, restOpts...)
+	httpServer := NewHTTPServer(unsecureGrpcServer, config.HTTPListenAddr, restHandler)
+
 	eng := &Engine{
 		log:                log,
 		unit:               engine.NewUnit(),
@@ -172,6 +189,17 @@ func New(log zerolog.Logger,
 		access.NewHandler(backend, chainID.Chain()),
 	)
 
+	// register the streaming extension of the Access API
+	access.RegisterAccessStreamAPIServer(
+		eng.unsecureGrpcServer,
+		access.NewHandler(backend, chainID.Chain()),
+	)
+
+	access.RegisterAccessStreamAPIServer(
+		eng.secureGrpcServer,
+		access.NewHandler(backend, chainID.Chain()),
+	)
+
 	if rpcMetricsEnabled {
 		// Not interested in legacy metrics, so initialize here
 		grpc_prometheus.EnableHandlingTimeHistogram()
@@ -199,6 +227,9 @@ func (e *Engine) Ready() <-chan struct{} {
 	e.unit.Launch(e.serveUnsecureGRPC)
 	e.unit.Launch(e.serveSecureGRPC)
 	e.unit.Launch(e.serveGRPCWebProxy)
+	e.unit.Launch(func() {
+		e.backend.RunTransactionSubscriptions(e.unit.Ctx())
+	})
 	return e.unit.Ready()
 }
 
@@ -234,6 +265,10 @@ func (e *Engine) SecureGRPCAddress() net.Addr {
 	return e.secureGrpcAddress
 }
 
+func (e *Engine) HTTPAddress() net.Addr {
+	return e.httpAddress
+}
+
 // process processes the given ingestion engine event. Events that are given
 // to this function originate within the expulsion engine on the node with the
 // given origin ID.
@@ -242,6 +277,9 @@ func (e *Engine) process(event interface{}) error {
 	case *flow.Block:
 		e.backend.NotifyFinalizedBlockHeight(entity.Header.Height)
 		return nil
+	case *flow.ExecutionReceipt:
+		e.backend.NotifyExecutionReceipt(entity)
+		return nil
 	default:
 		return fmt.Errorf("invalid event type (%T)", event)
 	}
@@ -299,7 +337,15 @@ func (e *Engine) serveGRPCWebProxy() {
 
 	log.Info().Msg("starting http proxy server on address")
 
-	err := e.httpServer.ListenAndServe()
+	l, err := net.Listen("tcp", e.config.HTTPListenAddr)
+	if err != nil {
+		e.log.Err(err).Msg("failed to start the http proxy server")
+		return
+	}
+
+	e.httpAddress = l.Addr()
+
+	err = e.httpServer.Serve(l) // blocking call
 	if errors.Is(err, http.ErrServerClosed) {
 		return
 	}
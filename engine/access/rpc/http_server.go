@@ -5,6 +5,8 @@ import (
 
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
 	"google.golang.org/grpc"
+
+	"github.com/onflow/flow-go/engine/access/rest"
 )
 
 type HTTPHeader struct {
@@ -27,10 +29,12 @@ var defaultHTTPHeaders = []HTTPHeader{
 	},
 }
 
-// NewHTTPServer creates and intializes a new HTTP GRPC proxy server
+// NewHTTPServer creates and intializes a new HTTP GRPC proxy server, which also
+// serves the REST API under the rest.Prefix path.
 func NewHTTPServer(
 	grpcServer *grpc.Server,
 	address string,
+	restHandler http.Handler,
 ) *http.Server {
 	wrappedServer := grpcweb.WrapServer(
 		grpcServer,
@@ -42,6 +46,9 @@ func NewHTTPServer(
 	// register gRPC HTTP proxy
 	mux.Handle("/", wrappedHandler(wrappedServer, defaultHTTPHeaders))
 
+	// register REST API
+	mux.Handle(rest.Prefix+"/", restAPIHandler(restHandler, defaultHTTPHeaders))
+
 	httpServer := &http.Server{
 		Addr:    address,
 		Handler: mux,
@@ -62,6 +69,18 @@ func wrappedHandler(wrappedServer *grpcweb.WrappedGrpcServer, headers []HTTPHead
 	}
 }
 
+func restAPIHandler(handler http.Handler, headers []HTTPHeader) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		setResponseHeaders(res, headers)
+
+		if req.Method == "OPTIONS" {
+			return
+		}
+
+		handler.ServeHTTP(res, req)
+	}
+}
+
 func setResponseHeaders(w http.ResponseWriter, headers []HTTPHeader) {
 	for _, header := range headers {
 		w.Header().Set(header.Key, header.Value)
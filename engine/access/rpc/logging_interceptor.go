@@ -23,3 +23,10 @@ func loggingInterceptor(log zerolog.Logger) []grpc.UnaryServerInterceptor {
 	loggingInterceptor := logging.UnaryServerInterceptor(grpczerolog.InterceptorLogger(log), logging.WithLevels(customClientCodeToLevel))
 	return []grpc.UnaryServerInterceptor{tagsInterceptor, loggingInterceptor}
 }
+
+// loggingStreamInterceptor creates the logging interceptors to log incoming GRPC streams (minus the payload bodies)
+func loggingStreamInterceptor(log zerolog.Logger) []grpc.StreamServerInterceptor {
+	tagsInterceptor := tags.StreamServerInterceptor(tags.WithFieldExtractor(tags.CodeGenRequestFieldExtractor))
+	loggingInterceptor := logging.StreamServerInterceptor(grpczerolog.InterceptorLogger(log), logging.WithLevels(customClientCodeToLevel))
+	return []grpc.StreamServerInterceptor{tagsInterceptor, loggingInterceptor}
+}
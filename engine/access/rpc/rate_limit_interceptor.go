@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"context"
+	"net/http"
 	"path/filepath"
 
 	"github.com/rs/zerolog"
@@ -62,16 +63,7 @@ func (interceptor *rateLimiterInterceptor) unaryServerInterceptor(ctx context.Co
 	// remove the package name (e.g. "/flow.access.AccessAPI/Ping" to "Ping")
 	methodName := filepath.Base(info.FullMethod)
 
-	// look up the limiter
-	limiter := interceptor.methodLimiterMap[methodName]
-
-	// if not found, use the default limiter
-	if limiter == nil {
-
-		interceptor.log.Trace().Str("method", methodName).Msg("rate limit not defined, using default limit")
-
-		limiter = interceptor.defaultLimiter
-	}
+	limiter := interceptor.limiter(methodName)
 
 	// check if request within limit
 	if !limiter.Allow() {
@@ -93,3 +85,74 @@ func (interceptor *rateLimiterInterceptor) unaryServerInterceptor(ctx context.Co
 
 	return h, err
 }
+
+// streamServerInterceptor rate limits the opening of the given stream based on the limits defined when creating
+// the rateLimiterInterceptor. Messages sent on an already open stream are not rate limited.
+func (interceptor *rateLimiterInterceptor) streamServerInterceptor(srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+
+	// remove the package name (e.g. "/flow.access.AccessAPI/SubscribeBlocks" to "SubscribeBlocks")
+	methodName := filepath.Base(info.FullMethod)
+
+	limiter := interceptor.limiter(methodName)
+
+	// check if stream within limit
+	if !limiter.Allow() {
+
+		// log the limit violation
+		interceptor.log.Trace().
+			Str("method", methodName).
+			Float64("limit", float64(limiter.Limit())).
+			Msg("rate limit exceeded")
+
+		// reject the stream
+		return status.Errorf(codes.ResourceExhausted, "%s rate limit reached, please retry later.",
+			info.FullMethod)
+	}
+
+	// call the handler
+	return handler(srv, stream)
+}
+
+// restInterceptor rate limits the given REST request based on the limits defined for the Access API method
+// serving it. The limiters are shared with the GRPC API, so the limits apply to the calls of both APIs together.
+func (interceptor *rateLimiterInterceptor) restInterceptor(req *http.Request, methodName string) error {
+
+	limiter := interceptor.limiter(methodName)
+
+	// check if request within limit
+	if !limiter.Allow() {
+
+		// log the limit violation
+		interceptor.log.Trace().
+			Str("method", methodName).
+			Str("path", req.URL.Path).
+			Float64("limit", float64(limiter.Limit())).
+			Msg("rate limit exceeded")
+
+		// reject the request, which the REST API responds to with 429 Too Many Requests
+		return status.Errorf(codes.ResourceExhausted, "%s rate limit reached, please retry later.",
+			methodName)
+	}
+
+	return nil
+}
+
+// limiter returns the limiter of the given method, or the default limiter if no limit is defined for it
+func (interceptor *rateLimiterInterceptor) limiter(methodName string) *rate.Limiter {
+
+	// look up the limiter
+	limiter := interceptor.methodLimiterMap[methodName]
+
+	// if not found, use the default limiter
+	if limiter == nil {
+
+		interceptor.log.Trace().Str("method", methodName).Msg("rate limit not defined, using default limit")
+
+		limiter = interceptor.defaultLimiter
+	}
+
+	return limiter
+}
@@ -112,6 +112,7 @@ const (
 	SyncCommittee     = network.Channel("sync-committee")
 	syncClusterPrefix = network.Channel("sync-cluster") // dynamic channel, use ChannelSyncCluster function
 	SyncExecution     = network.Channel("sync-execution")
+	SyncCheckpoint    = network.Channel("sync-checkpoint")
 
 	// Channels for dkg communication
 	DKGCommittee = "dkg-committee"
@@ -122,6 +123,7 @@ const (
 	PushBlocks       = network.Channel("push-blocks")
 	PushReceipts     = network.Channel("push-receipts")
 	PushApprovals    = network.Channel("push-approvals")
+	PushChallenges   = network.Channel("push-challenges")
 
 	// Channels for actively requesting missing entities
 	RequestCollections       = network.Channel("request-collections")
@@ -135,6 +137,7 @@ const (
 	ReceiveBlocks       = PushBlocks
 	ReceiveReceipts     = PushReceipts
 	ReceiveApprovals    = PushApprovals
+	ReceiveChallenges   = PushChallenges
 
 	ProvideCollections       = RequestCollections
 	ProvideChunks            = RequestChunks
@@ -163,6 +166,7 @@ func initializeChannelRoleMap() {
 	// Channels for protocols actively synchronizing state across nodes
 	channelRoleMap[SyncCommittee] = flow.RoleList{flow.RoleConsensus}
 	channelRoleMap[SyncExecution] = flow.RoleList{flow.RoleExecution}
+	channelRoleMap[SyncCheckpoint] = flow.RoleList{flow.RoleExecution}
 
 	// Channels for DKG communication
 	channelRoleMap[DKGCommittee] = flow.RoleList{flow.RoleConsensus}
@@ -175,6 +179,7 @@ func initializeChannelRoleMap() {
 	channelRoleMap[PushReceipts] = flow.RoleList{flow.RoleConsensus, flow.RoleExecution, flow.RoleVerification,
 		flow.RoleAccess}
 	channelRoleMap[PushApprovals] = flow.RoleList{flow.RoleConsensus, flow.RoleVerification}
+	channelRoleMap[PushChallenges] = flow.RoleList{flow.RoleConsensus, flow.RoleVerification}
 
 	// Channels for actively requesting missing entities
 	channelRoleMap[RequestCollections] = flow.RoleList{flow.RoleCollection, flow.RoleExecution}
@@ -189,6 +194,7 @@ func initializeChannelRoleMap() {
 	channelRoleMap[ReceiveReceipts] = flow.RoleList{flow.RoleConsensus, flow.RoleExecution, flow.RoleVerification,
 		flow.RoleAccess}
 	channelRoleMap[ReceiveApprovals] = flow.RoleList{flow.RoleConsensus, flow.RoleVerification}
+	channelRoleMap[ReceiveChallenges] = flow.RoleList{flow.RoleConsensus, flow.RoleVerification}
 
 	channelRoleMap[ProvideCollections] = flow.RoleList{flow.RoleCollection, flow.RoleExecution}
 	channelRoleMap[ProvideChunks] = flow.RoleList{flow.RoleExecution, flow.RoleVerification}
@@ -55,16 +55,18 @@ func TestGetChannelByRole(t *testing.T) {
 	// - PushApprovals
 	// - ProvideApprovalsByChunk
 	// - ProvideChunks
+	// - PushChallenges
 	// - TestNetwork
 	// - TestMetric
 	// the roles list should contain collection and consensus roles
 	topics := ChannelsByRole(flow.RoleVerification)
-	assert.Len(t, topics, 7)
+	assert.Len(t, topics, 8)
 	assert.Contains(t, topics, PushBlocks)
 	assert.Contains(t, topics, PushReceipts)
 	assert.Contains(t, topics, PushApprovals)
 	assert.Contains(t, topics, ProvideApprovalsByChunk)
 	assert.Contains(t, topics, RequestChunks)
+	assert.Contains(t, topics, PushChallenges)
 	assert.Contains(t, topics, TestMetrics)
 	assert.Contains(t, topics, TestNetwork)
 }
@@ -34,6 +34,7 @@ type Core struct {
 	pending           module.PendingClusterBlockBuffer // pending block cache
 	sync              module.BlockRequester
 	hotstuff          module.HotStuff
+	reportInvalid     func(originID flow.Identifier) // used to penalize the origins of invalid blocks
 }
 
 // NewCore instantiates the business logic for the collector clusters' compliance engine.
@@ -57,6 +58,7 @@ func NewCore(
 		pending:           pending,
 		sync:              nil, // use `WithSync`
 		hotstuff:          nil, // use `WithConsensus`
+		reportInvalid:     func(flow.Identifier) {},
 	}
 
 	// log the mempool size off the bat
@@ -169,7 +171,7 @@ func (c *Core) OnBlockProposal(originID flow.Identifier, proposal *messages.Clus
 	// execution of the entire recursion, which might include processing the
 	// proposal's pending children. There is another span within
 	// processBlockProposal that measures the time spent for a single proposal.
-	err = c.processBlockAndDescendants(proposal)
+	err = c.processBlockAndDescendants(originID, proposal)
 	c.mempoolMetrics.MempoolEntries(metrics.ResourceClusterProposal, c.pending.Size())
 	if err != nil {
 		return fmt.Errorf("could not process block proposal: %w", err)
@@ -182,7 +184,7 @@ func (c *Core) OnBlockProposal(originID flow.Identifier, proposal *messages.Clus
 // its pending proposals for its children. By induction, any children connected
 // to a valid proposal are validly connected to the finalized state and can be
 // processed as well.
-func (c *Core) processBlockAndDescendants(proposal *messages.ClusterBlockProposal) error {
+func (c *Core) processBlockAndDescendants(originID flow.Identifier, proposal *messages.ClusterBlockProposal) error {
 	blockID := proposal.Header.ID()
 
 	// process block itself
@@ -197,6 +199,7 @@ func (c *Core) processBlockAndDescendants(proposal *messages.ClusterBlockProposa
 	// ToDo: potential slashing
 	if engine.IsInvalidInputError(err) {
 		c.log.Warn().Err(err).Msg("received invalid block from other node (potential slashing evidence?)")
+		c.reportInvalid(originID)
 		return nil
 	}
 	if err != nil {
@@ -216,7 +219,7 @@ func (c *Core) processBlockAndDescendants(proposal *messages.ClusterBlockProposa
 			Header:  child.Header,
 			Payload: child.Payload,
 		}
-		cpr := c.processBlockAndDescendants(childProposal)
+		cpr := c.processBlockAndDescendants(child.OriginID, childProposal)
 		if cpr != nil {
 			// unexpected error: potentially corrupted internal state => abort processing and escalate error
 			return cpr
@@ -292,6 +295,18 @@ func (c *Core) OnBlockVote(originID flow.Identifier, vote *messages.ClusterBlock
 	return nil
 }
 
+// OnTimeout forwards incoming timeouts to the HotStuff instance.
+func (c *Core) OnTimeout(originID flow.Identifier, timeout *messages.ClusterTimeoutObject) error {
+
+	c.log.Debug().
+		Hex("origin_id", originID[:]).
+		Uint64("view", timeout.View).
+		Msg("received timeout")
+
+	c.hotstuff.SubmitTimeout(originID, timeout.View, timeout.HighestQC, timeout.HighestTC, timeout.SigData)
+	return nil
+}
+
 // prunePendingCache prunes the pending block cache by removing any blocks that
 // are below the finalized height.
 func (c *Core) prunePendingCache() {
@@ -288,7 +288,7 @@ func (cs *ComplianceCoreSuite) TestProcessBlockAndDescendants() {
 	cs.hotstuff.On("SubmitProposal", block3.Header, parent.Header.View).Return().Once()
 
 	// execute the connected children handling
-	err := cs.core.processBlockAndDescendants(proposal)
+	err := cs.core.processBlockAndDescendants(unittest.IdentifierFixture(), proposal)
 	require.NoError(cs.T(), err, "should pass handling children")
 
 	// check that we submitted each child to hotstuff
@@ -318,6 +318,26 @@ func (cs *ComplianceCoreSuite) TestOnSubmitVote() {
 	cs.hotstuff.AssertExpectations(cs.T())
 }
 
+func (cs *ComplianceCoreSuite) TestOnSubmitTimeout() {
+
+	// create a timeout
+	originID := unittest.IdentifierFixture()
+	timeout := messages.ClusterTimeoutObject{
+		View:      rand.Uint64(),
+		HighestQC: unittest.QuorumCertificateFixture(),
+		SigData:   unittest.SignatureFixture(),
+	}
+
+	cs.hotstuff.On("SubmitTimeout", originID, timeout.View, timeout.HighestQC, timeout.HighestTC, timeout.SigData).Return()
+
+	// execute the timeout submission
+	err := cs.core.OnTimeout(originID, &timeout)
+	require.NoError(cs.T(), err, "timeout should pass")
+
+	// check the submit timeout was called with correct parameters
+	cs.hotstuff.AssertExpectations(cs.T())
+}
+
 func (cs *ComplianceCoreSuite) TestProposalBufferingOrder() {
 
 	// create a proposal that we will not submit until the end
@@ -7,6 +7,7 @@ import (
 
 	"github.com/rs/zerolog"
 
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/fifoqueue"
 	"github.com/onflow/flow-go/model/cluster"
@@ -29,23 +30,27 @@ const defaultBlockQueueCapacity = 10000
 // defaultVoteQueueCapacity maximum capacity of block votes queue
 const defaultVoteQueueCapacity = 1000
 
+// defaultTimeoutQueueCapacity maximum capacity of timeouts queue
+const defaultTimeoutQueueCapacity = 1000
+
 // Engine is a wrapper struct for `Core` which implements cluster consensus algorithm.
 // Engine is responsible for handling incoming messages, queueing for processing, broadcasting proposals.
 type Engine struct {
-	unit           *engine.Unit
-	lm             *lifecycle.LifecycleManager
-	log            zerolog.Logger
-	metrics        module.EngineMetrics
-	me             module.Local
-	headers        storage.Headers
-	payloads       storage.ClusterPayloads
-	state          protocol.State
-	core           *Core
-	pendingBlocks  engine.MessageStore
-	pendingVotes   engine.MessageStore
-	messageHandler *engine.MessageHandler
-	con            network.Conduit
-	cluster        flow.IdentityList // consensus participants in our cluster
+	unit            *engine.Unit
+	lm              *lifecycle.LifecycleManager
+	log             zerolog.Logger
+	metrics         module.EngineMetrics
+	me              module.Local
+	headers         storage.Headers
+	payloads        storage.ClusterPayloads
+	state           protocol.State
+	core            *Core
+	pendingBlocks   engine.MessageStore
+	pendingVotes    engine.MessageStore
+	pendingTimeouts engine.MessageStore
+	messageHandler  *engine.MessageHandler
+	con             network.Conduit
+	cluster         flow.IdentityList // consensus participants in our cluster
 }
 
 func NewEngine(
@@ -93,6 +98,16 @@ func NewEngine(
 	}
 	pendingVotes := &engine.FifoMessageStore{FifoQueue: votesQueue}
 
+	// FIFO queue for timeouts
+	timeoutsQueue, err := fifoqueue.NewFifoQueue(
+		fifoqueue.WithCapacity(defaultTimeoutQueueCapacity),
+		fifoqueue.WithLengthObserver(func(len int) { core.mempoolMetrics.MempoolEntries(metrics.ResourceClusterTimeoutQueue, uint(len)) }),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create queue for inbound timeouts: %w", err)
+	}
+	pendingTimeouts := &engine.FifoMessageStore{FifoQueue: timeoutsQueue}
+
 	// define message queueing behaviour
 	handler := engine.NewMessageHandler(
 		engineLog,
@@ -138,23 +153,34 @@ func NewEngine(
 			},
 			Store: pendingVotes,
 		},
+		engine.Pattern{
+			Match: func(msg *engine.Message) bool {
+				_, ok := msg.Payload.(*messages.ClusterTimeoutObject)
+				if ok {
+					core.metrics.MessageReceived(metrics.EngineClusterCompliance, metrics.MessageClusterTimeoutObject)
+				}
+				return ok
+			},
+			Store: pendingTimeouts,
+		},
 	)
 
 	eng := &Engine{
-		unit:           engine.NewUnit(),
-		lm:             lifecycle.NewLifecycleManager(),
-		log:            engineLog,
-		metrics:        core.metrics,
-		me:             me,
-		headers:        core.headers,
-		payloads:       payloads,
-		state:          state,
-		core:           core,
-		pendingBlocks:  pendingBlocks,
-		pendingVotes:   pendingVotes,
-		messageHandler: handler,
-		con:            nil,
-		cluster:        currentCluster,
+		unit:            engine.NewUnit(),
+		lm:              lifecycle.NewLifecycleManager(),
+		log:             engineLog,
+		metrics:         core.metrics,
+		me:              me,
+		headers:         core.headers,
+		payloads:        payloads,
+		state:           state,
+		core:            core,
+		pendingBlocks:   pendingBlocks,
+		pendingVotes:    pendingVotes,
+		pendingTimeouts: pendingTimeouts,
+		messageHandler:  handler,
+		con:             nil,
+		cluster:         currentCluster,
 	}
 
 	chainID, err := core.state.Params().ChainID()
@@ -168,6 +194,7 @@ func NewEngine(
 		return nil, fmt.Errorf("could not register engine: %w", err)
 	}
 	eng.con = conduit
+	core.reportInvalid = conduit.ReportInvalidInput
 
 	return eng, nil
 }
@@ -277,6 +304,15 @@ func (e *Engine) processAvailableMessages() error {
 			continue
 		}
 
+		msg, ok = e.pendingTimeouts.Get()
+		if ok {
+			err := e.core.OnTimeout(msg.OriginID, msg.Payload.(*messages.ClusterTimeoutObject))
+			if err != nil {
+				return fmt.Errorf("could not handle timeout: %w", err)
+			}
+			continue
+		}
+
 		// when there is no more messages in the queue, back to the loop to wait
 		// for the next incoming message to arrive.
 		return nil
@@ -315,6 +351,49 @@ func (e *Engine) SendVote(blockID flow.Identifier, view uint64, sigData []byte,
 	return nil
 }
 
+// BroadcastTimeout submits a timeout to all the collection nodes in our cluster.
+func (e *Engine) BroadcastTimeout(timeout *model.TimeoutObject) error {
+
+	log := e.log.With().
+		Uint64("timeout_view", timeout.View).
+		Uint64("highest_qc_view", timeout.HighestQC.View).
+		Logger()
+
+	log.Debug().Msg("processing timeout broadcast request from hotstuff")
+
+	// retrieve all collection nodes in our cluster
+	recipients, err := e.state.Final().Identities(filter.And(
+		filter.In(e.cluster),
+		filter.Not(filter.HasNodeID(e.me.NodeID())),
+	))
+	if err != nil {
+		return fmt.Errorf("could not get cluster members: %w", err)
+	}
+
+	// create the timeout message for the cluster
+	msg := &messages.ClusterTimeoutObject{
+		View:      timeout.View,
+		HighestQC: timeout.HighestQC,
+		HighestTC: timeout.HighestTC,
+		SigData:   timeout.SigData,
+	}
+
+	e.unit.Launch(func() {
+		err := e.con.Publish(msg, recipients.NodeIDs()...)
+		if errors.Is(err, network.EmptyTargetList) {
+			return
+		}
+		if err != nil {
+			log.Warn().Err(err).Msg("could not broadcast timeout")
+			return
+		}
+		e.metrics.MessageSent(metrics.EngineClusterCompliance, metrics.MessageClusterTimeoutObject)
+		log.Debug().Msg("timeout broadcasted")
+	})
+
+	return nil
+}
+
 // BroadcastProposalWithDelay submits a cluster block proposal (effectively a proposal
 // for the next collection) to all the collection nodes in our cluster.
 func (e *Engine) BroadcastProposalWithDelay(header *flow.Header, delay time.Duration) error {
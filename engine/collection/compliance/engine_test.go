@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/model/cluster"
 	"github.com/onflow/flow-go/model/flow"
@@ -173,6 +174,43 @@ func (cs *ComplianceSuite) TestSendVote() {
 	cs.con.AssertCalled(cs.T(), "Unicast", &vote, recipientID)
 }
 
+// TestBroadcastTimeout tests that a timeout is broadcasted to all other
+// members of the cluster
+func (cs *ComplianceSuite) TestBroadcastTimeout() {
+
+	timeout := &model.TimeoutObject{
+		View:      rand.Uint64(),
+		HighestQC: unittest.QuorumCertificateFixture(),
+		SignerID:  cs.myID,
+		SigData:   unittest.SignatureFixture(),
+	}
+
+	// submit to broadcast timeout
+	err := cs.engine.BroadcastTimeout(timeout)
+	require.NoError(cs.T(), err, "timeout broadcast should pass")
+
+	done := func() <-chan struct{} {
+		channel := make(chan struct{})
+		close(channel)
+		return channel
+	}()
+
+	cs.hotstuff.On("Done", mock.Anything).Return(done)
+
+	// The timeout is transmitted asynchronously. We allow 10ms for the timeout to be received:
+	<-time.After(10 * time.Millisecond)
+	<-cs.engine.Done()
+
+	// the signer ID is conveyed over the network message
+	msg := &messages.ClusterTimeoutObject{
+		View:      timeout.View,
+		HighestQC: timeout.HighestQC,
+		HighestTC: timeout.HighestTC,
+		SigData:   timeout.SigData,
+	}
+	cs.con.AssertCalled(cs.T(), "Publish", msg, cs.cluster[1].NodeID, cs.cluster[2].NodeID)
+}
+
 // TestBroadcastProposalWithDelay tests broadcasting proposals with different
 // inputs
 func (cs *ComplianceSuite) TestBroadcastProposalWithDelay() {
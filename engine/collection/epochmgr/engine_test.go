@@ -117,7 +117,7 @@ func (suite *Suite) SetupTest() {
 	suite.AddEpoch(suite.counter)
 	suite.AddEpoch(suite.counter + 1)
 
-	suite.pools = epochs.NewTransactionPools(func() mempool.Transactions { return stdmap.NewTransactions(1000) })
+	suite.pools = epochs.NewTransactionPools(func(_ uint64) mempool.Transactions { return stdmap.NewTransactions(1000) })
 
 	var err error
 	suite.engine, err = New(suite.log, suite.me, suite.state, suite.pools, suite.voter, suite.factory, suite.heights)
@@ -31,6 +31,7 @@ type HotStuffFactory struct {
 	aggregator    module.AggregatingSigner
 	db            *badger.DB
 	protoState    protocol.State
+	evidence      storage.SlashingEvidence
 	createMetrics HotStuffMetricsFunc
 	opts          []consensus.Option
 }
@@ -41,6 +42,7 @@ func NewHotStuffFactory(
 	aggregator module.AggregatingSigner,
 	db *badger.DB,
 	protoState protocol.State,
+	evidence storage.SlashingEvidence,
 	createMetrics HotStuffMetricsFunc,
 	opts ...consensus.Option,
 ) (*HotStuffFactory, error) {
@@ -51,6 +53,7 @@ func NewHotStuffFactory(
 		aggregator:    aggregator,
 		db:            db,
 		protoState:    protoState,
+		evidence:      evidence,
 		createMetrics: createMetrics,
 		opts:          opts,
 	}
@@ -74,6 +77,7 @@ func (f *HotStuffFactory) Create(
 	notifier.AddConsumer(notifications.NewLogConsumer(f.log))
 	notifier.AddConsumer(hotmetrics.NewMetricsConsumer(metrics))
 	notifier.AddConsumer(notifications.NewTelemetryConsumer(f.log, cluster.ChainID()))
+	notifier.AddConsumer(notifications.NewSlashingViolationsConsumer(f.log, cluster.ChainID(), f.protoState, headers, f.evidence))
 	builder = blockproducer.NewMetricsWrapper(builder, metrics) // wrapper for measuring time spent building block payload component
 
 	var committee hotstuff.Committee
@@ -85,7 +89,7 @@ func (f *HotStuffFactory) Create(
 	committee = committees.NewMetricsWrapper(committee, metrics) // wrapper for measuring time spent determining consensus committee relations
 
 	// create a signing provider
-	var signer hotstuff.SignerVerifier = verification.NewSingleSignerVerifier(committee, f.aggregator, f.me.NodeID())
+	var signer hotstuff.SignerVerifier = verification.NewSingleSignerVerifier(committee, f.aggregator, f.me.NodeID(), cluster.ChainID())
 	signer = verification.NewMetricsWrapper(signer, metrics) // wrapper for measuring time spent with crypto-related operations
 
 	persist := persister.New(f.db, cluster.ChainID())
@@ -16,6 +16,7 @@ import (
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/logging"
 )
 
@@ -31,6 +32,7 @@ type Engine struct {
 	me                   module.Local
 	state                protocol.State
 	pools                *epochs.TransactionPools
+	journal              storage.TransactionJournal
 	transactionValidator *access.TransactionValidator
 
 	config Config
@@ -46,6 +48,7 @@ func New(
 	me module.Local,
 	chain flow.Chain,
 	pools *epochs.TransactionPools,
+	journal storage.TransactionJournal,
 	config Config,
 ) (*Engine, error) {
 
@@ -73,6 +76,7 @@ func New(
 		me:                   me,
 		state:                state,
 		pools:                pools,
+		journal:              journal,
 		config:               config,
 		transactionValidator: transactionValidator,
 	}
@@ -88,9 +92,9 @@ func New(
 }
 
 // Ready returns a ready channel that is closed once the engine has fully
-// started.
+// started, which includes restoring the journaled transactions.
 func (e *Engine) Ready() <-chan struct{} {
-	return e.unit.Ready()
+	return e.unit.Ready(e.replayJournal)
 }
 
 // Done returns a done channel that is closed once the engine has fully stopped.
@@ -223,9 +227,15 @@ func (e *Engine) onTransaction(originID flow.Identifier, tx *flow.TransactionBod
 
 	// if our cluster is responsible for the transaction, add it to the mempool
 	if localClusterFingerPrint == txClusterFingerPrint {
-		_ = pool.Add(tx)
-		e.colMetrics.TransactionIngested(txID)
-		log.Debug().Msg("added transaction to pool")
+		added := pool.Add(tx)
+		if added {
+			e.colMetrics.TransactionIngested(txID)
+			log.Debug().Msg("added transaction to pool")
+		} else {
+			// the transaction is either a duplicate, or was ejected right away
+			// as the pool is full of transactions with a higher priority
+			log.Debug().Msg("transaction not added to pool")
+		}
 	}
 
 	// if the message was submitted internally (ie. via the Access API)
@@ -249,3 +259,61 @@ func (e *Engine) onTransaction(originID flow.Identifier, tx *flow.TransactionBod
 
 	return nil
 }
+
+// replayJournal restores the transactions which were pending inclusion in a
+// collection when the node was stopped into the transaction pools. Journaled
+// transactions which are no longer valid, most notably because they expired
+// while the node was offline, are dropped from the journal instead, as are
+// transactions which no longer fit into a pool of a reduced size.
+//
+// A transaction included in a finalized cluster block right before the node
+// was stopped may still be journaled. The builder removes such transactions
+// from the pool, as they conflict with the finalized cluster blocks.
+func (e *Engine) replayJournal() {
+
+	journaled, err := e.journal.All()
+	if err != nil {
+		e.log.Error().Err(err).Msg("could not retrieve journaled transactions")
+		return
+	}
+
+	restored := 0
+	dropped := 0
+	for counter, transactions := range journaled {
+		for _, tx := range transactions {
+			txID := tx.ID()
+
+			err := e.transactionValidator.Validate(tx)
+			if err != nil {
+				e.log.Debug().Err(err).
+					Uint64("epoch", counter).
+					Hex("tx_id", txID[:]).
+					Msg("dropping invalid journaled transaction")
+				err = e.journal.Remove(counter, txID)
+				if err != nil {
+					e.log.Error().Err(err).Hex("tx_id", txID[:]).Msg("could not remove invalid journaled transaction")
+				}
+				dropped++
+				continue
+			}
+
+			pool := e.pools.ForEpoch(counter)
+			added := pool.Add(tx)
+			if !added && !pool.Has(txID) {
+				// the pool is full of transactions with a higher priority
+				err = e.journal.Remove(counter, txID)
+				if err != nil {
+					e.log.Error().Err(err).Hex("tx_id", txID[:]).Msg("could not remove ejected journaled transaction")
+				}
+				dropped++
+				continue
+			}
+			restored++
+		}
+	}
+
+	e.log.Info().
+		Int("restored", restored).
+		Int("dropped", dropped).
+		Msg("journaled transactions replayed")
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"io/ioutil"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/mock"
@@ -23,6 +24,7 @@ import (
 	realprotocol "github.com/onflow/flow-go/state/protocol"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
 	"github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
 	"github.com/onflow/flow-go/utils/unittest"
 	"github.com/onflow/flow-go/utils/unittest/mocks"
 )
@@ -37,7 +39,8 @@ type Suite struct {
 	me      *module.Local
 	conf    Config
 
-	pools *epochs.TransactionPools
+	pools   *epochs.TransactionPools
+	journal *storagemock.TransactionJournal
 
 	identities flow.IdentityList
 	clusters   flow.ClusterList
@@ -79,10 +82,12 @@ func (suite *Suite) SetupTest() {
 	suite.me = new(module.Local)
 	suite.me.On("NodeID").Return(me.NodeID)
 
-	suite.pools = epochs.NewTransactionPools(func() mempool.Transactions {
+	suite.pools = epochs.NewTransactionPools(func(_ uint64) mempool.Transactions {
 		return stdmap.NewTransactions(1000)
 	})
 
+	suite.journal = new(storagemock.TransactionJournal)
+
 	assignments := unittest.ClusterAssignment(suite.N_CLUSTERS, collectors)
 	suite.clusters, err = flow.NewClusterList(assignments, collectors)
 	suite.Require().NoError(err)
@@ -120,7 +125,7 @@ func (suite *Suite) SetupTest() {
 
 	suite.conf = DefaultConfig()
 	chain := flow.Testnet.Chain()
-	suite.engine, err = New(log, net, suite.state, metrics, metrics, suite.me, chain, suite.pools, suite.conf)
+	suite.engine, err = New(log, net, suite.state, metrics, metrics, suite.me, chain, suite.pools, suite.journal, suite.conf)
 	suite.Require().NoError(err)
 }
 
@@ -560,3 +565,31 @@ func (suite *Suite) TestRouting_ClusterAssignmentAdded() {
 	err = suite.engine.ProcessLocal(&tx)
 	suite.Assert().NoError(err)
 }
+
+// should restore journaled transactions into the pools of their epoch on
+// startup, and drop expired journaled transactions
+func (suite *Suite) TestReplayJournal() {
+
+	// "finalize" a sufficiently high block that root block is expired
+	final := unittest.BlockFixture()
+	final.Header.Height = suite.root.Header.Height + flow.DefaultTransactionExpiry + 1
+	suite.blocks[final.ID()] = &final
+	suite.final = &final
+
+	valid := unittest.TransactionBodyFixture()
+	valid.ReferenceBlockID = final.ID()
+	expired := unittest.TransactionBodyFixture()
+	expired.ReferenceBlockID = suite.root.ID()
+
+	suite.journal.On("All").Return(map[uint64][]*flow.TransactionBody{
+		1: {&valid, &expired},
+	}, nil).Once()
+	suite.journal.On("Remove", uint64(1), expired.ID()).Return(nil).Once()
+
+	unittest.AssertClosesBefore(suite.T(), suite.engine.Ready(), time.Second)
+
+	pool := suite.pools.ForEpoch(1)
+	suite.Assert().True(pool.Has(valid.ID()))
+	suite.Assert().False(pool.Has(expired.ID()))
+	suite.journal.AssertExpectations(suite.T())
+}
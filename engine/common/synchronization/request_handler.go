@@ -236,6 +236,11 @@ func (r *RequestHandlerEngine) onRangeRequest(originID flow.Identifier, req *mes
 			r.log.Error().Uint64("height", height).Msg("skipping unknown heights")
 			break
 		}
+		if errors.Is(err, storage.ErrPruned) {
+			// pruned heights are at the bottom of the range, so the remaining heights can still be served
+			r.log.Debug().Uint64("height", height).Msg("skipping pruned height")
+			continue
+		}
 		if err != nil {
 			return fmt.Errorf("could not get block for height (%d): %w", height, err)
 		}
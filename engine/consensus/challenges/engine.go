@@ -0,0 +1,474 @@
+package challenges
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/mempool/consensus"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// DefaultChallengeExpiry is the default number of finalized blocks above a
+// challenged block after which unresolved challenges of the block expire.
+const DefaultChallengeExpiry = 1000
+
+// Engine receives the chunk challenges raised by verification nodes. Each valid
+// challenge is persisted as evidence of the chunk fault, and the sealing of the
+// challenged execution result is suspended until the challenge is resolved.
+//
+// Challenges are only accepted from verification nodes which are assigned the
+// challenged chunk, for results which are incorporated in a known block.
+//
+// Challenges are resolved by human intervention, through Resolve. So that a
+// single challenge can't halt sealing forever, challenges which are not resolved
+// once the finalized height is more than the expiry above the challenged block
+// expire, and sealing of the challenged result resumes.
+type Engine struct {
+	unit       *engine.Unit                   // used for concurrency & shutdown
+	log        zerolog.Logger                 // used to log relevant actions with context
+	con        network.Conduit                // used to receive chunk challenges
+	state      protocol.State                 // used to access the protocol state
+	headers    storage.Headers                // used to find the blocks incorporating challenged results
+	index      storage.Index                  // used to find the blocks incorporating challenged results
+	me         module.Local                   // used to access local node information
+	verifier   module.Verifier                // used to verify the signatures of chunk challenges
+	assigner   module.ChunkAssigner           // used to check the challenger is assigned the challenged chunk
+	challenges storage.ChunkChallenges        // used to persist chunk challenges
+	suppressor *consensus.ChallengeSuppressor // used to suspend sealing of challenged results
+	expiry     uint64                         // number of finalized blocks above the challenged block after which challenges expire
+
+	mu         sync.Mutex
+	unresolved map[flow.Identifier]*flow.ChunkChallenge // unresolved challenges by ID
+	heights    map[flow.Identifier]uint64               // heights of the challenged blocks by challenge ID
+}
+
+// New creates a new chunk challenge engine. The unresolved challenges are loaded
+// from the database, so that they expire after a restart too.
+func New(
+	log zerolog.Logger,
+	net module.Network,
+	state protocol.State,
+	headers storage.Headers,
+	index storage.Index,
+	me module.Local,
+	verifier module.Verifier,
+	assigner module.ChunkAssigner,
+	challenges storage.ChunkChallenges,
+	suppressor *consensus.ChallengeSuppressor,
+	expiry uint64,
+) (*Engine, error) {
+
+	e := &Engine{
+		unit:       engine.NewUnit(),
+		log:        log.With().Str("engine", "challenges").Logger(),
+		state:      state,
+		headers:    headers,
+		index:      index,
+		me:         me,
+		verifier:   verifier,
+		assigner:   assigner,
+		challenges: challenges,
+		suppressor: suppressor,
+		expiry:     expiry,
+		unresolved: make(map[flow.Identifier]*flow.ChunkChallenge),
+		heights:    make(map[flow.Identifier]uint64),
+	}
+
+	unresolved, err := challenges.Unresolved()
+	if err != nil {
+		return nil, fmt.Errorf("could not load unresolved chunk challenges: %w", err)
+	}
+	for _, challenge := range unresolved {
+		header, err := headers.ByBlockID(challenge.Body.BlockID)
+		if err != nil {
+			return nil, fmt.Errorf("could not get challenged block %x: %w", challenge.Body.BlockID, err)
+		}
+		e.track(challenge, header.Height)
+	}
+
+	con, err := net.Register(engine.ReceiveChallenges, e)
+	if err != nil {
+		return nil, fmt.Errorf("could not register engine: %w", err)
+	}
+	e.con = con
+
+	return e, nil
+}
+
+// Ready returns a ready channel that is closed once the engine has fully
+// started.
+func (e *Engine) Ready() <-chan struct{} {
+	return e.unit.Ready()
+}
+
+// Done returns a done channel that is closed once the engine has fully stopped.
+func (e *Engine) Done() <-chan struct{} {
+	return e.unit.Done()
+}
+
+// SubmitLocal submits an event originating on the local node.
+func (e *Engine) SubmitLocal(event interface{}) {
+	e.unit.Launch(func() {
+		err := e.ProcessLocal(event)
+		if err != nil {
+			engine.LogError(e.log, err)
+		}
+	})
+}
+
+// Submit submits the given event from the node with the given origin ID
+// for processing in a non-blocking manner. It returns instantly and logs
+// a potential processing error internally when done.
+func (e *Engine) Submit(channel network.Channel, originID flow.Identifier, event interface{}) {
+	e.unit.Launch(func() {
+		err := e.Process(channel, originID, event)
+		if err != nil {
+			engine.LogError(e.log, err)
+		}
+	})
+}
+
+// ProcessLocal processes an event originating on the local node.
+func (e *Engine) ProcessLocal(event interface{}) error {
+	return e.unit.Do(func() error {
+		return e.process(e.me.NodeID(), event)
+	})
+}
+
+// Process processes the given event from the node with the given origin ID in
+// a blocking manner. It returns the potential processing error when done.
+func (e *Engine) Process(channel network.Channel, originID flow.Identifier, event interface{}) error {
+	return e.unit.Do(func() error {
+		return e.process(originID, event)
+	})
+}
+
+func (e *Engine) process(originID flow.Identifier, event interface{}) error {
+	switch ev := event.(type) {
+	case *flow.ChunkChallenge:
+		return e.onChunkChallenge(originID, ev)
+	default:
+		return fmt.Errorf("invalid event type (%T)", event)
+	}
+}
+
+// onChunkChallenge validates the given chunk challenge, persists it and
+// suspends the sealing of the challenged result.
+func (e *Engine) onChunkChallenge(originID flow.Identifier, challenge *flow.ChunkChallenge) error {
+	resultID := challenge.ExecutionResultID()
+	challengeID := challenge.ID()
+
+	log := e.log.With().
+		Hex("origin_id", originID[:]).
+		Hex("challenge_id", challengeID[:]).
+		Hex("result_id", resultID[:]).
+		Uint64("chunk_index", challenge.Body.ChunkIndex).
+		Str("fault_type", string(challenge.Body.FaultType)).
+		Logger()
+
+	// challenges which were stored before are either unresolved already, or were
+	// resolved or expired and must not suspend sealing again
+	_, err := e.challenges.ByID(challengeID)
+	if err == nil {
+		log.Debug().Msg("discarding known chunk challenge")
+		return nil
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("could not check for known chunk challenge: %w", err)
+	}
+
+	height, err := e.validateChallenge(originID, challenge)
+	if err != nil {
+		return fmt.Errorf("invalid chunk challenge: %w", err)
+	}
+
+	err = e.challenges.Store(challenge)
+	if err != nil {
+		return fmt.Errorf("could not store chunk challenge: %w", err)
+	}
+	e.mu.Lock()
+	e.track(challenge, height)
+	e.mu.Unlock()
+	e.suppressor.Challenge(resultID, challengeID)
+
+	log.Warn().
+		Str("fault", challenge.Body.Fault).
+		Msg("chunk challenge received, sealing of execution result suspended")
+
+	return nil
+}
+
+// validateChallenge checks that the challenge is raised by a staked
+// verification node at the challenged block, is signed by it, and that the
+// evidence it contains is consistent with the challenged result. It also checks
+// that the challenge hasn't expired, that the result is incorporated and that
+// the challenger is assigned the chunk. It returns the height of the challenged
+// block.
+func (e *Engine) validateChallenge(originID flow.Identifier, challenge *flow.ChunkChallenge) (uint64, error) {
+	body := challenge.Body
+
+	if body.ChallengerID != originID {
+		return 0, engine.NewInvalidInputErrorf("challenger (%x) does not match origin (%x)", body.ChallengerID, originID)
+	}
+	if body.ExecutionResult.BlockID != body.BlockID {
+		return 0, engine.NewInvalidInputErrorf("challenged result is for block %x, not %x", body.ExecutionResult.BlockID, body.BlockID)
+	}
+
+	challenger, err := e.state.AtBlockID(body.BlockID).Identity(originID)
+	if protocol.IsIdentityNotFound(err) {
+		return 0, engine.NewInvalidInputErrorf("unknown challenger (%x): %w", originID, err)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not get challenger identity: %w", err)
+	}
+	if challenger.Role != flow.RoleVerification {
+		return 0, engine.NewInvalidInputErrorf("invalid role for raising challenges: %s", challenger.Role)
+	}
+	if challenger.Stake == 0 || challenger.Ejected {
+		return 0, engine.NewInvalidInputErrorf("challenger (%x) is not staked", originID)
+	}
+
+	bodyID := body.ID()
+	valid, err := e.verifier.Verify(bodyID[:], challenge.ChallengerSignature, challenger.StakingPubKey)
+	if err != nil {
+		return 0, fmt.Errorf("could not verify challenger signature: %w", err)
+	}
+	if !valid {
+		return 0, engine.NewInvalidInputErrorf("invalid challenger signature")
+	}
+
+	chunk, ok := body.ExecutionResult.Chunks.ByIndex(body.ChunkIndex)
+	if !ok {
+		return 0, engine.NewInvalidInputErrorf("chunk index %d out of range", body.ChunkIndex)
+	}
+	if body.ChunkDataPackID == flow.ZeroID {
+		return 0, engine.NewInvalidInputErrorf("missing reference to chunk data pack")
+	}
+
+	resultID := body.ExecutionResult.ID()
+	for _, receipt := range body.Receipts {
+		if receipt.ResultID != resultID {
+			return 0, engine.NewInvalidInputErrorf("receipt %x does not commit to challenged result", receipt.ID())
+		}
+	}
+
+	header, err := e.headers.ByBlockID(body.BlockID)
+	if err != nil {
+		return 0, fmt.Errorf("could not get challenged block: %w", err)
+	}
+	final, err := e.state.Final().Head()
+	if err != nil {
+		return 0, fmt.Errorf("could not get finalized block: %w", err)
+	}
+	if final.Height > header.Height+e.expiry {
+		return 0, engine.NewInvalidInputErrorf("challenge expired at height %d, finalized height is %d", header.Height+e.expiry, final.Height)
+	}
+
+	err = e.checkAssignment(&body, chunk)
+	if err != nil {
+		return 0, err
+	}
+
+	return header.Height, nil
+}
+
+// checkAssignment checks that the challenged result is incorporated, and that
+// the challenger is assigned the challenged chunk by at least one block which
+// incorporates the result. The incorporating blocks are the first blocks on
+// each fork above the challenged block whose payload contains the result.
+func (e *Engine) checkAssignment(body *flow.ChunkChallengeBody, chunk *flow.Chunk) error {
+	resultID := body.ExecutionResult.ID()
+
+	incorporated := false
+	blockIDs := []flow.Identifier{body.BlockID}
+	for len(blockIDs) > 0 {
+		blockID := blockIDs[len(blockIDs)-1]
+		blockIDs = blockIDs[:len(blockIDs)-1]
+
+		children, err := e.headers.ByParentID(blockID)
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("could not get children of block %x: %w", blockID, err)
+		}
+
+		for _, child := range children {
+			childID := child.ID()
+			index, err := e.index.ByBlockID(childID)
+			if err != nil {
+				return fmt.Errorf("could not get payload index of block %x: %w", childID, err)
+			}
+			if !containsID(index.ResultIDs, resultID) {
+				blockIDs = append(blockIDs, childID)
+				continue
+			}
+
+			incorporated = true
+			assignment, err := e.assigner.Assign(&body.ExecutionResult, childID)
+			if err != nil {
+				return fmt.Errorf("could not get chunk assignment of result incorporated in block %x: %w", childID, err)
+			}
+			if assignment.HasVerifier(chunk, body.ChallengerID) {
+				return nil
+			}
+		}
+	}
+
+	if !incorporated {
+		return engine.NewInvalidInputErrorf("challenged result is not incorporated")
+	}
+	return engine.NewInvalidInputErrorf("challenger (%x) is not assigned chunk %d", body.ChallengerID, body.ChunkIndex)
+}
+
+func containsID(ids []flow.Identifier, id flow.Identifier) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// track keeps the given unresolved challenge of the block at the given height,
+// until it is resolved or expires. The caller must hold the lock.
+func (e *Engine) track(challenge *flow.ChunkChallenge, height uint64) {
+	challengeID := challenge.ID()
+	e.unresolved[challengeID] = challenge
+	e.heights[challengeID] = height
+}
+
+// untrack stops keeping the given challenge. The caller must hold the lock.
+func (e *Engine) untrack(challengeID flow.Identifier) {
+	delete(e.unresolved, challengeID)
+	delete(e.heights, challengeID)
+}
+
+// Unresolved returns the challenges which are neither resolved nor expired.
+func (e *Engine) Unresolved() []*flow.ChunkChallenge {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	challenges := make([]*flow.ChunkChallenge, 0, len(e.unresolved))
+	for _, challenge := range e.unresolved {
+		challenges = append(challenges, challenge)
+	}
+	return challenges
+}
+
+// Summary summarizes a chunk challenge for operators, without the evidence.
+type Summary struct {
+	ChallengeID  flow.Identifier     `json:"challenge_id"`
+	ResultID     flow.Identifier     `json:"result_id"`
+	BlockID      flow.Identifier     `json:"block_id"`
+	ChunkIndex   uint64              `json:"chunk_index"`
+	ChallengerID flow.Identifier     `json:"challenger_id"`
+	FaultType    flow.ChunkFaultType `json:"fault_type"`
+	Fault        string              `json:"fault"`
+}
+
+// Summarize summarizes the given chunk challenges.
+func Summarize(challenges []*flow.ChunkChallenge) []Summary {
+	summaries := make([]Summary, 0, len(challenges))
+	for _, challenge := range challenges {
+		summaries = append(summaries, Summary{
+			ChallengeID:  challenge.ID(),
+			ResultID:     challenge.ExecutionResultID(),
+			BlockID:      challenge.Body.BlockID,
+			ChunkIndex:   challenge.Body.ChunkIndex,
+			ChallengerID: challenge.Body.ChallengerID,
+			FaultType:    challenge.Body.FaultType,
+			Fault:        challenge.Body.Fault,
+		})
+	}
+	return summaries
+}
+
+// OnFinalizedBlock implements the `OnFinalizedBlock` callback from the
+// `hotstuff.FinalizationConsumer`. It expires the unresolved challenges.
+func (e *Engine) OnFinalizedBlock(flow.Identifier) {
+	e.unit.Launch(func() {
+		err := e.expireChallenges()
+		if err != nil {
+			e.log.Error().Err(err).Msg("could not expire chunk challenges")
+		}
+	})
+}
+
+// expireChallenges resolves the challenges whose expiry is below the finalized
+// height, which resumes the sealing of the challenged results.
+func (e *Engine) expireChallenges() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.unresolved) == 0 {
+		return nil
+	}
+
+	final, err := e.state.Final().Head()
+	if err != nil {
+		return fmt.Errorf("could not get finalized block: %w", err)
+	}
+
+	for challengeID, height := range e.heights {
+		if final.Height <= height+e.expiry {
+			continue
+		}
+
+		challenge := e.unresolved[challengeID]
+		err = e.challenges.Resolve(challengeID)
+		if err != nil {
+			return fmt.Errorf("could not resolve expired chunk challenge %x: %w", challengeID, err)
+		}
+		e.untrack(challengeID)
+		e.suppressor.Resolve(challenge.ExecutionResultID(), challengeID)
+
+		e.log.Error().
+			Hex("challenge_id", logging.ID(challengeID)).
+			Hex("result_id", logging.ID(challenge.ExecutionResultID())).
+			Uint64("expiry_height", height+e.expiry).
+			Msg("chunk challenge expired without resolution")
+	}
+
+	return nil
+}
+
+// Resolve resolves the chunk challenge with the given ID. The sealing of the
+// challenged result resumes once all its challenges are resolved.
+func (e *Engine) Resolve(challengeID flow.Identifier) error {
+	return e.unit.Do(func() error {
+		challenge, err := e.challenges.ByID(challengeID)
+		if err != nil {
+			return fmt.Errorf("could not retrieve chunk challenge: %w", err)
+		}
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		err = e.challenges.Resolve(challengeID)
+		if errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("chunk challenge %x is already resolved: %w", challengeID, err)
+		}
+		if err != nil {
+			return fmt.Errorf("could not resolve chunk challenge: %w", err)
+		}
+		e.untrack(challengeID)
+		e.suppressor.Resolve(challenge.ExecutionResultID(), challengeID)
+
+		e.log.Info().
+			Hex("challenge_id", logging.ID(challengeID)).
+			Hex("result_id", logging.ID(challenge.ExecutionResultID())).
+			Msg("chunk challenge resolved")
+
+		return nil
+	})
+}
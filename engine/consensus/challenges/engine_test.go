@@ -0,0 +1,235 @@
+package challenges
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/onflow/flow-go/engine"
+	chmodels "github.com/onflow/flow-go/model/chunks"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool/consensus"
+	module "github.com/onflow/flow-go/module/mock"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	mockstorage "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+type Suite struct {
+	suite.Suite
+
+	me         *module.Local
+	state      *protocol.State
+	snapshot   *protocol.Snapshot
+	final      *flow.Header
+	headers    *mockstorage.Headers
+	index      *mockstorage.Index
+	verifier   *module.Verifier
+	assigner   *module.ChunkAssigner
+	challenges *mockstorage.ChunkChallenges
+	suppressor *consensus.ChallengeSuppressor
+
+	challenger    *flow.Identity
+	challenge     *flow.ChunkChallenge
+	incorporating *flow.Header
+	assignment    *chmodels.Assignment
+
+	engine *Engine
+}
+
+func TestChallengesEngine(t *testing.T) {
+	suite.Run(t, new(Suite))
+}
+
+func (suite *Suite) SetupTest() {
+	suite.me = new(module.Local)
+	suite.state = new(protocol.State)
+	suite.snapshot = new(protocol.Snapshot)
+	suite.headers = new(mockstorage.Headers)
+	suite.index = new(mockstorage.Index)
+	suite.verifier = new(module.Verifier)
+	suite.assigner = new(module.ChunkAssigner)
+	suite.challenges = new(mockstorage.ChunkChallenges)
+
+	suite.challenger = unittest.IdentityFixture(unittest.WithRole(flow.RoleVerification))
+	suite.challenge = unittest.ChunkChallengeFixture(func(challenge *flow.ChunkChallenge) {
+		challenge.Body.ChallengerID = suite.challenger.NodeID
+	})
+
+	suite.challenges.On("Unresolved").Return(nil, nil).Once()
+	var err error
+	suite.suppressor, err = consensus.NewChallengeSuppressor(nil, suite.challenges, zerolog.Nop())
+	suite.Require().NoError(err)
+
+	suite.state.On("AtBlockID", suite.challenge.Body.BlockID).Return(suite.snapshot)
+	suite.snapshot.On("Identity", suite.challenger.NodeID).Return(suite.challenger, nil)
+	suite.challenges.On("ByID", suite.challenge.ID()).Return(nil, storage.ErrNotFound).Once()
+
+	// the challenged result is incorporated in a child of the executed block,
+	// which assigns the challenged chunk to the challenger
+	executed := unittest.BlockHeaderFixture()
+	executed.Height = 10
+	suite.incorporating = &flow.Header{ParentID: suite.challenge.Body.BlockID, Height: 11}
+	suite.final = &flow.Header{Height: 12}
+	suite.assignment = chmodels.NewAssignment()
+	suite.assignment.Add(suite.challenge.Body.ExecutionResult.Chunks[0], flow.IdentifierList{suite.challenger.NodeID})
+
+	final := new(protocol.Snapshot)
+	final.On("Head").Return(
+		func() *flow.Header { return suite.final },
+		func() error { return nil },
+	)
+	suite.state.On("Final").Return(final)
+	suite.headers.On("ByBlockID", suite.challenge.Body.BlockID).Return(&executed, nil)
+	suite.headers.On("ByParentID", suite.challenge.Body.BlockID).Return([]*flow.Header{suite.incorporating}, nil)
+	suite.headers.On("ByParentID", suite.incorporating.ID()).Return(nil, storage.ErrNotFound)
+	suite.index.On("ByBlockID", suite.incorporating.ID()).Return(
+		func(flow.Identifier) *flow.Index {
+			return &flow.Index{ResultIDs: []flow.Identifier{suite.challenge.ExecutionResultID()}}
+		},
+		nil,
+	)
+	suite.assigner.On("Assign", &suite.challenge.Body.ExecutionResult, suite.incorporating.ID()).Return(
+		func(*flow.ExecutionResult, flow.Identifier) *chmodels.Assignment { return suite.assignment },
+		nil,
+	)
+
+	suite.engine = &Engine{
+		unit:       engine.NewUnit(),
+		log:        zerolog.Nop(),
+		state:      suite.state,
+		headers:    suite.headers,
+		index:      suite.index,
+		me:         suite.me,
+		verifier:   suite.verifier,
+		assigner:   suite.assigner,
+		challenges: suite.challenges,
+		suppressor: suite.suppressor,
+		expiry:     100,
+		unresolved: make(map[flow.Identifier]*flow.ChunkChallenge),
+		heights:    make(map[flow.Identifier]uint64),
+	}
+}
+
+// TestOnChunkChallenge checks that a valid challenge is stored and suspends
+// sealing of the challenged result until it is resolved.
+func (suite *Suite) TestOnChunkChallenge() {
+	resultID := suite.challenge.ExecutionResultID()
+	suite.verifier.On("Verify", mock.Anything, suite.challenge.ChallengerSignature, suite.challenger.StakingPubKey).Return(true, nil).Once()
+	suite.challenges.On("Store", suite.challenge).Return(nil).Once()
+
+	err := suite.engine.Process(engine.ReceiveChallenges, suite.challenger.NodeID, suite.challenge)
+	suite.Require().NoError(err)
+	suite.Assert().True(suite.suppressor.Challenged(resultID))
+	suite.Assert().Equal([]*flow.ChunkChallenge{suite.challenge}, suite.engine.Unresolved())
+	suite.challenges.AssertExpectations(suite.T())
+
+	suite.challenges.On("ByID", suite.challenge.ID()).Return(suite.challenge, nil)
+	suite.challenges.On("Resolve", suite.challenge.ID()).Return(nil).Once()
+	err = suite.engine.Resolve(suite.challenge.ID())
+	suite.Require().NoError(err)
+	suite.Assert().False(suite.suppressor.Challenged(resultID))
+	suite.Assert().Empty(suite.engine.Unresolved())
+
+	// receiving the resolved challenge again doesn't suspend sealing
+	err = suite.engine.Process(engine.ReceiveChallenges, suite.challenger.NodeID, suite.challenge)
+	suite.Require().NoError(err)
+	suite.Assert().False(suite.suppressor.Challenged(resultID))
+
+	// resolving it again fails
+	suite.challenges.On("Resolve", suite.challenge.ID()).Return(storage.ErrNotFound).Once()
+	err = suite.engine.Resolve(suite.challenge.ID())
+	suite.Assert().True(errors.Is(err, storage.ErrNotFound))
+}
+
+// TestOnChunkChallenge_Invalid checks that invalid challenges are rejected
+// without being stored.
+func (suite *Suite) TestOnChunkChallenge_Invalid() {
+	suite.challenges.On("ByID", mock.Anything).Return(nil, storage.ErrNotFound)
+
+	suite.Run("origin is not the challenger", func() {
+		err := suite.engine.Process(engine.ReceiveChallenges, unittest.IdentifierFixture(), suite.challenge)
+		suite.Assert().True(engine.IsInvalidInputError(err))
+	})
+
+	suite.Run("challenger is not a verification node", func() {
+		suite.challenger.Role = flow.RoleExecution
+		defer func() { suite.challenger.Role = flow.RoleVerification }()
+		err := suite.engine.Process(engine.ReceiveChallenges, suite.challenger.NodeID, suite.challenge)
+		suite.Assert().True(engine.IsInvalidInputError(err))
+	})
+
+	suite.Run("invalid signature", func() {
+		suite.verifier.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(false, nil).Once()
+		err := suite.engine.Process(engine.ReceiveChallenges, suite.challenger.NodeID, suite.challenge)
+		suite.Assert().True(engine.IsInvalidInputError(err))
+	})
+
+	suite.Run("missing chunk data pack reference", func() {
+		challenge := *suite.challenge
+		challenge.Body.ChunkDataPackID = flow.ZeroID
+		suite.challenges.On("ByID", challenge.ID()).Return(nil, storage.ErrNotFound).Once()
+		suite.verifier.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+		err := suite.engine.Process(engine.ReceiveChallenges, suite.challenger.NodeID, &challenge)
+		suite.Assert().True(engine.IsInvalidInputError(err))
+	})
+
+	suite.Run("expired challenge", func() {
+		suite.final = &flow.Header{Height: 111}
+		defer func() { suite.final = &flow.Header{Height: 12} }()
+		suite.verifier.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+		err := suite.engine.Process(engine.ReceiveChallenges, suite.challenger.NodeID, suite.challenge)
+		suite.Assert().True(engine.IsInvalidInputError(err))
+	})
+
+	suite.Run("result not incorporated", func() {
+		challenge := *suite.challenge
+		challenge.Body.ExecutionResult.PreviousResultID = unittest.IdentifierFixture()
+		challenge.Body.Receipts = nil
+		suite.challenges.On("ByID", challenge.ID()).Return(nil, storage.ErrNotFound).Once()
+		suite.verifier.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+		err := suite.engine.Process(engine.ReceiveChallenges, suite.challenger.NodeID, &challenge)
+		suite.Assert().True(engine.IsInvalidInputError(err))
+	})
+
+	suite.Run("challenger not assigned the chunk", func() {
+		assignment := suite.assignment
+		suite.assignment = chmodels.NewAssignment()
+		defer func() { suite.assignment = assignment }()
+		suite.verifier.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+		err := suite.engine.Process(engine.ReceiveChallenges, suite.challenger.NodeID, suite.challenge)
+		suite.Assert().True(engine.IsInvalidInputError(err))
+	})
+
+	suite.challenges.AssertNotCalled(suite.T(), "Store", mock.Anything)
+	require.False(suite.T(), suite.suppressor.Challenged(suite.challenge.ExecutionResultID()))
+}
+
+// TestExpireChallenges checks that unresolved challenges expire once the
+// finalized height passes their expiry, which resumes sealing.
+func (suite *Suite) TestExpireChallenges() {
+	resultID := suite.challenge.ExecutionResultID()
+	suite.verifier.On("Verify", mock.Anything, suite.challenge.ChallengerSignature, suite.challenger.StakingPubKey).Return(true, nil).Once()
+	suite.challenges.On("Store", suite.challenge).Return(nil).Once()
+
+	err := suite.engine.Process(engine.ReceiveChallenges, suite.challenger.NodeID, suite.challenge)
+	suite.Require().NoError(err)
+	suite.Require().True(suite.suppressor.Challenged(resultID))
+
+	// the challenge expires above height 110
+	suite.final = &flow.Header{Height: 110}
+	suite.Require().NoError(suite.engine.expireChallenges())
+	suite.Assert().True(suite.suppressor.Challenged(resultID))
+
+	suite.final = &flow.Header{Height: 111}
+	suite.challenges.On("Resolve", suite.challenge.ID()).Return(nil).Once()
+	suite.Require().NoError(suite.engine.expireChallenges())
+	suite.Assert().False(suite.suppressor.Challenged(resultID))
+	suite.Assert().Empty(suite.engine.Unresolved())
+	suite.challenges.AssertExpectations(suite.T())
+}
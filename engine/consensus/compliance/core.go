@@ -38,6 +38,7 @@ type Core struct {
 	pending           module.PendingBlockBuffer // pending block cache
 	sync              module.BlockRequester
 	hotstuff          module.HotStuff
+	reportInvalid     func(originID flow.Identifier) // used to penalize the origins of invalid blocks
 }
 
 // NewCore instantiates the business logic for the main consensus' compliance engine.
@@ -68,6 +69,7 @@ func NewCore(
 		pending:           pending,
 		sync:              sync,
 		hotstuff:          nil, // use `WithConsensus`
+		reportInvalid:     func(flow.Identifier) {},
 	}
 
 	e.mempool.MempoolEntries(metrics.ResourceProposal, e.pending.Size())
@@ -196,7 +198,7 @@ func (c *Core) OnBlockProposal(originID flow.Identifier, proposal *messages.Bloc
 	// proposal's pending children. There is another span within
 	// processBlockProposal that measures the time spent for a single proposal.
 	recursiveProcessSpan := c.tracer.StartSpanFromParent(onBlockProposalSpan, trace.CONCompOnBlockProposalProcessRecursive)
-	err = c.processBlockAndDescendants(proposal)
+	err = c.processBlockAndDescendants(originID, proposal)
 	c.mempool.MempoolEntries(metrics.ResourceProposal, c.pending.Size())
 	recursiveProcessSpan.Finish()
 	if err != nil {
@@ -216,7 +218,7 @@ func (c *Core) OnBlockProposal(originID flow.Identifier, proposal *messages.Bloc
 // its pending proposals for its children. By induction, any children connected
 // to a valid proposal are validly connected to the finalized state and can be
 // processed as well.
-func (c *Core) processBlockAndDescendants(proposal *messages.BlockProposal) error {
+func (c *Core) processBlockAndDescendants(originID flow.Identifier, proposal *messages.BlockProposal) error {
 	blockID := proposal.Header.ID()
 
 	// process block itself
@@ -231,6 +233,7 @@ func (c *Core) processBlockAndDescendants(proposal *messages.BlockProposal) erro
 	// ToDo: potential slashing
 	if engine.IsInvalidInputError(err) {
 		c.log.Warn().Err(err).Msg("received invalid block from other node (potential slashing evidence?)")
+		c.reportInvalid(originID)
 		return nil
 	}
 	if err != nil {
@@ -250,7 +253,7 @@ func (c *Core) processBlockAndDescendants(proposal *messages.BlockProposal) erro
 			Header:  child.Header,
 			Payload: child.Payload,
 		}
-		cpr := c.processBlockAndDescendants(childProposal)
+		cpr := c.processBlockAndDescendants(child.OriginID, childProposal)
 		if cpr != nil {
 			// unexpected error: potentially corrupted internal state => abort processing and escalate error
 			return cpr
@@ -344,6 +347,15 @@ func (c *Core) OnBlockVote(originID flow.Identifier, vote *messages.BlockVote) e
 	return nil
 }
 
+// OnTimeout handles incoming timeouts, forwarding them to hotstuff.
+func (c *Core) OnTimeout(originID flow.Identifier, timeout *messages.TimeoutObject) error {
+	log := c.log.With().Uint64("timeout_view", timeout.View).Hex("signer", originID[:]).Logger()
+	log.Info().Msg("timeout received")
+	log.Info().Msg("forwarding timeout to hotstuff") // to keep logging consistent with votes
+	c.hotstuff.SubmitTimeout(originID, timeout.View, timeout.HighestQC, timeout.HighestTC, timeout.SigData)
+	return nil
+}
+
 // prunePendingCache prunes the pending block cache.
 func (c *Core) prunePendingCache() {
 
@@ -20,6 +20,7 @@ import (
 	"github.com/onflow/flow-go/module/trace"
 	netint "github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/state"
 	protint "github.com/onflow/flow-go/state/protocol"
 	protocol "github.com/onflow/flow-go/state/protocol/mock"
 	storerr "github.com/onflow/flow-go/storage"
@@ -327,6 +328,34 @@ func (cs *ComplianceCoreSuite) TestOnBlockProposalInvalidExtension() {
 	cs.hotstuff.AssertExpectations(cs.T())
 }
 
+// TestOnBlockProposalInvalidInputReported checks that the origin of a block
+// which is an invalid extension of the protocol state is reported.
+func (cs *ComplianceCoreSuite) TestOnBlockProposalInvalidInputReported() {
+	originID := cs.participants[1].NodeID
+	block := unittest.BlockWithParentFixture(cs.head)
+	proposal := unittest.ProposalFromBlock(&block)
+
+	var reported []flow.Identifier
+	cs.core.reportInvalid = func(originID flow.Identifier) {
+		reported = append(reported, originID)
+	}
+
+	*cs.state = protocol.MutableState{}
+	cs.state.On("Final").Return(
+		func() protint.Snapshot {
+			return cs.snapshot
+		},
+	)
+	cs.state.On("Extend", mock.Anything).Return(state.NewInvalidExtensionError("invalid payload"))
+
+	// the invalid block is dropped without error, and its origin is reported
+	err := cs.core.OnBlockProposal(originID, proposal)
+	require.NoError(cs.T(), err)
+	require.Equal(cs.T(), []flow.Identifier{originID}, reported)
+
+	cs.hotstuff.AssertNotCalled(cs.T(), "SubmitProposal", mock.Anything, mock.Anything)
+}
+
 func (cs *ComplianceCoreSuite) TestProcessBlockAndDescendants() {
 
 	// create three children blocks
@@ -356,7 +385,7 @@ func (cs *ComplianceCoreSuite) TestProcessBlockAndDescendants() {
 	cs.hotstuff.On("SubmitProposal", block3.Header, parent.Header.View).Return().Once()
 
 	// execute the connected children handling
-	err := cs.core.processBlockAndDescendants(proposal)
+	err := cs.core.processBlockAndDescendants(unittest.IdentifierFixture(), proposal)
 	require.NoError(cs.T(), err, "should pass handling children")
 
 	// check that we submitted each child to hotstuff
@@ -386,6 +415,26 @@ func (cs *ComplianceCoreSuite) TestOnSubmitVote() {
 	cs.hotstuff.AssertExpectations(cs.T())
 }
 
+func (cs *ComplianceCoreSuite) TestOnSubmitTimeout() {
+
+	// create a timeout
+	originID := unittest.IdentifierFixture()
+	timeout := messages.TimeoutObject{
+		View:      rand.Uint64(),
+		HighestQC: unittest.QuorumCertificateFixture(),
+		SigData:   unittest.SignatureFixture(),
+	}
+
+	cs.hotstuff.On("SubmitTimeout", originID, timeout.View, timeout.HighestQC, timeout.HighestTC, timeout.SigData).Return()
+
+	// execute the timeout submission
+	err := cs.core.OnTimeout(originID, &timeout)
+	require.NoError(cs.T(), err, "timeout should pass")
+
+	// check the submit timeout was called with correct parameters
+	cs.hotstuff.AssertExpectations(cs.T())
+}
+
 func (cs *ComplianceCoreSuite) TestProposalBufferingOrder() {
 
 	// create a proposal that we will not submit until the end
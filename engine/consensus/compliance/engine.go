@@ -7,6 +7,7 @@ import (
 
 	"github.com/rs/zerolog"
 
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/common/fifoqueue"
 	"github.com/onflow/flow-go/model/events"
@@ -29,25 +30,29 @@ const defaultBlockQueueCapacity = 10000
 // defaultVoteQueueCapacity maximum capacity of block votes queue
 const defaultVoteQueueCapacity = 1000
 
+// defaultTimeoutQueueCapacity maximum capacity of timeouts queue
+const defaultTimeoutQueueCapacity = 1000
+
 // Engine is a wrapper struct for `Core` which implements consensus algorithm.
 // Engine is responsible for handling incoming messages, queueing for processing, broadcasting proposals.
 type Engine struct {
-	unit           *engine.Unit
-	lm             *lifecycle.LifecycleManager
-	log            zerolog.Logger
-	mempool        module.MempoolMetrics
-	metrics        module.EngineMetrics
-	me             module.Local
-	headers        storage.Headers
-	payloads       storage.Payloads
-	tracer         module.Tracer
-	state          protocol.State
-	prov           network.Engine
-	core           *Core
-	pendingBlocks  engine.MessageStore
-	pendingVotes   engine.MessageStore
-	messageHandler *engine.MessageHandler
-	con            network.Conduit
+	unit            *engine.Unit
+	lm              *lifecycle.LifecycleManager
+	log             zerolog.Logger
+	mempool         module.MempoolMetrics
+	metrics         module.EngineMetrics
+	me              module.Local
+	headers         storage.Headers
+	payloads        storage.Payloads
+	tracer          module.Tracer
+	state           protocol.State
+	prov            network.Engine
+	core            *Core
+	pendingBlocks   engine.MessageStore
+	pendingVotes    engine.MessageStore
+	pendingTimeouts engine.MessageStore
+	messageHandler  *engine.MessageHandler
+	con             network.Conduit
 }
 
 func NewEngine(
@@ -79,6 +84,16 @@ func NewEngine(
 	}
 	pendingVotes := &engine.FifoMessageStore{FifoQueue: votesQueue}
 
+	// FIFO queue for timeouts
+	timeoutsQueue, err := fifoqueue.NewFifoQueue(
+		fifoqueue.WithCapacity(defaultTimeoutQueueCapacity),
+		fifoqueue.WithLengthObserver(func(len int) { core.mempool.MempoolEntries(metrics.ResourceTimeoutQueue, uint(len)) }),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create queue for inbound timeouts: %w", err)
+	}
+	pendingTimeouts := &engine.FifoMessageStore{FifoQueue: timeoutsQueue}
+
 	// define message queueing behaviour
 	handler := engine.NewMessageHandler(
 		log.With().Str("compliance", "engine").Logger(),
@@ -124,24 +139,35 @@ func NewEngine(
 			},
 			Store: pendingVotes,
 		},
+		engine.Pattern{
+			Match: func(msg *engine.Message) bool {
+				_, ok := msg.Payload.(*messages.TimeoutObject)
+				if ok {
+					core.metrics.MessageReceived(metrics.EngineCompliance, metrics.MessageTimeoutObject)
+				}
+				return ok
+			},
+			Store: pendingTimeouts,
+		},
 	)
 
 	eng := &Engine{
-		unit:           engine.NewUnit(),
-		lm:             lifecycle.NewLifecycleManager(),
-		log:            log.With().Str("compliance", "engine").Logger(),
-		me:             me,
-		mempool:        core.mempool,
-		metrics:        core.metrics,
-		headers:        core.headers,
-		payloads:       core.payloads,
-		pendingBlocks:  pendingBlocks,
-		pendingVotes:   pendingVotes,
-		state:          core.state,
-		tracer:         core.tracer,
-		prov:           prov,
-		core:           core,
-		messageHandler: handler,
+		unit:            engine.NewUnit(),
+		lm:              lifecycle.NewLifecycleManager(),
+		log:             log.With().Str("compliance", "engine").Logger(),
+		me:              me,
+		mempool:         core.mempool,
+		metrics:         core.metrics,
+		headers:         core.headers,
+		payloads:        core.payloads,
+		pendingBlocks:   pendingBlocks,
+		pendingVotes:    pendingVotes,
+		pendingTimeouts: pendingTimeouts,
+		state:           core.state,
+		tracer:          core.tracer,
+		prov:            prov,
+		core:            core,
+		messageHandler:  handler,
 	}
 
 	// register the core with the network layer and store the conduit
@@ -149,6 +175,7 @@ func NewEngine(
 	if err != nil {
 		return nil, fmt.Errorf("could not register core: %w", err)
 	}
+	core.reportInvalid = eng.con.ReportInvalidInput
 
 	return eng, nil
 }
@@ -253,6 +280,15 @@ func (e *Engine) processAvailableMessages() error {
 			continue
 		}
 
+		msg, ok = e.pendingTimeouts.Get()
+		if ok {
+			err := e.core.OnTimeout(msg.OriginID, msg.Payload.(*messages.TimeoutObject))
+			if err != nil {
+				return fmt.Errorf("could not handle timeout: %w", err)
+			}
+			continue
+		}
+
 		// when there is no more messages in the queue, back to the loop to wait
 		// for the next incoming message to arrive.
 		return nil
@@ -292,6 +328,50 @@ func (e *Engine) SendVote(blockID flow.Identifier, view uint64, sigData []byte,
 	return nil
 }
 
+// BroadcastTimeout will propagate a timeout to all non-local consensus nodes.
+func (e *Engine) BroadcastTimeout(timeout *model.TimeoutObject) error {
+
+	log := e.log.With().
+		Uint64("timeout_view", timeout.View).
+		Uint64("highest_qc_view", timeout.HighestQC.View).
+		Logger()
+
+	log.Info().Msg("processing timeout broadcast request from hotstuff")
+
+	// retrieve all consensus nodes without our ID
+	recipients, err := e.state.Final().Identities(filter.And(
+		filter.HasRole(flow.RoleConsensus),
+		filter.Not(filter.HasNodeID(e.me.NodeID())),
+	))
+	if err != nil {
+		return fmt.Errorf("could not get consensus recipients: %w", err)
+	}
+
+	// build the timeout message
+	msg := &messages.TimeoutObject{
+		View:      timeout.View,
+		HighestQC: timeout.HighestQC,
+		HighestTC: timeout.HighestTC,
+		SigData:   timeout.SigData,
+	}
+
+	e.unit.Launch(func() {
+		// broadcast the timeout to consensus nodes
+		err := e.con.Publish(msg, recipients.NodeIDs()...)
+		if errors.Is(err, network.EmptyTargetList) {
+			return
+		}
+		if err != nil {
+			log.Warn().Err(err).Msg("could not send timeout")
+			return
+		}
+		e.metrics.MessageSent(metrics.EngineCompliance, metrics.MessageTimeoutObject)
+		log.Info().Msg("timeout broadcasted")
+	})
+
+	return nil
+}
+
 // BroadcastProposalWithDelay will propagate a block proposal to all non-local consensus nodes.
 // Note the header has incomplete fields, because it was converted from a hotstuff.
 func (e *Engine) BroadcastProposalWithDelay(header *flow.Header, delay time.Duration) error {
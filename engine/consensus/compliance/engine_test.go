@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/messages"
@@ -146,6 +147,46 @@ func (cs *ComplianceSuite) TestBroadcastProposalWithDelay() {
 	header.View--
 }
 
+// TestBroadcastTimeout tests that a timeout is broadcasted to all other
+// consensus nodes
+func (cs *ComplianceSuite) TestBroadcastTimeout() {
+
+	// add execution node to participants to make sure we exclude them from broadcast
+	cs.participants = append(cs.participants, unittest.IdentityFixture(unittest.WithRole(flow.RoleExecution)))
+
+	timeout := &model.TimeoutObject{
+		View:      rand.Uint64(),
+		HighestQC: unittest.QuorumCertificateFixture(),
+		SignerID:  cs.myID,
+		SigData:   unittest.SignatureFixture(),
+	}
+
+	// submit to broadcast timeout
+	err := cs.engine.BroadcastTimeout(timeout)
+	require.NoError(cs.T(), err, "timeout broadcast should pass")
+
+	done := func() <-chan struct{} {
+		channel := make(chan struct{})
+		close(channel)
+		return channel
+	}()
+
+	cs.hotstuff.On("Done", mock.Anything).Return(done)
+
+	// The timeout is transmitted asynchronously. We allow 10ms for the timeout to be received:
+	<-time.After(10 * time.Millisecond)
+	<-cs.engine.Done()
+
+	// the signer ID is conveyed over the network message
+	msg := &messages.TimeoutObject{
+		View:      timeout.View,
+		HighestQC: timeout.HighestQC,
+		HighestTC: timeout.HighestTC,
+		SigData:   timeout.SigData,
+	}
+	cs.con.AssertCalled(cs.T(), "Publish", msg, cs.participants[1].NodeID, cs.participants[2].NodeID)
+}
+
 // TestSubmittingMultipleVotes tests that we can send multiple votes and they
 // are queued and processed in expected way
 func (cs *ComplianceSuite) TestSubmittingMultipleEntries() {
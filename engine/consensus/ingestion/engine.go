@@ -145,6 +145,7 @@ func (e *Engine) process(originID flow.Identifier, event interface{}) error {
 		if err != nil {
 			if engine.IsInvalidInputError(err) {
 				e.log.Error().Str("origin", originID.String()).Err(err).Msg("received invalid collection guarantee")
+				e.con.ReportInvalidInput(originID)
 				return nil
 			}
 			if engine.IsOutdatedInputError(err) {
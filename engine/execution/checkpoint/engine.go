@@ -0,0 +1,880 @@
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+const (
+	// DefaultDepth is the default depth of the subtries the execution state is split
+	// into when syncing it, resulting in 2^DefaultDepth chunks.
+	DefaultDepth = 10
+
+	// MaxDepth is the maximum depth of the subtries served to other execution nodes.
+	MaxDepth = 20
+
+	// DefaultRequestTimeout is the default duration after which a chunk request
+	// is considered failed, and the chunk is requested from the next peer.
+	DefaultRequestTimeout = 2 * time.Minute
+
+	// syncWorkers is the number of chunks requested concurrently.
+	syncWorkers = 8
+
+	// attemptsPerPeer is the number of times each peer is asked for a chunk
+	// before syncing fails.
+	attemptsPerPeer = 3
+
+	// maxAdvertisedStates is the maximum number of execution states of sealed
+	// blocks advertised to an execution node looking for a state to sync.
+	maxAdvertisedStates = 3
+
+	// maxStateLookups is the maximum number of sealed blocks looked at when
+	// collecting the execution states to advertise.
+	maxStateLookups = 100
+
+	// stateRetryInterval is the interval at which the execution states are
+	// requested again, while none of the advertised states is sealed in the
+	// protocol state of the syncing node yet.
+	stateRetryInterval = 10 * time.Second
+
+	// stateLease is the duration an advertised execution state is kept pinned
+	// after it was advertised or a chunk of it was requested for the last time.
+	stateLease = 10 * time.Minute
+
+	// leaseCheckInterval is the interval at which expired leases are released.
+	leaseCheckInterval = time.Minute
+)
+
+// Tries provides the tries of the execution states served to other execution nodes.
+// Served tries are pinned, so that they are not evicted while other execution nodes
+// sync them.
+type Tries interface {
+	Trie(state ledger.State) (*trie.MTrie, error)
+	Pin(state ledger.State) error
+	Unpin(state ledger.State)
+}
+
+// pendingRequest is a request awaiting its response.
+type pendingRequest struct {
+	peerID    flow.Identifier
+	responses chan interface{}
+}
+
+// sealedResult is an execution result together with its seal.
+type sealedResult struct {
+	result *flow.ExecutionResult
+	seal   *flow.Seal
+}
+
+// Engine syncs the execution state from other execution nodes, so that a new
+// execution node can be bootstrapped from the state of a sealed block instead
+// of a hand-copied checkpoint. It serves the execution state to other execution
+// nodes as well. Serving nodes advertise the states of the most recent sealed
+// blocks they hold, and keep them pinned while they are synced. The state is
+// transferred in chunks, each chunk being a subtrie of the state's trie, which
+// is verified against the state commitment.
+type Engine struct {
+	unit           *engine.Unit
+	log            zerolog.Logger
+	con            network.Conduit
+	state          protocol.State
+	me             module.Local
+	depth          uint8
+	requestTimeout time.Duration
+	retryInterval  time.Duration
+
+	mu      sync.Mutex
+	tries   Tries                              // tries served to other execution nodes, nil until Serve is called
+	commits storage.Commits                    // state commitments of the blocks executed by this node
+	leases  map[flow.StateCommitment]time.Time // expiry of the pins on the advertised states
+	pending map[uint64]*pendingRequest         // pending requests by nonce
+}
+
+// New creates a new checkpoint sync engine, which requests the execution state
+// in 2^depth chunks.
+func New(
+	log zerolog.Logger,
+	net module.Network,
+	state protocol.State,
+	me module.Local,
+	depth uint,
+	requestTimeout time.Duration,
+) (*Engine, error) {
+
+	if depth > MaxDepth {
+		return nil, fmt.Errorf("checkpoint sync depth must not exceed %d but is %d", MaxDepth, depth)
+	}
+
+	e := &Engine{
+		unit:           engine.NewUnit(),
+		log:            log.With().Str("engine", "checkpoint").Logger(),
+		state:          state,
+		me:             me,
+		depth:          uint8(depth),
+		requestTimeout: requestTimeout,
+		retryInterval:  stateRetryInterval,
+		leases:         make(map[flow.StateCommitment]time.Time),
+		pending:        make(map[uint64]*pendingRequest),
+	}
+
+	con, err := net.Register(engine.SyncCheckpoint, e)
+	if err != nil {
+		return nil, fmt.Errorf("could not register checkpoint sync engine: %w", err)
+	}
+	e.con = con
+
+	return e, nil
+}
+
+// Ready returns a channel that will close when the engine has
+// successfully started.
+func (e *Engine) Ready() <-chan struct{} {
+	e.unit.LaunchPeriodically(func() {
+		e.releaseLeases(false)
+	}, leaseCheckInterval, leaseCheckInterval)
+	return e.unit.Ready()
+}
+
+// Done returns a channel that will close when the engine has
+// successfully stopped.
+func (e *Engine) Done() <-chan struct{} {
+	return e.unit.Done(func() {
+		e.releaseLeases(true)
+	})
+}
+
+// Serve starts serving the execution states of the given tries to other
+// execution nodes. The states of the blocks with the given commitments are
+// advertised once the blocks are sealed. Requests received before are dropped.
+func (e *Engine) Serve(tries Tries, commits storage.Commits) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tries = tries
+	e.commits = commits
+}
+
+// SubmitLocal submits an event originating on the local node.
+func (e *Engine) SubmitLocal(event interface{}) {
+	e.unit.Launch(func() {
+		err := e.ProcessLocal(event)
+		if err != nil {
+			engine.LogError(e.log, err)
+		}
+	})
+}
+
+// Submit submits the given event from the node with the given origin ID
+// for processing in a non-blocking manner.
+func (e *Engine) Submit(channel network.Channel, originID flow.Identifier, event interface{}) {
+	e.unit.Launch(func() {
+		err := e.Process(channel, originID, event)
+		if err != nil {
+			engine.LogError(e.log, err)
+		}
+	})
+}
+
+// ProcessLocal processes an event originating on the local node.
+func (e *Engine) ProcessLocal(event interface{}) error {
+	return e.unit.Do(func() error {
+		return e.process(e.me.NodeID(), event)
+	})
+}
+
+// Process processes the given event from the node with the given origin ID in
+// a blocking manner. It returns the potential processing error when done.
+func (e *Engine) Process(channel network.Channel, originID flow.Identifier, event interface{}) error {
+	return e.unit.Do(func() error {
+		return e.process(originID, event)
+	})
+}
+
+func (e *Engine) process(originID flow.Identifier, event interface{}) error {
+	switch v := event.(type) {
+	case *messages.CheckpointChunkRequest:
+		return e.onChunkRequest(originID, v)
+	case *messages.CheckpointChunkResponse:
+		e.onResponse(originID, v.Nonce, v)
+		return nil
+	case *messages.CheckpointStatesRequest:
+		return e.onStatesRequest(originID, v)
+	case *messages.CheckpointStatesResponse:
+		e.onResponse(originID, v.Nonce, v)
+		return nil
+	default:
+		return fmt.Errorf("invalid event type (%T)", event)
+	}
+}
+
+// onChunkRequest sends the requested subtrie of the requested execution state,
+// together with its proof, to the requesting execution node.
+func (e *Engine) onChunkRequest(originID flow.Identifier, req *messages.CheckpointChunkRequest) error {
+	lg := e.log.With().
+		Hex("origin_id", logging.ID(originID)).
+		Hex("state_commitment", req.StateCommitment[:]).
+		Uint8("depth", req.Depth).
+		Uint64("index", req.Index).
+		Logger()
+
+	err := e.ensureExecutionNode(originID)
+	if err != nil {
+		return fmt.Errorf("could not verify origin of checkpoint chunk request: %w", err)
+	}
+
+	if req.Depth > MaxDepth {
+		return engine.NewInvalidInputErrorf("checkpoint chunk depth must not exceed %d but is %d", MaxDepth, req.Depth)
+	}
+	if req.Index >= 1<<req.Depth {
+		return engine.NewInvalidInputErrorf("checkpoint chunk index %d out of range at depth %d", req.Index, req.Depth)
+	}
+
+	e.mu.Lock()
+	tries := e.tries
+	e.mu.Unlock()
+	if tries == nil {
+		lg.Debug().Msg("execution state not available yet, dropping checkpoint chunk request")
+		return nil
+	}
+
+	t, err := tries.Trie(ledger.State(req.StateCommitment))
+	if err != nil {
+		lg.Warn().Err(err).Msg("requested execution state not available, dropping checkpoint chunk request")
+		return nil
+	}
+	e.renewLease(req.StateCommitment)
+
+	subTrie, proof, err := t.SubTrie(trie.SubTriePath(int(req.Depth), req.Index), int(req.Depth))
+	if err != nil {
+		return fmt.Errorf("could not get subtrie: %w", err)
+	}
+
+	storableNodes, err := flattener.FlattenSubTrie(subTrie)
+	if err != nil {
+		return fmt.Errorf("could not flatten subtrie: %w", err)
+	}
+	nodes := make([][]byte, 0, len(storableNodes))
+	for _, storableNode := range storableNodes {
+		nodes = append(nodes, flattener.EncodeStorableNode(storableNode))
+	}
+
+	res := &messages.CheckpointChunkResponse{
+		StateCommitment: req.StateCommitment,
+		Depth:           req.Depth,
+		Index:           req.Index,
+		Nodes:           nodes,
+		Proof:           encoding.EncodeSubTrieProof(proof),
+		Nonce:           req.Nonce,
+	}
+	err = e.con.Unicast(res, originID)
+	if err != nil {
+		return fmt.Errorf("could not send checkpoint chunk response: %w", err)
+	}
+
+	lg.Debug().Int("nodes", len(nodes)).Msg("checkpoint chunk sent")
+	return nil
+}
+
+// onStatesRequest sends the execution states of the most recent sealed blocks this
+// node holds to the requesting execution node. The advertised states are leased,
+// such that they are not evicted while the requesting node syncs them.
+func (e *Engine) onStatesRequest(originID flow.Identifier, req *messages.CheckpointStatesRequest) error {
+	lg := e.log.With().
+		Hex("origin_id", logging.ID(originID)).
+		Logger()
+
+	err := e.ensureExecutionNode(originID)
+	if err != nil {
+		return fmt.Errorf("could not verify origin of checkpoint states request: %w", err)
+	}
+
+	e.mu.Lock()
+	tries := e.tries
+	commits := e.commits
+	e.mu.Unlock()
+	if tries == nil {
+		lg.Debug().Msg("execution state not available yet, dropping checkpoint states request")
+		return nil
+	}
+
+	states, err := e.servableStates(tries, commits)
+	if err != nil {
+		return fmt.Errorf("could not get servable execution states: %w", err)
+	}
+
+	res := &messages.CheckpointStatesResponse{
+		States: states,
+		Nonce:  req.Nonce,
+	}
+	err = e.con.Unicast(res, originID)
+	if err != nil {
+		return fmt.Errorf("could not send checkpoint states response: %w", err)
+	}
+
+	lg.Debug().Int("states", len(states)).Msg("checkpoint states sent")
+	return nil
+}
+
+// servableStates returns the execution states of the most recent sealed blocks,
+// which this node has executed and still holds, and leases them.
+func (e *Engine) servableStates(tries Tries, commits storage.Commits) ([]messages.CheckpointState, error) {
+	sealed, err := e.state.Sealed().Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get sealed block: %w", err)
+	}
+
+	states := make([]messages.CheckpointState, 0, maxAdvertisedStates)
+	for i := uint64(0); i < maxStateLookups && i <= sealed.Height && len(states) < maxAdvertisedStates; i++ {
+		header, err := e.state.AtHeight(sealed.Height - i).Head()
+		if errors.Is(err, storage.ErrNotFound) {
+			// there are no blocks below the root block
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not get sealed block at height %d: %w", sealed.Height-i, err)
+		}
+
+		blockID := header.ID()
+		commit, err := commits.ByBlockID(blockID)
+		if errors.Is(err, storage.ErrNotFound) {
+			// the block has not been executed by this node
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not get state commitment of block %x: %w", blockID, err)
+		}
+
+		err = e.lease(tries, commit)
+		if err != nil {
+			// the trie of the state has been evicted already
+			continue
+		}
+
+		states = append(states, messages.CheckpointState{
+			BlockID:         blockID,
+			StateCommitment: commit,
+		})
+	}
+
+	return states, nil
+}
+
+// lease pins the trie of the given state, until the state has neither been
+// advertised nor been requested for the lease duration.
+func (e *Engine) lease(tries Tries, commit flow.StateCommitment) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	_, leased := e.leases[commit]
+	if !leased {
+		err := tries.Pin(ledger.State(commit))
+		if err != nil {
+			return err
+		}
+	}
+	e.leases[commit] = time.Now().Add(stateLease)
+	return nil
+}
+
+// renewLease extends the lease of the given state, if it is leased.
+func (e *Engine) renewLease(commit flow.StateCommitment) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	_, leased := e.leases[commit]
+	if leased {
+		e.leases[commit] = time.Now().Add(stateLease)
+	}
+}
+
+// releaseLeases unpins the tries of the states whose lease has expired, or of
+// all leased states.
+func (e *Engine) releaseLeases(all bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := time.Now()
+	for commit, expiry := range e.leases {
+		if all || now.After(expiry) {
+			e.tries.Unpin(ledger.State(commit))
+			delete(e.leases, commit)
+		}
+	}
+}
+
+// onResponse hands the response over to the pending request it answers. Only
+// the first response to a request is handed over.
+func (e *Engine) onResponse(originID flow.Identifier, nonce uint64, res interface{}) {
+	e.mu.Lock()
+	req, ok := e.pending[nonce]
+	if ok && req.peerID == originID {
+		delete(e.pending, nonce)
+	}
+	e.mu.Unlock()
+
+	if !ok || req.peerID != originID {
+		e.log.Debug().
+			Hex("origin_id", logging.ID(originID)).
+			Uint64("nonce", nonce).
+			Msg("dropping unexpected checkpoint sync response")
+		return
+	}
+
+	select {
+	case req.responses <- res:
+	default:
+	}
+}
+
+// ensureExecutionNode checks that the node with the given ID is a staked execution node.
+func (e *Engine) ensureExecutionNode(nodeID flow.Identifier) error {
+	identity, err := e.state.Final().Identity(nodeID)
+	if protocol.IsIdentityNotFound(err) {
+		return engine.NewInvalidInputErrorf("unknown node %x", nodeID)
+	}
+	if err != nil {
+		return fmt.Errorf("could not get identity of node %x: %w", nodeID, err)
+	}
+	if identity.Role != flow.RoleExecution {
+		return engine.NewInvalidInputErrorf("invalid role %s of node %x", identity.Role, nodeID)
+	}
+	if identity.Stake == 0 || identity.Ejected {
+		return engine.NewInvalidInputErrorf("node %x is not staked", nodeID)
+	}
+	return nil
+}
+
+// peers returns the IDs of the other staked execution nodes.
+func (e *Engine) peers() (flow.IdentifierList, error) {
+	peers, err := e.state.Final().Identities(filter.And(
+		filter.HasRole(flow.RoleExecution),
+		filter.HasStake(true),
+		filter.Not(filter.Ejected),
+		filter.Not(filter.HasNodeID(e.me.NodeID())),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("could not get execution nodes: %w", err)
+	}
+	if len(peers) == 0 {
+		return nil, fmt.Errorf("no execution nodes to sync the execution state from")
+	}
+	return peers.NodeIDs(), nil
+}
+
+// SyncLatestSealed asks the other staked execution nodes for the execution states
+// of sealed blocks they serve, and fetches the state of the highest such block from
+// the execution nodes serving it. Advertised states are checked against the seals
+// of the protocol state, so that only sealed states are synced. As long as none of
+// the advertised states is sealed in the protocol state, which is the case while
+// the protocol state of a new node catches up with the chain, the states are
+// requested again periodically. It returns the header of the block, the sealed
+// execution result of the block and the trie of its execution state.
+func (e *Engine) SyncLatestSealed(ctx context.Context) (*flow.Header, *flow.ExecutionResult, *trie.MTrie, error) {
+	for {
+		peerIDs, err := e.peers()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		best, sr, bestPeers, err := e.latestSealed(ctx, peerIDs)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if best != nil {
+			e.log.Info().
+				Hex("block_id", logging.ID(best.ID())).
+				Uint64("height", best.Height).
+				Int("peers", len(bestPeers)).
+				Msg("syncing execution state of latest sealed block served by execution nodes")
+
+			mTrie, err := e.sync(ctx, sr.seal.FinalState, bestPeers)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			return best, sr.result, mTrie, nil
+		}
+
+		e.log.Info().
+			Int("peers", len(peerIDs)).
+			Dur("retry_interval", e.retryInterval).
+			Msg("none of the execution nodes serves the execution state of a block sealed in the protocol state yet, waiting for the protocol state to catch up")
+
+		select {
+		case <-time.After(e.retryInterval):
+		case <-ctx.Done():
+			return nil, nil, nil, ctx.Err()
+		}
+	}
+}
+
+// latestSealed requests the execution states the given peers serve, and returns
+// the highest block whose advertised state matches its seal in the protocol
+// state, along with its sealed result and the peers serving its state. It
+// returns a nil header if none of the advertised states is sealed.
+func (e *Engine) latestSealed(ctx context.Context, peerIDs flow.IdentifierList) (*flow.Header, *sealedResult, flow.IdentifierList, error) {
+	advertised, err := e.requestStates(ctx, peerIDs)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("could not request execution states: %w", err)
+	}
+
+	// the seals looked up by the ID of the sealed block, nil for blocks which
+	// are not sealed
+	sealed := make(map[flow.Identifier]*sealedResult)
+
+	var best *flow.Header
+	var bestResult *sealedResult
+	var bestPeers flow.IdentifierList
+	for peerID, states := range advertised {
+		for _, state := range states {
+			sr, ok := sealed[state.BlockID]
+			if !ok {
+				sr, err = e.sealedResult(state.BlockID)
+				if err != nil {
+					return nil, nil, nil, fmt.Errorf("could not look up seal of block %x: %w", state.BlockID, err)
+				}
+				sealed[state.BlockID] = sr
+			}
+			if sr == nil {
+				e.log.Debug().
+					Hex("peer_id", logging.ID(peerID)).
+					Hex("block_id", logging.ID(state.BlockID)).
+					Msg("ignoring execution state of block which is not sealed yet")
+				continue
+			}
+			if sr.seal.FinalState != state.StateCommitment {
+				e.log.Warn().
+					Hex("peer_id", logging.ID(peerID)).
+					Hex("block_id", logging.ID(state.BlockID)).
+					Hex("state_commitment", state.StateCommitment[:]).
+					Msg("ignoring execution state which does not match the seal")
+				continue
+			}
+
+			header, err := e.state.AtBlockID(state.BlockID).Head()
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("could not get sealed block %x: %w", state.BlockID, err)
+			}
+			if best == nil || header.Height > best.Height {
+				best = header
+				bestResult = sr
+				bestPeers = nil
+			}
+			if header.ID() == best.ID() {
+				bestPeers = append(bestPeers, peerID)
+			}
+		}
+	}
+	return best, bestResult, bestPeers, nil
+}
+
+// requestStates asks the given peers for the execution states they serve, and
+// collects the responses received within the request timeout.
+func (e *Engine) requestStates(ctx context.Context, peerIDs flow.IdentifierList) (map[flow.Identifier][]messages.CheckpointState, error) {
+	responses := make(chan interface{}, len(peerIDs))
+	peerByNonce := make(map[uint64]flow.Identifier, len(peerIDs))
+	defer func() {
+		e.mu.Lock()
+		for nonce := range peerByNonce {
+			delete(e.pending, nonce)
+		}
+		e.mu.Unlock()
+	}()
+
+	for _, peerID := range peerIDs {
+		req := &messages.CheckpointStatesRequest{
+			Nonce: rand.Uint64(),
+		}
+		e.mu.Lock()
+		e.pending[req.Nonce] = &pendingRequest{
+			peerID:    peerID,
+			responses: responses,
+		}
+		e.mu.Unlock()
+		peerByNonce[req.Nonce] = peerID
+
+		err := e.con.Unicast(req, peerID)
+		if err != nil {
+			e.log.Warn().Err(err).Hex("peer_id", logging.ID(peerID)).Msg("could not send checkpoint states request")
+		}
+	}
+
+	timer := time.NewTimer(e.requestTimeout)
+	defer timer.Stop()
+
+	advertised := make(map[flow.Identifier][]messages.CheckpointState)
+	for received := 0; received < len(peerIDs); received++ {
+		select {
+		case msg := <-responses:
+			res, ok := msg.(*messages.CheckpointStatesResponse)
+			if !ok {
+				continue
+			}
+			advertised[peerByNonce[res.Nonce]] = res.States
+		case <-timer.C:
+			return advertised, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return advertised, nil
+}
+
+// sealedResult returns the seal of the given block and the sealed result, if the
+// block is finalized and sealed in the protocol state. It returns nil otherwise,
+// and if the block was sealed together with a descendant, in which case its
+// result is not known.
+func (e *Engine) sealedResult(blockID flow.Identifier) (*sealedResult, error) {
+	header, err := e.state.AtBlockID(blockID).Head()
+	if errors.Is(err, storage.ErrNotFound) {
+		// the protocol state has not caught up with the block yet
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get block: %w", err)
+	}
+	final, err := e.state.Final().Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get finalized block: %w", err)
+	}
+	if header.Height > final.Height {
+		return nil, nil
+	}
+	finalized, err := e.state.AtHeight(header.Height).Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get finalized block at height %d: %w", header.Height, err)
+	}
+	if finalized.ID() != blockID {
+		return nil, nil
+	}
+
+	// sealedAt returns the latest sealed result as of the finalized block at
+	// the given height, and the height of the block it seals
+	sealedAt := func(height uint64) (*sealedResult, uint64, error) {
+		result, seal, err := e.state.AtHeight(height).SealedResult()
+		if err != nil {
+			return nil, 0, fmt.Errorf("could not get sealed result at height %d: %w", height, err)
+		}
+		sealed, err := e.state.AtBlockID(seal.BlockID).Head()
+		if err != nil {
+			return nil, 0, fmt.Errorf("could not get sealed block %x: %w", seal.BlockID, err)
+		}
+		return &sealedResult{result: result, seal: seal}, sealed.Height, nil
+	}
+
+	_, sealedHeight, err := sealedAt(final.Height)
+	if err != nil {
+		return nil, err
+	}
+	if sealedHeight < header.Height {
+		return nil, nil
+	}
+
+	// the seal for the block is included in the lowest finalized block, as of
+	// which the block is sealed
+	low, high := header.Height, final.Height
+	for low < high {
+		mid := low + (high-low)/2
+		_, sealedHeight, err := sealedAt(mid)
+		if err != nil {
+			return nil, err
+		}
+		if sealedHeight >= header.Height {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+
+	sr, _, err := sealedAt(low)
+	if err != nil {
+		return nil, err
+	}
+	if sr.seal.BlockID != blockID {
+		return nil, nil
+	}
+	return sr, nil
+}
+
+// Sync fetches the trie of the execution state with the given commitment from
+// the other staked execution nodes, in verified chunks. It blocks until the
+// whole trie is fetched, or returns an error once every peer failed to provide
+// a chunk a number of times.
+func (e *Engine) Sync(ctx context.Context, commit flow.StateCommitment) (*trie.MTrie, error) {
+	peerIDs, err := e.peers()
+	if err != nil {
+		return nil, err
+	}
+	return e.sync(ctx, commit, peerIDs)
+}
+
+// sync fetches the trie of the execution state with the given commitment from
+// the given peers.
+func (e *Engine) sync(ctx context.Context, commit flow.StateCommitment, peerIDs flow.IdentifierList) (*trie.MTrie, error) {
+	depth := int(e.depth)
+	chunks := uint64(1) << e.depth
+
+	log := e.log.With().
+		Hex("state_commitment", commit[:]).
+		Int("depth", depth).
+		Int("peers", len(peerIDs)).
+		Logger()
+	log.Info().Uint64("chunks", chunks).Msg("syncing execution state from execution nodes")
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	subTries := make([]*node.Node, chunks)
+	indices := make(chan uint64)
+	errs := make(chan error, syncWorkers)
+	var fetched uint64
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for w := 0; w < syncWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indices {
+				subTrie, err := e.fetchChunk(ctx, commit, index, peerIDs)
+				if err != nil {
+					errs <- err
+					cancel()
+					return
+				}
+
+				mu.Lock()
+				subTries[index] = subTrie
+				fetched++
+				if fetched%(chunks/10+1) == 0 {
+					log.Info().Uint64("fetched", fetched).Uint64("chunks", chunks).Msg("execution state sync progress")
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// start with a random chunk, so that concurrently syncing nodes spread their load
+	offset := rand.Uint64() % chunks
+Loop:
+	for i := uint64(0); i < chunks; i++ {
+		select {
+		case indices <- (offset + i) % chunks:
+		case <-ctx.Done():
+			break Loop
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return nil, err
+	default:
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	mTrie, err := trie.NewMTrieFromSubTries(depth, subTries)
+	if err != nil {
+		return nil, fmt.Errorf("could not assemble trie: %w", err)
+	}
+	if flow.StateCommitment(mTrie.RootHash()) != commit {
+		return nil, fmt.Errorf("root hash of synced trie (%x) does not match state commitment (%x)", mTrie.RootHash(), commit)
+	}
+
+	log.Info().Uint64("registers", mTrie.AllocatedRegCount()).Msg("execution state synced")
+	return mTrie, nil
+}
+
+// fetchChunk requests the chunk with the given index from the peers in turn,
+// until a peer provides a valid chunk.
+func (e *Engine) fetchChunk(ctx context.Context, commit flow.StateCommitment, index uint64, peerIDs flow.IdentifierList) (*node.Node, error) {
+	var lastErr error
+	for attempt := 0; attempt < attemptsPerPeer*len(peerIDs); attempt++ {
+		peerID := peerIDs[(int(index)+attempt)%len(peerIDs)]
+		subTrie, err := e.requestChunk(ctx, peerID, commit, index)
+		if err == nil {
+			return subTrie, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		e.log.Warn().
+			Err(err).
+			Hex("peer_id", logging.ID(peerID)).
+			Uint64("index", index).
+			Msg("could not fetch checkpoint chunk, retrying")
+		lastErr = err
+	}
+	return nil, fmt.Errorf("could not fetch checkpoint chunk %d: %w", index, lastErr)
+}
+
+// errRequestTimedOut is returned when a peer does not answer a chunk request in time.
+var errRequestTimedOut = errors.New("checkpoint chunk request timed out")
+
+// requestChunk requests the chunk with the given index from the given peer,
+// and returns the verified subtrie of the chunk.
+func (e *Engine) requestChunk(ctx context.Context, peerID flow.Identifier, commit flow.StateCommitment, index uint64) (*node.Node, error) {
+	req := &messages.CheckpointChunkRequest{
+		StateCommitment: commit,
+		Depth:           e.depth,
+		Index:           index,
+		Nonce:           rand.Uint64(),
+	}
+
+	pending := &pendingRequest{
+		peerID:    peerID,
+		responses: make(chan interface{}, 1),
+	}
+	e.mu.Lock()
+	e.pending[req.Nonce] = pending
+	e.mu.Unlock()
+	defer func() {
+		e.mu.Lock()
+		delete(e.pending, req.Nonce)
+		e.mu.Unlock()
+	}()
+
+	err := e.con.Unicast(req, peerID)
+	if err != nil {
+		return nil, fmt.Errorf("could not send checkpoint chunk request: %w", err)
+	}
+
+	timer := time.NewTimer(e.requestTimeout)
+	defer timer.Stop()
+
+	select {
+	case msg := <-pending.responses:
+		res, ok := msg.(*messages.CheckpointChunkResponse)
+		if !ok {
+			return nil, fmt.Errorf("invalid response type (%T) to checkpoint chunk request", msg)
+		}
+		return VerifyChunk(res, commit, e.depth, index)
+	case <-timer.C:
+		return nil, errRequestTimedOut
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,454 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	module "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	protocol "github.com/onflow/flow-go/state/protocol/mock"
+	storageerr "github.com/onflow/flow-go/storage"
+	storage "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// tries serves a fixed set of tries by their root hash
+type tries map[ledger.State]*trie.MTrie
+
+func (t tries) Trie(state ledger.State) (*trie.MTrie, error) {
+	mTrie, ok := t[state]
+	if !ok {
+		return nil, fmt.Errorf("unknown state %x", state)
+	}
+	return mTrie, nil
+}
+
+func (t tries) Pin(state ledger.State) error {
+	_, err := t.Trie(state)
+	return err
+}
+
+func (t tries) Unpin(ledger.State) {}
+
+// trieFixture returns a trie with the given number of random registers,
+// whose paths are derived from their keys.
+func trieFixture(t *testing.T, registers int) *trie.MTrie {
+	payloads := utils.RandomPayloads(registers, 2, 32)
+	paths := make([]ledger.Path, 0, registers)
+	values := make([]ledger.Payload, 0, registers)
+	for _, payload := range payloads {
+		path, err := pathfinder.KeyToPath(payload.Key, complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+		paths = append(paths, path)
+		values = append(values, *payload)
+	}
+	mTrie, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, values)
+	require.NoError(t, err)
+	return mTrie
+}
+
+type testNode struct {
+	identity *flow.Identity
+	engine   *Engine
+	conduit  *mocknetwork.Conduit
+}
+
+// newTestNodes creates engines for the given identities, whose conduits deliver
+// messages between each other.
+func newTestNodes(t *testing.T, depth uint8, identities flow.IdentityList) map[flow.Identifier]*testNode {
+	snapshot := new(protocol.Snapshot)
+	snapshot.On("Identities", mock.Anything).Return(
+		func(selector flow.IdentityFilter) flow.IdentityList {
+			return identities.Filter(selector)
+		},
+		nil,
+	)
+	for _, identity := range identities {
+		snapshot.On("Identity", identity.NodeID).Return(identity, nil)
+	}
+	state := new(protocol.State)
+	state.On("Final").Return(snapshot)
+
+	nodes := make(map[flow.Identifier]*testNode)
+	for _, identity := range identities {
+		me := new(module.Local)
+		me.On("NodeID").Return(identity.NodeID)
+		nodes[identity.NodeID] = &testNode{
+			identity: identity,
+			conduit:  new(mocknetwork.Conduit),
+			engine: &Engine{
+				unit:           engine.NewUnit(),
+				log:            unittest.Logger(),
+				state:          state,
+				me:             me,
+				depth:          depth,
+				requestTimeout: time.Second,
+				retryInterval:  10 * time.Millisecond,
+				pending:        make(map[uint64]*pendingRequest),
+				leases:         make(map[flow.StateCommitment]time.Time),
+			},
+		}
+	}
+
+	for _, n := range nodes {
+		n.engine.con = n.conduit
+		originID := n.identity.NodeID
+		n.conduit.On("Unicast", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			target := nodes[args.Get(1).(flow.Identifier)]
+			go func() {
+				_ = target.engine.Process(engine.SyncCheckpoint, originID, args.Get(0))
+			}()
+		}).Return(nil)
+	}
+	return nodes
+}
+
+func TestSync(t *testing.T) {
+	identities := unittest.IdentityListFixture(3, unittest.WithRole(flow.RoleExecution))
+	served := trieFixture(t, 500)
+
+	for _, depth := range []uint8{0, 1, 4} {
+		t.Run(fmt.Sprintf("depth %d", depth), func(t *testing.T) {
+			nodes := newTestNodes(t, depth, identities)
+			nodes[identities[1].NodeID].engine.Serve(tries{ledger.State(served.RootHash()): served}, nil)
+			nodes[identities[2].NodeID].engine.Serve(tries{ledger.State(served.RootHash()): served}, nil)
+
+			syncer := nodes[identities[0].NodeID].engine
+			synced, err := syncer.Sync(context.Background(), flow.StateCommitment(served.RootHash()))
+			require.NoError(t, err)
+			require.Equal(t, served.RootHash(), synced.RootHash())
+			require.Equal(t, served.AllocatedRegCount(), synced.AllocatedRegCount())
+			require.ElementsMatch(t, served.AllPayloads(), synced.AllPayloads())
+			require.Empty(t, syncer.pending)
+		})
+	}
+}
+
+// TestSync_FailingPeer checks that chunks are fetched from the next peer, if a
+// peer does not serve the requested state.
+func TestSync_FailingPeer(t *testing.T) {
+	identities := unittest.IdentityListFixture(3, unittest.WithRole(flow.RoleExecution))
+	served := trieFixture(t, 100)
+
+	nodes := newTestNodes(t, 2, identities)
+	nodes[identities[1].NodeID].engine.Serve(tries{}, nil)
+	nodes[identities[2].NodeID].engine.Serve(tries{ledger.State(served.RootHash()): served}, nil)
+	for _, n := range nodes {
+		n.engine.requestTimeout = 100 * time.Millisecond
+	}
+
+	synced, err := nodes[identities[0].NodeID].engine.Sync(context.Background(), flow.StateCommitment(served.RootHash()))
+	require.NoError(t, err)
+	require.Equal(t, served.RootHash(), synced.RootHash())
+}
+
+// TestSync_NoPeerServing checks that syncing fails if no peer serves the state.
+func TestSync_NoPeerServing(t *testing.T) {
+	identities := unittest.IdentityListFixture(2, unittest.WithRole(flow.RoleExecution))
+	served := trieFixture(t, 10)
+
+	nodes := newTestNodes(t, 1, identities)
+	nodes[identities[0].NodeID].engine.requestTimeout = 10 * time.Millisecond
+
+	_, err := nodes[identities[0].NodeID].engine.Sync(context.Background(), flow.StateCommitment(served.RootHash()))
+	require.Error(t, err)
+}
+
+// TestSyncLatestSealed checks that the state of the highest sealed block, which
+// peers advertise, is synced, and that states not matching a seal are ignored.
+// chainFixture mocks a chain of six finalized blocks in the given protocol state,
+// where each block seals its grandparent, and whose latest sealed block is the
+// fourth one. It returns the headers, tries and sealed results of the blocks.
+func chainFixture(t *testing.T, state *protocol.State, final func() *flow.Header) ([]*flow.Header, []*trie.MTrie, []*flow.ExecutionResult) {
+	headers := make([]*flow.Header, 0, 6)
+	root := unittest.BlockHeaderFixture()
+	root.Height = 0
+	headers = append(headers, &root)
+	for len(headers) < 6 {
+		header := unittest.BlockHeaderWithParentFixture(headers[len(headers)-1])
+		headers = append(headers, &header)
+	}
+
+	mTries := make([]*trie.MTrie, 0, len(headers))
+	results := make([]*flow.ExecutionResult, 0, len(headers))
+	seals := make([]*flow.Seal, 0, len(headers))
+	for _, header := range headers {
+		mTrie := trieFixture(t, 20)
+		mTries = append(mTries, mTrie)
+		results = append(results, unittest.ExecutionResultFixture(unittest.WithExecutionResultBlockID(header.ID())))
+		seals = append(seals, &flow.Seal{
+			BlockID:    header.ID(),
+			FinalState: flow.StateCommitment(mTrie.RootHash()),
+		})
+	}
+	for i, header := range headers {
+		sealed := i - 2
+		if sealed < 0 {
+			sealed = 0
+		}
+		snapshot := new(protocol.Snapshot)
+		snapshot.On("Head").Return(header, nil)
+		snapshot.On("SealedResult").Return(results[sealed], seals[sealed], nil)
+		state.On("AtHeight", header.Height).Return(snapshot)
+		state.On("AtBlockID", header.ID()).Return(snapshot)
+	}
+	state.Final().(*protocol.Snapshot).On("Head").Return(
+		func() *flow.Header {
+			return final()
+		},
+		nil,
+	)
+	sealedSnapshot := new(protocol.Snapshot)
+	sealedSnapshot.On("Head").Return(headers[3], nil)
+	state.On("Sealed").Return(sealedSnapshot)
+
+	return headers, mTries, results
+}
+
+// commitsFixture returns the state commitments of the given blocks, the other
+// blocks of the chain are not executed.
+func commitsFixture(headers []*flow.Header, states map[*flow.Header]flow.StateCommitment) *storage.Commits {
+	commits := new(storage.Commits)
+	for _, header := range headers {
+		commit, ok := states[header]
+		if ok {
+			commits.On("ByBlockID", header.ID()).Return(commit, nil)
+		} else {
+			commits.On("ByBlockID", header.ID()).Return(nil, storageerr.ErrNotFound)
+		}
+	}
+	return commits
+}
+
+func TestSyncLatestSealed(t *testing.T) {
+	identities := unittest.IdentityListFixture(3, unittest.WithRole(flow.RoleExecution))
+	nodes := newTestNodes(t, 2, identities)
+
+	state := nodes[identities[0].NodeID].engine.state.(*protocol.State)
+	var headers []*flow.Header
+	headers, mTries, results := chainFixture(t, state, func() *flow.Header {
+		return headers[5]
+	})
+	commitOf := func(i int) flow.StateCommitment {
+		return flow.StateCommitment(mTries[i].RootHash())
+	}
+
+	// the first peer has executed the sealed blocks, but evicted the state of
+	// the latest one
+	first := nodes[identities[1].NodeID].engine
+	first.Serve(tries{
+		ledger.State(commitOf(1)): mTries[1],
+		ledger.State(commitOf(2)): mTries[2],
+	}, commitsFixture(headers, map[*flow.Header]flow.StateCommitment{
+		headers[0]: commitOf(0),
+		headers[1]: commitOf(1),
+		headers[2]: commitOf(2),
+		headers[3]: commitOf(3),
+	}))
+
+	// the second peer advertises a state for the latest sealed block which does
+	// not match its seal
+	bogus := trieFixture(t, 20)
+	second := nodes[identities[2].NodeID].engine
+	second.Serve(tries{
+		ledger.State(bogus.RootHash()): bogus,
+		ledger.State(commitOf(2)):      mTries[2],
+	}, commitsFixture(headers, map[*flow.Header]flow.StateCommitment{
+		headers[2]: commitOf(2),
+		headers[3]: flow.StateCommitment(bogus.RootHash()),
+	}))
+
+	syncer := nodes[identities[0].NodeID].engine
+	header, result, synced, err := syncer.SyncLatestSealed(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, headers[2].ID(), header.ID())
+	require.Equal(t, results[2], result)
+	require.Equal(t, mTries[2].RootHash(), synced.RootHash())
+	require.ElementsMatch(t, mTries[2].AllPayloads(), synced.AllPayloads())
+	require.Empty(t, syncer.pending)
+
+	// the advertised states are leased until released
+	require.Len(t, first.leases, 2)
+	require.Contains(t, first.leases, commitOf(2))
+	require.Len(t, second.leases, 2)
+	first.releaseLeases(true)
+	require.Empty(t, first.leases)
+}
+
+// TestSyncLatestSealed_CatchUp checks that the advertised states are requested
+// again until the protocol state of the syncing node has caught up with the
+// seals of the advertised blocks.
+func TestSyncLatestSealed_CatchUp(t *testing.T) {
+	identities := unittest.IdentityListFixture(2, unittest.WithRole(flow.RoleExecution))
+	nodes := newTestNodes(t, 2, identities)
+
+	// the syncing node has finalized the fourth block, which seals the second block
+	state := nodes[identities[0].NodeID].engine.state.(*protocol.State)
+	var headers []*flow.Header
+	var final atomic.Value
+	headers, mTries, results := chainFixture(t, state, func() *flow.Header {
+		return final.Load().(*flow.Header)
+	})
+	final.Store(headers[3])
+	commitOf := func(i int) flow.StateCommitment {
+		return flow.StateCommitment(mTries[i].RootHash())
+	}
+
+	// the peer only serves the state of the third block
+	peer := nodes[identities[1].NodeID].engine
+	peer.Serve(tries{
+		ledger.State(commitOf(2)): mTries[2],
+	}, commitsFixture(headers, map[*flow.Header]flow.StateCommitment{
+		headers[2]: commitOf(2),
+	}))
+
+	syncer := nodes[identities[0].NodeID].engine
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _, _, err := syncer.SyncLatestSealed(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// once the syncing node has finalized the seal of the third block, its state is synced
+	time.AfterFunc(50*time.Millisecond, func() {
+		final.Store(headers[4])
+	})
+	header, result, synced, err := syncer.SyncLatestSealed(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, headers[2].ID(), header.ID())
+	require.Equal(t, results[2], result)
+	require.Equal(t, mTries[2].RootHash(), synced.RootHash())
+}
+
+// TestLeases checks that leases on advertised states are renewed when the state
+// is requested, and released once they expire.
+func TestLeases(t *testing.T) {
+	served := trieFixture(t, 10)
+	commit := flow.StateCommitment(served.RootHash())
+	servedTries := tries{ledger.State(commit): served}
+
+	identities := unittest.IdentityListFixture(1, unittest.WithRole(flow.RoleExecution))
+	e := newTestNodes(t, 1, identities)[identities[0].NodeID].engine
+	e.Serve(servedTries, nil)
+
+	// states which are not held can not be leased
+	err := e.lease(servedTries, unittest.StateCommitmentFixture())
+	require.Error(t, err)
+	require.Empty(t, e.leases)
+
+	err = e.lease(servedTries, commit)
+	require.NoError(t, err)
+	require.Contains(t, e.leases, commit)
+
+	// leases which have not expired are kept
+	e.releaseLeases(false)
+	require.Contains(t, e.leases, commit)
+
+	// requesting the state renews an expired lease
+	e.leases[commit] = time.Now().Add(-time.Second)
+	e.renewLease(commit)
+	e.releaseLeases(false)
+	require.Contains(t, e.leases, commit)
+
+	e.leases[commit] = time.Now().Add(-time.Second)
+	e.releaseLeases(false)
+	require.Empty(t, e.leases)
+}
+
+// TestChunkRequest_NonExecutionNode checks that the state is only served to execution nodes.
+func TestChunkRequest_NonExecutionNode(t *testing.T) {
+	identities := flow.IdentityList{
+		unittest.IdentityFixture(unittest.WithRole(flow.RoleVerification)),
+		unittest.IdentityFixture(unittest.WithRole(flow.RoleExecution)),
+	}
+	served := trieFixture(t, 10)
+
+	nodes := newTestNodes(t, 1, identities)
+	server := nodes[identities[1].NodeID]
+	server.engine.Serve(tries{ledger.State(served.RootHash()): served}, nil)
+
+	err := server.engine.Process(engine.SyncCheckpoint, identities[0].NodeID, &messages.CheckpointChunkRequest{
+		StateCommitment: flow.StateCommitment(served.RootHash()),
+		Depth:           1,
+		Index:           0,
+		Nonce:           1,
+	})
+	require.True(t, engine.IsInvalidInputError(err))
+	server.conduit.AssertNotCalled(t, "Unicast", mock.Anything, mock.Anything)
+}
+
+// TestVerifyChunk checks that chunks not matching the state commitment are rejected.
+func TestVerifyChunk(t *testing.T) {
+	served := trieFixture(t, 100)
+	other := trieFixture(t, 100)
+	commit := flow.StateCommitment(served.RootHash())
+
+	identities := unittest.IdentityListFixture(2, unittest.WithRole(flow.RoleExecution))
+	nodes := newTestNodes(t, 2, identities)
+	server := nodes[identities[1].NodeID]
+	server.engine.Serve(tries{
+		ledger.State(served.RootHash()): served,
+		ledger.State(other.RootHash()):  other,
+	}, nil)
+
+	// capture the responses sent by the server instead of delivering them
+	responses := make(chan *messages.CheckpointChunkResponse, 1)
+	server.conduit = new(mocknetwork.Conduit)
+	server.conduit.On("Unicast", mock.Anything, identities[0].NodeID).Run(func(args mock.Arguments) {
+		responses <- args.Get(0).(*messages.CheckpointChunkResponse)
+	}).Return(nil)
+	server.engine.con = server.conduit
+
+	request := func(commit flow.StateCommitment, index uint64) *messages.CheckpointChunkResponse {
+		err := server.engine.Process(engine.SyncCheckpoint, identities[0].NodeID, &messages.CheckpointChunkRequest{
+			StateCommitment: commit,
+			Depth:           2,
+			Index:           index,
+		})
+		require.NoError(t, err)
+		return <-responses
+	}
+
+	res := request(commit, 1)
+	_, err := VerifyChunk(res, commit, 2, 1)
+	require.NoError(t, err)
+
+	t.Run("other index", func(t *testing.T) {
+		_, err := VerifyChunk(res, commit, 2, 2)
+		require.True(t, engine.IsInvalidInputError(err))
+	})
+
+	t.Run("forged proof", func(t *testing.T) {
+		forged := *res
+		forged.Proof = request(commit, 2).Proof
+		_, err := VerifyChunk(&forged, commit, 2, 1)
+		require.True(t, engine.IsInvalidInputError(err))
+	})
+
+	t.Run("forged nodes", func(t *testing.T) {
+		forged := *res
+		forged.Nodes = request(flow.StateCommitment(other.RootHash()), 1).Nodes
+		_, err := VerifyChunk(&forged, commit, 2, 1)
+		require.True(t, engine.IsInvalidInputError(err))
+	})
+
+	t.Run("missing nodes", func(t *testing.T) {
+		forged := *res
+		forged.Nodes = nil
+		_, err := VerifyChunk(&forged, commit, 2, 1)
+		require.True(t, engine.IsInvalidInputError(err))
+	})
+}
@@ -0,0 +1,99 @@
+package checkpoint
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/common/hash"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/common/proof"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+)
+
+// VerifyChunk rebuilds the subtrie contained in the given checkpoint chunk
+// response, and verifies it is the subtrie with the given index at the given
+// depth of the trie with the given state commitment. It returns the root of the
+// subtrie, which is nil for an empty subtrie.
+func VerifyChunk(res *messages.CheckpointChunkResponse, commit flow.StateCommitment, depth uint8, index uint64) (*node.Node, error) {
+	if res.StateCommitment != commit || res.Depth != depth || res.Index != index {
+		return nil, engine.NewInvalidInputErrorf("checkpoint chunk (%x, %d, %d) does not match request (%x, %d, %d)",
+			res.StateCommitment, res.Depth, res.Index, commit, depth, index)
+	}
+
+	subTrieProof, err := encoding.DecodeSubTrieProof(res.Proof)
+	if err != nil {
+		return nil, engine.NewInvalidInputErrorf("could not decode subtrie proof: %w", err)
+	}
+	if int(subTrieProof.Steps) != int(depth) || subTrieProof.Path != trie.SubTriePath(int(depth), index) {
+		return nil, engine.NewInvalidInputErrorf("subtrie proof is not for checkpoint chunk %d at depth %d", index, depth)
+	}
+
+	storableNodes := make([]*flattener.StorableNode, 0, len(res.Nodes))
+	for _, data := range res.Nodes {
+		storableNode, err := flattener.ReadStorableNode(bytes.NewReader(data))
+		if err != nil {
+			return nil, engine.NewInvalidInputErrorf("could not decode subtrie node: %w", err)
+		}
+		storableNodes = append(storableNodes, storableNode)
+	}
+
+	height := ledger.NodeMaxHeight - int(depth)
+	subTrie, err := flattener.RebuildSubTrie(storableNodes, height)
+	if err != nil {
+		return nil, engine.NewInvalidInputErrorf("could not rebuild subtrie: %w", err)
+	}
+
+	subTrieHash := ledger.GetDefaultHashForHeight(height)
+	if subTrie != nil {
+		subTrieHash = subTrie.Hash()
+	}
+	if !proof.VerifySubTrieProof(subTrieProof, subTrieHash, ledger.State(commit)) {
+		return nil, engine.NewInvalidInputErrorf("subtrie %d at depth %d does not match state commitment %x", index, depth, commit)
+	}
+
+	// the hashes of the leaves only commit to the paths and values of the
+	// registers, so the keys must be checked against the paths
+	err = verifyKeys(subTrie)
+	if err != nil {
+		return nil, engine.NewInvalidInputErrorf("invalid subtrie: %w", err)
+	}
+
+	return subTrie, nil
+}
+
+// verifyKeys checks that the paths of all leaves of the given subtrie are the
+// paths of the keys of their payloads.
+func verifyKeys(n *node.Node) error {
+	if n == nil {
+		return nil
+	}
+	if !n.IsLeaf() {
+		err := verifyKeys(n.LeftChild())
+		if err != nil {
+			return err
+		}
+		return verifyKeys(n.RightChild())
+	}
+
+	payload := n.Payload()
+	if len(payload.Key.KeyParts) == 0 {
+		// empty payloads of unallocated registers have no key
+		return nil
+	}
+	path, err := pathfinder.KeyToPath(payload.Key, complete.DefaultPathFinderVersion)
+	if err != nil {
+		return fmt.Errorf("could not compute path of key %s: %w", payload.Key.String(), err)
+	}
+	if path != *n.Path() {
+		return fmt.Errorf("path %x of leaf does not match key %s", hash.Hash(*n.Path()), payload.Key.String())
+	}
+	return nil
+}
@@ -52,6 +52,19 @@ type blockComputer struct {
 	log            zerolog.Logger
 	systemChunkCtx fvm.Context
 	committer      ViewCommitter
+	workers        uint
+}
+
+// BlockComputerOption configures optional behaviour of the block computer.
+type BlockComputerOption func(*blockComputer)
+
+// WithParallelExecution enables the optimistic parallel execution of the
+// transactions of each collection with the given number of workers. With less
+// than two workers, transactions are executed sequentially.
+func WithParallelExecution(workers uint) BlockComputerOption {
+	return func(e *blockComputer) {
+		e.workers = workers
+	}
 }
 
 func SystemChunkContext(vmCtx fvm.Context, logger zerolog.Logger) fvm.Context {
@@ -73,8 +86,9 @@ func NewBlockComputer(
 	tracer module.Tracer,
 	logger zerolog.Logger,
 	committer ViewCommitter,
+	options ...BlockComputerOption,
 ) (BlockComputer, error) {
-	return &blockComputer{
+	e := &blockComputer{
 		vm:             vm,
 		vmCtx:          vmCtx,
 		metrics:        metrics,
@@ -82,7 +96,13 @@ func NewBlockComputer(
 		log:            logger,
 		systemChunkCtx: SystemChunkContext(vmCtx, logger),
 		committer:      committer,
-	}, nil
+	}
+
+	for _, option := range options {
+		option(e)
+	}
+
+	return e, nil
 }
 
 // ExecuteBlock executes a block and returns the resulting chunks.
@@ -302,12 +322,20 @@ func (e *blockComputer) executeCollection(
 	}()
 
 	txCtx := fvm.NewContextFromParent(blockCtx, fvm.WithMetricsReporter(e.metrics), fvm.WithTracer(e.tracer))
-	for _, txBody := range collection.Transactions {
-		err := e.executeTransaction(txBody, colSpan, collectionView, programs, txCtx, collectionIndex, txIndex, res)
-		txIndex++
+	if e.workers > 1 && len(collection.Transactions) > 1 {
+		var err error
+		txIndex, err = e.executeTransactionsOptimistically(collection.Transactions, colSpan, collectionView, programs, txCtx, collectionIndex, txIndex, res)
 		if err != nil {
 			return txIndex, err
 		}
+	} else {
+		for _, txBody := range collection.Transactions {
+			err := e.executeTransaction(txBody, colSpan, collectionView, programs, txCtx, collectionIndex, txIndex, res)
+			txIndex++
+			if err != nil {
+				return txIndex, err
+			}
+		}
 	}
 	res.AddStateSnapshot(collectionView.(*delta.View).Interactions())
 	e.log.Info().Str("collectionID", collection.Guarantee.CollectionID.String()).
@@ -362,6 +390,21 @@ func (e *blockComputer) executeTransaction(
 		return fmt.Errorf("failed to execute transaction: %w", err)
 	}
 
+	return e.mergeTransaction(tx, txSpan, traceID, startedAt, txView, collectionView, collectionIndex, res)
+}
+
+// mergeTransaction merges the view of an executed transaction into the
+// collection view and records its results.
+func (e *blockComputer) mergeTransaction(
+	tx *fvm.TransactionProcedure,
+	txSpan opentracing.Span,
+	traceID string,
+	startedAt time.Time,
+	txView state.View,
+	collectionView state.View,
+	collectionIndex int,
+	res *execution.ComputationResult,
+) error {
 	txResult := flow.TransactionResult{
 		TransactionID:   tx.ID,
 		ComputationUsed: tx.ComputationUsed,
@@ -370,13 +413,13 @@ func (e *blockComputer) executeTransaction(
 	if tx.Err != nil {
 		txResult.ErrorMessage = tx.Err.Error()
 		e.log.Debug().
-			Hex("tx_id", logging.Entity(txBody)).
+			Hex("tx_id", tx.ID[:]).
 			Str("error_message", tx.Err.Error()).
 			Uint16("error_code", uint16(tx.Err.Code())).
 			Msg("transaction execution failed")
 	} else {
 		e.log.Debug().
-			Hex("tx_id", logging.Entity(txBody)).
+			Hex("tx_id", tx.ID[:]).
 			Msg("transaction executed successfully")
 	}
 
@@ -385,7 +428,7 @@ func (e *blockComputer) executeTransaction(
 
 	// always merge the view, fvm take cares of reverting changes
 	// of failed transaction invocation
-	err = collectionView.MergeView(txView)
+	err := collectionView.MergeView(txView)
 	if err != nil {
 		return fmt.Errorf("merging tx view to collection view failed: %w", err)
 	}
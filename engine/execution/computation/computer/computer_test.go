@@ -22,6 +22,7 @@ import (
 	"github.com/onflow/flow-go/engine/execution/computation/committer"
 	"github.com/onflow/flow-go/engine/execution/computation/computer"
 	computermock "github.com/onflow/flow-go/engine/execution/computation/computer/mock"
+	exeState "github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/engine/execution/state/delta"
 	"github.com/onflow/flow-go/engine/execution/testutil"
 	"github.com/onflow/flow-go/fvm"
@@ -29,6 +30,8 @@ import (
 	"github.com/onflow/flow-go/fvm/programs"
 	"github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/fvm/systemcontracts"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/epochs"
 	"github.com/onflow/flow-go/module/mempool/entity"
@@ -661,6 +664,123 @@ func Test_ExecutingSystemCollection(t *testing.T) {
 	committer.AssertExpectations(t)
 }
 
+func TestBlockExecutor_ExecuteBlockInParallel(t *testing.T) {
+
+	chain := flow.Localnet.Chain()
+
+	// signatures and sequence numbers are not checked, so that transactions can be
+	// generated freely
+	execCtx := fvm.NewContext(
+		zerolog.Nop(),
+		fvm.WithChain(chain),
+		fvm.WithBlocks(&fvm.NoopBlockFinder{}),
+		fvm.WithTransactionProcessors(fvm.NewTransactionInvocator(zerolog.Nop())),
+	)
+
+	vm := fvm.NewVirtualMachine(fvm.NewInterpreterRuntime())
+
+	// the header and the keys are shared by all executions, to make them deterministic
+	header := unittest.BlockHeaderFixture()
+	privateKeys, err := testutil.GenerateAccountPrivateKeys(2)
+	require.NoError(t, err)
+
+	execute := func(t *testing.T, options ...computer.BlockComputerOption) *execution.ComputationResult {
+		ledger, err := complete.NewLedger(&fixtures.NoopWAL{}, 100, metrics.NewNoopCollector(), zerolog.Nop(), complete.DefaultPathFinderVersion)
+		require.NoError(t, err)
+		ledgerCommitter := committer.NewLedgerViewCommitter(ledger, trace.NewNoopTracer())
+
+		// bootstrap the chain, with two accounts and a counter contract
+		epochConfig := epochs.DefaultEpochConfig()
+		epochConfig.NumCollectorClusters = 0
+		bootstrap := fvm.Bootstrap(
+			unittest.ServiceAccountPublicKey,
+			fvm.WithInitialTokenSupply(unittest.GenesisTokenSupply),
+			fvm.WithEpochConfig(epochConfig),
+		)
+
+		view := delta.NewView(delta.AlwaysEmptyGetRegisterFunc)
+		err = vm.Run(execCtx, bootstrap, view, programs.NewEmptyPrograms())
+		require.NoError(t, err)
+
+		accounts, err := testutil.CreateAccounts(vm, view, programs.NewEmptyPrograms(), privateKeys, chain)
+		require.NoError(t, err)
+
+		deploy := fvm.Transaction(testutil.DeployCounterContractTransaction(accounts[0], chain), 0)
+		err = vm.Run(execCtx, deploy, view, programs.NewEmptyPrograms())
+		require.NoError(t, err)
+		require.NoError(t, deploy.Err)
+
+		startState, _, _, err := ledgerCommitter.CommitView(view, flow.StateCommitment(ledger.InitialState()))
+		require.NoError(t, err)
+
+		// the first collection contains transactions of both accounts using the same
+		// counter contract, the second one deploys a contract and uses it right away
+		counter := accounts[0]
+		collections := []*entity.CompleteCollection{
+			completeCollection(
+				testutil.CreateCounterTransaction(counter, accounts[0]),
+				testutil.CreateCounterTransaction(counter, accounts[1]),
+				testutil.AddToCounterTransaction(counter, accounts[0]),
+				testutil.AddToCounterTransaction(counter, accounts[1]),
+				testutil.AddToCounterTransaction(counter, accounts[0]),
+			),
+			completeCollection(
+				testutil.DeployEventContractTransaction(accounts[1], chain, 42),
+				testutil.CreateEmitEventTransaction(accounts[1], accounts[0]),
+				testutil.AddToCounterTransaction(counter, accounts[1]),
+			),
+		}
+
+		block := flow.Block{
+			Header:  &header,
+			Payload: &flow.Payload{},
+		}
+		completeCollections := make(map[flow.Identifier]*entity.CompleteCollection)
+		for _, collection := range collections {
+			block.Payload.Guarantees = append(block.Payload.Guarantees, collection.Guarantee)
+			completeCollections[collection.Guarantee.ID()] = collection
+		}
+		executableBlock := &entity.ExecutableBlock{
+			Block:               &block,
+			CompleteCollections: completeCollections,
+			StartState:          &startState,
+		}
+
+		exe, err := computer.NewBlockComputer(vm, execCtx, metrics.NewNoopCollector(), trace.NewNoopTracer(), zerolog.Nop(), ledgerCommitter, options...)
+		require.NoError(t, err)
+
+		blockView := delta.NewView(exeState.LedgerGetRegister(ledger, startState))
+		result, err := exe.ExecuteBlock(context.Background(), executableBlock, blockView, programs.NewEmptyPrograms())
+		require.NoError(t, err)
+
+		return result
+	}
+
+	expected := execute(t)
+
+	// make sure the transactions succeeded, so that they actually conflict
+	for _, txResult := range expected.TransactionResults {
+		require.Empty(t, txResult.ErrorMessage)
+	}
+	require.NotEmpty(t, expected.Events[1])
+
+	for _, workers := range []uint{2, 4, 16} {
+		t.Run(fmt.Sprintf("%d workers", workers), func(t *testing.T) {
+			actual := execute(t, computer.WithParallelExecution(workers))
+
+			assert.Equal(t, expected.StateCommitments, actual.StateCommitments)
+			assert.Equal(t, expected.Proofs, actual.Proofs)
+			assert.Equal(t, expected.StateSnapshots, actual.StateSnapshots)
+			assert.Equal(t, expected.StateReads, actual.StateReads)
+			assert.Equal(t, expected.Events, actual.Events)
+			assert.Equal(t, expected.EventsHashes, actual.EventsHashes)
+			assert.Equal(t, expected.ServiceEvents, actual.ServiceEvents)
+			assert.Equal(t, expected.TransactionResults, actual.TransactionResults)
+			assert.Equal(t, expected.ComputationUsed, actual.ComputationUsed)
+		})
+	}
+}
+
 func generateBlock(collectionCount, transactionCount int, addressGenerator flow.AddressGenerator) *entity.ExecutableBlock {
 	return generateBlockWithVisitor(collectionCount, transactionCount, addressGenerator, nil)
 }
@@ -723,6 +843,21 @@ func generateCollection(transactionCount int, addressGenerator flow.AddressGener
 	}
 }
 
+// completeCollection returns a collection of the given transactions, with unique
+// transaction IDs.
+func completeCollection(transactions ...*flow.TransactionBody) *entity.CompleteCollection {
+	for i, txBody := range transactions {
+		txBody.SetGasLimit(fvm.DefaultGasLimit + uint64(i))
+	}
+
+	collection := flow.Collection{Transactions: transactions}
+
+	return &entity.CompleteCollection{
+		Guarantee:    &flow.CollectionGuarantee{CollectionID: collection.ID()},
+		Transactions: transactions,
+	}
+}
+
 func generateEvents(eventCount int, txIndex uint32) []flow.Event {
 	events := make([]flow.Event, eventCount)
 	for i := 0; i < eventCount; i++ {
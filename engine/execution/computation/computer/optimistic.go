@@ -0,0 +1,172 @@
+package computer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/uber/jaeger-client-go"
+
+	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/programs"
+	"github.com/onflow/flow-go/fvm/state"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/trace"
+)
+
+// optimisticTransaction is a transaction executed against a snapshot of the
+// collection view, before the results of the preceding transactions of the
+// collection are known.
+type optimisticTransaction struct {
+	tx        *fvm.TransactionProcedure
+	span      opentracing.Span
+	traceID   string
+	startedAt time.Time
+	view      *delta.View
+	programs  *programs.Programs
+	err       error
+}
+
+// conflicts returns whether the transaction touched a register which has been
+// written to the collection view since the snapshot was taken. As the collection
+// view starts empty, all registers in its delta were written by preceding
+// transactions of the collection.
+func (o *optimisticTransaction) conflicts(collectionView *delta.View) bool {
+	written := collectionView.Delta().Data
+	for key := range o.view.Interactions().Reads {
+		if _, ok := written[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// executeTransactionsOptimistically executes the transactions of a collection
+// concurrently against a snapshot of the collection view, and merges the results
+// into the collection view in order afterwards. Transactions which touched
+// registers written by preceding transactions of the collection, or which failed
+// to execute, are executed again against the up-to-date collection view. The
+// results are thus identical to executing the transactions one after another.
+func (e *blockComputer) executeTransactionsOptimistically(
+	transactions []*flow.TransactionBody,
+	colSpan opentracing.Span,
+	collectionView state.View,
+	programs *programs.Programs,
+	ctx fvm.Context,
+	collectionIndex int,
+	txIndex uint32,
+	res *execution.ComputationResult,
+) (uint32, error) {
+
+	baseView, ok := collectionView.(*delta.View)
+	if !ok {
+		return txIndex, fmt.Errorf("can not execute transactions optimistically: view type mismatch (given: %T, expected: delta.View)", collectionView)
+	}
+
+	// the underlying register reads are not safe for concurrent use
+	var readLock sync.Mutex
+	readFunc := func(owner, controller, key string) (flow.RegisterValue, error) {
+		readLock.Lock()
+		defer readLock.Unlock()
+		return baseView.Peek(owner, controller, key)
+	}
+
+	optimistic := make([]*optimisticTransaction, len(transactions))
+	indices := make(chan int, len(transactions))
+	for i := range transactions {
+		indices <- i
+	}
+	close(indices)
+
+	workers := int(e.workers)
+	if workers > len(transactions) {
+		workers = len(transactions)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				optimistic[i] = e.runTransactionOptimistically(transactions[i], colSpan, readFunc, programs, ctx, txIndex+uint32(i))
+			}
+		}()
+	}
+	wg.Wait()
+
+	reexecuted := 0
+	for i, o := range optimistic {
+		if o.err != nil || o.conflicts(baseView) {
+			o.span.Finish()
+
+			reexecuted++
+			err := e.executeTransaction(transactions[i], colSpan, collectionView, programs, ctx, collectionIndex, txIndex, res)
+			txIndex++
+			if err != nil {
+				return txIndex, err
+			}
+			continue
+		}
+
+		programs.MergeChild(o.programs)
+
+		err := e.mergeTransaction(o.tx, o.span, o.traceID, o.startedAt, o.view, collectionView, collectionIndex, res)
+		o.span.Finish()
+		txIndex++
+		if err != nil {
+			return txIndex, err
+		}
+	}
+
+	e.log.Debug().
+		Int("number_of_transactions", len(transactions)).
+		Int("number_of_reexecuted_transactions", reexecuted).
+		Msg("transactions executed optimistically")
+
+	return txIndex, nil
+}
+
+// runTransactionOptimistically executes a transaction against a snapshot view
+// using the given read function, with its own child programs.
+func (e *blockComputer) runTransactionOptimistically(
+	txBody *flow.TransactionBody,
+	colSpan opentracing.Span,
+	readFunc delta.GetRegisterFunc,
+	programs *programs.Programs,
+	ctx fvm.Context,
+	txIndex uint32,
+) *optimisticTransaction {
+
+	o := &optimisticTransaction{
+		startedAt: time.Now(),
+		span:      e.tracer.StartSpanFromParent(colSpan, trace.EXEComputeTransaction),
+		view:      delta.NewView(readFunc),
+		programs:  programs.ChildPrograms(),
+	}
+
+	if sc, ok := o.span.Context().(jaeger.SpanContext); ok {
+		o.traceID = sc.TraceID().String()
+	}
+
+	o.tx = fvm.Transaction(txBody, txIndex)
+	o.tx.SetTraceSpan(o.span)
+
+	// an inconsistent snapshot can make the execution fail in unexpected ways,
+	// the transaction is executed again against the collection view in that case
+	defer func() {
+		if r := recover(); r != nil {
+			o.err = fmt.Errorf("transaction execution panicked: %v", r)
+		}
+	}()
+
+	err := e.vm.Run(ctx, o.tx, o.view, o.programs)
+	if err != nil {
+		o.err = fmt.Errorf("failed to execute transaction: %w", err)
+	}
+
+	return o
+}
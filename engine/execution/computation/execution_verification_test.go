@@ -15,7 +15,6 @@ import (
 	bootstrapexec "github.com/onflow/flow-go/engine/execution/state/bootstrap"
 	"github.com/onflow/flow-go/engine/execution/state/delta"
 	"github.com/onflow/flow-go/engine/execution/testutil"
-	"github.com/onflow/flow-go/engine/verification/fetcher"
 	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/fvm/blueprints"
 	"github.com/onflow/flow-go/fvm/programs"
@@ -667,7 +666,7 @@ func executeBlockAndVerifyWithParameters(t *testing.T,
 
 	for i, chunk := range er.Chunks {
 		isSystemChunk := i == er.Chunks.Len()-1
-		offsetForChunk, err := fetcher.TransactionOffsetForChunk(er.Chunks, chunk.Index)
+		offsetForChunk, err := verification.TransactionOffsetForChunk(er.Chunks, chunk.Index)
 		require.NoError(t, err)
 
 		vcds[i] = &verification.VerifiableChunkData{
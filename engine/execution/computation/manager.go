@@ -67,6 +67,7 @@ func New(
 	committer computer.ViewCommitter,
 	scriptLogThreshold time.Duration,
 	uploader uploader.Uploader,
+	options ...computer.BlockComputerOption,
 ) (*Manager, error) {
 	log := logger.With().Str("engine", "computation").Logger()
 
@@ -77,6 +78,7 @@ func New(
 		tracer,
 		log.With().Str("component", "block_computer").Logger(),
 		committer,
+		options...,
 	)
 
 	if err != nil {
@@ -0,0 +1,262 @@
+package eviction
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+)
+
+// TriePinner exempts the tries of ledger states from eviction.
+type TriePinner interface {
+	// Pin exempts the trie of the given state from eviction until it is unpinned.
+	Pin(state ledger.State) error
+	// Unpin releases a pin on the trie of the given state.
+	Unpin(state ledger.State)
+}
+
+// pinnedBlock is an executed block whose trie is pinned.
+type pinnedBlock struct {
+	height uint64
+	commit flow.StateCommitment
+}
+
+// Policy is a sealing-driven eviction policy for the execution ledger. It pins
+// the tries of all executed blocks which are not sealed yet, such that they are
+// not evicted by the LRU heuristic of the forest, regardless of how long sealing
+// stalls. Tries are pinned as soon as their block is executed, and checked for
+// missing pins on startup and finalization. Once a block is sealed and the trie
+// of a newer sealed block is pinned, or once a block is orphaned, its trie is
+// released again.
+type Policy struct {
+	notifications.NoopConsumer // satisfy the FinalizationConsumer interface
+
+	unit      *engine.Unit
+	log       zerolog.Logger
+	state     protocol.State
+	execState state.ReadOnlyExecutionState
+	pinner    TriePinner
+	notifier  engine.Notifier
+
+	mu         sync.Mutex
+	pinned     map[flow.Identifier]pinnedBlock
+	nextHeight uint64 // the next finalized height to pin
+}
+
+func NewPolicy(
+	logger zerolog.Logger,
+	state protocol.State,
+	execState state.ReadOnlyExecutionState,
+	pinner TriePinner,
+) *Policy {
+	return &Policy{
+		unit:      engine.NewUnit(),
+		log:       logger.With().Str("component", "eviction_policy").Logger(),
+		state:     state,
+		execState: execState,
+		pinner:    pinner,
+		notifier:  engine.NewNotifier(),
+		pinned:    make(map[flow.Identifier]pinnedBlock),
+	}
+}
+
+// Ready pins the tries of the executed blocks which are not sealed yet, before
+// starting to process finalization events.
+func (p *Policy) Ready() <-chan struct{} {
+	err := p.update()
+	if err != nil {
+		p.log.Fatal().Err(err).Msg("could not pin tries of unsealed blocks on startup")
+	}
+	p.unit.Launch(p.loop)
+	return p.unit.Ready()
+}
+
+func (p *Policy) Done() <-chan struct{} {
+	return p.unit.Done()
+}
+
+// OnBlockExecuted pins the trie of the executed block right away, such that it
+// can not be evicted before the next update, which is triggered to release the
+// trie in case the block is sealed or orphaned already.
+func (p *Policy) OnBlockExecuted(header *flow.Header, finalState flow.StateCommitment) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pinCommit(header.ID(), header.Height, finalState)
+	p.notifier.Notify()
+}
+
+// OnFinalizedBlock triggers an update of the pinned tries. As updating the
+// pins requires querying the protocol state, the update is done asynchronously.
+func (p *Policy) OnFinalizedBlock(*model.Block) {
+	p.notifier.Notify()
+}
+
+func (p *Policy) loop() {
+	for {
+		select {
+		case <-p.unit.Quit():
+			return
+		case <-p.notifier.Channel():
+			err := p.update()
+			if err != nil {
+				p.log.Error().Err(err).Msg("could not update pinned tries")
+			}
+		}
+	}
+}
+
+// PinnedBlocks returns the number of blocks whose tries are pinned.
+func (p *Policy) PinnedBlocks() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.pinned)
+}
+
+// update pins the tries of newly executed blocks and releases the tries of
+// sealed and orphaned blocks.
+func (p *Policy) update() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sealed, err := p.state.Sealed().Head()
+	if err != nil {
+		return fmt.Errorf("could not get sealed block: %w", err)
+	}
+	final := p.state.Final()
+	finalized, err := final.Head()
+	if err != nil {
+		return fmt.Errorf("could not get finalized block: %w", err)
+	}
+
+	// pin the finalized blocks from the latest sealed block onwards, until the
+	// first block which is not executed yet
+	if p.nextHeight < sealed.Height {
+		p.nextHeight = sealed.Height
+	}
+	for ; p.nextHeight <= finalized.Height; p.nextHeight++ {
+		header, err := p.state.AtHeight(p.nextHeight).Head()
+		if err != nil {
+			return fmt.Errorf("could not get finalized block at height %d: %w", p.nextHeight, err)
+		}
+		executed, err := p.pin(header.ID(), header.Height)
+		if err != nil {
+			return err
+		}
+		if !executed {
+			break
+		}
+	}
+
+	// pin the executed blocks which are not finalized yet
+	descendants, err := final.ValidDescendants()
+	if err != nil {
+		return fmt.Errorf("could not get descendants of finalized block: %w", err)
+	}
+	for _, blockID := range descendants {
+		if _, ok := p.pinned[blockID]; ok {
+			continue
+		}
+		header, err := p.state.AtBlockID(blockID).Head()
+		if err != nil {
+			return fmt.Errorf("could not get block %v: %w", blockID, err)
+		}
+		_, err = p.pin(blockID, header.Height)
+		if err != nil {
+			return err
+		}
+	}
+
+	return p.release(sealed, finalized)
+}
+
+// pin pins the trie of the given block if it is executed. It returns false if
+// the block is not executed yet.
+func (p *Policy) pin(blockID flow.Identifier, height uint64) (bool, error) {
+	if _, ok := p.pinned[blockID]; ok {
+		return true, nil
+	}
+
+	commit, err := p.execState.StateCommitmentByBlockID(p.unit.Ctx(), blockID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not get state commitment for block %v: %w", blockID, err)
+	}
+
+	p.pinCommit(blockID, height, commit)
+	return true, nil
+}
+
+// pinCommit pins the trie of the given state commitment of an executed block,
+// unless the block is pinned already.
+func (p *Policy) pinCommit(blockID flow.Identifier, height uint64, commit flow.StateCommitment) {
+	if _, ok := p.pinned[blockID]; ok {
+		return
+	}
+
+	err := p.pinner.Pin(ledger.State(commit))
+	if err != nil {
+		// the trie is not available, so there is nothing to protect from eviction
+		p.log.Warn().Err(err).
+			Hex("block_id", blockID[:]).
+			Uint64("height", height).
+			Msg("could not pin trie of executed block")
+		return
+	}
+
+	p.pinned[blockID] = pinnedBlock{height: height, commit: commit}
+}
+
+// release unpins the tries of orphaned blocks, and the tries of sealed blocks
+// below the highest pinned sealed block.
+func (p *Policy) release(sealed *flow.Header, finalized *flow.Header) error {
+	// the highest sealed block with a pinned trie remains pinned, as it is the
+	// starting point for executing the unsealed blocks
+	var highestSealed uint64
+	var unpin []flow.Identifier
+	for blockID, block := range p.pinned {
+		if block.height > finalized.Height {
+			continue
+		}
+		header, err := p.state.AtHeight(block.height).Head()
+		if err != nil {
+			return fmt.Errorf("could not get finalized block at height %d: %w", block.height, err)
+		}
+		if header.ID() != blockID {
+			// the block conflicts with the finalized fork
+			unpin = append(unpin, blockID)
+			continue
+		}
+		if block.height <= sealed.Height && block.height > highestSealed {
+			highestSealed = block.height
+		}
+	}
+	for blockID, block := range p.pinned {
+		if block.height < highestSealed {
+			unpin = append(unpin, blockID)
+		}
+	}
+
+	for _, blockID := range unpin {
+		block, ok := p.pinned[blockID]
+		if !ok {
+			continue
+		}
+		p.pinner.Unpin(ledger.State(block.commit))
+		delete(p.pinned, blockID)
+	}
+
+	return nil
+}
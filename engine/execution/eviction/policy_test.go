@@ -0,0 +1,249 @@
+package eviction
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	statemock "github.com/onflow/flow-go/engine/execution/state/mock"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	protocolmock "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// countingPinner counts the pins of each state.
+type countingPinner struct {
+	sync.Mutex
+	pins map[ledger.State]int
+}
+
+func (c *countingPinner) Pin(state ledger.State) error {
+	c.Lock()
+	defer c.Unlock()
+	c.pins[state]++
+	return nil
+}
+
+func (c *countingPinner) Unpin(state ledger.State) {
+	c.Lock()
+	defer c.Unlock()
+	c.pins[state]--
+	if c.pins[state] == 0 {
+		delete(c.pins, state)
+	}
+}
+
+func (c *countingPinner) isPinned(commit flow.StateCommitment) bool {
+	c.Lock()
+	defer c.Unlock()
+	return c.pins[ledger.State(commit)] > 0
+}
+
+// testChain mocks the protocol and execution state of a chain of blocks, with
+// mutable sealed and finalized heights, and a mutable set of executed blocks.
+type testChain struct {
+	sealed      uint64
+	finalized   uint64
+	canonical   []*flow.Header
+	descendants []*flow.Header
+	commits     map[flow.Identifier]flow.StateCommitment
+	executed    map[flow.Identifier]bool
+
+	state     *protocolmock.State
+	execState *statemock.ReadOnlyExecutionState
+}
+
+func newTestChain(length int) *testChain {
+	c := &testChain{
+		commits:   make(map[flow.Identifier]flow.StateCommitment),
+		executed:  make(map[flow.Identifier]bool),
+		state:     new(protocolmock.State),
+		execState: new(statemock.ReadOnlyExecutionState),
+	}
+
+	root := unittest.BlockHeaderFixture()
+	root.Height = 0
+	c.canonical = append(c.canonical, &root)
+	for i := 1; i < length; i++ {
+		header := unittest.BlockHeaderWithParentFixture(c.canonical[i-1])
+		c.canonical = append(c.canonical, &header)
+	}
+	for _, header := range c.canonical {
+		c.commits[header.ID()] = unittest.StateCommitmentFixture()
+	}
+
+	c.state.On("Sealed").Return(func() protocol.Snapshot {
+		return c.snapshot(c.canonical[c.sealed])
+	})
+	c.state.On("Final").Return(func() protocol.Snapshot {
+		return c.snapshot(c.canonical[c.finalized])
+	})
+	c.state.On("AtHeight", mock.Anything).Return(func(height uint64) protocol.Snapshot {
+		return c.snapshot(c.canonical[height])
+	})
+	c.state.On("AtBlockID", mock.Anything).Return(func(blockID flow.Identifier) protocol.Snapshot {
+		for _, headers := range [][]*flow.Header{c.canonical, c.descendants} {
+			for _, header := range headers {
+				if header.ID() == blockID {
+					return c.snapshot(header)
+				}
+			}
+		}
+		panic("unknown block")
+	})
+
+	c.execState.On("StateCommitmentByBlockID", mock.Anything, mock.Anything).Return(
+		func(_ context.Context, blockID flow.Identifier) flow.StateCommitment {
+			return c.commits[blockID]
+		},
+		func(_ context.Context, blockID flow.Identifier) error {
+			if !c.executed[blockID] {
+				return storage.ErrNotFound
+			}
+			return nil
+		},
+	)
+
+	return c
+}
+
+func (c *testChain) snapshot(header *flow.Header) protocol.Snapshot {
+	snapshot := new(protocolmock.Snapshot)
+	snapshot.On("Head").Return(header, nil)
+	snapshot.On("ValidDescendants").Return(func() []flow.Identifier {
+		var ids []flow.Identifier
+		for _, descendant := range c.descendants {
+			ids = append(ids, descendant.ID())
+		}
+		return ids
+	}, nil)
+	return snapshot
+}
+
+// fork adds a block conflicting with the canonical block at the given height
+// as an unfinalized descendant.
+func (c *testChain) fork(height uint64) *flow.Header {
+	header := unittest.BlockHeaderWithParentFixture(c.canonical[height-1])
+	c.commits[header.ID()] = unittest.StateCommitmentFixture()
+	c.descendants = append(c.descendants, &header)
+	return &header
+}
+
+func (c *testChain) execute(headers ...*flow.Header) {
+	for _, header := range headers {
+		c.executed[header.ID()] = true
+	}
+}
+
+func (c *testChain) commit(header *flow.Header) flow.StateCommitment {
+	return c.commits[header.ID()]
+}
+
+func TestPolicy(t *testing.T) {
+	chain := newTestChain(6)
+	pinner := &countingPinner{pins: make(map[ledger.State]int)}
+	policy := NewPolicy(zerolog.Nop(), chain.state, chain.execState, pinner)
+	blocks := chain.canonical
+
+	// blocks 0 to 3 are executed, block 3 and a conflicting block are not finalized
+	chain.finalized = 2
+	chain.descendants = []*flow.Header{blocks[3]}
+	conflicting := chain.fork(3)
+	chain.execute(blocks[0], blocks[1], blocks[2], blocks[3], conflicting)
+
+	require.NoError(t, policy.update())
+	for _, header := range []*flow.Header{blocks[0], blocks[1], blocks[2], blocks[3], conflicting} {
+		assert.True(t, pinner.isPinned(chain.commit(header)), "trie of block at height %d should be pinned", header.Height)
+	}
+	assert.Equal(t, 5, policy.PinnedBlocks())
+
+	// a sealing stall does not release any tries
+	chain.finalized = 3
+	chain.descendants = []*flow.Header{blocks[4], blocks[5]}
+	chain.execute(blocks[4])
+
+	require.NoError(t, policy.update())
+	assert.True(t, pinner.isPinned(chain.commit(blocks[0])))
+	assert.True(t, pinner.isPinned(chain.commit(blocks[4])))
+	assert.False(t, pinner.isPinned(chain.commit(blocks[5])), "trie of unexecuted block should not be pinned")
+	// the conflicting block is orphaned
+	assert.False(t, pinner.isPinned(chain.commit(conflicting)))
+	assert.Equal(t, 5, policy.PinnedBlocks())
+
+	// sealing block 2 releases the tries of the older sealed blocks
+	chain.sealed = 2
+	chain.finalized = 5
+	chain.descendants = nil
+	chain.execute(blocks[5])
+
+	require.NoError(t, policy.update())
+	assert.False(t, pinner.isPinned(chain.commit(blocks[0])))
+	assert.False(t, pinner.isPinned(chain.commit(blocks[1])))
+	for _, header := range blocks[2:] {
+		assert.True(t, pinner.isPinned(chain.commit(header)), "trie of block at height %d should be pinned", header.Height)
+	}
+	assert.Equal(t, 4, policy.PinnedBlocks())
+	assert.Len(t, pinner.pins, 4)
+}
+
+// TestPolicy_UnexecutedFinalizedBlock tests that finalized blocks are pinned
+// once they are executed.
+func TestPolicy_UnexecutedFinalizedBlock(t *testing.T) {
+	chain := newTestChain(4)
+	pinner := &countingPinner{pins: make(map[ledger.State]int)}
+	policy := NewPolicy(zerolog.Nop(), chain.state, chain.execState, pinner)
+	blocks := chain.canonical
+
+	chain.finalized = 3
+	chain.execute(blocks[0])
+	require.NoError(t, policy.update())
+	assert.Equal(t, 1, policy.PinnedBlocks())
+
+	chain.execute(blocks[1], blocks[2], blocks[3])
+	require.NoError(t, policy.update())
+	assert.Equal(t, 4, policy.PinnedBlocks())
+	for _, header := range blocks {
+		assert.True(t, pinner.isPinned(chain.commit(header)))
+	}
+}
+
+// TestPolicy_OnBlockExecuted tests that tries are pinned as soon as their block
+// is executed, and released once the block is orphaned.
+func TestPolicy_OnBlockExecuted(t *testing.T) {
+	chain := newTestChain(3)
+	pinner := &countingPinner{pins: make(map[ledger.State]int)}
+	policy := NewPolicy(zerolog.Nop(), chain.state, chain.execState, pinner)
+	blocks := chain.canonical
+
+	chain.finalized = 1
+	chain.descendants = []*flow.Header{blocks[2]}
+	conflicting := chain.fork(2)
+	chain.execute(blocks[0], blocks[1])
+	require.NoError(t, policy.update())
+
+	// executed blocks are pinned without waiting for finalization
+	chain.execute(blocks[2], conflicting)
+	policy.OnBlockExecuted(blocks[2], chain.commit(blocks[2]))
+	policy.OnBlockExecuted(conflicting, chain.commit(conflicting))
+	assert.True(t, pinner.isPinned(chain.commit(blocks[2])))
+	assert.True(t, pinner.isPinned(chain.commit(conflicting)))
+
+	// pins are not duplicated by updates
+	require.NoError(t, policy.update())
+	assert.Equal(t, 4, policy.PinnedBlocks())
+	assert.Len(t, pinner.pins, 4)
+
+	chain.finalized = 2
+	chain.descendants = nil
+	require.NoError(t, policy.update())
+	assert.True(t, pinner.isPinned(chain.commit(blocks[2])))
+	assert.False(t, pinner.isPinned(chain.commit(conflicting)))
+}
@@ -35,6 +35,13 @@ import (
 	"github.com/onflow/flow-go/utils/logging"
 )
 
+// ExecutionConsumer consumes notifications about executed blocks.
+type ExecutionConsumer interface {
+	// OnBlockExecuted is called once the execution state of the given block has
+	// been persisted. It must not block.
+	OnBlockExecuted(header *flow.Header, finalState flow.StateCommitment)
+}
+
 // An Engine receives and saves incoming blocks.
 type Engine struct {
 	psEvents.Noop // satisfy protocol events consumer interface
@@ -65,6 +72,7 @@ type Engine struct {
 	syncFast           bool                // sync fast allows execution node to skip fetching collection during state syncing, and rely on state syncing to catch up
 	checkStakedAtBlock func(blockID flow.Identifier) (bool, error)
 	pauseExecution     bool
+	executionConsumers []ExecutionConsumer
 }
 
 func New(
@@ -134,6 +142,12 @@ func New(
 	return &eng, nil
 }
 
+// AddExecutionConsumer adds a consumer notified about executed blocks. It must
+// be called before the engine is started.
+func (e *Engine) AddExecutionConsumer(consumer ExecutionConsumer) {
+	e.executionConsumers = append(e.executionConsumers, consumer)
+}
+
 // Ready returns a channel that will close when the engine has
 // successfully started.
 func (e *Engine) Ready() <-chan struct{} {
@@ -589,6 +603,10 @@ func (e *Engine) executeBlock(ctx context.Context, executableBlock *entity.Execu
 		return
 	}
 
+	for _, consumer := range e.executionConsumers {
+		consumer.OnBlockExecuted(executableBlock.Block.Header, finalState)
+	}
+
 	// if the receipt is for a sealed block, then no need to broadcast it.
 	lastSealed, err := e.state.Sealed().Head()
 	if err != nil {
@@ -1016,7 +1034,10 @@ func (e *Engine) ExecuteScriptAtBlockID(ctx context.Context, script []byte, argu
 		return nil, fmt.Errorf("failed to get block (%s): %w", blockID, err)
 	}
 
-	blockView := e.execState.NewView(stateCommit)
+	blockView, err := e.newBlockView(ctx, block, stateCommit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create view for block (%s): %w", blockID, err)
+	}
 
 	if e.extensiveLogging {
 		args := make([]string, 0)
@@ -1045,11 +1066,45 @@ func (e *Engine) GetAccount(ctx context.Context, addr flow.Address, blockID flow
 		return nil, fmt.Errorf("failed to get block (%s): %w", blockID, err)
 	}
 
-	blockView := e.execState.NewView(stateCommit)
+	blockView, err := e.newBlockView(ctx, block, stateCommit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create view for block (%s): %w", blockID, err)
+	}
 
 	return e.computationManager.GetAccount(addr, block, blockView)
 }
 
+// newBlockView creates a view of the state after executing the given block.
+// The state of sealed blocks is read from the register history, as the ledger
+// only holds the state of recent blocks.
+func (e *Engine) newBlockView(ctx context.Context, header *flow.Header, commit flow.StateCommitment) (*delta.View, error) {
+	sealed, err := e.state.Sealed().Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sealed block: %w", err)
+	}
+	if header.Height > sealed.Height {
+		return e.execState.NewView(commit), nil
+	}
+
+	// the register history only contains the state of finalized blocks
+	finalized, err := e.state.AtHeight(header.Height).Head()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get finalized block at height %d: %w", header.Height, err)
+	}
+	if finalized.ID() != header.ID() {
+		return e.execState.NewView(commit), nil
+	}
+
+	view, err := e.execState.NewViewAtHeight(ctx, header.Height)
+	if errors.Is(err, state.ErrRegisterHistoryUnavailable) {
+		return e.execState.NewView(commit), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return view, nil
+}
+
 func (e *Engine) handleComputationResult(
 	ctx context.Context,
 	result *execution.ComputationResult,
@@ -1121,7 +1176,8 @@ func (e *Engine) saveExecutionResults(
 		executionReceipt,
 		result.Events,
 		result.ServiceEvents,
-		result.TransactionResults)
+		result.TransactionResults,
+		result.TrieUpdates)
 	if err != nil {
 		return nil, fmt.Errorf("cannot persist execution state: %w", err)
 	}
@@ -248,6 +248,7 @@ func (ctx *testingContext) assertSuccessfulBlockComputation(
 			mock.Anything,
 			mock.Anything,
 			mock.Anything,
+			mock.Anything,
 		).
 		Return(nil)
 
@@ -901,7 +902,7 @@ func TestExecutionGenerationResultsAreChained(t *testing.T) {
 		Return(previousExecutionResultID, nil)
 
 	execState.
-		On("PersistExecutionState", mock.Anything, executableBlock.Block.Header, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		On("PersistExecutionState", mock.Anything, executableBlock.Block.Header, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(nil)
 
 	e := Engine{
@@ -943,6 +944,11 @@ func TestExecuteScriptAtBlockID(t *testing.T) {
 		view := new(delta.View)
 		ctx.executionState.On("NewView", *blockA.StartState).Return(view)
 
+		// the block is not sealed yet, so its state is read from the ledger
+		sealedSnapshot := new(protocol.Snapshot)
+		sealedSnapshot.On("Head").Return(&flow.Header{Height: blockA.Block.Header.Height - 1}, nil)
+		ctx.state.On("Sealed").Return(sealedSnapshot)
+
 		// Successful call to computation manager
 		ctx.computationManager.
 			On("ExecuteScript", script, [][]byte(nil), blockA.Block.Header, view).
@@ -960,6 +966,42 @@ func TestExecuteScriptAtBlockID(t *testing.T) {
 	})
 }
 
+// TestExecuteScriptAtSealedBlockID tests that scripts at sealed blocks are
+// executed on the state read from the register history.
+func TestExecuteScriptAtSealedBlockID(t *testing.T) {
+	runWithEngine(t, func(ctx testingContext) {
+		script := []byte{1, 1, 2, 3, 5, 8, 11}
+		scriptResult := []byte{1}
+
+		blockA := unittest.ExecutableBlockFixture(nil)
+		blockA.StartState = unittest.StateCommitmentPointerFixture()
+
+		snapshot := new(protocol.Snapshot)
+		snapshot.On("Head").Return(blockA.Block.Header, nil)
+
+		ctx.stateCommitmentExist(blockA.ID(), *blockA.StartState)
+
+		ctx.state.On("AtBlockID", blockA.Block.ID()).Return(snapshot)
+		ctx.state.On("AtHeight", blockA.Block.Header.Height).Return(snapshot)
+		ctx.state.On("Sealed").Return(snapshot)
+
+		view := new(delta.View)
+		ctx.executionState.On("NewViewAtHeight", mock.Anything, blockA.Block.Header.Height).Return(view, nil)
+
+		ctx.computationManager.
+			On("ExecuteScript", script, [][]byte(nil), blockA.Block.Header, view).
+			Return(scriptResult, nil)
+
+		res, err := ctx.engine.ExecuteScriptAtBlockID(context.Background(), script, nil, blockA.Block.ID())
+		assert.NoError(t, err)
+		assert.Equal(t, scriptResult, res)
+
+		ctx.computationManager.AssertExpectations(t)
+		ctx.executionState.AssertExpectations(t)
+		ctx.state.AssertExpectations(t)
+	})
+}
+
 func Test_SPOCKGeneration(t *testing.T) {
 	runWithEngine(t, func(ctx testingContext) {
 
@@ -0,0 +1,249 @@
+package pruner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/module/pruner"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/badger/transaction"
+)
+
+// Pruner deletes the execution data of finalized blocks which are more than
+// a given number of blocks below the latest sealed and executed block. The
+// execution data of a block consists of its events, service events, transaction
+// results, register interactions and chunk data packs. State commitments and
+// execution results are kept, as they are small and required to resume
+// execution and to serve the protocol.
+//
+// Blocks which were executed but orphaned by finalization are pruned too: when
+// the finalized block at a height is pruned, all forks branching off its parent
+// are pruned entirely.
+type Pruner struct {
+	*pruner.Loop
+	log            zerolog.Logger
+	db             *badger.DB
+	state          protocol.State
+	headers        storage.Headers
+	execState      state.ReadOnlyExecutionState
+	results        storage.ExecutionResults
+	events         storage.Events
+	serviceEvents  storage.ServiceEvents
+	txResults      storage.TransactionResults
+	chunkDataPacks storage.ChunkDataPacks
+	retention      uint64 // number of blocks below the sealed height to keep
+}
+
+func NewPruner(
+	logger zerolog.Logger,
+	db *badger.DB,
+	state protocol.State,
+	headers storage.Headers,
+	execState state.ReadOnlyExecutionState,
+	results storage.ExecutionResults,
+	events storage.Events,
+	serviceEvents storage.ServiceEvents,
+	txResults storage.TransactionResults,
+	chunkDataPacks storage.ChunkDataPacks,
+	metrics module.ExecutionMetrics,
+	retention uint64,
+	batchSize uint,
+	interval time.Duration,
+) *Pruner {
+	log := logger.With().Str("component", "execution_data_pruner").Logger()
+	p := &Pruner{
+		log:            log,
+		db:             db,
+		state:          state,
+		headers:        headers,
+		execState:      execState,
+		results:        results,
+		events:         events,
+		serviceEvents:  serviceEvents,
+		txResults:      txResults,
+		chunkDataPacks: chunkDataPacks,
+		retention:      retention,
+	}
+	p.Loop = pruner.NewLoop(log, state, p, batchSize, interval,
+		pruner.WithOnPruned(func(height uint64, blocks uint, dur time.Duration) {
+			metrics.ExecutionDataPruned(height, int(blocks), dur)
+		}),
+	)
+	return p
+}
+
+// PrunedHeight returns the height up to which execution data is pruned.
+func (p *Pruner) PrunedHeight() (uint64, error) {
+	var pruned uint64
+	err := p.db.View(operation.RetrieveExecutionDataPrunedHeight(&pruned))
+	return pruned, err
+}
+
+// InitPrunedHeight initializes the height up to which execution data is pruned.
+func (p *Pruner) InitPrunedHeight(height uint64) error {
+	return operation.RetryOnConflict(p.db.Update, operation.InsertExecutionDataPrunedHeight(height))
+}
+
+// PruneHeight deletes the execution data of the given finalized block, and of
+// the forks orphaned by its finalization. Orphaned forks are pruned first, as
+// they are not found anymore once the pruned height is above their root.
+func (p *Pruner) PruneHeight(header *flow.Header) error {
+	orphans, err := p.pruneOrphans(header)
+	if err != nil {
+		return fmt.Errorf("could not prune orphaned forks: %w", err)
+	}
+	if orphans > 0 {
+		p.log.Debug().
+			Uint64("height", header.Height).
+			Int("pruned_orphans", orphans).
+			Msg("orphaned forks pruned")
+	}
+
+	return p.pruneBlock(header, true)
+}
+
+// PruneLimit returns the highest height whose execution data can be pruned,
+// which is the retention below the latest block that is both sealed and
+// executed. Blocks which are not executed yet are never pruned, as their
+// execution data would be stored after pruning otherwise.
+func (p *Pruner) PruneLimit() (uint64, error) {
+	sealed, err := p.state.Sealed().Head()
+	if err != nil {
+		return 0, fmt.Errorf("could not get sealed block: %w", err)
+	}
+
+	executed, _, err := p.execState.GetHighestExecutedBlockID(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("could not get highest executed block: %w", err)
+	}
+
+	limit := sealed.Height
+	if executed < limit {
+		limit = executed
+	}
+	if limit <= p.retention {
+		return 0, nil
+	}
+
+	return limit - p.retention, nil
+}
+
+// pruneOrphans deletes the execution data of all blocks on forks which branch
+// off the parent of the given finalized block, and returns their number. Such
+// forks can not be extended anymore, so their blocks are pruned regardless of
+// their height.
+func (p *Pruner) pruneOrphans(finalized *flow.Header) (int, error) {
+	siblings, err := p.children(finalized.ParentID)
+	if err != nil {
+		return 0, err
+	}
+
+	var forks []*flow.Header
+	for _, sibling := range siblings {
+		if sibling.ID() != finalized.ID() {
+			forks = append(forks, sibling)
+		}
+	}
+
+	pruned := 0
+	for len(forks) > 0 {
+		header := forks[len(forks)-1]
+		forks = forks[:len(forks)-1]
+
+		children, err := p.children(header.ID())
+		if err != nil {
+			return pruned, err
+		}
+		forks = append(forks, children...)
+
+		err = p.pruneBlock(header, false)
+		if err != nil {
+			return pruned, fmt.Errorf("could not prune orphaned block %v: %w", header.ID(), err)
+		}
+		pruned++
+	}
+
+	return pruned, nil
+}
+
+// children returns the children of the given block, if any.
+func (p *Pruner) children(blockID flow.Identifier) ([]*flow.Header, error) {
+	children, err := p.headers.ByParentID(blockID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get children of block %v: %w", blockID, err)
+	}
+	return children, nil
+}
+
+// pruneBlock deletes the execution data of the given block. For finalized
+// blocks, it updates the pruned height to the height of the block in the same
+// transaction.
+func (p *Pruner) pruneBlock(header *flow.Header, finalized bool) error {
+	blockID := header.ID()
+
+	var chunkIDs []flow.Identifier
+	result, err := p.results.ByBlockID(blockID)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("could not get execution result: %w", err)
+	}
+	if err == nil {
+		for _, chunk := range result.Chunks {
+			chunkIDs = append(chunkIDs, chunk.ID())
+		}
+	}
+
+	// the data is removed through the storage layer, so that it is evicted from
+	// the caches too and not served anymore once pruned
+	return operation.RetryOnConflictTx(p.db, transaction.Update, func(tx *transaction.Tx) error {
+		err := p.events.RemoveByBlockIDTx(blockID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not remove events: %w", err)
+		}
+
+		err = p.serviceEvents.RemoveByBlockIDTx(blockID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not remove service events: %w", err)
+		}
+
+		err = p.txResults.RemoveByBlockIDTx(blockID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not remove transaction results: %w", err)
+		}
+
+		err = operation.RemoveExecutionStateInteractions(blockID)(tx.DBTxn)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("could not remove register interactions: %w", err)
+		}
+
+		for _, chunkID := range chunkIDs {
+			err = p.chunkDataPacks.RemoveTx(chunkID)(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove chunk data pack %v: %w", chunkID, err)
+			}
+		}
+
+		if !finalized {
+			return nil
+		}
+
+		err = operation.UpdateExecutionDataPrunedHeight(header.Height)(tx.DBTxn)
+		if err != nil {
+			return fmt.Errorf("could not update pruned height: %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,300 @@
+package pruner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	statemock "github.com/onflow/flow-go/engine/execution/state/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/state/protocol"
+	protocolmock "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	badgermodel "github.com/onflow/flow-go/storage/badger/model"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/badger/procedure"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// storeExecutionData stores an execution result with a single chunk and the
+// execution data of the given block.
+func storeExecutionData(t *testing.T, db *badger.DB, header *flow.Header) *flow.ExecutionResult {
+	blockID := header.ID()
+	result := unittest.ExecutionResultFixture()
+	result.BlockID = blockID
+	txID := unittest.IdentifierFixture()
+
+	err := db.Update(func(tx *badger.Txn) error {
+		err := operation.InsertExecutionResult(result)(tx)
+		require.NoError(t, err)
+		err = operation.IndexExecutionResult(blockID, result.ID())(tx)
+		require.NoError(t, err)
+		for _, chunk := range result.Chunks {
+			err = operation.InsertChunkDataPack(&badgermodel.StoredChunkDataPack{ChunkID: chunk.ID()})(tx)
+			require.NoError(t, err)
+		}
+		err = operation.InsertEvent(blockID, unittest.EventFixture(flow.EventAccountCreated, 0, 0, txID, 10))(tx)
+		require.NoError(t, err)
+		err = operation.InsertServiceEvent(blockID, unittest.EventFixture(flow.EventAccountCreated, 0, 1, txID, 10))(tx)
+		require.NoError(t, err)
+		err = operation.InsertTransactionResult(blockID, &flow.TransactionResult{TransactionID: txID})(tx)
+		require.NoError(t, err)
+		return operation.InsertExecutionStateInteractions(blockID, []*delta.Snapshot{{Delta: delta.NewDelta()}})(tx)
+	})
+	require.NoError(t, err)
+
+	return result
+}
+
+// assertExecutionData asserts whether the execution data of the given block is stored.
+func assertExecutionData(t *testing.T, db *badger.DB, header *flow.Header, result *flow.ExecutionResult, stored bool) {
+	blockID := header.ID()
+
+	var events []flow.Event
+	require.NoError(t, db.View(operation.LookupEventsByBlockID(blockID, &events)))
+	var serviceEvents []flow.Event
+	require.NoError(t, db.View(operation.LookupServiceEventsByBlockID(blockID, &serviceEvents)))
+	var txResults []flow.TransactionResult
+	require.NoError(t, db.View(operation.LookupTransactionResultsByBlockID(blockID, &txResults)))
+	var interactions []*delta.Snapshot
+	interactionsErr := db.View(operation.RetrieveExecutionStateInteractions(blockID, &interactions))
+	var chunkDataPack badgermodel.StoredChunkDataPack
+	chunkDataPackErr := db.View(operation.RetrieveChunkDataPack(result.Chunks[0].ID(), &chunkDataPack))
+
+	if stored {
+		assert.Len(t, events, 1, "events of block at height %d should be stored", header.Height)
+		assert.Len(t, serviceEvents, 1)
+		assert.Len(t, txResults, 1)
+		assert.NoError(t, interactionsErr)
+		assert.NoError(t, chunkDataPackErr)
+		return
+	}
+
+	assert.Empty(t, events, "events of block at height %d should be pruned", header.Height)
+	assert.Empty(t, serviceEvents)
+	assert.Empty(t, txResults)
+	assert.ErrorIs(t, interactionsErr, storage.ErrNotFound)
+	assert.ErrorIs(t, chunkDataPackErr, storage.ErrNotFound)
+
+	// the execution result is kept
+	var resultID flow.Identifier
+	assert.NoError(t, db.View(operation.LookupExecutionResult(blockID, &resultID)))
+}
+
+func TestPruner(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		root := unittest.BlockHeaderFixture()
+		root.Height = 0
+		headers := []*flow.Header{&root}
+		for i := 1; i < 10; i++ {
+			header := unittest.BlockHeaderWithParentFixture(headers[i-1])
+			headers = append(headers, &header)
+		}
+		results := make([]*flow.ExecutionResult, 0, len(headers))
+		for _, header := range headers {
+			results = append(results, storeExecutionData(t, db, header))
+		}
+
+		sealed := uint64(8)
+		executed := uint64(9)
+
+		params := new(protocolmock.Params)
+		params.On("Root").Return(&root, nil)
+		state := new(protocolmock.State)
+		state.On("Params").Return(params)
+		state.On("Sealed").Return(func() protocol.Snapshot {
+			snapshot := new(protocolmock.Snapshot)
+			snapshot.On("Head").Return(headers[sealed], nil)
+			return snapshot
+		})
+		state.On("AtHeight", mock.Anything).Return(func(height uint64) protocol.Snapshot {
+			snapshot := new(protocolmock.Snapshot)
+			snapshot.On("Head").Return(headers[height], nil)
+			return snapshot
+		})
+		execState := new(statemock.ReadOnlyExecutionState)
+		execState.On("GetHighestExecutedBlockID", mock.Anything).Return(
+			func(_ context.Context) uint64 { return executed },
+			func(_ context.Context) flow.Identifier { return headers[executed].ID() },
+			nil,
+		)
+
+		events := bstorage.NewEvents(metrics.NewNoopCollector(), db)
+		txResults := bstorage.NewTransactionResults(metrics.NewNoopCollector(), db, 100)
+		chunkDataPacks := bstorage.NewChunkDataPacks(metrics.NewNoopCollector(), db, bstorage.NewCollections(db, bstorage.NewTransactions(metrics.NewNoopCollector(), db)), 100)
+
+		pruner := NewPruner(
+			zerolog.Nop(),
+			db,
+			state,
+			bstorage.NewHeaders(metrics.NewNoopCollector(), db),
+			execState,
+			bstorage.NewExecutionResults(metrics.NewNoopCollector(), db),
+			events,
+			bstorage.NewServiceEvents(metrics.NewNoopCollector(), db),
+			txResults,
+			chunkDataPacks,
+			metrics.NewNoopCollector(),
+			3,
+			2,
+			time.Hour,
+		)
+
+		prunedHeight := func() uint64 {
+			var height uint64
+			require.NoError(t, db.View(operation.RetrieveExecutionDataPrunedHeight(&height)))
+			return height
+		}
+
+		require.NoError(t, pruner.Bootstrap())
+		assert.Equal(t, root.Height, prunedHeight())
+
+		// the execution data of pruned blocks is not served from the caches anymore
+		cachedTxID := results[1].ID()
+		err := db.Update(operation.InsertTransactionResult(headers[1].ID(), &flow.TransactionResult{TransactionID: cachedTxID}))
+		require.NoError(t, err)
+		cachedEvents, err := events.ByBlockID(headers[1].ID())
+		require.NoError(t, err)
+		require.Len(t, cachedEvents, 1)
+		_, err = txResults.ByBlockIDTransactionID(headers[1].ID(), cachedTxID)
+		require.NoError(t, err)
+
+		// blocks up to height 5 can be pruned, two blocks per run
+		require.NoError(t, pruner.Prune())
+		assert.Equal(t, uint64(2), prunedHeight())
+		require.NoError(t, pruner.Prune())
+		require.NoError(t, pruner.Prune())
+		assert.Equal(t, uint64(5), prunedHeight())
+		require.NoError(t, pruner.Prune())
+		assert.Equal(t, uint64(5), prunedHeight())
+
+		for i, header := range headers {
+			stored := i == 0 || i > 5
+			assertExecutionData(t, db, header, results[i], stored)
+		}
+		cachedEvents, err = events.ByBlockID(headers[1].ID())
+		require.NoError(t, err)
+		assert.Empty(t, cachedEvents)
+		_, err = txResults.ByBlockIDTransactionID(headers[1].ID(), cachedTxID)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+
+		// pruning is limited by the highest executed block
+		sealed = 9
+		executed = 7
+		require.NoError(t, pruner.Prune())
+		assert.Equal(t, uint64(5), prunedHeight())
+		assertExecutionData(t, db, headers[6], results[6], true)
+
+		// the pruned height is not reset on restart
+		require.NoError(t, pruner.Bootstrap())
+		assert.Equal(t, uint64(5), prunedHeight())
+	})
+}
+
+func TestPruner_OrphanedForks(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		insert := func(header *flow.Header) {
+			err := db.Update(func(tx *badger.Txn) error {
+				err := operation.InsertHeader(header.ID(), header)(tx)
+				require.NoError(t, err)
+				return procedure.IndexNewBlock(header.ID(), header.ParentID)(tx)
+			})
+			require.NoError(t, err)
+		}
+
+		root := unittest.BlockHeaderFixture()
+		root.Height = 0
+		insert(&root)
+		finalized := []*flow.Header{&root}
+		for i := 1; i < 6; i++ {
+			header := unittest.BlockHeaderWithParentFixture(finalized[i-1])
+			insert(&header)
+			finalized = append(finalized, &header)
+		}
+
+		// a fork of two executed blocks branches off the block at height 1, and
+		// extends above the prunable height
+		fork1 := unittest.BlockHeaderWithParentFixture(finalized[1])
+		insert(&fork1)
+		fork2 := unittest.BlockHeaderWithParentFixture(&fork1)
+		fork2.Height = 4
+		insert(&fork2)
+		// another fork branches off the block at height 3
+		fork3 := unittest.BlockHeaderWithParentFixture(finalized[3])
+		insert(&fork3)
+
+		forks := []*flow.Header{&fork1, &fork2, &fork3}
+		forkResults := make([]*flow.ExecutionResult, 0, len(forks))
+		for _, header := range forks {
+			forkResults = append(forkResults, storeExecutionData(t, db, header))
+		}
+
+		params := new(protocolmock.Params)
+		params.On("Root").Return(&root, nil)
+		state := new(protocolmock.State)
+		state.On("Params").Return(params)
+		state.On("Sealed").Return(func() protocol.Snapshot {
+			snapshot := new(protocolmock.Snapshot)
+			snapshot.On("Head").Return(finalized[5], nil)
+			return snapshot
+		})
+		state.On("AtHeight", mock.Anything).Return(func(height uint64) protocol.Snapshot {
+			snapshot := new(protocolmock.Snapshot)
+			snapshot.On("Head").Return(finalized[height], nil)
+			return snapshot
+		})
+		execState := new(statemock.ReadOnlyExecutionState)
+		execState.On("GetHighestExecutedBlockID", mock.Anything).Return(
+			func(_ context.Context) uint64 { return 5 },
+			func(_ context.Context) flow.Identifier { return finalized[5].ID() },
+			nil,
+		)
+
+		events := bstorage.NewEvents(metrics.NewNoopCollector(), db)
+		txResults := bstorage.NewTransactionResults(metrics.NewNoopCollector(), db, 100)
+		chunkDataPacks := bstorage.NewChunkDataPacks(metrics.NewNoopCollector(), db, bstorage.NewCollections(db, bstorage.NewTransactions(metrics.NewNoopCollector(), db)), 100)
+
+		pruner := NewPruner(
+			zerolog.Nop(),
+			db,
+			state,
+			bstorage.NewHeaders(metrics.NewNoopCollector(), db),
+			execState,
+			bstorage.NewExecutionResults(metrics.NewNoopCollector(), db),
+			events,
+			bstorage.NewServiceEvents(metrics.NewNoopCollector(), db),
+			txResults,
+			chunkDataPacks,
+			metrics.NewNoopCollector(),
+			3,
+			1,
+			time.Hour,
+		)
+		require.NoError(t, pruner.Bootstrap())
+
+		// pruning the finalized block at height 1 leaves the forks untouched
+		require.NoError(t, pruner.Prune())
+		for i, header := range forks {
+			assertExecutionData(t, db, header, forkResults[i], true)
+		}
+
+		// pruning the finalized block at height 2 prunes the whole first fork
+		require.NoError(t, pruner.Prune())
+		assertExecutionData(t, db, &fork1, forkResults[0], false)
+		assertExecutionData(t, db, &fork2, forkResults[1], false)
+		assertExecutionData(t, db, &fork3, forkResults[2], true)
+
+		// the second fork branches off above the prunable height, so it is kept
+		require.NoError(t, pruner.Prune())
+		assertExecutionData(t, db, &fork3, forkResults[2], true)
+	})
+}
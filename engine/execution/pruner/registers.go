@@ -0,0 +1,160 @@
+package pruner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/pruner"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// DefaultRegisterHistoryRetention is the default number of blocks below the
+// latest sealed and executed block which the register history covers.
+const DefaultRegisterHistoryRetention = 100_000
+
+// RegisterHistoryPruner moves the start of the register history up, such that
+// it covers a given number of blocks below the latest sealed and executed block.
+// Register values which are overwritten at or below the new start height are
+// deleted, while the values still current at the start height are kept.
+//
+// The start height is moved up before the values are deleted, so that the
+// history never serves values which are about to be deleted. A crash while
+// deleting leaves the values of the height, which are deleted again on restart.
+type RegisterHistoryPruner struct {
+	*pruner.Loop
+	db        *badger.DB
+	state     protocol.State
+	execState state.ReadOnlyExecutionState
+	retention uint64 // number of blocks below the sealed height to keep
+}
+
+func NewRegisterHistoryPruner(
+	logger zerolog.Logger,
+	db *badger.DB,
+	state protocol.State,
+	execState state.ReadOnlyExecutionState,
+	retention uint64,
+	batchSize uint,
+	interval time.Duration,
+) *RegisterHistoryPruner {
+	p := &RegisterHistoryPruner{
+		db:        db,
+		state:     state,
+		execState: execState,
+		retention: retention,
+	}
+	log := logger.With().Str("component", "register_history_pruner").Logger()
+	p.Loop = pruner.NewLoop(log, state, p, batchSize, interval)
+	return p
+}
+
+// PrunedHeight returns the height up to which the register history is pruned.
+// No changes are indexed up to the height the history was started at, so the
+// history is considered pruned up to there.
+func (p *RegisterHistoryPruner) PrunedHeight() (uint64, error) {
+	var pruned uint64
+	err := p.db.View(operation.RetrieveRegisterHistoryPrunedHeight(&pruned))
+	if err != nil {
+		return 0, err
+	}
+
+	var start uint64
+	err = p.db.View(operation.RetrieveRegisterHistoryHeight(&start))
+	if errors.Is(err, storage.ErrNotFound) {
+		return pruned, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve register history height: %w", err)
+	}
+	if start > pruned {
+		return start, nil
+	}
+
+	return pruned, nil
+}
+
+// InitPrunedHeight initializes the height up to which the register history is pruned.
+func (p *RegisterHistoryPruner) InitPrunedHeight(height uint64) error {
+	return operation.RetryOnConflict(p.db.Update, operation.InsertRegisterHistoryPrunedHeight(height))
+}
+
+// PruneLimit returns the retention below the latest block that is both sealed
+// and executed. Nothing is pruned before the register history is started.
+func (p *RegisterHistoryPruner) PruneLimit() (uint64, error) {
+	var start uint64
+	err := p.db.View(operation.RetrieveRegisterHistoryHeight(&start))
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not retrieve register history height: %w", err)
+	}
+
+	sealed, err := p.state.Sealed().Head()
+	if err != nil {
+		return 0, fmt.Errorf("could not get sealed block: %w", err)
+	}
+
+	executed, _, err := p.execState.GetHighestExecutedBlockID(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("could not get highest executed block: %w", err)
+	}
+
+	limit := sealed.Height
+	if executed < limit {
+		limit = executed
+	}
+	if limit <= p.retention {
+		return 0, nil
+	}
+
+	return limit - p.retention, nil
+}
+
+// PruneHeight starts the register history at the height of the given finalized
+// block, and deletes the register values which are obsolete from then on.
+func (p *RegisterHistoryPruner) PruneHeight(header *flow.Header) error {
+	err := operation.RetryOnConflict(p.db.Update, func(tx *badger.Txn) error {
+		var start uint64
+		err := operation.RetrieveRegisterHistoryHeight(&start)(tx)
+		if err != nil {
+			return fmt.Errorf("could not retrieve register history height: %w", err)
+		}
+		// the history may have been started above blocks which are not pruned yet
+		if start >= header.Height {
+			return nil
+		}
+		return operation.UpdateRegisterHistoryHeight(header.Height)(tx)
+	})
+	if err != nil {
+		return fmt.Errorf("could not update register history height: %w", err)
+	}
+
+	batch := p.db.NewWriteBatch()
+	defer batch.Cancel()
+
+	err = p.db.View(operation.BatchPruneRegisterHistory(header.Height, header.ID(), batch))
+	if err != nil {
+		return fmt.Errorf("could not prune register history: %w", err)
+	}
+	err = batch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not flush pruned register history: %w", err)
+	}
+
+	err = operation.RetryOnConflict(p.db.Update, operation.UpdateRegisterHistoryPrunedHeight(header.Height))
+	if err != nil {
+		return fmt.Errorf("could not update pruned height: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package pruner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	statemock "github.com/onflow/flow-go/engine/execution/state/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	protocolmock "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestRegisterHistoryPruner(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		root := unittest.BlockHeaderFixture()
+		root.Height = 0
+		headers := []*flow.Header{&root}
+		for i := 1; i < 10; i++ {
+			header := unittest.BlockHeaderWithParentFixture(headers[i-1])
+			headers = append(headers, &header)
+			require.NoError(t, db.Update(operation.IndexBlockHeight(header.Height, header.ID())))
+		}
+
+		// the history starts at height 2, and the register is written at heights 2, 4 and 7
+		registerID := flow.NewRegisterID("owner", "controller", "key")
+		registers := bstorage.NewRegisters(db)
+		for _, height := range []uint64{2, 4, 7} {
+			batch := bstorage.NewBatch(db)
+			entries := flow.RegisterEntries{{Key: registerID, Value: []byte{byte(height)}}}
+			require.NoError(t, registers.BatchStore(headers[height], entries, batch))
+			require.NoError(t, batch.Flush())
+		}
+		require.NoError(t, db.Update(operation.InsertRegisterHistoryHeight(2)))
+
+		params := new(protocolmock.Params)
+		params.On("Root").Return(&root, nil)
+		state := new(protocolmock.State)
+		state.On("Params").Return(params)
+		state.On("Sealed").Return(func() protocol.Snapshot {
+			snapshot := new(protocolmock.Snapshot)
+			snapshot.On("Head").Return(headers[8], nil)
+			return snapshot
+		})
+		state.On("AtHeight", mock.Anything).Return(func(height uint64) protocol.Snapshot {
+			snapshot := new(protocolmock.Snapshot)
+			snapshot.On("Head").Return(headers[height], nil)
+			return snapshot
+		})
+		execState := new(statemock.ReadOnlyExecutionState)
+		execState.On("GetHighestExecutedBlockID", mock.Anything).Return(
+			func(_ context.Context) uint64 { return 9 },
+			func(_ context.Context) flow.Identifier { return headers[9].ID() },
+			nil,
+		)
+
+		pruner := NewRegisterHistoryPruner(zerolog.Nop(), db, state, execState, 3, 10, time.Hour)
+		require.NoError(t, pruner.Bootstrap())
+
+		// the history is pruned up to height 5, where the value written at
+		// height 4 is still current
+		require.NoError(t, pruner.Prune())
+		pruned, err := pruner.PrunedHeight()
+		require.NoError(t, err)
+		assert.Equal(t, uint64(5), pruned)
+
+		var start uint64
+		require.NoError(t, db.View(operation.RetrieveRegisterHistoryHeight(&start)))
+		assert.Equal(t, uint64(5), start)
+
+		value, err := registers.ByIDAtHeight(registerID, 5)
+		require.NoError(t, err)
+		assert.Equal(t, flow.RegisterValue{4}, value)
+		// the value written at height 2 is deleted
+		_, err = registers.ByIDAtHeight(registerID, 3)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
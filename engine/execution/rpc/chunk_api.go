@@ -0,0 +1,148 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+)
+
+// The chunk API of execution nodes is not part of the upstream protobuf
+// definitions, so the messages and the service descriptor below are declared
+// by hand. They follow the layout produced by protoc-gen-go and
+// protoc-gen-go-grpc, and are wire compatible with the following definition:
+//
+//	service ExecutionChunkAPI {
+//	  rpc GetVerifiableChunk(GetVerifiableChunkRequest)
+//	      returns (GetVerifiableChunkResponse);
+//	}
+//
+//	message GetVerifiableChunkRequest {
+//	  bytes block_id = 1;
+//	  bytes result_id = 2;
+//	  uint64 chunk_index = 3;
+//	}
+//
+//	message GetVerifiableChunkResponse {
+//	  bytes verifiable_chunk = 1;
+//	}
+
+// GetVerifiableChunkRequest is the request for the chunk with the given index of
+// an execution result for a block. If ResultId is empty, the execution result
+// the execution node has computed for the block is used.
+type GetVerifiableChunkRequest struct {
+	BlockId    []byte `protobuf:"bytes,1,opt,name=block_id,json=blockId,proto3" json:"block_id,omitempty"`
+	ResultId   []byte `protobuf:"bytes,2,opt,name=result_id,json=resultId,proto3" json:"result_id,omitempty"`
+	ChunkIndex uint64 `protobuf:"varint,3,opt,name=chunk_index,json=chunkIndex,proto3" json:"chunk_index,omitempty"`
+}
+
+func (m *GetVerifiableChunkRequest) Reset()         { *m = GetVerifiableChunkRequest{} }
+func (m *GetVerifiableChunkRequest) String() string { return proto.CompactTextString(m) }
+func (*GetVerifiableChunkRequest) ProtoMessage()    {}
+
+func (m *GetVerifiableChunkRequest) GetBlockId() []byte {
+	if m != nil {
+		return m.BlockId
+	}
+	return nil
+}
+
+func (m *GetVerifiableChunkRequest) GetResultId() []byte {
+	if m != nil {
+		return m.ResultId
+	}
+	return nil
+}
+
+func (m *GetVerifiableChunkRequest) GetChunkIndex() uint64 {
+	if m != nil {
+		return m.ChunkIndex
+	}
+	return 0
+}
+
+// GetVerifiableChunkResponse holds the requested chunk, together with the data
+// verification nodes need to verify it, as JSON encoded VerifiableChunkData.
+type GetVerifiableChunkResponse struct {
+	VerifiableChunk []byte `protobuf:"bytes,1,opt,name=verifiable_chunk,json=verifiableChunk,proto3" json:"verifiable_chunk,omitempty"`
+}
+
+func (m *GetVerifiableChunkResponse) Reset()         { *m = GetVerifiableChunkResponse{} }
+func (m *GetVerifiableChunkResponse) String() string { return proto.CompactTextString(m) }
+func (*GetVerifiableChunkResponse) ProtoMessage()    {}
+
+func (m *GetVerifiableChunkResponse) GetVerifiableChunk() []byte {
+	if m != nil {
+		return m.VerifiableChunk
+	}
+	return nil
+}
+
+// ExecutionChunkAPIClient is the client API for ExecutionChunkAPI service.
+type ExecutionChunkAPIClient interface {
+	// GetVerifiableChunk gets a chunk of an execution result, together with the
+	// chunk data pack and everything else needed to verify the chunk.
+	GetVerifiableChunk(ctx context.Context, in *GetVerifiableChunkRequest, opts ...grpc.CallOption) (*GetVerifiableChunkResponse, error)
+}
+
+type executionChunkAPIClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewExecutionChunkAPIClient(cc grpc.ClientConnInterface) ExecutionChunkAPIClient {
+	return &executionChunkAPIClient{cc}
+}
+
+func (c *executionChunkAPIClient) GetVerifiableChunk(ctx context.Context, in *GetVerifiableChunkRequest, opts ...grpc.CallOption) (*GetVerifiableChunkResponse, error) {
+	out := new(GetVerifiableChunkResponse)
+	err := c.cc.Invoke(ctx, "/flow.execution.ExecutionChunkAPI/GetVerifiableChunk", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExecutionChunkAPIServer is the server API for ExecutionChunkAPI service.
+type ExecutionChunkAPIServer interface {
+	// GetVerifiableChunk gets a chunk of an execution result, together with the
+	// chunk data pack and everything else needed to verify the chunk.
+	GetVerifiableChunk(context.Context, *GetVerifiableChunkRequest) (*GetVerifiableChunkResponse, error)
+}
+
+func RegisterExecutionChunkAPIServer(s grpc.ServiceRegistrar, srv ExecutionChunkAPIServer) {
+	s.RegisterService(&ExecutionChunkAPI_ServiceDesc, srv)
+}
+
+func _ExecutionChunkAPI_GetVerifiableChunk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVerifiableChunkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ExecutionChunkAPIServer).GetVerifiableChunk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/flow.execution.ExecutionChunkAPI/GetVerifiableChunk",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ExecutionChunkAPIServer).GetVerifiableChunk(ctx, req.(*GetVerifiableChunkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ExecutionChunkAPI_ServiceDesc is the grpc.ServiceDesc for ExecutionChunkAPI service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ExecutionChunkAPI_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "flow.execution.ExecutionChunkAPI",
+	HandlerType: (*ExecutionChunkAPIServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetVerifiableChunk",
+			Handler:    _ExecutionChunkAPI_GetVerifiableChunk_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "flow/execution/execution_chunk.proto",
+}
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -47,9 +48,11 @@ func New(
 	config Config,
 	e *ingestion.Engine,
 	blocks storage.Blocks,
+	headers storage.Headers,
 	events storage.Events,
 	exeResults storage.ExecutionResults,
 	txResults storage.TransactionResults,
+	chunkDataPacks storage.ChunkDataPacks,
 	chainID flow.ChainID) *Engine {
 	log = log.With().Str("engine", "rpc").Logger()
 
@@ -76,9 +79,11 @@ func New(
 			engine:             e,
 			chain:              chainID,
 			blocks:             blocks,
+			headers:            headers,
 			events:             events,
 			exeResults:         exeResults,
 			transactionResults: txResults,
+			chunkDataPacks:     chunkDataPacks,
 			log:                log,
 		},
 		server: server,
@@ -91,6 +96,7 @@ func New(
 	}
 
 	execution.RegisterExecutionAPIServer(eng.server, eng.handler)
+	RegisterExecutionChunkAPIServer(eng.server, eng.handler)
 
 	return eng
 }
@@ -132,13 +138,16 @@ type handler struct {
 	engine             ingestion.IngestRPC
 	chain              flow.ChainID
 	blocks             storage.Blocks
+	headers            storage.Headers
 	events             storage.Events
 	exeResults         storage.ExecutionResults
 	transactionResults storage.TransactionResults
+	chunkDataPacks     storage.ChunkDataPacks
 	log                zerolog.Logger
 }
 
 var _ execution.ExecutionAPIServer = &handler{}
+var _ ExecutionChunkAPIServer = &handler{}
 
 // Ping responds to requests when the server is up.
 func (h *handler) Ping(ctx context.Context, req *execution.PingRequest) (*execution.PingResponse, error) {
@@ -332,3 +341,39 @@ func (h *handler) GetAccountAtBlockID(
 	return res, nil
 
 }
+
+// GetVerifiableChunk returns a chunk of an execution result together with its chunk data pack,
+// so that it can be verified outside of a verification node, e.g. with the verify-chunk util.
+func (h *handler) GetVerifiableChunk(
+	_ context.Context,
+	req *GetVerifiableChunkRequest,
+) (*GetVerifiableChunkResponse, error) {
+
+	blockID, err := convert.BlockID(req.GetBlockId())
+	if err != nil {
+		return nil, err
+	}
+
+	// without a result ID, the execution result of this node is used
+	resultID := flow.ZeroID
+	if len(req.GetResultId()) > 0 {
+		resultID = flow.HashToID(req.GetResultId())
+	}
+
+	vc, err := LoadVerifiableChunk(h.headers, h.exeResults, h.chunkDataPacks, blockID, resultID, req.GetChunkIndex())
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, status.Errorf(codes.NotFound, "could not find chunk: %v", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not load chunk: %v", err)
+	}
+
+	data, err := json.Marshal(vc)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not encode chunk: %v", err)
+	}
+
+	return &GetVerifiableChunkResponse{
+		VerifiableChunk: data,
+	}, nil
+}
@@ -2,12 +2,15 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net"
 	"testing"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -17,6 +20,7 @@ import (
 	"github.com/onflow/flow-go/engine/common/rpc/convert"
 	ingestion "github.com/onflow/flow-go/engine/execution/ingestion/mock"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/verification"
 	realstorage "github.com/onflow/flow-go/storage"
 	storage "github.com/onflow/flow-go/storage/mock"
 	"github.com/onflow/flow-go/utils/unittest"
@@ -424,3 +428,75 @@ func (suite *Suite) TestGetTransactionResult() {
 		suite.events.AssertExpectations(suite.T())
 	})
 }
+
+// TestGetVerifiableChunk tests the GetVerifiableChunk API call, served over gRPC
+func (suite *Suite) TestGetVerifiableChunk() {
+
+	// an execution result with two chunks, the last one being the system chunk
+	header := unittest.BlockHeaderFixture()
+	blockID := header.ID()
+	result := unittest.ExecutionResultFixture()
+	result.BlockID = blockID
+	result.Chunks = flow.ChunkList{
+		{ChunkBody: flow.ChunkBody{StartState: unittest.StateCommitmentFixture(), NumberOfTransactions: 3}, Index: 0, EndState: unittest.StateCommitmentFixture()},
+		{ChunkBody: flow.ChunkBody{StartState: unittest.StateCommitmentFixture(), NumberOfTransactions: 1}, Index: 1, EndState: unittest.StateCommitmentFixture()},
+	}
+	chunkDataPack := unittest.ChunkDataPackFixture(result.Chunks[1].ID())
+
+	headers := new(storage.Headers)
+	headers.On("ByBlockID", blockID).Return(&header, nil)
+	headers.On("ByBlockID", mock.Anything).Return(nil, realstorage.ErrNotFound)
+	suite.exeResults.On("ByBlockID", blockID).Return(result, nil)
+	chunkDataPacks := new(storage.ChunkDataPacks)
+	chunkDataPacks.On("ByChunkID", result.Chunks[1].ID()).Return(chunkDataPack, nil)
+
+	server := grpc.NewServer()
+	RegisterExecutionChunkAPIServer(server, &handler{
+		headers:        headers,
+		exeResults:     suite.exeResults,
+		chunkDataPacks: chunkDataPacks,
+	})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	conn, err := grpc.Dial(listener.Addr().String(), grpc.WithInsecure())
+	suite.Require().NoError(err)
+	defer conn.Close()
+	client := NewExecutionChunkAPIClient(conn)
+
+	suite.Run("happy path with the result of the execution node", func() {
+		resp, err := client.GetVerifiableChunk(context.Background(), &GetVerifiableChunkRequest{
+			BlockId:    blockID[:],
+			ChunkIndex: 1,
+		})
+		suite.Require().NoError(err)
+
+		var vc verification.VerifiableChunkData
+		err = json.Unmarshal(resp.GetVerifiableChunk(), &vc)
+		suite.Require().NoError(err)
+		suite.Require().True(vc.IsSystemChunk)
+		suite.Require().Equal(blockID, vc.Header.ID())
+		suite.Require().Equal(result.ID(), vc.Result.ID())
+		suite.Require().Equal(result.Chunks[1].ID(), vc.Chunk.ID())
+		suite.Require().Equal(chunkDataPack.ID(), vc.ChunkDataPack.ID())
+		suite.Require().Equal(result.Chunks[1].EndState, vc.EndState)
+		suite.Require().Equal(uint32(3), vc.TransactionOffset)
+	})
+
+	suite.Run("unknown block", func() {
+		unknownID := unittest.IdentifierFixture()
+		_, err := client.GetVerifiableChunk(context.Background(), &GetVerifiableChunkRequest{
+			BlockId: unknownID[:],
+		})
+		suite.Require().Equal(codes.NotFound, status.Code(err))
+	})
+
+	suite.Run("invalid request with nil block id", func() {
+		_, err := client.GetVerifiableChunk(context.Background(), &GetVerifiableChunkRequest{})
+		suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+	})
+}
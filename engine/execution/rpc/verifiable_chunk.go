@@ -0,0 +1,73 @@
+package rpc
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/verification"
+	"github.com/onflow/flow-go/storage"
+)
+
+// LoadVerifiableChunk reads the chunk with the given index of an execution
+// result for the block from storage, and turns it into a verifiable chunk the
+// same way the fetcher engine of a verification node does. If resultID is the
+// zero ID, the execution result stored for the block is used, which is the
+// one of the execution node the storage belongs to.
+func LoadVerifiableChunk(
+	headers storage.Headers,
+	results storage.ExecutionResults,
+	chunkDataPacks storage.ChunkDataPacks,
+	blockID flow.Identifier,
+	resultID flow.Identifier,
+	chunkIndex uint64,
+) (*verification.VerifiableChunkData, error) {
+	header, err := headers.ByBlockID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get block header: %w", err)
+	}
+
+	var result *flow.ExecutionResult
+	if resultID == flow.ZeroID {
+		result, err = results.ByBlockID(blockID)
+	} else {
+		result, err = results.ByID(resultID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get execution result: %w", err)
+	}
+	if result.BlockID != blockID {
+		return nil, fmt.Errorf("execution result (id: %x) is for a different block (id: %x)", result.ID(), result.BlockID)
+	}
+
+	chunk, ok := result.Chunks.ByIndex(chunkIndex)
+	if !ok {
+		return nil, fmt.Errorf("chunk index %d out of range, execution result has %d chunks", chunkIndex, len(result.Chunks))
+	}
+
+	chunkDataPack, err := chunkDataPacks.ByChunkID(chunk.ID())
+	if err != nil {
+		return nil, fmt.Errorf("could not get chunk data pack (chunk id: %x): %w", chunk.ID(), err)
+	}
+
+	isSystemChunk := verification.IsSystemChunk(chunkIndex, result)
+
+	endState, err := verification.EndStateCommitment(result, chunkIndex, isSystemChunk)
+	if err != nil {
+		return nil, fmt.Errorf("could not compute end state of chunk: %w", err)
+	}
+
+	transactionOffset, err := verification.TransactionOffsetForChunk(result.Chunks, chunkIndex)
+	if err != nil {
+		return nil, fmt.Errorf("could not compute transaction offset for chunk: %w", err)
+	}
+
+	return &verification.VerifiableChunkData{
+		IsSystemChunk:     isSystemChunk,
+		Chunk:             chunk,
+		Header:            header,
+		Result:            result,
+		ChunkDataPack:     chunkDataPack,
+		EndState:          endState,
+		TransactionOffset: transactionOffset,
+	}, nil
+}
@@ -1,6 +1,7 @@
 package bootstrap
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 
@@ -12,6 +13,9 @@ import (
 	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/fvm/programs"
 	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/flattener"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
@@ -119,3 +123,189 @@ func (b *Bootstrapper) BootstrapExecutionDatabase(db *badger.DB, commit flow.Sta
 
 	return nil
 }
+
+// BootstrapExecutionDatabaseAtSealed bootstraps the execution database at the given sealed
+// block, whose execution state has been synced from other execution nodes instead of being
+// loaded from the root checkpoint. Besides the state commitment of the block, its sealed
+// result is stored, as it is the previous result of the receipts for the children of the block.
+func (b *Bootstrapper) BootstrapExecutionDatabaseAtSealed(db *badger.DB, result *flow.ExecutionResult, sealed *flow.Header) error {
+	if result.BlockID != sealed.ID() {
+		return fmt.Errorf("sealed result is for block %x, not for sealed block %x", result.BlockID, sealed.ID())
+	}
+
+	commit, err := result.FinalStateCommitment()
+	if err != nil {
+		return fmt.Errorf("could not get final state of sealed result: %w", err)
+	}
+
+	err = operation.RetryOnConflict(db.Update, func(txn *badger.Txn) error {
+		// the result of the sealed root block has been stored when bootstrapping the protocol state
+		err := operation.SkipDuplicates(operation.InsertExecutionResult(result))(txn)
+		if err != nil {
+			return fmt.Errorf("could not insert sealed result: %w", err)
+		}
+		err = operation.SkipDuplicates(operation.IndexExecutionResult(result.BlockID, result.ID()))(txn)
+		if err != nil {
+			return fmt.Errorf("could not index sealed result: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return b.BootstrapExecutionDatabase(db, commit, sealed)
+}
+
+// IsRegisterHistoryStarted returns whether the register history has been
+// started, if yes, returns the height it starts at.
+func (b *Bootstrapper) IsRegisterHistoryStarted(db *badger.DB) (uint64, bool, error) {
+	var height uint64
+	err := db.View(operation.RetrieveRegisterHistoryHeight(&height))
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("could not retrieve register history height: %w", err)
+	}
+	return height, true, nil
+}
+
+// BootstrapRegisterHistory starts the register history at the given executed and
+// finalized block, by indexing the values of all registers in the state of the
+// block. Registers written by later blocks are indexed as the blocks are executed.
+func (b *Bootstrapper) BootstrapRegisterHistory(db *badger.DB, header *flow.Header, stateTrie *trie.MTrie) error {
+	blockID := header.ID()
+	batch := db.NewWriteBatch()
+	defer batch.Cancel()
+
+	registers := 0
+	for itr := flattener.NewNodeIterator(stateTrie); itr.Next(); {
+		n := itr.Value()
+		if !n.IsLeaf() || n.Payload() == nil || n.Payload().IsEmpty() {
+			continue
+		}
+		payload := n.Payload()
+		registerID, err := state.KeyToRegisterID(payload.Key)
+		if err != nil {
+			return fmt.Errorf("could not convert register key: %w", err)
+		}
+		err = operation.BatchIndexRegister(registerID, header.Height, blockID, flow.RegisterValue(payload.Value))(batch)
+		if err != nil {
+			return fmt.Errorf("could not index register %s: %w", registerID.String(), err)
+		}
+		registers++
+	}
+
+	err := batch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not flush register snapshot: %w", err)
+	}
+
+	// the history height is inserted last, such that an interrupted snapshot is
+	// indexed again on the next start
+	err = operation.RetryOnConflict(db.Update, operation.InsertRegisterHistoryHeight(header.Height))
+	if err != nil {
+		return fmt.Errorf("could not insert register history height: %w", err)
+	}
+
+	b.logger.Info().
+		Hex("block_id", blockID[:]).
+		Uint64("height", header.Height).
+		Int("registers", registers).
+		Msg("register history started")
+
+	return nil
+}
+
+// IndexRegisterChanges indexes the values of the registers written by a block
+// which was executed before the register history was started. As the register
+// updates of the block are not stored, they are recovered by comparing the state
+// of the block with the state of its parent. Registers removed by the block are
+// indexed with an empty value.
+func (b *Bootstrapper) IndexRegisterChanges(db *badger.DB, header *flow.Header, parentTrie *trie.MTrie, stateTrie *trie.MTrie) error {
+	changes := make(map[flow.RegisterID]flow.RegisterValue)
+	err := diffRegisters(parentTrie.RootNode(), stateTrie.RootNode(), changes)
+	if err != nil {
+		return fmt.Errorf("could not compare states: %w", err)
+	}
+
+	blockID := header.ID()
+	batch := db.NewWriteBatch()
+	defer batch.Cancel()
+
+	for registerID, value := range changes {
+		err = operation.BatchIndexRegister(registerID, header.Height, blockID, value)(batch)
+		if err != nil {
+			return fmt.Errorf("could not index register %s: %w", registerID.String(), err)
+		}
+		err = operation.BatchIndexRegisterChange(registerID, header.Height, blockID)(batch)
+		if err != nil {
+			return fmt.Errorf("could not index change of register %s: %w", registerID.String(), err)
+		}
+	}
+
+	err = batch.Flush()
+	if err != nil {
+		return fmt.Errorf("could not flush register changes: %w", err)
+	}
+
+	b.logger.Info().
+		Hex("block_id", blockID[:]).
+		Uint64("height", header.Height).
+		Int("registers", len(changes)).
+		Msg("register changes of executed block indexed")
+
+	return nil
+}
+
+// diffRegisters adds the registers whose values differ between the subtries of
+// two states to the given changes. Subtries with equal hashes are skipped, so
+// only the paths to changed registers are traversed.
+func diffRegisters(before *node.Node, after *node.Node, changes map[flow.RegisterID]flow.RegisterValue) error {
+	if before == nil && after == nil {
+		return nil
+	}
+	if before != nil && after != nil && before.Hash() == after.Hash() {
+		return nil
+	}
+
+	// compact leaves don't line up with the subtries of the other state, so
+	// the registers of both subtries are compared directly
+	if before == nil || after == nil || before.IsLeaf() || after.IsLeaf() {
+		values := make(map[flow.RegisterID]flow.RegisterValue)
+		if before != nil {
+			for _, payload := range before.AllPayloads() {
+				registerID, err := state.KeyToRegisterID(payload.Key)
+				if err != nil {
+					return fmt.Errorf("could not convert register key: %w", err)
+				}
+				values[registerID] = flow.RegisterValue(payload.Value)
+			}
+		}
+		if after != nil {
+			for _, payload := range after.AllPayloads() {
+				registerID, err := state.KeyToRegisterID(payload.Key)
+				if err != nil {
+					return fmt.Errorf("could not convert register key: %w", err)
+				}
+				value, ok := values[registerID]
+				delete(values, registerID)
+				if ok && bytes.Equal(value, payload.Value) {
+					continue
+				}
+				changes[registerID] = flow.RegisterValue(payload.Value)
+			}
+		}
+		for registerID := range values {
+			changes[registerID] = flow.RegisterValue{}
+		}
+		return nil
+	}
+
+	err := diffRegisters(before.LeftChild(), after.LeftChild(), changes)
+	if err != nil {
+		return err
+	}
+	return diffRegisters(before.RightChild(), after.RightChild(), changes)
+}
@@ -4,15 +4,22 @@ import (
 	"encoding/hex"
 	"testing"
 
+	"github.com/dgraph-io/badger/v2"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
 	completeLedger "github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
 	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -76,3 +83,51 @@ func TestBootstrapLedger_ZeroTokenSupply(t *testing.T) {
 		}
 	})
 }
+
+func TestIndexRegisterChanges(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		update := func(parent *trie.MTrie, values map[flow.RegisterID]string) *trie.MTrie {
+			var paths []ledger.Path
+			var payloads []ledger.Payload
+			for registerID, value := range values {
+				key := state.RegisterIDToKey(registerID)
+				path, err := pathfinder.KeyToPath(key, completeLedger.DefaultPathFinderVersion)
+				require.NoError(t, err)
+				paths = append(paths, path)
+				payloads = append(payloads, *ledger.NewPayload(key, ledger.Value(value)))
+			}
+			updated, err := trie.NewTrieWithUpdatedRegisters(parent, paths, payloads)
+			require.NoError(t, err)
+			return updated
+		}
+
+		unchanged := flow.NewRegisterID("owner", "controller", "unchanged")
+		changed := flow.NewRegisterID("owner", "controller", "changed")
+		added := flow.NewRegisterID("owner", "controller", "added")
+		parentTrie := update(trie.NewEmptyMTrie(), map[flow.RegisterID]string{unchanged: "a", changed: "b"})
+		stateTrie := update(parentTrie, map[flow.RegisterID]string{changed: "c", added: "d"})
+
+		header := unittest.BlockHeaderFixture()
+		require.NoError(t, db.Update(operation.IndexBlockHeight(header.Height, header.ID())))
+
+		bootstrapper := NewBootstrapper(zerolog.Nop())
+		err := bootstrapper.IndexRegisterChanges(db, &header, parentTrie, stateTrie)
+		require.NoError(t, err)
+
+		lookup := func(registerID flow.RegisterID) (string, error) {
+			var value flow.RegisterValue
+			err := db.View(operation.LookupRegisterAtHeight(registerID, header.Height, &value))
+			return string(value), err
+		}
+
+		// only the registers written by the block are indexed
+		_, err = lookup(unchanged)
+		assert.ErrorIs(t, err, storage.ErrNotFound)
+		value, err := lookup(changed)
+		require.NoError(t, err)
+		assert.Equal(t, "c", value)
+		value, err = lookup(added)
+		require.NoError(t, err)
+		assert.Equal(t, "d", value)
+	})
+}
@@ -8,6 +8,8 @@ import (
 	delta "github.com/onflow/flow-go/engine/execution/state/delta"
 	flow "github.com/onflow/flow-go/model/flow"
 
+	ledger "github.com/onflow/flow-go/ledger"
+
 	messages "github.com/onflow/flow-go/model/messages"
 
 	mock "github.com/stretchr/testify/mock"
@@ -163,6 +165,29 @@ func (_m *ExecutionState) GetProof(_a0 context.Context, _a1 flow.StateCommitment
 	return r0, r1
 }
 
+// GetRegisterAtHeight provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ExecutionState) GetRegisterAtHeight(_a0 context.Context, _a1 flow.RegisterID, _a2 uint64) ([]byte, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(context.Context, flow.RegisterID, uint64) []byte); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, flow.RegisterID, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRegisters provides a mock function with given fields: _a0, _a1, _a2
 func (_m *ExecutionState) GetRegisters(_a0 context.Context, _a1 flow.StateCommitment, _a2 []flow.RegisterID) ([][]byte, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -202,13 +227,36 @@ func (_m *ExecutionState) NewView(_a0 flow.StateCommitment) *delta.View {
 	return r0
 }
 
-// PersistExecutionState provides a mock function with given fields: ctx, header, endState, chunkDataPacks, executionReceipt, events, serviceEvents, results
-func (_m *ExecutionState) PersistExecutionState(ctx context.Context, header *flow.Header, endState flow.StateCommitment, chunkDataPacks []*flow.ChunkDataPack, executionReceipt *flow.ExecutionReceipt, events []flow.EventsList, serviceEvents flow.EventsList, results []flow.TransactionResult) error {
-	ret := _m.Called(ctx, header, endState, chunkDataPacks, executionReceipt, events, serviceEvents, results)
+// NewViewAtHeight provides a mock function with given fields: _a0, _a1
+func (_m *ExecutionState) NewViewAtHeight(_a0 context.Context, _a1 uint64) (*delta.View, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *delta.View
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *delta.View); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*delta.View)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PersistExecutionState provides a mock function with given fields: ctx, header, endState, chunkDataPacks, executionReceipt, events, serviceEvents, results, trieUpdates
+func (_m *ExecutionState) PersistExecutionState(ctx context.Context, header *flow.Header, endState flow.StateCommitment, chunkDataPacks []*flow.ChunkDataPack, executionReceipt *flow.ExecutionReceipt, events []flow.EventsList, serviceEvents flow.EventsList, results []flow.TransactionResult, trieUpdates []*ledger.TrieUpdate) error {
+	ret := _m.Called(ctx, header, endState, chunkDataPacks, executionReceipt, events, serviceEvents, results, trieUpdates)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *flow.Header, flow.StateCommitment, []*flow.ChunkDataPack, *flow.ExecutionReceipt, []flow.EventsList, flow.EventsList, []flow.TransactionResult) error); ok {
-		r0 = rf(ctx, header, endState, chunkDataPacks, executionReceipt, events, serviceEvents, results)
+	if rf, ok := ret.Get(0).(func(context.Context, *flow.Header, flow.StateCommitment, []*flow.ChunkDataPack, *flow.ExecutionReceipt, []flow.EventsList, flow.EventsList, []flow.TransactionResult, []*ledger.TrieUpdate) error); ok {
+		r0 = rf(ctx, header, endState, chunkDataPacks, executionReceipt, events, serviceEvents, results, trieUpdates)
 	} else {
 		r0 = ret.Error(0)
 	}
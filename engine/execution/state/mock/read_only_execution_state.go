@@ -163,6 +163,29 @@ func (_m *ReadOnlyExecutionState) GetProof(_a0 context.Context, _a1 flow.StateCo
 	return r0, r1
 }
 
+// GetRegisterAtHeight provides a mock function with given fields: _a0, _a1, _a2
+func (_m *ReadOnlyExecutionState) GetRegisterAtHeight(_a0 context.Context, _a1 flow.RegisterID, _a2 uint64) ([]byte, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(context.Context, flow.RegisterID, uint64) []byte); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, flow.RegisterID, uint64) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRegisters provides a mock function with given fields: _a0, _a1, _a2
 func (_m *ReadOnlyExecutionState) GetRegisters(_a0 context.Context, _a1 flow.StateCommitment, _a2 []flow.RegisterID) ([][]byte, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -202,6 +225,29 @@ func (_m *ReadOnlyExecutionState) NewView(_a0 flow.StateCommitment) *delta.View
 	return r0
 }
 
+// NewViewAtHeight provides a mock function with given fields: _a0, _a1
+func (_m *ReadOnlyExecutionState) NewViewAtHeight(_a0 context.Context, _a1 uint64) (*delta.View, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *delta.View
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) *delta.View); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*delta.View)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RetrieveStateDelta provides a mock function with given fields: _a0, _a1
 func (_m *ReadOnlyExecutionState) RetrieveStateDelta(_a0 context.Context, _a1 flow.Identifier) (*messages.ExecutionStateDelta, error) {
 	ret := _m.Called(_a0, _a1)
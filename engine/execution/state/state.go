@@ -25,12 +25,23 @@ type ReadOnlyExecutionState interface {
 	// NewView creates a new ready-only view at the given state commitment.
 	NewView(flow.StateCommitment) *delta.View
 
+	// NewViewAtHeight creates a new read-only view of the state after executing
+	// the finalized block at the given height, reading registers from the
+	// register history. It returns ErrRegisterHistoryUnavailable if the register
+	// history does not cover the given height.
+	NewViewAtHeight(context.Context, uint64) (*delta.View, error)
+
 	GetRegisters(
 		context.Context,
 		flow.StateCommitment,
 		[]flow.RegisterID,
 	) ([]flow.RegisterValue, error)
 
+	// GetRegisterAtHeight returns the value of a register after executing the
+	// finalized block at the given height. It returns ErrRegisterHistoryUnavailable
+	// if the register history does not cover the given height.
+	GetRegisterAtHeight(context.Context, flow.RegisterID, uint64) (flow.RegisterValue, error)
+
 	GetProof(
 		context.Context,
 		flow.StateCommitment,
@@ -66,9 +77,14 @@ type ExecutionState interface {
 
 	PersistExecutionState(ctx context.Context, header *flow.Header, endState flow.StateCommitment,
 		chunkDataPacks []*flow.ChunkDataPack,
-		executionReceipt *flow.ExecutionReceipt, events []flow.EventsList, serviceEvents flow.EventsList, results []flow.TransactionResult) error
+		executionReceipt *flow.ExecutionReceipt, events []flow.EventsList, serviceEvents flow.EventsList, results []flow.TransactionResult,
+		trieUpdates []*ledger.TrieUpdate) error
 }
 
+// ErrRegisterHistoryUnavailable is returned when the register history does not
+// cover the requested height.
+var ErrRegisterHistoryUnavailable = errors.New("register history unavailable at height")
+
 const (
 	KeyPartOwner      = uint16(0)
 	KeyPartController = uint16(1)
@@ -89,6 +105,7 @@ type state struct {
 	events             storage.Events
 	serviceEvents      storage.ServiceEvents
 	transactionResults storage.TransactionResults
+	registers          storage.Registers
 	db                 *badger.DB
 }
 
@@ -114,6 +131,7 @@ func NewExecutionState(
 	events storage.Events,
 	serviceEvents storage.ServiceEvents,
 	transactionResults storage.TransactionResults,
+	registers storage.Registers,
 	db *badger.DB,
 	tracer module.Tracer,
 ) ExecutionState {
@@ -131,11 +149,28 @@ func NewExecutionState(
 		events:             events,
 		serviceEvents:      serviceEvents,
 		transactionResults: transactionResults,
+		registers:          registers,
 		db:                 db,
 	}
 
 }
 
+// KeyToRegisterID converts a ledger key into the register ID it was created from.
+func KeyToRegisterID(key ledger.Key) (flow.RegisterID, error) {
+	if len(key.KeyParts) != 3 ||
+		key.KeyParts[0].Type != KeyPartOwner ||
+		key.KeyParts[1].Type != KeyPartController ||
+		key.KeyParts[2].Type != KeyPartKey {
+		return flow.RegisterID{}, fmt.Errorf("key not in expected format: %s", key.String())
+	}
+
+	return flow.NewRegisterID(
+		string(key.KeyParts[0].Value),
+		string(key.KeyParts[1].Value),
+		string(key.KeyParts[2].Value),
+	), nil
+}
+
 func makeSingleValueQuery(commitment flow.StateCommitment, owner, controller, key string) (*ledger.Query, error) {
 	return ledger.NewQuery(ledger.State(commitment),
 		[]ledger.Key{
@@ -211,6 +246,32 @@ func (s *state) NewView(commitment flow.StateCommitment) *delta.View {
 	return delta.NewView(LedgerGetRegister(s.ls, commitment))
 }
 
+func (s *state) NewViewAtHeight(ctx context.Context, height uint64) (*delta.View, error) {
+	_, err := s.registerHistoryHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	readCache := make(map[flow.RegisterID]flow.RegisterEntry)
+
+	return delta.NewView(func(owner, controller, key string) (flow.RegisterValue, error) {
+		regID := flow.NewRegisterID(owner, controller, key)
+
+		if value, ok := readCache[regID]; ok {
+			return value.Value, nil
+		}
+
+		value, err := s.GetRegisterAtHeight(ctx, regID, height)
+		if err != nil {
+			return nil, err
+		}
+
+		readCache[regID] = flow.RegisterEntry{Key: regID, Value: value}
+
+		return value, nil
+	}), nil
+}
+
 type RegisterUpdatesHolder interface {
 	RegisterUpdates() ([]flow.RegisterID, []flow.RegisterValue)
 }
@@ -280,6 +341,44 @@ func (s *state) GetRegisters(
 	return registerValues, nil
 }
 
+// GetRegisterAtHeight returns the value of a register after executing the
+// finalized block at the given height. The register history starts with the
+// values of all registers at its first height, so registers which are not in the
+// history do not exist.
+func (s *state) GetRegisterAtHeight(ctx context.Context, registerID flow.RegisterID, height uint64) (flow.RegisterValue, error) {
+	_, err := s.registerHistoryHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := s.registers.ByIDAtHeight(registerID, height)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot look up register %s at height %d: %w", registerID.String(), height, err)
+	}
+
+	return value, nil
+}
+
+// registerHistoryHeight returns the height the register history starts at, if
+// the history covers the given height.
+func (s *state) registerHistoryHeight(height uint64) (uint64, error) {
+	var historyHeight uint64
+	err := s.db.View(operation.RetrieveRegisterHistoryHeight(&historyHeight))
+	if errors.Is(err, storage.ErrNotFound) {
+		return 0, fmt.Errorf("register history not started yet, height %d: %w", height, ErrRegisterHistoryUnavailable)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("cannot retrieve register history height: %w", err)
+	}
+	if height < historyHeight {
+		return 0, fmt.Errorf("register history not available below height %d, height %d: %w", historyHeight, height, ErrRegisterHistoryUnavailable)
+	}
+	return historyHeight, nil
+}
+
 func (s *state) GetProof(
 	ctx context.Context,
 	commit flow.StateCommitment,
@@ -329,7 +428,7 @@ func (s *state) GetExecutionResultID(ctx context.Context, blockID flow.Identifie
 
 func (s *state) PersistExecutionState(ctx context.Context, header *flow.Header, endState flow.StateCommitment,
 	chunkDataPacks []*flow.ChunkDataPack, executionReceipt *flow.ExecutionReceipt, events []flow.EventsList, serviceEvents flow.EventsList,
-	results []flow.TransactionResult) error {
+	results []flow.TransactionResult, trieUpdates []*ledger.TrieUpdate) error {
 
 	spew.Config.DisableMethods = true
 	spew.Config.DisablePointerMethods = true
@@ -367,6 +466,17 @@ func (s *state) PersistExecutionState(ctx context.Context, header *flow.Header,
 	}
 	sp.Finish()
 
+	sp, _ = s.tracer.StartSpanFromContext(ctx, trace.EXEPersistRegisterHistory)
+	entries, err := registerEntries(trieUpdates)
+	if err != nil {
+		return fmt.Errorf("cannot convert trie updates: %w", err)
+	}
+	err = s.registers.BatchStore(header, entries, batch)
+	if err != nil {
+		return fmt.Errorf("cannot store register history: %w", err)
+	}
+	sp.Finish()
+
 	sp, _ = s.tracer.StartSpanFromContext(ctx, trace.EXEPersistEvents)
 
 	err = s.events.BatchStore(blockID, events, batch)
@@ -417,6 +527,32 @@ func (s *state) PersistExecutionState(ctx context.Context, header *flow.Header,
 	return nil
 }
 
+// registerEntries returns the final value of each register written by the
+// given trie updates, which are applied in order. The trie update of a chunk
+// which wrote no registers is nil.
+func registerEntries(trieUpdates []*ledger.TrieUpdate) (flow.RegisterEntries, error) {
+	indices := make(map[flow.RegisterID]int)
+	var entries flow.RegisterEntries
+	for _, update := range trieUpdates {
+		if update == nil {
+			continue
+		}
+		for _, payload := range update.Payloads {
+			regID, err := KeyToRegisterID(payload.Key)
+			if err != nil {
+				return nil, err
+			}
+			if i, ok := indices[regID]; ok {
+				entries[i].Value = payload.Value
+				continue
+			}
+			indices[regID] = len(entries)
+			entries = append(entries, flow.RegisterEntry{Key: regID, Value: payload.Value})
+		}
+	}
+	return entries, nil
+}
+
 func (s *state) RetrieveStateDelta(ctx context.Context, blockID flow.Identifier) (*messages.ExecutionStateDelta, error) {
 	block, err := s.blocks.ByID(blockID)
 	if err != nil {
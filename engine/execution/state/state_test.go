@@ -14,11 +14,14 @@ import (
 	"github.com/onflow/flow-go/ledger/common/pathfinder"
 
 	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/engine/execution/state/bootstrap"
 	ledger "github.com/onflow/flow-go/ledger/complete"
 	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/trace"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
 	storage "github.com/onflow/flow-go/storage/mock"
 	"github.com/onflow/flow-go/storage/mocks"
 	"github.com/onflow/flow-go/utils/unittest"
@@ -51,9 +54,10 @@ func prepareTest(f func(t *testing.T, es state.ExecutionState, l *ledger.Ledger)
 			results := new(storage.ExecutionResults)
 			receipts := new(storage.ExecutionReceipts)
 			myReceipts := new(storage.MyExecutionReceipts)
+			registers := new(storage.Registers)
 
 			es := state.NewExecutionState(
-				ls, stateCommitments, blocks, headers, collections, chunkDataPacks, results, receipts, myReceipts, events, serviceEvents, txResults, badgerDB, trace.NewNoopTracer(),
+				ls, stateCommitments, blocks, headers, collections, chunkDataPacks, results, receipts, myReceipts, events, serviceEvents, txResults, registers, badgerDB, trace.NewNoopTracer(),
 			)
 
 			f(t, es, ls)
@@ -213,3 +217,113 @@ func TestExecutionStateWithTrieStorage(t *testing.T) {
 	}))
 
 }
+
+func TestExecutionStateRegisterHistory(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(badgerDB *badger.DB) {
+		ls, err := ledger.NewLedger(&fixtures.NoopWAL{}, 100, &metrics.NoopCollector{}, zerolog.Nop(), ledger.DefaultPathFinderVersion)
+		require.NoError(t, err)
+
+		ctrl := gomock.NewController(t)
+		stateCommitments := mocks.NewMockCommits(ctrl)
+		headers := mocks.NewMockHeaders(ctrl)
+		registers := badgerstorage.NewRegisters(badgerDB)
+
+		es := state.NewExecutionState(
+			ls, stateCommitments, mocks.NewMockBlocks(ctrl), headers, mocks.NewMockCollections(ctrl), new(storage.ChunkDataPacks),
+			new(storage.ExecutionResults), new(storage.ExecutionReceipts), new(storage.MyExecutionReceipts), mocks.NewMockEvents(ctrl),
+			mocks.NewMockServiceEvents(ctrl), mocks.NewMockTransactionResults(ctrl), registers, badgerDB, trace.NewNoopTracer(),
+		)
+
+		// the register history starts with a snapshot of the root state, with an apple
+		root := unittest.BlockHeaderFixture()
+		root.Height = 10
+		view := es.NewView(flow.StateCommitment(ls.InitialState()))
+		require.NoError(t, view.Set("fruit", "", "", flow.RegisterValue("apple")))
+		rootCommit, _, err := state.CommitDelta(ls, view.Delta(), flow.StateCommitment(ls.InitialState()))
+		require.NoError(t, err)
+
+		require.NoError(t, badgerDB.Update(operation.IndexBlockHeight(root.Height, root.ID())))
+		rootTrie, err := ls.Trie(ledger2.State(rootCommit))
+		require.NoError(t, err)
+		require.NoError(t, bootstrap.NewBootstrapper(zerolog.Nop()).BootstrapRegisterHistory(badgerDB, &root, rootTrie))
+
+		// the finalized child of the root block adds a carrot
+		child := unittest.BlockHeaderWithParentFixture(&root)
+		require.NoError(t, badgerDB.Update(operation.IndexBlockHeight(child.Height, child.ID())))
+		view = es.NewView(rootCommit)
+		require.NoError(t, view.Set("vegetable", "", "", flow.RegisterValue("carrot")))
+		_, trieUpdate, err := state.CommitDelta(ls, view.Delta(), rootCommit)
+		require.NoError(t, err)
+
+		var entries flow.RegisterEntries
+		for _, payload := range trieUpdate.Payloads {
+			registerID, err := state.KeyToRegisterID(payload.Key)
+			require.NoError(t, err)
+			entries = append(entries, flow.RegisterEntry{Key: registerID, Value: payload.Value})
+		}
+		batch := badgerstorage.NewBatch(badgerDB)
+		require.NoError(t, registers.BatchStore(&child, entries, batch))
+		require.NoError(t, batch.Flush())
+
+		ctx := context.Background()
+		fruit := flow.NewRegisterID("fruit", "", "")
+		vegetable := flow.NewRegisterID("vegetable", "", "")
+
+		t.Run("registers written since the root block are read from the history", func(t *testing.T) {
+			value, err := es.GetRegisterAtHeight(ctx, vegetable, child.Height)
+			require.NoError(t, err)
+			assert.Equal(t, flow.RegisterValue("carrot"), value)
+
+			value, err = es.GetRegisterAtHeight(ctx, vegetable, root.Height)
+			require.NoError(t, err)
+			assert.Empty(t, value)
+		})
+
+		t.Run("registers of the root state are read from the snapshot", func(t *testing.T) {
+			value, err := es.GetRegisterAtHeight(ctx, fruit, root.Height)
+			require.NoError(t, err)
+			assert.Equal(t, flow.RegisterValue("apple"), value)
+
+			value, err = es.GetRegisterAtHeight(ctx, fruit, child.Height)
+			require.NoError(t, err)
+			assert.Equal(t, flow.RegisterValue("apple"), value)
+		})
+
+		t.Run("registers not in the history do not exist", func(t *testing.T) {
+			value, err := es.GetRegisterAtHeight(ctx, flow.NewRegisterID("fruit", "", "pear"), child.Height)
+			require.NoError(t, err)
+			assert.Empty(t, value)
+		})
+
+		t.Run("view at height", func(t *testing.T) {
+			view, err := es.NewViewAtHeight(ctx, child.Height)
+			require.NoError(t, err)
+
+			value, err := view.Get("fruit", "", "")
+			require.NoError(t, err)
+			assert.Equal(t, flow.RegisterValue("apple"), value)
+			value, err = view.Get("vegetable", "", "")
+			require.NoError(t, err)
+			assert.Equal(t, flow.RegisterValue("carrot"), value)
+		})
+
+		t.Run("heights before the root block are unavailable", func(t *testing.T) {
+			_, err := es.GetRegisterAtHeight(ctx, fruit, root.Height-1)
+			require.ErrorIs(t, err, state.ErrRegisterHistoryUnavailable)
+
+			_, err = es.NewViewAtHeight(ctx, root.Height-1)
+			require.ErrorIs(t, err, state.ErrRegisterHistoryUnavailable)
+		})
+	})
+}
+
+func TestKeyToRegisterID(t *testing.T) {
+	registerID := flow.NewRegisterID("owner", "controller", "key")
+
+	converted, err := state.KeyToRegisterID(state.RegisterIDToKey(registerID))
+	require.NoError(t, err)
+	assert.Equal(t, registerID, converted)
+
+	_, err = state.KeyToRegisterID(ledger2.NewKey([]ledger2.KeyPart{ledger2.NewKeyPart(0, []byte("owner"))}))
+	require.Error(t, err)
+}
@@ -11,6 +11,8 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 
+	accessingestion "github.com/onflow/flow-go/engine/access/ingestion"
+	"github.com/onflow/flow-go/engine/access/rpc"
 	"github.com/onflow/flow-go/engine/collection/epochmgr"
 	collectioningest "github.com/onflow/flow-go/engine/collection/ingest"
 	"github.com/onflow/flow-go/engine/collection/pusher"
@@ -111,6 +113,38 @@ func (g *GenericNode) CloseDB() error {
 	return g.DB.Close()
 }
 
+// AccessNode implements an in-process access node for tests.
+type AccessNode struct {
+	GenericNode
+	Collections     storage.Collections
+	Transactions    storage.Transactions
+	Results         storage.ExecutionResults
+	Receipts        storage.ExecutionReceipts
+	IngestionEngine *accessingestion.Engine
+	RequesterEngine *requester.Engine
+	RPCEngine       *rpc.Engine
+}
+
+func (n AccessNode) Ready() <-chan struct{} {
+	return lifecycle.AllReady(
+		n.RequesterEngine,
+		n.IngestionEngine,
+	)
+}
+
+func (n AccessNode) Done() <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		<-lifecycle.AllDone(
+			n.RequesterEngine,
+			n.IngestionEngine,
+		)
+		n.GenericNode.Done()
+		close(done)
+	}()
+	return done
+}
+
 // CollectionNode implements an in-process collection node for tests.
 type CollectionNode struct {
 	GenericNode
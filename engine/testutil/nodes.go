@@ -17,6 +17,8 @@ import (
 	"github.com/onflow/flow-go/consensus/hotstuff/notifications/pubsub"
 	"github.com/onflow/flow-go/crypto"
 	"github.com/onflow/flow-go/engine"
+	accessingestion "github.com/onflow/flow-go/engine/access/ingestion"
+	"github.com/onflow/flow-go/engine/access/rpc"
 	"github.com/onflow/flow-go/engine/collection/epochmgr"
 	"github.com/onflow/flow-go/engine/collection/epochmgr/factories"
 	collectioningest "github.com/onflow/flow-go/engine/collection/ingest"
@@ -219,20 +221,69 @@ func CompleteStateFixture(
 	}
 }
 
+// AccessNode returns an access node, which indexes the collections of the blocks
+// it is notified of as finalized, and the execution receipts it receives. Its RPC
+// engine is not started, it only serves as the sink of the ingestion engine.
+func AccessNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identities []*flow.Identity, chainID flow.ChainID) testmock.AccessNode {
+	node := GenericNodeFromParticipants(t, hub, identity, identities, chainID)
+
+	transactions := storage.NewTransactions(node.Metrics, node.DB)
+	collections := storage.NewCollections(node.DB, transactions)
+	results := storage.NewExecutionResults(node.Metrics, node.DB)
+	receipts := storage.NewExecutionReceipts(node.Metrics, node.DB, results, storage.DefaultCacheSize)
+
+	collectionsToMarkFinalized, err := stdmap.NewTimes(100)
+	require.NoError(t, err)
+	collectionsToMarkExecuted, err := stdmap.NewTimes(100)
+	require.NoError(t, err)
+	blocksToMarkExecuted, err := stdmap.NewTimes(100)
+	require.NoError(t, err)
+
+	rpcEngine := rpc.New(node.Log, node.State, rpc.Config{}, nil, nil, node.Blocks, node.Headers, collections, transactions,
+		receipts, results, chainID, node.Metrics, 0, 0, false, false, nil, nil)
+
+	requesterEngine, err := requester.New(node.Log, node.Metrics, node.Net, node.Me, node.State,
+		engine.RequestCollections,
+		filter.HasRole(flow.RoleCollection),
+		func() flow.Entity { return &flow.Collection{} },
+	)
+	require.NoError(t, err)
+
+	ingestionEngine, err := accessingestion.New(node.Log, node.Net, node.State, node.Me, requesterEngine, node.Blocks, node.Headers,
+		collections, transactions, results, receipts, node.Metrics, collectionsToMarkFinalized, collectionsToMarkExecuted,
+		blocksToMarkExecuted, rpcEngine)
+	require.NoError(t, err)
+	requesterEngine.WithHandle(ingestionEngine.OnCollection)
+
+	return testmock.AccessNode{
+		GenericNode:     node,
+		Collections:     collections,
+		Transactions:    transactions,
+		Results:         results,
+		Receipts:        receipts,
+		IngestionEngine: ingestionEngine,
+		RequesterEngine: requesterEngine,
+		RPCEngine:       rpcEngine,
+	}
+}
+
 // CollectionNode returns a mock collection node.
 func CollectionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, rootSnapshot protocol.Snapshot) testmock.CollectionNode {
 
 	node := GenericNode(t, hub, identity, rootSnapshot)
 
-	pools := epochs.NewTransactionPools(func() mempool.Transactions { return stdmap.NewTransactions(1000) })
+	journal := storage.NewTransactionJournal(node.DB)
+	pools := epochs.NewTransactionPools(func(epoch uint64) mempool.Transactions {
+		return epochs.NewJournaledTransactions(node.Log, epoch, stdmap.NewTransactions(1000), journal)
+	})
 	transactions := storage.NewTransactions(node.Metrics, node.DB)
 	collections := storage.NewCollections(node.DB, transactions)
 	clusterPayloads := storage.NewClusterPayloads(node.Metrics, node.DB)
 
-	ingestionEngine, err := collectioningest.New(node.Log, node.Net, node.State, node.Metrics, node.Metrics, node.Me, node.ChainID.Chain(), pools, collectioningest.DefaultConfig())
+	ingestionEngine, err := collectioningest.New(node.Log, node.Net, node.State, node.Metrics, node.Metrics, node.Me, node.ChainID.Chain(), pools, journal, collectioningest.DefaultConfig())
 	require.NoError(t, err)
 
-	selector := filter.HasRole(flow.RoleAccess, flow.RoleVerification)
+	selector := filter.HasRole(flow.RoleAccess, flow.RoleExecution)
 	retrieve := func(collID flow.Identifier) (flow.Entity, error) {
 		coll, err := collections.ByID(collID)
 		return coll, err
@@ -295,6 +346,7 @@ func CollectionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, rootSn
 		aggregator,
 		node.DB,
 		node.State,
+		storage.NewSlashingEvidence(node.DB),
 		createMetrics,
 		consensus.WithInitialTimeout(time.Second*2),
 	)
@@ -486,6 +538,7 @@ func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 	eventsStorage := storage.NewEvents(node.Metrics, node.DB)
 	serviceEventsStorage := storage.NewServiceEvents(node.Metrics, node.DB)
 	txResultStorage := storage.NewTransactionResults(node.Metrics, node.DB, storage.DefaultCacheSize)
+	registersStorage := storage.NewRegisters(node.DB)
 	commitsStorage := storage.NewCommits(node.Metrics, node.DB)
 	chunkDataPackStorage := storage.NewChunkDataPacks(node.Metrics, node.DB, collectionsStorage, 100)
 	results := storage.NewExecutionResults(node.Metrics, node.DB)
@@ -529,7 +582,7 @@ func ExecutionNode(t *testing.T, hub *stub.Hub, identity *flow.Identity, identit
 	require.NoError(t, err)
 
 	execState := executionState.NewExecutionState(
-		ls, commitsStorage, node.Blocks, node.Headers, collectionsStorage, chunkDataPackStorage, results, receipts, myReceipts, eventsStorage, serviceEventsStorage, txResultStorage, node.DB, node.Tracer,
+		ls, commitsStorage, node.Blocks, node.Headers, collectionsStorage, chunkDataPackStorage, results, receipts, myReceipts, eventsStorage, serviceEventsStorage, txResultStorage, registersStorage, node.DB, node.Tracer,
 	)
 
 	requestEngine, err := requester.New(
@@ -844,6 +897,7 @@ func VerificationNode(t testing.TB,
 		chunkVerifier := chunks.NewChunkVerifier(vm, vmCtx, node.Log)
 
 		approvalStorage := storage.NewResultApprovals(node.Metrics, node.DB)
+		challengeStorage := storage.NewChunkChallenges(node.DB)
 
 		node.VerifierEngine, err = verifier.New(node.Log,
 			collector,
@@ -852,7 +906,11 @@ func VerificationNode(t testing.TB,
 			node.State,
 			node.Me,
 			chunkVerifier,
-			approvalStorage)
+			approvalStorage,
+			node.Receipts,
+			challengeStorage,
+			verifier.DefaultChunkWorkers,
+			verifier.DefaultChunkQueueCapacity)
 		require.Nil(t, err)
 	}
 
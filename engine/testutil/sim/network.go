@@ -0,0 +1,713 @@
+// Package sim assembles access, collection, consensus, execution and verification
+// nodes of the test utilities into an in-process network over a hub of stub
+// networks, and injects faults into the delivery of their messages. It exercises
+// the pipeline from transactions to sealed results with scripted consensus: the
+// block production of consensus and collection nodes is driven by the network,
+// so faults only apply to the messages exchanged outside of consensus, which
+// include the block proposals delivered to the execution nodes.
+//
+// A simulation advances in rounds, each of which takes the configured round
+// duration on the virtual clock of the simulation, which the block timestamps
+// are taken from. A round ends once the nodes have processed the
+// messages delivered in it, rather than after a fixed amount of real time.
+//
+// The scope of a simulation is limited by the nodes of the test utilities:
+//   - Consensus is scripted. The consensus nodes do not run HotStuff; the network
+//     builds, incorporates and finalizes blocks itself, so votes, timeouts and
+//     leader failures are not simulated. Likewise, the collection nodes do not
+//     run cluster consensus; the network builds collections of the transactions
+//     in their pools.
+//   - The engines still use real-time timers internally, for instance to retry
+//     requests, and work a node does for longer than the quiet period without
+//     sending messages ends up in a later round. Only message delivery, block
+//     production and the pending requests are driven by the rounds.
+//
+// TODO: run the HotStuff participants of the consensus nodes and the cluster
+// consensus of the collection nodes on the virtual clock, with their messages
+// delivered by the router, so that votes, timeouts and leader failures are
+// subject to the fault injection rules as well.
+package sim
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/testutil"
+	testmock "github.com/onflow/flow-go/engine/testutil/mock"
+	"github.com/onflow/flow-go/model/encodable"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/messages"
+	builder "github.com/onflow/flow-go/module/builder/consensus"
+	"github.com/onflow/flow-go/module/chunks"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/signature"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/stub"
+	"github.com/onflow/flow-go/state/protocol"
+	storageerr "github.com/onflow/flow-go/storage"
+	storage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// finalizationDepth is the number of descendants a block needs to be finalized,
+// as HotStuff finalizes the head of a 3-chain of blocks with consecutive views.
+const finalizationDepth = 3
+
+// Config configures a simulated network.
+type Config struct {
+	AccessNodes       uint          // number of access nodes
+	CollectionNodes   uint          // number of collection nodes, forming a single cluster
+	ConsensusNodes    uint          // number of consensus nodes
+	ExecutionNodes    uint          // number of execution nodes
+	VerificationNodes uint          // number of verification nodes
+	Seed              int64         // seed of all random decisions of the simulation
+	BlockRounds       uint          // number of rounds between two block proposals
+	RoundDuration     time.Duration // virtual time a round takes on the clock of the simulation
+	QuietPeriod       time.Duration // real time without messages sent after which the nodes are done with a round, see Step
+	MaxRoundDuration  time.Duration // real time after which a round ends even though the nodes keep sending messages
+}
+
+// DefaultConfig returns a configuration with one node of each role.
+func DefaultConfig() Config {
+	return Config{
+		AccessNodes:       1,
+		CollectionNodes:   1,
+		ConsensusNodes:    1,
+		ExecutionNodes:    1,
+		VerificationNodes: 1,
+		Seed:              0,
+		BlockRounds:       5,
+		RoundDuration:     200 * time.Millisecond,
+		QuietPeriod:       20 * time.Millisecond,
+		MaxRoundDuration:  time.Second,
+	}
+}
+
+// Network is an in-process network of access, collection, consensus, execution
+// and verification nodes connected over a hub of stub networks, whose messages
+// are delivered by a router applying fault injection rules.
+//
+// The consensus nodes of the test utilities do not run HotStuff, so the network
+// scripts consensus instead: every few rounds, it has the leader of the next view
+// build a block on top of the previous one, incorporates the block into the
+// states of all nodes without a follower, and finalizes blocks by the 3-chain
+// rule. Execution nodes receive the block proposals over the router like any
+// other message, so they are subject to the rules, and run their own follower.
+// The consensus nodes do not serve synchronization requests either, so each
+// proposal is sent along with the proposals which are not yet finalized by all
+// execution nodes. Proposals already processed by a node are deduplicated.
+//
+// Likewise, collection nodes do not run cluster consensus. Transactions are sent
+// with SendTransaction to the access nodes, which send them to the collection
+// nodes over the router. Before each block proposal, the network has a node of
+// each cluster build a collection of the transactions in the pools of the
+// cluster and guarantee it. Collections can also be sent with SendCollection,
+// bypassing the access nodes and the pools.
+type Network struct {
+	t      *testing.T
+	config Config
+	rng    *rand.Rand
+	hub    *stub.Hub
+	Router *Router
+
+	Identities        flow.IdentityList
+	AccessNodes       []testmock.AccessNode
+	CollectionNodes   []testmock.CollectionNode
+	ConsensusNodes    []testmock.ConsensusNode
+	ExecutionNodes    []testmock.ExecutionNode
+	VerificationNodes []testmock.VerificationNode
+
+	leaders       flow.IdentityList // consensus committee, in the order used for leader selection
+	builders      map[flow.Identifier]*builder.Builder
+	chain         []*flow.Header    // all proposed blocks, starting with the root block
+	txConduits    []network.Conduit // conduits of the access nodes sending transactions to collection nodes
+	proposals     []*messages.BlockProposal
+	nextAccess    int
+	nextCollector int
+}
+
+// New creates a network of nodes as per the given configuration, and starts the
+// engines of all nodes. It must be stopped with Stop at the end of the test.
+func New(t *testing.T, config Config) *Network {
+	require.NotZero(t, config.AccessNodes, "an access node is required")
+	require.NotZero(t, config.CollectionNodes, "a collection node is required")
+	require.NotZero(t, config.ConsensusNodes, "a consensus node is required")
+	require.NotZero(t, config.ExecutionNodes, "an execution node is required")
+	require.NotZero(t, config.VerificationNodes, "a verification node is required")
+	require.NotZero(t, config.BlockRounds, "blocks must be proposed at least every round")
+
+	chainID := flow.Testnet
+	hub := stub.NewNetworkHub()
+
+	var identities flow.IdentityList
+	for _, nodes := range []struct {
+		role  flow.Role
+		count uint
+	}{
+		{flow.RoleAccess, config.AccessNodes},
+		{flow.RoleCollection, config.CollectionNodes},
+		{flow.RoleConsensus, config.ConsensusNodes},
+		{flow.RoleExecution, config.ExecutionNodes},
+		{flow.RoleVerification, config.VerificationNodes},
+	} {
+		identities = append(identities, unittest.IdentityListFixture(int(nodes.count), unittest.WithRole(nodes.role), unittest.WithKeys)...)
+	}
+
+	n := &Network{
+		t:          t,
+		config:     config,
+		rng:        rand.New(rand.NewSource(config.Seed)),
+		hub:        hub,
+		Router:     NewRouter(hub, config.Seed),
+		Identities: identities,
+		builders:   make(map[flow.Identifier]*builder.Builder),
+	}
+
+	for _, identity := range identities.Filter(filter.HasRole(flow.RoleAccess)) {
+		node := testutil.AccessNode(t, hub, identity, identities, chainID)
+		// access nodes send transactions to collection nodes over gRPC, which is
+		// replaced by the transaction channel of the stub network
+		con, err := node.Net.Register(engine.PushTransactions, &transactionSender{})
+		require.NoError(t, err)
+		n.txConduits = append(n.txConduits, con)
+		n.AccessNodes = append(n.AccessNodes, node)
+	}
+
+	rootSnapshot := unittest.RootSnapshotFixture(identities)
+	for _, identity := range identities.Filter(filter.HasRole(flow.RoleCollection)) {
+		n.CollectionNodes = append(n.CollectionNodes, testutil.CollectionNode(t, hub, identity, rootSnapshot))
+	}
+
+	for _, identity := range identities.Filter(filter.HasRole(flow.RoleConsensus)) {
+		node := testutil.ConsensusNode(t, hub, identity, identities, chainID)
+		resultsDB := storage.NewExecutionResults(node.Metrics, node.DB)
+		receiptsDB := storage.NewExecutionReceipts(node.Metrics, node.DB, resultsDB, storage.DefaultCacheSize)
+		build, err := builder.NewBuilder(node.Metrics, node.DB, node.State, node.Headers, node.GenericNode.Seals, node.Index,
+			node.Blocks, resultsDB, receiptsDB, node.Guarantees, node.Seals, node.Receipts, node.Tracer)
+		require.NoError(t, err)
+		n.builders[identity.NodeID] = build
+		n.ConsensusNodes = append(n.ConsensusNodes, node)
+	}
+
+	for _, identity := range identities.Filter(filter.HasRole(flow.RoleExecution)) {
+		n.ExecutionNodes = append(n.ExecutionNodes, testutil.ExecutionNode(t, hub, identity, identities, 21, chainID))
+	}
+
+	for _, identity := range identities.Filter(filter.HasRole(flow.RoleVerification)) {
+		generic := testutil.GenericNodeFromParticipants(t, hub, identity, identities, chainID)
+		assigner, err := chunks.NewChunkAssigner(chunks.DefaultChunkAssignmentAlpha, generic.State)
+		require.NoError(t, err)
+		collector := metrics.NewNoopCollector()
+		n.VerificationNodes = append(n.VerificationNodes, testutil.VerificationNode(t, hub, identity, identities, assigner,
+			100, chainID, collector, collector, testutil.WithGenericNode(&generic)))
+	}
+
+	// the committee is ordered as by the followers of the execution nodes
+	leaders, err := n.ExecutionNodes[0].State.AtHeight(0).Identities(filter.HasRole(flow.RoleConsensus))
+	require.NoError(t, err)
+	n.leaders = leaders
+
+	root, err := n.ConsensusNodes[0].State.Params().Root()
+	require.NoError(t, err)
+	n.chain = []*flow.Header{root}
+
+	n.start()
+
+	return n
+}
+
+// start starts the engines of all nodes. The epoch managers of the collection
+// nodes are not started, as they would run cluster consensus in real time.
+func (n *Network) start() {
+	for _, node := range n.AccessNodes {
+		unittest.RequireCloseBefore(n.t, node.Ready(), time.Second, "could not start access node")
+	}
+	for _, node := range n.CollectionNodes {
+		unittest.RequireComponentsReadyBefore(n.t, time.Second, node.IngestionEngine, node.PusherEngine, node.ProviderEngine)
+	}
+	for _, node := range n.ConsensusNodes {
+		unittest.RequireComponentsReadyBefore(n.t, time.Second, node.IngestionEngine, node.SealingEngine, node.MatchingEngine)
+	}
+	for _, node := range n.ExecutionNodes {
+		unittest.RequireReturnsBefore(n.t, node.Ready, 10*time.Second, "could not start execution node")
+	}
+	for _, node := range n.VerificationNodes {
+		unittest.RequireComponentsReadyBefore(n.t, time.Second, node.BlockConsumer, node.ChunkConsumer, node.AssignerEngine,
+			node.FetcherEngine, node.RequesterEngine, node.VerifierEngine)
+	}
+}
+
+// Stop stops the engines of all nodes and removes their databases.
+func (n *Network) Stop() {
+	for _, node := range n.AccessNodes {
+		unittest.RequireCloseBefore(n.t, node.Done(), time.Second, "could not stop access node")
+	}
+	for _, node := range n.CollectionNodes {
+		unittest.RequireComponentsDoneBefore(n.t, time.Second, node.IngestionEngine, node.PusherEngine, node.ProviderEngine)
+		node.GenericNode.Done()
+	}
+	for _, node := range n.ConsensusNodes {
+		unittest.RequireComponentsDoneBefore(n.t, time.Second, node.IngestionEngine, node.SealingEngine, node.MatchingEngine)
+		node.GenericNode.Done()
+	}
+	for _, node := range n.ExecutionNodes {
+		unittest.RequireReturnsBefore(n.t, node.Done, 10*time.Second, "could not stop execution node")
+	}
+	for _, node := range n.VerificationNodes {
+		unittest.RequireComponentsDoneBefore(n.t, time.Second, node.BlockConsumer, node.ChunkConsumer, node.AssignerEngine,
+			node.FetcherEngine, node.RequesterEngine, node.VerifierEngine)
+		node.GenericNode.Done()
+	}
+}
+
+// Step runs a single round of the simulation: if a block is due in this round,
+// it has the collection nodes guarantee the pooled transactions and proposes a
+// block. It dispatches the pending requests of the nodes, and has the router
+// deliver the messages of the round. The round ends once the nodes have not sent
+// any message for the quiet period, such that the messages they send while
+// processing the messages of the round are delivered in the next round.
+func (n *Network) Step() {
+	if n.Router.Round()%uint64(n.config.BlockRounds) == 0 {
+		n.collect()
+		n.propose()
+	}
+	for _, node := range n.AccessNodes {
+		node.RequesterEngine.Force()
+	}
+	for _, node := range n.ExecutionNodes {
+		node.RequestEngine.Force()
+	}
+	n.Router.Step()
+	n.Router.Settle(n.config.QuietPeriod, n.config.MaxRoundDuration)
+}
+
+// Now returns the time on the virtual clock of the simulation, which starts at
+// the timestamp of the root block and advances by the round duration per round.
+func (n *Network) Now() time.Time {
+	return n.chain[0].Timestamp.Add(time.Duration(n.Router.Round()) * n.config.RoundDuration)
+}
+
+// RunUntil runs rounds until the given condition holds, and returns false if it
+// does not hold after the given number of rounds.
+func (n *Network) RunUntil(condition func() bool, rounds uint) bool {
+	for i := uint(0); i < rounds; i++ {
+		if condition() {
+			return true
+		}
+		n.Step()
+	}
+	return condition()
+}
+
+// Head returns the last proposed block.
+func (n *Network) Head() *flow.Header {
+	return n.chain[len(n.chain)-1]
+}
+
+// propose has the leader of the next view build a block on top of the last
+// proposed block, and sends it to all other nodes.
+func (n *Network) propose() {
+	parent := n.Head()
+	view := parent.View + 1
+	leaderID := n.leaders[int(view)%len(n.leaders)].NodeID
+
+	voterSig, err := n.signature()
+	require.NoError(n.t, err)
+	proposerSig, err := n.signature()
+	require.NoError(n.t, err)
+	setter := func(header *flow.Header) error {
+		header.View = view
+		header.Timestamp = n.Now()
+		header.ParentVoterIDs = n.leaders.NodeIDs()
+		header.ParentVoterSigData = voterSig
+		header.ProposerID = leaderID
+		header.ProposerSigData = proposerSig
+		return nil
+	}
+
+	header, err := n.builders[leaderID].BuildOn(parent.ID(), setter)
+	require.NoError(n.t, err, "could not build block on %x", parent.ID())
+	block, err := n.consensusNode(leaderID).Blocks.ByID(header.ID())
+	require.NoError(n.t, err)
+	n.chain = append(n.chain, header)
+
+	for _, state := range n.incorporatingStates() {
+		if state.nodeID == leaderID {
+			continue
+		}
+		err = state.Extend(block)
+		require.NoError(n.t, err, "could not incorporate block %x at node %x", header.ID(), state.nodeID)
+	}
+	for _, node := range n.ConsensusNodes {
+		node.MatchingEngine.OnBlockIncorporated(header.ID())
+		node.SealingEngine.OnBlockIncorporated(header.ID())
+	}
+
+	if len(n.chain) > finalizationDepth+1 {
+		n.finalize(n.chain[len(n.chain)-1-finalizationDepth])
+	}
+
+	n.proposals = append(n.proposals, &messages.BlockProposal{
+		Header:  block.Header,
+		Payload: block.Payload,
+	})
+	n.sendProposals()
+}
+
+// finalize finalizes the given block in the states of all nodes without a
+// follower, and notifies their engines.
+func (n *Network) finalize(header *flow.Header) {
+	blockID := header.ID()
+	for _, state := range n.incorporatingStates() {
+		err := state.Finalize(blockID)
+		require.NoError(n.t, err, "could not finalize block %x at node %x", blockID, state.nodeID)
+	}
+	for _, node := range n.ConsensusNodes {
+		node.MatchingEngine.OnFinalizedBlock(blockID)
+		node.SealingEngine.OnFinalizedBlock(blockID)
+	}
+	for _, node := range n.VerificationNodes {
+		node.BlockConsumer.OnFinalizedBlock(&model.Block{BlockID: blockID})
+	}
+	for _, node := range n.AccessNodes {
+		node.IngestionEngine.OnFinalizedBlock(&model.Block{BlockID: blockID})
+	}
+}
+
+// sendProposals sends the proposals of all blocks not yet finalized by all
+// execution nodes from their proposers to the execution nodes.
+func (n *Network) sendProposals() {
+	finalized := n.Head().Height
+	for _, node := range n.ExecutionNodes {
+		final, err := node.State.Final().Head()
+		require.NoError(n.t, err)
+		if final.Height < finalized {
+			finalized = final.Height
+		}
+	}
+
+	targetIDs := n.Identities.Filter(filter.HasRole(flow.RoleExecution)).NodeIDs()
+	for _, proposal := range n.proposals {
+		if proposal.Header.Height <= finalized {
+			continue
+		}
+		n.hub.Buffer.Save(&stub.PendingMessage{
+			From:      proposal.Header.ProposerID,
+			Channel:   engine.ReceiveBlocks,
+			Event:     proposal,
+			TargetIDs: targetIDs,
+		})
+	}
+}
+
+// signature returns a random combined signature, which is accepted by the
+// mocked verifiers of the nodes, and from which the block seeds are derived.
+func (n *Network) signature() ([]byte, error) {
+	stakingSig := make([]byte, encodable.ConsensusVoteSigLen)
+	beaconSig := make([]byte, encodable.RandomBeaconSigLen)
+	_, _ = n.rng.Read(stakingSig)
+	_, _ = n.rng.Read(beaconSig)
+	return signature.NewCombiner(encodable.ConsensusVoteSigLen, encodable.RandomBeaconSigLen).Join(stakingSig, beaconSig)
+}
+
+// nodeState is the protocol state of a node.
+type nodeState struct {
+	protocol.MutableState
+	nodeID flow.Identifier
+}
+
+// incorporatingStates returns the states of the nodes without a follower, which
+// the network incorporates blocks into.
+func (n *Network) incorporatingStates() []nodeState {
+	var states []nodeState
+	for _, node := range n.AccessNodes {
+		states = append(states, nodeState{MutableState: node.State, nodeID: node.Me.NodeID()})
+	}
+	for _, node := range n.CollectionNodes {
+		states = append(states, nodeState{MutableState: node.State, nodeID: node.Me.NodeID()})
+	}
+	for _, node := range n.ConsensusNodes {
+		states = append(states, nodeState{MutableState: node.State, nodeID: node.Me.NodeID()})
+	}
+	for _, node := range n.VerificationNodes {
+		states = append(states, nodeState{MutableState: node.State, nodeID: node.Me.NodeID()})
+	}
+	return states
+}
+
+// consensusNode returns the consensus node with the given ID.
+func (n *Network) consensusNode(nodeID flow.Identifier) testmock.ConsensusNode {
+	for _, node := range n.ConsensusNodes {
+		if node.Me.NodeID() == nodeID {
+			return node
+		}
+	}
+	require.FailNow(n.t, "unknown consensus node", "node %x", nodeID)
+	return testmock.ConsensusNode{}
+}
+
+// collectionNode returns the collection node with the given ID.
+func (n *Network) collectionNode(nodeID flow.Identifier) testmock.CollectionNode {
+	for _, node := range n.CollectionNodes {
+		if node.Me.NodeID() == nodeID {
+			return node
+		}
+	}
+	require.FailNow(n.t, "unknown collection node", "node %x", nodeID)
+	return testmock.CollectionNode{}
+}
+
+// transactionSender is the engine of the access nodes on the transaction channel,
+// which only sends transactions.
+type transactionSender struct{}
+
+func (s *transactionSender) SubmitLocal(interface{}) {}
+
+func (s *transactionSender) Submit(network.Channel, flow.Identifier, interface{}) {}
+
+func (s *transactionSender) ProcessLocal(interface{}) error {
+	return nil
+}
+
+func (s *transactionSender) Process(network.Channel, flow.Identifier, interface{}) error {
+	return nil
+}
+
+// SendTransaction submits the transaction to the next access node, which sends it
+// to the collection nodes of the cluster responsible for it. Transactions without
+// a reference block reference the latest block finalized by the access node. It
+// returns the ID of the sent transaction.
+func (n *Network) SendTransaction(tx *flow.TransactionBody) flow.Identifier {
+	index := n.nextAccess % len(n.AccessNodes)
+	n.nextAccess++
+	node := n.AccessNodes[index]
+
+	if tx.ReferenceBlockID == flow.ZeroID {
+		final, err := node.State.Final().Head()
+		require.NoError(n.t, err)
+		tx.ReferenceBlockID = final.ID()
+	}
+
+	txID := tx.ID()
+	clusters, err := node.State.Final().Epochs().Current().Clustering()
+	require.NoError(n.t, err)
+	cluster, ok := clusters.ByTxID(txID)
+	require.True(n.t, ok, "no cluster is responsible for transaction %x", txID)
+
+	err = n.txConduits[index].Publish(tx, cluster.NodeIDs()...)
+	require.NoError(n.t, err)
+
+	return txID
+}
+
+// collect has a node of each cluster build a collection of the transactions in
+// the pools of the cluster and guarantee it, in place of cluster consensus. The
+// transactions are removed from the pools.
+func (n *Network) collect() {
+	epoch := n.CollectionNodes[0].State.Final().Epochs().Current()
+	counter, err := epoch.Counter()
+	require.NoError(n.t, err)
+	clusters, err := epoch.Clustering()
+	require.NoError(n.t, err)
+
+	for _, cluster := range clusters {
+		pooled := make(map[flow.Identifier]*flow.TransactionBody)
+		for _, nodeID := range cluster.NodeIDs() {
+			pool := n.collectionNode(nodeID).TxPools.ForEpoch(counter)
+			for _, tx := range pool.All() {
+				pooled[tx.ID()] = tx
+				pool.Rem(tx.ID())
+			}
+		}
+		if len(pooled) == 0 {
+			continue
+		}
+
+		// the transactions are ordered by ID, so that the collection does not
+		// depend on the order the pools return them in
+		txIDs := make(flow.IdentifierList, 0, len(pooled))
+		for txID := range pooled {
+			txIDs = append(txIDs, txID)
+		}
+		sort.Slice(txIDs, func(i, j int) bool {
+			return bytes.Compare(txIDs[i][:], txIDs[j][:]) < 0
+		})
+		transactions := make([]*flow.TransactionBody, 0, len(txIDs))
+		for _, txID := range txIDs {
+			transactions = append(transactions, pooled[txID])
+		}
+
+		collector := cluster[int(n.Head().Height)%len(cluster)]
+		n.guarantee(n.collectionNode(collector.NodeID), cluster, transactions)
+	}
+}
+
+// SendCollection stores a collection of the given transactions at all nodes of
+// the cluster of the next collection node, which then guarantees it to the
+// consensus nodes.
+func (n *Network) SendCollection(transactions ...*flow.TransactionBody) *flow.CollectionGuarantee {
+	collector := n.CollectionNodes[n.nextCollector%len(n.CollectionNodes)]
+	n.nextCollector++
+
+	clusters, err := collector.State.Final().Epochs().Current().Clustering()
+	require.NoError(n.t, err)
+	cluster, _, ok := clusters.ByNodeID(collector.Me.NodeID())
+	require.True(n.t, ok, "collection node %x is not in any cluster", collector.Me.NodeID())
+
+	return n.guarantee(collector, cluster, transactions)
+}
+
+// guarantee stores a collection of the given transactions at all nodes of the
+// given cluster, and has the given collection node guarantee it to the consensus
+// nodes.
+func (n *Network) guarantee(collector testmock.CollectionNode, cluster flow.IdentityList, transactions []*flow.TransactionBody) *flow.CollectionGuarantee {
+	final, err := collector.State.Final().Head()
+	require.NoError(n.t, err)
+
+	collection := &flow.Collection{Transactions: transactions}
+	for _, node := range n.CollectionNodes {
+		if _, ok := cluster.ByNodeID(node.Me.NodeID()); ok {
+			err = node.Collections.Store(collection)
+			require.NoError(n.t, err)
+		}
+	}
+
+	sig, err := n.signature()
+	require.NoError(n.t, err)
+	guarantee := &flow.CollectionGuarantee{
+		CollectionID:     collection.ID(),
+		ReferenceBlockID: final.ID(),
+		SignerIDs:        cluster.NodeIDs(),
+		Signature:        sig,
+	}
+	err = collector.PusherEngine.SubmitCollectionGuarantee(guarantee)
+	require.NoError(n.t, err)
+
+	return guarantee
+}
+
+// states returns the protocol states of all nodes.
+func (n *Network) states() []nodeState {
+	states := n.incorporatingStates()
+	for _, node := range n.ExecutionNodes {
+		states = append(states, nodeState{MutableState: node.State, nodeID: node.Me.NodeID()})
+	}
+	return states
+}
+
+// Finalized returns true if all nodes have finalized a block at the given height.
+func (n *Network) Finalized(height uint64) bool {
+	for _, state := range n.states() {
+		final, err := state.Final().Head()
+		require.NoError(n.t, err)
+		if final.Height < height {
+			return false
+		}
+	}
+	return true
+}
+
+// Sealed returns true if all nodes have sealed a block at the given height.
+func (n *Network) Sealed(height uint64) bool {
+	for _, state := range n.states() {
+		sealed, err := state.Sealed().Head()
+		require.NoError(n.t, err)
+		if sealed.Height < height {
+			return false
+		}
+	}
+	return true
+}
+
+// Executed returns true if all execution nodes have executed a block at the
+// given height.
+func (n *Network) Executed(height uint64) bool {
+	for _, node := range n.ExecutionNodes {
+		executed, _, err := node.ExecutionState.GetHighestExecutedBlockID(context.Background())
+		require.NoError(n.t, err)
+		if executed < height {
+			return false
+		}
+	}
+	return true
+}
+
+// Indexed returns true if all access nodes have indexed the given transactions,
+// which they do once they fetched the collections of the finalized blocks.
+func (n *Network) Indexed(txIDs ...flow.Identifier) bool {
+	for _, node := range n.AccessNodes {
+		for _, txID := range txIDs {
+			_, err := node.Collections.LightByTransactionID(txID)
+			if errors.Is(err, storageerr.ErrNotFound) {
+				return false
+			}
+			require.NoError(n.t, err)
+		}
+	}
+	return true
+}
+
+// RequireFinalized runs rounds until all nodes have finalized a block at the
+// given height, and fails the test if they have not within the given number of rounds.
+func (n *Network) RequireFinalized(height uint64, rounds uint) {
+	require.True(n.t, n.RunUntil(func() bool { return n.Finalized(height) }, rounds),
+		"blocks were not finalized up to height %d within %d rounds", height, rounds)
+}
+
+// RequireSealed runs rounds until all nodes have sealed a block at the given
+// height, and fails the test if they have not within the given number of rounds.
+func (n *Network) RequireSealed(height uint64, rounds uint) {
+	require.True(n.t, n.RunUntil(func() bool { return n.Sealed(height) }, rounds),
+		"blocks were not sealed up to height %d within %d rounds", height, rounds)
+}
+
+// RequireExecuted runs rounds until all execution nodes have executed a block at
+// the given height, and fails the test if they have not within the given number
+// of rounds.
+func (n *Network) RequireExecuted(height uint64, rounds uint) {
+	require.True(n.t, n.RunUntil(func() bool { return n.Executed(height) }, rounds),
+		"blocks were not executed up to height %d within %d rounds", height, rounds)
+}
+
+// RequireIndexed runs rounds until all access nodes have indexed the given
+// transactions, and fails the test if they have not within the given number of
+// rounds.
+func (n *Network) RequireIndexed(rounds uint, txIDs ...flow.Identifier) {
+	require.True(n.t, n.RunUntil(func() bool { return n.Indexed(txIDs...) }, rounds),
+		"transactions were not indexed within %d rounds", rounds)
+}
+
+// RequireConsistent checks that all nodes have finalized the same blocks, up to
+// the lowest height finalized by all nodes.
+func (n *Network) RequireConsistent() {
+	states := n.states()
+	finalized := n.Head().Height
+	for _, state := range states {
+		final, err := state.Final().Head()
+		require.NoError(n.t, err)
+		if final.Height < finalized {
+			finalized = final.Height
+		}
+	}
+
+	for height := uint64(0); height <= finalized; height++ {
+		expected, err := states[0].AtHeight(height).Head()
+		require.NoError(n.t, err)
+		for _, state := range states[1:] {
+			header, err := state.AtHeight(height).Head()
+			require.NoError(n.t, err)
+			require.Equal(n.t, expected.ID(), header.ID(),
+				"node %x finalized a different block at height %d", state.nodeID, height)
+		}
+	}
+}
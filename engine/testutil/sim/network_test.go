@@ -0,0 +1,61 @@
+package sim
+
+import (
+	"testing"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestNetwork_HappyPath checks that blocks with collections are finalized,
+// executed and sealed by all nodes, and that transactions sent to the access
+// nodes are collected and indexed.
+func TestNetwork_HappyPath(t *testing.T) {
+	net := New(t, DefaultConfig())
+	defer net.Stop()
+
+	net.SendCollection(&flow.TransactionBody{Script: []byte("transaction { execute { log(1) } }")})
+
+	address := net.CollectionNodes[0].ChainID.Chain().ServiceAddress()
+	tx := unittest.TransactionBodyFixture(func(tx *flow.TransactionBody) {
+		tx.Script = []byte("transaction { execute { log(2) } }")
+		tx.ReferenceBlockID = flow.ZeroID
+		tx.ProposalKey.Address = address
+		tx.Payer = address
+		tx.Authorizers = []flow.Address{address}
+		tx.EnvelopeSignatures[0].Address = address
+	})
+	txID := net.SendTransaction(&tx)
+
+	net.RequireFinalized(5, 100)
+	net.RequireIndexed(100, txID)
+	net.RequireExecuted(5, 100)
+	net.RequireSealed(1, 500)
+	net.RequireConsistent()
+}
+
+// TestNetwork_Faults checks that execution nodes catch up after being cut off
+// from block proposals, while messages are dropped and delayed on other channels.
+func TestNetwork_Faults(t *testing.T) {
+	config := DefaultConfig()
+	config.ExecutionNodes = 2
+	config.Seed = 7
+	net := New(t, config)
+	defer net.Stop()
+
+	net.Router.AddRule(DropRate(engine.PushReceipts, 0.2))
+	net.Router.AddRule(Delay(engine.RequestCollections, 0, 3))
+	net.Router.AddRule(Reorder(AnyChannel))
+
+	executionIDs := net.Identities.Filter(filter.HasRole(flow.RoleExecution)).NodeIDs()
+	heal := net.Router.AddRule(Isolate(engine.ReceiveBlocks, executionIDs[0]))
+	net.RunUntil(func() bool { return false }, 50)
+	heal()
+
+	height := net.Head().Height
+	net.RequireFinalized(height, 200)
+	net.RequireExecuted(height, 200)
+	net.RequireConsistent()
+}
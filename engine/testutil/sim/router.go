@@ -0,0 +1,256 @@
+package sim
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/encoding"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/stub"
+)
+
+// settlePollInterval is the interval at which the buffer of the hub is checked
+// for new messages while settling.
+const settlePollInterval = time.Millisecond
+
+// Stats counts the messages handled by a router.
+type Stats struct {
+	Delivered uint64 // messages processed by their target
+	Dropped   uint64 // messages dropped by a rule
+	Failed    uint64 // messages whose target is unknown or failed to process them
+}
+
+// keyed is a message taken out of the buffer of the hub, along with the encoding
+// of its event, which orders the messages of the same sender and channel.
+type keyed struct {
+	msg *stub.PendingMessage
+	key []byte
+}
+
+// scheduled is a message scheduled for delivery in a round.
+type scheduled struct {
+	msg     *Message
+	reorder bool
+}
+
+// rule is a rule added to the router, identified so it can be removed again.
+type rule struct {
+	id    uint64
+	apply Rule
+}
+
+// Router delivers the messages buffered in the hub of stub networks in rounds,
+// which are the unit of time of a simulation. In each round, the messages sent
+// since the previous round are split per target node, and the rules are applied
+// to each of them to decide whether it is dropped, delayed or reordered. Then
+// all messages due in the round are processed synchronously by their targets.
+//
+// Delivery is scheduled deterministically: the messages of a round are ordered
+// by sender, channel and content rather than by the order they were sent in, and
+// all random decisions are taken from a source seeded on creation. Between two
+// rounds, Settle waits for the targets to finish processing the delivered
+// messages, so that the messages they send in turn are all handled in the next
+// round. Hence, given the same seed and the same messages sent by the nodes, the
+// router delivers the same messages in the same rounds and order.
+type Router struct {
+	mu        sync.Mutex
+	hub       *stub.Hub
+	rng       *rand.Rand
+	round     uint64
+	rules     []rule
+	nextRule  uint64
+	inbox     []*stub.PendingMessage // messages collected while settling, handled in the next round
+	scheduled map[uint64][]scheduled
+	stats     Stats
+}
+
+// NewRouter creates a router delivering the messages of the given hub, whose
+// random decisions are derived from the given seed.
+func NewRouter(hub *stub.Hub, seed int64) *Router {
+	return &Router{
+		hub:       hub,
+		rng:       rand.New(rand.NewSource(seed)),
+		scheduled: make(map[uint64][]scheduled),
+	}
+}
+
+// AddRule adds a rule applied to all messages sent from now on, and returns a
+// function removing the rule again, for instance to heal a partition.
+func (r *Router) AddRule(apply Rule) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := r.nextRule
+	r.nextRule++
+	r.rules = append(r.rules, rule{id: id, apply: apply})
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		for i, added := range r.rules {
+			if added.id == id {
+				r.rules = append(r.rules[:i:i], r.rules[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Round returns the current round, which is the number of completed steps.
+func (r *Router) Round() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.round
+}
+
+// Stats returns the counts of the messages handled so far.
+func (r *Router) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+// Pending returns the number of messages scheduled for a later round.
+func (r *Router) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending := 0
+	for _, messages := range r.scheduled {
+		pending += len(messages)
+	}
+	return pending
+}
+
+// Step runs a single round: it schedules the messages sent since the previous
+// round, and delivers all messages due in this round. Messages sent while
+// processing the delivered messages are handled in the next round.
+func (r *Router) Step() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, msg := range r.take() {
+		fault := Fault{}
+		for _, added := range r.rules {
+			fault = fault.merge(added.apply(msg, r.rng))
+		}
+		if fault.Drop {
+			r.stats.Dropped++
+			continue
+		}
+		round := r.round + uint64(fault.Delay)
+		r.scheduled[round] = append(r.scheduled[round], scheduled{msg: msg, reorder: fault.Reorder})
+	}
+
+	due := r.scheduled[r.round]
+	delete(r.scheduled, r.round)
+	r.reorder(due)
+
+	for _, s := range due {
+		r.deliver(s.msg)
+	}
+
+	r.round++
+}
+
+// Settle collects the messages the nodes send while processing the messages of
+// the last round, until no message has been sent for the quiet period, or the
+// timeout expired. It returns false if the nodes did not settle in time. The
+// collected messages are handled in the next round.
+func (r *Router) Settle(quiet time.Duration, timeout time.Duration) bool {
+	start := time.Now()
+	lastSent := start
+	for {
+		pending := r.hub.Buffer.TakeAll()
+		now := time.Now()
+		if len(pending) > 0 {
+			r.mu.Lock()
+			r.inbox = append(r.inbox, pending...)
+			r.mu.Unlock()
+			lastSent = now
+		}
+		if now.Sub(lastSent) >= quiet {
+			return true
+		}
+		if now.Sub(start) >= timeout {
+			return false
+		}
+		time.Sleep(settlePollInterval)
+	}
+}
+
+// take takes all messages collected while settling and left in the buffer of the
+// hub, and splits them into one message per target node, ordered by sender,
+// channel and the encoding of their event.
+func (r *Router) take() []*Message {
+	pending := make([]keyed, 0, len(r.inbox))
+	for _, m := range append(r.inbox, r.hub.Buffer.TakeAll()...) {
+		// events which cannot be encoded keep the order they were sent in
+		key, _ := encoding.DefaultEncoder.Encode(m.Event)
+		pending = append(pending, keyed{msg: m, key: key})
+	}
+	r.inbox = nil
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		order := bytes.Compare(pending[i].msg.From[:], pending[j].msg.From[:])
+		if order != 0 {
+			return order < 0
+		}
+		if pending[i].msg.Channel != pending[j].msg.Channel {
+			return pending[i].msg.Channel < pending[j].msg.Channel
+		}
+		return bytes.Compare(pending[i].key, pending[j].key) < 0
+	})
+
+	var messages []*Message
+	for _, p := range pending {
+		for _, targetID := range p.msg.TargetIDs {
+			messages = append(messages, &Message{
+				From:    p.msg.From,
+				To:      targetID,
+				Channel: p.msg.Channel,
+				Event:   p.msg.Event,
+			})
+		}
+	}
+	return messages
+}
+
+// reorder shuffles the messages to be reordered among their positions, leaving
+// all other messages in place.
+func (r *Router) reorder(due []scheduled) {
+	var positions []int
+	for i, s := range due {
+		if s.reorder {
+			positions = append(positions, i)
+		}
+	}
+	r.rng.Shuffle(len(positions), func(i, j int) {
+		due[positions[i]], due[positions[j]] = due[positions[j]], due[positions[i]]
+	})
+}
+
+// deliver has the message processed by its target.
+func (r *Router) deliver(msg *Message) {
+	net, ok := r.hub.GetNetwork(msg.To)
+	if !ok {
+		r.stats.Failed++
+		return
+	}
+
+	err := net.Deliver(&stub.PendingMessage{
+		From:      msg.From,
+		Channel:   msg.Channel,
+		Event:     msg.Event,
+		TargetIDs: []flow.Identifier{msg.To},
+	}, true)
+	if err != nil {
+		r.stats.Failed++
+		return
+	}
+	r.stats.Delivered++
+}
@@ -0,0 +1,309 @@
+package sim
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/testutil/mocklocal"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/stub"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+const (
+	testChannel  = network.Channel("test-channel")
+	otherChannel = network.Channel("other-channel")
+)
+
+// recorder records the events it receives, and replies to them if configured to.
+type recorder struct {
+	sync.Mutex
+	events []interface{}
+	reply  func(originID flow.Identifier, event interface{})
+}
+
+func (r *recorder) SubmitLocal(event interface{}) {}
+
+func (r *recorder) Submit(channel network.Channel, originID flow.Identifier, event interface{}) {
+	_ = r.Process(channel, originID, event)
+}
+
+func (r *recorder) ProcessLocal(event interface{}) error {
+	return nil
+}
+
+func (r *recorder) Process(channel network.Channel, originID flow.Identifier, event interface{}) error {
+	r.Lock()
+	r.events = append(r.events, event)
+	r.Unlock()
+	if r.reply != nil {
+		r.reply(originID, event)
+	}
+	return nil
+}
+
+func (r *recorder) received() []interface{} {
+	r.Lock()
+	defer r.Unlock()
+	return append([]interface{}{}, r.events...)
+}
+
+type testNode struct {
+	conduits  map[network.Channel]network.Conduit
+	recorders map[network.Channel]*recorder
+}
+
+// newTestNodes creates nodes with the given IDs on the hub, with a recorder
+// registered on both test channels.
+func newTestNodes(t *testing.T, hub *stub.Hub, nodeIDs flow.IdentifierList) map[flow.Identifier]*testNode {
+	nodes := make(map[flow.Identifier]*testNode)
+	for _, nodeID := range nodeIDs {
+		net := stub.NewNetwork(nil, mocklocal.NewMockLocal(nil, nodeID, t), hub)
+		node := &testNode{
+			conduits:  make(map[network.Channel]network.Conduit),
+			recorders: make(map[network.Channel]*recorder),
+		}
+		for _, channel := range []network.Channel{testChannel, otherChannel} {
+			node.recorders[channel] = &recorder{}
+			con, err := net.Register(channel, node.recorders[channel])
+			require.NoError(t, err)
+			node.conduits[channel] = con
+		}
+		nodes[nodeID] = node
+	}
+	return nodes
+}
+
+// publish sends the given number of numbered events from the node.
+func (n *testNode) publish(t *testing.T, channel network.Channel, count int, targetIDs ...flow.Identifier) {
+	for i := 0; i < count; i++ {
+		err := n.conduits[channel].Publish(fmt.Sprintf("event %d", i), targetIDs...)
+		require.NoError(t, err)
+	}
+}
+
+func TestRouter_Rounds(t *testing.T) {
+	hub := stub.NewNetworkHub()
+	nodeIDs := unittest.IdentifierListFixture(2)
+	nodes := newTestNodes(t, hub, nodeIDs)
+	router := NewRouter(hub, 0)
+
+	// the second node replies to each event, which is delivered in the next round
+	nodes[nodeIDs[1]].recorders[testChannel].reply = func(originID flow.Identifier, event interface{}) {
+		err := nodes[nodeIDs[1]].conduits[testChannel].Unicast(fmt.Sprintf("reply to %s", event), originID)
+		require.NoError(t, err)
+	}
+
+	nodes[nodeIDs[0]].publish(t, testChannel, 2, nodeIDs[1])
+	assert.Empty(t, nodes[nodeIDs[1]].recorders[testChannel].received())
+
+	router.Step()
+	assert.Equal(t, []interface{}{"event 0", "event 1"}, nodes[nodeIDs[1]].recorders[testChannel].received())
+	assert.Empty(t, nodes[nodeIDs[0]].recorders[testChannel].received())
+
+	router.Step()
+	assert.Equal(t, []interface{}{"reply to event 0", "reply to event 1"}, nodes[nodeIDs[0]].recorders[testChannel].received())
+	assert.Equal(t, uint64(2), router.Round())
+	assert.Equal(t, Stats{Delivered: 4}, router.Stats())
+}
+
+func TestRouter_Drop(t *testing.T) {
+	hub := stub.NewNetworkHub()
+	nodeIDs := unittest.IdentifierListFixture(2)
+	nodes := newTestNodes(t, hub, nodeIDs)
+	router := NewRouter(hub, 0)
+
+	remove := router.AddRule(Drop(testChannel))
+	nodes[nodeIDs[0]].publish(t, testChannel, 1, nodeIDs[1])
+	nodes[nodeIDs[0]].publish(t, otherChannel, 1, nodeIDs[1])
+	router.Step()
+	assert.Empty(t, nodes[nodeIDs[1]].recorders[testChannel].received())
+	assert.Len(t, nodes[nodeIDs[1]].recorders[otherChannel].received(), 1)
+
+	// messages sent again are delivered once the rule is removed
+	remove()
+	nodes[nodeIDs[0]].publish(t, testChannel, 1, nodeIDs[1])
+	router.Step()
+	assert.Equal(t, []interface{}{"event 0"}, nodes[nodeIDs[1]].recorders[testChannel].received())
+	assert.Equal(t, Stats{Delivered: 2, Dropped: 1}, router.Stats())
+}
+
+func TestRouter_DropRate(t *testing.T) {
+	dropped := func(seed int64) []interface{} {
+		hub := stub.NewNetworkHub()
+		nodeIDs := unittest.IdentifierListFixture(2)
+		nodes := newTestNodes(t, hub, nodeIDs)
+		router := NewRouter(hub, seed)
+		router.AddRule(DropRate(AnyChannel, 0.5))
+
+		nodes[nodeIDs[0]].publish(t, testChannel, 100, nodeIDs[1])
+		router.Step()
+		stats := router.Stats()
+		assert.Equal(t, uint64(100), stats.Delivered+stats.Dropped)
+		assert.InDelta(t, 50, stats.Dropped, 20)
+		return nodes[nodeIDs[1]].recorders[testChannel].received()
+	}
+
+	// the same messages are dropped for the same seed
+	assert.Equal(t, dropped(42), dropped(42))
+}
+
+func TestRouter_Delay(t *testing.T) {
+	hub := stub.NewNetworkHub()
+	nodeIDs := unittest.IdentifierListFixture(2)
+	nodes := newTestNodes(t, hub, nodeIDs)
+	router := NewRouter(hub, 0)
+	router.AddRule(Delay(testChannel, 2, 2))
+
+	nodes[nodeIDs[0]].publish(t, testChannel, 1, nodeIDs[1])
+	nodes[nodeIDs[0]].publish(t, otherChannel, 1, nodeIDs[1])
+	router.Step()
+	assert.Empty(t, nodes[nodeIDs[1]].recorders[testChannel].received())
+	assert.Len(t, nodes[nodeIDs[1]].recorders[otherChannel].received(), 1)
+	assert.Equal(t, 1, router.Pending())
+
+	router.Step()
+	assert.Empty(t, nodes[nodeIDs[1]].recorders[testChannel].received())
+
+	router.Step()
+	assert.Len(t, nodes[nodeIDs[1]].recorders[testChannel].received(), 1)
+	assert.Equal(t, 0, router.Pending())
+}
+
+func TestRouter_Reorder(t *testing.T) {
+	// messages are ordered by their content
+	events := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		events = append(events, fmt.Sprintf("event %d", i))
+	}
+	sort.Strings(events)
+	ordered := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		ordered = append(ordered, event)
+	}
+
+	received := func(seed int64) []interface{} {
+		hub := stub.NewNetworkHub()
+		nodeIDs := unittest.IdentifierListFixture(2)
+		nodes := newTestNodes(t, hub, nodeIDs)
+		router := NewRouter(hub, seed)
+		router.AddRule(Reorder(testChannel))
+
+		nodes[nodeIDs[0]].publish(t, testChannel, 20, nodeIDs[1])
+		nodes[nodeIDs[0]].publish(t, otherChannel, 20, nodeIDs[1])
+		router.Step()
+
+		// messages on other channels are not reordered
+		assert.Equal(t, ordered, nodes[nodeIDs[1]].recorders[otherChannel].received())
+		return nodes[nodeIDs[1]].recorders[testChannel].received()
+	}
+
+	reordered := received(42)
+	assert.ElementsMatch(t, ordered, reordered)
+	assert.NotEqual(t, ordered, reordered)
+	assert.Equal(t, reordered, received(42))
+}
+
+// TestRouter_Order checks that the messages of a round are delivered in the same
+// order, independent of the order they were sent in.
+func TestRouter_Order(t *testing.T) {
+	hub := stub.NewNetworkHub()
+	nodeIDs := unittest.IdentifierListFixture(2)
+	nodes := newTestNodes(t, hub, nodeIDs)
+	router := NewRouter(hub, 0)
+
+	for _, event := range []string{"b", "c", "a"} {
+		err := nodes[nodeIDs[0]].conduits[testChannel].Publish(event, nodeIDs[1])
+		require.NoError(t, err)
+	}
+	router.Step()
+	for _, event := range []string{"e", "d", "f"} {
+		err := nodes[nodeIDs[0]].conduits[testChannel].Publish(event, nodeIDs[1])
+		require.NoError(t, err)
+	}
+	router.Step()
+
+	assert.Equal(t, []interface{}{"a", "b", "c", "d", "e", "f"}, nodes[nodeIDs[1]].recorders[testChannel].received())
+}
+
+// TestRouter_Settle checks that the messages sent while settling are delivered
+// in the next round, and that settling times out if the nodes keep sending.
+func TestRouter_Settle(t *testing.T) {
+	hub := stub.NewNetworkHub()
+	nodeIDs := unittest.IdentifierListFixture(2)
+	nodes := newTestNodes(t, hub, nodeIDs)
+	router := NewRouter(hub, 0)
+
+	// the second node replies to each event after a while
+	nodes[nodeIDs[1]].recorders[testChannel].reply = func(originID flow.Identifier, event interface{}) {
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			err := nodes[nodeIDs[1]].conduits[testChannel].Unicast(fmt.Sprintf("reply to %s", event), originID)
+			require.NoError(t, err)
+		}()
+	}
+
+	nodes[nodeIDs[0]].publish(t, testChannel, 1, nodeIDs[1])
+	router.Step()
+	assert.True(t, router.Settle(50*time.Millisecond, time.Second))
+	router.Step()
+	assert.Equal(t, []interface{}{"reply to event 0"}, nodes[nodeIDs[0]].recorders[testChannel].received())
+
+	// a node sending messages all the time never settles
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(time.Millisecond):
+				nodes[nodeIDs[0]].publish(t, otherChannel, 1, nodeIDs[1])
+			}
+		}
+	}()
+	assert.False(t, router.Settle(50*time.Millisecond, 200*time.Millisecond))
+}
+
+func TestRouter_Partition(t *testing.T) {
+	hub := stub.NewNetworkHub()
+	nodeIDs := flow.IdentifierList(unittest.IdentifierListFixture(4))
+	nodes := newTestNodes(t, hub, nodeIDs)
+	router := NewRouter(hub, 0)
+
+	// the last node is in no group, and can communicate with all nodes
+	heal := router.AddRule(Partition(testChannel, nodeIDs[:1], nodeIDs[1:3]))
+	for _, nodeID := range nodeIDs {
+		nodes[nodeID].publish(t, testChannel, 1, nodeIDs.Filter(func(id flow.Identifier) bool { return id != nodeID })...)
+	}
+	router.Step()
+
+	assert.Len(t, nodes[nodeIDs[0]].recorders[testChannel].received(), 1)
+	assert.Len(t, nodes[nodeIDs[1]].recorders[testChannel].received(), 2)
+	assert.Len(t, nodes[nodeIDs[2]].recorders[testChannel].received(), 2)
+	assert.Len(t, nodes[nodeIDs[3]].recorders[testChannel].received(), 3)
+	assert.Equal(t, Stats{Delivered: 8, Dropped: 4}, router.Stats())
+
+	heal()
+	nodes[nodeIDs[0]].publish(t, testChannel, 2, nodeIDs[1])
+	router.Step()
+	assert.Len(t, nodes[nodeIDs[1]].recorders[testChannel].received(), 4)
+}
+
+func TestRouter_UnknownTarget(t *testing.T) {
+	hub := stub.NewNetworkHub()
+	nodeIDs := unittest.IdentifierListFixture(1)
+	nodes := newTestNodes(t, hub, nodeIDs)
+	router := NewRouter(hub, 0)
+
+	nodes[nodeIDs[0]].publish(t, testChannel, 1, unittest.IdentifierFixture())
+	router.Step()
+	assert.Equal(t, Stats{Failed: 1}, router.Stats())
+}
@@ -0,0 +1,128 @@
+package sim
+
+import (
+	"math/rand"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+)
+
+// AnyChannel matches messages on all channels when passed to a rule constructor.
+const AnyChannel = network.Channel("")
+
+// Message is a message in transit from one node to a single target node.
+type Message struct {
+	From    flow.Identifier
+	To      flow.Identifier
+	Channel network.Channel
+	Event   interface{}
+}
+
+// Fault describes how the delivery of a message is disturbed.
+type Fault struct {
+	// Drop permanently drops the message.
+	Drop bool
+	// Delay postpones the delivery of the message by the given number of rounds.
+	Delay uint
+	// Reorder shuffles the message among the other reordered messages delivered
+	// in the same round.
+	Reorder bool
+}
+
+// merge combines two faults applying to the same message: the message is
+// dropped or reordered if any of the faults says so, and the delays add up.
+func (f Fault) merge(other Fault) Fault {
+	return Fault{
+		Drop:    f.Drop || other.Drop,
+		Delay:   f.Delay + other.Delay,
+		Reorder: f.Reorder || other.Reorder,
+	}
+}
+
+// Rule decides on the fault to apply to a message. Rules are called in the
+// order they were added to the router, and should only use the given source of
+// randomness, so that the decisions of the router are reproducible from its seed.
+type Rule func(msg *Message, rng *rand.Rand) Fault
+
+// matches returns true if the message was sent on the given channel.
+func matches(channel network.Channel, msg *Message) bool {
+	return channel == AnyChannel || channel == msg.Channel
+}
+
+// Drop drops all messages on the given channel.
+func Drop(channel network.Channel) Rule {
+	return DropRate(channel, 1)
+}
+
+// DropRate drops messages on the given channel with the given probability.
+func DropRate(channel network.Channel, rate float64) Rule {
+	return func(msg *Message, rng *rand.Rand) Fault {
+		if !matches(channel, msg) {
+			return Fault{}
+		}
+		return Fault{Drop: rng.Float64() < rate}
+	}
+}
+
+// Delay delays messages on the given channel by a random number of rounds
+// between min and max, inclusive.
+func Delay(channel network.Channel, min uint, max uint) Rule {
+	return func(msg *Message, rng *rand.Rand) Fault {
+		if !matches(channel, msg) {
+			return Fault{}
+		}
+		delay := min
+		if max > min {
+			delay += uint(rng.Intn(int(max-min) + 1))
+		}
+		return Fault{Delay: delay}
+	}
+}
+
+// Reorder shuffles the messages on the given channel delivered in the same round.
+func Reorder(channel network.Channel) Rule {
+	return func(msg *Message, _ *rand.Rand) Fault {
+		return Fault{Reorder: matches(channel, msg)}
+	}
+}
+
+// Partition drops messages on the given channel between nodes of different
+// groups. Nodes which are not part of any group can communicate with all nodes.
+func Partition(channel network.Channel, groups ...flow.IdentifierList) Rule {
+	group := make(map[flow.Identifier]int)
+	for i, nodeIDs := range groups {
+		for _, nodeID := range nodeIDs {
+			group[nodeID] = i
+		}
+	}
+	return func(msg *Message, _ *rand.Rand) Fault {
+		if !matches(channel, msg) {
+			return Fault{}
+		}
+		from, ok := group[msg.From]
+		if !ok {
+			return Fault{}
+		}
+		to, ok := group[msg.To]
+		if !ok {
+			return Fault{}
+		}
+		return Fault{Drop: from != to}
+	}
+}
+
+// Isolate drops all messages on the given channel from and to the given nodes.
+func Isolate(channel network.Channel, nodeIDs ...flow.Identifier) Rule {
+	isolated := make(map[flow.Identifier]struct{})
+	for _, nodeID := range nodeIDs {
+		isolated[nodeID] = struct{}{}
+	}
+	return func(msg *Message, _ *rand.Rand) Fault {
+		if !matches(channel, msg) {
+			return Fault{}
+		}
+		_, from := isolated[msg.From]
+		_, to := isolated[msg.To]
+		return Fault{Drop: from || to}
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/rs/zerolog"
-	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
@@ -671,10 +670,10 @@ func mockVerifierEngine(t *testing.T,
 			require.Equal(t, expected.Result.ID(), vc.Result.ID())
 			require.Equal(t, expected.Header.ID(), vc.Header.ID())
 
-			isSystemChunk := fetcher.IsSystemChunk(vc.Chunk.Index, vc.Result)
+			isSystemChunk := verification.IsSystemChunk(vc.Chunk.Index, vc.Result)
 			require.Equal(t, isSystemChunk, vc.IsSystemChunk)
 
-			endState, err := fetcher.EndStateCommitment(vc.Result, vc.Chunk.Index, isSystemChunk)
+			endState, err := verification.EndStateCommitment(vc.Result, vc.Chunk.Index, isSystemChunk)
 			require.NoError(t, err)
 
 			require.Equal(t, endState, vc.EndState)
@@ -775,7 +774,7 @@ func chunkDataPackResponseFixture(t *testing.T,
 		chunkID := chunk.ID()
 		coll, ok := collMap[chunkID]
 		// only non-system chunks must have a collection
-		require.Equal(t, ok, !fetcher.IsSystemChunk(chunk.Index, result))
+		require.Equal(t, ok, !verification.IsSystemChunk(chunk.Index, result))
 
 		chunkDataPacks[chunkID] = unittest.ChunkDataPackFixture(chunkID,
 			unittest.WithStartState(chunk.StartState),
@@ -799,11 +798,11 @@ func verifiableChunkFixture(t *testing.T, chunks flow.ChunkList, block *flow.Blo
 
 		chunkDataPack := chunkDataPacks[chunkID]
 
-		if fetcher.IsSystemChunk(chunk.Index, result) {
+		if verification.IsSystemChunk(chunk.Index, result) {
 			collMap[chunkID] = &flow.Collection{Transactions: nil}
 		}
 
-		offsetForChunk, err := fetcher.TransactionOffsetForChunk(chunks, chunk.Index)
+		offsetForChunk, err := verification.TransactionOffsetForChunk(chunks, chunk.Index)
 		require.NoError(t, err)
 
 		verifiableChunks[chunkID] = &verification.VerifiableChunkData{
@@ -866,7 +865,7 @@ func completeChunkStatusListFixture(t *testing.T, chunkCount int, statusCount in
 	locators := unittest.ChunkStatusListToChunkLocatorFixture(statuses)
 
 	for _, status := range statuses {
-		if fetcher.IsSystemChunk(status.ChunkIndex, result) {
+		if verification.IsSystemChunk(status.ChunkIndex, result) {
 			// system-chunk should have a nil collection
 			continue
 		}
@@ -875,61 +874,3 @@ func completeChunkStatusListFixture(t *testing.T, chunkCount int, statusCount in
 
 	return block, result, statuses, locators, collMap
 }
-
-func TestTransactionOffsetForChunk(t *testing.T) {
-	t.Run("first chunk index always returns zero offset", func(t *testing.T) {
-		offsetForChunk, err := fetcher.TransactionOffsetForChunk([]*flow.Chunk{nil}, 0)
-		require.NoError(t, err)
-		assert.Equal(t, uint32(0), offsetForChunk)
-	})
-
-	t.Run("offset is calculated", func(t *testing.T) {
-
-		chunksList := []*flow.Chunk{
-			{
-				ChunkBody: flow.ChunkBody{
-					NumberOfTransactions: 1,
-				},
-			},
-			{
-				ChunkBody: flow.ChunkBody{
-					NumberOfTransactions: 2,
-				},
-			},
-			{
-				ChunkBody: flow.ChunkBody{
-					NumberOfTransactions: 3,
-				},
-			},
-			{
-				ChunkBody: flow.ChunkBody{
-					NumberOfTransactions: 5,
-				},
-			},
-		}
-
-		offsetForChunk, err := fetcher.TransactionOffsetForChunk(chunksList, 0)
-		require.NoError(t, err)
-		assert.Equal(t, uint32(0), offsetForChunk)
-
-		offsetForChunk, err = fetcher.TransactionOffsetForChunk(chunksList, 1)
-		require.NoError(t, err)
-		assert.Equal(t, uint32(1), offsetForChunk)
-
-		offsetForChunk, err = fetcher.TransactionOffsetForChunk(chunksList, 2)
-		require.NoError(t, err)
-		assert.Equal(t, uint32(3), offsetForChunk)
-
-		offsetForChunk, err = fetcher.TransactionOffsetForChunk(chunksList, 3)
-		require.NoError(t, err)
-		assert.Equal(t, uint32(6), offsetForChunk)
-	})
-
-	t.Run("requesting index beyond length triggers error", func(t *testing.T) {
-
-		chunksList := make([]*flow.Chunk, 2)
-
-		_, err := fetcher.TransactionOffsetForChunk(chunksList, 2)
-		require.Error(t, err)
-	})
-}
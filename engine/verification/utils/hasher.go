@@ -12,3 +12,10 @@ func NewResultApprovalHasher() hash.Hasher {
 	h := crypto.NewBLSKMAC(encoding.ResultApprovalTag)
 	return h
 }
+
+// NewChunkChallengeHasher generates and returns a hasher for signing
+// and verification of chunk challenges
+func NewChunkChallengeHasher() hash.Hasher {
+	h := crypto.NewBLSKMAC(encoding.ChunkChallengeTag)
+	return h
+}
@@ -3,6 +3,7 @@ package verifier
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/opentracing/opentracing-go"
 	"github.com/rs/zerolog"
@@ -25,23 +26,48 @@ import (
 	"github.com/onflow/flow-go/utils/logging"
 )
 
+// DefaultChunkWorkers is the default number of chunks verified in parallel by the verifier engine.
+const DefaultChunkWorkers = 4
+
+// DefaultChunkQueueCapacity is the default maximum number of verifiable chunks waiting for a worker
+// at the verifier engine.
+const DefaultChunkQueueCapacity = 10
+
+// verificationJob represents a verifiable chunk waiting in the queue of the verifier engine. Its done
+// channel is closed once a worker finishes verifying the chunk.
+type verificationJob struct {
+	originID flow.Identifier
+	chunk    *verification.VerifiableChunkData
+	done     chan struct{}
+}
+
 // Engine (verifier engine) verifies chunks, generates result approvals or raises challenges.
 // as input it accepts verifiable chunks (chunk + all data needed) and perform verification by
 // constructing a partial trie, executing transactions and check the final state commitment and
 // other chunk meta data (e.g. tx count)
+//
+// Verifiable chunks are verified concurrently by a bounded pool of workers. Submitting a chunk
+// blocks until it is verified, and blocks longer if the queue in front of the workers is full,
+// which back-pressures the fetcher engine and, through it, the chunk consumer.
 type Engine struct {
-	unit        *engine.Unit               // used to control startup/shutdown
-	log         zerolog.Logger             // used to log relevant actions
-	metrics     module.VerificationMetrics // used to capture the performance metrics
-	tracer      module.Tracer              // used for tracing
-	pushConduit network.Conduit            // used to push result approvals
-	pullConduit network.Conduit            // used to respond to requests for result approvals
-	me          module.Local               // used to access local node information
-	state       protocol.State             // used to access the protocol state
-	rah         hash.Hasher                // used as hasher to sign the result approvals
-	chVerif     module.ChunkVerifier       // used to verify chunks
-	spockHasher hash.Hasher                // used for generating spocks
-	approvals   storage.ResultApprovals    // used to store result approvals
+	unit             *engine.Unit               // used to control startup/shutdown
+	log              zerolog.Logger             // used to log relevant actions
+	metrics          module.VerificationMetrics // used to capture the performance metrics
+	tracer           module.Tracer              // used for tracing
+	pushConduit      network.Conduit            // used to push result approvals
+	pullConduit      network.Conduit            // used to respond to requests for result approvals
+	challengeConduit network.Conduit            // used to push chunk challenges
+	me               module.Local               // used to access local node information
+	state            protocol.State             // used to access the protocol state
+	rah              hash.Hasher                // used as hasher to sign the result approvals
+	chh              hash.Hasher                // used as hasher to sign the chunk challenges
+	chVerif          module.ChunkVerifier       // used to verify chunks
+	spockHasher      hash.Hasher                // used for generating spocks
+	approvals        storage.ResultApprovals    // used to store result approvals
+	receipts         storage.ExecutionReceipts  // used to retrieve the receipts committing to a challenged result
+	challenges       storage.ChunkChallenges    // used to store chunk challenges
+	workers          uint                       // number of chunks verified in parallel
+	pending          chan *verificationJob      // bounded queue of chunks waiting for a worker
 }
 
 // New creates and returns a new instance of a verifier engine.
@@ -54,8 +80,16 @@ func New(
 	me module.Local,
 	chVerif module.ChunkVerifier,
 	approvals storage.ResultApprovals,
+	receipts storage.ExecutionReceipts,
+	challenges storage.ChunkChallenges,
+	workers uint,
+	queueCapacity uint,
 ) (*Engine, error) {
 
+	if workers == 0 {
+		return nil, fmt.Errorf("number of chunk workers must be positive")
+	}
+
 	e := &Engine{
 		unit:        engine.NewUnit(),
 		log:         log.With().Str("engine", "verifier").Logger(),
@@ -65,8 +99,13 @@ func New(
 		me:          me,
 		chVerif:     chVerif,
 		rah:         utils.NewResultApprovalHasher(),
+		chh:         utils.NewChunkChallengeHasher(),
 		spockHasher: crypto.NewBLSKMAC(encoding.SPOCKTag),
 		approvals:   approvals,
+		receipts:    receipts,
+		challenges:  challenges,
+		workers:     workers,
+		pending:     make(chan *verificationJob, queueCapacity),
 	}
 
 	var err error
@@ -80,12 +119,22 @@ func New(
 		return nil, fmt.Errorf("could not register engine on approval pull channel: %w", err)
 	}
 
+	e.challengeConduit, err = net.Register(engine.PushChallenges, e)
+	if err != nil {
+		return nil, fmt.Errorf("could not register engine on challenge push channel: %w", err)
+	}
+
 	return e, nil
 }
 
 // Ready returns a channel that is closed when the verifier engine is ready.
+// It starts the workers verifying the queued chunks.
 func (e *Engine) Ready() <-chan struct{} {
-	return e.unit.Ready()
+	return e.unit.Ready(func() {
+		for i := uint(0); i < e.workers; i++ {
+			e.unit.Launch(e.verificationLoop)
+		}
+	})
 }
 
 // Done returns a channel that is closed when the verifier engine is done.
@@ -136,7 +185,7 @@ func (e *Engine) process(originID flow.Identifier, event interface{}) error {
 
 	switch resource := event.(type) {
 	case *verification.VerifiableChunkData:
-		err = e.verifiableChunkHandler(originID, resource)
+		err = e.enqueueVerifiableChunk(originID, resource)
 	case *messages.ApprovalRequest:
 		err = e.approvalRequestHandler(originID, resource)
 	default:
@@ -153,6 +202,54 @@ func (e *Engine) process(originID flow.Identifier, event interface{}) error {
 	return nil
 }
 
+// enqueueVerifiableChunk puts the verifiable chunk in the queue of the workers and waits till
+// it is verified. It blocks while the queue is full, hence holding back its caller from
+// fetching more chunks till the workers catch up.
+func (e *Engine) enqueueVerifiableChunk(originID flow.Identifier, vc *verification.VerifiableChunkData) error {
+	job := &verificationJob{
+		originID: originID,
+		chunk:    vc,
+		done:     make(chan struct{}),
+	}
+
+	select {
+	case e.pending <- job:
+		e.metrics.SetVerifiableChunkQueueSizeAtVerifier(uint(len(e.pending)))
+	case <-e.unit.Quit():
+		return fmt.Errorf("verifier engine is shutting down, dropping chunk (index: %d)", vc.Chunk.Index)
+	}
+
+	select {
+	case <-job.done:
+	case <-e.unit.Quit():
+		return fmt.Errorf("verifier engine is shutting down, abandoning chunk (index: %d)", vc.Chunk.Index)
+	}
+
+	return nil
+}
+
+// verificationLoop is run by each worker of the engine. It picks verifiable chunks off the
+// queue and verifies them one at a time, till the engine shuts down.
+func (e *Engine) verificationLoop() {
+	for {
+		select {
+		case <-e.unit.Quit():
+			return
+		case job := <-e.pending:
+			e.metrics.SetVerifiableChunkQueueSizeAtVerifier(uint(len(e.pending)))
+
+			started := time.Now()
+			err := e.verifiableChunkHandler(job.originID, job.chunk)
+			if err != nil {
+				e.log.Warn().Err(err).Msg("could not handle verifiable chunk")
+			}
+			e.metrics.OnChunkVerifiedAtVerifier(time.Since(started))
+
+			close(job.done)
+		}
+	}
+}
+
 // verify handles the core verification process. It accepts a verifiable chunk
 // and all dependent resources, verifies the chunk, and emits a
 // result approval if applicable.
@@ -208,17 +305,14 @@ func (e *Engine) verify(ctx context.Context, originID flow.Identifier,
 			e.log.Warn().Msg(chFault.String())
 			// still create approvals for this case
 		case *chmodels.CFNonMatchingFinalState:
-			// TODO raise challenge
 			e.log.Warn().Msg(chFault.String())
-			return nil
+			return e.raiseChallenge(ctx, vc, flow.ChunkFaultNonMatchingFinalState, chFault)
 		case *chmodels.CFInvalidVerifiableChunk:
-			// TODO raise challenge
 			e.log.Error().Msg(chFault.String())
-			return nil
+			return e.raiseChallenge(ctx, vc, flow.ChunkFaultInvalidVerifiableChunk, chFault)
 		case *chmodels.CFInvalidEventsCollection:
-			// TODO raise challenge
 			e.log.Error().Msg(chFault.String())
-			return nil
+			return e.raiseChallenge(ctx, vc, flow.ChunkFaultInvalidEventsCollection, chFault)
 		default:
 			return engine.NewInvalidInputErrorf("unknown type of chunk fault is received (type: %T) : %v",
 				chFault, chFault.String())
@@ -265,6 +359,99 @@ func (e *Engine) verify(ctx context.Context, originID flow.Identifier,
 	return nil
 }
 
+// raiseChallenge packages the fault found with the chunk into a signed chunk
+// challenge, together with the receipts committing to the faulty result and a
+// reference to the chunk data pack the chunk was verified against. The challenge is persisted
+// before it is broadcast to the consensus nodes, which hold back the sealing of
+// the challenged result until the challenge is resolved.
+func (e *Engine) raiseChallenge(ctx context.Context,
+	vc *verification.VerifiableChunkData,
+	faultType flow.ChunkFaultType,
+	chFault chmodels.ChunkFault,
+) error {
+	resultID := vc.Result.ID()
+	log := e.log.With().
+		Hex("result_id", logging.ID(resultID)).
+		Uint64("chunk_index", vc.Chunk.Index).
+		Str("fault_type", string(faultType)).
+		Logger()
+
+	span, _ := e.tracer.StartSpanFromContext(ctx, trace.VERVerGenerateChunkChallenge)
+	challenge, err := e.GenerateChunkChallenge(vc, faultType, chFault)
+	span.Finish()
+	if err != nil {
+		return fmt.Errorf("couldn't generate a chunk challenge: %w", err)
+	}
+
+	err = e.challenges.Store(challenge)
+	if err != nil {
+		return fmt.Errorf("could not store chunk challenge: %w", err)
+	}
+
+	consensusNodes, err := e.state.Final().
+		Identities(filter.HasRole(flow.RoleConsensus))
+	if err != nil {
+		return fmt.Errorf("could not load consensus node IDs: %w", err)
+	}
+
+	// broadcast chunk challenge to the consensus nodes
+	err = e.challengeConduit.Publish(challenge, consensusNodes.NodeIDs()...)
+	if err != nil {
+		return fmt.Errorf("could not submit chunk challenge: %w", err)
+	}
+	log.Warn().
+		Hex("challenge_id", logging.ID(challenge.ID())).
+		Msg("chunk challenge submitted")
+
+	return nil
+}
+
+// GenerateChunkChallenge generates a signed chunk challenge for the fault found
+// with the given verifiable chunk.
+func (e *Engine) GenerateChunkChallenge(vc *verification.VerifiableChunkData,
+	faultType flow.ChunkFaultType,
+	chFault chmodels.ChunkFault,
+) (*flow.ChunkChallenge, error) {
+
+	resultID := vc.Result.ID()
+
+	// collects the receipts of the execution nodes committing to the result
+	receipts, err := e.receipts.ByBlockID(vc.Header.ID())
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve receipts for block: %w", err)
+	}
+	metas := make([]*flow.ExecutionReceiptMeta, 0, len(receipts))
+	for _, receipt := range receipts {
+		if receipt.ExecutionResult.ID() != resultID {
+			continue
+		}
+		metas = append(metas, receipt.Meta())
+	}
+
+	body := flow.ChunkChallengeBody{
+		ChallengerID:    e.me.NodeID(),
+		BlockID:         vc.Header.ID(),
+		ExecutionResult: *vc.Result,
+		ChunkIndex:      vc.Chunk.Index,
+		FaultType:       faultType,
+		Fault:           chFault.String(),
+		Receipts:        metas,
+		ChunkDataPackID: flow.ChunkDataPackFingerprint(vc.ChunkDataPack),
+	}
+
+	// generates a signature over the challenge body
+	bodyID := body.ID()
+	bodySign, err := e.me.Sign(bodyID[:], e.chh)
+	if err != nil {
+		return nil, fmt.Errorf("could not sign chunk challenge body: %w", err)
+	}
+
+	return &flow.ChunkChallenge{
+		Body:                body,
+		ChallengerSignature: bodySign,
+	}, nil
+}
+
 // GenerateResultApproval generates result approval for specific chunk of an execution receipt.
 func (e *Engine) GenerateResultApproval(chunkIndex uint64,
 	execResultID flow.Identifier,
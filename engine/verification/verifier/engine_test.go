@@ -4,7 +4,9 @@ import (
 	"crypto/rand"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/mock"
@@ -32,18 +34,21 @@ import (
 
 type VerifierEngineTestSuite struct {
 	suite.Suite
-	net       *mockmodule.Network
-	tracer    realModule.Tracer
-	state     *protocol.State
-	ss        *protocol.Snapshot
-	me        *mocklocal.MockLocal
-	sk        crypto.PrivateKey
-	hasher    hash.Hasher
-	chain     flow.Chain
-	pushCon   *mocknetwork.Conduit // mocks con for submitting result approvals
-	pullCon   *mocknetwork.Conduit
-	metrics   *mockmodule.VerificationMetrics // mocks performance monitoring metrics
-	approvals *mockstorage.ResultApprovals
+	net        *mockmodule.Network
+	tracer     realModule.Tracer
+	state      *protocol.State
+	ss         *protocol.Snapshot
+	me         *mocklocal.MockLocal
+	sk         crypto.PrivateKey
+	hasher     hash.Hasher
+	chain      flow.Chain
+	pushCon    *mocknetwork.Conduit // mocks con for submitting result approvals
+	pullCon    *mocknetwork.Conduit
+	chCon      *mocknetwork.Conduit            // mocks con for submitting chunk challenges
+	metrics    *mockmodule.VerificationMetrics // mocks performance monitoring metrics
+	approvals  *mockstorage.ResultApprovals
+	receipts   *mockstorage.ExecutionReceipts
+	challenges *mockstorage.ChunkChallenges
 }
 
 func TestVerifierEngine(t *testing.T) {
@@ -58,6 +63,7 @@ func (suite *VerifierEngineTestSuite) SetupTest() {
 	suite.ss = &protocol.Snapshot{}
 	suite.pushCon = &mocknetwork.Conduit{}
 	suite.pullCon = &mocknetwork.Conduit{}
+	suite.chCon = &mocknetwork.Conduit{}
 	suite.metrics = &mockmodule.VerificationMetrics{}
 	suite.chain = flow.Testnet.Chain()
 	suite.approvals = &mockstorage.ResultApprovals{}
@@ -65,6 +71,11 @@ func (suite *VerifierEngineTestSuite) SetupTest() {
 	suite.approvals.On("Store", mock.Anything).Return(nil)
 	suite.approvals.On("Index", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
+	suite.receipts = &mockstorage.ExecutionReceipts{}
+	suite.receipts.On("ByBlockID", mock.Anything).Return(flow.ExecutionReceiptList{}, nil)
+	suite.challenges = &mockstorage.ChunkChallenges{}
+	suite.challenges.On("Store", mock.Anything).Return(nil)
+
 	suite.net.On("Register", engine.PushApprovals, testifymock.Anything).
 		Return(suite.pushCon, nil).
 		Once()
@@ -73,8 +84,15 @@ func (suite *VerifierEngineTestSuite) SetupTest() {
 		Return(suite.pullCon, nil).
 		Once()
 
+	suite.net.On("Register", engine.PushChallenges, testifymock.Anything).
+		Return(suite.chCon, nil).
+		Once()
+
 	suite.state.On("Final").Return(suite.ss)
 
+	suite.metrics.On("SetVerifiableChunkQueueSizeAtVerifier", mock.Anything).Return()
+	suite.metrics.On("OnChunkVerifiedAtVerifier", mock.Anything).Return()
+
 	// Mocks the signature oracle of the engine
 	//
 	// generates signing and verification keys
@@ -98,6 +116,11 @@ func (suite *VerifierEngineTestSuite) SetupTest() {
 }
 
 func (suite *VerifierEngineTestSuite) TestNewEngine() *verifier.Engine {
+	return suite.newEngine(ChunkVerifierMock{}, verifier.DefaultChunkWorkers, verifier.DefaultChunkQueueCapacity)
+}
+
+// newEngine creates a verifier engine with the given chunk verifier and worker pool, and starts it.
+func (suite *VerifierEngineTestSuite) newEngine(chVerif realModule.ChunkVerifier, workers uint, queueCapacity uint) *verifier.Engine {
 	e, err := verifier.New(
 		zerolog.Logger{},
 		suite.metrics,
@@ -105,13 +128,17 @@ func (suite *VerifierEngineTestSuite) TestNewEngine() *verifier.Engine {
 		suite.net,
 		suite.state,
 		suite.me,
-		ChunkVerifierMock{},
-		suite.approvals)
+		chVerif,
+		suite.approvals,
+		suite.receipts,
+		suite.challenges,
+		workers,
+		queueCapacity)
 	require.Nil(suite.T(), err)
 
 	suite.net.AssertExpectations(suite.T())
+	unittest.RequireCloseBefore(suite.T(), e.Ready(), time.Second, "could not start verifier engine on time")
 	return e
-
 }
 
 func (suite *VerifierEngineTestSuite) TestIncorrectResult() {
@@ -185,12 +212,27 @@ func (suite *VerifierEngineTestSuite) TestVerifyUnhappyPaths() {
 		On("Publish", testifymock.Anything, testifymock.Anything).
 		Return(nil).
 		Run(func(args testifymock.Arguments) {
-			// TODO change this to check challeneges
 			_, ok := args[0].(*flow.ResultApproval)
 			// TODO change this to false when missing register is rolled back
 			suite.Assert().True(ok)
 		})
 
+	// the invalid verifiable chunk and the non-matching final state are challenged
+	challengeHasher := utils.NewChunkChallengeHasher()
+	suite.chCon.
+		On("Publish", testifymock.Anything, testifymock.Anything).
+		Return(nil).
+		Run(func(args testifymock.Arguments) {
+			challenge, ok := args[0].(*flow.ChunkChallenge)
+			suite.Require().True(ok)
+			suite.Assert().Equal(myID, challenge.Body.ChallengerID)
+
+			// verifies the signature
+			bodyID := challenge.Body.ID()
+			suite.Assert().True(suite.sk.PublicKey().Verify(challenge.ChallengerSignature, bodyID[:], challengeHasher))
+		}).
+		Twice()
+
 	// emission of result approval
 	suite.metrics.On("OnResultApprovalDispatchedInNetworkByVerifier").Return()
 
@@ -206,6 +248,123 @@ func (suite *VerifierEngineTestSuite) TestVerifyUnhappyPaths() {
 		err := eng.ProcessLocal(test.vc)
 		suite.Assert().NoError(err)
 	}
+	suite.chCon.AssertExpectations(suite.T())
+	suite.challenges.AssertNumberOfCalls(suite.T(), "Store", 2)
+}
+
+// TestVerifyConcurrently evaluates that the verifier engine verifies as many chunks in parallel
+// as it has workers.
+func (suite *VerifierEngineTestSuite) TestVerifyConcurrently() {
+	workers := 4
+	chVerif := newBlockingChunkVerifier()
+	eng := suite.newEngine(chVerif, uint(workers), 0)
+	suite.me.MockNodeID(unittest.IdentifierFixture())
+	suite.metrics.On("OnVerifiableChunkReceivedAtVerifierEngine").Return()
+
+	verified := sync.WaitGroup{}
+	verified.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func(index int) {
+			defer verified.Done()
+			err := eng.ProcessLocal(unittest.VerifiableChunkDataFixture(uint64(index)))
+			suite.Assert().NoError(err)
+		}(i)
+	}
+
+	// all chunks should be under verification at the same time, before any of them is done.
+	for i := 0; i < workers; i++ {
+		select {
+		case <-chVerif.started:
+		case <-time.After(time.Second):
+			suite.FailNow("chunks are not verified concurrently")
+		}
+	}
+
+	close(chVerif.release)
+	unittest.RequireReturnsBefore(suite.T(), verified.Wait, time.Second, "could not verify chunks on time")
+	suite.metrics.AssertNumberOfCalls(suite.T(), "OnChunkVerifiedAtVerifier", workers)
+}
+
+// TestVerifyBackPressure evaluates that once all workers of the verifier engine are busy and its queue
+// is full, submitting a chunk blocks till a worker is freed up.
+func (suite *VerifierEngineTestSuite) TestVerifyBackPressure() {
+	chVerif := newBlockingChunkVerifier()
+	eng := suite.newEngine(chVerif, 1, 1)
+	suite.me.MockNodeID(unittest.IdentifierFixture())
+	suite.metrics.On("OnVerifiableChunkReceivedAtVerifierEngine").Return()
+
+	chunks := 3
+	verified := sync.WaitGroup{}
+	verified.Add(chunks)
+	for i := 0; i < chunks; i++ {
+		go func(index int) {
+			defer verified.Done()
+			err := eng.ProcessLocal(unittest.VerifiableChunkDataFixture(uint64(index)))
+			suite.Assert().NoError(err)
+		}(i)
+	}
+
+	// only one chunk is picked up by the single worker, and the queue never grows beyond its capacity.
+	<-chVerif.started
+	select {
+	case <-chVerif.started:
+		suite.FailNow("more chunks verified than workers")
+	case <-time.After(100 * time.Millisecond):
+	}
+	suite.metrics.AssertNotCalled(suite.T(), "SetVerifiableChunkQueueSizeAtVerifier", uint(2))
+
+	close(chVerif.release)
+	unittest.RequireReturnsBefore(suite.T(), verified.Wait, time.Second, "could not verify chunks on time")
+}
+
+// TestVerifyShutdown evaluates that submitting chunks to the verifier engine does not block
+// once the engine is shutting down.
+func (suite *VerifierEngineTestSuite) TestVerifyShutdown() {
+	chVerif := newBlockingChunkVerifier()
+	eng := suite.newEngine(chVerif, 1, 0)
+	suite.me.MockNodeID(unittest.IdentifierFixture())
+	suite.metrics.On("OnVerifiableChunkReceivedAtVerifierEngine").Return()
+
+	verified := sync.WaitGroup{}
+	verified.Add(2)
+	for i := 0; i < 2; i++ {
+		go func(index int) {
+			defer verified.Done()
+			_ = eng.ProcessLocal(unittest.VerifiableChunkDataFixture(uint64(index)))
+		}(i)
+	}
+	<-chVerif.started
+
+	done := eng.Done()
+	unittest.RequireReturnsBefore(suite.T(), verified.Wait, time.Second, "chunk submission blocked on shutdown")
+
+	close(chVerif.release)
+	unittest.RequireCloseBefore(suite.T(), done, time.Second, "could not stop verifier engine on time")
+}
+
+// blockingChunkVerifier is a chunk verifier that signals on started whenever it starts verifying a
+// chunk, and blocks till release is closed. It fails the verification of all chunks, so that no
+// result approval is emitted.
+type blockingChunkVerifier struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func newBlockingChunkVerifier() *blockingChunkVerifier {
+	return &blockingChunkVerifier{
+		started: make(chan struct{}, 100),
+		release: make(chan struct{}),
+	}
+}
+
+func (v *blockingChunkVerifier) Verify(vc *verification.VerifiableChunkData) ([]byte, chmodel.ChunkFault, error) {
+	v.started <- struct{}{}
+	<-v.release
+	return nil, nil, fmt.Errorf("blocking chunk verifier does not verify chunks")
+}
+
+func (v *blockingChunkVerifier) SystemChunkVerify(vc *verification.VerifiableChunkData) ([]byte, chmodel.ChunkFault, error) {
+	return v.Verify(vc)
 }
 
 type ChunkVerifierMock struct {
@@ -33,6 +33,7 @@ type Config struct {
 	dataDir        string              // directory to store the protocol state (if the badger storage is not provided)
 	bootstrapDir   string              // path to the bootstrap directory
 	logLevel       string              // log level
+	pruning        uint64              // number of finalized blocks to keep, 0 disables pruning
 }
 
 type Option func(c *Config)
@@ -68,6 +69,14 @@ func WithDB(db *badger.DB) Option {
 	}
 }
 
+// WithProtocolPruning enables pruning of the protocol state, keeping only the
+// given number of finalized blocks below the latest finalized block.
+func WithProtocolPruning(retention uint64) Option {
+	return func(cf *Config) {
+		cf.pruning = retention
+	}
+}
+
 // BootstrapNodeInfo contains the details about the upstream bootstrap peer the consensus follower uses
 type BootstrapNodeInfo struct {
 	Host             string // ip or hostname
@@ -94,6 +103,7 @@ func getAccessNodeOptions(config *Config) []access.Option {
 		access.WithBootStrapPeers(ids...),
 		access.WithBaseOptions(getBaseOptions(config)),
 		access.WithNetworkKey(config.networkPrivKey),
+		access.WithProtocolPruning(config.pruning),
 	}
 }
 
@@ -154,3 +154,24 @@ func (p *Programs) Cleanup(changedContracts []ContractUpdateKey) {
 		}
 	}
 }
+
+// MergeChild applies the changes captured by the given child programs to these
+// programs, as if the child's operations had been performed on these programs
+// directly.
+func (p *Programs) MergeChild(child *Programs) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	child.lock.RLock()
+	defer child.lock.RUnlock()
+
+	if child.cleaned {
+		p.cleaned = true
+		p.parentFunc = emptyProgramGetFunc
+		p.programs = make(map[common.LocationID]ProgramEntry)
+	}
+
+	for id, entry := range child.programs {
+		p.programs[id] = entry
+	}
+}
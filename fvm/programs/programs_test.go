@@ -169,4 +169,38 @@ func Test_Programs(t *testing.T) {
 		require.True(t, child.HasChanges())
 	})
 
+	t.Run("merge child", func(t *testing.T) {
+		parent := NewEmptyPrograms()
+		parent.Set(someLocation, someProgram, newState)
+
+		child := parent.ChildPrograms()
+		child.Set(addressLocation, &interpreter.Program{}, newState)
+		child.Cleanup(nil)
+
+		parent.MergeChild(child)
+
+		retrieved, _, has := parent.Get(addressLocation)
+		require.NotNil(t, retrieved)
+		require.True(t, has)
+
+		retrieved, _, has = parent.Get(someLocation)
+		require.NotNil(t, retrieved)
+		require.True(t, has)
+
+		// cleaning the child with changed contracts cleans the parent
+		child = parent.ChildPrograms()
+		child.Cleanup([]ContractUpdateKey{{}})
+
+		parent.MergeChild(child)
+		require.True(t, parent.HasChanges())
+
+		retrieved, _, has = parent.Get(addressLocation)
+		require.Nil(t, retrieved)
+		require.False(t, has)
+
+		retrieved, _, has = parent.Get(someLocation)
+		require.Nil(t, retrieved)
+		require.False(t, has)
+	})
+
 }
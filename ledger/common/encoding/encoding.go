@@ -46,12 +46,15 @@ const (
 	TypeUpdate
 	// TypeTrieUpdate - type for trie update
 	TypeTrieUpdate
+	// TypeSubTrieProof - type for SubTrieProofs
+	// (all data needed to verify a subtrie at specific state)
+	TypeSubTrieProof
 	// this is used to flag types from the future
 	typeUnsuported
 )
 
 func (e Type) String() string {
-	return [...]string{"Unknown", "State", "KeyPart", "Key", "Value", "Path", "Payload", "Proof", "BatchProof", "Query", "Update", "Trie Update"}[e]
+	return [...]string{"Unknown", "State", "KeyPart", "Key", "Value", "Path", "Payload", "Proof", "BatchProof", "Query", "Update", "Trie Update", "SubTrie Proof"}[e]
 }
 
 // CheckVersion extracts encoding bytes from a raw encoded message
@@ -699,6 +702,130 @@ func decodeTrieProof(inp []byte) (*ledger.TrieProof, error) {
 	return pInst, nil
 }
 
+// EncodeSubTrieProof encodes the content of a subtrie proof into a byte slice
+func EncodeSubTrieProof(p *ledger.SubTrieProof) []byte {
+	if p == nil {
+		return []byte{}
+	}
+	// encode version
+	buffer := utils.AppendUint16([]byte{}, Version)
+
+	// encode proof entity type
+	buffer = utils.AppendUint8(buffer, TypeSubTrieProof)
+
+	// append encoded proof content
+	proof := encodeSubTrieProof(p)
+	buffer = append(buffer, proof[:]...)
+
+	return buffer
+}
+
+func encodeSubTrieProof(p *ledger.SubTrieProof) []byte {
+	// steps are encoded as two bytes, as a subtrie can be at depth NodeMaxHeight
+	buffer := utils.AppendUint16([]byte{}, p.Steps)
+
+	// include flags size and content
+	buffer = utils.AppendUint8(buffer, uint8(len(p.Flags)))
+	buffer = append(buffer, p.Flags...)
+
+	// include path size and content
+	buffer = utils.AppendUint16(buffer, uint16(ledger.PathLen))
+	buffer = append(buffer, p.Path[:]...)
+
+	// and finally include all interims (hash values)
+	// number of interims
+	buffer = utils.AppendUint16(buffer, uint16(len(p.Interims)))
+	for _, inter := range p.Interims {
+		buffer = utils.AppendUint16(buffer, uint16(len(inter)))
+		buffer = append(buffer, inter[:]...)
+	}
+
+	return buffer
+}
+
+// DecodeSubTrieProof constructs a subtrie proof from an encoded byte slice
+func DecodeSubTrieProof(encodedProof []byte) (*ledger.SubTrieProof, error) {
+	// check the enc dec version
+	rest, _, err := CheckVersion(encodedProof)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding subtrie proof: %w", err)
+	}
+	// check the encoding type
+	rest, err = CheckType(rest, TypeSubTrieProof)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding subtrie proof: %w", err)
+	}
+	return decodeSubTrieProof(rest)
+}
+
+func decodeSubTrieProof(inp []byte) (*ledger.SubTrieProof, error) {
+	pInst := ledger.NewSubTrieProof()
+
+	// read steps
+	steps, rest, err := utils.ReadUint16(inp)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding subtrie proof: %w", err)
+	}
+	pInst.Steps = steps
+
+	// read flags
+	flagsSize, rest, err := utils.ReadUint8(rest)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding subtrie proof: %w", err)
+	}
+	flags, rest, err := utils.ReadSlice(rest, int(flagsSize))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding subtrie proof: %w", err)
+	}
+	pInst.Flags = flags
+
+	// read path
+	pathSize, rest, err := utils.ReadUint16(rest)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding subtrie proof: %w", err)
+	}
+	path, rest, err := utils.ReadSlice(rest, int(pathSize))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding subtrie proof: %w", err)
+	}
+	pInst.Path, err = ledger.ToPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding subtrie proof: %w", err)
+	}
+
+	// read interims
+	interimsLen, rest, err := utils.ReadUint16(rest)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding subtrie proof: %w", err)
+	}
+	interims := make([]hash.Hash, 0, interimsLen)
+
+	var interimSize uint16
+	var interim hash.Hash
+	var interimBytes []byte
+
+	for i := 0; i < int(interimsLen); i++ {
+		interimSize, rest, err = utils.ReadUint16(rest)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding subtrie proof: %w", err)
+		}
+
+		interimBytes, rest, err = utils.ReadSlice(rest, int(interimSize))
+		if err != nil {
+			return nil, fmt.Errorf("error decoding subtrie proof: %w", err)
+		}
+		interim, err = hash.ToHash(interimBytes)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding subtrie proof: %w", err)
+		}
+
+		interims = append(interims, interim)
+	}
+	pInst.Interims = interims
+
+	return pInst, nil
+}
+
 // EncodeTrieBatchProof encodes a batch proof into a byte slice
 func EncodeTrieBatchProof(bp *ledger.TrieBatchProof) []byte {
 	if bp == nil {
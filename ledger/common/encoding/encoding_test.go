@@ -7,6 +7,7 @@ import (
 
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/common/hash"
 	"github.com/onflow/flow-go/ledger/common/utils"
 )
 
@@ -89,6 +90,23 @@ func Test_BatchProofEncodingDecoding(t *testing.T) {
 	require.True(t, newbp.Equals(bp))
 }
 
+// Test_SubTrieProofEncodingDecoding tests encoding decoding functionality of a subtrie proof
+func Test_SubTrieProofEncodingDecoding(t *testing.T) {
+	p := ledger.NewSubTrieProof()
+	p.Path = utils.PathByUint16(0xa000)
+	p.Steps = 4
+	p.Flags[0] = 0x50
+	p.Interims = append(p.Interims, hash.HashLeaf(hash.Hash(p.Path), []byte("a")), hash.HashLeaf(hash.Hash(p.Path), []byte("b")))
+
+	encoded := encoding.EncodeSubTrieProof(p)
+	newp, err := encoding.DecodeSubTrieProof(encoded)
+	require.NoError(t, err)
+	require.Equal(t, p, newp)
+
+	_, err = encoding.DecodeTrieProof(encoded)
+	require.Error(t, err)
+}
+
 // Test_TrieUpdateEncodingDecoding tests encoding decoding functionality of a trie update
 func Test_TrieUpdateEncodingDecoding(t *testing.T) {
 
@@ -17,28 +17,59 @@ func VerifyTrieProof(p *ledger.TrieProof, expectedState ledger.State) bool {
 		return false
 	}
 	// We start with the leaf and hash our way upwards towards the root
-	proofIndex := len(p.Interims) - 1                                                      // the index of the last non-default value furthest down the tree (-1 if there is none)
 	computed := ledger.ComputeCompactValue(hash.Hash(p.Path), p.Payload.Value, leafHeight) // we first compute the hash of the fully-expanded leaf (at height 0)
-	for h := leafHeight + 1; h <= treeHeight; h++ {                                        // then, we hash our way upwards until we hit the root (at height `treeHeight`)
+	computed, ok := computeRootHash(p.Path, p.Flags, p.Interims, leafHeight, computed)
+	if !ok {
+		return false
+	}
+	return (computed == hash.Hash(expectedState)) == p.Inclusion
+}
+
+// VerifySubTrieProof verifies that the subtrie with the given root hash is part of
+// the trie with the expected state, at the path and depth of the proof, by
+// constructing all the hash from the subtrie root to the trie root.
+func VerifySubTrieProof(p *ledger.SubTrieProof, subTrieHash hash.Hash, expectedState ledger.State) bool {
+	treeHeight := ledger.NodeMaxHeight
+	subTrieHeight := treeHeight - int(p.Steps)
+	if !(0 <= subTrieHeight && subTrieHeight <= treeHeight) { // sanity check
+		return false
+	}
+	if len(p.Flags) != ledger.PathLen {
+		return false
+	}
+	computed, ok := computeRootHash(p.Path, p.Flags, p.Interims, subTrieHeight, subTrieHash)
+	if !ok {
+		return false
+	}
+	return computed == hash.Hash(expectedState)
+}
+
+// computeRootHash hashes its way up from the node at the given height on the given
+// path, whose hash is `computed`, to the trie root, using the sibling hashes
+// defined by the flags and interims. It returns false if there are too few interims.
+func computeRootHash(path ledger.Path, flags []byte, interims []hash.Hash, height int, computed hash.Hash) (hash.Hash, bool) {
+	treeHeight := ledger.NodeMaxHeight
+	proofIndex := len(interims) - 1             // the index of the last non-default value furthest down the tree (-1 if there is none)
+	for h := height + 1; h <= treeHeight; h++ { // then, we hash our way upwards until we hit the root (at height `treeHeight`)
 		// we are currently at a node n (initially the leaf). In this iteration, we want to compute the
 		// parent's hash. Here, h is the height of the parent, whose hash want to compute.
 		// The parent has two children: child n, whose hash we have already computed (aka `computed`);
 		// and the sibling to node n, whose hash (aka `siblingHash`) must be defined by the Proof.
 
 		var siblingHash hash.Hash
-		flag := bitutils.Bit(p.Flags, treeHeight-h)
+		flag := bitutils.Bit(flags, treeHeight-h)
 
 		if flag == 1 { // if flag is set, siblingHash is stored in the proof
 			if proofIndex < 0 { // proof invalid: too few values
-				return false
+				return computed, false
 			}
-			siblingHash = p.Interims[proofIndex]
+			siblingHash = interims[proofIndex]
 			proofIndex--
 		} else { // otherwise, siblingHash is a default hash
 			siblingHash = ledger.GetDefaultHashForHeight(h - 1)
 		}
 
-		bit := bitutils.Bit(p.Path[:], treeHeight-h)
+		bit := bitutils.Bit(path[:], treeHeight-h)
 		// hashing is order dependent
 		if bit == 1 { // we hash our way up to the parent along the parent's right branch
 			computed = hash.HashInterNode(siblingHash, computed)
@@ -46,7 +77,7 @@ func VerifyTrieProof(p *ledger.TrieProof, expectedState ledger.State) bool {
 			computed = hash.HashInterNode(computed, siblingHash)
 		}
 	}
-	return (computed == hash.Hash(expectedState)) == p.Inclusion
+	return computed, true
 }
 
 // VerifyTrieBatchProof verifies all the proof inside the batchproof
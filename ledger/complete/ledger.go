@@ -32,6 +32,7 @@ const DefaultPathFinderVersion = 1
 // In order to limit the memory usage and maintain the performance storage only keeps a limited number of
 // tries and purge the old ones (LRU-based); in other words, Ledger is not designed to be used
 // for archival usage but make it possible for other software components to reconstruct very old tries using write-ahead logs.
+// If the write-ahead log has a trie store, purged tries are persisted to disk and reloaded when queried again.
 type Ledger struct {
 	forest            *mtrie.Forest
 	wal               wal.LedgerWAL
@@ -48,9 +49,10 @@ func NewLedger(
 	log zerolog.Logger,
 	pathFinderVer uint8) (*Ledger, error) {
 
-	forest, err := mtrie.NewForest(capacity, metrics, func(evictedTrie *trie.MTrie) error {
+	// if the WAL has a trie store, evicted tries are persisted and reloaded on demand
+	forest, err := mtrie.NewForestWithTrieStore(capacity, metrics, func(evictedTrie *trie.MTrie) error {
 		return wal.RecordDelete(evictedTrie.RootHash())
-	})
+	}, wal.TrieStore(), log)
 	if err != nil {
 		return nil, fmt.Errorf("cannot create forest: %w", err)
 	}
@@ -225,6 +227,25 @@ func (l *Ledger) ForestSize() int {
 	return l.forest.Size()
 }
 
+// Pin exempts the trie of the given state from eviction until it is unpinned.
+func (l *Ledger) Pin(state ledger.State) error {
+	return l.forest.Pin(ledger.RootHash(state))
+}
+
+// Unpin releases a pin on the trie of the given state.
+func (l *Ledger) Unpin(state ledger.State) {
+	l.forest.Unpin(ledger.RootHash(state))
+}
+
+// Trie returns the trie of the given state
+func (l *Ledger) Trie(state ledger.State) (*trie.MTrie, error) {
+	t, err := l.forest.GetTrie(ledger.RootHash(state))
+	if err != nil {
+		return nil, fmt.Errorf("cannot find the trie for state %s: %w", state, err)
+	}
+	return t, nil
+}
+
 // Checkpointer returns a checkpointer instance
 func (l *Ledger) Checkpointer() (*wal.Checkpointer, error) {
 	checkpointer, err := l.wal.NewCheckpointer()
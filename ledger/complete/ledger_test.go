@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"path"
 	"sync"
 	"testing"
 	"time"
@@ -644,3 +645,80 @@ func migrationByKey(p []ledger.Payload) ([]ledger.Payload, error) {
 	}
 	return ret, nil
 }
+
+// TestLedger_TrieStore tests that tries evicted from the forest are reloaded
+// from the trie store, both before and after restarting the ledger.
+func TestLedger_TrieStore(t *testing.T) {
+	capacity := 3
+	steps := 10
+	metricsCollector := &metrics.NoopCollector{}
+
+	unittest.RunWithTempDir(t, func(dir string) {
+
+		newLedger := func() (*wal.DiskWAL, *complete.Ledger) {
+			diskWal, err := wal.NewDiskWAL(zerolog.Nop(), nil, metricsCollector, dir, capacity, pathfinder.PathByteSize, wal.SegmentSize)
+			require.NoError(t, err)
+
+			trieStore, err := wal.NewDiskTrieStore(zerolog.Nop(), path.Join(dir, wal.DefaultTrieStoreDir))
+			require.NoError(t, err)
+			diskWal.SetTrieStore(trieStore)
+
+			led, err := complete.NewLedger(diskWal, capacity, metricsCollector, zerolog.Nop(), complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+			return diskWal, led
+		}
+
+		diskWal, led := newLedger()
+
+		states := make([]ledger.State, 0, steps)
+		updates := make([]*ledger.Update, 0, steps)
+		state := led.InitialState()
+		for i := 0; i < steps; i++ {
+			keys := utils.RandomUniqueKeys(2, 2, 1, 10)
+			values := utils.RandomValues(2, 1, 32)
+			update, err := ledger.NewUpdate(state, keys, values)
+			require.NoError(t, err)
+
+			state, _, err = led.Set(update)
+			require.NoError(t, err)
+
+			states = append(states, state)
+			updates = append(updates, update)
+		}
+		require.Equal(t, capacity, led.ForestSize())
+
+		requireReadable := func(led *complete.Ledger) {
+			for i, state := range states {
+				query, err := ledger.NewQuery(state, updates[i].Keys())
+				require.NoError(t, err)
+
+				values, err := led.Get(query)
+				require.NoError(t, err)
+				assert.Equal(t, updates[i].Values(), values)
+
+				_, err = led.Prove(query)
+				require.NoError(t, err)
+			}
+		}
+
+		// the oldest states have been evicted, and are reloaded on demand
+		requireReadable(led)
+		assert.Equal(t, capacity, led.ForestSize())
+
+		// updates can be applied to evicted states
+		update, err := ledger.NewUpdate(states[0], updates[1].Keys(), updates[1].Values())
+		require.NoError(t, err)
+		_, _, err = led.Set(update)
+		require.NoError(t, err)
+
+		<-diskWal.Done()
+		<-led.Done()
+
+		// after restarting, the states evicted in the previous run are reloaded
+		diskWal, led = newLedger()
+		requireReadable(led)
+
+		<-diskWal.Done()
+		<-led.Done()
+	})
+}
@@ -66,12 +66,17 @@ type NodeIterator struct {
 // When re-building the Trie from the sequence of nodes, one can build the trie on the fly,
 // as for each node, the children have been previously encountered.
 func NewNodeIterator(mTrie *trie.MTrie) *NodeIterator {
+	return newNodeIterator(mTrie.RootNode())
+}
+
+// newNodeIterator returns a NodeIterator over all nodes of the (sub)trie with the given root.
+func newNodeIterator(root *node.Node) *NodeIterator {
 	// for a Trie with height H (measured by number of edges), the longest possible path contains H+1 vertices
 	stackSize := ledger.NodeMaxHeight + 1
 	i := &NodeIterator{
 		stack: make([]*node.Node, 0, stackSize),
 	}
-	i.unprocessedRoot = mTrie.RootNode()
+	i.unprocessedRoot = root
 	return i
 }
 
@@ -3,6 +3,8 @@ package flattener
 import (
 	"fmt"
 
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
 )
@@ -25,14 +27,43 @@ func (ft *FlattenedTrie) ToFlattenedForestWithASingleTrie() *FlattenedForest {
 
 // FlattenTrie returns the trie as a FlattenedTrie, which contains all nodes of that trie.
 func FlattenTrie(trie *trie.MTrie) (*FlattenedTrie, error) {
+	storableNodes, allNodes, err := flattenNodes(trie.RootNode())
+	if err != nil {
+		return nil, err
+	}
+	// fix root nodes indices
+	// since we indexed all nodes, root must be present
+	storableTrie, err := toStorableTrie(trie, allNodes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to construct storable trie: %w", err)
+	}
+
+	return &FlattenedTrie{
+		Nodes: storableNodes,
+		Trie:  storableTrie,
+	}, nil
+}
+
+// FlattenSubTrie returns all nodes of the subtrie with the given root as a sequence of
+// StorableNodes satisfying the Descendents-First-Relationship. Hence, the root is the
+// last node of the sequence. Contrary to FlattenTrie, the 0th element (nil) is not included.
+func FlattenSubTrie(root *node.Node) ([]*StorableNode, error) {
+	storableNodes, _, err := flattenNodes(root)
+	if err != nil {
+		return nil, err
+	}
+	return storableNodes[1:], nil
+}
+
+func flattenNodes(root *node.Node) ([]*StorableNode, node2indexMap, error) {
 	storableNodes := []*StorableNode{nil} // 0th element is nil
 
 	// assign unique value to every node
-	allNodes := make(map[*node.Node]uint64)
+	allNodes := make(node2indexMap)
 	allNodes[nil] = 0 // 0th element is nil
 
 	counter := uint64(1) // start from 1, as 0 marks nil
-	for itr := NewNodeIterator(trie); itr.Next(); {
+	for itr := newNodeIterator(root); itr.Next(); {
 		n := itr.Value()
 		// if node not in map
 		if _, has := allNodes[n]; !has {
@@ -40,22 +71,85 @@ func FlattenTrie(trie *trie.MTrie) (*FlattenedTrie, error) {
 			counter++
 			storableNode, err := toStorableNode(n, allNodes)
 			if err != nil {
-				return nil, fmt.Errorf("failed to construct storable node: %w", err)
+				return nil, nil, fmt.Errorf("failed to construct storable node: %w", err)
 			}
 			storableNodes = append(storableNodes, storableNode)
 		}
 	}
-	// fix root nodes indices
-	// since we indexed all nodes, root must be present
-	storableTrie, err := toStorableTrie(trie, allNodes)
-	if err != nil {
-		return nil, fmt.Errorf("failed to construct storable trie: %w", err)
+	return storableNodes, allNodes, nil
+}
+
+// RebuildSubTrie constructs the subtrie of the given height from a sequence of
+// StorableNodes, as returned by FlattenSubTrie. Contrary to RebuildNodes, the
+// hashes, max depths and register counts of the storables are not trusted but
+// re-computed, and the structure of the subtrie is validated. The caller is
+// responsible for checking the hash of the returned root. An empty sequence
+// denotes the empty subtrie, for which nil is returned.
+func RebuildSubTrie(storableNodes []*StorableNode, height int) (*node.Node, error) {
+	if height < 0 || height > ledger.NodeMaxHeight {
+		return nil, fmt.Errorf("subtrie height must be between 0 and %d but is %d", ledger.NodeMaxHeight, height)
+	}
+	if len(storableNodes) == 0 {
+		return nil, nil
 	}
 
-	return &FlattenedTrie{
-		Nodes: storableNodes,
-		Trie:  storableTrie,
-	}, nil
+	nodes := make([]*node.Node, 1, len(storableNodes)+1) // 0th element is nil
+	referenced := make([]bool, len(storableNodes)+1)
+	for i, snode := range storableNodes {
+		index := uint64(i + 1)
+		if snode == nil {
+			return nil, fmt.Errorf("storable node %d is nil", index)
+		}
+		if (snode.LIndex >= index) || (snode.RIndex >= index) {
+			return nil, fmt.Errorf("sequence of StorableNodes does not satisfy Descendents-First-Relationship")
+		}
+		if int(snode.Height) > height {
+			return nil, fmt.Errorf("height of node %d exceeds the subtrie height %d", index, height)
+		}
+
+		if snode.LIndex == 0 && snode.RIndex == 0 {
+			path, err := ledger.ToPath(snode.Path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode a path of a storableNode %w", err)
+			}
+			payload, err := encoding.DecodePayload(snode.EncPayload)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode a payload for an storableNode %w", err)
+			}
+			if payload == nil {
+				return nil, fmt.Errorf("leaf %d has no payload", index)
+			}
+			nodes = append(nodes, node.NewLeaf(path, payload, int(snode.Height)))
+			continue
+		}
+
+		for _, child := range []uint64{snode.LIndex, snode.RIndex} {
+			if child == 0 {
+				continue
+			}
+			if referenced[child] {
+				return nil, fmt.Errorf("node %d is referenced more than once", child)
+			}
+			referenced[child] = true
+			if nodes[child].Height() != int(snode.Height)-1 {
+				return nil, fmt.Errorf("height of node %d must be %d but is %d", child, int(snode.Height)-1, nodes[child].Height())
+			}
+		}
+		nodes = append(nodes, node.NewInterimNode(int(snode.Height), nodes[snode.LIndex], nodes[snode.RIndex]))
+	}
+
+	// all nodes but the root must be part of the subtrie
+	for index := 1; index < len(storableNodes); index++ {
+		if !referenced[index] {
+			return nil, fmt.Errorf("node %d is not part of the subtrie", index)
+		}
+	}
+
+	root := nodes[len(nodes)-1]
+	if root.Height() != height {
+		return nil, fmt.Errorf("height of subtrie root must be %d but is %d", height, root.Height())
+	}
+	return root, nil
 }
 
 // RebuildTrie construct a trie from a storable FlattenedForest
@@ -1,6 +1,7 @@
 package flattener_test
 
 import (
+	"bytes"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -54,3 +55,97 @@ func TestTrieStoreAndLoad(t *testing.T) {
 		require.True(t, retPayloads[i].Equals(newRetPayloads[i]))
 	}
 }
+
+func TestSubTrieFlattenAndRebuild(t *testing.T) {
+	paths := utils.RandomPaths(200)
+	payloads := utils.RandomPayloads(200, 1, 64)
+	updatePayloads := make([]ledger.Payload, 0, len(payloads))
+	for _, p := range payloads {
+		updatePayloads = append(updatePayloads, *p)
+	}
+
+	newTrie, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, updatePayloads)
+	require.NoError(t, err)
+
+	depth := 3
+	height := ledger.NodeMaxHeight - depth
+	for i := uint64(0); i < 1<<depth; i++ {
+		subTrie, _, err := newTrie.SubTrie(trie.SubTriePath(depth, i), depth)
+		require.NoError(t, err)
+
+		storableNodes, err := flattener.FlattenSubTrie(subTrie)
+		require.NoError(t, err)
+
+		// storable nodes survive an encoding round trip
+		encoded := make([][]byte, 0, len(storableNodes))
+		for _, storableNode := range storableNodes {
+			encoded = append(encoded, flattener.EncodeStorableNode(storableNode))
+		}
+		decoded := make([]*flattener.StorableNode, 0, len(encoded))
+		for _, data := range encoded {
+			storableNode, err := flattener.ReadStorableNode(bytes.NewReader(data))
+			require.NoError(t, err)
+			decoded = append(decoded, storableNode)
+		}
+
+		rebuilt, err := flattener.RebuildSubTrie(decoded, height)
+		require.NoError(t, err)
+		require.Equal(t, subTrie.Hash(), rebuilt.Hash())
+		require.Equal(t, subTrie.RegCount(), rebuilt.RegCount())
+		require.Equal(t, subTrie.MaxDepth(), rebuilt.MaxDepth())
+	}
+
+	t.Run("empty subtrie", func(t *testing.T) {
+		storableNodes, err := flattener.FlattenSubTrie(nil)
+		require.NoError(t, err)
+		require.Empty(t, storableNodes)
+
+		rebuilt, err := flattener.RebuildSubTrie(storableNodes, height)
+		require.NoError(t, err)
+		require.Nil(t, rebuilt)
+	})
+
+	t.Run("forged hashes are ignored", func(t *testing.T) {
+		subTrie, _, err := newTrie.SubTrie(trie.SubTriePath(depth, 0), depth)
+		require.NoError(t, err)
+		storableNodes, err := flattener.FlattenSubTrie(subTrie)
+		require.NoError(t, err)
+
+		root := storableNodes[len(storableNodes)-1]
+		root.HashValue = make([]byte, len(root.HashValue))
+
+		rebuilt, err := flattener.RebuildSubTrie(storableNodes, height)
+		require.NoError(t, err)
+		require.Equal(t, subTrie.Hash(), rebuilt.Hash())
+	})
+
+	t.Run("wrong height", func(t *testing.T) {
+		subTrie, _, err := newTrie.SubTrie(trie.SubTriePath(depth, 0), depth)
+		require.NoError(t, err)
+		storableNodes, err := flattener.FlattenSubTrie(subTrie)
+		require.NoError(t, err)
+
+		_, err = flattener.RebuildSubTrie(storableNodes, height-1)
+		require.Error(t, err)
+	})
+
+	t.Run("unreferenced node", func(t *testing.T) {
+		subTrie, _, err := newTrie.SubTrie(trie.SubTriePath(depth, 0), depth)
+		require.NoError(t, err)
+		storableNodes, err := flattener.FlattenSubTrie(subTrie)
+		require.NoError(t, err)
+
+		storableNodes = append([]*flattener.StorableNode{storableNodes[0]}, storableNodes...)
+		for _, storableNode := range storableNodes[1:] {
+			if storableNode.LIndex > 0 {
+				storableNode.LIndex++
+			}
+			if storableNode.RIndex > 0 {
+				storableNode.RIndex++
+			}
+		}
+
+		_, err = flattener.RebuildSubTrie(storableNodes, height)
+		require.Error(t, err)
+	})
+}
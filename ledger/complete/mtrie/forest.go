@@ -3,8 +3,10 @@ package mtrie
 import (
 	"errors"
 	"fmt"
+	"sync"
 
 	lru "github.com/hashicorp/golang-lru"
+	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/hash"
@@ -12,6 +14,18 @@ import (
 	"github.com/onflow/flow-go/module"
 )
 
+// TrieStore persists tries which are evicted from the Forest, such that they
+// can be reloaded on demand.
+type TrieStore interface {
+	// StoreTrie persists the given trie. Storing a trie which is already
+	// stored is a no-op. As tries are stored while the forest is locked,
+	// implementations should persist tries asynchronously.
+	StoreTrie(t *trie.MTrie) error
+	// LoadTrie loads the trie with the given root hash. Implementations should
+	// share the nodes the loaded trie has in common with the given resident tries.
+	LoadTrie(rootHash ledger.RootHash, resident []*trie.MTrie) (*trie.MTrie, error)
+}
+
 // Forest holds several in-memory tries. As Forest is a storage-abstraction layer,
 // we assume that all registers are addressed via paths of pre-defined uniform length.
 //
@@ -21,16 +35,38 @@ import (
 // tries that are still needed. In fully matured Flow, we will have an
 // explicit eviction policy.
 //
-// TODO: Storage Eviction Policy for Forest
-//       For the execution node: we only evict on sealing a result.
+// If the Forest is backed by a TrieStore, evicted tries are persisted to the
+// store and transparently reloaded when they are needed again. This bounds the
+// memory usage independently of the number of tries which are still in use.
+//
+// Tries can be pinned, which exempts them from eviction until they are unpinned
+// again. This allows an explicit eviction policy, e.g. the execution node pins the
+// tries of all executed blocks until they are sealed, while the LRU heuristic
+// bounds the number of unpinned tries.
 type Forest struct {
-	// tries stores all MTries in the forest. It is NOT a CACHE in the conventional sense:
-	// there is no mechanism to load a trie from disk in case of a cache miss. Missing a
-	// needed trie in the forest might cause a fatal application logic error.
+	// tries stores the MTries held in memory. Without a TrieStore, it is NOT a CACHE in
+	// the conventional sense: there is no mechanism to load a trie from disk in case of
+	// a cache miss. Missing a needed trie in the forest might cause a fatal application
+	// logic error.
 	tries          *lru.Cache
 	forestCapacity int
 	onTreeEvicted  func(tree *trie.MTrie) error
+	store          TrieStore
 	metrics        module.LedgerMetrics
+	log            zerolog.Logger
+
+	// pinned holds the pinned tries, which are retained regardless of the
+	// LRU eviction. Pinned tries are usually also held in the LRU cache.
+	// CAUTION: pinMu must never be held while accessing the LRU cache, as
+	// the eviction callback acquires pinMu while holding the cache lock.
+	pinMu  sync.Mutex
+	pinned map[ledger.RootHash]*pinnedTrie
+}
+
+// pinnedTrie is a pinned trie and the number of times it has been pinned.
+type pinnedTrie struct {
+	trie  *trie.MTrie
+	count uint
 }
 
 // NewForest returns a new instance of memory forest.
@@ -41,30 +77,38 @@ type Forest struct {
 // Make sure you chose a sufficiently large forestCapacity, such that, when reaching the capacity, the
 // Least Recently Used trie will never be needed again.
 func NewForest(forestCapacity int, metrics module.LedgerMetrics, onTreeEvicted func(tree *trie.MTrie) error) (*Forest, error) {
-	// init LRU cache as a SHORTCUT for a usage-related storage eviction policy
+	return NewForestWithTrieStore(forestCapacity, metrics, onTreeEvicted, nil, zerolog.Nop())
+}
+
+// NewForestWithTrieStore returns a new instance of memory forest backed by the given store.
+// Tries evicted from memory are persisted to the store before onTreeEvicted is called,
+// and are reloaded from the store when they are requested again. Hence, forestCapacity
+// only bounds the number of tries held in memory. If store is nil, the forest behaves
+// as the one returned by NewForest. Failures of the eviction are logged with the given logger.
+func NewForestWithTrieStore(forestCapacity int, metrics module.LedgerMetrics, onTreeEvicted func(tree *trie.MTrie) error, store TrieStore, log zerolog.Logger) (*Forest, error) {
+	forest := &Forest{
+		forestCapacity: forestCapacity,
+		onTreeEvicted:  onTreeEvicted,
+		store:          store,
+		metrics:        metrics,
+		log:            log.With().Str("component", "forest").Logger(),
+		pinned:         make(map[ledger.RootHash]*pinnedTrie),
+	}
+
+	// init LRU cache as a SHORTCUT for a usage-related storage eviction policy.
+	// Without an eviction callback, pinned tries evicted from the cache are
+	// still retained by the pinned map.
 	var cache *lru.Cache
 	var err error
-	if onTreeEvicted != nil {
-		cache, err = lru.NewWithEvict(forestCapacity, func(key interface{}, value interface{}) {
-			trie, ok := value.(*trie.MTrie)
-			if !ok {
-				panic(fmt.Sprintf("cache contains item of type %T", value))
-			}
-			//TODO Log error
-			_ = onTreeEvicted(trie)
-		})
+	if onTreeEvicted != nil || store != nil {
+		cache, err = lru.NewWithEvict(forestCapacity, forest.onEvicted)
 	} else {
 		cache, err = lru.New(forestCapacity)
 	}
 	if err != nil {
 		return nil, fmt.Errorf("cannot create forest cache: %w", err)
 	}
-
-	forest := &Forest{tries: cache,
-		forestCapacity: forestCapacity,
-		onTreeEvicted:  onTreeEvicted,
-		metrics:        metrics,
-	}
+	forest.tries = cache
 
 	// add trie with no allocated registers
 	emptyTrie := trie.NewEmptyMTrie()
@@ -75,6 +119,34 @@ func NewForest(forestCapacity int, metrics module.LedgerMetrics, onTreeEvicted f
 	return forest, nil
 }
 
+// onEvicted is called when a trie is evicted from the LRU cache.
+func (f *Forest) onEvicted(key interface{}, value interface{}) {
+	trie, ok := value.(*trie.MTrie)
+	if !ok {
+		panic(fmt.Sprintf("cache contains item of type %T", value))
+	}
+
+	// pinned tries remain in the forest
+	if f.isPinned(trie.RootHash()) {
+		return
+	}
+
+	if f.store != nil {
+		// without a persisted copy, the trie can not be reloaded, which is
+		// the behaviour of a forest without store
+		err := f.store.StoreTrie(trie)
+		if err != nil {
+			f.log.Error().Err(err).Str("root_hash", trie.RootHash().String()).Msg("could not store evicted trie")
+		}
+	}
+	if f.onTreeEvicted != nil {
+		err := f.onTreeEvicted(trie)
+		if err != nil {
+			f.log.Error().Err(err).Str("root_hash", trie.RootHash().String()).Msg("eviction callback failed")
+		}
+	}
+}
+
 // Read reads values for an slice of paths and returns values and error (if any)
 // TODO: can be optimized further if we don't care about changing the order of the input r.Paths
 func (f *Forest) Read(r *ledger.TrieRead) ([]*ledger.Payload, error) {
@@ -235,6 +307,11 @@ func (f *Forest) Proofs(r *ledger.TrieRead) (*ledger.TrieBatchProof, error) {
 // GetTrie returns trie at specific rootHash
 // warning, use this function for read-only operation
 func (f *Forest) GetTrie(rootHash ledger.RootHash) (*trie.MTrie, error) {
+	// if pinned
+	if trie, found := f.pinnedTrie(rootHash); found {
+		return trie, nil
+	}
+
 	// if in memory
 	if ent, found := f.tries.Get(rootHash); found {
 		trie, ok := ent.(*trie.MTrie)
@@ -243,7 +320,43 @@ func (f *Forest) GetTrie(rootHash ledger.RootHash) (*trie.MTrie, error) {
 		}
 		return trie, nil
 	}
-	return nil, fmt.Errorf("trie with the given rootHash %s not found", rootHash)
+
+	if f.store == nil {
+		return nil, fmt.Errorf("trie with the given rootHash %s not found", rootHash)
+	}
+
+	// reload the trie if it has been evicted from memory, sharing its unchanged
+	// sub-tries with the tries in memory
+	loaded, err := f.store.LoadTrie(rootHash, f.residentTries())
+	if err != nil {
+		return nil, fmt.Errorf("trie with the given rootHash %s not found: %w", rootHash, err)
+	}
+	if loaded.RootHash() != rootHash {
+		return nil, fmt.Errorf("loaded trie has root hash %s, expected %s", loaded.RootHash(), rootHash)
+	}
+
+	err = f.AddTrie(loaded)
+	if err != nil {
+		return nil, fmt.Errorf("adding reloaded trie to forest failed: %w", err)
+	}
+	return loaded, nil
+}
+
+// residentTries returns the tries held in memory, without affecting their
+// recency in the LRU cache.
+func (f *Forest) residentTries() []*trie.MTrie {
+	keys := f.tries.Keys()
+	tries := f.pinnedTries()
+	for _, key := range keys {
+		ent, ok := f.tries.Peek(key)
+		if !ok {
+			continue
+		}
+		if t, ok := ent.(*trie.MTrie); ok {
+			tries = append(tries, t)
+		}
+	}
+	return tries
 }
 
 // GetTries returns list of currently cached tree root hashes
@@ -262,6 +375,13 @@ func (f *Forest) GetTries() ([]*trie.MTrie, error) {
 		}
 		tries = append(tries, trie)
 	}
+
+	// add pinned tries which have been evicted from the LRU cache
+	for _, pinned := range f.pinnedTries() {
+		if !f.tries.Contains(pinned.RootHash()) {
+			tries = append(tries, pinned)
+		}
+	}
 	return tries, nil
 }
 
@@ -295,16 +415,123 @@ func (f *Forest) AddTrie(newTrie *trie.MTrie) error {
 		return fmt.Errorf("forest already contains a tree with same root hash but other properties")
 	}
 	f.tries.Add(rootHash, newTrie)
-	f.metrics.ForestNumberOfTrees(uint64(f.tries.Len()))
+	f.reportSize()
 
 	return nil
 }
 
-// RemoveTrie removes a trie to the forest
+// RemoveTrie removes a trie to the forest, regardless of whether it is pinned
 func (f *Forest) RemoveTrie(rootHash ledger.RootHash) {
-	// TODO remove from the file as well
+	f.pinMu.Lock()
+	delete(f.pinned, rootHash)
+	f.pinMu.Unlock()
+
+	// the persisted copy of the trie, if any, is kept: the WAL records evictions
+	// as removals, and stored tries are pruned along with old checkpoints
 	f.tries.Remove(rootHash)
-	f.metrics.ForestNumberOfTrees(uint64(f.tries.Len()))
+	f.reportSize()
+}
+
+// Pin exempts the trie with the given root hash from eviction, until it is
+// unpinned as many times as it was pinned. The trie is reloaded from the
+// trie store if it has been evicted already.
+func (f *Forest) Pin(rootHash ledger.RootHash) error {
+	trie, err := f.GetTrie(rootHash)
+	if err != nil {
+		return fmt.Errorf("cannot pin trie: %w", err)
+	}
+
+	f.pinMu.Lock()
+	pinned, ok := f.pinned[rootHash]
+	if !ok {
+		pinned = &pinnedTrie{trie: trie}
+		f.pinned[rootHash] = pinned
+	}
+	pinned.count++
+	f.pinMu.Unlock()
+
+	f.reportSize()
+	return nil
+}
+
+// Unpin releases a pin on the trie with the given root hash. Once all pins are
+// released, the trie is subject to the LRU eviction again. Unpinning a trie
+// which is not pinned is a no-op.
+func (f *Forest) Unpin(rootHash ledger.RootHash) {
+	f.pinMu.Lock()
+	pinned, ok := f.pinned[rootHash]
+	if !ok {
+		f.pinMu.Unlock()
+		return
+	}
+	pinned.count--
+	released := pinned.count == 0
+	if released {
+		delete(f.pinned, rootHash)
+	}
+	f.pinMu.Unlock()
+
+	if released && !f.tries.Contains(rootHash) {
+		// the trie has been skipped by the LRU eviction while it was pinned,
+		// add it back such that it is evicted in order
+		err := f.AddTrie(pinned.trie)
+		if err != nil {
+			f.log.Error().Err(err).Str("root_hash", rootHash.String()).Msg("could not add unpinned trie back to forest")
+		}
+	}
+
+	f.reportSize()
+}
+
+// PinnedSize returns the number of pinned tries
+func (f *Forest) PinnedSize() int {
+	f.pinMu.Lock()
+	defer f.pinMu.Unlock()
+	return len(f.pinned)
+}
+
+func (f *Forest) isPinned(rootHash ledger.RootHash) bool {
+	f.pinMu.Lock()
+	defer f.pinMu.Unlock()
+	_, ok := f.pinned[rootHash]
+	return ok
+}
+
+func (f *Forest) pinnedTrie(rootHash ledger.RootHash) (*trie.MTrie, bool) {
+	f.pinMu.Lock()
+	defer f.pinMu.Unlock()
+	pinned, ok := f.pinned[rootHash]
+	if !ok {
+		return nil, false
+	}
+	return pinned.trie, true
+}
+
+func (f *Forest) pinnedTries() []*trie.MTrie {
+	f.pinMu.Lock()
+	defer f.pinMu.Unlock()
+	tries := make([]*trie.MTrie, 0, len(f.pinned))
+	for _, pinned := range f.pinned {
+		tries = append(tries, pinned.trie)
+	}
+	return tries
+}
+
+// reportSize reports the number of tries in the forest, as well as the number
+// of pinned and evictable tries.
+func (f *Forest) reportSize() {
+	pinned := 0
+	size := f.tries.Len()
+	for _, t := range f.pinnedTries() {
+		pinned++
+		if !f.tries.Contains(t.RootHash()) {
+			size++
+		}
+	}
+
+	f.metrics.ForestNumberOfTrees(uint64(size))
+	f.metrics.ForestNumberOfPinnedTrees(uint64(pinned))
+	f.metrics.ForestNumberOfEvictableTrees(uint64(size - pinned))
 }
 
 // GetEmptyRootHash returns the rootHash of empty Trie
@@ -321,7 +548,13 @@ func (f *Forest) MostRecentTouchedRootHash() (ledger.RootHash, error) {
 	return ledger.RootHash(hash.DummyHash), fmt.Errorf("no trie is stored in the forest")
 }
 
-// Size returns the number of active tries in this store
+// Size returns the number of active tries in this store, including pinned tries
 func (f *Forest) Size() int {
-	return f.tries.Len()
+	size := f.tries.Len()
+	for _, t := range f.pinnedTries() {
+		if !f.tries.Contains(t.RootHash()) {
+			size++
+		}
+	}
+	return size
 }
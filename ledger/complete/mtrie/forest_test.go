@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -49,6 +50,128 @@ func TestTrieOperations(t *testing.T) {
 	require.Equal(t, forest.Size(), 1)
 }
 
+// memoryTrieStore is a TrieStore keeping evicted tries in a map.
+type memoryTrieStore map[ledger.RootHash]*trie.MTrie
+
+func (s memoryTrieStore) StoreTrie(t *trie.MTrie) error {
+	s[t.RootHash()] = t
+	return nil
+}
+
+func (s memoryTrieStore) LoadTrie(rootHash ledger.RootHash, _ []*trie.MTrie) (*trie.MTrie, error) {
+	t, ok := s[rootHash]
+	if !ok {
+		return nil, fmt.Errorf("trie %s not stored", rootHash)
+	}
+	return t, nil
+}
+
+// TestTrieStore tests that tries evicted from the Forest are persisted to the
+// store and reloaded on demand.
+func TestTrieStore(t *testing.T) {
+
+	store := make(memoryTrieStore)
+	evicted := 0
+	forest, err := NewForestWithTrieStore(2, &metrics.NoopCollector{}, func(tree *trie.MTrie) error {
+		evicted++
+		return nil
+	}, store, zerolog.Nop())
+	require.NoError(t, err)
+
+	p1 := pathByUint8s([]uint8{uint8(53), uint8(74)})
+	v1 := payloadBySlices([]byte{'A'}, []byte{'A'})
+	v2 := payloadBySlices([]byte{'B'}, []byte{'B'})
+	paths := []ledger.Path{p1}
+
+	root1, err := forest.Update(&ledger.TrieUpdate{RootHash: forest.GetEmptyRootHash(), Paths: paths, Payloads: []*ledger.Payload{v1}})
+	require.NoError(t, err)
+	root2, err := forest.Update(&ledger.TrieUpdate{RootHash: root1, Paths: paths, Payloads: []*ledger.Payload{v2}})
+	require.NoError(t, err)
+
+	// the empty trie has been evicted and persisted
+	require.Equal(t, 2, forest.Size())
+	require.Equal(t, 1, evicted)
+	require.Contains(t, store, forest.GetEmptyRootHash())
+
+	// reading from the evicted trie reloads it
+	retPayloads, err := forest.Read(&ledger.TrieRead{RootHash: forest.GetEmptyRootHash(), Paths: paths})
+	require.NoError(t, err)
+	require.True(t, retPayloads[0].IsEmpty())
+	require.Equal(t, 2, forest.Size())
+	require.Contains(t, store, root1)
+
+	retPayloads, err = forest.Read(&ledger.TrieRead{RootHash: root1, Paths: paths})
+	require.NoError(t, err)
+	require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[0]), encoding.EncodePayload(v1)))
+
+	retPayloads, err = forest.Read(&ledger.TrieRead{RootHash: root2, Paths: paths})
+	require.NoError(t, err)
+	require.True(t, bytes.Equal(encoding.EncodePayload(retPayloads[0]), encoding.EncodePayload(v2)))
+
+	// tries which were never stored can not be loaded
+	_, err = forest.GetTrie(ledger.RootHash(ledger.GetDefaultHashForHeight(1)))
+	require.Error(t, err)
+}
+
+// TestPinnedTries tests that pinned tries are retained by the forest regardless
+// of the LRU eviction, and are subject to eviction again once unpinned.
+func TestPinnedTries(t *testing.T) {
+
+	evicted := make(map[ledger.RootHash]int)
+	forest, err := NewForest(2, &metrics.NoopCollector{}, func(tree *trie.MTrie) error {
+		evicted[tree.RootHash()]++
+		return nil
+	})
+	require.NoError(t, err)
+	emptyRoot := forest.GetEmptyRootHash()
+
+	p1 := pathByUint8s([]uint8{uint8(53), uint8(74)})
+	v1 := payloadBySlices([]byte{'A'}, []byte{'A'})
+	v2 := payloadBySlices([]byte{'B'}, []byte{'B'})
+	paths := []ledger.Path{p1}
+
+	// pinning is counted
+	require.NoError(t, forest.Pin(emptyRoot))
+	require.NoError(t, forest.Pin(emptyRoot))
+	require.Equal(t, 1, forest.PinnedSize())
+
+	root1, err := forest.Update(&ledger.TrieUpdate{RootHash: emptyRoot, Paths: paths, Payloads: []*ledger.Payload{v1}})
+	require.NoError(t, err)
+	root2, err := forest.Update(&ledger.TrieUpdate{RootHash: root1, Paths: paths, Payloads: []*ledger.Payload{v2}})
+	require.NoError(t, err)
+
+	// the pinned empty trie is skipped by the eviction, but still in the forest
+	require.Empty(t, evicted)
+	require.Equal(t, 3, forest.Size())
+	tries, err := forest.GetTries()
+	require.NoError(t, err)
+	require.Len(t, tries, 3)
+	retPayloads, err := forest.Read(&ledger.TrieRead{RootHash: emptyRoot, Paths: paths})
+	require.NoError(t, err)
+	require.True(t, retPayloads[0].IsEmpty())
+
+	// the empty trie remains pinned until it is unpinned as often as it was pinned
+	forest.Unpin(emptyRoot)
+	require.Equal(t, 1, forest.PinnedSize())
+	forest.Unpin(emptyRoot)
+	require.Equal(t, 0, forest.PinnedSize())
+
+	// the unpinned trie has been added back to the LRU cache, evicting the oldest trie
+	require.Equal(t, 2, forest.Size())
+	require.Equal(t, 1, evicted[root1])
+	_, err = forest.GetTrie(emptyRoot)
+	require.NoError(t, err)
+	_, err = forest.GetTrie(root2)
+	require.NoError(t, err)
+
+	// tries which are not in the forest can not be pinned
+	require.Error(t, forest.Pin(root1))
+
+	// unpinning a trie which is not pinned is a no-op
+	forest.Unpin(root2)
+	require.Equal(t, 2, forest.Size())
+}
+
 // TestTrieUpdate updates the empty trie with some values and verifies that the
 // written values can be retrieved from the updated trie.
 func TestTrieUpdate(t *testing.T) {
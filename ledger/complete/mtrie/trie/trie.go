@@ -412,6 +412,99 @@ func addSiblingTrieHashToProofs(siblingTrie *node.Node, depth int, proofs []*led
 	}
 }
 
+// SubTrie returns the root node of the subtrie at the given depth, whose path
+// from the trie root is given by the first `depth` bits of `prefix`, together
+// with a proof that the subtrie is part of this trie. The returned node is nil
+// if the subtrie is empty. If the subtrie is represented by a compact leaf
+// higher up in the trie, the leaf is re-created at the height of the subtrie.
+// Concurrency safe (as Tries are immutable structures by convention)
+func (mt *MTrie) SubTrie(prefix ledger.Path, depth int) (*node.Node, *ledger.SubTrieProof, error) {
+	if depth < 0 || depth > ledger.NodeMaxHeight {
+		return nil, nil, fmt.Errorf("subtrie depth must be between 0 and %d but is %d", ledger.NodeMaxHeight, depth)
+	}
+
+	proof := ledger.NewSubTrieProof()
+	proof.Path = prefix
+	proof.Steps = uint16(depth)
+
+	head := mt.root
+	for d := 0; d < depth; d++ {
+		if head == nil {
+			return nil, proof, nil
+		}
+		if head.IsLeaf() {
+			// a compact leaf is part of the subtrie only if its path shares the subtrie's prefix
+			for i := d; i < depth; i++ {
+				if bitutils.Bit(head.Path()[:], i) != bitutils.Bit(prefix[:], i) {
+					return nil, proof, nil
+				}
+			}
+			return node.NewLeaf(*head.Path(), head.Payload(), ledger.NodeMaxHeight-depth), proof, nil
+		}
+
+		child, sibling := head.LeftChild(), head.RightChild()
+		if bitutils.Bit(prefix[:], d) == 1 {
+			child, sibling = sibling, child
+		}
+		if sibling != nil {
+			siblingHash := sibling.Hash()
+			if siblingHash != ledger.GetDefaultHashForHeight(sibling.Height()) { // in proofs, we only provide non-default value hashes
+				bitutils.SetBit(proof.Flags, d)
+				proof.Interims = append(proof.Interims, siblingHash)
+			}
+		}
+		head = child
+	}
+	return head, proof, nil
+}
+
+// SubTriePath returns the path of the subtrie with the given index at the given depth,
+// i.e. the path whose first `depth` bits are the binary representation of index.
+func SubTriePath(depth int, index uint64) ledger.Path {
+	var path ledger.Path
+	for d := 0; d < depth; d++ {
+		if index&(1<<uint(depth-1-d)) != 0 {
+			bitutils.SetBit(path[:], d)
+		}
+	}
+	return path
+}
+
+// NewMTrieFromSubTries assembles a trie from the 2^depth subtries at the given depth,
+// ordered by their path from the trie root. Nil entries denote empty subtries.
+// UNCHECKED requirement: all non-nil subtries have height NodeMaxHeight - depth
+func NewMTrieFromSubTries(depth int, subTries []*node.Node) (*MTrie, error) {
+	if depth < 0 || depth > ledger.NodeMaxHeight || len(subTries) != 1<<depth {
+		return nil, fmt.Errorf("expected %d subtries at depth %d but got %d", 1<<depth, depth, len(subTries))
+	}
+
+	level := make([]*node.Node, len(subTries))
+	copy(level, subTries)
+	for height := ledger.NodeMaxHeight - depth + 1; height <= ledger.NodeMaxHeight; height++ {
+		parents := make([]*node.Node, len(level)/2)
+		for i := range parents {
+			parents[i] = mergeSubTries(height, level[2*i], level[2*i+1])
+		}
+		level = parents
+	}
+	return NewMTrie(level[0])
+}
+
+// mergeSubTries returns the node at the given height with children lchild and rchild,
+// keeping the trie compact: a single leaf is moved up instead of being wrapped.
+func mergeSubTries(height int, lchild, rchild *node.Node) *node.Node {
+	switch {
+	case lchild == nil && rchild == nil:
+		return nil
+	case rchild == nil && lchild.IsLeaf():
+		return node.NewLeaf(*lchild.Path(), lchild.Payload(), height)
+	case lchild == nil && rchild.IsLeaf():
+		return node.NewLeaf(*rchild.Path(), rchild.Payload(), height)
+	default:
+		return node.NewInterimNode(height, lchild, rchild)
+	}
+}
+
 // Equals compares two tries for equality.
 // Tries are equal iff they store the same data (i.e. root hash matches)
 // and their number and height are identical
@@ -14,7 +14,9 @@ import (
 	"github.com/onflow/flow-go/ledger"
 	"github.com/onflow/flow-go/ledger/common/bitutils"
 	"github.com/onflow/flow-go/ledger/common/hash"
+	"github.com/onflow/flow-go/ledger/common/proof"
 	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
 )
 
@@ -302,3 +304,55 @@ func TestSplitByPath(t *testing.T) {
 func hashToString(hash ledger.RootHash) string {
 	return hex.EncodeToString(hash[:])
 }
+
+// Test_SubTries tests that a trie can be split into verifiable subtries at
+// different depths, and re-assembled into a trie with the same root hash.
+func Test_SubTries(t *testing.T) {
+	rng := &LinearCongruentialGenerator{seed: 0}
+	paths, payloads := deduplicateWrites(sampleRandomRegisterWrites(rng, 1000))
+	manyRegisters, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloads)
+	require.NoError(t, err)
+
+	// few registers result in compact leaves above the subtrie depth
+	fewRegisters, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths[:3], payloads[:3])
+	require.NoError(t, err)
+
+	for _, mt := range []*trie.MTrie{trie.NewEmptyMTrie(), fewRegisters, manyRegisters} {
+		for _, depth := range []int{0, 1, 4, 8} {
+			subTries := make([]*node.Node, 1<<depth)
+			for i := range subTries {
+				subTrie, subTrieProof, err := mt.SubTrie(trie.SubTriePath(depth, uint64(i)), depth)
+				require.NoError(t, err)
+
+				subTrieHash := ledger.GetDefaultHashForHeight(ledger.NodeMaxHeight - depth)
+				if subTrie != nil {
+					require.Equal(t, ledger.NodeMaxHeight-depth, subTrie.Height())
+					subTrieHash = subTrie.Hash()
+				}
+				require.True(t, proof.VerifySubTrieProof(subTrieProof, subTrieHash, ledger.State(mt.RootHash())))
+
+				// a proof for a different subtrie must not verify
+				tampered := hash.HashLeaf(hash.Hash(paths[0]), payloads[0].Value)
+				require.False(t, proof.VerifySubTrieProof(subTrieProof, tampered, ledger.State(mt.RootHash())))
+
+				subTries[i] = subTrie
+			}
+
+			assembled, err := trie.NewMTrieFromSubTries(depth, subTries)
+			require.NoError(t, err)
+			require.Equal(t, mt.RootHash(), assembled.RootHash())
+			require.Equal(t, mt.AllocatedRegCount(), assembled.AllocatedRegCount())
+		}
+	}
+}
+
+func TestSubTriePath(t *testing.T) {
+	path := trie.SubTriePath(4, 5)
+	require.Equal(t, byte(0x50), path[0])
+
+	path = trie.SubTriePath(12, 0xabc)
+	require.Equal(t, byte(0xab), path[0])
+	require.Equal(t, byte(0xc0), path[1])
+
+	require.Equal(t, ledger.Path{}, trie.SubTriePath(0, 0))
+}
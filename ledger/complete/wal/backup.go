@@ -0,0 +1,177 @@
+package wal
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/onflow/flow-go/model/bootstrap"
+	utilsio "github.com/onflow/flow-go/utils/io"
+)
+
+// LedgerBackup describes the ledger files copied by a backup: the latest checkpoint,
+// the WAL segments following it and the evicted tries, if any. Replaying the backup
+// restores all updates recorded before the backup was started.
+type LedgerBackup struct {
+	// Checkpoint is the number of the copied checkpoint, or -1 if the root checkpoint,
+	// or no checkpoint at all, was copied.
+	Checkpoint     int  `json:"checkpoint"`
+	RootCheckpoint bool `json:"rootCheckpoint"`
+	FirstSegment   int  `json:"firstSegment"`
+	LastSegment    int  `json:"lastSegment"`
+	// Files are the names of the copied files, relative to the backup directory.
+	Files []string `json:"files"`
+}
+
+// Backup copies the ledger files required to restore all updates recorded so far into
+// the given directory, while updates keep being recorded. It closes the current WAL
+// segment, so that all copied segments are complete, and copies the latest checkpoint
+// along with the segments following it.
+// Checkpoints must not be removed concurrently, see Compactor.Backup.
+func (c *Checkpointer) Backup(dir string) (*LedgerBackup, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create backup directory: %w", err)
+	}
+
+	// all updates recorded before this point are in complete segments, up to the
+	// segment preceding the newly created one
+	err = c.wal.wal.NextSegment()
+	if err != nil {
+		return nil, fmt.Errorf("cannot close current segment: %w", err)
+	}
+
+	first, last, err := c.wal.Segments()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get range of segments: %w", err)
+	}
+	lastComplete := last - 1
+	if lastComplete < first {
+		return nil, fmt.Errorf("no complete segment to back up (segments %d to %d)", first, last)
+	}
+
+	latestCheckpoint, err := c.LatestCheckpoint()
+	if err != nil {
+		return nil, fmt.Errorf("cannot get latest checkpoint: %w", err)
+	}
+
+	backup := &LedgerBackup{
+		Checkpoint:   latestCheckpoint,
+		FirstSegment: first,
+		LastSegment:  lastComplete,
+	}
+
+	if latestCheckpoint >= 0 {
+		name := NumberToFilename(latestCheckpoint)
+		err = backup.copyFile(c.dir, dir, name)
+		if err != nil {
+			return nil, fmt.Errorf("cannot copy checkpoint %d: %w", latestCheckpoint, err)
+		}
+
+		// a replay needs at least one segment to find the checkpoint, so the last segment
+		// is copied even if it is checkpointed already
+		backup.FirstSegment = latestCheckpoint + 1
+		if backup.FirstSegment > lastComplete {
+			backup.FirstSegment = lastComplete
+		}
+		if backup.FirstSegment < first {
+			return nil, fmt.Errorf("gap between checkpoint %d and first segment %d", latestCheckpoint, first)
+		}
+	} else {
+		hasRootCheckpoint, err := c.HasRootCheckpoint()
+		if err != nil {
+			return nil, fmt.Errorf("cannot check root checkpoint existence: %w", err)
+		}
+		if hasRootCheckpoint {
+			err = backup.copyFile(c.dir, dir, bootstrap.FilenameWALRootCheckpoint)
+			if err != nil {
+				return nil, fmt.Errorf("cannot copy root checkpoint: %w", err)
+			}
+			backup.RootCheckpoint = true
+		}
+	}
+
+	for segment := backup.FirstSegment; segment <= backup.LastSegment; segment++ {
+		err = backup.copyFile(c.dir, dir, NumberToFilenamePart(segment))
+		if err != nil {
+			return nil, fmt.Errorf("cannot copy segment %d: %w", segment, err)
+		}
+	}
+
+	store, ok := c.wal.TrieStore().(*DiskTrieStore)
+	if ok {
+		// the tries evicted before the last segment was copied must be included
+		store.Flush()
+		err = backup.copyTrieStore(store, dir)
+		if err != nil {
+			return nil, fmt.Errorf("cannot copy evicted tries: %w", err)
+		}
+	}
+
+	return backup, nil
+}
+
+// copyTrieStore writes a snapshot of the tries persisted by the given store,
+// which is consistent while tries keep being stored and removed.
+func (b *LedgerBackup) copyTrieStore(store *DiskTrieStore, dir string) error {
+	f, err := os.Create(path.Join(dir, TrieStoreBackupFilename))
+	if err != nil {
+		return fmt.Errorf("cannot create trie store snapshot: %w", err)
+	}
+	defer f.Close()
+
+	err = store.Backup(f)
+	if err != nil {
+		return fmt.Errorf("cannot write trie store snapshot: %w", err)
+	}
+	err = f.Sync()
+	if err != nil {
+		return fmt.Errorf("cannot sync trie store snapshot: %w", err)
+	}
+
+	b.Files = append(b.Files, TrieStoreBackupFilename)
+	return nil
+}
+
+func (b *LedgerBackup) copyFile(from, to, name string) error {
+	err := utilsio.Copy(path.Join(from, name), path.Join(to, name))
+	if err != nil {
+		return err
+	}
+	b.Files = append(b.Files, name)
+	return nil
+}
+
+// Validate checks that the backup contains the checkpoint and all segments it describes.
+func (b *LedgerBackup) Validate() error {
+	files := make(map[string]struct{}, len(b.Files))
+	for _, name := range b.Files {
+		files[name] = struct{}{}
+	}
+
+	expected := make([]string, 0, b.LastSegment-b.FirstSegment+2)
+	if b.Checkpoint >= 0 {
+		expected = append(expected, NumberToFilename(b.Checkpoint))
+	}
+	if b.RootCheckpoint {
+		expected = append(expected, bootstrap.FilenameWALRootCheckpoint)
+	}
+	for segment := b.FirstSegment; segment <= b.LastSegment; segment++ {
+		expected = append(expected, NumberToFilenamePart(segment))
+	}
+
+	for _, name := range expected {
+		if _, ok := files[name]; !ok {
+			return fmt.Errorf("missing ledger file %s", name)
+		}
+	}
+
+	if b.Checkpoint >= 0 && (b.FirstSegment > b.Checkpoint+1 || b.LastSegment < b.Checkpoint) {
+		return fmt.Errorf("segments %d to %d do not follow checkpoint %d", b.FirstSegment, b.LastSegment, b.Checkpoint)
+	}
+	if b.Checkpoint < 0 && b.FirstSegment != 0 {
+		return fmt.Errorf("segments start at %d without a checkpoint", b.FirstSegment)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package wal
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func Test_Backup(t *testing.T) {
+
+	pathByteSize := 32
+	size := 10
+	metricsCollector := &metrics.NoopCollector{}
+
+	// recordUpdates records the given number of updates, each filling at least one
+	// 32kB segment, and returns the root hash of the last update
+	recordUpdates := func(t *testing.T, wal *DiskWAL, f *mtrie.Forest, rootHash ledger.RootHash, count int) ledger.RootHash {
+		for i := 0; i < count; i++ {
+			update := &ledger.TrieUpdate{
+				RootHash: rootHash,
+				Paths:    utils.RandomPaths(2),
+				Payloads: utils.RandomPayloads(2, 2<<15, 2<<16),
+			}
+			err := wal.RecordUpdate(update)
+			require.NoError(t, err)
+			rootHash, err = f.Update(update)
+			require.NoError(t, err)
+		}
+		return rootHash
+	}
+
+	// requireRestored replays the backup in the given directory and checks it contains the trie with the given root hash
+	requireRestored := func(t *testing.T, dir string, rootHash ledger.RootHash) {
+		restored, err := NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, size*10, pathByteSize, 32*1024)
+		require.NoError(t, err)
+
+		f, err := mtrie.NewForest(size*10, metricsCollector, func(tree *trie.MTrie) error { return nil })
+		require.NoError(t, err)
+		err = restored.ReplayOnForest(f)
+		require.NoError(t, err)
+
+		_, err = f.GetTrie(rootHash)
+		require.NoError(t, err)
+
+		<-restored.Done()
+	}
+
+	t.Run("backup with checkpoint", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			unittest.RunWithTempDir(t, func(backupDir string) {
+
+				f, err := mtrie.NewForest(size*10, metricsCollector, func(tree *trie.MTrie) error { return nil })
+				require.NoError(t, err)
+
+				wal, err := NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, size*10, pathByteSize, 32*1024)
+				require.NoError(t, err)
+
+				checkpointer, err := wal.NewCheckpointer()
+				require.NoError(t, err)
+				compactor := NewCompactor(checkpointer, time.Hour, 100, 0)
+
+				rootHash := recordUpdates(t, wal, f, f.GetEmptyRootHash(), 3)
+				checkpoint, err := compactor.TriggerCheckpoint()
+				require.NoError(t, err)
+
+				// the last update is in the segment being written to when the backup starts
+				rootHash = recordUpdates(t, wal, f, rootHash, 1)
+
+				backup, err := compactor.Backup(backupDir)
+				require.NoError(t, err)
+				require.NoError(t, backup.Validate())
+
+				assert.Equal(t, checkpoint, backup.Checkpoint)
+				assert.False(t, backup.RootCheckpoint)
+				assert.Equal(t, checkpoint+1, backup.FirstSegment)
+				require.FileExists(t, path.Join(backupDir, NumberToFilename(checkpoint)))
+
+				<-wal.Done()
+
+				requireRestored(t, backupDir, rootHash)
+			})
+		})
+	})
+
+	t.Run("backup without checkpoint", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			unittest.RunWithTempDir(t, func(backupDir string) {
+
+				f, err := mtrie.NewForest(size*10, metricsCollector, func(tree *trie.MTrie) error { return nil })
+				require.NoError(t, err)
+
+				wal, err := NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, size*10, pathByteSize, 32*1024)
+				require.NoError(t, err)
+
+				checkpointer, err := wal.NewCheckpointer()
+				require.NoError(t, err)
+
+				rootHash := recordUpdates(t, wal, f, f.GetEmptyRootHash(), 2)
+
+				backup, err := checkpointer.Backup(backupDir)
+				require.NoError(t, err)
+				require.NoError(t, backup.Validate())
+
+				assert.Equal(t, -1, backup.Checkpoint)
+				assert.Equal(t, 0, backup.FirstSegment)
+
+				<-wal.Done()
+
+				requireRestored(t, backupDir, rootHash)
+			})
+		})
+	})
+
+	t.Run("missing files are detected", func(t *testing.T) {
+		backup := &LedgerBackup{
+			Checkpoint:   3,
+			FirstSegment: 4,
+			LastSegment:  5,
+			Files:        []string{NumberToFilename(3), NumberToFilenamePart(4)},
+		}
+		require.Error(t, backup.Validate())
+
+		backup.Files = append(backup.Files, NumberToFilenamePart(5))
+		require.NoError(t, backup.Validate())
+	})
+}
@@ -153,9 +153,11 @@ func (c *Checkpointer) Checkpoint(to int, targetWriter func() (io.WriteCloser, e
 		return fmt.Errorf("no segments to checkpoint to %d, latests not checkpointed segment: %d", to, notCheckpointedTo)
 	}
 
-	forest, err := mtrie.NewForest(c.forestCapacity, &metrics.NoopCollector{}, func(evictedTrie *trie.MTrie) error {
+	// tries evicted while replaying are persisted to the trie store (if any), such that
+	// later updates can be applied to them
+	forest, err := mtrie.NewForestWithTrieStore(c.forestCapacity, &metrics.NoopCollector{}, func(evictedTrie *trie.MTrie) error {
 		return nil
-	})
+	}, c.wal.trieStore, c.wal.log)
 	if err != nil {
 		return fmt.Errorf("cannot create Forest: %w", err)
 	}
@@ -311,6 +313,23 @@ func (c *Checkpointer) RemoveCheckpoint(checkpoint int) error {
 	return os.Remove(path.Join(c.dir, NumberToFilename(checkpoint)))
 }
 
+// PruneTrieStore removes the evicted tries which were persisted before the given
+// checkpoint was created, if the WAL persists evicted tries. It returns the
+// number of removed tries.
+func (c *Checkpointer) PruneTrieStore(checkpoint int) (int, error) {
+	store, ok := c.wal.TrieStore().(*DiskTrieStore)
+	if !ok {
+		return 0, nil
+	}
+
+	info, err := os.Stat(path.Join(c.dir, NumberToFilename(checkpoint)))
+	if err != nil {
+		return 0, fmt.Errorf("cannot get checkpoint file info: %w", err)
+	}
+
+	return store.PruneBefore(info.ModTime())
+}
+
 func LoadCheckpoint(filepath string) (*flattener.FlattenedForest, error) {
 	file, err := os.Open(filepath)
 	if err != nil {
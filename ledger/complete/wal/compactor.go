@@ -99,6 +99,52 @@ func (c *Compactor) Run() error {
 	return nil
 }
 
+// TriggerCheckpoint checkpoints all segments but the last one, which is presumably
+// being written to, regardless of the checkpoint distance, and cleans up the old
+// checkpoints. It returns the number of the new checkpoint.
+func (c *Compactor) TriggerCheckpoint() (int, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	from, to, err := c.checkpointer.NotCheckpointedSegments()
+	if err != nil {
+		return -1, fmt.Errorf("cannot get latest checkpoint: %w", err)
+	}
+
+	checkpointNumber := to - 1
+	if checkpointNumber < from {
+		return -1, fmt.Errorf("no complete segment to checkpoint (segments %d to %d are not checkpointed)", from, to)
+	}
+
+	err = c.checkpointer.Checkpoint(checkpointNumber, func() (io.WriteCloser, error) {
+		return c.checkpointer.CheckpointWriter(checkpointNumber)
+	})
+	if err != nil {
+		return -1, fmt.Errorf("error creating checkpoint (%d): %w", checkpointNumber, err)
+	}
+
+	err = c.cleanupCheckpoints()
+	if err != nil {
+		return -1, fmt.Errorf("cannot cleanup checkpoints: %w", err)
+	}
+
+	for observer := range c.observers {
+		observer.OnNext(checkpointNumber)
+	}
+
+	return checkpointNumber, nil
+}
+
+// Backup copies the ledger files required to restore all updates recorded so far into
+// the given directory, see Checkpointer.Backup. Checkpoints are neither created nor
+// removed while the backup runs.
+func (c *Compactor) Backup(dir string) (*LedgerBackup, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.checkpointer.Backup(dir)
+}
+
 func (c *Compactor) createCheckpoints() (int, error) {
 	from, to, err := c.checkpointer.NotCheckpointedSegments()
 	if err != nil {
@@ -145,5 +191,19 @@ func (c *Compactor) cleanupCheckpoints() error {
 			}
 		}
 	}
+
+	// the tries evicted before the oldest remaining checkpoint are pruned along
+	// with the checkpoints, to bound the size of the trie store
+	if len(checkpoints) == 0 {
+		return nil
+	}
+	oldest := checkpoints[0]
+	if len(checkpoints) > int(c.checkpointsToKeep) {
+		oldest = checkpoints[len(checkpoints)-int(c.checkpointsToKeep)]
+	}
+	_, err = c.checkpointer.PruneTrieStore(oldest)
+	if err != nil {
+		return fmt.Errorf("cannot prune trie store: %w", err)
+	}
 	return nil
 }
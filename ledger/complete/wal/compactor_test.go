@@ -344,3 +344,53 @@ func loadIntoForest(forest *mtrie.Forest, forestSequencing *flattener.FlattenedF
 	}
 	return nil
 }
+
+func Test_CompactorTriggerCheckpoint(t *testing.T) {
+
+	pathByteSize := 32
+	size := 10
+	metricsCollector := &metrics.NoopCollector{}
+
+	unittest.RunWithTempDir(t, func(dir string) {
+
+		f, err := mtrie.NewForest(size*10, metricsCollector, func(tree *trie.MTrie) error { return nil })
+		require.NoError(t, err)
+		rootHash := f.GetEmptyRootHash()
+
+		wal, err := NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dir, size*10, pathByteSize, 32*1024)
+		require.NoError(t, err)
+
+		checkpointer, err := wal.NewCheckpointer()
+		require.NoError(t, err)
+
+		// the checkpoint distance is never reached, so that only triggered checkpoints are made
+		compactor := NewCompactor(checkpointer, time.Hour, 100, 0)
+
+		// a single segment, which is still written to, can not be checkpointed
+		_, err = compactor.TriggerCheckpoint()
+		require.Error(t, err)
+
+		// WAL segments are 32kB, so each update of 2 keys 64kB each fills at least one segment
+		for i := 0; i < 3; i++ {
+			update := &ledger.TrieUpdate{
+				RootHash: rootHash,
+				Paths:    utils.RandomPaths(2),
+				Payloads: utils.RandomPayloads(2, 2<<15, 2<<16),
+			}
+			err = wal.RecordUpdate(update)
+			require.NoError(t, err)
+			rootHash, err = f.Update(update)
+			require.NoError(t, err)
+		}
+
+		_, to, err := checkpointer.NotCheckpointedSegments()
+		require.NoError(t, err)
+
+		checkpoint, err := compactor.TriggerCheckpoint()
+		require.NoError(t, err)
+		assert.Equal(t, to-1, checkpoint)
+		require.FileExists(t, path.Join(dir, NumberToFilename(checkpoint)))
+
+		<-wal.Done()
+	})
+}
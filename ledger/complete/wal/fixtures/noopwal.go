@@ -25,6 +25,8 @@ func (w *NoopWAL) NewCheckpointer() (*wal.Checkpointer, error) {
 	return nil, nil
 }
 
+func (w *NoopWAL) TrieStore() mtrie.TrieStore { return nil }
+
 func (w *NoopWAL) PauseRecord() {}
 
 func (w *NoopWAL) UnpauseRecord() {}
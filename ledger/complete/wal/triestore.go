@@ -0,0 +1,977 @@
+package wal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/common/hash"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+)
+
+// DefaultTrieStoreDir is the name of the directory within the WAL directory
+// holding the tries evicted from memory.
+const DefaultTrieStoreDir = "evicted"
+
+// DefaultTrieStoreQueueSize is the maximum number of tries waiting to be
+// written, before storing further tries blocks until writes complete.
+const DefaultTrieStoreQueueSize = 16
+
+// TrieStoreBackupFilename is the name of the snapshot of the evicted tries
+// within a ledger backup.
+const TrieStoreBackupFilename = "evicted.bak"
+
+// maxBatchUnits is the number of changes, each consisting of a few small writes,
+// which are committed to the trie store database in a single transaction.
+const maxBatchUnits = 256
+
+// maxPendingWrites is the maximum number of pending writes while loading a
+// snapshot of the trie store.
+const maxPendingWrites = 256
+
+// key prefixes of the trie store database
+const (
+	codeNode    byte = 1 // node hash -> reference count and encoded node
+	codeRoot    byte = 2 // root hash -> whether the trie is complete and the time it was stored
+	codeGarbage byte = 3 // node hash -> nil, for unreferenced nodes which are yet to be removed
+)
+
+// flags of a stored node, telling which child references are accounted for in
+// the reference counts of the children
+const (
+	flagLeft  byte = 1
+	flagRight byte = 2
+)
+
+// DiskTrieStore persists tries evicted from the forest on disk. The nodes of the
+// stored tries are kept in a database, keyed by their hash. As tries share most
+// of their nodes with the tries they are derived from, storing a trie only writes
+// the nodes which are not stored yet, such that the disk usage grows with the
+// registers updated between the stored tries rather than with the size of the state.
+// Likewise, loading a trie re-uses the nodes of the given resident tries, and only
+// reads the nodes which differ from them, such that a reloaded trie shares its
+// unchanged sub-tries with the tries in memory.
+//
+// Nodes are reference counted: a node is referenced by each stored parent node and
+// by the trie it is the root of, and it is removed along with the last trie
+// referencing it. Each reference is added together with a flag of the referencing
+// node, in a single transaction, so that a write interrupted by a crash leaves a
+// consistent database: the tries which are not completely written are released
+// when the store is opened again.
+//
+// Tries are written in the background, such that evicting a trie does not block
+// the forest while the trie is written. Until then, the trie is served from
+// memory. Stored tries are removed by PruneBefore, usually once the checkpoints
+// covering them are removed.
+type DiskTrieStore struct {
+	db  *badger.DB
+	log zerolog.Logger
+
+	// dbMu serializes the changes of the database, which read the reference
+	// counts before updating them
+	dbMu sync.Mutex
+
+	mu      sync.Mutex
+	written *sync.Cond // signalled whenever a queued trie has been handled
+	pending map[ledger.RootHash]*trie.MTrie
+	queue   []*trie.MTrie
+	writing bool
+	closed  bool
+}
+
+var _ mtrie.TrieStore = (*DiskTrieStore)(nil)
+
+// NewDiskTrieStore opens the trie store in the given directory, creating the
+// directory if it does not exist. The tries which were not completely written
+// when the store was closed are released.
+func NewDiskTrieStore(log zerolog.Logger, dir string) (*DiskTrieStore, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create trie store directory %s: %w", dir, err)
+	}
+
+	db, err := badger.Open(badger.DefaultOptions(dir).WithLogger(nil))
+	if err != nil {
+		return nil, fmt.Errorf("cannot open trie store database %s: %w", dir, err)
+	}
+
+	s := &DiskTrieStore{
+		db:      db,
+		log:     log.With().Str("component", "trie_store").Logger(),
+		pending: make(map[ledger.RootHash]*trie.MTrie),
+	}
+	s.written = sync.NewCond(&s.mu)
+
+	err = s.recover()
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("cannot recover trie store: %w", err)
+	}
+
+	return s, nil
+}
+
+// recover releases the tries which were not completely written, and removes the
+// nodes which were left unreferenced, by a crash.
+func (s *DiskTrieStore) recover() error {
+	s.dbMu.Lock()
+	defer s.dbMu.Unlock()
+
+	incomplete, err := s.roots(func(complete bool, _ time.Time) bool { return !complete })
+	if err != nil {
+		return err
+	}
+	for _, rootHash := range incomplete {
+		s.log.Warn().Str("root_hash", rootHash.String()).Msg("releasing incompletely stored trie")
+		_, err = s.release(rootHash)
+		if err != nil {
+			return fmt.Errorf("cannot release incomplete trie %s: %w", rootHash, err)
+		}
+	}
+
+	return s.collect()
+}
+
+// Close waits for the queued tries to be written, and closes the database.
+// Closing a closed store is a no-op.
+func (s *DiskTrieStore) Close() error {
+	s.Flush()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.db.Close()
+}
+
+// StoreTrie queues the trie to be written to disk, unless it is already stored
+// or queued. It only blocks if DefaultTrieStoreQueueSize tries are queued
+// already. Failures to write the trie are logged.
+func (s *DiskTrieStore) StoreTrie(t *trie.MTrie) error {
+	rootHash := t.RootHash()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		if _, ok := s.pending[rootHash]; ok {
+			return nil
+		}
+		if len(s.queue) < DefaultTrieStoreQueueSize {
+			break
+		}
+		s.written.Wait()
+	}
+	if s.closed {
+		return fmt.Errorf("trie store is closed")
+	}
+
+	s.pending[rootHash] = t
+	s.queue = append(s.queue, t)
+	if !s.writing {
+		s.writing = true
+		go s.writeQueued()
+	}
+
+	return nil
+}
+
+// writeQueued writes the queued tries to disk, until the queue is empty.
+func (s *DiskTrieStore) writeQueued() {
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.writing = false
+			s.written.Broadcast()
+			s.mu.Unlock()
+			return
+		}
+		t := s.queue[0]
+		s.queue[0] = nil
+		s.queue = s.queue[1:]
+		rootHash := t.RootHash()
+		// the trie might have been removed while it was queued
+		queued := s.pending[rootHash] == t
+		s.mu.Unlock()
+
+		var err error
+		if queued {
+			err = s.storeTrie(t)
+			if err != nil {
+				s.log.Error().Err(err).Str("root_hash", rootHash.String()).Msg("could not store evicted trie")
+			}
+		}
+
+		s.mu.Lock()
+		if s.pending[rootHash] == t {
+			delete(s.pending, rootHash)
+		} else if queued && err == nil {
+			// the trie was removed while it was written
+			err = s.releaseTrie(rootHash)
+			if err != nil {
+				s.log.Error().Err(err).Str("root_hash", rootHash.String()).Msg("could not remove evicted trie")
+			}
+		}
+		s.written.Broadcast()
+		s.mu.Unlock()
+	}
+}
+
+// Flush blocks until all queued tries are written to disk.
+func (s *DiskTrieStore) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.writing {
+		s.written.Wait()
+	}
+}
+
+// storeTrie writes the nodes of the trie which are not stored yet, and marks the
+// trie complete once all of them are written. If writing the trie fails, the
+// nodes written so far are released.
+func (s *DiskTrieStore) storeTrie(t *trie.MTrie) error {
+	// the empty trie has no nodes, and is always available
+	if t.IsEmpty() {
+		return nil
+	}
+	rootHash := t.RootHash()
+
+	s.dbMu.Lock()
+	defer s.dbMu.Unlock()
+
+	err := s.writeTrie(rootHash, t.RootNode())
+	if err != nil {
+		_, releaseErr := s.release(rootHash)
+		if releaseErr != nil {
+			s.log.Error().Err(releaseErr).Str("root_hash", rootHash.String()).Msg("could not release incompletely stored trie")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *DiskTrieStore) writeTrie(rootHash ledger.RootHash, root *node.Node) error {
+	b := s.newBatch()
+	defer b.discard()
+
+	// reference the root node from the root entry, which marks the trie as
+	// incomplete until all nodes are written
+	var rec *nodeRecord
+	stored := false
+	err := b.unit(func(txn *badger.Txn) error {
+		complete, _, found, err := readRoot(txn, rootHash)
+		if err != nil {
+			return err
+		}
+		if found && complete {
+			stored = true
+			return nil
+		}
+		if found {
+			// left incomplete by an earlier write, the root node is referenced already
+			rec, _, err = readNode(txn, hash.Hash(rootHash))
+			return err
+		}
+		err = setRoot(txn, rootHash, false, time.Now())
+		if err != nil {
+			return err
+		}
+		rec, _, err = reference(txn, root)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("cannot store root of trie: %w", err)
+	}
+	if stored {
+		return nil
+	}
+	if rec == nil {
+		return fmt.Errorf("root node of incompletely stored trie is missing")
+	}
+
+	err = s.writeChildren(b, root, rec)
+	if err != nil {
+		return fmt.Errorf("cannot store nodes of trie: %w", err)
+	}
+
+	err = b.unit(func(txn *badger.Txn) error {
+		return setRoot(txn, rootHash, true, time.Now())
+	})
+	if err != nil {
+		return fmt.Errorf("cannot complete trie: %w", err)
+	}
+
+	return b.commit()
+}
+
+// writeChildren references the children of the given node, which is stored as
+// the given record, unless they are referenced already. The children which are
+// not complete yet, usually because they have just been written, are completed
+// recursively. Hence, the sub-tries of nodes which were stored before are not
+// visited.
+func (s *DiskTrieStore) writeChildren(b *batch, n *node.Node, rec *nodeRecord) error {
+	for _, side := range []byte{flagLeft, flagRight} {
+		child := n.LeftChild()
+		if side == flagRight {
+			child = n.RightChild()
+		}
+		if child == nil || rec.flags&side != 0 {
+			continue
+		}
+
+		var childRec *nodeRecord
+		err := b.unit(func(txn *badger.Txn) error {
+			var err error
+			childRec, _, err = reference(txn, child)
+			if err != nil {
+				return err
+			}
+			// flag the reference in the same transaction, so that the child
+			// is dereferenced when the node is removed
+			current, found, err := readNode(txn, n.Hash())
+			if err != nil {
+				return err
+			}
+			if !found {
+				return fmt.Errorf("node %s is missing", n.Hash())
+			}
+			current.flags |= side
+			rec.flags = current.flags
+			return setNode(txn, n.Hash(), current)
+		})
+		if err != nil {
+			return err
+		}
+
+		if !childRec.complete() {
+			err = s.writeChildren(b, child, childRec)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// reference adds a reference to the given node, writing the node if it is not
+// stored yet, and returns its record.
+func reference(txn *badger.Txn, n *node.Node) (*nodeRecord, bool, error) {
+	rec, found, err := readNode(txn, n.Hash())
+	if err != nil {
+		return nil, false, err
+	}
+	if !found {
+		rec = newNodeRecord(n)
+	}
+	if found && rec.refs == 0 {
+		// the node is referenced again before it was removed
+		err = txn.Delete(garbageKey(n.Hash()))
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	rec.refs++
+	err = setNode(txn, n.Hash(), rec)
+	if err != nil {
+		return nil, false, err
+	}
+	return rec, !found, nil
+}
+
+// LoadTrie reads the trie with the given root hash from disk, or returns it
+// from memory if it is not written yet. The loaded trie shares the nodes it has
+// in common with the given resident tries at the same positions.
+func (s *DiskTrieStore) LoadTrie(rootHash ledger.RootHash, resident []*trie.MTrie) (*trie.MTrie, error) {
+	if rootHash == trie.EmptyTrieRootHash() {
+		return trie.NewEmptyMTrie(), nil
+	}
+
+	s.mu.Lock()
+	pending, ok := s.pending[rootHash]
+	s.mu.Unlock()
+	if ok {
+		return pending, nil
+	}
+
+	candidates := make([]*node.Node, 0, len(resident))
+	for _, t := range resident {
+		if !t.IsEmpty() {
+			candidates = append(candidates, t.RootNode())
+		}
+	}
+
+	var root *node.Node
+	err := s.db.View(func(txn *badger.Txn) error {
+		complete, _, found, err := readRoot(txn, rootHash)
+		if err != nil {
+			return err
+		}
+		if !found || !complete {
+			return fmt.Errorf("trie %s is not stored", rootHash)
+		}
+		root, err = loadNode(txn, hash.Hash(rootHash), ledger.NodeMaxHeight, candidates)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot load trie %s: %w", rootHash, err)
+	}
+
+	return trie.NewMTrie(root)
+}
+
+// loadNode returns the node with the given hash at the given height, which is
+// one of the given candidate nodes of resident tries at the same position, or
+// is read from the database otherwise. The hashes of read nodes are verified.
+func loadNode(txn *badger.Txn, nodeHash hash.Hash, height int, candidates []*node.Node) (*node.Node, error) {
+	for _, candidate := range candidates {
+		if candidate.Hash() == nodeHash {
+			return candidate, nil
+		}
+	}
+
+	rec, found, err := readNode(txn, nodeHash)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("node %s is missing", nodeHash)
+	}
+	if rec.height != height {
+		return nil, fmt.Errorf("node %s has height %d, expected %d", nodeHash, rec.height, height)
+	}
+
+	var n *node.Node
+	if rec.payload != nil {
+		n = node.NewLeaf(rec.path, rec.payload, rec.height)
+	} else {
+		children := make([]*node.Node, 2)
+		for i, childHash := range []*hash.Hash{rec.left, rec.right} {
+			if childHash == nil {
+				continue
+			}
+			// nodes are shared among the resident tries, so the candidates for
+			// the children are de-duplicated by identity
+			next := make([]*node.Node, 0, len(candidates))
+			seen := make(map[*node.Node]struct{}, len(candidates))
+			for _, candidate := range candidates {
+				child := candidate.LeftChild()
+				if i == 1 {
+					child = candidate.RightChild()
+				}
+				if child == nil {
+					continue
+				}
+				if _, ok := seen[child]; ok {
+					continue
+				}
+				seen[child] = struct{}{}
+				next = append(next, child)
+			}
+			children[i], err = loadNode(txn, *childHash, height-1, next)
+			if err != nil {
+				return nil, err
+			}
+		}
+		n = node.NewInterimNode(rec.height, children[0], children[1])
+	}
+
+	if n.Hash() != nodeHash {
+		return nil, fmt.Errorf("node %s has hash %s when loaded", nodeHash, n.Hash())
+	}
+	return n, nil
+}
+
+// Has returns true if the trie with the given root hash is stored or queued.
+func (s *DiskTrieStore) Has(rootHash ledger.RootHash) bool {
+	if rootHash == trie.EmptyTrieRootHash() {
+		return true
+	}
+
+	s.mu.Lock()
+	_, ok := s.pending[rootHash]
+	s.mu.Unlock()
+	if ok {
+		return true
+	}
+
+	var complete bool
+	err := s.db.View(func(txn *badger.Txn) error {
+		var err error
+		complete, _, _, err = readRoot(txn, rootHash)
+		return err
+	})
+	if err != nil {
+		s.log.Error().Err(err).Str("root_hash", rootHash.String()).Msg("could not read trie root")
+		return false
+	}
+	return complete
+}
+
+// RemoveTrie removes the trie with the given root hash, along with the nodes
+// which are not shared with other stored tries. Removing a trie which is not
+// stored is a no-op.
+func (s *DiskTrieStore) RemoveTrie(rootHash ledger.RootHash) error {
+	s.mu.Lock()
+	delete(s.pending, rootHash)
+	s.mu.Unlock()
+
+	return s.releaseTrie(rootHash)
+}
+
+func (s *DiskTrieStore) releaseTrie(rootHash ledger.RootHash) error {
+	s.dbMu.Lock()
+	defer s.dbMu.Unlock()
+
+	_, err := s.release(rootHash)
+	if err != nil {
+		return fmt.Errorf("cannot remove trie %s: %w", rootHash, err)
+	}
+	return nil
+}
+
+// release removes the root entry of the trie and its reference to the root node,
+// then removes the nodes left unreferenced. It returns false if the trie is not
+// stored.
+func (s *DiskTrieStore) release(rootHash ledger.RootHash) (bool, error) {
+	b := s.newBatch()
+	defer b.discard()
+
+	found := false
+	err := b.unit(func(txn *badger.Txn) error {
+		var err error
+		_, _, found, err = readRoot(txn, rootHash)
+		if err != nil || !found {
+			return err
+		}
+		err = txn.Delete(rootKey(rootHash))
+		if err != nil {
+			return err
+		}
+		return dereference(txn, hash.Hash(rootHash))
+	})
+	if err != nil {
+		return false, err
+	}
+	err = b.commit()
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		return false, nil
+	}
+
+	return true, s.collect()
+}
+
+// dereference removes a reference to the node with the given hash. Once the node
+// is not referenced anymore, it is marked as garbage to be removed by collect.
+// Missing nodes are ignored, as a node referenced by an incompletely stored trie
+// might not have been written.
+func dereference(txn *badger.Txn, nodeHash hash.Hash) error {
+	rec, found, err := readNode(txn, nodeHash)
+	if err != nil || !found {
+		return err
+	}
+	rec.refs--
+	if rec.refs == 0 {
+		err = txn.Set(garbageKey(nodeHash), nil)
+		if err != nil {
+			return err
+		}
+	}
+	return setNode(txn, nodeHash, rec)
+}
+
+// collect removes the nodes marked as garbage, and dereferences their children,
+// until no garbage is left.
+func (s *DiskTrieStore) collect() error {
+	for {
+		var garbage []hash.Hash
+		err := s.db.View(func(txn *badger.Txn) error {
+			it := txn.NewIterator(badger.IteratorOptions{Prefix: []byte{codeGarbage}})
+			defer it.Close()
+			for it.Rewind(); it.Valid() && len(garbage) < maxBatchUnits; it.Next() {
+				nodeHash, err := hash.ToHash(it.Item().Key()[1:])
+				if err != nil {
+					return err
+				}
+				garbage = append(garbage, nodeHash)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("cannot list garbage nodes: %w", err)
+		}
+		if len(garbage) == 0 {
+			return nil
+		}
+
+		b := s.newBatch()
+		defer b.discard()
+		for _, nodeHash := range garbage {
+			nodeHash := nodeHash
+			err = b.unit(func(txn *badger.Txn) error {
+				err := txn.Delete(garbageKey(nodeHash))
+				if err != nil {
+					return err
+				}
+				rec, found, err := readNode(txn, nodeHash)
+				if err != nil || !found || rec.refs > 0 {
+					return err
+				}
+				err = txn.Delete(nodeKey(nodeHash))
+				if err != nil {
+					return err
+				}
+				if rec.flags&flagLeft != 0 {
+					err = dereference(txn, *rec.left)
+					if err != nil {
+						return err
+					}
+				}
+				if rec.flags&flagRight != 0 {
+					err = dereference(txn, *rec.right)
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("cannot remove garbage node %s: %w", nodeHash, err)
+			}
+		}
+		err = b.commit()
+		if err != nil {
+			return fmt.Errorf("cannot remove garbage nodes: %w", err)
+		}
+	}
+}
+
+// roots returns the root hashes of the stored tries matching the given filter.
+func (s *DiskTrieStore) roots(filter func(complete bool, stored time.Time) bool) ([]ledger.RootHash, error) {
+	var rootHashes []ledger.RootHash
+	err := s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{PrefetchValues: true, PrefetchSize: 100, Prefix: []byte{codeRoot}})
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			rootHash, err := ledger.ToRootHash(item.Key()[1:])
+			if err != nil {
+				return err
+			}
+			err = item.Value(func(val []byte) error {
+				complete, stored, err := decodeRoot(val)
+				if err != nil {
+					return err
+				}
+				if filter(complete, stored) {
+					rootHashes = append(rootHashes, rootHash)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list stored tries: %w", err)
+	}
+	return rootHashes, nil
+}
+
+// PruneBefore removes the tries which were written to disk before the given
+// time, and returns the number of removed tries.
+func (s *DiskTrieStore) PruneBefore(cutoff time.Time) (int, error) {
+	s.dbMu.Lock()
+	defer s.dbMu.Unlock()
+
+	rootHashes, err := s.roots(func(complete bool, stored time.Time) bool {
+		return complete && stored.Before(cutoff)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, rootHash := range rootHashes {
+		found, err := s.release(rootHash)
+		if err != nil {
+			return removed, fmt.Errorf("cannot remove trie %s: %w", rootHash, err)
+		}
+		if found {
+			removed++
+		}
+	}
+
+	return removed, nil
+}
+
+// Backup writes a consistent snapshot of the stored tries to the given writer,
+// while tries keep being stored.
+func (s *DiskTrieStore) Backup(w io.Writer) error {
+	_, err := s.db.Backup(w, 0)
+	return err
+}
+
+// RestoreTrieStore loads the snapshot written by Backup into a new trie store in
+// the given directory.
+func RestoreTrieStore(log zerolog.Logger, snapshot string, dir string) error {
+	f, err := os.Open(snapshot)
+	if err != nil {
+		return fmt.Errorf("cannot open trie store snapshot: %w", err)
+	}
+	defer f.Close()
+
+	store, err := NewDiskTrieStore(log, dir)
+	if err != nil {
+		return err
+	}
+	err = store.db.Load(f, maxPendingWrites)
+	if err != nil {
+		_ = store.Close()
+		return fmt.Errorf("cannot load trie store snapshot: %w", err)
+	}
+	return store.Close()
+}
+
+// batch groups the changes of the database into transactions. Each change is a
+// unit of a few writes, which is committed atomically, while the batch commits
+// a transaction every maxBatchUnits units. Values are small, or stored outside
+// of the LSM tree, so the transactions stay well within the size limits.
+type batch struct {
+	db    *badger.DB
+	txn   *badger.Txn
+	units int
+}
+
+func (s *DiskTrieStore) newBatch() *batch {
+	return &batch{db: s.db, txn: s.db.NewTransaction(true)}
+}
+
+func (b *batch) unit(f func(txn *badger.Txn) error) error {
+	if b.units >= maxBatchUnits {
+		err := b.commit()
+		if err != nil {
+			return err
+		}
+	}
+	b.units++
+	return f(b.txn)
+}
+
+// commit commits the pending units, and starts a new transaction.
+func (b *batch) commit() error {
+	err := b.txn.Commit()
+	b.txn = b.db.NewTransaction(true)
+	b.units = 0
+	return err
+}
+
+func (b *batch) discard() {
+	b.txn.Discard()
+}
+
+func nodeKey(nodeHash hash.Hash) []byte {
+	return append([]byte{codeNode}, nodeHash[:]...)
+}
+
+func rootKey(rootHash ledger.RootHash) []byte {
+	return append([]byte{codeRoot}, rootHash[:]...)
+}
+
+func garbageKey(nodeHash hash.Hash) []byte {
+	return append([]byte{codeGarbage}, nodeHash[:]...)
+}
+
+func setRoot(txn *badger.Txn, rootHash ledger.RootHash, complete bool, stored time.Time) error {
+	var state uint8
+	if complete {
+		state = 1
+	}
+	val := utils.AppendUint8(nil, state)
+	val = utils.AppendUint64(val, uint64(stored.UnixNano()))
+	return txn.Set(rootKey(rootHash), val)
+}
+
+func readRoot(txn *badger.Txn, rootHash ledger.RootHash) (complete bool, stored time.Time, found bool, err error) {
+	item, err := txn.Get(rootKey(rootHash))
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return false, time.Time{}, false, nil
+	}
+	if err != nil {
+		return false, time.Time{}, false, err
+	}
+	err = item.Value(func(val []byte) error {
+		complete, stored, err = decodeRoot(val)
+		return err
+	})
+	return complete, stored, err == nil, err
+}
+
+func decodeRoot(val []byte) (bool, time.Time, error) {
+	state, rest, err := utils.ReadUint8(val)
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("cannot decode trie root: %w", err)
+	}
+	stored, _, err := utils.ReadUint64(rest)
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("cannot decode trie root: %w", err)
+	}
+	return state == 1, time.Unix(0, int64(stored)), nil
+}
+
+// nodeRecord is a stored node. Leaves have a payload, while interim nodes have
+// the hashes of their children, if any.
+type nodeRecord struct {
+	refs    uint64
+	flags   byte
+	height  int
+	path    ledger.Path
+	payload *ledger.Payload
+	left    *hash.Hash
+	right   *hash.Hash
+}
+
+func newNodeRecord(n *node.Node) *nodeRecord {
+	rec := &nodeRecord{height: n.Height()}
+	if n.IsLeaf() {
+		rec.path = *n.Path()
+		rec.payload = n.Payload()
+		return rec
+	}
+	if n.LeftChild() != nil {
+		left := n.LeftChild().Hash()
+		rec.left = &left
+	}
+	if n.RightChild() != nil {
+		right := n.RightChild().Hash()
+		rec.right = &right
+	}
+	return rec
+}
+
+// complete returns true if the references to all children of the node are
+// accounted for.
+func (rec *nodeRecord) complete() bool {
+	return (rec.left == nil || rec.flags&flagLeft != 0) && (rec.right == nil || rec.flags&flagRight != 0)
+}
+
+func setNode(txn *badger.Txn, nodeHash hash.Hash, rec *nodeRecord) error {
+	val := utils.AppendUint64(nil, rec.refs)
+	val = utils.AppendUint8(val, rec.flags)
+	val = utils.AppendUint16(val, uint16(rec.height))
+	if rec.payload != nil {
+		val = utils.AppendUint8(val, 0)
+		val = append(val, rec.path[:]...)
+		val = utils.AppendLongData(val, encoding.EncodePayload(rec.payload))
+	} else {
+		val = utils.AppendUint8(val, 1)
+		for _, child := range []*hash.Hash{rec.left, rec.right} {
+			var childHash []byte
+			if child != nil {
+				childHash = child[:]
+			}
+			val = utils.AppendShortData(val, childHash)
+		}
+	}
+	return txn.Set(nodeKey(nodeHash), val)
+}
+
+func readNode(txn *badger.Txn, nodeHash hash.Hash) (*nodeRecord, bool, error) {
+	item, err := txn.Get(nodeKey(nodeHash))
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	var rec *nodeRecord
+	err = item.Value(func(val []byte) error {
+		rec, err = decodeNode(val)
+		return err
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot read node %s: %w", nodeHash, err)
+	}
+	return rec, true, nil
+}
+
+func decodeNode(val []byte) (*nodeRecord, error) {
+	rec := &nodeRecord{}
+	var err error
+	var height uint16
+	var kind uint8
+	rec.refs, val, err = utils.ReadUint64(val)
+	if err != nil {
+		return nil, err
+	}
+	rec.flags, val, err = utils.ReadUint8(val)
+	if err != nil {
+		return nil, err
+	}
+	height, val, err = utils.ReadUint16(val)
+	if err != nil {
+		return nil, err
+	}
+	rec.height = int(height)
+	kind, val, err = utils.ReadUint8(val)
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == 0 {
+		if len(val) < ledger.PathLen {
+			return nil, fmt.Errorf("leaf is too short")
+		}
+		rec.path, err = ledger.ToPath(val[:ledger.PathLen])
+		if err != nil {
+			return nil, err
+		}
+		encPayload, err := utils.ReadLongDataFromReader(bytes.NewReader(val[ledger.PathLen:]))
+		if err != nil {
+			return nil, err
+		}
+		rec.payload, err = encoding.DecodePayload(encPayload)
+		if err != nil {
+			return nil, err
+		}
+		return rec, nil
+	}
+
+	for _, child := range []**hash.Hash{&rec.left, &rec.right} {
+		var childHash []byte
+		childHash, val, err = utils.ReadShortData(val)
+		if err != nil {
+			return nil, err
+		}
+		if len(childHash) == 0 {
+			continue
+		}
+		h, err := hash.ToHash(childHash)
+		if err != nil {
+			return nil, err
+		}
+		*child = &h
+		val = val[len(childHash):]
+	}
+	return rec, nil
+}
@@ -0,0 +1,353 @@
+package wal
+
+import (
+	"io"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestDiskTrieStore(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		store, err := NewDiskTrieStore(zerolog.Nop(), path.Join(dir, DefaultTrieStoreDir))
+		require.NoError(t, err)
+		defer store.Close()
+
+		paths := []ledger.Path{utils.PathByUint8(1), utils.PathByUint8(2)}
+		payloads := []ledger.Payload{*utils.LightPayload8(1, 1), *utils.LightPayload8(2, 2)}
+		mTrie, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloads)
+		require.NoError(t, err)
+		rootHash := mTrie.RootHash()
+
+		t.Run("missing trie", func(t *testing.T) {
+			assert.False(t, store.Has(rootHash))
+			_, err := store.LoadTrie(rootHash, nil)
+			require.Error(t, err)
+		})
+
+		t.Run("store and load", func(t *testing.T) {
+			require.NoError(t, store.StoreTrie(mTrie))
+			assert.True(t, store.Has(rootHash))
+
+			// storing a trie again is a no-op
+			require.NoError(t, store.StoreTrie(mTrie))
+
+			loaded, err := store.LoadTrie(rootHash, nil)
+			require.NoError(t, err)
+			assert.Equal(t, rootHash, loaded.RootHash())
+			assert.Equal(t, mTrie.UnsafeRead(paths), loaded.UnsafeRead(paths))
+		})
+
+		t.Run("flush", func(t *testing.T) {
+			// tries are written in the background
+			store.Flush()
+			assert.True(t, store.Has(rootHash))
+			assert.Empty(t, store.pending)
+
+			loaded, err := store.LoadTrie(rootHash, nil)
+			require.NoError(t, err)
+			assert.Equal(t, rootHash, loaded.RootHash())
+			assert.NotSame(t, mTrie.RootNode(), loaded.RootNode())
+			assert.Equal(t, mTrie.UnsafeRead(paths), loaded.UnsafeRead(paths))
+		})
+
+		t.Run("remove", func(t *testing.T) {
+			require.NoError(t, store.RemoveTrie(rootHash))
+			assert.False(t, store.Has(rootHash))
+			assert.Zero(t, countNodes(t, store))
+
+			// removing a missing trie is a no-op
+			require.NoError(t, store.RemoveTrie(rootHash))
+		})
+
+		t.Run("prune", func(t *testing.T) {
+			require.NoError(t, store.StoreTrie(mTrie))
+			store.Flush()
+
+			removed, err := store.PruneBefore(time.Now().Add(-time.Hour))
+			require.NoError(t, err)
+			assert.Equal(t, 0, removed)
+			assert.True(t, store.Has(rootHash))
+
+			removed, err = store.PruneBefore(time.Now().Add(time.Second))
+			require.NoError(t, err)
+			assert.Equal(t, 1, removed)
+			assert.False(t, store.Has(rootHash))
+			assert.Zero(t, countNodes(t, store))
+		})
+
+		t.Run("empty trie", func(t *testing.T) {
+			emptyTrie := trie.NewEmptyMTrie()
+			require.NoError(t, store.StoreTrie(emptyTrie))
+			store.Flush()
+
+			assert.True(t, store.Has(emptyTrie.RootHash()))
+			loaded, err := store.LoadTrie(emptyTrie.RootHash(), nil)
+			require.NoError(t, err)
+			assert.True(t, loaded.IsEmpty())
+		})
+	})
+}
+
+// TestDiskTrieStore_Incremental tests that storing a trie derived from a stored
+// trie only writes the nodes on the paths of the updated registers, and that
+// removing tries only removes the nodes which are not shared with other tries.
+func TestDiskTrieStore_Incremental(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		store, err := NewDiskTrieStore(zerolog.Nop(), path.Join(dir, DefaultTrieStoreDir))
+		require.NoError(t, err)
+		defer store.Close()
+
+		base, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), utils.RandomPaths(1000), payloadValues(utils.RandomPayloads(1000, 1, 32)))
+		require.NoError(t, err)
+		require.NoError(t, store.StoreTrie(base))
+		store.Flush()
+		baseNodes := countNodes(t, store)
+		assert.Equal(t, countTrieNodes(base.RootNode()), baseNodes)
+
+		// each eviction of a derived trie writes at most the nodes on the path to
+		// the inserted register, and the leaf it displaces
+		tries := []*trie.MTrie{base}
+		for i := 0; i < 10; i++ {
+			parent := tries[len(tries)-1]
+			updated, err := trie.NewTrieWithUpdatedRegisters(parent, utils.RandomPaths(1), payloadValues(utils.RandomPayloads(1, 1, 32)))
+			require.NoError(t, err)
+
+			before := countNodes(t, store)
+			require.NoError(t, store.StoreTrie(updated))
+			store.Flush()
+			assert.LessOrEqual(t, countNodes(t, store)-before, int(updated.MaxDepth())+2)
+
+			tries = append(tries, updated)
+		}
+
+		// removing the derived tries removes their nodes, but keeps the nodes of
+		// the base trie
+		for _, derived := range tries[1:] {
+			require.NoError(t, store.RemoveTrie(derived.RootHash()))
+		}
+		assert.Equal(t, baseNodes, countNodes(t, store))
+
+		loaded, err := store.LoadTrie(base.RootHash(), nil)
+		require.NoError(t, err)
+		assert.True(t, loaded.Equals(base))
+
+		require.NoError(t, store.RemoveTrie(base.RootHash()))
+		assert.Zero(t, countNodes(t, store))
+	})
+}
+
+// TestDiskTrieStore_Sharing tests that a reloaded trie shares its unchanged
+// sub-tries with the resident tries.
+func TestDiskTrieStore_Sharing(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		store, err := NewDiskTrieStore(zerolog.Nop(), path.Join(dir, DefaultTrieStoreDir))
+		require.NoError(t, err)
+		defer store.Close()
+
+		paths := utils.RandomPaths(1000)
+		base, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloadValues(utils.RandomPayloads(1000, 1, 32)))
+		require.NoError(t, err)
+		updated, err := trie.NewTrieWithUpdatedRegisters(base, paths[:1], payloadValues(utils.RandomPayloads(1, 33, 64)))
+		require.NoError(t, err)
+
+		require.NoError(t, store.StoreTrie(updated))
+		store.Flush()
+
+		// without resident tries, all nodes are read from disk
+		loaded, err := store.LoadTrie(updated.RootHash(), nil)
+		require.NoError(t, err)
+		assert.True(t, loaded.Equals(updated))
+		assert.Equal(t, countTrieNodes(updated.RootNode()), countUnshared(loaded.RootNode(), base.RootNode()))
+
+		// with the base trie resident, only the nodes on the path to the updated
+		// register are read from disk
+		loaded, err = store.LoadTrie(updated.RootHash(), []*trie.MTrie{base})
+		require.NoError(t, err)
+		assert.True(t, loaded.Equals(updated))
+		assert.Equal(t, updated.UnsafeRead(paths), loaded.UnsafeRead(paths))
+		assert.LessOrEqual(t, countUnshared(loaded.RootNode(), base.RootNode()), int(updated.MaxDepth())+1)
+
+		// the resident trie itself is returned as is
+		require.NoError(t, store.StoreTrie(base))
+		store.Flush()
+		loaded, err = store.LoadTrie(base.RootHash(), []*trie.MTrie{updated, base})
+		require.NoError(t, err)
+		assert.Same(t, base.RootNode(), loaded.RootNode())
+	})
+}
+
+// TestDiskTrieStore_Recover tests that the nodes of a trie which was not stored
+// completely are removed when the store is opened again.
+func TestDiskTrieStore_Recover(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		storeDir := path.Join(dir, DefaultTrieStoreDir)
+		store, err := NewDiskTrieStore(zerolog.Nop(), storeDir)
+		require.NoError(t, err)
+
+		mTrie, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), utils.RandomPaths(100), payloadValues(utils.RandomPayloads(100, 1, 32)))
+		require.NoError(t, err)
+		rootHash := mTrie.RootHash()
+
+		// write the root node and the left child, as a write interrupted by a
+		// crash would
+		err = store.db.Update(func(txn *badger.Txn) error {
+			err := setRoot(txn, rootHash, false, time.Now())
+			require.NoError(t, err)
+			rec, _, err := reference(txn, mTrie.RootNode())
+			require.NoError(t, err)
+			_, _, err = reference(txn, mTrie.RootNode().LeftChild())
+			require.NoError(t, err)
+			rec.flags |= flagLeft
+			return setNode(txn, mTrie.RootNode().Hash(), rec)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, countNodes(t, store))
+		assert.False(t, store.Has(rootHash))
+		require.NoError(t, store.Close())
+
+		store, err = NewDiskTrieStore(zerolog.Nop(), storeDir)
+		require.NoError(t, err)
+		defer store.Close()
+		assert.Zero(t, countNodes(t, store))
+
+		// the trie can be stored completely afterwards
+		require.NoError(t, store.StoreTrie(mTrie))
+		store.Flush()
+		loaded, err := store.LoadTrie(rootHash, nil)
+		require.NoError(t, err)
+		assert.True(t, loaded.Equals(mTrie))
+	})
+}
+
+// TestCheckpointer_TrieStore tests that checkpointing a WAL with a trie store
+// succeeds if updates are applied to tries evicted while replaying.
+func TestCheckpointer_TrieStore(t *testing.T) {
+	capacity := 2
+	metricsCollector := &metrics.NoopCollector{}
+
+	unittest.RunWithTempDir(t, func(dir string) {
+		newWAL := func() *DiskWAL {
+			diskWal, err := NewDiskWAL(zerolog.Nop(), nil, metricsCollector, dir, capacity, pathfinder.PathByteSize, segmentSize)
+			require.NoError(t, err)
+
+			store, err := NewDiskTrieStore(zerolog.Nop(), path.Join(dir, DefaultTrieStoreDir))
+			require.NoError(t, err)
+			diskWal.SetTrieStore(store)
+			return diskWal
+		}
+		diskWal := newWAL()
+
+		// compute the updates on a forest without eviction
+		forest, err := mtrie.NewForest(10, metricsCollector, nil)
+		require.NoError(t, err)
+
+		// extend a chain of tries beyond the capacity, such that the empty trie is
+		// evicted when replaying, then fork off the empty trie
+		emptyRootHash := trie.EmptyTrieRootHash()
+		rootHash := emptyRootHash
+		for i := 0; i <= capacity; i++ {
+			update := &ledger.TrieUpdate{
+				RootHash: rootHash,
+				Paths:    []ledger.Path{utils.PathByUint8(uint8(i))},
+				Payloads: []*ledger.Payload{utils.LightPayload8(uint8(i), uint8(i))},
+			}
+			require.NoError(t, diskWal.RecordUpdate(update))
+			rootHash, err = forest.Update(update)
+			require.NoError(t, err)
+		}
+		fork := &ledger.TrieUpdate{
+			RootHash: emptyRootHash,
+			Paths:    []ledger.Path{utils.PathByUint8(100)},
+			Payloads: []*ledger.Payload{utils.LightPayload8(100, 100)},
+		}
+		require.NoError(t, diskWal.RecordUpdate(fork))
+		<-diskWal.Done()
+
+		diskWal = newWAL()
+
+		checkpointer, err := diskWal.NewCheckpointer()
+		require.NoError(t, err)
+
+		_, to, err := checkpointer.NotCheckpointedSegments()
+		require.NoError(t, err)
+		require.NoError(t, checkpointer.Checkpoint(to, func() (io.WriteCloser, error) {
+			return checkpointer.CheckpointWriter(to)
+		}))
+
+		<-diskWal.Done()
+	})
+}
+
+func payloadValues(payloads []*ledger.Payload) []ledger.Payload {
+	values := make([]ledger.Payload, 0, len(payloads))
+	for _, payload := range payloads {
+		values = append(values, *payload)
+	}
+	return values
+}
+
+// countNodes returns the number of nodes in the trie store database.
+func countNodes(t *testing.T, store *DiskTrieStore) int {
+	count := 0
+	err := store.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: []byte{codeNode}})
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			count++
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return count
+}
+
+// countTrieNodes returns the number of nodes in the given sub-trie.
+func countTrieNodes(n *node.Node) int {
+	if n == nil {
+		return 0
+	}
+	return 1 + countTrieNodes(n.LeftChild()) + countTrieNodes(n.RightChild())
+}
+
+// countUnshared returns the number of nodes of the given sub-trie which are not
+// nodes of the other sub-trie.
+func countUnshared(n *node.Node, other *node.Node) int {
+	shared := make(map[*node.Node]struct{})
+	var collect func(n *node.Node)
+	collect = func(n *node.Node) {
+		if n == nil {
+			return
+		}
+		shared[n] = struct{}{}
+		collect(n.LeftChild())
+		collect(n.RightChild())
+	}
+	collect(other)
+
+	var count func(n *node.Node) int
+	count = func(n *node.Node) int {
+		if n == nil {
+			return 0
+		}
+		if _, ok := shared[n]; ok {
+			return 0
+		}
+		return 1 + count(n.LeftChild()) + count(n.RightChild())
+	}
+	return count(n)
+}
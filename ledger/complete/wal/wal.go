@@ -28,6 +28,7 @@ type DiskWAL struct {
 	diskUpdateLimiter *time.Ticker
 	metrics           module.WALMetrics
 	dir               string
+	trieStore         mtrie.TrieStore
 }
 
 // TODO use real logger and metrics, but that would require passing them to Trie storage
@@ -48,6 +49,19 @@ func NewDiskWAL(logger zerolog.Logger, reg prometheus.Registerer, metrics module
 	}, nil
 }
 
+// SetTrieStore sets the store which persists the tries evicted from forests
+// replaying this WAL. It must be set before the WAL is replayed. The WAL closes
+// the store when it is done.
+func (w *DiskWAL) SetTrieStore(store mtrie.TrieStore) {
+	w.trieStore = store
+}
+
+// TrieStore returns the store which persists evicted tries, or nil if evicted
+// tries are not persisted.
+func (w *DiskWAL) TrieStore() mtrie.TrieStore {
+	return w.trieStore
+}
+
 func (w *DiskWAL) PauseRecord() {
 	w.paused = true
 }
@@ -321,8 +335,15 @@ func (w *DiskWAL) Ready() <-chan struct{} {
 }
 
 // Done implements interface module.ReadyDoneAware
-// it closes all the open write-ahead log files.
+// it closes all the open write-ahead log files, after the evicted tries queued
+// in the trie store are written and the trie store is closed.
 func (w *DiskWAL) Done() <-chan struct{} {
+	if store, ok := w.trieStore.(*DiskTrieStore); ok {
+		err := store.Close()
+		if err != nil {
+			w.log.Err(err).Msg("error while closing trie store")
+		}
+	}
 	err := w.wal.Close()
 	if err != nil {
 		w.log.Err(err).Msg("error while closing WAL")
@@ -336,6 +357,7 @@ type LedgerWAL interface {
 	module.ReadyDoneAware
 
 	NewCheckpointer() (*Checkpointer, error)
+	TrieStore() mtrie.TrieStore
 	PauseRecord()
 	UnpauseRecord()
 	RecordUpdate(update *ledger.TrieUpdate) error
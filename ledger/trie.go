@@ -320,6 +320,24 @@ func (p *TrieProof) Equals(o *TrieProof) bool {
 	return true
 }
 
+// SubTrieProof includes all the information needed to walk through a trie
+// branch from the root of a subtrie up to the root of the trie.
+type SubTrieProof struct {
+	Path     Path        // path of the subtrie, only the first Steps bits are relevant
+	Interims []hash.Hash // the non-default intermediate nodes in the proof
+	Flags    []byte      // The flags of the proofs (is set if an intermediate node has a non-default)
+	Steps    uint16      // depth of the subtrie root, i.e. number of steps up to the trie root
+}
+
+// NewSubTrieProof creates a new instance of SubTrieProof
+func NewSubTrieProof() *SubTrieProof {
+	return &SubTrieProof{
+		Interims: make([]hash.Hash, 0),
+		Flags:    make([]byte, PathLen),
+		Steps:    0,
+	}
+}
+
 // TrieBatchProof is a struct that holds the proofs for several keys
 //
 // so there is no need for two calls (read, proofs)
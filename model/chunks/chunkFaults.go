@@ -73,6 +73,16 @@ func (cf CFNonMatchingFinalState) ExecutionResultID() flow.Identifier {
 	return cf.execResID
 }
 
+// Expected returns the end state commitment of the faulty chunk claimed by the execution result
+func (cf CFNonMatchingFinalState) Expected() flow.StateCommitment {
+	return cf.expected
+}
+
+// Computed returns the end state commitment computed by executing the faulty chunk
+func (cf CFNonMatchingFinalState) Computed() flow.StateCommitment {
+	return cf.computed
+}
+
 // NewCFNonMatchingFinalState creates a new instance of Chunk Fault (NonMatchingFinalState)
 func NewCFNonMatchingFinalState(expected flow.StateCommitment, computed flow.StateCommitment, chInx uint64, execResID flow.Identifier) *CFNonMatchingFinalState {
 	return &CFNonMatchingFinalState{expected: expected,
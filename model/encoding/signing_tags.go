@@ -29,6 +29,8 @@ var (
 	ExecutionReceiptTag = tag("Execution-Receipt")
 	// ResultApprovalTag is used for result approvals
 	ResultApprovalTag = tag("Result-Approval")
+	// ChunkChallengeTag is used for chunk challenges
+	ChunkChallengeTag = tag("Chunk-Challenge")
 	// SPOCKTag is used to generate SPoCK proofs
 	SPOCKTag = tag("SPoCK")
 	// DKGMessageTag is used for DKG messages
@@ -0,0 +1,125 @@
+package flow
+
+import (
+	"github.com/onflow/flow-go/crypto"
+)
+
+// ChunkFaultType identifies the kind of fault a verification node found when
+// verifying a chunk.
+type ChunkFaultType string
+
+const (
+	// ChunkFaultNonMatchingFinalState is raised when the final state computed
+	// by the verification node differs from the one committed by the chunk.
+	ChunkFaultNonMatchingFinalState ChunkFaultType = "non_matching_final_state"
+	// ChunkFaultInvalidVerifiableChunk is raised when the chunk data pack or
+	// the chunk itself is malformed, e.g. the proofs don't match the start state.
+	ChunkFaultInvalidVerifiableChunk ChunkFaultType = "invalid_verifiable_chunk"
+	// ChunkFaultInvalidEventsCollection is raised when the events computed by
+	// the verification node differ from the ones committed by the chunk.
+	ChunkFaultInvalidEventsCollection ChunkFaultType = "invalid_events_collection"
+)
+
+// ChunkChallengeBody holds the evidence of a chunk fault, i.e. everything a
+// third party needs to re-verify the faulty chunk: the challenged execution
+// result, the receipts of the execution nodes committing to it, and a reference
+// to the chunk data pack including the proofs of the registers touched by the
+// chunk. The chunk data pack itself can be too large for a network message, it
+// is requested from the execution nodes by the chunk ID instead, and matched
+// against the referenced fingerprint.
+type ChunkChallengeBody struct {
+	ChallengerID    Identifier              // node ID of the verification node raising the challenge
+	BlockID         Identifier              // ID of the executed block
+	ExecutionResult ExecutionResult         // the challenged execution result
+	ChunkIndex      uint64                  // index of the faulty chunk in the result
+	FaultType       ChunkFaultType          // kind of the chunk fault
+	Fault           string                  // human readable description of the chunk fault
+	Receipts        []*ExecutionReceiptMeta // receipts committing to the challenged result
+	ChunkDataPackID Identifier              // fingerprint of the chunk data pack the chunk was verified against
+}
+
+// ID generates a unique identifier using the challenge body.
+func (cb ChunkChallengeBody) ID() Identifier {
+	return MakeID(cb.encodable())
+}
+
+// encodable returns a representation of the challenge body which can be
+// fingerprinted. The receipts are represented by their checksums.
+func (cb ChunkChallengeBody) encodable() interface{} {
+	receiptIDs := make([]Identifier, 0, len(cb.Receipts))
+	for _, receipt := range cb.Receipts {
+		receiptIDs = append(receiptIDs, receipt.Checksum())
+	}
+
+	return struct {
+		ChallengerID      Identifier
+		BlockID           Identifier
+		ExecutionResultID Identifier
+		ChunkIndex        uint64
+		FaultType         ChunkFaultType
+		Fault             string
+		ReceiptIDs        []Identifier
+		ChunkDataPackID   Identifier
+	}{
+		ChallengerID:      cb.ChallengerID,
+		BlockID:           cb.BlockID,
+		ExecutionResultID: cb.ExecutionResult.ID(),
+		ChunkIndex:        cb.ChunkIndex,
+		FaultType:         cb.FaultType,
+		Fault:             cb.Fault,
+		ReceiptIDs:        receiptIDs,
+		ChunkDataPackID:   cb.ChunkDataPackID,
+	}
+}
+
+// ChunkDataPackFingerprint returns the fingerprint by which a chunk challenge
+// references the given chunk data pack. Unlike the ID of the chunk data pack,
+// which is the ID of its chunk, it covers the full content of the pack. The
+// collection is represented by its checksum, as transaction bodies can't be
+// encoded directly, and is nil for system chunks.
+func ChunkDataPackFingerprint(pack *ChunkDataPack) Identifier {
+	var collectionID Identifier
+	if pack.Collection != nil {
+		collectionID = pack.Collection.Checksum()
+	}
+
+	return MakeID(struct {
+		ChunkID      Identifier
+		StartState   StateCommitment
+		Proof        StorageProof
+		CollectionID Identifier
+	}{
+		ChunkID:      pack.ChunkID,
+		StartState:   pack.StartState,
+		Proof:        pack.Proof,
+		CollectionID: collectionID,
+	})
+}
+
+// ChunkChallenge is the signed evidence of a chunk fault, which a verification
+// node raises instead of approving the chunk.
+type ChunkChallenge struct {
+	Body                ChunkChallengeBody
+	ChallengerSignature crypto.Signature // signature over the challenge body
+}
+
+// ID generates a unique identifier using the challenge body.
+func (c ChunkChallenge) ID() Identifier {
+	return c.Body.ID()
+}
+
+// Checksum generates checksum using the full content of the challenge.
+func (c ChunkChallenge) Checksum() Identifier {
+	return MakeID(struct {
+		BodyID              Identifier
+		ChallengerSignature crypto.Signature
+	}{
+		BodyID:              c.Body.ID(),
+		ChallengerSignature: c.ChallengerSignature,
+	})
+}
+
+// ExecutionResultID returns the ID of the challenged execution result.
+func (c ChunkChallenge) ExecutionResultID() Identifier {
+	return c.Body.ExecutionResult.ID()
+}
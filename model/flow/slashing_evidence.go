@@ -0,0 +1,40 @@
+package flow
+
+// SlashingViolation identifies a slashable protocol violation of a consensus
+// participant, either of the main consensus or of a collection cluster.
+type SlashingViolation string
+
+const (
+	// SlashingViolationDoubleVote is committed by voting for two different
+	// blocks in the same view.
+	SlashingViolationDoubleVote SlashingViolation = "double_vote"
+	// SlashingViolationInvalidVote is committed by sending a vote with an
+	// invalid signature, or for a block at a different view.
+	SlashingViolationInvalidVote SlashingViolation = "invalid_vote"
+	// SlashingViolationDoubleProposal is committed by proposing two different
+	// blocks in the same view.
+	SlashingViolationDoubleProposal SlashingViolation = "double_proposal"
+)
+
+// SlashingVote is a consensus vote as it was received from the voter,
+// including the voter's signature.
+type SlashingVote struct {
+	View     uint64
+	BlockID  Identifier
+	SignerID Identifier
+	SigData  []byte
+}
+
+// SlashingEvidence is the durable proof of a slashable protocol violation. It
+// holds everything a third party needs to verify the violation: the conflicting
+// votes or proposals with their signatures, and the identity of the offender,
+// including its staking key, at the time of the violation.
+type SlashingEvidence struct {
+	Violation  SlashingViolation
+	ChainID    ChainID         // chain on which the violation was committed, main consensus or cluster
+	View       uint64          // view in which the violation was committed
+	OffenderID Identifier      // node ID of the offender
+	Offender   *Identity       // identity of the offender, nil if it is unknown
+	Votes      []*SlashingVote // the conflicting votes, or the invalid vote
+	Proposals  []*Header       // the conflicting proposals, including the proposer signatures
+}
@@ -0,0 +1,14 @@
+package flow
+
+// TimeoutCertificate represents a timeout certificate for a view as used for the view synchronization of the
+// HotStuff algorithm. A timeout certificate is a collection of timeouts for a particular view. Valid timeout
+// certificates contain signatures from a super-majority of consensus committee members, which proves that the
+// super-majority has given up on the view. It carries the highest quorum certificate among the aggregated
+// timeouts, such that replicas which missed it can catch up. The aggregated signature only covers the view,
+// the highest QC is a QC in its own right and validated separately.
+type TimeoutCertificate struct {
+	View      uint64
+	HighestQC *QuorumCertificate
+	SignerIDs []Identifier
+	SigData   []byte
+}
@@ -39,3 +39,12 @@ type ClusterBlockVote struct {
 	View    uint64
 	SigData []byte
 }
+
+// ClusterTimeoutObject is a timeout for a round in collection node cluster
+// consensus, which includes the highest QC and TC known to the node.
+type ClusterTimeoutObject struct {
+	View      uint64
+	HighestQC *flow.QuorumCertificate
+	HighestTC *flow.TimeoutCertificate
+	SigData   []byte
+}
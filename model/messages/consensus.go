@@ -18,3 +18,13 @@ type BlockVote struct {
 	View    uint64
 	SigData []byte
 }
+
+// TimeoutObject is part of the consensus protocol and represents a consensus node
+// timing out on a given round. It includes the highest QC and TC known to the
+// node, which allows other nodes to synchronize their round.
+type TimeoutObject struct {
+	View      uint64
+	HighestQC *flow.QuorumCertificate
+	HighestTC *flow.TimeoutCertificate
+	SigData   []byte
+}
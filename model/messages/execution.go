@@ -29,6 +29,48 @@ type ExecutionStateSyncRequest struct {
 	ToHeight   uint64
 }
 
+// CheckpointChunkRequest represents a request for one chunk of the execution
+// state with the given state commitment. The state is split into 2^Depth chunks,
+// each chunk being the subtrie at the given depth with the given index.
+type CheckpointChunkRequest struct {
+	StateCommitment flow.StateCommitment
+	Depth           uint8
+	Index           uint64
+	Nonce           uint64 // so that we aren't deduplicated by the network layer
+}
+
+// CheckpointChunkResponse is the response to a checkpoint chunk request. It
+// contains the encoded nodes of the requested subtrie, root last, and the encoded
+// proof of the subtrie against the state commitment.
+type CheckpointChunkResponse struct {
+	StateCommitment flow.StateCommitment
+	Depth           uint8
+	Index           uint64
+	Nodes           [][]byte
+	Proof           []byte
+	Nonce           uint64 // nonce of the request, to match the response with it
+}
+
+// CheckpointStatesRequest asks an execution node for the execution states of
+// sealed blocks it can serve to execution nodes bootstrapping from them.
+type CheckpointStatesRequest struct {
+	Nonce uint64 // so that we aren't deduplicated by the network layer
+}
+
+// CheckpointState is the execution state of a sealed block.
+type CheckpointState struct {
+	BlockID         flow.Identifier
+	StateCommitment flow.StateCommitment
+}
+
+// CheckpointStatesResponse is the response to a checkpoint states request. It
+// lists the execution states of sealed blocks the execution node serves, highest
+// block first.
+type CheckpointStatesResponse struct {
+	States []CheckpointState
+	Nonce  uint64 // nonce of the request, to match the response with it
+}
+
 type ExecutionStateDelta struct {
 	entity.ExecutableBlock
 	StateInteractions  []*delta.Snapshot
@@ -1,6 +1,8 @@
 package verification
 
 import (
+	"fmt"
+
 	"github.com/onflow/flow-go/model/flow"
 )
 
@@ -15,3 +17,42 @@ type VerifiableChunkData struct {
 	EndState          flow.StateCommitment  // state commitment at the end of this chunk
 	TransactionOffset uint32                // index of the first transaction in a chunk within a block
 }
+
+// EndStateCommitment computes the end state of the given chunk.
+func EndStateCommitment(result *flow.ExecutionResult, chunkIndex uint64, systemChunk bool) (flow.StateCommitment, error) {
+	var endState flow.StateCommitment
+	if systemChunk {
+		var err error
+		// last chunk in a result is the system chunk and takes final state commitment
+		endState, err = result.FinalStateCommitment()
+		if err != nil {
+			return flow.DummyStateCommitment, fmt.Errorf("can not read final state commitment, likely a bug:%w", err)
+		}
+	} else {
+		// any chunk except last takes the subsequent chunk's start state
+		endState = result.Chunks[chunkIndex+1].StartState
+	}
+
+	return endState, nil
+}
+
+//TransactionOffsetForChunk calculates transaction offset for a given chunk which is the index of the first
+// transaction of this chunk within the whole block
+func TransactionOffsetForChunk(chunks flow.ChunkList, chunkIndex uint64) (uint32, error) {
+	if int(chunkIndex) > len(chunks)-1 {
+		return 0, fmt.Errorf("chunk list out of bounds, len %d asked for chunk %d", len(chunks), chunkIndex)
+	}
+	var offset uint32 = 0
+	for i := 0; i < int(chunkIndex); i++ {
+		offset += uint32(chunks[i].NumberOfTransactions)
+	}
+	return offset, nil
+}
+
+// IsSystemChunk returns true if `chunkIndex` points to a system chunk in `result`.
+// Otherwise, it returns false.
+// In the current version, a chunk is a system chunk if it is the last chunk of the
+// execution result.
+func IsSystemChunk(chunkIndex uint64, result *flow.ExecutionResult) bool {
+	return chunkIndex == uint64(len(result.Chunks)-1)
+}
@@ -0,0 +1,69 @@
+package verification_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/verification"
+)
+
+func TestTransactionOffsetForChunk(t *testing.T) {
+	t.Run("first chunk index always returns zero offset", func(t *testing.T) {
+		offsetForChunk, err := verification.TransactionOffsetForChunk([]*flow.Chunk{nil}, 0)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(0), offsetForChunk)
+	})
+
+	t.Run("offset is calculated", func(t *testing.T) {
+
+		chunksList := []*flow.Chunk{
+			{
+				ChunkBody: flow.ChunkBody{
+					NumberOfTransactions: 1,
+				},
+			},
+			{
+				ChunkBody: flow.ChunkBody{
+					NumberOfTransactions: 2,
+				},
+			},
+			{
+				ChunkBody: flow.ChunkBody{
+					NumberOfTransactions: 3,
+				},
+			},
+			{
+				ChunkBody: flow.ChunkBody{
+					NumberOfTransactions: 5,
+				},
+			},
+		}
+
+		offsetForChunk, err := verification.TransactionOffsetForChunk(chunksList, 0)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(0), offsetForChunk)
+
+		offsetForChunk, err = verification.TransactionOffsetForChunk(chunksList, 1)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1), offsetForChunk)
+
+		offsetForChunk, err = verification.TransactionOffsetForChunk(chunksList, 2)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(3), offsetForChunk)
+
+		offsetForChunk, err = verification.TransactionOffsetForChunk(chunksList, 3)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(6), offsetForChunk)
+	})
+
+	t.Run("requesting index beyond length triggers error", func(t *testing.T) {
+
+		chunksList := make([]*flow.Chunk, 2)
+
+		_, err := verification.TransactionOffsetForChunk(chunksList, 2)
+		require.Error(t, err)
+	})
+}
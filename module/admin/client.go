@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Client runs admin commands on the admin server of a node running on the same host.
+type Client struct {
+	token  string
+	client *http.Client
+}
+
+// NewClient creates a client of the admin server listening on the given socket. It
+// authenticates with the token the server wrote next to the socket.
+func NewClient(socketPath string) (*Client, error) {
+	token, err := ioutil.ReadFile(TokenFile(socketPath))
+	if err != nil {
+		return nil, fmt.Errorf("could not read token of admin server: %w", err)
+	}
+
+	dialer := &net.Dialer{}
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+
+	return &Client{
+		token:  strings.TrimSpace(string(token)),
+		client: client,
+	}, nil
+}
+
+// RunCommand runs the given admin command with the input data, and returns the JSON
+// encoded output of the command.
+func (c *Client) RunCommand(ctx context.Context, command string, data map[string]interface{}) (json.RawMessage, error) {
+	body, err := json.Marshal(&CommandRequest{CommandName: command, Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("could not encode request: %w", err)
+	}
+
+	// the host is ignored, the client always connects to the socket
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://admin"+CommandPath, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("could not create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not send request to admin server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Output json.RawMessage `json:"output"`
+		Error  string          `json:"error"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode response (status: %s): %w", resp.Status, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("admin command failed (status: %s): %s", resp.Status, response.Error)
+	}
+
+	return response.Output, nil
+}
@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ListCommandsCommand is the name of the command every command runner provides,
+// which lists the commands registered with the runner.
+const ListCommandsCommand = "list-commands"
+
+// ErrUnknownCommand is returned when running a command that is not registered.
+var ErrUnknownCommand = errors.New("unknown admin command")
+
+// CommandHandler runs an admin command with the given input data, and returns the
+// output of the command, which has to be encodable as JSON. Errors are reported
+// back to the caller of the command.
+type CommandHandler func(ctx context.Context, data map[string]interface{}) (interface{}, error)
+
+// CommandRunner keeps the admin commands of a node, and runs them by name.
+type CommandRunner struct {
+	sync.RWMutex
+	handlers map[string]CommandHandler
+}
+
+// NewCommandRunner creates a command runner with no commands but the one listing
+// the registered commands.
+func NewCommandRunner() *CommandRunner {
+	r := &CommandRunner{
+		handlers: make(map[string]CommandHandler),
+	}
+	r.handlers[ListCommandsCommand] = func(context.Context, map[string]interface{}) (interface{}, error) {
+		return r.Commands(), nil
+	}
+	return r
+}
+
+// RegisterHandler registers the handler of the given command. It returns an error
+// if a handler is already registered for the command.
+func (r *CommandRunner) RegisterHandler(command string, handler CommandHandler) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if _, ok := r.handlers[command]; ok {
+		return fmt.Errorf("handler already registered for admin command: %s", command)
+	}
+	r.handlers[command] = handler
+
+	return nil
+}
+
+// Commands returns the names of the registered commands, in alphabetical order.
+func (r *CommandRunner) Commands() []string {
+	r.RLock()
+	defer r.RUnlock()
+
+	commands := make([]string, 0, len(r.handlers))
+	for command := range r.handlers {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+
+	return commands
+}
+
+// RunCommand runs the given command with the input data, and returns its output. It
+// returns ErrUnknownCommand if no handler is registered for the command.
+func (r *CommandRunner) RunCommand(ctx context.Context, command string, data map[string]interface{}) (interface{}, error) {
+	r.RLock()
+	handler, ok := r.handlers[command]
+	r.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCommand, command)
+	}
+
+	return handler(ctx, data)
+}
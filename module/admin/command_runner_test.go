@@ -0,0 +1,52 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandRunner(t *testing.T) {
+	runner := NewCommandRunner()
+
+	echo := func(_ context.Context, data map[string]interface{}) (interface{}, error) {
+		return data, nil
+	}
+	fail := func(context.Context, map[string]interface{}) (interface{}, error) {
+		return nil, errors.New("failure")
+	}
+	require.NoError(t, runner.RegisterHandler("echo", echo))
+	require.NoError(t, runner.RegisterHandler("fail", fail))
+
+	t.Run("duplicate command", func(t *testing.T) {
+		err := runner.RegisterHandler("echo", echo)
+		require.Error(t, err)
+	})
+
+	t.Run("list commands", func(t *testing.T) {
+		output, err := runner.RunCommand(context.Background(), ListCommandsCommand, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"echo", "fail", ListCommandsCommand}, output)
+	})
+
+	t.Run("run command", func(t *testing.T) {
+		data := map[string]interface{}{"key": "value"}
+		output, err := runner.RunCommand(context.Background(), "echo", data)
+		require.NoError(t, err)
+		assert.Equal(t, data, output)
+	})
+
+	t.Run("failing command", func(t *testing.T) {
+		_, err := runner.RunCommand(context.Background(), "fail", nil)
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, ErrUnknownCommand))
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		_, err := runner.RunCommand(context.Background(), "unknown", nil)
+		require.True(t, errors.Is(err, ErrUnknownCommand))
+	})
+}
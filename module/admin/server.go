@@ -0,0 +1,190 @@
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// CommandPath is the HTTP path admin commands are run on.
+const CommandPath = "/admin/run_command"
+
+// tokenLength is the number of random bytes of the token authenticating clients.
+const tokenLength = 32
+
+// CommandRequest is the body of the request running an admin command.
+type CommandRequest struct {
+	CommandName string                 `json:"commandName"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+}
+
+// CommandResponse is the body of the response to running an admin command. Exactly
+// one of Output and Error is set.
+type CommandResponse struct {
+	Output interface{} `json:"output,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// TokenFile returns the path of the file holding the token that authenticates
+// clients of the admin server listening on the given socket.
+func TokenFile(socketPath string) string {
+	return socketPath + ".token"
+}
+
+// Server is the admin server of a node. It serves admin commands over HTTP on a unix
+// socket that only the user running the node can access. On top of this, requests
+// have to carry the token the server writes next to the socket when starting up, as
+// a bearer token.
+type Server struct {
+	log        zerolog.Logger
+	runner     *CommandRunner
+	socketPath string
+	token      []byte
+	server     *http.Server
+}
+
+// NewServer creates an admin server that runs the commands of the given runner, and
+// listens on the unix socket at the given path once started.
+func NewServer(log zerolog.Logger, runner *CommandRunner, socketPath string) *Server {
+	s := &Server{
+		log:        log.With().Str("component", "admin_server").Logger(),
+		runner:     runner,
+		socketPath: socketPath,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(CommandPath, s.handleCommand)
+	s.server = &http.Server{Handler: mux}
+
+	return s
+}
+
+// Ready returns a channel that is closed once the server listens on its socket.
+func (s *Server) Ready() <-chan struct{} {
+	ready := make(chan struct{})
+	go func() {
+		defer close(ready)
+
+		listener, err := s.listen()
+		if err != nil {
+			s.log.Error().Err(err).Str("socket", s.socketPath).Msg("could not start admin server")
+			return
+		}
+
+		go func() {
+			err := s.server.Serve(listener)
+			// http.ErrServerClosed is returned when Close or Shutdown is called
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				s.log.Err(err).Msg("error shutting down admin server")
+			}
+		}()
+
+		s.log.Info().Str("socket", s.socketPath).Msg("admin server started")
+	}()
+	return ready
+}
+
+// Done returns a channel that is closed once the server is shut down, and the socket
+// and token file are removed.
+func (s *Server) Done() <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_ = s.server.Shutdown(ctx)
+		cancel()
+		_ = os.Remove(TokenFile(s.socketPath))
+		close(done)
+	}()
+	return done
+}
+
+// listen opens the socket of the server, and writes the token clients have to present.
+func (s *Server) listen() (net.Listener, error) {
+	token := make([]byte, tokenLength)
+	_, err := rand.Read(token)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate token: %w", err)
+	}
+	s.token = []byte(hex.EncodeToString(token))
+
+	// a socket left behind by a previous run of the node prevents listening
+	err = os.Remove(s.socketPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("could not remove stale socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not listen on socket: %w", err)
+	}
+
+	err = os.Chmod(s.socketPath, 0600)
+	if err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("could not restrict access to socket: %w", err)
+	}
+
+	err = ioutil.WriteFile(TokenFile(s.socketPath), s.token, 0600)
+	if err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("could not write token file: %w", err)
+	}
+
+	return listener, nil
+}
+
+// handleCommand runs the admin command of the request, and responds with its output.
+func (s *Server) handleCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeResponse(w, http.StatusMethodNotAllowed, &CommandResponse{Error: "admin commands must be run with POST"})
+		return
+	}
+
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), s.token) != 1 {
+		s.log.Warn().Msg("rejected admin command with invalid token")
+		writeResponse(w, http.StatusUnauthorized, &CommandResponse{Error: "invalid token"})
+		return
+	}
+
+	var req CommandRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeResponse(w, http.StatusBadRequest, &CommandResponse{Error: fmt.Sprintf("malformed request: %v", err)})
+		return
+	}
+
+	log := s.log.With().Str("command", req.CommandName).Logger()
+	log.Info().Interface("data", req.Data).Msg("running admin command")
+
+	output, err := s.runner.RunCommand(r.Context(), req.CommandName, req.Data)
+	if errors.Is(err, ErrUnknownCommand) {
+		writeResponse(w, http.StatusNotFound, &CommandResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		log.Warn().Err(err).Msg("admin command failed")
+		writeResponse(w, http.StatusBadRequest, &CommandResponse{Error: err.Error()})
+		return
+	}
+
+	writeResponse(w, http.StatusOK, &CommandResponse{Output: output})
+}
+
+func writeResponse(w http.ResponseWriter, status int, response *CommandResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
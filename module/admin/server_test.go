@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestServer(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		socketPath := filepath.Join(dir, "admin.sock")
+
+		runner := NewCommandRunner()
+		err := runner.RegisterHandler("echo", func(_ context.Context, data map[string]interface{}) (interface{}, error) {
+			return data["message"], nil
+		})
+		require.NoError(t, err)
+		err = runner.RegisterHandler("fail", func(context.Context, map[string]interface{}) (interface{}, error) {
+			return nil, errors.New("failure")
+		})
+		require.NoError(t, err)
+
+		server := NewServer(unittest.Logger(), runner, socketPath)
+		unittest.RequireCloseBefore(t, server.Ready(), time.Second, "could not start admin server")
+
+		// only the user running the node can access the socket and the token
+		for _, path := range []string{socketPath, TokenFile(socketPath)} {
+			info, err := os.Stat(path)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		}
+
+		client, err := NewClient(socketPath)
+		require.NoError(t, err)
+
+		t.Run("run command", func(t *testing.T) {
+			output, err := client.RunCommand(context.Background(), "echo", map[string]interface{}{"message": "hello"})
+			require.NoError(t, err)
+			assert.JSONEq(t, `"hello"`, string(output))
+		})
+
+		t.Run("failing command", func(t *testing.T) {
+			_, err := client.RunCommand(context.Background(), "fail", nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "failure")
+		})
+
+		t.Run("unknown command", func(t *testing.T) {
+			_, err := client.RunCommand(context.Background(), "unknown", nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), ErrUnknownCommand.Error())
+		})
+
+		t.Run("invalid token", func(t *testing.T) {
+			client.token = "invalid"
+			_, err := client.RunCommand(context.Background(), "echo", nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid token")
+		})
+
+		t.Run("missing token", func(t *testing.T) {
+			httpClient := &http.Client{
+				Transport: &http.Transport{
+					DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+						return (&net.Dialer{}).DialContext(ctx, "unix", socketPath)
+					},
+				},
+			}
+			body, err := json.Marshal(&CommandRequest{CommandName: "echo"})
+			require.NoError(t, err)
+			resp, err := httpClient.Post("http://admin"+CommandPath, "application/json", bytes.NewReader(body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		})
+
+		unittest.RequireCloseBefore(t, server.Done(), time.Second, "could not stop admin server")
+
+		// the socket and the token are removed on shutdown
+		_, err = os.Stat(socketPath)
+		assert.True(t, os.IsNotExist(err))
+		_, err = os.Stat(TokenFile(socketPath))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
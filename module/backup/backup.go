@@ -0,0 +1,280 @@
+// Package backup creates consistent backups of the state of running nodes, and restores
+// nodes from them.
+//
+// A backup is a directory holding a snapshot of the node database, the ledger files of
+// execution nodes, and a manifest describing them. The database snapshot is taken within
+// a single read transaction, and the ledger files are copied after it, so that the ledger
+// contains the state commitments the snapshot refers to.
+package backup
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/dgraph-io/badger/v2/pb"
+
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// batchSize is the maximum number of entries per list of the database snapshot.
+const batchSize = 1000
+
+// Ledger copies the ledger files required to restore all updates recorded so far
+// into a directory. It is implemented by wal.Compactor.
+type Ledger interface {
+	Backup(dir string) (*wal.LedgerBackup, error)
+}
+
+// Create backs up the given database, and the given ledger if not nil, into the given
+// directory, which must not exist or be empty. It returns the manifest of the backup.
+func Create(db *badger.DB, ledger Ledger, dir string) (*Manifest, error) {
+	err := requireEmptyDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("could not create backup directory: %w", err)
+	}
+
+	manifest := &Manifest{
+		Version:   ManifestVersion,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	// the ledger is copied after the database snapshot, so that it contains at least all
+	// the updates leading to the state commitments referred to by the snapshot
+	err = snapshotDB(db, ledger != nil, filepath.Join(dir, ProtocolStateFilename), manifest)
+	if err != nil {
+		return nil, fmt.Errorf("could not snapshot database: %w", err)
+	}
+
+	if ledger != nil {
+		ledgerBackup, err := ledger.Backup(filepath.Join(dir, LedgerDir))
+		if err != nil {
+			return nil, fmt.Errorf("could not back up ledger: %w", err)
+		}
+		manifest.Execution.Ledger = ledgerBackup
+	}
+
+	manifest.Files, err = describeFiles(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not describe backup files: %w", err)
+	}
+
+	err = writeManifest(dir, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// snapshotDB writes all entries of the database into the given file, in the format of
+// badger backups, and fills the manifest with the state of the snapshot. Everything is
+// read within the same transaction, to get a consistent snapshot.
+func snapshotDB(db *badger.DB, execution bool, filename string, manifest *Manifest) error {
+	f, err := os.OpenFile(filename, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("could not create snapshot file: %w", err)
+	}
+	defer f.Close()
+
+	writer := bufio.NewWriter(f)
+
+	err = db.View(func(txn *badger.Txn) error {
+		err := readState(txn, execution, manifest)
+		if err != nil {
+			return err
+		}
+
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+		list := &pb.KVList{}
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return fmt.Errorf("could not read value of key %x: %w", item.Key(), err)
+			}
+			list.Kv = append(list.Kv, &pb.KV{
+				Key:       item.KeyCopy(nil),
+				Value:     value,
+				UserMeta:  []byte{item.UserMeta()},
+				Version:   item.Version(),
+				ExpiresAt: item.ExpiresAt(),
+			})
+
+			if len(list.Kv) >= batchSize {
+				err = writeList(writer, list)
+				if err != nil {
+					return err
+				}
+				list = &pb.KVList{}
+			}
+		}
+
+		return writeList(writer, list)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return fmt.Errorf("could not flush snapshot file: %w", err)
+	}
+
+	return f.Sync()
+}
+
+// writeList writes the given list of entries as badger.DB.Load reads them.
+func writeList(writer *bufio.Writer, list *pb.KVList) error {
+	if len(list.Kv) == 0 {
+		return nil
+	}
+
+	data, err := list.Marshal()
+	if err != nil {
+		return fmt.Errorf("could not encode entries: %w", err)
+	}
+
+	err = binary.Write(writer, binary.LittleEndian, uint64(len(data)))
+	if err != nil {
+		return fmt.Errorf("could not write entries: %w", err)
+	}
+
+	_, err = writer.Write(data)
+	if err != nil {
+		return fmt.Errorf("could not write entries: %w", err)
+	}
+
+	return nil
+}
+
+// readState fills the manifest with the protocol state, and the execution state if
+// requested, read in the given transaction.
+func readState(txn *badger.Txn, execution bool, manifest *Manifest) error {
+	err := operation.RetrieveRootHeight(&manifest.RootHeight)(txn)
+	if err != nil {
+		return fmt.Errorf("could not retrieve root height: %w", err)
+	}
+
+	var root flow.Header
+	var rootID flow.Identifier
+	err = operation.LookupBlockHeight(manifest.RootHeight, &rootID)(txn)
+	if err != nil {
+		return fmt.Errorf("could not look up root block: %w", err)
+	}
+	err = operation.RetrieveHeader(rootID, &root)(txn)
+	if err != nil {
+		return fmt.Errorf("could not retrieve root block: %w", err)
+	}
+	manifest.ChainID = root.ChainID
+
+	err = operation.RetrieveFinalizedHeight(&manifest.FinalizedHeight)(txn)
+	if err != nil {
+		return fmt.Errorf("could not retrieve finalized height: %w", err)
+	}
+	err = operation.LookupBlockHeight(manifest.FinalizedHeight, &manifest.FinalizedBlockID)(txn)
+	if err != nil {
+		return fmt.Errorf("could not look up finalized block: %w", err)
+	}
+
+	err = operation.RetrieveSealedHeight(&manifest.SealedHeight)(txn)
+	if err != nil {
+		return fmt.Errorf("could not retrieve sealed height: %w", err)
+	}
+	err = operation.LookupBlockHeight(manifest.SealedHeight, &manifest.SealedBlockID)(txn)
+	if err != nil {
+		return fmt.Errorf("could not look up sealed block: %w", err)
+	}
+
+	if !execution {
+		return nil
+	}
+
+	state := &ExecutionState{}
+	err = operation.RetrieveExecutedBlock(&state.LastExecutedBlockID)(txn)
+	if err != nil {
+		return fmt.Errorf("could not retrieve last executed block: %w", err)
+	}
+
+	var executed flow.Header
+	err = operation.RetrieveHeader(state.LastExecutedBlockID, &executed)(txn)
+	if err != nil {
+		return fmt.Errorf("could not retrieve last executed block: %w", err)
+	}
+	state.LastExecutedHeight = executed.Height
+
+	var commit flow.StateCommitment
+	err = operation.LookupStateCommitment(state.LastExecutedBlockID, &commit)(txn)
+	if err != nil {
+		return fmt.Errorf("could not look up state commitment of last executed block: %w", err)
+	}
+	state.StateCommitment = hex.EncodeToString(commit[:])
+
+	manifest.Execution = state
+
+	return nil
+}
+
+// describeFiles describes all files in the given directory and its subdirectories,
+// sorted by name.
+func describeFiles(dir string) ([]File, error) {
+	var files []File
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		file, err := describeFile(dir, name)
+		if err != nil {
+			return err
+		}
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+
+	return files, nil
+}
+
+// requireEmptyDir returns an error if the given directory exists and is not empty.
+func requireEmptyDir(dir string) error {
+	entries, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not read directory %s: %w", dir, err)
+	}
+	if len(entries) > 0 {
+		return fmt.Errorf("directory %s is not empty", dir)
+	}
+	return nil
+}
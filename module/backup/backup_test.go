@@ -0,0 +1,209 @@
+package backup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// bootstrapState stores a chain of blocks, with the root block at height 10, the last
+// block finalized, its parent sealed and executed with the given state commitment.
+func bootstrapState(t *testing.T, db *badger.DB, commit flow.StateCommitment) []*flow.Header {
+	root := unittest.BlockHeaderFixture()
+	root.Height = 10
+	headers := []*flow.Header{&root}
+	for i := 0; i < 3; i++ {
+		header := unittest.BlockHeaderWithParentFixture(headers[i])
+		headers = append(headers, &header)
+	}
+
+	err := db.Update(func(txn *badger.Txn) error {
+		for _, header := range headers {
+			err := operation.InsertHeader(header.ID(), header)(txn)
+			if err != nil {
+				return err
+			}
+			err = operation.IndexBlockHeight(header.Height, header.ID())(txn)
+			if err != nil {
+				return err
+			}
+		}
+		sealed := headers[len(headers)-2]
+		err := operation.InsertRootHeight(root.Height)(txn)
+		if err != nil {
+			return err
+		}
+		err = operation.InsertFinalizedHeight(headers[len(headers)-1].Height)(txn)
+		if err != nil {
+			return err
+		}
+		err = operation.InsertSealedHeight(sealed.Height)(txn)
+		if err != nil {
+			return err
+		}
+		err = operation.InsertExecutedBlock(sealed.ID())(txn)
+		if err != nil {
+			return err
+		}
+		return operation.IndexStateCommitment(sealed.ID(), commit)(txn)
+	})
+	require.NoError(t, err)
+
+	return headers
+}
+
+func TestBackupAndRestoreProtocolState(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		headers := bootstrapState(t, db, unittest.StateCommitmentFixture())
+
+		backupDir := filepath.Join(unittest.TempDir(t), "backup")
+		defer os.RemoveAll(filepath.Dir(backupDir))
+
+		manifest, err := Create(db, nil, backupDir)
+		require.NoError(t, err)
+
+		assert.Equal(t, headers[0].ChainID, manifest.ChainID)
+		assert.Equal(t, headers[0].Height, manifest.RootHeight)
+		assert.Equal(t, headers[3].ID(), manifest.FinalizedBlockID)
+		assert.Equal(t, headers[3].Height, manifest.FinalizedHeight)
+		assert.Equal(t, headers[2].ID(), manifest.SealedBlockID)
+		assert.Equal(t, headers[2].Height, manifest.SealedHeight)
+		assert.Nil(t, manifest.Execution)
+
+		// the backup can not be created twice in the same directory
+		_, err = Create(db, nil, backupDir)
+		require.Error(t, err)
+
+		t.Run("restore", func(t *testing.T) {
+			unittest.RunWithTempDir(t, func(datadir string) {
+				restored, err := Restore(backupDir, datadir, "")
+				require.NoError(t, err)
+				assert.Equal(t, manifest.FinalizedBlockID, restored.FinalizedBlockID)
+
+				db := unittest.BadgerDB(t, datadir)
+				defer db.Close()
+
+				var header flow.Header
+				err = db.View(operation.RetrieveHeader(headers[1].ID(), &header))
+				require.NoError(t, err)
+				assert.Equal(t, headers[1].ID(), header.ID())
+			})
+		})
+
+		t.Run("restore into non-empty directory", func(t *testing.T) {
+			unittest.RunWithTempDir(t, func(datadir string) {
+				err := ioutil.WriteFile(filepath.Join(datadir, "file"), []byte("data"), 0600)
+				require.NoError(t, err)
+				_, err = Restore(backupDir, datadir, "")
+				require.Error(t, err)
+			})
+		})
+
+		t.Run("restore ledger of protocol state backup", func(t *testing.T) {
+			unittest.RunWithTempDir(t, func(datadir string) {
+				_, err := Restore(backupDir, datadir, filepath.Join(datadir, "trie"))
+				require.Error(t, err)
+			})
+		})
+
+		t.Run("corrupted backup", func(t *testing.T) {
+			f, err := os.OpenFile(filepath.Join(backupDir, ProtocolStateFilename), os.O_APPEND|os.O_WRONLY, 0600)
+			require.NoError(t, err)
+			_, err = f.Write([]byte{0x01})
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+
+			_, err = Validate(backupDir)
+			require.Error(t, err)
+
+			unittest.RunWithTempDir(t, func(datadir string) {
+				_, err := Restore(backupDir, datadir, "")
+				require.Error(t, err)
+			})
+		})
+	})
+}
+
+func TestBackupAndRestoreExecutionState(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		unittest.RunWithTempDir(t, func(triedir string) {
+			collector := &metrics.NoopCollector{}
+
+			diskWAL, err := wal.NewDiskWAL(zerolog.Nop(), nil, collector, triedir, 100, pathfinder.PathByteSize, wal.SegmentSize)
+			require.NoError(t, err)
+			led, err := complete.NewLedger(diskWAL, 100, collector, zerolog.Nop(), complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+
+			key := ledger.NewKey([]ledger.KeyPart{ledger.NewKeyPart(0, []byte("owner")), ledger.NewKeyPart(2, []byte("key"))})
+			update, err := ledger.NewUpdate(led.InitialState(), []ledger.Key{key}, []ledger.Value{[]byte("value")})
+			require.NoError(t, err)
+			state, _, err := led.Set(update)
+			require.NoError(t, err)
+
+			headers := bootstrapState(t, db, flow.StateCommitment(state))
+
+			checkpointer, err := led.Checkpointer()
+			require.NoError(t, err)
+			compactor := wal.NewCompactor(checkpointer, time.Hour, 100, 0)
+
+			backupDir := filepath.Join(unittest.TempDir(t), "backup")
+			defer os.RemoveAll(filepath.Dir(backupDir))
+
+			manifest, err := Create(db, compactor, backupDir)
+			require.NoError(t, err)
+			<-diskWAL.Done()
+
+			require.NotNil(t, manifest.Execution)
+			assert.Equal(t, headers[2].ID(), manifest.Execution.LastExecutedBlockID)
+			assert.Equal(t, headers[2].Height, manifest.Execution.LastExecutedHeight)
+			require.NotNil(t, manifest.Execution.Ledger)
+
+			t.Run("restore", func(t *testing.T) {
+				unittest.RunWithTempDir(t, func(dir string) {
+					datadir := filepath.Join(dir, "data")
+					restoredTriedir := filepath.Join(dir, "trie")
+
+					// the ledger must be restored along with the database
+					_, err := Restore(backupDir, datadir, "")
+					require.Error(t, err)
+
+					restored, err := Restore(backupDir, datadir, restoredTriedir)
+					require.NoError(t, err)
+
+					err = VerifyLedger(zerolog.Nop(), restoredTriedir, restored, 100)
+					require.NoError(t, err)
+				})
+			})
+
+			t.Run("ledger without state commitment", func(t *testing.T) {
+				unittest.RunWithTempDir(t, func(dir string) {
+					datadir := filepath.Join(dir, "data")
+					restoredTriedir := filepath.Join(dir, "trie")
+
+					restored, err := Restore(backupDir, datadir, restoredTriedir)
+					require.NoError(t, err)
+
+					restored.Execution.StateCommitment = "6a7a565add94fb36069d79e8725c221cd1e5740742501ef014ea6db999fd98ad"
+					err = VerifyLedger(zerolog.Nop(), restoredTriedir, restored, 100)
+					require.Error(t, err)
+				})
+			})
+		})
+	})
+}
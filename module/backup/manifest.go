@@ -0,0 +1,188 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+const (
+	// ManifestVersion is the version of the manifest format.
+	ManifestVersion = 1
+
+	// ManifestFilename is the name of the manifest within a backup directory. The
+	// manifest is written last, so a backup without manifest is incomplete.
+	ManifestFilename = "manifest.json"
+
+	// ProtocolStateFilename is the name of the snapshot of the node database within a
+	// backup directory, in the format of badger backups.
+	ProtocolStateFilename = "protocol-state.badger"
+
+	// LedgerDir is the name of the directory holding the ledger files within a backup
+	// directory.
+	LedgerDir = "ledger"
+)
+
+// Manifest describes the content of a backup, so that it can be validated before
+// a node is started from it.
+type Manifest struct {
+	Version          uint            `json:"version"`
+	CreatedAt        time.Time       `json:"createdAt"`
+	ChainID          flow.ChainID    `json:"chainID"`
+	RootHeight       uint64          `json:"rootHeight"`
+	FinalizedHeight  uint64          `json:"finalizedHeight"`
+	FinalizedBlockID flow.Identifier `json:"finalizedBlockID"`
+	SealedHeight     uint64          `json:"sealedHeight"`
+	SealedBlockID    flow.Identifier `json:"sealedBlockID"`
+	// Execution is only set for backups including the execution state.
+	Execution *ExecutionState `json:"execution,omitempty"`
+	Files     []File          `json:"files"`
+}
+
+// ExecutionState describes the execution state included in a backup.
+type ExecutionState struct {
+	LastExecutedHeight  uint64          `json:"lastExecutedHeight"`
+	LastExecutedBlockID flow.Identifier `json:"lastExecutedBlockID"`
+	// StateCommitment is the hex encoded state commitment of the last executed block.
+	StateCommitment string            `json:"stateCommitment"`
+	Ledger          *wal.LedgerBackup `json:"ledger"`
+}
+
+// File is a file of a backup, with its path relative to the backup directory.
+type File struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ReadManifest reads the manifest of the backup in the given directory.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, ManifestFilename))
+	if err != nil {
+		return nil, fmt.Errorf("could not read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode manifest: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+// writeManifest writes the manifest in the given directory, atomically.
+func writeManifest(dir string, manifest *Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode manifest: %w", err)
+	}
+
+	tmp := filepath.Join(dir, ManifestFilename+".tmp")
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return fmt.Errorf("could not write manifest: %w", err)
+	}
+
+	err = os.Rename(tmp, filepath.Join(dir, ManifestFilename))
+	if err != nil {
+		return fmt.Errorf("could not rename manifest: %w", err)
+	}
+
+	return nil
+}
+
+// Validate reads the manifest of the backup in the given directory, and checks that
+// the backup is complete and its files are intact.
+func Validate(dir string) (*Manifest, error) {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.Version != ManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d (expected: %d)", manifest.Version, ManifestVersion)
+	}
+
+	files := make(map[string]struct{}, len(manifest.Files))
+	for _, expected := range manifest.Files {
+		actual, err := describeFile(dir, expected.Name)
+		if err != nil {
+			return nil, fmt.Errorf("could not read backup file: %w", err)
+		}
+		if actual != expected {
+			return nil, fmt.Errorf("backup file %s is corrupted (size: %d, sha256: %s, expected size: %d, expected sha256: %s)",
+				expected.Name, actual.Size, actual.SHA256, expected.Size, expected.SHA256)
+		}
+		files[expected.Name] = struct{}{}
+	}
+
+	if _, ok := files[ProtocolStateFilename]; !ok {
+		return nil, fmt.Errorf("backup has no protocol state")
+	}
+
+	if manifest.Execution != nil {
+		ledger := manifest.Execution.Ledger
+		if ledger == nil {
+			return nil, fmt.Errorf("backup has execution state but no ledger")
+		}
+		for _, name := range ledger.Files {
+			if _, ok := files[filepath.Join(LedgerDir, name)]; !ok {
+				return nil, fmt.Errorf("ledger file %s is not part of the backup", name)
+			}
+		}
+		err = ledger.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("invalid ledger backup: %w", err)
+		}
+		_, err = stateCommitment(manifest.Execution)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return manifest, nil
+}
+
+// describeFile computes the size and checksum of the given file of a backup.
+func describeFile(dir string, name string) (File, error) {
+	f, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return File{}, err
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	size, err := io.Copy(hasher, f)
+	if err != nil {
+		return File{}, fmt.Errorf("could not read %s: %w", name, err)
+	}
+
+	return File{
+		Name:   name,
+		Size:   size,
+		SHA256: hex.EncodeToString(hasher.Sum(nil)),
+	}, nil
+}
+
+// stateCommitment decodes the state commitment of the given execution state.
+func stateCommitment(state *ExecutionState) (flow.StateCommitment, error) {
+	data, err := hex.DecodeString(state.StateCommitment)
+	if err != nil {
+		return flow.DummyStateCommitment, fmt.Errorf("could not decode state commitment: %w", err)
+	}
+	commit, err := flow.ToStateCommitment(data)
+	if err != nil {
+		return flow.DummyStateCommitment, fmt.Errorf("invalid state commitment: %w", err)
+	}
+	return commit, nil
+}
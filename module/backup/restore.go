@@ -0,0 +1,203 @@
+package backup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/module/metrics"
+	utilsio "github.com/onflow/flow-go/utils/io"
+)
+
+// maxPendingWrites is the maximum number of pending writes while loading the database snapshot.
+const maxPendingWrites = 256
+
+// Restore validates the backup in the given directory, then restores the database into
+// datadir and, for backups including the execution state, the ledger into triedir.
+// Both directories must not exist or be empty. The restored database is checked
+// against the manifest before returning it.
+func Restore(backupDir string, datadir string, triedir string) (*Manifest, error) {
+	manifest, err := Validate(backupDir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup: %w", err)
+	}
+
+	if manifest.Execution != nil && triedir == "" {
+		return nil, fmt.Errorf("backup includes the execution state, but no directory to restore the ledger into is given")
+	}
+	if manifest.Execution == nil && triedir != "" {
+		return nil, fmt.Errorf("backup does not include the execution state, but a directory to restore the ledger into is given")
+	}
+
+	err = requireEmptyDir(datadir)
+	if err != nil {
+		return nil, err
+	}
+	if triedir != "" {
+		err = requireEmptyDir(triedir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = restoreDB(filepath.Join(backupDir, ProtocolStateFilename), datadir, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("could not restore database: %w", err)
+	}
+
+	if manifest.Execution != nil {
+		err = restoreLedger(filepath.Join(backupDir, LedgerDir), triedir, manifest.Execution.Ledger)
+		if err != nil {
+			return nil, fmt.Errorf("could not restore ledger: %w", err)
+		}
+	}
+
+	return manifest, nil
+}
+
+// restoreDB loads the snapshot into a new database in datadir, and checks that the
+// restored state matches the manifest.
+func restoreDB(snapshot string, datadir string, manifest *Manifest) error {
+	f, err := os.Open(snapshot)
+	if err != nil {
+		return fmt.Errorf("could not open snapshot: %w", err)
+	}
+	defer f.Close()
+
+	db, err := badger.Open(badger.DefaultOptions(datadir).WithLogger(nil))
+	if err != nil {
+		return fmt.Errorf("could not open database: %w", err)
+	}
+	defer db.Close()
+
+	err = db.Load(f, maxPendingWrites)
+	if err != nil {
+		return fmt.Errorf("could not load snapshot: %w", err)
+	}
+
+	restored := &Manifest{}
+	err = db.View(func(txn *badger.Txn) error {
+		return readState(txn, manifest.Execution != nil, restored)
+	})
+	if err != nil {
+		return fmt.Errorf("could not read restored state: %w", err)
+	}
+
+	err = checkState(manifest, restored)
+	if err != nil {
+		return fmt.Errorf("restored state does not match manifest: %w", err)
+	}
+
+	return nil
+}
+
+// checkState checks that the state read from the restored database matches the manifest.
+func checkState(manifest *Manifest, restored *Manifest) error {
+	if restored.ChainID != manifest.ChainID {
+		return fmt.Errorf("chain ID %s differs from %s", restored.ChainID, manifest.ChainID)
+	}
+	if restored.RootHeight != manifest.RootHeight {
+		return fmt.Errorf("root height %d differs from %d", restored.RootHeight, manifest.RootHeight)
+	}
+	if restored.FinalizedHeight != manifest.FinalizedHeight || restored.FinalizedBlockID != manifest.FinalizedBlockID {
+		return fmt.Errorf("finalized block %x at height %d differs from %x at height %d",
+			restored.FinalizedBlockID, restored.FinalizedHeight, manifest.FinalizedBlockID, manifest.FinalizedHeight)
+	}
+	if restored.SealedHeight != manifest.SealedHeight || restored.SealedBlockID != manifest.SealedBlockID {
+		return fmt.Errorf("sealed block %x at height %d differs from %x at height %d",
+			restored.SealedBlockID, restored.SealedHeight, manifest.SealedBlockID, manifest.SealedHeight)
+	}
+
+	if manifest.Execution == nil {
+		return nil
+	}
+
+	executed, expected := restored.Execution, manifest.Execution
+	if executed.LastExecutedBlockID != expected.LastExecutedBlockID || executed.LastExecutedHeight != expected.LastExecutedHeight {
+		return fmt.Errorf("last executed block %x at height %d differs from %x at height %d",
+			executed.LastExecutedBlockID, executed.LastExecutedHeight, expected.LastExecutedBlockID, expected.LastExecutedHeight)
+	}
+	if executed.StateCommitment != expected.StateCommitment {
+		return fmt.Errorf("state commitment %s differs from %s", executed.StateCommitment, expected.StateCommitment)
+	}
+
+	return nil
+}
+
+// restoreLedger copies the ledger files of the backup into triedir, and loads the
+// snapshot of the evicted tries, if any, into the trie store.
+func restoreLedger(from string, triedir string, backup *wal.LedgerBackup) error {
+	for _, name := range backup.Files {
+		if name == wal.TrieStoreBackupFilename {
+			err := wal.RestoreTrieStore(zerolog.Nop(), filepath.Join(from, name), filepath.Join(triedir, wal.DefaultTrieStoreDir))
+			if err != nil {
+				return fmt.Errorf("could not restore evicted tries: %w", err)
+			}
+			continue
+		}
+		target := filepath.Join(triedir, name)
+		err := os.MkdirAll(filepath.Dir(target), 0700)
+		if err != nil {
+			return fmt.Errorf("could not create directory for %s: %w", name, err)
+		}
+		err = utilsio.Copy(filepath.Join(from, name), target)
+		if err != nil {
+			return fmt.Errorf("could not copy %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// VerifyLedger replays the ledger restored into triedir, and checks that it contains
+// the state commitment of the last executed block of the manifest. Replaying the ledger
+// takes as long as starting an execution node.
+func VerifyLedger(log zerolog.Logger, triedir string, manifest *Manifest, capacity int) error {
+	if manifest.Execution == nil {
+		return fmt.Errorf("backup does not include the execution state")
+	}
+
+	commit, err := stateCommitment(manifest.Execution)
+	if err != nil {
+		return err
+	}
+
+	collector := &metrics.NoopCollector{}
+	diskWAL, err := wal.NewDiskWAL(log, nil, collector, triedir, capacity, pathfinder.PathByteSize, wal.SegmentSize)
+	if err != nil {
+		return fmt.Errorf("could not open WAL: %w", err)
+	}
+	defer func() {
+		<-diskWAL.Done()
+	}()
+
+	trieStoreDir := filepath.Join(triedir, wal.DefaultTrieStoreDir)
+	if utilsio.Exists(trieStoreDir) {
+		trieStore, err := wal.NewDiskTrieStore(log, trieStoreDir)
+		if err != nil {
+			return fmt.Errorf("could not open trie store: %w", err)
+		}
+		diskWAL.SetTrieStore(trieStore)
+	}
+
+	led, err := complete.NewLedger(diskWAL, capacity, collector, log, complete.DefaultPathFinderVersion)
+	if err != nil {
+		return fmt.Errorf("could not replay ledger: %w", err)
+	}
+
+	// pinning loads the trie of the state commitment, or fails if the ledger does not have it
+	state := ledger.State(commit)
+	err = led.Pin(state)
+	if err != nil {
+		return fmt.Errorf("ledger does not contain state commitment %s: %w", manifest.Execution.StateCommitment, err)
+	}
+	led.Unpin(state)
+
+	return nil
+}
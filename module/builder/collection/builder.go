@@ -194,6 +194,8 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 		// start with the finalized reference ID (longest expiry time)
 		minRefID := refChainFinalizedID
 
+		// the transactions are considered in the order given by the mempool, which
+		// is their order of priority for mempools which prioritize transactions
 		var transactions []*flow.TransactionBody
 		var totalByteSize uint64
 		var totalGas uint64
@@ -212,10 +214,10 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 				continue
 			}
 
-			// because the max byte size per tx is way smaller than the max collection byte size, we can stop here and not continue.
-			// to make it more effective in the future we can continue adding smaller ones
+			// skip transactions which do not fit into the remaining byte size, smaller
+			// transactions of lower priority may still fit
 			if totalByteSize+txByteSize > b.config.MaxCollectionByteSize {
-				break
+				continue
 			}
 
 			// ignore transactions with max gas bigger that the max total gas per collection
@@ -224,10 +226,10 @@ func (b *Builder) BuildOn(parentID flow.Identifier, setter func(*flow.Header) er
 				continue
 			}
 
-			// cause the max gas limit per tx is way smaller than the total max gas per collection, we can stop here and not continue.
-			// to make it more effective in the future we can continue adding smaller ones
+			// skip transactions which do not fit into the remaining gas, transactions
+			// of lower priority with a smaller gas limit may still fit
 			if totalGas+tx.GasLimit > b.config.MaxCollectionTotalGas {
-				break
+				continue
 			}
 
 			// retrieve the main chain header that was used as reference
@@ -14,6 +14,7 @@ import (
 	model "github.com/onflow/flow-go/model/cluster"
 	"github.com/onflow/flow-go/model/flow"
 	builder "github.com/onflow/flow-go/module/builder/collection"
+	"github.com/onflow/flow-go/module/mempool"
 	"github.com/onflow/flow-go/module/mempool/stdmap"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/module/trace"
@@ -49,7 +50,7 @@ type BuilderSuite struct {
 	// protocol state for reference blocks for transactions
 	protoState protocol.MutableState
 
-	pool    *stdmap.Transactions
+	pool    mempool.Transactions
 	builder *builder.Builder
 }
 
@@ -583,6 +584,41 @@ func (suite *BuilderSuite) TestBuildOn_EmptyMempool() {
 	suite.Assert().Equal(0, built.Payload.Collection.Len())
 }
 
+// With a priority mempool, the transactions with the highest gas limit should be
+// included first, and the remaining gas filled with transactions of lower priority.
+func (suite *BuilderSuite) TestBuildOn_PriorityTransactions() {
+
+	// the mempool contains two transactions with a gas limit of 9,999 from the setup
+	pool := stdmap.NewPriorityTransactions(1000)
+	for _, tx := range suite.pool.All() {
+		pool.Add(tx)
+	}
+	suite.pool = pool
+
+	mainGenesis, err := suite.protoState.AtHeight(0).Head()
+	suite.Require().Nil(err)
+	small := unittest.TransactionBodyFixture(func(tx *flow.TransactionBody) {
+		tx.ReferenceBlockID = mainGenesis.ID()
+		tx.GasLimit = 100
+	})
+	suite.pool.Add(&small)
+
+	// set the max gas to 20,100, so that two large transactions and the small one fit
+	suite.builder = builder.NewBuilder(suite.db, trace.NewNoopTracer(), suite.headers, suite.headers, suite.payloads, suite.pool, builder.WithMaxCollectionTotalGas(20100))
+
+	header, err := suite.builder.BuildOn(suite.genesis.ID(), noopSetter)
+	suite.Require().Nil(err)
+
+	var built model.Block
+	err = suite.db.View(procedure.RetrieveClusterBlock(header.ID(), &built))
+	suite.Require().Nil(err)
+	builtCollection := built.Payload.Collection
+
+	// the third large transaction does not fit, but the small one does
+	suite.Require().Equal(3, builtCollection.Len())
+	suite.Assert().Equal(small.ID(), builtCollection.Transactions[2].ID())
+}
+
 // With rate limiting turned off, we should fill collections as fast as we can
 // regardless of how many transactions with the same payer we include.
 func (suite *BuilderSuite) TestBuildOn_NoRateLimiting() {
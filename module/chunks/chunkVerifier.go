@@ -119,7 +119,8 @@ func (fcv *ChunkVerifier) verifyTransactionsInContext(context fvm.Context, chunk
 	}
 
 	// transactions in chunk can reuse the same cache, but its unknown
-	// if there were changes between chunks, so we always start with a new one
+	// if there were changes between chunks, so we always start with a new one.
+	// This also keeps the caches of chunks verified concurrently isolated.
 	programs := programs.NewEmptyPrograms()
 
 	// chunk view construction
@@ -5,8 +5,8 @@ import (
 )
 
 // HotStuff defines the interface to the core HotStuff algorithm. It includes
-// a method to start the event loop, and utilities to submit block proposals
-// and votes received from other replicas.
+// a method to start the event loop, and utilities to submit block proposals,
+// votes and timeouts received from other replicas.
 type HotStuff interface {
 	ReadyDoneAware
 
@@ -22,6 +22,12 @@ type HotStuff interface {
 	//
 	// Votes may be submitted in any order.
 	SubmitVote(originID flow.Identifier, blockID flow.Identifier, view uint64, sigData []byte)
+
+	// SubmitTimeout submits a new timeout to the HotStuff event loop.
+	// This method blocks until the timeout is accepted to the event queue.
+	//
+	// Timeouts may be submitted in any order.
+	SubmitTimeout(originID flow.Identifier, view uint64, highestQC *flow.QuorumCertificate, highestTC *flow.TimeoutCertificate, sigData []byte)
 }
 
 // HotStuffFollower is run by non-consensus nodes to observe the block chain
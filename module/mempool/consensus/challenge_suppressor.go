@@ -0,0 +1,173 @@
+package consensus
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool"
+	"github.com/onflow/flow-go/storage"
+)
+
+// ChallengeSuppressor is a wrapper around a conventional mempool.IncorporatedResultSeals
+// mempool. It withholds the candidate seals for execution results which are
+// challenged by a verification node:
+//   * While a result has at least one unresolved chunk challenge, the seals for
+//     the result are hidden from ByID and All. Hence, the builder doesn't
+//     include them into new blocks, which blocks sealing of the challenged result.
+//     The challenges are local to the node, so seals for challenged results
+//     included by other leaders are not rejected; the validity of a block must
+//     not depend on state that other consensus nodes might not share.
+//   * Seals for challenged results are still added to the wrapped mempool, so
+//     that sealing resumes once all challenges for the result are resolved.
+//   * The unresolved challenges are loaded from the database on construction,
+//     so that sealing stays blocked after a restart.
+//   * Challenges are resolved by operators, or expire after a while, both through
+//     the challenges engine.
+// Implementation is concurrency safe.
+type ChallengeSuppressor struct {
+	mutex      sync.RWMutex
+	seals      mempool.IncorporatedResultSeals
+	challenged map[flow.Identifier]map[flow.Identifier]struct{} // map ResultID -> set of unresolved challenge IDs
+	log        zerolog.Logger
+}
+
+func NewChallengeSuppressor(seals mempool.IncorporatedResultSeals, challenges storage.ChunkChallenges, log zerolog.Logger) (*ChallengeSuppressor, error) {
+	unresolved, err := challenges.Unresolved()
+	if err != nil {
+		return nil, fmt.Errorf("could not load unresolved chunk challenges: %w", err)
+	}
+
+	wrapper := &ChallengeSuppressor{
+		mutex:      sync.RWMutex{},
+		seals:      seals,
+		challenged: make(map[flow.Identifier]map[flow.Identifier]struct{}),
+		log:        log.With().Str("mempool", "ChallengeSuppressor").Logger(),
+	}
+	for _, challenge := range unresolved {
+		wrapper.challenge(challenge.ExecutionResultID(), challenge.ID())
+	}
+
+	return wrapper, nil
+}
+
+// Challenge withholds the seals for the given execution result until the given
+// challenge is resolved.
+func (s *ChallengeSuppressor) Challenge(resultID flow.Identifier, challengeID flow.Identifier) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.challenge(resultID, challengeID)
+}
+
+func (s *ChallengeSuppressor) challenge(resultID flow.Identifier, challengeID flow.Identifier) {
+	challenges, found := s.challenged[resultID]
+	if !found {
+		challenges = make(map[flow.Identifier]struct{})
+		s.challenged[resultID] = challenges
+	}
+	challenges[challengeID] = struct{}{}
+
+	s.log.Warn().
+		Hex("result_id", resultID[:]).
+		Hex("challenge_id", challengeID[:]).
+		Msg("sealing of challenged execution result suspended")
+}
+
+// Resolve marks the given challenge of the execution result as resolved. The
+// seals for the result are released once all its challenges are resolved.
+func (s *ChallengeSuppressor) Resolve(resultID flow.Identifier, challengeID flow.Identifier) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	challenges, found := s.challenged[resultID]
+	if !found {
+		return
+	}
+	delete(challenges, challengeID)
+	if len(challenges) > 0 {
+		return
+	}
+	delete(s.challenged, resultID)
+
+	s.log.Info().
+		Hex("result_id", resultID[:]).
+		Msg("sealing of execution result resumed")
+}
+
+// Challenged returns whether the given execution result has unresolved challenges.
+func (s *ChallengeSuppressor) Challenged(resultID flow.Identifier) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	_, found := s.challenged[resultID]
+	return found
+}
+
+// Add adds the given seal to the mempool, including seals for challenged results.
+func (s *ChallengeSuppressor) Add(irSeal *flow.IncorporatedResultSeal) (bool, error) {
+	return s.seals.Add(irSeal)
+}
+
+// All returns all the IncorporatedResultSeals in the mempool, except for the
+// seals of challenged results.
+func (s *ChallengeSuppressor) All() []*flow.IncorporatedResultSeal {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	all := s.seals.All()
+	if len(s.challenged) == 0 {
+		return all
+	}
+	seals := make([]*flow.IncorporatedResultSeal, 0, len(all))
+	for _, irSeal := range all {
+		if _, found := s.challenged[irSeal.IncorporatedResult.Result.ID()]; found {
+			continue
+		}
+		seals = append(seals, irSeal)
+	}
+	return seals
+}
+
+// ByID returns an IncorporatedResultSeal by its ID, unless it seals a
+// challenged result.
+func (s *ChallengeSuppressor) ByID(identifier flow.Identifier) (*flow.IncorporatedResultSeal, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	irSeal, found := s.seals.ByID(identifier)
+	if !found {
+		return nil, false
+	}
+	if _, challenged := s.challenged[irSeal.IncorporatedResult.Result.ID()]; challenged {
+		return nil, false
+	}
+	return irSeal, true
+}
+
+// Rem removes the IncorporatedResultSeal with id from the mempool
+func (s *ChallengeSuppressor) Rem(id flow.Identifier) bool {
+	return s.seals.Rem(id)
+}
+
+// Size returns the number of items in the mempool, including the seals of
+// challenged results.
+func (s *ChallengeSuppressor) Size() uint {
+	return s.seals.Size()
+}
+
+// Limit returns the size limit of the mempool
+func (s *ChallengeSuppressor) Limit() uint {
+	return s.seals.Limit()
+}
+
+// Clear removes all entities from the pool. The challenges are retained.
+func (s *ChallengeSuppressor) Clear() {
+	s.seals.Clear()
+}
+
+// PruneUpToHeight remove all seals for blocks whose height is strictly
+// smaller that height. Note: seals for blocks at height are retained.
+func (s *ChallengeSuppressor) PruneUpToHeight(height uint64) error {
+	return s.seals.PruneUpToHeight(height)
+}
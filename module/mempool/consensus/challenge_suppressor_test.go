@@ -0,0 +1,88 @@
+package consensus
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool"
+	poolmock "github.com/onflow/flow-go/module/mempool/mock"
+	storage "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestChallengeSuppressor_ImplementsInterfaces is a compile-time check:
+// verifies that ChallengeSuppressor implements mempool.IncorporatedResultSeals interface
+func TestChallengeSuppressor_ImplementsInterfaces(t *testing.T) {
+	var _ mempool.IncorporatedResultSeals = &ChallengeSuppressor{}
+}
+
+// TestChallengeSuppressor_Challenge checks that the seals of a challenged result
+// are withheld until all challenges of the result are resolved, while the seals
+// of other results are unaffected.
+func TestChallengeSuppressor_Challenge(t *testing.T) {
+	WithChallengeSuppressor(t, nil, func(wrapper *ChallengeSuppressor, wrappedMempool *poolmock.IncorporatedResultSeals) {
+		seals := unittest.IncorporatedResultSeal.Fixtures(2)
+		challenged := seals[0]
+		resultID := challenged.IncorporatedResult.Result.ID()
+		wrappedMempool.On("All").Return(seals)
+		wrappedMempool.On("ByID", challenged.ID()).Return(challenged, true)
+		wrappedMempool.On("ByID", seals[1].ID()).Return(seals[1], true)
+
+		challengeIDs := unittest.IdentifierListFixture(2)
+		wrapper.Challenge(resultID, challengeIDs[0])
+		wrapper.Challenge(resultID, challengeIDs[1])
+		require.True(t, wrapper.Challenged(resultID))
+
+		_, found := wrapper.ByID(challenged.ID())
+		require.False(t, found)
+		require.Equal(t, []*flow.IncorporatedResultSeal{seals[1]}, wrapper.All())
+		_, found = wrapper.ByID(seals[1].ID())
+		require.True(t, found)
+
+		// sealing stays blocked while any challenge is unresolved
+		wrapper.Resolve(resultID, challengeIDs[0])
+		_, found = wrapper.ByID(challenged.ID())
+		require.False(t, found)
+
+		wrapper.Resolve(resultID, challengeIDs[1])
+		require.False(t, wrapper.Challenged(resultID))
+		irSeal, found := wrapper.ByID(challenged.ID())
+		require.True(t, found)
+		require.Equal(t, challenged, irSeal)
+		require.Equal(t, seals, wrapper.All())
+	})
+}
+
+// TestChallengeSuppressor_UnresolvedLoaded checks that the unresolved
+// challenges stored in the database are suppressing sealing after a restart.
+func TestChallengeSuppressor_UnresolvedLoaded(t *testing.T) {
+	challenge := unittest.ChunkChallengeFixture()
+	WithChallengeSuppressor(t, []*flow.ChunkChallenge{challenge}, func(wrapper *ChallengeSuppressor, wrappedMempool *poolmock.IncorporatedResultSeals) {
+		irSeal := unittest.IncorporatedResultSeal.Fixture()
+		irSeal.IncorporatedResult.Result = &challenge.Body.ExecutionResult
+		wrappedMempool.On("ByID", irSeal.ID()).Return(irSeal, true)
+
+		require.True(t, wrapper.Challenged(challenge.ExecutionResultID()))
+		_, found := wrapper.ByID(irSeal.ID())
+		require.False(t, found)
+
+		wrapper.Resolve(challenge.ExecutionResultID(), challenge.ID())
+		_, found = wrapper.ByID(irSeal.ID())
+		require.True(t, found)
+	})
+}
+
+func WithChallengeSuppressor(t testing.TB, unresolved []*flow.ChunkChallenge, testLogic func(wrapper *ChallengeSuppressor, wrappedMempool *poolmock.IncorporatedResultSeals)) {
+	challenges := &storage.ChunkChallenges{}
+	challenges.On("Unresolved").Return(unresolved, nil).Once()
+	wrappedMempool := &poolmock.IncorporatedResultSeals{}
+
+	wrapper, err := NewChallengeSuppressor(wrappedMempool, challenges, zerolog.Nop())
+	require.NoError(t, err)
+	challenges.AssertExpectations(t)
+
+	testLogic(wrapper, wrappedMempool)
+}
@@ -0,0 +1,113 @@
+package epochs
+
+import (
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+// JournaledTransactions is a transaction pool for a single epoch, which
+// mirrors its contents into a persistent transaction journal. This allows a
+// collection node to restore the transactions pending inclusion in a
+// collection after a restart.
+//
+// The in-memory pool is authoritative: failing to update the journal is
+// logged, but never fails the operation on the pool. Transactions are removed
+// from the journal together with the pool, that is once they are included in
+// a finalized cluster block, when the pool ejects them on exceeding its limit,
+// or when the pool is cleared at the end of the epoch. Transactions are only
+// journaled once the pool has accepted them, so a transaction the pool rejects
+// or ejects right away is never journaled.
+type JournaledTransactions struct {
+	mempool.Transactions
+	// mu serializes the changes of the pool and the journal, so that a
+	// transaction removed while it is added is not journaled afterwards.
+	// The pool ejects transactions only while they are added, with mu held.
+	mu      sync.Mutex
+	log     zerolog.Logger
+	epoch   uint64
+	journal storage.TransactionJournal
+}
+
+// ejectingPool is a transaction pool which ejects transactions when it exceeds
+// its limit, such as the pools of package stdmap.
+type ejectingPool interface {
+	RegisterEjectionCallbacks(callbacks ...mempool.OnEjection)
+}
+
+// NewJournaledTransactions wraps the transaction pool of the given epoch, such
+// that its contents are journaled.
+func NewJournaledTransactions(log zerolog.Logger, epoch uint64, pool mempool.Transactions, journal storage.TransactionJournal) *JournaledTransactions {
+	j := &JournaledTransactions{
+		Transactions: pool,
+		log:          log.With().Str("component", "transaction_journal").Uint64("epoch", epoch).Logger(),
+		epoch:        epoch,
+		journal:      journal,
+	}
+	if ejecting, ok := pool.(ejectingPool); ok {
+		ejecting.RegisterEjectionCallbacks(j.onEjection)
+	}
+	return j
+}
+
+// onEjection removes a transaction ejected by the pool from the journal.
+func (j *JournaledTransactions) onEjection(entity flow.Entity) {
+	txID := entity.ID()
+	err := j.journal.Remove(j.epoch, txID)
+	if err != nil {
+		j.log.Error().Err(err).Hex("tx_id", logging.ID(txID)).Msg("could not remove ejected transaction from journal")
+	}
+}
+
+// Add adds the transaction to the pool and journals it. It returns false if the
+// pool did not accept the transaction, or ejected it right away.
+func (j *JournaledTransactions) Add(tx *flow.TransactionBody) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	txID := tx.ID()
+	added := j.Transactions.Add(tx)
+	// pools which eject transactions by other criteria than their priority might
+	// eject the added transaction itself
+	if !added || !j.Transactions.Has(txID) {
+		return false
+	}
+	err := j.journal.Add(j.epoch, tx)
+	if err != nil {
+		j.log.Error().Err(err).Hex("tx_id", logging.ID(txID)).Msg("could not journal transaction")
+	}
+	return true
+}
+
+// Rem removes the transaction from the pool and the journal.
+func (j *JournaledTransactions) Rem(txID flow.Identifier) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	removed := j.Transactions.Rem(txID)
+	if !removed {
+		return false
+	}
+	err := j.journal.Remove(j.epoch, txID)
+	if err != nil {
+		j.log.Error().Err(err).Hex("tx_id", logging.ID(txID)).Msg("could not remove journaled transaction")
+	}
+	return true
+}
+
+// Clear removes all transactions from the pool and the journal.
+func (j *JournaledTransactions) Clear() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.Transactions.Clear()
+	err := j.journal.Clear(j.epoch)
+	if err != nil {
+		j.log.Error().Err(err).Msg("could not clear transaction journal")
+	}
+}
@@ -0,0 +1,105 @@
+package epochs_test
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool"
+	"github.com/onflow/flow-go/module/mempool/epochs"
+	"github.com/onflow/flow-go/module/mempool/stdmap"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// the journal should mirror the contents of the transaction pool of each epoch
+func TestJournaledTransactions(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		journal := bstorage.NewTransactionJournal(db)
+		create := func(epoch uint64) mempool.Transactions {
+			return epochs.NewJournaledTransactions(zerolog.Nop(), epoch, stdmap.NewTransactions(100), journal)
+		}
+		pools := epochs.NewTransactionPools(create)
+
+		tx1 := unittest.TransactionBodyFixture()
+		tx2 := unittest.TransactionBodyFixture()
+		tx3 := unittest.TransactionBodyFixture()
+		assert.True(t, pools.ForEpoch(1).Add(&tx1))
+		assert.True(t, pools.ForEpoch(1).Add(&tx2))
+		assert.True(t, pools.ForEpoch(2).Add(&tx3))
+		assert.False(t, pools.ForEpoch(1).Add(&tx1))
+
+		journaled, err := journal.All()
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []*flow.TransactionBody{&tx1, &tx2}, journaled[1])
+		assert.Equal(t, []*flow.TransactionBody{&tx3}, journaled[2])
+
+		// removing a transaction, e.g. once it is finalized, removes it from the journal
+		assert.True(t, pools.ForEpoch(1).Rem(tx1.ID()))
+		assert.False(t, pools.ForEpoch(1).Rem(tx1.ID()))
+
+		journaled, err = journal.All()
+		require.NoError(t, err)
+		assert.Equal(t, []*flow.TransactionBody{&tx2}, journaled[1])
+
+		// clearing the pool at the end of the epoch clears the journal of the epoch
+		pools.ForEpoch(1).Clear()
+
+		journaled, err = journal.All()
+		require.NoError(t, err)
+		assert.Equal(t, map[uint64][]*flow.TransactionBody{2: {&tx3}}, journaled)
+	})
+}
+
+// transactions ejected by the pool on exceeding its limit are removed from the journal
+func TestJournaledTransactions_Ejection(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		journal := bstorage.NewTransactionJournal(db)
+		limit := uint(10)
+		pool := epochs.NewJournaledTransactions(zerolog.Nop(), 1, stdmap.NewPriorityTransactions(limit), journal)
+
+		// the pool ejects once it exceeds its limit by a batch of transactions
+		for i := 0; i < 200; i++ {
+			tx := unittest.TransactionBodyFixture()
+			tx.GasLimit = uint64(i)
+			pool.Add(&tx)
+		}
+		require.Less(t, pool.Size(), uint(200))
+
+		journaled, err := journal.All()
+		require.NoError(t, err)
+		assert.ElementsMatch(t, pool.All(), journaled[1])
+	})
+}
+
+// a transaction ejected right away, as the pool is full of transactions with a
+// higher priority, is neither reported as added nor journaled
+func TestJournaledTransactions_EjectAdded(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		journal := bstorage.NewTransactionJournal(db)
+		limit := uint(10)
+		pool := epochs.NewJournaledTransactions(zerolog.Nop(), 1, stdmap.NewPriorityTransactions(limit, stdmap.WithAgingPeriod(0)), journal)
+
+		// fill the pool up to the point where the next transaction makes it eject
+		for i := 0; i < 138; i++ {
+			tx := unittest.TransactionBodyFixture()
+			tx.GasLimit = 100
+			require.True(t, pool.Add(&tx))
+		}
+
+		low := unittest.TransactionBodyFixture()
+		low.GasLimit = 1
+		assert.False(t, pool.Add(&low))
+		assert.False(t, pool.Has(low.ID()))
+		assert.EqualValues(t, limit, pool.Size())
+
+		journaled, err := journal.All()
+		require.NoError(t, err)
+		assert.NotContains(t, journaled[1], &low)
+		assert.ElementsMatch(t, pool.All(), journaled[1])
+	})
+}
@@ -16,11 +16,12 @@ import (
 type TransactionPools struct {
 	mu     sync.RWMutex
 	pools  map[uint64]mempool.Transactions
-	create func() mempool.Transactions
+	create func(epoch uint64) mempool.Transactions
 }
 
 // NewTransactionPools returns a new set of epoch-scoped transaction pools.
-func NewTransactionPools(create func() mempool.Transactions) *TransactionPools {
+// The create function is called with the epoch counter of the pool to create.
+func NewTransactionPools(create func(epoch uint64) mempool.Transactions) *TransactionPools {
 
 	pools := &TransactionPools{
 		pools:  make(map[uint64]mempool.Transactions),
@@ -43,7 +44,7 @@ func (t *TransactionPools) ForEpoch(epoch uint64) mempool.Transactions {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	pool = t.create()
+	pool = t.create(epoch)
 	t.pools[epoch] = pool
 	return pool
 }
@@ -18,7 +18,7 @@ import (
 // subsequent calls to Get should return the same transaction pool
 func TestConsistency(t *testing.T) {
 
-	create := func() mempool.Transactions { return stdmap.NewTransactions(100) }
+	create := func(_ uint64) mempool.Transactions { return stdmap.NewTransactions(100) }
 	pools := epochs.NewTransactionPools(create)
 	epoch := rand.Uint64()
 
@@ -29,7 +29,7 @@ func TestConsistency(t *testing.T) {
 // test that different epochs don't interfere, also test concurrent access
 func TestMultipleEpochs(t *testing.T) {
 
-	create := func() mempool.Transactions { return stdmap.NewTransactions(100) }
+	create := func(_ uint64) mempool.Transactions { return stdmap.NewTransactions(100) }
 	pools := epochs.NewTransactionPools(create)
 
 	var wg sync.WaitGroup
@@ -58,7 +58,7 @@ func TestMultipleEpochs(t *testing.T) {
 
 func TestCombinedSize(t *testing.T) {
 
-	create := func() mempool.Transactions { return stdmap.NewTransactions(100) }
+	create := func(_ uint64) mempool.Transactions { return stdmap.NewTransactions(100) }
 	pools := epochs.NewTransactionPools(create)
 
 	nEpochs := rand.Uint64() % 10
@@ -0,0 +1,252 @@
+package stdmap
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool"
+)
+
+// DefaultTransactionAgingPeriod is the default period over which the priority of
+// a transaction in the mempool grows by the priority of a transaction with the
+// maximum gas limit.
+const DefaultTransactionAgingPeriod = time.Minute
+
+// PriorityTransactions implements the transactions memory pool of the collection
+// nodes, ordering the transactions by priority.
+//
+// The priority of a transaction is given by its declared gas limit, which bounds the
+// fees paid for it, and grows linearly with the time the transaction has spent in the
+// mempool. Over each aging period, a transaction gains the priority of a transaction
+// with the maximum gas limit, so transactions with a low gas limit are not starved. When the
+// mempool exceeds its limit, the transactions with the lowest priority are ejected,
+// and the registered ejection callbacks are called for each of them.
+//
+// All returns the transactions in the order in which they should be included in
+// collections. Payers take turns: the best transaction of each payer comes before the
+// second best transaction of any payer, and so on, so that no single payer can crowd
+// out the others by sending many high-priority transactions.
+type PriorityTransactions struct {
+	sync.RWMutex
+	limit       uint
+	agingPeriod time.Duration
+	txs         map[flow.Identifier]*prioritizedTransaction
+	now         func() time.Time
+	onEjection  []mempool.OnEjection
+}
+
+type prioritizedTransaction struct {
+	tx    *flow.TransactionBody
+	added time.Time
+}
+
+// PriorityTransactionsOption is an option of the priority transactions mempool.
+type PriorityTransactionsOption func(*PriorityTransactions)
+
+// WithAgingPeriod sets the period over which the priority of a transaction grows by
+// the priority of a transaction with the maximum gas limit. A non-positive period
+// disables aging.
+func WithAgingPeriod(period time.Duration) PriorityTransactionsOption {
+	return func(p *PriorityTransactions) {
+		p.agingPeriod = period
+	}
+}
+
+// NewPriorityTransactions creates a new priority-ordered memory pool for transactions.
+func NewPriorityTransactions(limit uint, opts ...PriorityTransactionsOption) *PriorityTransactions {
+	p := &PriorityTransactions{
+		limit:       limit,
+		agingPeriod: DefaultTransactionAgingPeriod,
+		txs:         make(map[flow.Identifier]*prioritizedTransaction),
+		now:         time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Has checks whether the transaction with the given ID is in the mempool.
+func (p *PriorityTransactions) Has(txID flow.Identifier) bool {
+	p.RLock()
+	defer p.RUnlock()
+	_, ok := p.txs[txID]
+	return ok
+}
+
+// Add adds a transaction to the mempool. If the mempool exceeds its limit, the
+// transactions with the lowest priority are ejected. It returns false if the
+// transaction was already in the mempool, or if it was ejected right away as its
+// priority is lower than the priorities of all other transactions. The ejection
+// callbacks are not called for a transaction which is not added.
+func (p *PriorityTransactions) Add(tx *flow.TransactionBody) bool {
+	p.Lock()
+	defer p.Unlock()
+
+	txID := tx.ID()
+	if _, ok := p.txs[txID]; ok {
+		return false
+	}
+	p.txs[txID] = &prioritizedTransaction{tx: tx, added: p.now()}
+
+	p.reduce(txID)
+
+	_, added := p.txs[txID]
+	return added
+}
+
+// Rem removes the transaction with the given ID from the mempool. It returns true if
+// the transaction was known and removed.
+func (p *PriorityTransactions) Rem(txID flow.Identifier) bool {
+	p.Lock()
+	defer p.Unlock()
+	_, ok := p.txs[txID]
+	delete(p.txs, txID)
+	return ok
+}
+
+// ByID returns the transaction with the given ID from the mempool.
+func (p *PriorityTransactions) ByID(txID flow.Identifier) (*flow.TransactionBody, bool) {
+	p.RLock()
+	defer p.RUnlock()
+	ptx, ok := p.txs[txID]
+	if !ok {
+		return nil, false
+	}
+	return ptx.tx, true
+}
+
+// Size returns the number of transactions in the mempool.
+func (p *PriorityTransactions) Size() uint {
+	p.RLock()
+	defer p.RUnlock()
+	return uint(len(p.txs))
+}
+
+// All returns all transactions from the mempool, in the order in which they should
+// be included in collections.
+func (p *PriorityTransactions) All() []*flow.TransactionBody {
+	p.RLock()
+	defer p.RUnlock()
+
+	now := p.now()
+	ranked := make([]rankedTransaction, 0, len(p.txs))
+	for txID, ptx := range p.txs {
+		ranked = append(ranked, rankedTransaction{
+			txID:     txID,
+			tx:       ptx.tx,
+			priority: p.priority(ptx, now),
+		})
+	}
+	sortByPriority(ranked)
+
+	// rank the transactions of each payer, starting with its best transaction
+	turns := make(map[flow.Address]int)
+	for i := range ranked {
+		payer := ranked[i].tx.Payer
+		ranked[i].turn = turns[payer]
+		turns[payer]++
+	}
+
+	// the sort is stable, so the transactions of each turn remain ordered by priority
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].turn < ranked[j].turn
+	})
+
+	txs := make([]*flow.TransactionBody, 0, len(ranked))
+	for _, r := range ranked {
+		txs = append(txs, r.tx)
+	}
+	return txs
+}
+
+// Clear removes all transactions from the mempool.
+func (p *PriorityTransactions) Clear() {
+	p.Lock()
+	defer p.Unlock()
+	p.txs = make(map[flow.Identifier]*prioritizedTransaction)
+}
+
+// RegisterEjectionCallbacks adds the given callbacks, which are called with each
+// transaction ejected when the mempool exceeds its limit. The callbacks are called
+// while the mempool is locked, so they must not access the mempool.
+func (p *PriorityTransactions) RegisterEjectionCallbacks(callbacks ...mempool.OnEjection) {
+	p.Lock()
+	defer p.Unlock()
+	p.onEjection = append(p.onEjection, callbacks...)
+}
+
+// Hash returns a fingerprint of the contents of the mempool.
+func (p *PriorityTransactions) Hash() flow.Identifier {
+	p.RLock()
+	defer p.RUnlock()
+	txIDs := make([]flow.Identifier, 0, len(p.txs))
+	for txID := range p.txs {
+		txIDs = append(txIDs, txID)
+	}
+	return flow.MerkleRoot(txIDs...)
+}
+
+// reduce ejects the transactions with the lowest priority once the mempool exceeds
+// its limit by more than the over capacity threshold, so that the cost of sorting
+// the transactions is amortized over many additions. The ejection callbacks are
+// called for all ejected transactions, except the given one which is being added.
+func (p *PriorityTransactions) reduce(adding flow.Identifier) {
+	if p.limit == 0 || uint(len(p.txs)) <= p.limit+overCapacityThreshold {
+		return
+	}
+
+	now := p.now()
+	ranked := make([]rankedTransaction, 0, len(p.txs))
+	for txID, ptx := range p.txs {
+		ranked = append(ranked, rankedTransaction{
+			txID:     txID,
+			tx:       ptx.tx,
+			priority: p.priority(ptx, now),
+		})
+	}
+	sortByPriority(ranked)
+
+	for _, r := range ranked[p.limit:] {
+		delete(p.txs, r.txID)
+		if r.txID == adding {
+			continue
+		}
+		for _, callback := range p.onEjection {
+			callback(r.tx)
+		}
+	}
+}
+
+// priority returns the priority of the transaction at the given time.
+func (p *PriorityTransactions) priority(ptx *prioritizedTransaction, now time.Time) float64 {
+	priority := float64(ptx.tx.GasLimit)
+	if p.agingPeriod <= 0 {
+		return priority
+	}
+	age := now.Sub(ptx.added)
+	return priority + float64(flow.DefaultMaxTransactionGasLimit)*float64(age)/float64(p.agingPeriod)
+}
+
+type rankedTransaction struct {
+	txID     flow.Identifier
+	tx       *flow.TransactionBody
+	priority float64
+	turn     int
+}
+
+// sortByPriority sorts the transactions by descending priority. Ties are broken
+// by transaction ID, so that the order is deterministic.
+func sortByPriority(ranked []rankedTransaction) {
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].priority != ranked[j].priority {
+			return ranked[i].priority > ranked[j].priority
+		}
+		return bytes.Compare(ranked[i].txID[:], ranked[j].txID[:]) < 0
+	})
+}
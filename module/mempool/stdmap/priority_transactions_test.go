@@ -0,0 +1,145 @@
+package stdmap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func priorityTx(payer flow.Address, gasLimit uint64) *flow.TransactionBody {
+	tx := unittest.TransactionBodyFixture(func(tx *flow.TransactionBody) {
+		tx.Payer = payer
+		tx.GasLimit = gasLimit
+	})
+	return &tx
+}
+
+// TestPriorityTransactions_Basic tests the basic mempool operations.
+func TestPriorityTransactions_Basic(t *testing.T) {
+	pool := NewPriorityTransactions(1000)
+	tx := priorityTx(unittest.RandomAddressFixture(), 10)
+
+	assert.True(t, pool.Add(tx))
+	assert.False(t, pool.Add(tx))
+	assert.True(t, pool.Has(tx.ID()))
+	assert.EqualValues(t, 1, pool.Size())
+
+	got, ok := pool.ByID(tx.ID())
+	require.True(t, ok)
+	assert.Equal(t, tx, got)
+	assert.Equal(t, flow.MerkleRoot(tx.ID()), pool.Hash())
+
+	assert.True(t, pool.Rem(tx.ID()))
+	assert.False(t, pool.Rem(tx.ID()))
+	assert.False(t, pool.Has(tx.ID()))
+
+	pool.Add(tx)
+	pool.Clear()
+	assert.EqualValues(t, 0, pool.Size())
+}
+
+// TestPriorityTransactions_Order tests that transactions are ordered by gas limit,
+// with payers taking turns.
+func TestPriorityTransactions_Order(t *testing.T) {
+	pool := NewPriorityTransactions(1000, WithAgingPeriod(0))
+	payer1 := unittest.RandomAddressFixture()
+	payer2 := unittest.RandomAddressFixture()
+
+	tx1 := priorityTx(payer1, 100)
+	tx2 := priorityTx(payer1, 90)
+	tx3 := priorityTx(payer1, 80)
+	tx4 := priorityTx(payer2, 50)
+	tx5 := priorityTx(payer2, 10)
+	for _, tx := range []*flow.TransactionBody{tx5, tx3, tx1, tx4, tx2} {
+		pool.Add(tx)
+	}
+
+	// the best transaction of each payer comes first
+	assert.Equal(t, []*flow.TransactionBody{tx1, tx4, tx2, tx5, tx3}, pool.All())
+}
+
+// TestPriorityTransactions_Aging tests that the priority of transactions grows with
+// their time in the mempool.
+func TestPriorityTransactions_Aging(t *testing.T) {
+	now := time.Now()
+	pool := NewPriorityTransactions(1000, WithAgingPeriod(time.Minute))
+	pool.now = func() time.Time { return now }
+	payer1 := unittest.RandomAddressFixture()
+	payer2 := unittest.RandomAddressFixture()
+	payer3 := unittest.RandomAddressFixture()
+
+	old := priorityTx(payer1, 10)
+	pool.Add(old)
+
+	now = now.Add(time.Second)
+	young := priorityTx(payer2, flow.DefaultMaxTransactionGasLimit)
+	pool.Add(young)
+	assert.Equal(t, []*flow.TransactionBody{young, old}, pool.All())
+
+	// a transaction which has been in the mempool for an aging period longer than
+	// another one has a higher priority, regardless of their gas limits
+	now = now.Add(time.Minute)
+	youngest := priorityTx(payer3, flow.DefaultMaxTransactionGasLimit)
+	pool.Add(youngest)
+	assert.Equal(t, []*flow.TransactionBody{young, old, youngest}, pool.All())
+}
+
+// TestPriorityTransactions_Eject tests that the transactions with the lowest priority
+// are ejected once the mempool exceeds its limit, and passed to the ejection callbacks.
+func TestPriorityTransactions_Eject(t *testing.T) {
+	limit := uint(10)
+	pool := NewPriorityTransactions(limit, WithAgingPeriod(0))
+	payer := unittest.RandomAddressFixture()
+
+	ejected := make(map[flow.Identifier]struct{})
+	pool.RegisterEjectionCallbacks(func(entity flow.Entity) {
+		ejected[entity.ID()] = struct{}{}
+	})
+
+	var best []*flow.TransactionBody
+	total := limit + overCapacityThreshold + 1
+	for i := uint(0); i < total; i++ {
+		tx := priorityTx(payer, uint64(i))
+		pool.Add(tx)
+		if i >= total-limit {
+			best = append(best, tx)
+		}
+	}
+
+	require.EqualValues(t, limit, pool.Size())
+	for _, tx := range best {
+		assert.True(t, pool.Has(tx.ID()))
+		assert.NotContains(t, ejected, tx.ID())
+	}
+	assert.Len(t, ejected, int(total-limit))
+}
+
+// TestPriorityTransactions_EjectAdded tests that a transaction which is ejected
+// right away, as the mempool is full of transactions with a higher priority, is
+// not reported as added, nor passed to the ejection callbacks.
+func TestPriorityTransactions_EjectAdded(t *testing.T) {
+	limit := uint(10)
+	pool := NewPriorityTransactions(limit, WithAgingPeriod(0))
+	payer := unittest.RandomAddressFixture()
+
+	ejected := make(map[flow.Identifier]struct{})
+	pool.RegisterEjectionCallbacks(func(entity flow.Entity) {
+		ejected[entity.ID()] = struct{}{}
+	})
+
+	for i := uint(0); i < limit+overCapacityThreshold; i++ {
+		require.True(t, pool.Add(priorityTx(payer, 100)))
+	}
+
+	low := priorityTx(payer, 1)
+	assert.False(t, pool.Add(low))
+	assert.False(t, pool.Has(low.ID()))
+	assert.NotContains(t, ejected, low.ID())
+	assert.EqualValues(t, limit, pool.Size())
+	assert.Len(t, ejected, int(overCapacityThreshold))
+}
@@ -61,6 +61,21 @@ type NetworkMetrics interface {
 
 	// UnstakedInboundConnections updates the metric tracking the number of inbound connections from unstaked nodes
 	UnstakedInboundConnections(connectionCount uint)
+
+	// InboundMessageRateLimited counts the number of messages from the given peer dropped on the given topic due to rate limiting
+	InboundMessageRateLimited(peerID string, topic string)
+
+	// PeerMisbehaviour counts the number of misbehaviours of the given kind committed by the given peer
+	PeerMisbehaviour(peerID string, misbehaviour string)
+
+	// PeerScore updates the metric tracking the misbehaviour score of the given peer
+	PeerScore(peerID string, score float64)
+
+	// PeerBlocklisted counts the number of times the given peer was blocklisted due to misbehaviour
+	PeerBlocklisted(peerID string)
+
+	// PayloadCompressed tracks the compression ratio of an outbound payload compressed with the given algorithm
+	PayloadCompressed(algorithm string, uncompressedSize int, compressedSize int)
 }
 
 type EngineMetrics interface {
@@ -253,6 +268,14 @@ type VerificationMetrics interface {
 	// OnResultApprovalDispatchedInNetwork increments a counter that keeps track of number of result approvals dispatched in the network
 	// by verifier engine.
 	OnResultApprovalDispatchedInNetworkByVerifier()
+
+	// SetVerifiableChunkQueueSizeAtVerifier sets a gauge that keeps track of number of verifiable chunks waiting in the queue
+	// of verifier engine for a worker to verify them.
+	SetVerifiableChunkQueueSizeAtVerifier(size uint)
+
+	// OnChunkVerifiedAtVerifier is invoked whenever a worker of verifier engine is done verifying a chunk. It records the
+	// time it took to verify the chunk.
+	OnChunkVerifiedAtVerifier(duration time.Duration)
 }
 
 // LedgerMetrics provides an interface to record Ledger Storage metrics.
@@ -265,6 +288,12 @@ type LedgerMetrics interface {
 	// ForestNumberOfTrees current number of trees in a forest (in memory)
 	ForestNumberOfTrees(number uint64)
 
+	// ForestNumberOfPinnedTrees current number of trees in a forest which are exempt from eviction
+	ForestNumberOfPinnedTrees(number uint64)
+
+	// ForestNumberOfEvictableTrees current number of trees in a forest which are subject to eviction
+	ForestNumberOfEvictableTrees(number uint64)
+
 	// LatestTrieRegCount records the number of unique register allocated (the latest created trie)
 	LatestTrieRegCount(number uint64)
 
@@ -380,6 +409,10 @@ type ExecutionMetrics interface {
 	ExecutionBlockDataUploadStarted()
 
 	ExecutionBlockDataUploadFinished(dur time.Duration)
+
+	// ExecutionDataPruned reports the height up to which execution data was pruned,
+	// the number of blocks pruned in a run of the pruner, and the duration of the run.
+	ExecutionDataPruned(height uint64, blocks int, dur time.Duration)
 }
 
 type TransactionMetrics interface {
@@ -30,6 +30,8 @@ type ExecutionCollector struct {
 	storageStateCommitment           prometheus.Gauge
 	forestApproxMemorySize           prometheus.Gauge
 	forestNumberOfTrees              prometheus.Gauge
+	forestNumberOfPinnedTrees        prometheus.Gauge
+	forestNumberOfEvictableTrees     prometheus.Gauge
 	latestTrieRegCount               prometheus.Gauge
 	latestTrieRegCountDiff           prometheus.Gauge
 	latestTrieMaxDepth               prometheus.Gauge
@@ -67,6 +69,9 @@ type ExecutionCollector struct {
 	executionStateDiskUsage          prometheus.Gauge
 	blockDataUploadsInProgress       prometheus.Gauge
 	blockDataUploadsDuration         prometheus.Histogram
+	prunedHeight                     prometheus.Gauge
+	prunedBlocks                     prometheus.Counter
+	pruningDuration                  prometheus.Histogram
 }
 
 func NewExecutionCollector(tracer module.Tracer, registerer prometheus.Registerer) *ExecutionCollector {
@@ -85,6 +90,20 @@ func NewExecutionCollector(tracer module.Tracer, registerer prometheus.Registere
 		Help:      "the number of trees in memory",
 	})
 
+	forestNumberOfPinnedTrees := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemMTrie,
+		Name:      "forest_number_of_pinned_trees",
+		Help:      "the number of trees which are exempt from eviction",
+	})
+
+	forestNumberOfEvictableTrees := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemMTrie,
+		Name:      "forest_number_of_evictable_trees",
+		Help:      "the number of trees in memory which are subject to eviction",
+	})
+
 	latestTrieRegCount := prometheus.NewGauge(prometheus.GaugeOpts{
 		Namespace: namespaceExecution,
 		Subsystem: subsystemMTrie,
@@ -336,8 +355,32 @@ func NewExecutionCollector(tracer module.Tracer, registerer prometheus.Registere
 		Buckets:   []float64{1, 100, 500, 1000, 2000},
 	})
 
+	prunedHeight := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemPruner,
+		Name:      "pruned_height",
+		Help:      "the height up to which the execution data of blocks was pruned",
+	})
+
+	prunedBlocks := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemPruner,
+		Name:      "pruned_blocks_total",
+		Help:      "the total number of blocks whose execution data was pruned",
+	})
+
+	pruningDuration := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemPruner,
+		Name:      "pruning_duration_ms",
+		Help:      "the duration of a run of the execution data pruner",
+		Buckets:   []float64{10, 100, 500, 1000, 5000, 10000},
+	})
+
 	registerer.MustRegister(forestApproxMemorySize)
 	registerer.MustRegister(forestNumberOfTrees)
+	registerer.MustRegister(forestNumberOfPinnedTrees)
+	registerer.MustRegister(forestNumberOfEvictableTrees)
 	registerer.MustRegister(latestTrieRegCount)
 	registerer.MustRegister(latestTrieRegCountDiff)
 	registerer.MustRegister(latestTrieMaxDepth)
@@ -372,46 +415,54 @@ func NewExecutionCollector(tracer module.Tracer, registerer prometheus.Registere
 	registerer.MustRegister(totalChunkDataPackRequests)
 	registerer.MustRegister(blockDataUploadsInProgress)
 	registerer.MustRegister(blockDataUploadsDuration)
+	registerer.MustRegister(prunedHeight)
+	registerer.MustRegister(prunedBlocks)
+	registerer.MustRegister(pruningDuration)
 
 	ec := &ExecutionCollector{
 		tracer: tracer,
 
-		forestApproxMemorySize:      forestApproxMemorySize,
-		forestNumberOfTrees:         forestNumberOfTrees,
-		latestTrieRegCount:          latestTrieRegCount,
-		latestTrieRegCountDiff:      latestTrieRegCountDiff,
-		latestTrieMaxDepth:          latestTrieMaxDepth,
-		latestTrieMaxDepthDiff:      latestTrieMaxDepthDiff,
-		updated:                     updatedCount,
-		proofSize:                   proofSize,
-		updatedValuesNumber:         updatedValuesNumber,
-		updatedValuesSize:           updatedValuesSize,
-		updatedDuration:             updatedDuration,
-		updatedDurationPerValue:     updatedDurationPerValue,
-		readValuesNumber:            readValuesNumber,
-		readValuesSize:              readValuesSize,
-		readDuration:                readDuration,
-		readDurationPerValue:        readDurationPerValue,
-		blockExecutionTime:          blockExecutionTime,
-		blockComputationUsed:        blockComputationUsed,
-		blockTransactionCounts:      blockTransactionCounts,
-		blockCollectionCounts:       blockCollectionCounts,
-		collectionExecutionTime:     collectionExecutionTime,
-		collectionComputationUsed:   collectionComputationUsed,
-		collectionTransactionCounts: collectionTransactionCounts,
-		collectionRequestSent:       collectionRequestsSent,
-		collectionRequestRetried:    collectionRequestsRetries,
-		transactionParseTime:        transactionParseTime,
-		transactionCheckTime:        transactionCheckTime,
-		transactionInterpretTime:    transactionInterpretTime,
-		transactionExecutionTime:    transactionExecutionTime,
-		transactionComputationUsed:  transactionComputationUsed,
-		transactionEmittedEvents:    transactionEmittedEvents,
-		scriptExecutionTime:         scriptExecutionTime,
-		scriptComputationUsed:       scriptComputationUsed,
-		totalChunkDataPackRequests:  totalChunkDataPackRequests,
-		blockDataUploadsInProgress:  blockDataUploadsInProgress,
-		blockDataUploadsDuration:    blockDataUploadsDuration,
+		forestApproxMemorySize:       forestApproxMemorySize,
+		forestNumberOfTrees:          forestNumberOfTrees,
+		forestNumberOfPinnedTrees:    forestNumberOfPinnedTrees,
+		forestNumberOfEvictableTrees: forestNumberOfEvictableTrees,
+		latestTrieRegCount:           latestTrieRegCount,
+		latestTrieRegCountDiff:       latestTrieRegCountDiff,
+		latestTrieMaxDepth:           latestTrieMaxDepth,
+		latestTrieMaxDepthDiff:       latestTrieMaxDepthDiff,
+		updated:                      updatedCount,
+		proofSize:                    proofSize,
+		updatedValuesNumber:          updatedValuesNumber,
+		updatedValuesSize:            updatedValuesSize,
+		updatedDuration:              updatedDuration,
+		updatedDurationPerValue:      updatedDurationPerValue,
+		readValuesNumber:             readValuesNumber,
+		readValuesSize:               readValuesSize,
+		readDuration:                 readDuration,
+		readDurationPerValue:         readDurationPerValue,
+		blockExecutionTime:           blockExecutionTime,
+		blockComputationUsed:         blockComputationUsed,
+		blockTransactionCounts:       blockTransactionCounts,
+		blockCollectionCounts:        blockCollectionCounts,
+		collectionExecutionTime:      collectionExecutionTime,
+		collectionComputationUsed:    collectionComputationUsed,
+		collectionTransactionCounts:  collectionTransactionCounts,
+		collectionRequestSent:        collectionRequestsSent,
+		collectionRequestRetried:     collectionRequestsRetries,
+		transactionParseTime:         transactionParseTime,
+		transactionCheckTime:         transactionCheckTime,
+		transactionInterpretTime:     transactionInterpretTime,
+		transactionExecutionTime:     transactionExecutionTime,
+		transactionComputationUsed:   transactionComputationUsed,
+		transactionEmittedEvents:     transactionEmittedEvents,
+		scriptExecutionTime:          scriptExecutionTime,
+		scriptComputationUsed:        scriptComputationUsed,
+		totalChunkDataPackRequests:   totalChunkDataPackRequests,
+		blockDataUploadsInProgress:   blockDataUploadsInProgress,
+		blockDataUploadsDuration:     blockDataUploadsDuration,
+		prunedHeight:                 prunedHeight,
+		prunedBlocks:                 prunedBlocks,
+		pruningDuration:              pruningDuration,
 
 		stateReadsPerBlock: promauto.NewHistogram(prometheus.HistogramOpts{
 			Namespace: namespaceExecution,
@@ -579,6 +630,16 @@ func (ec *ExecutionCollector) ForestNumberOfTrees(number uint64) {
 	ec.forestNumberOfTrees.Set(float64(number))
 }
 
+// ForestNumberOfPinnedTrees current number of trees in a forest which are exempt from eviction
+func (ec *ExecutionCollector) ForestNumberOfPinnedTrees(number uint64) {
+	ec.forestNumberOfPinnedTrees.Set(float64(number))
+}
+
+// ForestNumberOfEvictableTrees current number of trees in a forest which are subject to eviction
+func (ec *ExecutionCollector) ForestNumberOfEvictableTrees(number uint64) {
+	ec.forestNumberOfEvictableTrees.Set(float64(number))
+}
+
 // LatestTrieRegCount records the number of unique register allocated (the lastest created trie)
 func (ec *ExecutionCollector) LatestTrieRegCount(number uint64) {
 	ec.latestTrieRegCount.Set(float64(number))
@@ -666,6 +727,14 @@ func (ec *ExecutionCollector) ExecutionBlockDataUploadFinished(dur time.Duration
 	ec.blockDataUploadsDuration.Observe(float64(dur.Milliseconds()))
 }
 
+// ExecutionDataPruned reports the height up to which execution data was pruned,
+// the number of blocks pruned in a run of the pruner, and the duration of the run.
+func (ec *ExecutionCollector) ExecutionDataPruned(height uint64, blocks int, dur time.Duration) {
+	ec.prunedHeight.Set(float64(height))
+	ec.prunedBlocks.Add(float64(blocks))
+	ec.pruningDuration.Observe(float64(dur.Milliseconds()))
+}
+
 // TransactionParsed reports the time spent parsing a single transaction
 func (ec *ExecutionCollector) RuntimeTransactionParsed(dur time.Duration) {
 	ec.transactionParseTime.Observe(float64(dur))
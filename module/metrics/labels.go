@@ -1,18 +1,21 @@
 package metrics
 
 const (
-	LabelChannel     = "topic"
-	LabelChain       = "chain"
-	LabelProposer    = "proposer"
-	EngineLabel      = "engine"
-	LabelResource    = "resource"
-	LabelMessage     = "message"
-	LabelNodeID      = "nodeid"
-	LabelNodeAddress = "nodeaddress"
-	LabelNodeRole    = "noderole"
-	LabelNodeInfo    = "nodeinfo"
-	LabelNodeVersion = "nodeversion"
-	LabelPriority    = "priority"
+	LabelChannel      = "topic"
+	LabelChain        = "chain"
+	LabelProposer     = "proposer"
+	EngineLabel       = "engine"
+	LabelResource     = "resource"
+	LabelMessage      = "message"
+	LabelNodeID       = "nodeid"
+	LabelNodeAddress  = "nodeaddress"
+	LabelNodeRole     = "noderole"
+	LabelNodeInfo     = "nodeinfo"
+	LabelNodeVersion  = "nodeversion"
+	LabelPriority     = "priority"
+	LabelPeerID       = "peerid"
+	LabelMisbehaviour = "misbehaviour"
+	LabelAlgorithm    = "algorithm"
 )
 
 const (
@@ -78,12 +81,14 @@ const (
 
 	ResourceClusterBlockProposalQueue = "cluster_compliance_proposal_queue" // collection node, compliance engine
 	ResourceClusterBlockVoteQueue     = "cluster_compliance_vote_queue"     // collection node, compliance engine
+	ResourceClusterTimeoutQueue       = "cluster_compliance_timeout_queue"  // collection node, compliance engine
 	ResourceDKGKey                    = "dkg-key"                           // consensus node, DKG engine
 	ResourceApprovalQueue             = "sealing_approval_queue"            // consensus node, sealing engine
 	ResourceReceiptQueue              = "sealing_receipt_queue"             // consensus node, sealing engine
 	ResourceApprovalResponseQueue     = "sealing_approval_response_queue"   // consensus node, sealing engine
 	ResourceBlockProposalQueue        = "compliance_proposal_queue"         // consensus node, compliance engine
 	ResourceBlockVoteQueue            = "compliance_vote_queue"             // consensus node, compliance engine
+	ResourceTimeoutQueue              = "compliance_timeout_queue"          // consensus node, compliance engine
 	ResourceChunkDataPack             = "chunk_data_pack"                   // execution node
 	ResourceEvents                    = "events"                            // execution node
 	ResourceServiceEvents             = "service_events"                    // execution node
@@ -94,6 +99,7 @@ const (
 	MessageCollectionGuarantee  = "guarantee"
 	MessageBlockProposal        = "proposal"
 	MessageBlockVote            = "vote"
+	MessageTimeoutObject        = "timeout"
 	MessageExecutionReceipt     = "receipt"
 	MessageResultApproval       = "approval"
 	MessageSyncRequest          = "ping"
@@ -104,6 +110,7 @@ const (
 	MessageSyncedBlock          = "synced_block"
 	MessageClusterBlockProposal = "cluster_proposal"
 	MessageClusterBlockVote     = "cluster_vote"
+	MessageClusterTimeoutObject = "cluster_timeout"
 	MessageClusterBlockResponse = "cluster_block_response"
 	MessageSyncedClusterBlock   = "synced_cluster_block"
 	MessageTransaction          = "transaction"
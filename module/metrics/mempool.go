@@ -56,6 +56,19 @@ func (mc *MempoolCollector) Register(resource string, entriesFunc module.Entries
 	return nil
 }
 
+// Entries returns the current number of entries of the registered mempools, by resource.
+func (mc *MempoolCollector) Entries() map[string]uint {
+	mc.unit.Lock()
+	defer mc.unit.Unlock()
+
+	entries := make(map[string]uint, len(mc.entriesFuncs))
+	for r, f := range mc.entriesFuncs {
+		entries[r] = f()
+	}
+
+	return entries
+}
+
 func (mc *MempoolCollector) Ready() <-chan struct{} {
 	mc.unit.LaunchPeriodically(mc.gaugeEntries, mc.interval, mc.delay)
 	return mc.unit.Ready()
@@ -53,6 +53,7 @@ const (
 	subsystemRuntime           = "runtime"
 	subsystemProvider          = "provider"
 	subsystemBlockDataUploader = "block_data_uploader"
+	subsystemPruner            = "pruner"
 )
 
 // Verification Subsystems
@@ -30,6 +30,12 @@ type NetworkCollector struct {
 	dnsCacheInvalidationCount       prometheus.Counter
 	unstakedOutboundConnectionCount prometheus.Gauge
 	unstakedInboundConnectionCount  prometheus.Gauge
+	rateLimitedMessages             *prometheus.CounterVec
+	peerMisbehaviours               *prometheus.CounterVec
+	peerScore                       *prometheus.GaugeVec
+	peerBlocklisted                 *prometheus.CounterVec
+	compressionRatio                *prometheus.HistogramVec
+	compressionSavedBytes           *prometheus.CounterVec
 }
 
 func NewNetworkCollector() *NetworkCollector {
@@ -137,6 +143,49 @@ func NewNetworkCollector() *NetworkCollector {
 			Name:      "unstaked_inbound_connection_count",
 			Help:      "the number of inbound connections from unstaked nodes",
 		}),
+
+		rateLimitedMessages: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      "rate_limited_messages_total",
+			Help:      "the number of inbound messages dropped due to rate limiting",
+		}, []string{LabelPeerID, LabelChannel}),
+
+		peerMisbehaviours: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      "peer_misbehaviours_total",
+			Help:      "the number of misbehaviours committed by a peer",
+		}, []string{LabelPeerID, LabelMisbehaviour}),
+
+		peerScore: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      "peer_misbehaviour_score",
+			Help:      "the misbehaviour score of a peer as of its last misbehaviour",
+		}, []string{LabelPeerID}),
+
+		peerBlocklisted: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      "peer_blocklisted_total",
+			Help:      "the number of times a peer was blocklisted due to misbehaviour",
+		}, []string{LabelPeerID}),
+
+		compressionRatio: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      "payload_compression_ratio",
+			Help:      "the ratio of the uncompressed to the compressed size of outbound payloads",
+			Buckets:   []float64{1, 1.25, 1.5, 2, 3, 5, 10},
+		}, []string{LabelAlgorithm}),
+
+		compressionSavedBytes: promauto.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      "payload_compression_saved_bytes_total",
+			Help:      "the number of bytes saved by compressing outbound payloads",
+		}, []string{LabelAlgorithm}),
 	}
 
 	return nc
@@ -214,3 +263,32 @@ func (nc *NetworkCollector) UnstakedOutboundConnections(connectionCount uint) {
 func (nc *NetworkCollector) UnstakedInboundConnections(connectionCount uint) {
 	nc.unstakedInboundConnectionCount.Set(float64(connectionCount))
 }
+
+// InboundMessageRateLimited counts the number of messages from the given peer dropped on the given topic due to rate limiting
+func (nc *NetworkCollector) InboundMessageRateLimited(peerID string, topic string) {
+	nc.rateLimitedMessages.WithLabelValues(peerID, topic).Inc()
+}
+
+// PeerMisbehaviour counts the number of misbehaviours of the given kind committed by the given peer
+func (nc *NetworkCollector) PeerMisbehaviour(peerID string, misbehaviour string) {
+	nc.peerMisbehaviours.WithLabelValues(peerID, misbehaviour).Inc()
+}
+
+// PeerScore updates the metric tracking the misbehaviour score of the given peer
+func (nc *NetworkCollector) PeerScore(peerID string, score float64) {
+	nc.peerScore.WithLabelValues(peerID).Set(score)
+}
+
+// PeerBlocklisted counts the number of times the given peer was blocklisted due to misbehaviour
+func (nc *NetworkCollector) PeerBlocklisted(peerID string) {
+	nc.peerBlocklisted.WithLabelValues(peerID).Inc()
+}
+
+// PayloadCompressed tracks the compression ratio of an outbound payload compressed with the given algorithm
+func (nc *NetworkCollector) PayloadCompressed(algorithm string, uncompressedSize int, compressedSize int) {
+	if compressedSize <= 0 {
+		return
+	}
+	nc.compressionRatio.WithLabelValues(algorithm).Observe(float64(uncompressedSize) / float64(compressedSize))
+	nc.compressionSavedBytes.WithLabelValues(algorithm).Add(float64(uncompressedSize - compressedSize))
+}
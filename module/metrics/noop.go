@@ -33,6 +33,11 @@ func (nc *NoopCollector) OnDNSCacheInvalidated()
 func (nc *NoopCollector) OnDNSCacheHit()                                                         {}
 func (nc *NoopCollector) UnstakedOutboundConnections(_ uint)                                     {}
 func (nc *NoopCollector) UnstakedInboundConnections(_ uint)                                      {}
+func (nc *NoopCollector) InboundMessageRateLimited(peerID string, topic string)                  {}
+func (nc *NoopCollector) PeerMisbehaviour(peerID string, misbehaviour string)                    {}
+func (nc *NoopCollector) PeerScore(peerID string, score float64)                                 {}
+func (nc *NoopCollector) PeerBlocklisted(peerID string)                                          {}
+func (nc *NoopCollector) PayloadCompressed(algorithm string, uncompressed, compressed int)       {}
 func (nc *NoopCollector) RanGC(duration time.Duration)                                           {}
 func (nc *NoopCollector) BadgerLSMSize(sizeBytes int64)                                          {}
 func (nc *NoopCollector) BadgerVLogSize(sizeBytes int64)                                         {}
@@ -101,6 +106,8 @@ func (nc *NoopCollector) OnChunkDataPackRequestReceivedByRequester()
 func (nc *NoopCollector) OnChunkDataPackArrivedAtFetcher()                                      {}
 func (nc *NoopCollector) OnChunkDataPackSentToFetcher()                                         {}
 func (nc *NoopCollector) OnVerifiableChunkSentToVerifier()                                      {}
+func (nc *NoopCollector) SetVerifiableChunkQueueSizeAtVerifier(size uint)                       {}
+func (nc *NoopCollector) OnChunkVerifiedAtVerifier(duration time.Duration)                      {}
 func (nc *NoopCollector) OnBlockConsumerJobDone(uint64)                                         {}
 func (nc *NoopCollector) OnChunkConsumerJobDone(uint64)                                         {}
 func (nc *NoopCollector) OnChunkDataPackResponseReceivedFromNetworkByRequester()                {}
@@ -116,6 +123,8 @@ func (nc *NoopCollector) ExecutionTransactionExecuted(_ time.Duration, _ uint64,
 func (nc *NoopCollector) ExecutionScriptExecuted(dur time.Duration, compUsed uint64)            {}
 func (nc *NoopCollector) ForestApproxMemorySize(bytes uint64)                                   {}
 func (nc *NoopCollector) ForestNumberOfTrees(number uint64)                                     {}
+func (nc *NoopCollector) ForestNumberOfPinnedTrees(number uint64)                               {}
+func (nc *NoopCollector) ForestNumberOfEvictableTrees(number uint64)                            {}
 func (nc *NoopCollector) LatestTrieRegCount(number uint64)                                      {}
 func (nc *NoopCollector) LatestTrieRegCountDiff(number uint64)                                  {}
 func (nc *NoopCollector) LatestTrieMaxDepth(number uint64)                                      {}
@@ -146,3 +155,4 @@ func (nc *NoopCollector) ExecutionSync(syncing bool)
 func (nc *NoopCollector) DiskSize(uint64)                                                       {}
 func (nc *NoopCollector) ExecutionBlockDataUploadStarted()                                      {}
 func (nc *NoopCollector) ExecutionBlockDataUploadFinished(dur time.Duration)                    {}
+func (nc *NoopCollector) ExecutionDataPruned(height uint64, blocks int, dur time.Duration)      {}
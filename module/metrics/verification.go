@@ -1,6 +1,8 @@
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/onflow/flow-go/module"
@@ -39,8 +41,10 @@ type VerificationCollector struct {
 	maxChunkDataPackRequestAttemptForNextUnsealedHeight prometheus.Gauge
 
 	// Verifier Engine
-	receivedVerifiableChunkTotalVerifier prometheus.Counter // total verifiable chunks received by verifier engine
-	sentResultApprovalTotalVerifier      prometheus.Counter // total result approvals sent by verifier engine
+	receivedVerifiableChunkTotalVerifier prometheus.Counter   // total verifiable chunks received by verifier engine
+	sentResultApprovalTotalVerifier      prometheus.Counter   // total result approvals sent by verifier engine
+	chunkQueueSizeVerifier               prometheus.Gauge     // number of verifiable chunks waiting for a worker at verifier engine
+	chunkVerificationDurationVerifier    prometheus.Histogram // time it takes verifier engine to verify a chunk
 
 }
 
@@ -173,6 +177,21 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 		Help:      "total number of emitted result approvals by verifier engine",
 	})
 
+	chunkQueueSizeVerifier := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:      "verifiable_chunk_queue_size",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemVerifierEngine,
+		Help:      "number of verifiable chunks waiting in the queue of verifier engine for a worker",
+	})
+
+	chunkVerificationDurationVerifier := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:      "chunk_verification_duration_seconds",
+		Namespace: namespaceVerification,
+		Subsystem: subsystemVerifierEngine,
+		Help:      "the time it takes verifier engine to verify a chunk",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	})
+
 	// registers all metrics and panics if any fails.
 	registerer.MustRegister(
 		// job consumers
@@ -200,7 +219,9 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 
 		// verifier engine
 		receivedVerifiableChunksTotalVerifier,
-		sentResultApprovalTotalVerifier)
+		sentResultApprovalTotalVerifier,
+		chunkQueueSizeVerifier,
+		chunkVerificationDurationVerifier)
 
 	vc := &VerificationCollector{
 		tracer: tracer,
@@ -224,6 +245,8 @@ func NewVerificationCollector(tracer module.Tracer, registerer prometheus.Regist
 		// verifier
 		sentResultApprovalTotalVerifier:      sentResultApprovalTotalVerifier,
 		receivedVerifiableChunkTotalVerifier: receivedVerifiableChunksTotalVerifier,
+		chunkQueueSizeVerifier:               chunkQueueSizeVerifier,
+		chunkVerificationDurationVerifier:    chunkVerificationDurationVerifier,
 
 		// requester
 		receivedChunkDataPackRequestsTotalRequester:         receivedChunkDataPackRequestsTotalRequester,
@@ -339,3 +362,15 @@ func (vc *VerificationCollector) OnBlockConsumerJobDone(processedIndex uint64) {
 func (vc *VerificationCollector) SetMaxChunkDataPackAttemptsForNextUnsealedHeightAtRequester(attempts uint64) {
 	vc.maxChunkDataPackRequestAttemptForNextUnsealedHeight.Set(float64(attempts))
 }
+
+// SetVerifiableChunkQueueSizeAtVerifier sets a gauge that keeps track of number of verifiable chunks waiting in the queue
+// of verifier engine for a worker to verify them.
+func (vc *VerificationCollector) SetVerifiableChunkQueueSizeAtVerifier(size uint) {
+	vc.chunkQueueSizeVerifier.Set(float64(size))
+}
+
+// OnChunkVerifiedAtVerifier is invoked whenever a worker of verifier engine is done verifying a chunk. It records the
+// time it took to verify the chunk.
+func (vc *VerificationCollector) OnChunkVerifiedAtVerifier(duration time.Duration) {
+	vc.chunkVerificationDurationVerifier.Observe(duration.Seconds())
+}
@@ -54,6 +54,11 @@ func (_m *ExecutionMetrics) ExecutionCollectionRequestSent() {
 	_m.Called()
 }
 
+// ExecutionDataPruned provides a mock function with given fields: height, blocks, dur
+func (_m *ExecutionMetrics) ExecutionDataPruned(height uint64, blocks int, dur time.Duration) {
+	_m.Called(height, blocks, dur)
+}
+
 // ExecutionLastExecutedBlockHeight provides a mock function with given fields: height
 func (_m *ExecutionMetrics) ExecutionLastExecutedBlockHeight(height uint64) {
 	_m.Called(height)
@@ -94,6 +99,16 @@ func (_m *ExecutionMetrics) ForestApproxMemorySize(bytes uint64) {
 	_m.Called(bytes)
 }
 
+// ForestNumberOfEvictableTrees provides a mock function with given fields: number
+func (_m *ExecutionMetrics) ForestNumberOfEvictableTrees(number uint64) {
+	_m.Called(number)
+}
+
+// ForestNumberOfPinnedTrees provides a mock function with given fields: number
+func (_m *ExecutionMetrics) ForestNumberOfPinnedTrees(number uint64) {
+	_m.Called(number)
+}
+
 // ForestNumberOfTrees provides a mock function with given fields: number
 func (_m *ExecutionMetrics) ForestNumberOfTrees(number uint64) {
 	_m.Called(number)
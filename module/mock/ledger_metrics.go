@@ -18,6 +18,16 @@ func (_m *LedgerMetrics) ForestApproxMemorySize(bytes uint64) {
 	_m.Called(bytes)
 }
 
+// ForestNumberOfEvictableTrees provides a mock function with given fields: number
+func (_m *LedgerMetrics) ForestNumberOfEvictableTrees(number uint64) {
+	_m.Called(number)
+}
+
+// ForestNumberOfPinnedTrees provides a mock function with given fields: number
+func (_m *LedgerMetrics) ForestNumberOfPinnedTrees(number uint64) {
+	_m.Called(number)
+}
+
 // ForestNumberOfTrees provides a mock function with given fields: number
 func (_m *LedgerMetrics) ForestNumberOfTrees(number uint64) {
 	_m.Called(number)
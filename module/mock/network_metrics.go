@@ -23,6 +23,11 @@ func (_m *NetworkMetrics) InboundConnections(connectionCount uint) {
 	_m.Called(connectionCount)
 }
 
+// InboundMessageRateLimited provides a mock function with given fields: peerID, topic
+func (_m *NetworkMetrics) InboundMessageRateLimited(peerID string, topic string) {
+	_m.Called(peerID, topic)
+}
+
 // InboundProcessDuration provides a mock function with given fields: topic, duration
 func (_m *NetworkMetrics) InboundProcessDuration(topic string, duration time.Duration) {
 	_m.Called(topic, duration)
@@ -73,6 +78,26 @@ func (_m *NetworkMetrics) OutboundConnections(connectionCount uint) {
 	_m.Called(connectionCount)
 }
 
+// PayloadCompressed provides a mock function with given fields: algorithm, uncompressedSize, compressedSize
+func (_m *NetworkMetrics) PayloadCompressed(algorithm string, uncompressedSize int, compressedSize int) {
+	_m.Called(algorithm, uncompressedSize, compressedSize)
+}
+
+// PeerBlocklisted provides a mock function with given fields: peerID
+func (_m *NetworkMetrics) PeerBlocklisted(peerID string) {
+	_m.Called(peerID)
+}
+
+// PeerMisbehaviour provides a mock function with given fields: peerID, misbehaviour
+func (_m *NetworkMetrics) PeerMisbehaviour(peerID string, misbehaviour string) {
+	_m.Called(peerID, misbehaviour)
+}
+
+// PeerScore provides a mock function with given fields: peerID, score
+func (_m *NetworkMetrics) PeerScore(peerID string, score float64) {
+	_m.Called(peerID, score)
+}
+
 // QueueDuration provides a mock function with given fields: duration, priority
 func (_m *NetworkMetrics) QueueDuration(duration time.Duration, priority int) {
 	_m.Called(duration, priority)
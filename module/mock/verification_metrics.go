@@ -2,7 +2,11 @@
 
 package mock
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
 
 // VerificationMetrics is an autogenerated mock type for the VerificationMetrics type
 type VerificationMetrics struct {
@@ -59,6 +63,11 @@ func (_m *VerificationMetrics) OnChunkDataPackSentToFetcher() {
 	_m.Called()
 }
 
+// OnChunkVerifiedAtVerifier provides a mock function with given fields: duration
+func (_m *VerificationMetrics) OnChunkVerifiedAtVerifier(duration time.Duration) {
+	_m.Called(duration)
+}
+
 // OnChunksAssignmentDoneAtAssigner provides a mock function with given fields: chunks
 func (_m *VerificationMetrics) OnChunksAssignmentDoneAtAssigner(chunks int) {
 	_m.Called(chunks)
@@ -93,3 +102,8 @@ func (_m *VerificationMetrics) OnVerifiableChunkSentToVerifier() {
 func (_m *VerificationMetrics) SetMaxChunkDataPackAttemptsForNextUnsealedHeightAtRequester(attempts uint64) {
 	_m.Called(attempts)
 }
+
+// SetVerifiableChunkQueueSizeAtVerifier provides a mock function with given fields: size
+func (_m *VerificationMetrics) SetVerifiableChunkQueueSizeAtVerifier(size uint) {
+	_m.Called(size)
+}
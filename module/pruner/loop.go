@@ -0,0 +1,171 @@
+package pruner
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+)
+
+const (
+	DefaultBatchSize = 100
+	DefaultInterval  = time.Minute
+)
+
+// Heights is the data pruned by a Loop, one finalized block after the other.
+type Heights interface {
+
+	// PrunedHeight returns the height up to which the data is pruned. It
+	// returns storage.ErrNotFound if the pruned height is not initialized yet.
+	PrunedHeight() (uint64, error)
+
+	// InitPrunedHeight initializes the pruned height on the first start.
+	InitPrunedHeight(height uint64) error
+
+	// PruneLimit returns the highest height whose data can currently be pruned.
+	PruneLimit() (uint64, error)
+
+	// PruneHeight prunes the data of the given finalized block. It must update
+	// the pruned height to the height of the block in the same database
+	// transaction, so that pruning is resumed where it left off after a crash.
+	PruneHeight(header *flow.Header) error
+}
+
+// Loop prunes data by the height of finalized blocks in the background. Each
+// run prunes at most a batch of heights above the pruned height, up to the
+// limit given by the pruned data.
+type Loop struct {
+	unit      *engine.Unit
+	log       zerolog.Logger
+	state     protocol.State
+	heights   Heights
+	onPruned  func(height uint64, blocks uint, dur time.Duration)
+	batchSize uint          // maximum number of blocks to prune in one run
+	interval  time.Duration // interval between runs
+}
+
+// LoopOption configures a Loop.
+type LoopOption func(*Loop)
+
+// WithOnPruned sets a callback invoked after each run with the pruned height,
+// the number of blocks pruned by the run and its duration.
+func WithOnPruned(onPruned func(height uint64, blocks uint, dur time.Duration)) LoopOption {
+	return func(l *Loop) {
+		l.onPruned = onPruned
+	}
+}
+
+func NewLoop(
+	log zerolog.Logger,
+	state protocol.State,
+	heights Heights,
+	batchSize uint,
+	interval time.Duration,
+	opts ...LoopOption,
+) *Loop {
+	l := &Loop{
+		unit:      engine.NewUnit(),
+		log:       log,
+		state:     state,
+		heights:   heights,
+		onPruned:  func(uint64, uint, time.Duration) {},
+		batchSize: batchSize,
+		interval:  interval,
+	}
+	for _, apply := range opts {
+		apply(l)
+	}
+	return l
+}
+
+// Ready initializes the pruned height on the first start, before starting to
+// prune periodically.
+func (l *Loop) Ready() <-chan struct{} {
+	err := l.Bootstrap()
+	if err != nil {
+		l.log.Fatal().Err(err).Msg("could not initialize pruned height")
+	}
+	l.unit.LaunchPeriodically(l.run, l.interval, 0)
+	return l.unit.Ready()
+}
+
+func (l *Loop) Done() <-chan struct{} {
+	return l.unit.Done()
+}
+
+// Bootstrap initializes the pruned height with the root height, if it has not
+// been initialized before. The root block is thus never pruned.
+func (l *Loop) Bootstrap() error {
+	_, err := l.heights.PrunedHeight()
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("could not retrieve pruned height: %w", err)
+	}
+
+	root, err := l.state.Params().Root()
+	if err != nil {
+		return fmt.Errorf("could not get root block: %w", err)
+	}
+	err = l.heights.InitPrunedHeight(root.Height)
+	if err != nil {
+		return fmt.Errorf("could not insert pruned height: %w", err)
+	}
+
+	return nil
+}
+
+func (l *Loop) run() {
+	err := l.Prune()
+	if err != nil {
+		l.log.Error().Err(err).Msg("could not prune")
+	}
+}
+
+// Prune prunes at most one batch of blocks, starting from the block above the
+// pruned height.
+func (l *Loop) Prune() error {
+	start := time.Now()
+
+	pruned, err := l.heights.PrunedHeight()
+	if err != nil {
+		return fmt.Errorf("could not retrieve pruned height: %w", err)
+	}
+
+	limit, err := l.heights.PruneLimit()
+	if err != nil {
+		return err
+	}
+
+	blocks := uint(0)
+	for height := pruned + 1; height <= limit && blocks < l.batchSize; height++ {
+		header, err := l.state.AtHeight(height).Head()
+		if err != nil {
+			return fmt.Errorf("could not get finalized block at height %d: %w", height, err)
+		}
+		err = l.heights.PruneHeight(header)
+		if err != nil {
+			return fmt.Errorf("could not prune block at height %d: %w", height, err)
+		}
+		pruned = height
+		blocks++
+	}
+
+	l.onPruned(pruned, blocks, time.Since(start))
+
+	if blocks > 0 {
+		l.log.Debug().
+			Uint64("pruned_height", pruned).
+			Uint("pruned_blocks", blocks).
+			Msg("blocks pruned")
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package pruner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/badger/procedure"
+	"github.com/onflow/flow-go/storage/badger/transaction"
+)
+
+// Headers is the header storage whose caches are cleared of pruned blocks.
+type Headers interface {
+	EvictTx(header *flow.Header) func(*transaction.Tx) error
+}
+
+// Pruner bounds the protocol state of access and follower nodes, which don't
+// take part in consensus, to the most recent finalized blocks. Older blocks are
+// deleted with their payloads, collections and transactions, and lookups of
+// their height return storage.ErrPruned from then on.
+//
+// The root block and the sealing segment of the latest sealed block are kept
+// regardless of the retention, as protocol state snapshots are built from them.
+type Pruner struct {
+	*Loop
+	db        *badger.DB
+	state     protocol.State
+	headers   Headers
+	retention uint64 // number of finalized blocks to keep
+}
+
+func New(
+	logger zerolog.Logger,
+	db *badger.DB,
+	state protocol.State,
+	headers Headers,
+	retention uint64,
+	batchSize uint,
+	interval time.Duration,
+) *Pruner {
+	p := &Pruner{
+		db:        db,
+		state:     state,
+		headers:   headers,
+		retention: retention,
+	}
+	p.Loop = NewLoop(logger.With().Str("component", "protocol_pruner").Logger(), state, p, batchSize, interval)
+	return p
+}
+
+// PrunedHeight returns the height up to which finalized blocks are pruned.
+func (p *Pruner) PrunedHeight() (uint64, error) {
+	var pruned uint64
+	err := p.db.View(operation.RetrieveProtocolPrunedHeight(&pruned))
+	return pruned, err
+}
+
+// InitPrunedHeight initializes the height up to which finalized blocks are pruned.
+func (p *Pruner) InitPrunedHeight(height uint64) error {
+	return operation.RetryOnConflict(p.db.Update, operation.InsertProtocolPrunedHeight(height))
+}
+
+// PruneHeight deletes the given finalized block, and evicts it from the header
+// caches once deleted.
+func (p *Pruner) PruneHeight(header *flow.Header) error {
+	root, err := p.state.Params().Root()
+	if err != nil {
+		return fmt.Errorf("could not get root block: %w", err)
+	}
+
+	return operation.RetryOnConflictTx(p.db, transaction.Update, func(tx *transaction.Tx) error {
+		err := transaction.WithTx(procedure.PruneFinalizedBlock(header, root.ID()))(tx)
+		if err != nil {
+			return err
+		}
+		return p.headers.EvictTx(header)(tx)
+	})
+}
+
+// PruneLimit returns the highest height which can be pruned. This is the
+// retention below the latest finalized block, unless the sealing segment of the
+// latest sealed block reaches further back.
+func (p *Pruner) PruneLimit() (uint64, error) {
+	final, err := p.state.Final().Head()
+	if err != nil {
+		return 0, fmt.Errorf("could not get finalized block: %w", err)
+	}
+	_, seal, err := p.state.Sealed().SealedResult()
+	if err != nil {
+		return 0, fmt.Errorf("could not get latest seal of sealed block: %w", err)
+	}
+	segmentRoot, err := p.state.AtBlockID(seal.BlockID).Head()
+	if err != nil {
+		return 0, fmt.Errorf("could not get lowest block of sealing segment: %w", err)
+	}
+
+	if final.Height <= p.retention || segmentRoot.Height == 0 {
+		return 0, nil
+	}
+	limit := final.Height - p.retention
+	if limit >= segmentRoot.Height {
+		limit = segmentRoot.Height - 1
+	}
+
+	return limit, nil
+}
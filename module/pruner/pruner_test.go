@@ -0,0 +1,55 @@
+package pruner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/module/metrics"
+	protocolmock "github.com/onflow/flow-go/state/protocol/mock"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestPruneLimit(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		final := unittest.BlockHeaderFixture()
+		segmentRoot := unittest.BlockHeaderFixture()
+
+		seal := unittest.Seal.Fixture(unittest.Seal.WithBlockID(segmentRoot.ID()))
+		sealed := new(protocolmock.Snapshot)
+		sealed.On("SealedResult").Return(unittest.ExecutionResultFixture(), seal, nil)
+		finalized := new(protocolmock.Snapshot)
+		finalized.On("Head").Return(&final, nil)
+		segment := new(protocolmock.Snapshot)
+		segment.On("Head").Return(&segmentRoot, nil)
+
+		state := new(protocolmock.State)
+		state.On("Final").Return(finalized)
+		state.On("Sealed").Return(sealed)
+		state.On("AtBlockID", segmentRoot.ID()).Return(segment)
+
+		pruner := New(zerolog.Nop(), db, state, bstorage.NewHeaders(metrics.NewNoopCollector(), db), 100, DefaultBatchSize, time.Hour)
+
+		check := func(finalHeight, segmentRootHeight, expected uint64) {
+			final.Height = finalHeight
+			segmentRoot.Height = segmentRootHeight
+			limit, err := pruner.PruneLimit()
+			require.NoError(t, err)
+			assert.Equal(t, expected, limit)
+		}
+
+		// limited by the retention
+		check(1000, 950, 900)
+		// limited by the sealing segment
+		check(1000, 850, 849)
+		// nothing to prune within the retention
+		check(100, 50, 0)
+		// the root block is never pruned
+		check(1000, 0, 0)
+	})
+}
@@ -113,6 +113,7 @@ const (
 	EXEPersistStateCommitment             SpanName = "exe.state.persistStateCommitment"
 	EXEPersistEvents                      SpanName = "exe.state.persistEvents"
 	EXEPersistChunkDataPack               SpanName = "exe.state.persistChunkDataPack"
+	EXEPersistRegisterHistory             SpanName = "exe.state.persistRegisterHistory"
 	EXEGetExecutionResultID               SpanName = "exe.state.getExecutionResultID"
 	EXEPersistExecutionResult             SpanName = "exe.state.persistExecutionResult"
 	EXEUpdateHighestExecutedBlockIfHigher SpanName = "exe.state.updateHighestExecutedBlockIfHigher"
@@ -162,6 +163,7 @@ const (
 	VERVerVerifyWithMetrics       SpanName = "ver.verify.verifyWithMetrics"
 	VERVerChunkVerify             SpanName = "ver.verify.ChunkVerifier.Verify"
 	VERVerGenerateResultApproval  SpanName = "ver.verify.GenerateResultApproval"
+	VERVerGenerateChunkChallenge  SpanName = "ver.verify.GenerateChunkChallenge"
 
 	// Flow Virtual Machine
 	FVMVerifyTransaction             SpanName = "fvm.verifyTransaction"
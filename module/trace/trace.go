@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
@@ -28,6 +29,7 @@ type OpenTracer struct {
 	log       zerolog.Logger
 	openSpans map[string]opentracing.Span
 	lock      sync.RWMutex
+	disabled  uint32 // whether recording of new spans is paused, 1 if it is
 }
 
 type traceLogger struct {
@@ -88,12 +90,35 @@ func (t *OpenTracer) Done() <-chan struct{} {
 	return done
 }
 
+// SetEnabled resumes or pauses recording new spans. While paused, spans are started
+// with a noop tracer and are not reported.
+func (t *OpenTracer) SetEnabled(enabled bool) {
+	var disabled uint32
+	if !enabled {
+		disabled = 1
+	}
+	atomic.StoreUint32(&t.disabled, disabled)
+}
+
+// Enabled returns whether the tracer is recording new spans.
+func (t *OpenTracer) Enabled() bool {
+	return atomic.LoadUint32(&t.disabled) == 0
+}
+
+// tracer returns the tracer new spans are started with.
+func (t *OpenTracer) tracer() opentracing.Tracer {
+	if !t.Enabled() {
+		return opentracing.NoopTracer{}
+	}
+	return t.Tracer
+}
+
 // StartSpan starts a span using the flow identifier as a key into the span map
 func (t *OpenTracer) StartSpan(entityID flow.Identifier, spanName SpanName, opts ...opentracing.StartSpanOption) opentracing.Span {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 	key := spanKey(entityID, spanName)
-	t.openSpans[key] = t.Tracer.StartSpan(string(spanName), opts...)
+	t.openSpans[key] = t.tracer().StartSpan(string(spanName), opts...)
 	return t.openSpans[key]
 }
 
@@ -105,8 +130,10 @@ func (t *OpenTracer) FinishSpan(entityID flow.Identifier, spanName SpanName) {
 	span, ok := t.openSpans[key]
 	if ok {
 		span.Finish()
-		jaegerSpan := span.(*jaeger.Span)
-		spanDurationMetric.WithLabelValues(jaegerSpan.OperationName()).Observe(jaegerSpan.Duration().Seconds())
+		// spans started while the tracer is paused are not recorded
+		if jaegerSpan, ok := span.(*jaeger.Span); ok {
+			spanDurationMetric.WithLabelValues(jaegerSpan.OperationName()).Observe(jaegerSpan.Duration().Seconds())
+		}
 		delete(t.openSpans, key)
 	}
 }
@@ -125,7 +152,7 @@ func (t *OpenTracer) StartSpanFromContext(
 	operationName SpanName,
 	opts ...opentracing.StartSpanOption,
 ) (opentracing.Span, context.Context) {
-	return opentracing.StartSpanFromContextWithTracer(ctx, t.Tracer, string(operationName), opts...)
+	return opentracing.StartSpanFromContextWithTracer(ctx, t.tracer(), string(operationName), opts...)
 }
 
 func (t *OpenTracer) StartSpanFromParent(
@@ -134,7 +161,7 @@ func (t *OpenTracer) StartSpanFromParent(
 	opts ...opentracing.StartSpanOption,
 ) opentracing.Span {
 	opts = append(opts, opentracing.FollowsFrom(span.Context()))
-	return t.Tracer.StartSpan(string(operationName), opts...)
+	return t.tracer().StartSpan(string(operationName), opts...)
 }
 
 func (t *OpenTracer) RecordSpanFromParent(
@@ -150,7 +177,7 @@ func (t *OpenTracer) RecordSpanFromParent(
 	start := end.Add(-duration)
 	opts = append(opts, opentracing.FollowsFrom(span.Context()))
 	opts = append(opts, opentracing.StartTime(start))
-	sp := t.Tracer.StartSpan(string(operationName), opts...)
+	sp := t.tracer().StartSpan(string(operationName), opts...)
 	sp.FinishWithOptions(opentracing.FinishOptions{FinishTime: end, LogRecords: logs})
 }
 
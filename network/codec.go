@@ -14,6 +14,14 @@ type Codec interface {
 	Decode(data []byte) (interface{}, error)
 }
 
+// SizeLimitedCodec is a codec whose decoded payloads can be much larger than the
+// encoded ones, such as a compressing codec. It decodes payloads up to a maximum
+// size given by the caller, as the size of the encoded payload doesn't bound it.
+type SizeLimitedCodec interface {
+	Codec
+	DecodeWithLimit(data []byte, maxSize int) (interface{}, error)
+}
+
 // Encoder encodes the given message into the underlying writer.
 type Encoder interface {
 	Encode(v interface{}) error
@@ -87,6 +87,24 @@ func switchv2code(v interface{}) (uint8, error) {
 	case *messages.DKGMessage:
 		code = CodeDKGMessage
 
+	// chunk challenges
+	case *flow.ChunkChallenge:
+		code = CodeChunkChallenge
+
+	case *messages.TimeoutObject:
+		code = CodeTimeoutObject
+	case *messages.ClusterTimeoutObject:
+		code = CodeClusterTimeoutObject
+
+	case *messages.CheckpointChunkRequest:
+		code = CodeCheckpointChunkRequest
+	case *messages.CheckpointChunkResponse:
+		code = CodeCheckpointChunkResponse
+	case *messages.CheckpointStatesRequest:
+		code = CodeCheckpointStatesRequest
+	case *messages.CheckpointStatesResponse:
+		code = CodeCheckpointStatesResponse
+
 	default:
 		return 0, errors.Errorf("invalid encode type (%T)", v)
 	}
@@ -171,6 +189,24 @@ func switchv2what(v interface{}) (string, error) {
 	case *messages.DKGMessage:
 		what = "CodeDKGMessage"
 
+	// chunk challenges
+	case *flow.ChunkChallenge:
+		what = "CodeChunkChallenge"
+
+	case *messages.TimeoutObject:
+		what = "CodeTimeoutObject"
+	case *messages.ClusterTimeoutObject:
+		what = "CodeClusterTimeoutObject"
+
+	case *messages.CheckpointChunkRequest:
+		what = "CodeCheckpointChunkRequest"
+	case *messages.CheckpointChunkResponse:
+		what = "CodeCheckpointChunkResponse"
+	case *messages.CheckpointStatesRequest:
+		what = "CodeCheckpointStatesRequest"
+	case *messages.CheckpointStatesResponse:
+		what = "CodeCheckpointStatesResponse"
+
 	default:
 		return "", errors.Errorf("invalid encode type (%T)", v)
 	}
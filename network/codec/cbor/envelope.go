@@ -52,5 +52,18 @@ const (
 	// DKG
 	CodeDKGMessage
 
+	// chunk challenges
+	CodeChunkChallenge
+
+	// timeouts for view synchronization
+	CodeTimeoutObject
+	CodeClusterTimeoutObject
+
+	// execution state checkpoints
+	CodeCheckpointChunkRequest
+	CodeCheckpointChunkResponse
+	CodeCheckpointStatesRequest
+	CodeCheckpointStatesResponse
+
 	CodeMax
 )
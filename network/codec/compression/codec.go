@@ -0,0 +1,137 @@
+package compression
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network"
+)
+
+// DefaultThreshold is the default size in bytes from which payloads are compressed.
+// Smaller payloads, such as votes, barely compress and are not worth the overhead.
+const DefaultThreshold = 16 * 1024 // 16 kb
+
+// Codec wraps a codec of our network, and compresses the encoded payloads above a
+// size threshold.
+//
+// Compressed payloads are prefixed with the code of their compressor, which tells
+// the decoding node how to decompress them. Payloads without such a prefix are
+// passed to the inner codec as they are, so nodes decode payloads of nodes which do
+// not compress, as well as payloads compressed by any supported compressor. Hence,
+// compression can be enabled once all nodes of the network decode compressed payloads.
+//
+// Streams created by NewEncoder and NewDecoder are not compressed.
+type Codec struct {
+	codec      network.Codec
+	metrics    module.NetworkMetrics
+	compressor Compressor // compressor of encoded payloads, nil if compression is disabled
+	threshold  int
+	byCode     map[byte]Compressor
+}
+
+var _ network.SizeLimitedCodec = (*Codec)(nil)
+
+// Option is a function which configures the codec.
+type Option func(*Codec)
+
+// WithCompressor sets the compressor of encoded payloads. By default, payloads are
+// not compressed.
+func WithCompressor(compressor Compressor) Option {
+	return func(c *Codec) {
+		c.compressor = compressor
+	}
+}
+
+// WithThreshold sets the size in bytes from which encoded payloads are compressed.
+func WithThreshold(threshold int) Option {
+	return func(c *Codec) {
+		c.threshold = threshold
+	}
+}
+
+// NewCodec creates a new compression codec wrapping the given codec.
+func NewCodec(codec network.Codec, metrics module.NetworkMetrics, opts ...Option) *Codec {
+	c := &Codec{
+		codec:     codec,
+		metrics:   metrics,
+		threshold: DefaultThreshold,
+		byCode:    make(map[byte]Compressor, len(compressors)),
+	}
+	for _, compressor := range compressors {
+		c.byCode[compressor.Code()] = compressor
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// NewEncoder creates a new encoder of the inner codec with the given underlying writer.
+func (c *Codec) NewEncoder(w io.Writer) network.Encoder {
+	return c.codec.NewEncoder(w)
+}
+
+// NewDecoder creates a new decoder of the inner codec with the given underlying reader.
+func (c *Codec) NewDecoder(r io.Reader) network.Decoder {
+	return c.codec.NewDecoder(r)
+}
+
+// Encode encodes the given value with the inner codec, and compresses the payload if
+// it reaches the threshold. The payload is sent uncompressed if compression does not
+// reduce its size.
+func (c *Codec) Encode(v interface{}) ([]byte, error) {
+	data, err := c.codec.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.compressor == nil || len(data) < c.threshold {
+		return data, nil
+	}
+
+	compressed, err := c.compressor.Compress(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not compress payload with %s: %w", c.compressor.Name(), err)
+	}
+	if len(compressed)+1 >= len(data) {
+		return data, nil
+	}
+
+	c.metrics.PayloadCompressed(c.compressor.Name(), len(data), len(compressed)+1)
+
+	return append([]byte{c.compressor.Code()}, compressed...), nil
+}
+
+// Decode decompresses the given payload if it is prefixed with the code of a
+// compressor, and decodes it with the inner codec. Payloads decompressing to more
+// than DefaultMaxDecompressedSize are rejected.
+func (c *Codec) Decode(data []byte) (interface{}, error) {
+	return c.DecodeWithLimit(data, DefaultMaxDecompressedSize)
+}
+
+// DecodeWithLimit decompresses the given payload if it is prefixed with the code
+// of a compressor, and decodes it with the inner codec. Payloads decompressing to
+// more than the given maximum size are rejected.
+func (c *Codec) DecodeWithLimit(data []byte, maxSize int) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty payload")
+	}
+
+	compressor, ok := c.byCode[data[0]]
+	if !ok {
+		return c.codec.Decode(data)
+	}
+
+	decompressed, err := compressor.Decompress(data[1:], maxSize)
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress payload with %s: %w", compressor.Name(), err)
+	}
+	if len(decompressed) == 0 {
+		return nil, fmt.Errorf("empty decompressed payload")
+	}
+
+	return c.codec.Decode(decompressed)
+}
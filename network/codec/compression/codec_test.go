@@ -0,0 +1,134 @@
+package compression
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/module/metrics"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func entityResponse(blobSize int) *messages.EntityResponse {
+	return &messages.EntityResponse{
+		Nonce:     1,
+		EntityIDs: unittest.IdentifierListFixture(1),
+		Blobs:     [][]byte{bytes.Repeat([]byte("flow"), blobSize/4)},
+	}
+}
+
+// TestCodec_RoundTrip tests that payloads compressed by each compressor decode to the
+// original value, and are smaller than the uncompressed payload.
+func TestCodec_RoundTrip(t *testing.T) {
+	response := entityResponse(64 * 1024)
+	uncompressed, err := cbor.NewCodec().Encode(response)
+	require.NoError(t, err)
+
+	for _, compressor := range compressors {
+		t.Run(compressor.Name(), func(t *testing.T) {
+			collector := new(mockmodule.NetworkMetrics)
+			collector.On("PayloadCompressed", compressor.Name(), len(uncompressed), mock.Anything).Once()
+			codec := NewCodec(cbor.NewCodec(), collector, WithCompressor(compressor))
+
+			data, err := codec.Encode(response)
+			require.NoError(t, err)
+			assert.Equal(t, compressor.Code(), data[0])
+			assert.Less(t, len(data), len(uncompressed))
+			collector.AssertExpectations(t)
+
+			decoded, err := codec.Decode(data)
+			require.NoError(t, err)
+			assert.Equal(t, response, decoded)
+		})
+	}
+}
+
+// TestCodec_Threshold tests that payloads below the threshold are not compressed.
+func TestCodec_Threshold(t *testing.T) {
+	codec := NewCodec(cbor.NewCodec(), metrics.NewNoopCollector(), WithCompressor(SnappyCompressor{}), WithThreshold(1024))
+
+	response := entityResponse(512)
+	uncompressed, err := cbor.NewCodec().Encode(response)
+	require.NoError(t, err)
+
+	data, err := codec.Encode(response)
+	require.NoError(t, err)
+	assert.Equal(t, uncompressed, data)
+}
+
+// TestCodec_BackwardCompatible tests that payloads of nodes which do not compress are
+// decoded, and that payloads compressed with any compressor are decoded by nodes
+// which do not compress.
+func TestCodec_BackwardCompatible(t *testing.T) {
+	response := entityResponse(64 * 1024)
+	plain := NewCodec(cbor.NewCodec(), metrics.NewNoopCollector())
+
+	uncompressed, err := cbor.NewCodec().Encode(response)
+	require.NoError(t, err)
+	decoded, err := plain.Decode(uncompressed)
+	require.NoError(t, err)
+	assert.Equal(t, response, decoded)
+
+	data, err := plain.Encode(response)
+	require.NoError(t, err)
+	assert.Equal(t, uncompressed, data)
+
+	for _, compressor := range compressors {
+		compressing := NewCodec(cbor.NewCodec(), metrics.NewNoopCollector(), WithCompressor(compressor))
+		data, err := compressing.Encode(response)
+		require.NoError(t, err)
+
+		decoded, err := plain.Decode(data)
+		require.NoError(t, err)
+		assert.Equal(t, response, decoded)
+	}
+}
+
+// TestCodec_InvalidPayload tests that corrupted compressed payloads are rejected.
+func TestCodec_InvalidPayload(t *testing.T) {
+	codec := NewCodec(cbor.NewCodec(), metrics.NewNoopCollector())
+
+	_, err := codec.Decode(nil)
+	assert.Error(t, err)
+
+	for _, compressor := range compressors {
+		_, err := codec.Decode([]byte{compressor.Code(), 0xff, 0xff, 0xff, 0xff, 0xff})
+		assert.Error(t, err)
+	}
+}
+
+// TestCodec_MaxDecompressedSize tests that payloads decompressing to more than the
+// maximum size are rejected, and that snappy rejects them before allocating the
+// decompressed payload.
+func TestCodec_MaxDecompressedSize(t *testing.T) {
+	response := entityResponse(64 * 1024)
+	uncompressed, err := cbor.NewCodec().Encode(response)
+	require.NoError(t, err)
+
+	for _, compressor := range compressors {
+		t.Run(compressor.Name(), func(t *testing.T) {
+			codec := NewCodec(cbor.NewCodec(), metrics.NewNoopCollector(), WithCompressor(compressor))
+			data, err := codec.Encode(response)
+			require.NoError(t, err)
+
+			_, err = codec.DecodeWithLimit(data, len(uncompressed)-1)
+			assert.Error(t, err)
+
+			decoded, err := codec.DecodeWithLimit(data, len(uncompressed))
+			require.NoError(t, err)
+			assert.Equal(t, response, decoded)
+		})
+	}
+
+	// a snappy payload of a few bytes declaring a decompressed size of 1 gb
+	bomb := []byte{CodeSnappy, 0x80, 0x80, 0x80, 0x80, 0x04}
+	_, err = NewCodec(cbor.NewCodec(), metrics.NewNoopCollector()).Decode(bomb)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds maximum size")
+}
@@ -0,0 +1,127 @@
+package compression
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/golang/snappy"
+)
+
+// The codes prepended to compressed payloads. Inner codecs never start their payloads
+// with these bytes: the CBOR codec starts with its envelope code, which is far lower,
+// and the JSON codec starts with the opening brace of its envelope.
+const (
+	CodeSnappy  byte = 0xf1
+	CodeDeflate byte = 0xf2
+)
+
+// DefaultMaxDecompressedSize is the maximum size of a decompressed payload, unless
+// a larger one is allowed by the caller. It matches the maximum size of most unicast
+// messages (p2p.DefaultMaxUnicastMsgSize), which is larger than the maximum size of
+// pubsub messages, and protects against decompression bombs.
+const DefaultMaxDecompressedSize = 10 << 20 // 10 mb
+
+// Compressor is a compression algorithm for network payloads.
+type Compressor interface {
+	// Code returns the code prepended to payloads compressed by the compressor.
+	Code() byte
+	// Name returns the name of the algorithm, used for instrumentation.
+	Name() string
+	// Compress returns the compressed data.
+	Compress(data []byte) ([]byte, error)
+	// Decompress returns the decompressed data. It fails without allocating the
+	// decompressed data if it exceeds the given maximum size.
+	Decompress(data []byte, maxSize int) ([]byte, error)
+}
+
+// SnappyCompressor compresses payloads with snappy, which favours speed over
+// compression ratio.
+type SnappyCompressor struct{}
+
+func (SnappyCompressor) Code() byte {
+	return CodeSnappy
+}
+
+func (SnappyCompressor) Name() string {
+	return "snappy"
+}
+
+func (SnappyCompressor) Compress(data []byte) ([]byte, error) {
+	return snappy.Encode(nil, data), nil
+}
+
+func (SnappyCompressor) Decompress(data []byte, maxSize int) ([]byte, error) {
+	// snappy allocates the decompressed size declared in the header of the payload
+	size, err := snappy.DecodedLen(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not read decompressed size: %w", err)
+	}
+	if size > maxSize {
+		return nil, fmt.Errorf("decompressed size %d exceeds maximum size %d", size, maxSize)
+	}
+	return snappy.Decode(nil, data)
+}
+
+// DeflateCompressor compresses payloads with deflate, which favours compression
+// ratio over speed.
+type DeflateCompressor struct{}
+
+func (DeflateCompressor) Code() byte {
+	return CodeDeflate
+}
+
+func (DeflateCompressor) Name() string {
+	return "deflate"
+}
+
+func (DeflateCompressor) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, fmt.Errorf("could not create deflate writer: %w", err)
+	}
+	_, err = w.Write(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not compress data: %w", err)
+	}
+	err = w.Close()
+	if err != nil {
+		return nil, fmt.Errorf("could not flush compressed data: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (DeflateCompressor) Decompress(data []byte, maxSize int) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+
+	// read one byte more than the maximum to detect oversized payloads
+	decompressed, err := ioutil.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return nil, fmt.Errorf("could not decompress data: %w", err)
+	}
+	if len(decompressed) > maxSize {
+		return nil, fmt.Errorf("decompressed size exceeds maximum size %d", maxSize)
+	}
+	return decompressed, nil
+}
+
+// CompressorByName returns the compressor with the given name.
+func CompressorByName(name string) (Compressor, error) {
+	for _, compressor := range compressors {
+		if compressor.Name() == name {
+			return compressor, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown compressor: %s", name)
+}
+
+// compressors are all the supported compressors. Payloads compressed by any of them
+// can be decoded, regardless of the compressor used for encoding.
+var compressors = []Compressor{
+	SnappyCompressor{},
+	DeflateCompressor{},
+}
@@ -90,6 +90,24 @@ func switchenv2v(env Envelope) (interface{}, error) {
 	case CodeDKGMessage:
 		v = &messages.DKGMessage{}
 
+	// chunk challenges
+	case CodeChunkChallenge:
+		v = &flow.ChunkChallenge{}
+
+	case CodeTimeoutObject:
+		v = &messages.TimeoutObject{}
+	case CodeClusterTimeoutObject:
+		v = &messages.ClusterTimeoutObject{}
+
+	case CodeCheckpointChunkRequest:
+		v = &messages.CheckpointChunkRequest{}
+	case CodeCheckpointChunkResponse:
+		v = &messages.CheckpointChunkResponse{}
+	case CodeCheckpointStatesRequest:
+		v = &messages.CheckpointStatesRequest{}
+	case CodeCheckpointStatesResponse:
+		v = &messages.CheckpointStatesResponse{}
+
 	default:
 		return nil, errors.Errorf("invalid message code (%d)", env.Code)
 	}
@@ -173,6 +191,24 @@ func switchenv2what(env Envelope) (string, error) {
 	case CodeDKGMessage:
 		what = "CodeDKGMessage"
 
+	// chunk challenges
+	case CodeChunkChallenge:
+		what = "CodeChunkChallenge"
+
+	case CodeTimeoutObject:
+		what = "CodeTimeoutObject"
+	case CodeClusterTimeoutObject:
+		what = "CodeClusterTimeoutObject"
+
+	case CodeCheckpointChunkRequest:
+		what = "CodeCheckpointChunkRequest"
+	case CodeCheckpointChunkResponse:
+		what = "CodeCheckpointChunkResponse"
+	case CodeCheckpointStatesRequest:
+		what = "CodeCheckpointStatesRequest"
+	case CodeCheckpointStatesResponse:
+		what = "CodeCheckpointStatesResponse"
+
 	default:
 		return "", errors.Errorf("invalid message code (%d)", env.Code)
 	}
@@ -54,6 +54,19 @@ const (
 
 	// DKG
 	CodeDKGMessage
+
+	// chunk challenges
+	CodeChunkChallenge
+
+	// timeouts for view synchronization
+	CodeTimeoutObject
+	CodeClusterTimeoutObject
+
+	// execution state checkpoints
+	CodeCheckpointChunkRequest
+	CodeCheckpointChunkResponse
+	CodeCheckpointStatesRequest
+	CodeCheckpointStatesResponse
 )
 
 // Envelope is a wrapper to convey type information with JSON encoding without
@@ -81,6 +81,12 @@ type Conduit interface {
 	// The recipients are selected randomly from the targetIDs.
 	Multicast(event interface{}, num uint, targetIDs ...flow.Identifier) error
 
+	// ReportInvalidInput reports that the node with the given ID sent an invalid
+	// message on the channel of this conduit, so that the node is penalized. The
+	// network layer only sees the errors of messages processed synchronously,
+	// engines which queue messages report invalid input once they process them.
+	ReportInvalidInput(originID flow.Identifier)
+
 	// Close unsubscribes from the channels of this conduit. After calling close,
 	// the conduit can no longer be used to send a message.
 	Close() error
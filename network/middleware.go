@@ -58,6 +58,11 @@ type Middleware interface {
 	// UpdateNodeAddresses fetches and updates the addresses of all the staked participants
 	// in the Flow protocol.
 	UpdateNodeAddresses()
+
+	// ReportMisbehaviour reports a misbehaviour of the node with the given ID, which
+	// is detected after the message is delivered to the overlay, such as an undecodable
+	// payload or a message rejected by an engine.
+	ReportMisbehaviour(nodeID flow.Identifier, misbehaviour Misbehaviour)
 }
 
 // Overlay represents the interface that middleware uses to interact with the
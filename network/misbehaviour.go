@@ -0,0 +1,23 @@
+package network
+
+// Misbehaviour is a kind of misbehaviour of a remote node on the network, which
+// is penalized by the middleware.
+type Misbehaviour string
+
+const (
+	// MisbehaviourRateLimitExceeded is committed by sending more messages on a
+	// channel than the rate limit of the channel permits.
+	MisbehaviourRateLimitExceeded Misbehaviour = "rate_limit_exceeded"
+	// MisbehaviourSpoofedOrigin is committed by sending a message which claims
+	// to originate from another node.
+	MisbehaviourSpoofedOrigin Misbehaviour = "spoofed_origin"
+	// MisbehaviourInvalidMessage is committed by sending a message which is
+	// rejected by a message validator.
+	MisbehaviourInvalidMessage Misbehaviour = "invalid_message"
+	// MisbehaviourUndecodableMessage is committed by sending a message with a
+	// payload which can not be decoded.
+	MisbehaviourUndecodableMessage Misbehaviour = "undecodable_message"
+	// MisbehaviourInvalidInput is committed by sending a message which is
+	// rejected by the receiving engine as invalid input.
+	MisbehaviourInvalidInput Misbehaviour = "invalid_input"
+)
@@ -68,6 +68,11 @@ func (_m *Conduit) Publish(event interface{}, targetIDs ...flow.Identifier) erro
 	return r0
 }
 
+// ReportInvalidInput provides a mock function with given fields: originID
+func (_m *Conduit) ReportInvalidInput(originID flow.Identifier) {
+	_m.Called(originID)
+}
+
 // Unicast provides a mock function with given fields: event, targetID
 func (_m *Conduit) Unicast(event interface{}, targetID flow.Identifier) error {
 	ret := _m.Called(event, targetID)
@@ -60,6 +60,11 @@ func (_m *Middleware) Publish(msg *message.Message, channel network.Channel) err
 	return r0
 }
 
+// ReportMisbehaviour provides a mock function with given fields: nodeID, misbehaviour
+func (_m *Middleware) ReportMisbehaviour(nodeID flow.Identifier, misbehaviour network.Misbehaviour) {
+	_m.Called(nodeID, misbehaviour)
+}
+
 // SendDirect provides a mock function with given fields: msg, targetID
 func (_m *Middleware) SendDirect(msg *message.Message, targetID flow.Identifier) error {
 	ret := _m.Called(msg, targetID)
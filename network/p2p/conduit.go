@@ -20,6 +20,10 @@ type UnicastFunc func(channel network.Channel, event interface{}, targetID flow.
 // network to randomly chosen subset of nodes from targetIDs
 type MulticastFunc func(channel network.Channel, event interface{}, num uint, targetIDs ...flow.Identifier) error
 
+// ReportFunc is a function that penalizes the node with the given ID for a
+// misbehaviour on the network
+type ReportFunc func(originID flow.Identifier, misbehaviour network.Misbehaviour)
+
 // CloseFunc is a function that unsubscribes the conduit from the channel
 type CloseFunc func(channel network.Channel) error
 
@@ -33,6 +37,7 @@ type Conduit struct {
 	publish   PublishFunc
 	unicast   UnicastFunc
 	multicast MulticastFunc
+	report    ReportFunc
 	close     CloseFunc
 }
 
@@ -66,6 +71,11 @@ func (c *Conduit) Multicast(event interface{}, num uint, targetIDs ...flow.Ident
 	return c.multicast(c.channel, event, num, targetIDs...)
 }
 
+// ReportInvalidInput penalizes the given node for sending an invalid message.
+func (c *Conduit) ReportInvalidInput(originID flow.Identifier) {
+	c.report(originID, network.MisbehaviourInvalidInput)
+}
+
 func (c *Conduit) Close() error {
 	if c.ctx.Err() != nil {
 		return fmt.Errorf("conduit for channel %s already closed", c.channel)
@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"sync"
+	"time"
 
 	"github.com/libp2p/go-libp2p-core/connmgr"
 	"github.com/libp2p/go-libp2p-core/control"
@@ -14,16 +15,19 @@ import (
 var _ connmgr.ConnectionGater = (*ConnGater)(nil)
 
 // ConnGater is the implementation of the libp2p connmgr.ConnectionGater interface
-// It provides node allowlisting by libp2p peer.ID which is derived from the node public networking key
+// It provides node allowlisting by libp2p peer.ID which is derived from the node public networking key.
+// Peers on the allowlist can be temporarily blocklisted, e.g. due to misbehaviour.
 type ConnGater struct {
 	sync.RWMutex
-	peerIDAllowlist map[peer.ID]struct{} // the in-memory map of approved peer IDs
+	peerIDAllowlist map[peer.ID]struct{}  // the in-memory map of approved peer IDs
+	peerIDBlocklist map[peer.ID]time.Time // the in-memory map of blocked peer IDs to the expiry of their block
 	log             zerolog.Logger
 }
 
 func NewConnGater(log zerolog.Logger) *ConnGater {
 	cg := &ConnGater{
-		log: log,
+		peerIDBlocklist: make(map[peer.ID]time.Time),
+		log:             log,
 	}
 	return cg
 }
@@ -47,6 +51,18 @@ func (c *ConnGater) update(pids peer.IDSlice) {
 	c.log.Info().Msg("approved list of peers updated")
 }
 
+// blocklist blocks all connections with the peer until the given time, regardless of the allowlist
+func (c *ConnGater) blocklist(pid peer.ID, until time.Time) {
+	c.Lock()
+	c.peerIDBlocklist[pid] = until
+	c.Unlock()
+
+	c.log.Warn().
+		Str("peer_id", pid.Pretty()).
+		Time("until", until).
+		Msg("peer blocklisted")
+}
+
 // InterceptPeerDial - a callback which allows or disallows outbound connection
 func (c *ConnGater) InterceptPeerDial(p peer.ID) bool {
 	return c.validPeerID(p)
@@ -90,7 +106,21 @@ func (c *ConnGater) InterceptUpgraded(network.Conn) (allow bool, reason control.
 
 func (c *ConnGater) validPeerID(p peer.ID) bool {
 	c.RLock()
-	defer c.RUnlock()
+	until, blocked := c.peerIDBlocklist[p]
 	_, ok := c.peerIDAllowlist[p]
+	c.RUnlock()
+
+	if blocked {
+		if time.Now().Before(until) {
+			return false
+		}
+		// the block has expired
+		c.Lock()
+		if c.peerIDBlocklist[p] == until {
+			delete(c.peerIDBlocklist, p)
+		}
+		c.Unlock()
+	}
+
 	return ok
 }
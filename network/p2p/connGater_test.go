@@ -0,0 +1,27 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestConnGaterBlocklist tests that a blocklisted peer is rejected until its block expires,
+// even if it is on the allowlist.
+func TestConnGaterBlocklist(t *testing.T) {
+	connGater := NewConnGater(zerolog.Nop())
+	allowed := peer.ID("allowed")
+	blocked := peer.ID("blocked")
+	connGater.update(peer.IDSlice{allowed, blocked})
+
+	connGater.blocklist(blocked, time.Now().Add(time.Hour))
+	assert.True(t, connGater.InterceptPeerDial(allowed))
+	assert.False(t, connGater.InterceptPeerDial(blocked))
+
+	// the peer is allowed again once the block has expired
+	connGater.blocklist(blocked, time.Now().Add(-time.Second))
+	assert.True(t, connGater.InterceptPeerDial(blocked))
+}
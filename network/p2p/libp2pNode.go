@@ -611,6 +611,21 @@ func (n *Node) UpdateAllowList(peers peer.IDSlice) {
 	n.connGater.update(peers)
 }
 
+// BlocklistPeer disconnects from the peer and rejects any connection with it for the given duration.
+func (n *Node) BlocklistPeer(peerID peer.ID, duration time.Duration) error {
+	if n.connGater == nil {
+		n.logger.Debug().Hex("node_id", logging.ID(n.id)).Msg("skipping blocklisting of peer, connection gating is not enabled")
+	} else {
+		n.connGater.blocklist(peerID, time.Now().Add(duration))
+	}
+
+	err := n.host.Network().ClosePeer(peerID)
+	if err != nil {
+		return fmt.Errorf("failed to disconnect blocklisted peer %s: %w", peerID, err)
+	}
+	return nil
+}
+
 // Host returns pointer to host object of node.
 func (n *Node) Host() host.Host {
 	return n.host
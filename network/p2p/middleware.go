@@ -84,6 +84,8 @@ type Middleware struct {
 	idProvider                 id.IdentifierProvider
 	previousProtocolStatePeers []peer.AddrInfo
 	stakedTopicValidator       *StakedValidator
+	rateLimiter                *RateLimiter
+	peerScorer                 *PeerScorer
 }
 
 type MiddlewareOption func(*Middleware)
@@ -106,6 +108,21 @@ func WithPeerManager(peerManagerFunc PeerManagerFactoryFunc) MiddlewareOption {
 	}
 }
 
+// WithRateLimiter enables rate limiting of the inbound messages of each peer on each channel.
+func WithRateLimiter(config RateLimiterConfig) MiddlewareOption {
+	return func(mw *Middleware) {
+		mw.rateLimiter = NewRateLimiter(config)
+	}
+}
+
+// WithPeerScoring enables misbehaviour scoring of peers, disconnecting and blocklisting
+// peers whose score crosses the configured thresholds.
+func WithPeerScoring(config PeerScoringConfig) MiddlewareOption {
+	return func(mw *Middleware) {
+		mw.peerScorer = NewPeerScorer(config)
+	}
+}
+
 // NewMiddleware creates a new middleware instance
 // libP2PNodeFactory is the factory used to create a LibP2PNode
 // flowID is this node's Flow ID
@@ -378,7 +395,7 @@ func (m *Middleware) handleIncomingStream(s libp2pnetwork.Stream) {
 	_, isStaked := m.ov.Identities().ByNodeID(nodeID)
 
 	//create a new readConnection with the context of the middleware
-	conn := newReadConnection(m.ctx, s, m.processAuthenticatedMessage, log, m.metrics, LargeMsgMaxUnicastMsgSize, isStaked)
+	conn := newReadConnection(m.ctx, s, m.unicastCallback, log, m.metrics, LargeMsgMaxUnicastMsgSize, isStaked)
 
 	// kick off the receive loop to continuously receive messages
 	m.wg.Add(1)
@@ -402,7 +419,7 @@ func (m *Middleware) Subscribe(channel network.Channel) error {
 	}
 
 	// create a new readSubscription with the context of the middleware
-	rs := newReadSubscription(m.ctx, s, m.processAuthenticatedMessage, m.log, m.metrics)
+	rs := newReadSubscription(m.ctx, s, m.pubsubCallback, m.log, m.metrics)
 	m.wg.Add(1)
 
 	// kick off the receive loop to continuously receive messages
@@ -432,7 +449,7 @@ func (m *Middleware) Unsubscribe(channel network.Channel) error {
 // In particular, it checks the claim of protocol authorship situated in the message against `peerID`
 // The assumption is that the message has been authenticated at the network level (libp2p) to originate from the peer with ID `peerID`
 // this requirement is fulfilled by e.g. the output of readConnection and readSubscription
-func (m *Middleware) processAuthenticatedMessage(msg *message.Message, peerID peer.ID) {
+func (m *Middleware) processAuthenticatedMessage(msg *message.Message, peerID peer.ID, mode communicationMode) {
 	flowID, err := m.idTranslator.GetFlowID(peerID)
 	if err != nil {
 		m.log.Warn().Err(err).Msgf("received message from unknown peer %v, and was dropped", peerID.String())
@@ -444,20 +461,47 @@ func (m *Middleware) processAuthenticatedMessage(msg *message.Message, peerID pe
 
 	if flowID != originID {
 		m.log.Warn().Msgf("received message claiming to be from nodeID %v was actually from %v and dropped", originID, flowID)
+		m.penalize(peerID, network.MisbehaviourSpoofedOrigin)
+		return
+	}
+
+	if m.rateLimiter != nil && !m.rateLimiter.Allow(peerID, network.Channel(msg.ChannelID)) {
+		m.log.Debug().
+			Hex("origin_id", msg.OriginID).
+			Str("channel", msg.ChannelID).
+			Msg("rate limit exceeded, message dropped")
+		m.metrics.InboundMessageRateLimited(peerID.String(), msg.ChannelID)
+		m.penalize(peerID, network.MisbehaviourRateLimitExceeded)
 		return
 	}
 
-	m.processMessage(msg)
+	// pubsub delivers messages to all subscribers of a topic, including the ones which are
+	// not targeted, and back to the sender. Hence, only rejected unicast messages are
+	// considered a misbehaviour of the peer.
+	if !m.processMessage(msg) && mode == OneToOne {
+		m.penalize(peerID, network.MisbehaviourInvalidMessage)
+	}
+}
+
+// unicastCallback processes a message received on a unicast stream from the given peer.
+func (m *Middleware) unicastCallback(msg *message.Message, peerID peer.ID) {
+	m.processAuthenticatedMessage(msg, peerID, OneToOne)
+}
+
+// pubsubCallback processes a message published by the given peer.
+func (m *Middleware) pubsubCallback(msg *message.Message, peerID peer.ID) {
+	m.processAuthenticatedMessage(msg, peerID, OneToK)
 }
 
-// processMessage processes a message and eventually passes it to the overlay
-func (m *Middleware) processMessage(msg *message.Message) {
+// processMessage processes a message and eventually passes it to the overlay.
+// It returns false if the message is rejected by any of the message validators.
+func (m *Middleware) processMessage(msg *message.Message) bool {
 
 	// run through all the message validators
 	for _, v := range m.validators {
 		// if any one fails, stop message propagation
 		if !v.Validate(*msg) {
-			return
+			return false
 		}
 	}
 
@@ -466,6 +510,64 @@ func (m *Middleware) processMessage(msg *message.Message) {
 	if err != nil {
 		m.log.Error().Err(err).Msg("could not deliver payload")
 	}
+
+	return true
+}
+
+// ReportMisbehaviour penalizes the node with the given ID for a misbehaviour detected
+// by the overlay or by the engines. Reports of local inputs are ignored.
+func (m *Middleware) ReportMisbehaviour(nodeID flow.Identifier, misbehaviour network.Misbehaviour) {
+	if nodeID == m.me {
+		return
+	}
+	peerID, err := m.idTranslator.GetPeerID(nodeID)
+	if err != nil {
+		m.log.Warn().Err(err).
+			Hex("node_id", nodeID[:]).
+			Str("misbehaviour", string(misbehaviour)).
+			Msg("could not find peer id of misbehaving node")
+		return
+	}
+	m.penalize(peerID, misbehaviour)
+}
+
+// penalize adds the misbehaviour to the score of the peer, and disconnects or
+// blocklists the peer once its score crosses the respective threshold.
+func (m *Middleware) penalize(peerID peer.ID, misbehaviour network.Misbehaviour) {
+	m.metrics.PeerMisbehaviour(peerID.String(), string(misbehaviour))
+	if m.peerScorer == nil {
+		return
+	}
+
+	score, action := m.peerScorer.Penalize(peerID, misbehaviour)
+	m.metrics.PeerScore(peerID.String(), score)
+
+	log := m.log.With().
+		Str("peer_id", peerID.String()).
+		Str("misbehaviour", string(misbehaviour)).
+		Float64("score", score).
+		Logger()
+
+	switch action {
+	case PeerActionBlocklist:
+		log.Warn().Dur("duration", m.peerScorer.config.BlocklistDuration).Msg("blocklisting misbehaving peer")
+		m.metrics.PeerBlocklisted(peerID.String())
+		if m.rateLimiter != nil {
+			m.rateLimiter.Remove(peerID)
+		}
+		err := m.libP2PNode.BlocklistPeer(peerID, m.peerScorer.config.BlocklistDuration)
+		if err != nil {
+			log.Error().Err(err).Msg("could not blocklist misbehaving peer")
+		}
+	case PeerActionDisconnect:
+		log.Warn().Msg("disconnecting misbehaving peer")
+		err := m.libP2PNode.RemovePeer(m.ctx, peerID)
+		if err != nil {
+			log.Error().Err(err).Msg("could not disconnect misbehaving peer")
+		}
+	default:
+		log.Debug().Msg("peer misbehaved")
+	}
 }
 
 // Publish publishes a message on the channel. It models a distributed broadcast where the message is meant for all or
@@ -536,10 +638,31 @@ func (m *Middleware) IsConnected(nodeID flow.Identifier) (bool, error) {
 	return m.libP2PNode.IsConnected(peerID)
 }
 
+// ConnectedPeers returns the peers this node is currently connected to, with the
+// identifiers of the Flow nodes they belong to. Peers which do not translate to a
+// Flow node map to the zero identifier.
+func (m *Middleware) ConnectedPeers() map[peer.ID]flow.Identifier {
+	peers := make(map[peer.ID]flow.Identifier)
+	if m.libP2PNode == nil {
+		// middleware is not started yet
+		return peers
+	}
+
+	for _, pid := range m.libP2PNode.host.Network().Peers() {
+		fid, err := m.idTranslator.GetFlowID(pid)
+		if err != nil {
+			fid = flow.ZeroID
+		}
+		peers[pid] = fid
+	}
+
+	return peers
+}
+
 // unicastMaxMsgSize returns the max permissible size for a unicast message
 func unicastMaxMsgSize(msg *message.Message) int {
 	switch msg.Type {
-	case "messages.ChunkDataResponse":
+	case "messages.ChunkDataResponse", "messages.CheckpointChunkResponse":
 		return LargeMsgMaxUnicastMsgSize
 	default:
 		return DefaultMaxUnicastMsgSize
@@ -549,7 +672,7 @@ func unicastMaxMsgSize(msg *message.Message) int {
 // unicastMaxMsgDuration returns the max duration to allow for a unicast send to complete
 func (m *Middleware) unicastMaxMsgDuration(msg *message.Message) time.Duration {
 	switch msg.Type {
-	case "messages.ChunkDataResponse":
+	case "messages.ChunkDataResponse", "messages.CheckpointChunkResponse":
 		if LargeMsgUnicastTimeout > m.unicastMessageTimeout {
 			return LargeMsgMaxUnicastMsgSize
 		}
@@ -143,6 +143,7 @@ func (n *Network) Register(channel network.Channel, engine network.Engine) (netw
 		publish:   n.publish,
 		unicast:   n.unicast,
 		multicast: n.multicast,
+		report:    n.mw.ReportMisbehaviour,
 		close:     n.unregister,
 	}
 
@@ -208,8 +209,9 @@ func (n *Network) processNetworkMessage(senderID flow.Identifier, message *messa
 	}
 
 	// Convert message payload to a known message type
-	decodedMessage, err := n.codec.Decode(message.Payload)
+	decodedMessage, err := n.decode(message)
 	if err != nil {
+		n.mw.ReportMisbehaviour(senderID, network.MisbehaviourUndecodableMessage)
 		return fmt.Errorf("could not decode event: %w", err)
 	}
 
@@ -230,6 +232,17 @@ func (n *Network) processNetworkMessage(senderID flow.Identifier, message *messa
 	return nil
 }
 
+// decode decodes the payload of the given message. If the codec can expand the
+// payload, the decoded payload is bounded by the maximum size the transport allows
+// for messages of the type, which is the largest for unicast messages.
+func (n *Network) decode(message *message.Message) (interface{}, error) {
+	codec, ok := n.codec.(network.SizeLimitedCodec)
+	if !ok {
+		return n.codec.Decode(message.Payload)
+	}
+	return codec.DecodeWithLimit(message.Payload, unicastMaxMsgSize(message))
+}
+
 // genNetworkMessage uses the codec to encode an event into a NetworkMessage
 func (n *Network) genNetworkMessage(channel network.Channel, event interface{}, targetIDs ...flow.Identifier) (*message.Message, error) {
 	// encode the payload using the configured codec
@@ -387,6 +400,9 @@ func (n *Network) queueSubmitFunc(message interface{}) {
 	startTimestamp := time.Now()
 
 	err = eng.Process(qm.Target, qm.SenderID, qm.Payload)
+	if channels.IsInvalidInputError(err) {
+		n.mw.ReportMisbehaviour(qm.SenderID, network.MisbehaviourInvalidInput)
+	}
 	if err != nil {
 		n.logger.Error().
 			Err(err).
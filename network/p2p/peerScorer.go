@@ -0,0 +1,123 @@
+package p2p
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/onflow/flow-go/network"
+)
+
+// PeerAction is the action to take against a peer as a consequence of its misbehaviour.
+type PeerAction int
+
+const (
+	// PeerActionNone keeps the connection with the peer.
+	PeerActionNone PeerAction = iota
+	// PeerActionDisconnect closes the connections with the peer, which is free to reconnect.
+	PeerActionDisconnect
+	// PeerActionBlocklist closes the connections with the peer, and rejects any connection
+	// with it for the blocklist duration.
+	PeerActionBlocklist
+)
+
+// PeerScoringConfig configures the misbehaviour scoring of peers.
+type PeerScoringConfig struct {
+	Penalties           map[network.Misbehaviour]float64 // penalty added to the score of a peer for each kind of misbehaviour
+	HalfLife            time.Duration                    // duration over which the score of a peer decays to half its value
+	DisconnectThreshold float64                          // score from which a misbehaving peer is disconnected
+	BlocklistThreshold  float64                          // score from which a misbehaving peer is blocklisted
+	BlocklistDuration   time.Duration                    // duration for which a peer is blocklisted
+}
+
+// DefaultPeerScoringConfig returns the default misbehaviour scoring configuration. A peer
+// is disconnected for a handful of clearly invalid messages in a short time, and is
+// blocklisted if it keeps misbehaving.
+func DefaultPeerScoringConfig() PeerScoringConfig {
+	return PeerScoringConfig{
+		Penalties: map[network.Misbehaviour]float64{
+			network.MisbehaviourRateLimitExceeded:  1,
+			network.MisbehaviourSpoofedOrigin:      25,
+			network.MisbehaviourInvalidMessage:     10,
+			network.MisbehaviourUndecodableMessage: 10,
+			network.MisbehaviourInvalidInput:       5,
+		},
+		HalfLife:            10 * time.Minute,
+		DisconnectThreshold: 50,
+		BlocklistThreshold:  100,
+		BlocklistDuration:   time.Hour,
+	}
+}
+
+// PeerScorer keeps track of the misbehaviour score of peers. Each misbehaviour of a peer
+// adds the configured penalty to its score, which decays exponentially over time.
+type PeerScorer struct {
+	sync.Mutex
+	config PeerScoringConfig
+	scores map[peer.ID]*peerScore
+	now    func() time.Time
+}
+
+type peerScore struct {
+	value   float64
+	updated time.Time
+}
+
+// NewPeerScorer creates a new peer scorer with the given configuration.
+func NewPeerScorer(config PeerScoringConfig) *PeerScorer {
+	return &PeerScorer{
+		config: config,
+		scores: make(map[peer.ID]*peerScore),
+		now:    time.Now,
+	}
+}
+
+// Penalize adds the penalty of the misbehaviour to the score of the peer. It returns the
+// new score of the peer and the action to take against it.
+func (s *PeerScorer) Penalize(peerID peer.ID, misbehaviour network.Misbehaviour) (float64, PeerAction) {
+	s.Lock()
+	defer s.Unlock()
+
+	now := s.now()
+	score, ok := s.scores[peerID]
+	if !ok {
+		score = &peerScore{updated: now}
+		s.scores[peerID] = score
+	}
+	score.value = s.decay(score, now) + s.config.Penalties[misbehaviour]
+	score.updated = now
+
+	switch {
+	case score.value >= s.config.BlocklistThreshold:
+		// the peer starts with a clean score once its blocklisting expires
+		delete(s.scores, peerID)
+		return score.value, PeerActionBlocklist
+	case score.value >= s.config.DisconnectThreshold:
+		return score.value, PeerActionDisconnect
+	default:
+		return score.value, PeerActionNone
+	}
+}
+
+// Score returns the current score of the peer.
+func (s *PeerScorer) Score(peerID peer.ID) float64 {
+	s.Lock()
+	defer s.Unlock()
+
+	score, ok := s.scores[peerID]
+	if !ok {
+		return 0
+	}
+	return s.decay(score, s.now())
+}
+
+// decay returns the value of the score decayed until the given time.
+func (s *PeerScorer) decay(score *peerScore, now time.Time) float64 {
+	if s.config.HalfLife <= 0 {
+		return score.value
+	}
+	elapsed := now.Sub(score.updated)
+	return score.value * math.Exp2(-float64(elapsed)/float64(s.config.HalfLife))
+}
@@ -0,0 +1,57 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/onflow/flow-go/network"
+)
+
+// TestPeerScorer tests that the penalties of misbehaviours add up to the disconnect and
+// blocklist thresholds, and that the score of a peer decays over time.
+func TestPeerScorer(t *testing.T) {
+	now := time.Now()
+	scorer := NewPeerScorer(PeerScoringConfig{
+		Penalties: map[network.Misbehaviour]float64{
+			network.MisbehaviourInvalidInput:       10,
+			network.MisbehaviourUndecodableMessage: 40,
+		},
+		HalfLife:            time.Minute,
+		DisconnectThreshold: 50,
+		BlocklistThreshold:  100,
+		BlocklistDuration:   time.Hour,
+	})
+	scorer.now = func() time.Time { return now }
+	peerID := peer.ID("peer")
+
+	score, action := scorer.Penalize(peerID, network.MisbehaviourInvalidInput)
+	assert.Equal(t, 10.0, score)
+	assert.Equal(t, PeerActionNone, action)
+
+	// misbehaviours without a penalty do not change the score
+	score, action = scorer.Penalize(peerID, network.MisbehaviourRateLimitExceeded)
+	assert.Equal(t, 10.0, score)
+	assert.Equal(t, PeerActionNone, action)
+
+	score, action = scorer.Penalize(peerID, network.MisbehaviourUndecodableMessage)
+	assert.Equal(t, 50.0, score)
+	assert.Equal(t, PeerActionDisconnect, action)
+
+	// the score halves after the half life
+	now = now.Add(time.Minute)
+	assert.InDelta(t, 25.0, scorer.Score(peerID), 0.0001)
+
+	score, action = scorer.Penalize(peerID, network.MisbehaviourUndecodableMessage)
+	assert.InDelta(t, 65.0, score, 0.0001)
+	assert.Equal(t, PeerActionDisconnect, action)
+
+	score, action = scorer.Penalize(peerID, network.MisbehaviourUndecodableMessage)
+	assert.InDelta(t, 105.0, score, 0.0001)
+	assert.Equal(t, PeerActionBlocklist, action)
+
+	// a blocklisted peer starts with a clean score
+	assert.Equal(t, 0.0, scorer.Score(peerID))
+}
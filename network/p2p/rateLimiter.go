@@ -0,0 +1,79 @@
+package p2p
+
+import (
+	"sync"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"golang.org/x/time/rate"
+
+	"github.com/onflow/flow-go/network"
+)
+
+// RateLimit is the configuration of a token bucket rate limit.
+type RateLimit struct {
+	Rate  rate.Limit // sustained number of messages per second, zero disables the limit
+	Burst int        // maximum number of messages accepted at once
+}
+
+// RateLimiterConfig configures the rate limits of the inbound messages of each peer.
+type RateLimiterConfig struct {
+	Default  RateLimit                     // limit of the channels without a limit of their own
+	Channels map[network.Channel]RateLimit // limits of individual channels
+}
+
+// limit returns the rate limit of the given channel.
+func (c RateLimiterConfig) limit(channel network.Channel) RateLimit {
+	limit, ok := c.Channels[channel]
+	if !ok {
+		return c.Default
+	}
+	return limit
+}
+
+// RateLimiter limits the rate of inbound messages of each peer on each channel,
+// with a token bucket per peer and channel.
+type RateLimiter struct {
+	sync.Mutex
+	config   RateLimiterConfig
+	limiters map[peer.ID]map[network.Channel]*rate.Limiter
+}
+
+// NewRateLimiter creates a new rate limiter with the given configuration.
+func NewRateLimiter(config RateLimiterConfig) *RateLimiter {
+	return &RateLimiter{
+		config:   config,
+		limiters: make(map[peer.ID]map[network.Channel]*rate.Limiter),
+	}
+}
+
+// Allow returns true if a message of the peer on the channel is within the rate
+// limit, consuming a token of the bucket of the peer and channel.
+func (r *RateLimiter) Allow(peerID peer.ID, channel network.Channel) bool {
+	limit := r.config.limit(channel)
+	if limit.Rate == 0 {
+		return true
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	channels, ok := r.limiters[peerID]
+	if !ok {
+		channels = make(map[network.Channel]*rate.Limiter)
+		r.limiters[peerID] = channels
+	}
+	limiter, ok := channels[channel]
+	if !ok {
+		limiter = rate.NewLimiter(limit.Rate, limit.Burst)
+		channels[channel] = limiter
+	}
+
+	return limiter.Allow()
+}
+
+// Remove drops the token buckets of the peer.
+func (r *RateLimiter) Remove(peerID peer.ID) {
+	r.Lock()
+	defer r.Unlock()
+	delete(r.limiters, peerID)
+}
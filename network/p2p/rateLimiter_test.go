@@ -0,0 +1,48 @@
+package p2p
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/network"
+)
+
+// TestRateLimiter tests that the messages of each peer on each channel are limited
+// by their own token bucket, with the limit of the channel.
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(RateLimiterConfig{
+		Default: RateLimit{Rate: rate.Every(time.Hour), Burst: 2},
+		Channels: map[network.Channel]RateLimit{
+			engine.SyncCommittee:    {Rate: rate.Every(time.Hour), Burst: 1},
+			engine.PushTransactions: {Rate: 0},
+		},
+	})
+	peer1 := peer.ID("peer-1")
+	peer2 := peer.ID("peer-2")
+
+	// the default limit applies to channels without a limit of their own
+	assert.True(t, limiter.Allow(peer1, engine.PushBlocks))
+	assert.True(t, limiter.Allow(peer1, engine.PushBlocks))
+	assert.False(t, limiter.Allow(peer1, engine.PushBlocks))
+
+	// each peer has its own bucket
+	assert.True(t, limiter.Allow(peer2, engine.PushBlocks))
+
+	// each channel has its own bucket, with its own limit
+	assert.True(t, limiter.Allow(peer1, engine.SyncCommittee))
+	assert.False(t, limiter.Allow(peer1, engine.SyncCommittee))
+
+	// a zero rate disables the limit
+	for i := 0; i < 10; i++ {
+		assert.True(t, limiter.Allow(peer1, engine.PushTransactions))
+	}
+
+	// removing a peer resets its buckets
+	limiter.Remove(peer1)
+	assert.True(t, limiter.Allow(peer1, engine.PushBlocks))
+}
@@ -44,6 +44,8 @@ func getPriorityByType(message interface{}) Priority {
 		return HighPriority
 	case *messages.BlockVote:
 		return HighPriority
+	case *messages.TimeoutObject:
+		return HighPriority
 
 	// protocol state sync
 	case *messages.SyncRequest:
@@ -62,6 +64,8 @@ func getPriorityByType(message interface{}) Priority {
 		return HighPriority
 	case *messages.ClusterBlockVote:
 		return HighPriority
+	case *messages.ClusterTimeoutObject:
+		return HighPriority
 	case *messages.ClusterBlockResponse:
 		return HighPriority
 
@@ -78,6 +82,8 @@ func getPriorityByType(message interface{}) Priority {
 		return HighPriority
 	case *flow.ResultApproval:
 		return HighPriority
+	case *flow.ChunkChallenge:
+		return HighPriority
 
 	// execution state synchronization
 	case *messages.ExecutionStateSyncRequest:
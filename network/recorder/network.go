@@ -0,0 +1,95 @@
+package recorder
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/network"
+)
+
+// Network wraps the network of a node, and records all messages the network
+// hands to the engines of the node, so that they can be replayed offline.
+type Network struct {
+	module.ReadyDoneAwareNetwork
+	log      zerolog.Logger
+	recorder *Recorder
+	done     chan struct{}
+	doneOnce sync.Once
+	fullOnce sync.Once
+}
+
+// NewNetwork creates a network recording the inbound messages of the given
+// network with the given recorder. The recorder is closed once the network is done.
+func NewNetwork(log zerolog.Logger, net module.ReadyDoneAwareNetwork, recorder *Recorder) *Network {
+	return &Network{
+		ReadyDoneAwareNetwork: net,
+		log:                   log.With().Str("component", "network_recorder").Logger(),
+		recorder:              recorder,
+		done:                  make(chan struct{}),
+	}
+}
+
+// Register registers the engine with the wrapped network, such that the
+// messages delivered to the engine are recorded.
+func (n *Network) Register(channel network.Channel, engine network.Engine) (network.Conduit, error) {
+	return n.ReadyDoneAwareNetwork.Register(channel, &recordingEngine{
+		Engine:  engine,
+		network: n,
+	})
+}
+
+// Done returns a channel which is closed once the wrapped network is done and
+// the recording is closed.
+func (n *Network) Done() <-chan struct{} {
+	n.doneOnce.Do(func() {
+		go func() {
+			<-n.ReadyDoneAwareNetwork.Done()
+			err := n.recorder.Close()
+			if err != nil {
+				n.log.Error().Err(err).Msg("could not close network recording")
+			}
+			close(n.done)
+		}()
+	})
+	return n.done
+}
+
+// record records the message, and logs failures instead of failing the delivery
+// of the message.
+func (n *Network) record(originID flow.Identifier, channel network.Channel, event interface{}) {
+	err := n.recorder.Record(originID, channel, event)
+	if errors.Is(err, ErrRecordingFull) {
+		n.fullOnce.Do(func() {
+			n.log.Warn().Msg("network recording reached its maximum size, messages are not recorded anymore")
+		})
+		return
+	}
+	if err != nil {
+		n.log.Error().
+			Err(err).
+			Hex("origin_id", originID[:]).
+			Str("channel", channel.String()).
+			Msg("could not record message")
+	}
+}
+
+// recordingEngine records the messages received from the network before
+// handing them to the wrapped engine. Local events are not recorded.
+type recordingEngine struct {
+	network.Engine
+	network *Network
+}
+
+func (e *recordingEngine) Submit(channel network.Channel, originID flow.Identifier, event interface{}) {
+	e.network.record(originID, channel, event)
+	e.Engine.Submit(channel, originID, event)
+}
+
+func (e *recordingEngine) Process(channel network.Channel, originID flow.Identifier, event interface{}) error {
+	e.network.record(originID, channel, event)
+	return e.Engine.Process(channel, originID, event)
+}
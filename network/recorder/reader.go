@@ -0,0 +1,65 @@
+package recorder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/onflow/flow-go/network"
+)
+
+// Reader reads the messages of a recording in the order they were recorded.
+type Reader struct {
+	codec   network.Codec
+	file    *os.File
+	decoder *json.Decoder
+	read    uint64
+}
+
+// NewReader opens the recording file at the given path, whose messages were
+// encoded with the given codec.
+func NewReader(codec network.Codec, path string) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open recording file: %w", err)
+	}
+
+	return &Reader{
+		codec:   codec,
+		file:    file,
+		decoder: json.NewDecoder(file),
+	}, nil
+}
+
+// Next returns the next message of the recording, or io.EOF once all messages
+// have been read.
+func (r *Reader) Next() (*Message, error) {
+	var e entry
+	err := r.decoder.Decode(&e)
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read message %d: %w", r.read, err)
+	}
+
+	event, err := r.codec.Decode(e.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode message %d: %w", r.read, err)
+	}
+	r.read++
+
+	return &Message{
+		Timestamp: e.Timestamp,
+		OriginID:  e.OriginID,
+		Channel:   e.Channel,
+		Event:     event,
+	}, nil
+}
+
+// Close closes the recording file.
+func (r *Reader) Close() error {
+	return r.file.Close()
+}
@@ -0,0 +1,213 @@
+package recorder
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+)
+
+// Message is an inbound message of a node, as recorded.
+type Message struct {
+	Timestamp time.Time       // time at which the message was handed to the engine
+	OriginID  flow.Identifier // node which sent the message
+	Channel   network.Channel // channel the message was received on
+	Event     interface{}     // the message itself
+}
+
+// entry is a message as persisted in a recording, with its event encoded by the
+// network codec. A recording is a sequence of entries encoded as JSON, one per line.
+type entry struct {
+	Timestamp time.Time
+	OriginID  flow.Identifier
+	Channel   network.Channel
+	Payload   []byte
+}
+
+const (
+	// DefaultFlushInterval is the default interval at which buffered messages are
+	// written to the recording file.
+	DefaultFlushInterval = time.Second
+	// DefaultMaxSize is the default size in bytes from which a recording file is
+	// not appended to anymore.
+	DefaultMaxSize = 1 << 30
+)
+
+// ErrRecordingFull is returned when recording a message which would make the
+// recording file exceed its maximum size.
+var ErrRecordingFull = errors.New("recording file reached its maximum size")
+
+// RecorderOption configures a recorder.
+type RecorderOption func(*Recorder)
+
+// WithFlushInterval sets the interval at which buffered messages are written to
+// the recording file. A zero interval writes every message once it is recorded.
+func WithFlushInterval(interval time.Duration) RecorderOption {
+	return func(r *Recorder) {
+		r.flushInterval = interval
+	}
+}
+
+// WithMaxSize sets the size in bytes the recording file may grow to, including
+// the messages recorded before. Messages which would make the file exceed the
+// size are rejected. A zero size does not limit the recording.
+func WithMaxSize(size int64) RecorderOption {
+	return func(r *Recorder) {
+		r.maxSize = size
+	}
+}
+
+// Recorder persists inbound messages to a recording file. Messages are buffered,
+// and written to the file periodically. It is safe for concurrent use.
+type Recorder struct {
+	mu            sync.Mutex
+	codec         network.Codec
+	file          *os.File
+	writer        *bufio.Writer
+	size          int64 // size of the recording, including buffered messages
+	maxSize       int64
+	flushInterval time.Duration
+	stop          chan struct{}
+}
+
+// NewRecorder creates a recorder appending messages to the recording file at the
+// given path, which is created if it does not exist. Messages are encoded with
+// the given codec, which must be used to read the recording again. The recording
+// must be closed with Close.
+func NewRecorder(codec network.Codec, path string, opts ...RecorderOption) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not open recording file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("could not get size of recording file: %w", err)
+	}
+
+	r := &Recorder{
+		codec:         codec,
+		file:          file,
+		writer:        bufio.NewWriter(file),
+		size:          info.Size(),
+		maxSize:       DefaultMaxSize,
+		flushInterval: DefaultFlushInterval,
+		stop:          make(chan struct{}),
+	}
+	for _, apply := range opts {
+		apply(r)
+	}
+
+	if r.flushInterval > 0 {
+		go r.flushPeriodically()
+	}
+
+	return r, nil
+}
+
+// flushPeriodically writes the buffered messages to the recording file at the
+// flush interval, until the recorder is closed.
+func (r *Recorder) flushPeriodically() {
+	ticker := time.NewTicker(r.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// write errors are sticky, and returned on the next record or close
+			_ = r.Flush()
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// Record persists a message received from the given origin on the given channel.
+// Messages are buffered, they are written to the recording file within the flush
+// interval, or on Flush or Close. Once the recording file reached its maximum
+// size, messages are rejected with ErrRecordingFull.
+func (r *Recorder) Record(originID flow.Identifier, channel network.Channel, event interface{}) error {
+	payload, err := r.codec.Encode(event)
+	if err != nil {
+		return fmt.Errorf("could not encode message: %w", err)
+	}
+
+	line, err := json.Marshal(entry{
+		Timestamp: time.Now().UTC(),
+		OriginID:  originID,
+		Channel:   channel,
+		Payload:   payload,
+	})
+	if err != nil {
+		return fmt.Errorf("could not encode entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return fmt.Errorf("recorder is closed")
+	}
+
+	if r.maxSize > 0 && r.size+int64(len(line)) > r.maxSize {
+		return ErrRecordingFull
+	}
+
+	_, err = r.writer.Write(line)
+	if err != nil {
+		return fmt.Errorf("could not write message: %w", err)
+	}
+	r.size += int64(len(line))
+
+	if r.flushInterval == 0 {
+		err = r.writer.Flush()
+		if err != nil {
+			return fmt.Errorf("could not flush message: %w", err)
+		}
+	}
+	return nil
+}
+
+// Flush writes all buffered messages to the recording file.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	return r.writer.Flush()
+}
+
+// Close writes all buffered messages and closes the recording file. Messages
+// recorded after closing are rejected.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	close(r.stop)
+
+	err := r.writer.Flush()
+	if err != nil {
+		_ = r.file.Close()
+		r.file = nil
+		return fmt.Errorf("could not flush recording: %w", err)
+	}
+
+	err = r.file.Close()
+	r.file = nil
+	if err != nil {
+		return fmt.Errorf("could not close recording file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,182 @@
+package recorder_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/messages"
+	mockmodule "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/network"
+	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/network/recorder"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+const testChannel = network.Channel("test-channel")
+
+// readAll reads all messages of the recording at the given path.
+func readAll(t *testing.T, path string) []*recorder.Message {
+	reader, err := recorder.NewReader(cborcodec.NewCodec(), path)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	var recorded []*recorder.Message
+	for {
+		msg, err := reader.Next()
+		if err == io.EOF {
+			return recorded
+		}
+		require.NoError(t, err)
+		recorded = append(recorded, msg)
+	}
+}
+
+// TestRecordAndRead checks that recorded messages are read in order, including
+// those appended to an existing recording.
+func TestRecordAndRead(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		path := filepath.Join(dir, "recording")
+		originID := unittest.IdentifierFixture()
+		request := &messages.EntityRequest{Nonce: 1, EntityIDs: unittest.IdentifierListFixture(2)}
+		guarantee := unittest.CollectionGuaranteeFixture()
+
+		rec, err := recorder.NewRecorder(cborcodec.NewCodec(), path)
+		require.NoError(t, err)
+		require.NoError(t, rec.Record(originID, testChannel, request))
+		require.NoError(t, rec.Close())
+
+		// messages recorded after closing are rejected
+		assert.Error(t, rec.Record(originID, testChannel, request))
+
+		rec, err = recorder.NewRecorder(cborcodec.NewCodec(), path)
+		require.NoError(t, err)
+		require.NoError(t, rec.Record(originID, testChannel, guarantee))
+		require.NoError(t, rec.Close())
+
+		recorded := readAll(t, path)
+		require.Len(t, recorded, 2)
+		assert.Equal(t, originID, recorded[0].OriginID)
+		assert.Equal(t, testChannel, recorded[0].Channel)
+		assert.Equal(t, request, recorded[0].Event)
+		assert.Equal(t, guarantee, recorded[1].Event)
+		assert.False(t, recorded[1].Timestamp.Before(recorded[0].Timestamp))
+	})
+}
+
+// TestRecorder_Flush checks that recorded messages are written to the recording
+// file within the flush interval, without closing the recorder.
+func TestRecorder_Flush(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		originID := unittest.IdentifierFixture()
+		request := &messages.EntityRequest{Nonce: 1}
+
+		t.Run("interval", func(t *testing.T) {
+			path := filepath.Join(dir, "interval")
+			rec, err := recorder.NewRecorder(cborcodec.NewCodec(), path, recorder.WithFlushInterval(10*time.Millisecond))
+			require.NoError(t, err)
+			defer rec.Close()
+
+			require.NoError(t, rec.Record(originID, testChannel, request))
+			require.Eventually(t, func() bool {
+				return len(readAll(t, path)) == 1
+			}, time.Second, 10*time.Millisecond)
+		})
+
+		t.Run("every message", func(t *testing.T) {
+			path := filepath.Join(dir, "every")
+			rec, err := recorder.NewRecorder(cborcodec.NewCodec(), path, recorder.WithFlushInterval(0))
+			require.NoError(t, err)
+			defer rec.Close()
+
+			require.NoError(t, rec.Record(originID, testChannel, request))
+			require.Len(t, readAll(t, path), 1)
+		})
+	})
+}
+
+// TestRecorder_MaxSize checks that messages which would make the recording file
+// exceed its maximum size are rejected, including after reopening the recording.
+func TestRecorder_MaxSize(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		path := filepath.Join(dir, "recording")
+		originID := unittest.IdentifierFixture()
+		request := &messages.EntityRequest{Nonce: 1}
+
+		rec, err := recorder.NewRecorder(cborcodec.NewCodec(), path, recorder.WithFlushInterval(0))
+		require.NoError(t, err)
+		require.NoError(t, rec.Record(originID, testChannel, request))
+		require.NoError(t, rec.Close())
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		size := info.Size()
+
+		// room for two more messages
+		rec, err = recorder.NewRecorder(cborcodec.NewCodec(), path, recorder.WithMaxSize(3*size+size/2))
+		require.NoError(t, err)
+		require.NoError(t, rec.Record(originID, testChannel, request))
+		require.NoError(t, rec.Record(originID, testChannel, request))
+		err = rec.Record(originID, testChannel, request)
+		require.ErrorIs(t, err, recorder.ErrRecordingFull)
+		require.NoError(t, rec.Close())
+
+		require.Len(t, readAll(t, path), 3)
+	})
+}
+
+// TestNetwork checks that the messages delivered to registered engines are
+// recorded, and that the recording is closed once the network is done.
+func TestNetwork(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		path := filepath.Join(dir, "recording")
+		rec, err := recorder.NewRecorder(cborcodec.NewCodec(), path)
+		require.NoError(t, err)
+
+		var registered network.Engine
+		done := make(chan struct{})
+		net := new(mockmodule.ReadyDoneAwareNetwork)
+		net.On("Register", testChannel, mock.Anything).Run(func(args mock.Arguments) {
+			registered = args.Get(1).(network.Engine)
+		}).Return(new(mocknetwork.Conduit), nil)
+		net.On("Done").Return((<-chan struct{})(done))
+
+		engine := new(mocknetwork.Engine)
+		recNet := recorder.NewNetwork(unittest.Logger(), net, rec)
+		_, err = recNet.Register(testChannel, engine)
+		require.NoError(t, err)
+		require.NotNil(t, registered)
+
+		originID := unittest.IdentifierFixture()
+		first := &messages.EntityRequest{Nonce: 1}
+		second := &messages.EntityRequest{Nonce: 2}
+		engine.On("Process", testChannel, originID, first).Return(nil).Once()
+		engine.On("Submit", testChannel, originID, second).Once()
+		engine.On("SubmitLocal", second).Once()
+
+		require.NoError(t, registered.Process(testChannel, originID, first))
+		registered.Submit(testChannel, originID, second)
+		registered.SubmitLocal(second)
+		engine.AssertExpectations(t)
+
+		close(done)
+		unittest.RequireCloseBefore(t, recNet.Done(), time.Second, "network not done")
+		// done is idempotent
+		unittest.RequireCloseBefore(t, recNet.Done(), time.Second, "network not done")
+
+		recorded := readAll(t, path)
+		require.Len(t, recorded, 2)
+		assert.Equal(t, first, recorded[0].Event)
+		assert.Equal(t, second, recorded[1].Event)
+		for _, msg := range recorded {
+			assert.Equal(t, originID, msg.OriginID)
+			assert.Equal(t, testChannel, msg.Channel)
+		}
+	})
+}
@@ -44,7 +44,7 @@ func (b *Buffer) Save(m *PendingMessage) {
 func (b *Buffer) DeliverRecursive(sendOne func(*PendingMessage)) {
 	for {
 		// get all pending messages, and clear the buffer
-		messages := b.takeAll()
+		messages := b.TakeAll()
 
 		// This check is necessary to exit the endless for loop
 		if len(messages) == 0 {
@@ -62,7 +62,7 @@ func (b *Buffer) DeliverRecursive(sendOne func(*PendingMessage)) {
 // and will remain in the buffer.
 func (b *Buffer) Deliver(sendOne func(*PendingMessage) bool) {
 
-	messages := b.takeAll()
+	messages := b.TakeAll()
 	var unsent []*PendingMessage
 
 	for _, msg := range messages {
@@ -78,8 +78,8 @@ func (b *Buffer) Deliver(sendOne func(*PendingMessage) bool) {
 	b.Unlock()
 }
 
-// takeAll takes all pending messages from the buffer and empties the buffer.
-func (b *Buffer) takeAll() []*PendingMessage {
+// TakeAll takes all pending messages from the buffer and empties the buffer.
+func (b *Buffer) TakeAll() []*PendingMessage {
 	b.Lock()
 	defer b.Unlock()
 
@@ -40,6 +40,9 @@ func (c *Conduit) Multicast(event interface{}, num uint, targetIDs ...flow.Ident
 	return c.multicast(c.channel, event, num, targetIDs...)
 }
 
+// ReportInvalidInput is a no-op, as the stub network doesn't penalize nodes.
+func (c *Conduit) ReportInvalidInput(flow.Identifier) {}
+
 func (c *Conduit) Close() error {
 	if c.ctx.Err() != nil {
 		return fmt.Errorf("conduit for channel %s closed", c.channel)
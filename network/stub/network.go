@@ -205,6 +205,16 @@ func (n *Network) DeliverSome(syncOnProcess bool, shouldDeliver func(*PendingMes
 	})
 }
 
+// Deliver sends the given message to all its targeted nodes, bypassing the
+// buffer of the hub. It is meant for test harnesses taking messages out of the
+// buffer themselves, in order to control their order and timing of delivery.
+//
+// If syncOnProcess is true, the sender and receiver are synchronized on processing the message.
+// Otherwise they sync on delivery of the message.
+func (n *Network) Deliver(m *PendingMessage, syncOnProcess bool) error {
+	return n.sendToAllTargets(m, syncOnProcess)
+}
+
 // sendToAllTargets send a message to all its targeted nodes if the targeted
 // node has not yet seen it.
 // sync parameter defines whether the sender and receiver are synced over processing or delivery of
@@ -0,0 +1,110 @@
+package stub
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/onflow/flow-go/network/recorder"
+)
+
+// ReplayOption configures a replay.
+type ReplayOption func(*replayConfig)
+
+type replayConfig struct {
+	paced   bool
+	onSent  func(*PendingMessage)
+	onError func(*recorder.Message, error) error
+}
+
+// WithPacing has the replay wait between messages as long as between their
+// recording, instead of delivering them as fast as they are processed.
+func WithPacing() ReplayOption {
+	return func(cfg *replayConfig) {
+		cfg.paced = true
+	}
+}
+
+// WithSentHandler sets a function called with each message sent by the node
+// while processing the replayed messages. By default, sent messages are dropped.
+func WithSentHandler(onSent func(*PendingMessage)) ReplayOption {
+	return func(cfg *replayConfig) {
+		cfg.onSent = onSent
+	}
+}
+
+// WithErrorHandler sets a function called with each replayed message that the
+// node failed to process. The replay continues if the function returns nil, and
+// stops with the returned error otherwise. By default, the replay stops at the
+// first failure.
+func WithErrorHandler(onError func(*recorder.Message, error) error) ReplayOption {
+	return func(cfg *replayConfig) {
+		cfg.onError = onError
+	}
+}
+
+// Replay feeds the messages of a recording to the engines of the node attached
+// to the given network, in the order they were recorded. The node should be
+// bootstrapped from the same root state as the recorded node.
+//
+// Each message is processed synchronously by the engine registered on its
+// channel, before the next message is delivered. Unlike messages sent over the
+// hub, replayed messages are not deduplicated, as the recording only contains
+// the messages the recorded network handed to its engines. Messages sent by the
+// node are taken out of the buffer of the hub after each replayed message, and
+// passed to the handler set with WithSentHandler.
+func Replay(net *Network, reader *recorder.Reader, opts ...ReplayOption) error {
+	cfg := replayConfig{
+		onError: func(_ *recorder.Message, err error) error { return err },
+	}
+	for _, apply := range opts {
+		apply(&cfg)
+	}
+
+	var previous time.Time
+	for index := 0; ; index++ {
+		msg, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not read recording: %w", err)
+		}
+
+		if cfg.paced && !previous.IsZero() && msg.Timestamp.After(previous) {
+			time.Sleep(msg.Timestamp.Sub(previous))
+		}
+		previous = msg.Timestamp
+
+		err = net.replay(msg)
+		if err != nil {
+			err = cfg.onError(msg, err)
+			if err != nil {
+				return fmt.Errorf("could not replay message %d: %w", index, err)
+			}
+		}
+
+		for _, sent := range net.hub.Buffer.TakeAll() {
+			if cfg.onSent != nil {
+				cfg.onSent(sent)
+			}
+		}
+	}
+}
+
+// replay has the engine registered on the channel of the message process it.
+func (n *Network) replay(msg *recorder.Message) error {
+	n.Lock()
+	receiverEngine, ok := n.engines[msg.Channel]
+	n.Unlock()
+	if !ok {
+		return fmt.Errorf("no engine registered on channel %s", msg.Channel)
+	}
+
+	err := receiverEngine.Process(msg.Channel, msg.OriginID, msg.Event)
+	if err != nil {
+		return fmt.Errorf("receiver engine failed to process event (%v): %w", msg.Event, err)
+	}
+	return nil
+}
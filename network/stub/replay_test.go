@@ -0,0 +1,143 @@
+package stub_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/testutil/mocklocal"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/network"
+	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/recorder"
+	"github.com/onflow/flow-go/network/stub"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+const (
+	testChannel  = network.Channel("test-channel")
+	otherChannel = network.Channel("other-channel")
+)
+
+// echoEngine records the events it processes, and sends them back to their origin.
+type echoEngine struct {
+	con       network.Conduit
+	processed []interface{}
+}
+
+func (e *echoEngine) SubmitLocal(interface{}) {}
+
+func (e *echoEngine) Submit(channel network.Channel, originID flow.Identifier, event interface{}) {
+	_ = e.Process(channel, originID, event)
+}
+
+func (e *echoEngine) ProcessLocal(interface{}) error {
+	return nil
+}
+
+func (e *echoEngine) Process(_ network.Channel, originID flow.Identifier, event interface{}) error {
+	e.processed = append(e.processed, event)
+	return e.con.Unicast(event, originID)
+}
+
+// recordingFixture records the given events, all sent by the given origin, and
+// returns a reader of the recording.
+func recordingFixture(t *testing.T, dir string, originID flow.Identifier, channels []network.Channel, events []interface{}) *recorder.Reader {
+	path := filepath.Join(dir, "recording")
+	rec, err := recorder.NewRecorder(cborcodec.NewCodec(), path)
+	require.NoError(t, err)
+	for i, event := range events {
+		require.NoError(t, rec.Record(originID, channels[i], event))
+	}
+	require.NoError(t, rec.Close())
+
+	reader, err := recorder.NewReader(cborcodec.NewCodec(), path)
+	require.NoError(t, err)
+	return reader
+}
+
+func TestReplay(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		originID := unittest.IdentifierFixture()
+		nodeID := unittest.IdentifierFixture()
+
+		// identical messages are replayed as often as they were recorded
+		events := []interface{}{
+			&messages.EntityRequest{Nonce: 1},
+			&messages.EntityRequest{Nonce: 2},
+			&messages.EntityRequest{Nonce: 1},
+		}
+		reader := recordingFixture(t, dir, originID, []network.Channel{testChannel, testChannel, testChannel}, events)
+		defer reader.Close()
+
+		net := stub.NewNetwork(nil, mocklocal.NewMockLocal(nil, nodeID, t), stub.NewNetworkHub())
+		eng := &echoEngine{}
+		con, err := net.Register(testChannel, eng)
+		require.NoError(t, err)
+		eng.con = con
+
+		var sent []*stub.PendingMessage
+		err = stub.Replay(net, reader, stub.WithSentHandler(func(msg *stub.PendingMessage) {
+			sent = append(sent, msg)
+		}))
+		require.NoError(t, err)
+
+		assert.Equal(t, events, eng.processed)
+		require.Len(t, sent, len(events))
+		for i, msg := range sent {
+			assert.Equal(t, nodeID, msg.From)
+			assert.Equal(t, []flow.Identifier{originID}, msg.TargetIDs)
+			assert.Equal(t, events[i], msg.Event)
+		}
+	})
+}
+
+func TestReplay_Failure(t *testing.T) {
+	events := []interface{}{
+		&messages.EntityRequest{Nonce: 1},
+		&messages.EntityRequest{Nonce: 2},
+	}
+	channels := []network.Channel{otherChannel, testChannel}
+
+	newNetwork := func(t *testing.T) (*stub.Network, *echoEngine) {
+		net := stub.NewNetwork(nil, mocklocal.NewMockLocal(nil, unittest.IdentifierFixture(), t), stub.NewNetworkHub())
+		eng := &echoEngine{}
+		con, err := net.Register(testChannel, eng)
+		require.NoError(t, err)
+		eng.con = con
+		return net, eng
+	}
+
+	t.Run("stops by default", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			reader := recordingFixture(t, dir, unittest.IdentifierFixture(), channels, events)
+			defer reader.Close()
+
+			net, eng := newNetwork(t)
+			err := stub.Replay(net, reader)
+			assert.Error(t, err)
+			assert.Empty(t, eng.processed)
+		})
+	})
+
+	t.Run("continues with error handler", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			reader := recordingFixture(t, dir, unittest.IdentifierFixture(), channels, events)
+			defer reader.Close()
+
+			net, eng := newNetwork(t)
+			var failed []*recorder.Message
+			err := stub.Replay(net, reader, stub.WithErrorHandler(func(msg *recorder.Message, err error) error {
+				failed = append(failed, msg)
+				return nil
+			}))
+			require.NoError(t, err)
+			require.Len(t, failed, 1)
+			assert.Equal(t, otherChannel, failed[0].Channel)
+			assert.Equal(t, events[1:], eng.processed)
+		})
+	})
+}
@@ -0,0 +1,91 @@
+package badger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// ChunkChallenges implements persistent storage for chunk challenges. Challenges
+// are rare, hence they are not cached.
+type ChunkChallenges struct {
+	db *badger.DB
+}
+
+func NewChunkChallenges(db *badger.DB) *ChunkChallenges {
+	return &ChunkChallenges{
+		db: db,
+	}
+}
+
+// Store stores a chunk challenge, and indexes it as unresolved. Storing a
+// challenge which is stored already is a no-op.
+func (c *ChunkChallenges) Store(challenge *flow.ChunkChallenge) error {
+	err := operation.RetryOnConflict(c.db.Update, func(tx *badger.Txn) error {
+		err := operation.InsertChunkChallenge(challenge)(tx)
+		if errors.Is(err, storage.ErrAlreadyExists) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not insert chunk challenge: %w", err)
+		}
+		err = operation.IndexUnresolvedChunkChallenge(challenge.ID())(tx)
+		if err != nil {
+			return fmt.Errorf("could not index unresolved chunk challenge: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("could not store chunk challenge: %w", err)
+	}
+	return nil
+}
+
+// ByID retrieves a chunk challenge by its ID.
+func (c *ChunkChallenges) ByID(challengeID flow.Identifier) (*flow.ChunkChallenge, error) {
+	var challenge flow.ChunkChallenge
+	err := c.db.View(operation.RetrieveChunkChallenge(challengeID, &challenge))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve chunk challenge: %w", err)
+	}
+	return &challenge, nil
+}
+
+// Unresolved retrieves all chunk challenges which are not resolved yet.
+func (c *ChunkChallenges) Unresolved() ([]*flow.ChunkChallenge, error) {
+	var challenges []*flow.ChunkChallenge
+	err := c.db.View(func(tx *badger.Txn) error {
+		var challengeIDs []flow.Identifier
+		err := operation.LookupUnresolvedChunkChallenges(&challengeIDs)(tx)
+		if err != nil {
+			return fmt.Errorf("could not look up unresolved chunk challenges: %w", err)
+		}
+		for _, challengeID := range challengeIDs {
+			var challenge flow.ChunkChallenge
+			err = operation.RetrieveChunkChallenge(challengeID, &challenge)(tx)
+			if err != nil {
+				return fmt.Errorf("could not retrieve chunk challenge %x: %w", challengeID, err)
+			}
+			challenges = append(challenges, &challenge)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return challenges, nil
+}
+
+// Resolve marks a chunk challenge as resolved, while keeping the challenge.
+func (c *ChunkChallenges) Resolve(challengeID flow.Identifier) error {
+	err := operation.RetryOnConflict(c.db.Update, operation.RemoveUnresolvedChunkChallenge(challengeID))
+	if err != nil {
+		return fmt.Errorf("could not resolve chunk challenge: %w", err)
+	}
+	return nil
+}
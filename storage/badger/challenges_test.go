@@ -0,0 +1,66 @@
+package badger_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestChunkChallengeStoreAndRetrieve(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := bstorage.NewChunkChallenges(db)
+
+		challenge := unittest.ChunkChallengeFixture()
+		err := store.Store(challenge)
+		require.NoError(t, err)
+
+		byID, err := store.ByID(challenge.ID())
+		require.NoError(t, err)
+		require.Equal(t, challenge.Checksum(), byID.Checksum())
+
+		unresolved, err := store.Unresolved()
+		require.NoError(t, err)
+		require.Len(t, unresolved, 1)
+		require.Equal(t, challenge.ID(), unresolved[0].ID())
+	})
+}
+
+func TestChunkChallengeResolve(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := bstorage.NewChunkChallenges(db)
+
+		challenge := unittest.ChunkChallengeFixture()
+		other := unittest.ChunkChallengeFixture()
+		require.NoError(t, store.Store(challenge))
+		require.NoError(t, store.Store(other))
+
+		err := store.Resolve(challenge.ID())
+		require.NoError(t, err)
+
+		// the resolved challenge is kept as evidence
+		_, err = store.ByID(challenge.ID())
+		require.NoError(t, err)
+
+		unresolved, err := store.Unresolved()
+		require.NoError(t, err)
+		require.Len(t, unresolved, 1)
+		require.Equal(t, other.ID(), unresolved[0].ID())
+
+		// resolving twice fails
+		err = store.Resolve(challenge.ID())
+		require.True(t, errors.Is(err, storage.ErrNotFound))
+
+		// storing a resolved challenge again does not re-open it
+		require.NoError(t, store.Store(challenge))
+		unresolved, err = store.Unresolved()
+		require.NoError(t, err)
+		require.Equal(t, []flow.Identifier{other.ID()}, flow.GetIDs(unresolved))
+	})
+}
@@ -1,6 +1,7 @@
 package badger
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/dgraph-io/badger/v2"
@@ -70,6 +71,22 @@ func (ch *ChunkDataPacks) Remove(chunkID flow.Identifier) error {
 	return nil
 }
 
+// RemoveTx removes the chunk data pack with the given chunk ID in a transaction,
+// and evicts it from the cache once the transaction succeeded. Removing a chunk
+// data pack which is not stored is a no-op.
+func (ch *ChunkDataPacks) RemoveTx(chunkID flow.Identifier) func(*transaction.Tx) error {
+	return func(tx *transaction.Tx) error {
+		err := operation.RemoveChunkDataPack(chunkID)(tx.DBTxn)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("could not remove chunk data pack: %w", err)
+		}
+		tx.OnSucceed(func() {
+			ch.byChunkIDCache.Remove(chunkID)
+		})
+		return nil
+	}
+}
+
 func (ch *ChunkDataPacks) BatchStore(c *flow.ChunkDataPack, batch storage.BatchStorage) error {
 	sc := toStoredChunkDataPack(c)
 	writeBatch := batch.GetWriter()
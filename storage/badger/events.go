@@ -10,6 +10,7 @@ import (
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/badger/transaction"
 )
 
 type Events struct {
@@ -93,6 +94,21 @@ func (e *Events) ByBlockIDTransactionID(blockID flow.Identifier, txID flow.Ident
 	return matched, nil
 }
 
+// RemoveByBlockIDTx removes the events for the given block ID in a transaction,
+// and evicts them from the cache once the transaction succeeded.
+func (e *Events) RemoveByBlockIDTx(blockID flow.Identifier) func(*transaction.Tx) error {
+	return func(tx *transaction.Tx) error {
+		err := operation.RemoveEventsByBlockID(blockID)(tx.DBTxn)
+		if err != nil {
+			return fmt.Errorf("could not remove events: %w", err)
+		}
+		tx.OnSucceed(func() {
+			e.cache.Remove(blockID)
+		})
+		return nil
+	}
+}
+
 // ByBlockIDEventType returns the events for the given block ID and event type
 func (e *Events) ByBlockIDEventType(blockID flow.Identifier, eventType flow.EventType) ([]flow.Event, error) {
 	events, err := e.ByBlockID(blockID)
@@ -158,3 +174,18 @@ func (e *ServiceEvents) ByBlockID(blockID flow.Identifier) ([]flow.Event, error)
 	}
 	return val.([]flow.Event), nil
 }
+
+// RemoveByBlockIDTx removes the service events for the given block ID in a
+// transaction, and evicts them from the cache once the transaction succeeded.
+func (e *ServiceEvents) RemoveByBlockIDTx(blockID flow.Identifier) func(*transaction.Tx) error {
+	return func(tx *transaction.Tx) error {
+		err := operation.RemoveServiceEventsByBlockID(blockID)(tx.DBTxn)
+		if err != nil {
+			return fmt.Errorf("could not remove service events: %w", err)
+		}
+		tx.OnSucceed(func() {
+			e.cache.Remove(blockID)
+		})
+		return nil
+	}
+}
@@ -3,6 +3,7 @@
 package badger
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/dgraph-io/badger/v2"
@@ -100,6 +101,16 @@ func (h *Headers) storeTx(header *flow.Header) func(*transaction.Tx) error {
 func (h *Headers) retrieveTx(blockID flow.Identifier) func(*badger.Txn) (*flow.Header, error) {
 	return func(tx *badger.Txn) (*flow.Header, error) {
 		val, err := h.cache.Get(blockID)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			var height uint64
+			prunedErr := operation.LookupPrunedBlockHeight(blockID, &height)(tx)
+			if prunedErr == nil {
+				return nil, fmt.Errorf("block %x at height %d was pruned: %w", blockID, height, storage.ErrPruned)
+			}
+			if !errors.Is(prunedErr, storage.ErrNotFound) {
+				return nil, fmt.Errorf("could not check whether block %x is pruned: %w", blockID, prunedErr)
+			}
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -110,6 +121,15 @@ func (h *Headers) retrieveTx(blockID flow.Identifier) func(*badger.Txn) (*flow.H
 func (h *Headers) retrieveIdByHeightTx(height uint64) func(*badger.Txn) (flow.Identifier, error) {
 	return func(tx *badger.Txn) (flow.Identifier, error) {
 		blockID, err := h.heightCache.Get(height)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			pruned, prunedErr := isPrunedHeight(height)(tx)
+			if prunedErr != nil {
+				return flow.ZeroID, fmt.Errorf("could not check whether height %d is pruned: %w", height, prunedErr)
+			}
+			if pruned {
+				return flow.ZeroID, fmt.Errorf("block at height %d was pruned: %w", height, storage.ErrPruned)
+			}
+		}
 		if err != nil {
 			return flow.ZeroID, fmt.Errorf("failed to retrieve block ID for height %d: %w", height, err)
 		}
@@ -117,6 +137,27 @@ func (h *Headers) retrieveIdByHeightTx(height uint64) func(*badger.Txn) (flow.Id
 	}
 }
 
+// isPrunedHeight returns whether the finalized block at the given height was
+// pruned. The root block is never pruned.
+func isPrunedHeight(height uint64) func(*badger.Txn) (bool, error) {
+	return func(tx *badger.Txn) (bool, error) {
+		var pruned uint64
+		err := operation.RetrieveProtocolPrunedHeight(&pruned)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("could not retrieve pruned height: %w", err)
+		}
+		var root uint64
+		err = operation.RetrieveRootHeight(&root)(tx)
+		if err != nil {
+			return false, fmt.Errorf("could not retrieve root height: %w", err)
+		}
+		return height > root && height <= pruned, nil
+	}
+}
+
 func (h *Headers) Store(header *flow.Header) error {
 	return operation.RetryOnConflictTx(h.db, transaction.Update, h.storeTx(header))
 }
@@ -138,6 +179,19 @@ func (h *Headers) ByHeight(height uint64) (*flow.Header, error) {
 	return h.retrieveTx(blockID)(tx)
 }
 
+// EvictTx evicts the given finalized header and its height from the caches once
+// the transaction succeeded, such that a header pruned in the transaction is not
+// served from the caches anymore.
+func (h *Headers) EvictTx(header *flow.Header) func(*transaction.Tx) error {
+	return func(tx *transaction.Tx) error {
+		tx.OnSucceed(func() {
+			h.cache.Remove(header.ID())
+			h.heightCache.Remove(header.Height)
+		})
+		return nil
+	}
+}
+
 func (h *Headers) ByParentID(parentID flow.Identifier) ([]*flow.Header, error) {
 	var blockIDs []flow.Identifier
 	err := h.db.View(procedure.LookupBlockChildren(parentID, &blockIDs))
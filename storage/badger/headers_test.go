@@ -14,6 +14,7 @@ import (
 	"github.com/onflow/flow-go/utils/unittest"
 
 	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/transaction"
 )
 
 func TestHeaderStoreRetrieve(t *testing.T) {
@@ -50,3 +51,76 @@ func TestHeaderRetrieveWithoutStore(t *testing.T) {
 		require.True(t, errors.Is(err, storage.ErrNotFound))
 	})
 }
+
+func TestHeaderRetrievePruned(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		metrics := metrics.NewNoopCollector()
+		headers := badgerstorage.NewHeaders(metrics, db)
+
+		err := db.Update(operation.InsertRootHeight(10))
+		require.NoError(t, err)
+		err = db.Update(operation.InsertProtocolPrunedHeight(20))
+		require.NoError(t, err)
+
+		// retrieve header by height, should err as pruned for heights above the
+		// root height up to the pruned height
+		_, err = headers.ByHeight(15)
+		require.True(t, errors.Is(err, storage.ErrPruned))
+		_, err = headers.ByHeight(20)
+		require.True(t, errors.Is(err, storage.ErrPruned))
+
+		// heights which were never pruned are not found
+		_, err = headers.ByHeight(10)
+		require.True(t, errors.Is(err, storage.ErrNotFound))
+		_, err = headers.ByHeight(21)
+		require.True(t, errors.Is(err, storage.ErrNotFound))
+
+		// retrieve header by ID, should err as pruned for pruned blocks
+		blockID := unittest.IdentifierFixture()
+		err = db.Update(operation.IndexPrunedBlockID(blockID, 15))
+		require.NoError(t, err)
+		_, err = headers.ByBlockID(blockID)
+		require.True(t, errors.Is(err, storage.ErrPruned))
+
+		// unknown blocks are not found
+		_, err = headers.ByBlockID(unittest.IdentifierFixture())
+		require.True(t, errors.Is(err, storage.ErrNotFound))
+	})
+}
+
+func TestHeaderRetrieveEvicted(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		metrics := metrics.NewNoopCollector()
+		headers := badgerstorage.NewHeaders(metrics, db)
+
+		block := unittest.BlockFixture()
+		block.Header.Height = 15
+		err := headers.Store(block.Header)
+		require.NoError(t, err)
+		err = db.Update(operation.IndexBlockHeight(block.Header.Height, block.ID()))
+		require.NoError(t, err)
+		err = db.Update(operation.InsertRootHeight(10))
+		require.NoError(t, err)
+		err = db.Update(operation.InsertProtocolPrunedHeight(10))
+		require.NoError(t, err)
+
+		// cache the header by height
+		_, err = headers.ByHeight(block.Header.Height)
+		require.NoError(t, err)
+
+		// prune the header and evict it in the same transaction
+		err = transaction.Update(db, func(tx *transaction.Tx) error {
+			require.NoError(t, operation.RemoveHeader(block.ID())(tx.DBTxn))
+			require.NoError(t, operation.RemoveBlockHeight(block.Header.Height)(tx.DBTxn))
+			require.NoError(t, operation.UpdateProtocolPrunedHeight(block.Header.Height)(tx.DBTxn))
+			return headers.EvictTx(block.Header)(tx)
+		})
+		require.NoError(t, err)
+
+		// the pruned header is not served from the caches anymore
+		_, err = headers.ByHeight(block.Header.Height)
+		require.True(t, errors.Is(err, storage.ErrPruned))
+		_, err = headers.ByBlockID(block.ID())
+		require.True(t, errors.Is(err, storage.ErrNotFound))
+	})
+}
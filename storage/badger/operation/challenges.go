@@ -0,0 +1,47 @@
+package operation
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// InsertChunkChallenge inserts a chunk challenge by ID.
+func InsertChunkChallenge(challenge *flow.ChunkChallenge) func(*badger.Txn) error {
+	return insert(makePrefix(codeChunkChallenge, challenge.ID()), challenge)
+}
+
+// RetrieveChunkChallenge retrieves a chunk challenge by ID.
+func RetrieveChunkChallenge(challengeID flow.Identifier, challenge *flow.ChunkChallenge) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeChunkChallenge, challengeID), challenge)
+}
+
+// IndexUnresolvedChunkChallenge indexes a chunk challenge as not resolved yet.
+func IndexUnresolvedChunkChallenge(challengeID flow.Identifier) func(*badger.Txn) error {
+	return insert(makePrefix(codeUnresolvedChunkChallenge, challengeID), challengeID)
+}
+
+// RemoveUnresolvedChunkChallenge removes a chunk challenge from the index of
+// unresolved challenges.
+func RemoveUnresolvedChunkChallenge(challengeID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeUnresolvedChunkChallenge, challengeID))
+}
+
+// LookupUnresolvedChunkChallenges finds the IDs of all chunk challenges which
+// are not resolved yet.
+func LookupUnresolvedChunkChallenges(challengeIDs *[]flow.Identifier) func(*badger.Txn) error {
+	return traverse(makePrefix(codeUnresolvedChunkChallenge), func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			return true
+		}
+		var challengeID flow.Identifier
+		create := func() interface{} {
+			return &challengeID
+		}
+		handle := func() error {
+			*challengeIDs = append(*challengeIDs, challengeID)
+			return nil
+		}
+		return check, create, handle
+	})
+}
@@ -44,3 +44,8 @@ func IndexCollectionByTransaction(txID flow.Identifier, collectionID flow.Identi
 func RetrieveCollectionID(txID flow.Identifier, collectionID *flow.Identifier) func(*badger.Txn) error {
 	return retrieve(makePrefix(codeIndexCollectionByTransaction, txID), collectionID)
 }
+
+// RemoveCollectionByTransaction removes the collection id keyed by a transaction id
+func RemoveCollectionByTransaction(txID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeIndexCollectionByTransaction, txID))
+}
@@ -376,6 +376,40 @@ func TestRemove(t *testing.T) {
 	})
 }
 
+func TestRemoveByPrefix(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		keys := [][]byte{{0x42, 0x00}, {0xff}, {0x42, 0x56}, {0x00}, {0x42, 0xff}}
+
+		_ = db.Update(func(tx *badger.Txn) error {
+			for _, key := range keys {
+				err := tx.Set(key, []byte{0x01})
+				require.NoError(t, err)
+			}
+			return nil
+		})
+
+		err := db.Update(removeByPrefix([]byte{0x42}))
+		require.NoError(t, err)
+
+		_ = db.View(func(tx *badger.Txn) error {
+			for _, key := range keys {
+				_, err := tx.Get(key)
+				if key[0] == 0x42 {
+					assert.ErrorIs(t, err, badger.ErrKeyNotFound)
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+			return nil
+		})
+
+		t.Run("should not error when no key has the prefix", func(t *testing.T) {
+			err := db.Update(removeByPrefix([]byte{0x42}))
+			assert.NoError(t, err)
+		})
+	})
+}
+
 func TestIterateBoundaries(t *testing.T) {
 
 	// create range of keys covering all boundaries around our start/end values
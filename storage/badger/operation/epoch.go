@@ -29,3 +29,7 @@ func InsertEpochStatus(blockID flow.Identifier, status *flow.EpochStatus) func(*
 func RetrieveEpochStatus(blockID flow.Identifier, status *flow.EpochStatus) func(*badger.Txn) error {
 	return retrieve(makePrefix(codeBlockEpochStatus, blockID), status)
 }
+
+func RemoveEpochStatus(blockID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeBlockEpochStatus, blockID))
+}
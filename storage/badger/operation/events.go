@@ -48,6 +48,16 @@ func LookupEventsByBlockIDEventType(blockID flow.Identifier, eventType flow.Even
 	return traverse(makePrefix(codeEvent, blockID), iterationFunc)
 }
 
+// RemoveEventsByBlockID removes all events of the given block.
+func RemoveEventsByBlockID(blockID flow.Identifier) func(*badger.Txn) error {
+	return removeByPrefix(makePrefix(codeEvent, blockID))
+}
+
+// RemoveServiceEventsByBlockID removes all service events of the given block.
+func RemoveServiceEventsByBlockID(blockID flow.Identifier) func(*badger.Txn) error {
+	return removeByPrefix(makePrefix(codeServiceEvent, blockID))
+}
+
 // eventIterationFunc returns an in iteration function which returns all events found during traversal or iteration
 func eventIterationFunc(events *[]flow.Event) func() (checkFunc, createFunc, handleFunc) {
 	return func() (checkFunc, createFunc, handleFunc) {
@@ -93,3 +93,23 @@ func FindHeaders(filter func(header *flow.Header) bool, found *[]flow.Header) fu
 		return check, create, handle
 	})
 }
+
+// RemoveHeader removes the header of the given block.
+func RemoveHeader(blockID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeHeader, blockID))
+}
+
+// RemoveBlockHeight removes the index of the finalized block at the given height.
+func RemoveBlockHeight(height uint64) func(*badger.Txn) error {
+	return remove(makePrefix(codeHeightToBlock, height))
+}
+
+// RemoveBlockValidity removes the validity of the given block.
+func RemoveBlockValidity(blockID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeBlockValidity, blockID))
+}
+
+// RemoveCollectionBlock removes the index of the block containing the given collection.
+func RemoveCollectionBlock(collID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeCollectionBlock, collID))
+}
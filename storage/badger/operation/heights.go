@@ -49,3 +49,75 @@ func UpdateLastCompleteBlockHeight(height uint64) func(*badger.Txn) error {
 func RetrieveLastCompleteBlockHeight(height *uint64) func(*badger.Txn) error {
 	return retrieve(makePrefix(codeLastCompleteBlockHeight), height)
 }
+
+// InsertRegisterHistoryHeight inserts the height from which on the history of
+// register values is complete.
+func InsertRegisterHistoryHeight(height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codeRegisterHistoryHeight), height)
+}
+
+// RetrieveRegisterHistoryHeight retrieves the height from which on the history
+// of register values is complete.
+func RetrieveRegisterHistoryHeight(height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeRegisterHistoryHeight), height)
+}
+
+// UpdateRegisterHistoryHeight updates the height from which on the history of
+// register values is complete.
+func UpdateRegisterHistoryHeight(height uint64) func(*badger.Txn) error {
+	return update(makePrefix(codeRegisterHistoryHeight), height)
+}
+
+// InsertRegisterHistoryPrunedHeight inserts the height up to which the history
+// of register values was pruned.
+func InsertRegisterHistoryPrunedHeight(height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codeRegisterHistoryPruned), height)
+}
+
+// UpdateRegisterHistoryPrunedHeight updates the height up to which the history
+// of register values was pruned.
+func UpdateRegisterHistoryPrunedHeight(height uint64) func(*badger.Txn) error {
+	return update(makePrefix(codeRegisterHistoryPruned), height)
+}
+
+// RetrieveRegisterHistoryPrunedHeight retrieves the height up to which the
+// history of register values was pruned.
+func RetrieveRegisterHistoryPrunedHeight(height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeRegisterHistoryPruned), height)
+}
+
+// InsertExecutionDataPrunedHeight inserts the height up to which the execution
+// data of blocks was pruned.
+func InsertExecutionDataPrunedHeight(height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codeExecutionDataPruned), height)
+}
+
+// UpdateExecutionDataPrunedHeight updates the height up to which the execution
+// data of blocks was pruned.
+func UpdateExecutionDataPrunedHeight(height uint64) func(*badger.Txn) error {
+	return update(makePrefix(codeExecutionDataPruned), height)
+}
+
+// RetrieveExecutionDataPrunedHeight retrieves the height up to which the
+// execution data of blocks was pruned.
+func RetrieveExecutionDataPrunedHeight(height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeExecutionDataPruned), height)
+}
+
+// InsertProtocolPrunedHeight inserts the height up to which finalized blocks
+// were pruned.
+func InsertProtocolPrunedHeight(height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codeProtocolPrunedHeight), height)
+}
+
+// UpdateProtocolPrunedHeight updates the height up to which finalized blocks
+// were pruned.
+func UpdateProtocolPrunedHeight(height uint64) func(*badger.Txn) error {
+	return update(makePrefix(codeProtocolPrunedHeight), height)
+}
+
+// RetrieveProtocolPrunedHeight retrieves the height up to which finalized
+// blocks were pruned.
+func RetrieveProtocolPrunedHeight(height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeProtocolPrunedHeight), height)
+}
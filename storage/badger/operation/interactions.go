@@ -14,3 +14,9 @@ func InsertExecutionStateInteractions(blockID flow.Identifier, interactions []*d
 func RetrieveExecutionStateInteractions(blockID flow.Identifier, interactions *[]*delta.Snapshot) func(*badger.Txn) error {
 	return retrieve(makePrefix(codeExecutionStateInteractions, blockID), interactions)
 }
+
+// RemoveExecutionStateInteractions removes the register interactions of the
+// given block.
+func RemoveExecutionStateInteractions(blockID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeExecutionStateInteractions, blockID))
+}
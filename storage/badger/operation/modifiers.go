@@ -21,6 +21,18 @@ func SkipDuplicates(op func(*badger.Txn) error) func(tx *badger.Txn) error {
 	}
 }
 
+// SkipNonExist ignores the error of an operation on an entity which does not
+// exist, such as the removal of an entity which was not stored.
+func SkipNonExist(op func(*badger.Txn) error) func(tx *badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		err := op(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+}
+
 func RetryOnConflict(action func(func(*badger.Txn) error) error, op func(tx *badger.Txn) error) error {
 	for {
 		err := action(op)
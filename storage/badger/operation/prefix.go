@@ -18,6 +18,7 @@ const (
 	codeStartedView           = 10 // latest view hotstuff started
 	codeVotedView             = 11 // latest view hotstuff voted on
 	codeRootQuorumCertificate = 12
+	codeHighestTimeoutCert    = 13 // highest timeout certificate hotstuff observed
 
 	// code for heights with special meaning
 	codeFinalizedHeight         = 20 // latest finalized block height
@@ -26,6 +27,10 @@ const (
 	codeExecutedBlock           = 23 // latest executed block with max height
 	codeRootHeight              = 24 // the height of the first loaded block
 	codeLastCompleteBlockHeight = 25 // the height of the last block for which all collections were received
+	codeRegisterHistoryHeight   = 26 // the height from which on the register history is complete
+	codeExecutionDataPruned     = 27 // the height up to which execution data was pruned
+	codeProtocolPrunedHeight    = 28 // the height up to which finalized blocks were pruned
+	codeRegisterHistoryPruned   = 29 // the height up to which the register history was pruned
 
 	// codes for single entity storage
 	// 31 was used for identities before epochs
@@ -38,6 +43,7 @@ const (
 	codeExecutionReceiptMeta = 36
 	codeResultApproval       = 37
 	codeChunk                = 38
+	codeChunkChallenge       = 39
 
 	// codes for indexing single identifier by identifier
 	codeHeightToBlock       = 40 // index mapping height to block ID
@@ -81,6 +87,23 @@ const (
 	codeIndexCollectionByTransaction = 203
 	codeIndexResultApprovalByChunk   = 204
 
+	// codes for the history of the execution state
+	codeRegisterHistory = 110 // index mapping register ID, height and block ID to register value
+	codeRegisterChange  = 111 // index of the IDs of the registers written by a block, keyed by height and block ID
+
+	// codes for the pruning of the protocol state
+	codePrunedBlock   = 120 // index mapping height to ID of pruned blocks whose receipts and results are not pruned yet
+	codePrunedBlockID = 121 // index mapping ID to height of all pruned blocks
+
+	// codes for chunk challenges
+	codeUnresolvedChunkChallenge = 130 // index of the IDs of chunk challenges which are not resolved yet
+
+	// codes for slashing evidence
+	codeSlashingEvidence = 140 // slashing evidence, keyed by offender ID, view and violation
+
+	// codes for the collection transaction journal
+	codeJournaledTransaction = 150 // transactions pending inclusion in a collection, keyed by epoch and transaction ID
+
 	// internal failure information that should be preserved across restarts
 	codeExecutionFork = 254
 )
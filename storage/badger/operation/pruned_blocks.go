@@ -0,0 +1,54 @@
+package operation
+
+import (
+	"encoding/binary"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// IndexPrunedBlock indexes a pruned finalized block by its height, until the
+// execution receipts and results of the block are pruned as well.
+func IndexPrunedBlock(height uint64, blockID flow.Identifier) func(*badger.Txn) error {
+	return insert(makePrefix(codePrunedBlock, height), blockID)
+}
+
+// RemovePrunedBlock removes the index of the pruned block at the given height.
+func RemovePrunedBlock(height uint64) func(*badger.Txn) error {
+	return remove(makePrefix(codePrunedBlock, height))
+}
+
+// LookupPrunedBlocks retrieves the heights and IDs of the indexed pruned blocks,
+// in ascending order of height.
+func LookupPrunedBlocks(heights *[]uint64, blockIDs *[]flow.Identifier) func(*badger.Txn) error {
+	return traverse(makePrefix(codePrunedBlock), func() (checkFunc, createFunc, handleFunc) {
+		var height uint64
+		check := func(key []byte) bool {
+			height = binary.BigEndian.Uint64(key[1:])
+			return true
+		}
+		var blockID flow.Identifier
+		create := func() interface{} {
+			return &blockID
+		}
+		handle := func() error {
+			*heights = append(*heights, height)
+			*blockIDs = append(*blockIDs, blockID)
+			return nil
+		}
+		return check, create, handle
+	})
+}
+
+// IndexPrunedBlockID indexes the height of a pruned finalized block by its ID.
+// The index is kept, so that lookups of pruned blocks by ID can tell them apart
+// from unknown blocks.
+func IndexPrunedBlockID(blockID flow.Identifier, height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codePrunedBlockID, blockID), height)
+}
+
+// LookupPrunedBlockHeight retrieves the height of the pruned block with the given ID.
+func LookupPrunedBlockHeight(blockID flow.Identifier, height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codePrunedBlockID, blockID), height)
+}
@@ -0,0 +1,45 @@
+package operation
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestPrunedBlocks(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		blockIDs := unittest.IdentifierListFixture(3)
+		heights := []uint64{300, 5, 256}
+		for i, blockID := range blockIDs {
+			require.NoError(t, db.Update(IndexPrunedBlock(heights[i], blockID)))
+		}
+		require.NoError(t, db.Update(RemovePrunedBlock(256)))
+
+		var actualHeights []uint64
+		var actualIDs []flow.Identifier
+		require.NoError(t, db.View(LookupPrunedBlocks(&actualHeights, &actualIDs)))
+		assert.Equal(t, []uint64{5, 300}, actualHeights)
+		assert.Equal(t, []flow.Identifier{blockIDs[1], blockIDs[0]}, actualIDs)
+	})
+}
+
+func TestPrunedBlockIDs(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		blockID := unittest.IdentifierFixture()
+		require.NoError(t, db.Update(IndexPrunedBlockID(blockID, 42)))
+
+		var height uint64
+		require.NoError(t, db.View(LookupPrunedBlockHeight(blockID, &height)))
+		assert.Equal(t, uint64(42), height)
+
+		err := db.View(LookupPrunedBlockHeight(unittest.IdentifierFixture(), &height))
+		assert.True(t, errors.Is(err, storage.ErrNotFound))
+	})
+}
@@ -71,3 +71,13 @@ func receiptIterationFunc(receiptIDs *[]flow.Identifier) func() (checkFunc, crea
 		return check, create, handle
 	}
 }
+
+// RemoveExecutionReceiptMeta removes an execution receipt meta by ID.
+func RemoveExecutionReceiptMeta(receiptID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeExecutionReceiptMeta, receiptID))
+}
+
+// RemoveExecutionReceipts removes the index of all execution receipts of the given block.
+func RemoveExecutionReceipts(blockID flow.Identifier) func(*badger.Txn) error {
+	return removeByPrefix(makePrefix(codeAllBlockReceipts, blockID))
+}
@@ -0,0 +1,215 @@
+package operation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/vmihailenco/msgpack/v4"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// registerPrefix returns the prefix of the history of the given register.
+func registerPrefix(registerID flow.RegisterID) []byte {
+	return append(makePrefix(codeRegisterHistory), registerKey(registerID)...)
+}
+
+// registerKey returns the key of the given register. The parts of the register
+// ID are length-prefixed, so that the key of one register is never a prefix of
+// the key of another register.
+func registerKey(registerID flow.RegisterID) []byte {
+	var key []byte
+	for _, part := range []string{registerID.Owner, registerID.Controller, registerID.Key} {
+		length := make([]byte, 2)
+		binary.BigEndian.PutUint16(length, uint16(len(part)))
+		key = append(key, length...)
+		key = append(key, part...)
+	}
+	return key
+}
+
+// BatchIndexRegister indexes the value of a register written by the block with
+// the given height and ID into a batch.
+//
+// As blocks are indexed before they are finalized, the history of a register
+// can contain values written by several blocks at the same height.
+func BatchIndexRegister(registerID flow.RegisterID, height uint64, blockID flow.Identifier, value flow.RegisterValue) func(batch *badger.WriteBatch) error {
+	key := append(registerPrefix(registerID), b(height)...)
+	key = append(key, b(blockID)...)
+	return batchInsert(key, value)
+}
+
+// BatchIndexRegisterChange indexes that a register was written by the block
+// with the given height and ID into a batch. The registers written at a height
+// are looked up by this index when pruning the register history.
+func BatchIndexRegisterChange(registerID flow.RegisterID, height uint64, blockID flow.Identifier) func(batch *badger.WriteBatch) error {
+	key := append(makePrefix(codeRegisterChange, height, blockID), registerKey(registerID)...)
+	return batchInsert(key, nil)
+}
+
+// LookupRegisterAtHeight retrieves the value of a register at the given height,
+// i.e. the value written by the finalized block with the highest height lower
+// than or equal to the given height. Values written by blocks which are not
+// finalized are skipped.
+//
+// Returns storage.ErrNotFound if no finalized block up to the given height has
+// written the register.
+func LookupRegisterAtHeight(registerID flow.RegisterID, height uint64, value *flow.RegisterValue) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		prefix := registerPrefix(registerID)
+
+		options := badger.DefaultIteratorOptions
+		options.Reverse = true
+		options.Prefix = prefix
+		it := tx.NewIterator(options)
+		defer it.Close()
+
+		// seek to the last block ID at the given height
+		start := append(append([]byte{}, prefix...), b(height)...)
+		start = append(start, b(flow.Identifier{
+			0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+			0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+		})...)
+
+		for it.Seek(start); it.ValidForPrefix(prefix); it.Next() {
+			item := it.Item()
+			key := item.Key()
+			if len(key) != len(start) {
+				return fmt.Errorf("invalid register history key length: %d", len(key))
+			}
+
+			blockHeight := binary.BigEndian.Uint64(key[len(prefix):])
+			var blockID flow.Identifier
+			copy(blockID[:], key[len(prefix)+8:])
+
+			// skip values written by blocks which are not finalized
+			var finalizedID flow.Identifier
+			err := LookupBlockHeight(blockHeight, &finalizedID)(tx)
+			if errors.Is(err, storage.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("could not look up finalized block at height %d: %w", blockHeight, err)
+			}
+			if finalizedID != blockID {
+				continue
+			}
+
+			return item.Value(func(val []byte) error {
+				err := msgpack.Unmarshal(val, value)
+				if err != nil {
+					return fmt.Errorf("could not decode register value: %w", err)
+				}
+				return nil
+			})
+		}
+
+		return storage.ErrNotFound
+	}
+}
+
+// BatchPruneRegisterHistory adds the deletion of the register history entries
+// which are obsolete once the history starts at the given height to a batch:
+//   - the values written at the given height by blocks other than the given
+//     finalized block, as they are never read,
+//   - the values of registers written by the finalized block at lower heights,
+//     as lookups at the given height or above find the newer value, and
+//   - the values removed by the finalized block, as lookups find no value
+//     once the lower heights are deleted.
+//
+// The changes indexed at the given height are deleted too.
+func BatchPruneRegisterHistory(height uint64, finalizedID flow.Identifier, batch *badger.WriteBatch) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		changesPrefix := makePrefix(codeRegisterChange, height)
+
+		options := badger.DefaultIteratorOptions
+		options.PrefetchValues = false
+		options.Prefix = changesPrefix
+		it := tx.NewIterator(options)
+		defer it.Close()
+
+		var changes [][]byte
+		for it.Seek(changesPrefix); it.ValidForPrefix(changesPrefix); it.Next() {
+			changes = append(changes, it.Item().KeyCopy(nil))
+		}
+
+		for _, change := range changes {
+			if len(change) < len(changesPrefix)+len(flow.ZeroID) {
+				return fmt.Errorf("invalid register change key length: %d", len(change))
+			}
+			var blockID flow.Identifier
+			copy(blockID[:], change[len(changesPrefix):])
+			prefix := append(makePrefix(codeRegisterHistory), change[len(changesPrefix)+len(flow.ZeroID):]...)
+			own := append(append(append([]byte{}, prefix...), b(height)...), b(blockID)...)
+
+			err := batch.Delete(change)
+			if err != nil {
+				return fmt.Errorf("could not delete register change: %w", err)
+			}
+
+			if blockID != finalizedID {
+				err = batch.Delete(own)
+				if err != nil {
+					return fmt.Errorf("could not delete register value: %w", err)
+				}
+				continue
+			}
+
+			err = batchDeleteRegisterValuesBelow(tx, batch, prefix, height)
+			if err != nil {
+				return fmt.Errorf("could not delete obsolete register values: %w", err)
+			}
+
+			var value flow.RegisterValue
+			item, err := tx.Get(own)
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("could not get register value: %w", err)
+			}
+			err = item.Value(func(val []byte) error {
+				return msgpack.Unmarshal(val, &value)
+			})
+			if err != nil {
+				return fmt.Errorf("could not decode register value: %w", err)
+			}
+			if len(value) == 0 {
+				err = batch.Delete(own)
+				if err != nil {
+					return fmt.Errorf("could not delete removed register value: %w", err)
+				}
+			}
+		}
+
+		return nil
+	}
+}
+
+// batchDeleteRegisterValuesBelow adds the deletion of all values in the history
+// of a register which were written below the given height to a batch.
+func batchDeleteRegisterValuesBelow(tx *badger.Txn, batch *badger.WriteBatch, prefix []byte, height uint64) error {
+	options := badger.DefaultIteratorOptions
+	options.PrefetchValues = false
+	options.Prefix = prefix
+	it := tx.NewIterator(options)
+	defer it.Close()
+
+	end := append(append([]byte{}, prefix...), b(height)...)
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		key := it.Item().KeyCopy(nil)
+		if bytes.Compare(key, end) >= 0 {
+			break
+		}
+		err := batch.Delete(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
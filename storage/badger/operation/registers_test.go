@@ -0,0 +1,158 @@
+package operation
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestRegisterHistory(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		registerID := flow.NewRegisterID("owner", "controller", "key")
+		// a register whose ID shares a prefix with the one above
+		otherID := flow.NewRegisterID("owner", "controller", "key2")
+
+		finalized := map[uint64]flow.Identifier{
+			3: unittest.IdentifierFixture(),
+			5: unittest.IdentifierFixture(),
+			6: unittest.IdentifierFixture(),
+		}
+		for height, blockID := range finalized {
+			require.NoError(t, db.Update(IndexBlockHeight(height, blockID)))
+		}
+		orphaned := unittest.IdentifierFixture()
+		unfinalized := unittest.IdentifierFixture()
+
+		writes := []struct {
+			registerID flow.RegisterID
+			height     uint64
+			blockID    flow.Identifier
+			value      flow.RegisterValue
+		}{
+			{registerID, 3, finalized[3], []byte("a")},
+			{registerID, 5, orphaned, []byte("orphaned")},
+			{registerID, 6, finalized[6], []byte("b")},
+			{registerID, 7, unfinalized, []byte("unfinalized")},
+			{otherID, 4, unittest.IdentifierFixture(), []byte("other")},
+			{otherID, 5, finalized[5], []byte("other")},
+		}
+		batch := db.NewWriteBatch()
+		for _, w := range writes {
+			require.NoError(t, BatchIndexRegister(w.registerID, w.height, w.blockID, w.value)(batch))
+		}
+		require.NoError(t, batch.Flush())
+
+		lookup := func(registerID flow.RegisterID, height uint64) (flow.RegisterValue, error) {
+			var value flow.RegisterValue
+			err := db.View(LookupRegisterAtHeight(registerID, height, &value))
+			return value, err
+		}
+
+		t.Run("before first write", func(t *testing.T) {
+			_, err := lookup(registerID, 2)
+			require.ErrorIs(t, err, storage.ErrNotFound)
+		})
+
+		t.Run("at and after finalized write", func(t *testing.T) {
+			for height, expected := range map[uint64]string{3: "a", 4: "a", 5: "a", 6: "b", 7: "b", 100: "b"} {
+				value, err := lookup(registerID, height)
+				require.NoError(t, err)
+				assert.Equal(t, expected, string(value), "height %d", height)
+			}
+		})
+
+		t.Run("other register", func(t *testing.T) {
+			_, err := lookup(otherID, 4)
+			require.ErrorIs(t, err, storage.ErrNotFound)
+
+			value, err := lookup(otherID, 6)
+			require.NoError(t, err)
+			assert.Equal(t, "other", string(value))
+		})
+	})
+}
+
+func TestPruneRegisterHistory(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		registerID := flow.NewRegisterID("owner", "controller", "key")
+		otherID := flow.NewRegisterID("owner", "controller", "key2")
+
+		finalized := map[uint64]flow.Identifier{
+			3: unittest.IdentifierFixture(),
+			5: unittest.IdentifierFixture(),
+			6: unittest.IdentifierFixture(),
+		}
+		for height, blockID := range finalized {
+			require.NoError(t, db.Update(IndexBlockHeight(height, blockID)))
+		}
+
+		writes := []struct {
+			registerID flow.RegisterID
+			height     uint64
+			blockID    flow.Identifier
+			value      flow.RegisterValue
+		}{
+			{registerID, 3, finalized[3], []byte("a")},
+			{registerID, 5, unittest.IdentifierFixture(), []byte("orphaned")},
+			{registerID, 6, finalized[6], []byte("b")},
+			{otherID, 5, finalized[5], []byte("other")},
+			{otherID, 6, finalized[6], nil},
+		}
+		batch := db.NewWriteBatch()
+		for _, w := range writes {
+			require.NoError(t, BatchIndexRegister(w.registerID, w.height, w.blockID, w.value)(batch))
+			require.NoError(t, BatchIndexRegisterChange(w.registerID, w.height, w.blockID)(batch))
+		}
+		require.NoError(t, batch.Flush())
+
+		prune := func(height uint64) {
+			batch := db.NewWriteBatch()
+			require.NoError(t, db.View(BatchPruneRegisterHistory(height, finalized[height], batch)))
+			require.NoError(t, batch.Flush())
+		}
+		lookup := func(registerID flow.RegisterID, height uint64) (flow.RegisterValue, error) {
+			var value flow.RegisterValue
+			err := db.View(LookupRegisterAtHeight(registerID, height, &value))
+			return value, err
+		}
+		count := func(code byte) int {
+			keys := 0
+			require.NoError(t, db.View(func(tx *badger.Txn) error {
+				options := badger.DefaultIteratorOptions
+				options.Prefix = makePrefix(code)
+				it := tx.NewIterator(options)
+				defer it.Close()
+				for it.Seek(options.Prefix); it.ValidForPrefix(options.Prefix); it.Next() {
+					keys++
+				}
+				return nil
+			}))
+			return keys
+		}
+
+		// pruning up to height 5 only deletes the orphaned value
+		for height := uint64(3); height <= 5; height++ {
+			prune(height)
+		}
+		assert.Equal(t, 4, count(codeRegisterHistory))
+		value, err := lookup(registerID, 5)
+		require.NoError(t, err)
+		assert.Equal(t, "a", string(value))
+
+		// pruning height 6 deletes the overwritten and the removed values
+		prune(6)
+		assert.Equal(t, 1, count(codeRegisterHistory))
+		assert.Equal(t, 0, count(codeRegisterChange))
+		value, err = lookup(registerID, 6)
+		require.NoError(t, err)
+		assert.Equal(t, "b", string(value))
+		_, err = lookup(otherID, 6)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
@@ -40,3 +40,13 @@ func BatchIndexExecutionResult(blockID flow.Identifier, resultID flow.Identifier
 func LookupExecutionResult(blockID flow.Identifier, resultID *flow.Identifier) func(*badger.Txn) error {
 	return retrieve(makePrefix(codeIndexExecutionResultByBlock, blockID), resultID)
 }
+
+// RemoveExecutionResult removes an execution result by ID.
+func RemoveExecutionResult(resultID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeExecutionResult, resultID))
+}
+
+// RemoveExecutionResultIndex removes the execution result ID keyed by block ID
+func RemoveExecutionResultIndex(blockID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeIndexExecutionResultByBlock, blockID))
+}
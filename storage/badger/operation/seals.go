@@ -14,6 +14,10 @@ func RetrieveSeal(sealID flow.Identifier, seal *flow.Seal) func(*badger.Txn) err
 	return retrieve(makePrefix(codeSeal, sealID), seal)
 }
 
+func RemoveSeal(sealID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeSeal, sealID))
+}
+
 func IndexPayloadSeals(blockID flow.Identifier, sealIDs []flow.Identifier) func(*badger.Txn) error {
 	return insert(makePrefix(codePayloadSeals, blockID), sealIDs)
 }
@@ -38,6 +42,18 @@ func LookupPayloadResults(blockID flow.Identifier, resultIDs *[]flow.Identifier)
 	return retrieve(makePrefix(codePayloadResults, blockID), resultIDs)
 }
 
+func RemovePayloadSeals(blockID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codePayloadSeals, blockID))
+}
+
+func RemovePayloadReceipts(blockID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codePayloadReceipts, blockID))
+}
+
+func RemovePayloadResults(blockID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codePayloadResults, blockID))
+}
+
 func IndexBlockSeal(blockID flow.Identifier, sealID flow.Identifier) func(*badger.Txn) error {
 	return insert(makePrefix(codeBlockToSeal, blockID), sealID)
 }
@@ -46,6 +62,10 @@ func LookupBlockSeal(blockID flow.Identifier, sealID *flow.Identifier) func(*bad
 	return retrieve(makePrefix(codeBlockToSeal, blockID), &sealID)
 }
 
+func RemoveBlockSeal(blockID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeBlockToSeal, blockID))
+}
+
 func InsertExecutionForkEvidence(conflictingSeals []*flow.IncorporatedResultSeal) func(*badger.Txn) error {
 	return insert(makePrefix(codeExecutionFork), conflictingSeals)
 }
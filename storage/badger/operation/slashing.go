@@ -0,0 +1,48 @@
+package operation
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// InsertSlashingEvidence inserts the evidence of a slashing violation, keyed by
+// the offender, the view, the kind of violation and the chain. The views of
+// cluster chains start over with each epoch, hence the chain is part of the key.
+func InsertSlashingEvidence(evidence *flow.SlashingEvidence) func(*badger.Txn) error {
+	return insert(makePrefix(codeSlashingEvidence, evidence.OffenderID, evidence.View, string(evidence.Violation), string(evidence.ChainID)), evidence)
+}
+
+// RetrieveSlashingEvidence retrieves the evidence of the given violation of the
+// offender in the given view on the given chain.
+func RetrieveSlashingEvidence(offenderID flow.Identifier, view uint64, violation flow.SlashingViolation, chainID flow.ChainID, evidence *flow.SlashingEvidence) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeSlashingEvidence, offenderID, view, string(violation), string(chainID)), evidence)
+}
+
+// LookupSlashingEvidenceByOffender retrieves the evidence of all violations of
+// the given offender, ordered by view.
+func LookupSlashingEvidenceByOffender(offenderID flow.Identifier, evidence *[]flow.SlashingEvidence) func(*badger.Txn) error {
+	return lookupSlashingEvidence(makePrefix(codeSlashingEvidence, offenderID), evidence)
+}
+
+// LookupSlashingEvidence retrieves the evidence of all violations.
+func LookupSlashingEvidence(evidence *[]flow.SlashingEvidence) func(*badger.Txn) error {
+	return lookupSlashingEvidence(makePrefix(codeSlashingEvidence), evidence)
+}
+
+func lookupSlashingEvidence(prefix []byte, evidence *[]flow.SlashingEvidence) func(*badger.Txn) error {
+	return traverse(prefix, func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			return true
+		}
+		var val flow.SlashingEvidence
+		create := func() interface{} {
+			return &val
+		}
+		handle := func() error {
+			*evidence = append(*evidence, val)
+			return nil
+		}
+		return check, create, handle
+	})
+}
@@ -0,0 +1,48 @@
+package operation
+
+import (
+	"encoding/binary"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// InsertJournaledTransaction journals a transaction pending inclusion in a
+// collection of the given epoch.
+func InsertJournaledTransaction(epoch uint64, tx *flow.TransactionBody) func(*badger.Txn) error {
+	return insert(makePrefix(codeJournaledTransaction, epoch, tx.ID()), tx)
+}
+
+// RemoveJournaledTransaction removes the transaction with the given ID from
+// the journal of the given epoch.
+func RemoveJournaledTransaction(epoch uint64, txID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeJournaledTransaction, epoch, txID))
+}
+
+// RemoveJournaledTransactionsByEpoch removes all journaled transactions of the
+// given epoch.
+func RemoveJournaledTransactionsByEpoch(epoch uint64) func(*badger.Txn) error {
+	return removeByPrefix(makePrefix(codeJournaledTransaction, epoch))
+}
+
+// LookupJournaledTransactions retrieves all journaled transactions, indexed by
+// the epoch they were journaled for.
+func LookupJournaledTransactions(transactions map[uint64][]*flow.TransactionBody) func(*badger.Txn) error {
+	return traverse(makePrefix(codeJournaledTransaction), func() (checkFunc, createFunc, handleFunc) {
+		var epoch uint64
+		check := func(key []byte) bool {
+			epoch = binary.BigEndian.Uint64(key[1:])
+			return true
+		}
+		var tx flow.TransactionBody
+		create := func() interface{} {
+			return &tx
+		}
+		handle := func() error {
+			transactions[epoch] = append(transactions[epoch], &tx)
+			return nil
+		}
+		return check, create, handle
+	})
+}
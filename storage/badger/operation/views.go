@@ -35,3 +35,18 @@ func UpdateVotedView(chainID flow.ChainID, view uint64) func(*badger.Txn) error
 func RetrieveVotedView(chainID flow.ChainID, view *uint64) func(*badger.Txn) error {
 	return retrieve(makePrefix(codeVotedView, chainID), view)
 }
+
+// InsertHighestTimeoutCertificate inserts a timeout certificate into the database.
+func InsertHighestTimeoutCertificate(chainID flow.ChainID, tc *flow.TimeoutCertificate) func(*badger.Txn) error {
+	return insert(makePrefix(codeHighestTimeoutCert, chainID), tc)
+}
+
+// UpdateHighestTimeoutCertificate updates the timeout certificate in the database.
+func UpdateHighestTimeoutCertificate(chainID flow.ChainID, tc *flow.TimeoutCertificate) func(*badger.Txn) error {
+	return update(makePrefix(codeHighestTimeoutCert, chainID), tc)
+}
+
+// RetrieveHighestTimeoutCertificate retrieves a timeout certificate from the database.
+func RetrieveHighestTimeoutCertificate(chainID flow.ChainID, tc *flow.TimeoutCertificate) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeHighestTimeoutCert, chainID), tc)
+}
@@ -0,0 +1,261 @@
+package procedure
+
+import (
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// PruneFinalizedBlock removes the given finalized block from the database, and
+// updates the pruned height to its height. Blocks have to be pruned in order of
+// height, starting with the child of the root block, which is never pruned.
+//
+// The data required by the remaining blocks is retained:
+//   - the seal which is the latest seal as of the pruned block, as it is the
+//     latest seal of the following blocks until they include a newer one
+//   - the execution receipts and results of the pruned block, until a block
+//     sealed after it is pruned, as they can be referenced by the payloads of
+//     the following blocks until then
+//   - the epoch setup and commit events, which are referenced by the epoch
+//     statuses of the following blocks
+//
+// The height of the pruned block remains indexed by its ID, so that lookups by
+// ID can report it as pruned.
+func PruneFinalizedBlock(header *flow.Header, rootID flow.Identifier) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		blockID := header.ID()
+
+		var index flow.Index
+		err := RetrieveIndex(blockID, &index)(tx)
+		if err != nil {
+			return fmt.Errorf("could not retrieve payload index: %w", err)
+		}
+
+		for _, collID := range index.CollectionIDs {
+			err = pruneCollection(collID)(tx)
+			if err != nil {
+				return fmt.Errorf("could not prune collection %x: %w", collID, err)
+			}
+		}
+
+		latestSeal, err := pruneSeals(blockID, header.ParentID, rootID, index.SealIDs)(tx)
+		if err != nil {
+			return fmt.Errorf("could not prune seals: %w", err)
+		}
+
+		err = prunePrunedBlocks(latestSeal.BlockID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not prune receipts and results of pruned blocks: %w", err)
+		}
+		err = operation.IndexPrunedBlock(header.Height, blockID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not index pruned block: %w", err)
+		}
+		err = operation.IndexPrunedBlockID(blockID, header.Height)(tx)
+		if err != nil {
+			return fmt.Errorf("could not index pruned block ID: %w", err)
+		}
+
+		ops := []func(*badger.Txn) error{
+			operation.RemovePayloadGuarantees(blockID),
+			operation.RemovePayloadSeals(blockID),
+			operation.RemovePayloadReceipts(blockID),
+			operation.RemovePayloadResults(blockID),
+			operation.RemoveHeader(blockID),
+			operation.RemoveBlockHeight(header.Height),
+			operation.SkipNonExist(operation.RemoveBlockChildren(blockID)),
+			operation.SkipNonExist(operation.RemoveBlockValidity(blockID)),
+			operation.SkipNonExist(operation.RemoveEpochStatus(blockID)),
+			operation.UpdateProtocolPrunedHeight(header.Height),
+		}
+		for _, op := range ops {
+			err = op(tx)
+			if err != nil {
+				return fmt.Errorf("could not prune block: %w", err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// pruneCollection removes the guarantee of a collection, and the collection and
+// its transactions if they are stored.
+func pruneCollection(collID flow.Identifier) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		var collection flow.LightCollection
+		err := operation.SkipNonExist(operation.RetrieveCollection(collID, &collection))(tx)
+		if err != nil {
+			return fmt.Errorf("could not retrieve collection: %w", err)
+		}
+
+		for _, txID := range collection.Transactions {
+			err = operation.SkipNonExist(operation.RemoveTransaction(txID))(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove transaction: %w", err)
+			}
+			err = operation.SkipNonExist(operation.RemoveCollectionByTransaction(txID))(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove transaction index: %w", err)
+			}
+		}
+
+		ops := []func(*badger.Txn) error{
+			operation.SkipNonExist(operation.RemoveCollection(collID)),
+			operation.SkipNonExist(operation.RemoveCollectionBlock(collID)),
+			operation.SkipNonExist(operation.RemoveGuarantee(collID)),
+		}
+		for _, op := range ops {
+			err = op(tx)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// pruneSeals removes the seals included in the payload of the pruned block, and
+// the latest seal as of its parent, except for the latest seal as of the pruned
+// block and the root seal. The latest seal of the parent is indexed until the
+// block is pruned, so that it can be found once it is superseded. It returns
+// the latest seal as of the pruned block.
+func pruneSeals(blockID flow.Identifier, parentID flow.Identifier, rootID flow.Identifier, payloadSealIDs []flow.Identifier) func(*badger.Txn) (*flow.Seal, error) {
+	return func(tx *badger.Txn) (*flow.Seal, error) {
+		var latestSealID flow.Identifier
+		err := operation.LookupBlockSeal(blockID, &latestSealID)(tx)
+		if err != nil {
+			return nil, fmt.Errorf("could not look up latest seal: %w", err)
+		}
+		var latestSeal flow.Seal
+		err = operation.RetrieveSeal(latestSealID, &latestSeal)(tx)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve latest seal: %w", err)
+		}
+		var rootSealID flow.Identifier
+		err = operation.LookupBlockSeal(rootID, &rootSealID)(tx)
+		if err != nil {
+			return nil, fmt.Errorf("could not look up root seal: %w", err)
+		}
+
+		sealIDs := append([]flow.Identifier{}, payloadSealIDs...)
+		if parentID != rootID {
+			var parentSealID flow.Identifier
+			err = operation.LookupBlockSeal(parentID, &parentSealID)(tx)
+			if err != nil {
+				return nil, fmt.Errorf("could not look up latest seal of parent: %w", err)
+			}
+			err = operation.RemoveBlockSeal(parentID)(tx)
+			if err != nil {
+				return nil, fmt.Errorf("could not remove latest seal index of parent: %w", err)
+			}
+			sealIDs = append(sealIDs, parentSealID)
+		}
+
+		for _, sealID := range sealIDs {
+			if sealID == latestSealID || sealID == rootSealID {
+				continue
+			}
+			err = operation.SkipNonExist(operation.RemoveSeal(sealID))(tx)
+			if err != nil {
+				return nil, fmt.Errorf("could not remove seal %x: %w", sealID, err)
+			}
+		}
+
+		return &latestSeal, nil
+	}
+}
+
+// prunePrunedBlocks removes the execution receipts and results of the pruned
+// blocks below the given sealed block. They can't be referenced by the payloads
+// of blocks which have the sealed block sealed, as receipts and results are only
+// included for unsealed blocks. The receipts and results of the sealed block
+// itself are retained, as its seal is still the latest seal of unpruned blocks.
+func prunePrunedBlocks(sealedID flow.Identifier) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		var heights []uint64
+		var blockIDs []flow.Identifier
+		err := operation.LookupPrunedBlocks(&heights, &blockIDs)(tx)
+		if err != nil {
+			return fmt.Errorf("could not look up pruned blocks: %w", err)
+		}
+
+		// if the sealed block is not indexed, it is not pruned yet, or its
+		// receipts and results were pruned already
+		sealed := -1
+		for i, blockID := range blockIDs {
+			if blockID == sealedID {
+				sealed = i
+				break
+			}
+		}
+
+		for i := 0; i < sealed; i++ {
+			err = pruneExecution(blockIDs[i])(tx)
+			if err != nil {
+				return fmt.Errorf("could not prune receipts and results of block %x: %w", blockIDs[i], err)
+			}
+			err = operation.RemovePrunedBlock(heights[i])(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove pruned block index: %w", err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// pruneExecution removes the execution receipts and results of a block.
+func pruneExecution(blockID flow.Identifier) func(*badger.Txn) error {
+	return func(tx *badger.Txn) error {
+		var receiptIDs []flow.Identifier
+		err := operation.LookupExecutionReceipts(blockID, &receiptIDs)(tx)
+		if err != nil {
+			return fmt.Errorf("could not look up receipts: %w", err)
+		}
+
+		for _, receiptID := range receiptIDs {
+			var meta flow.ExecutionReceiptMeta
+			err = operation.RetrieveExecutionReceiptMeta(receiptID, &meta)(tx)
+			if err != nil {
+				return fmt.Errorf("could not retrieve receipt %x: %w", receiptID, err)
+			}
+			err = operation.SkipNonExist(operation.RemoveExecutionResult(meta.ResultID))(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove result %x: %w", meta.ResultID, err)
+			}
+			err = operation.RemoveExecutionReceiptMeta(receiptID)(tx)
+			if err != nil {
+				return fmt.Errorf("could not remove receipt %x: %w", receiptID, err)
+			}
+		}
+		err = operation.RemoveExecutionReceipts(blockID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not remove receipts index: %w", err)
+		}
+
+		var resultID flow.Identifier
+		err = operation.SkipNonExist(operation.LookupExecutionResult(blockID, &resultID))(tx)
+		if err != nil {
+			return fmt.Errorf("could not look up result: %w", err)
+		}
+		if resultID != flow.ZeroID {
+			ops := []func(*badger.Txn) error{
+				operation.SkipNonExist(operation.RemoveExecutionResult(resultID)),
+				operation.RemoveExecutionResultIndex(blockID),
+			}
+			for _, op := range ops {
+				err = op(tx)
+				if err != nil {
+					return fmt.Errorf("could not remove result: %w", err)
+				}
+			}
+		}
+
+		return nil
+	}
+}
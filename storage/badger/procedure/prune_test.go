@@ -0,0 +1,171 @@
+package procedure
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestPruneFinalizedBlock(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		root := unittest.BlockHeaderFixture()
+		root.Height = 0
+		rootID := root.ID()
+		rootSeal := unittest.Seal.Fixture(unittest.Seal.WithBlockID(rootID))
+
+		require.NoError(t, db.Update(func(tx *badger.Txn) error {
+			require.NoError(t, operation.InsertHeader(rootID, &root)(tx))
+			require.NoError(t, operation.IndexBlockHeight(root.Height, rootID)(tx))
+			require.NoError(t, InsertIndex(rootID, &flow.Index{})(tx))
+			require.NoError(t, operation.InsertSeal(rootSeal.ID(), rootSeal)(tx))
+			require.NoError(t, operation.IndexBlockSeal(rootID, rootSeal.ID())(tx))
+			return operation.InsertProtocolPrunedHeight(root.Height)(tx)
+		}))
+
+		// every block contains a collection, the receipt for its parent and the
+		// seal for its grandparent
+		headers := []*flow.Header{&root}
+		collections := []*flow.Collection{nil}
+		receipts := []*flow.ExecutionReceipt{nil}
+		seals := []*flow.Seal{rootSeal}
+		for i := 1; i <= 6; i++ {
+			header := unittest.BlockHeaderWithParentFixture(headers[i-1])
+			blockID := header.ID()
+			collection := unittest.CollectionFixture(1)
+			light := collection.Light()
+			guarantee := collection.Guarantee()
+
+			receipt := unittest.ExecutionReceiptFixture(unittest.WithResult(
+				unittest.ExecutionResultFixture(unittest.WithExecutionResultBlockID(headers[i-1].ID())),
+			))
+			seal := unittest.Seal.Fixture(unittest.Seal.WithBlockID(blockID))
+
+			index := &flow.Index{
+				CollectionIDs: []flow.Identifier{guarantee.ID()},
+				ReceiptIDs:    []flow.Identifier{receipt.ID()},
+				ResultIDs:     []flow.Identifier{receipt.ExecutionResult.ID()},
+			}
+			latestSeal := rootSeal
+			if i >= 3 {
+				latestSeal = seals[i-2]
+				index.SealIDs = []flow.Identifier{latestSeal.ID()}
+			}
+
+			require.NoError(t, db.Update(func(tx *badger.Txn) error {
+				require.NoError(t, operation.InsertHeader(blockID, &header)(tx))
+				require.NoError(t, operation.IndexBlockHeight(header.Height, blockID)(tx))
+				require.NoError(t, InsertIndex(blockID, index)(tx))
+				require.NoError(t, operation.IndexBlockSeal(blockID, latestSeal.ID())(tx))
+				require.NoError(t, operation.InsertSeal(seal.ID(), seal)(tx))
+				require.NoError(t, operation.InsertGuarantee(guarantee.ID(), &guarantee)(tx))
+				require.NoError(t, operation.InsertCollection(&light)(tx))
+				require.NoError(t, operation.IndexCollectionBlock(guarantee.ID(), blockID)(tx))
+				for _, transaction := range collection.Transactions {
+					require.NoError(t, operation.InsertTransaction(transaction.ID(), transaction)(tx))
+					require.NoError(t, operation.IndexCollectionByTransaction(transaction.ID(), collection.ID())(tx))
+				}
+				require.NoError(t, operation.InsertExecutionReceiptMeta(receipt.ID(), receipt.Meta())(tx))
+				require.NoError(t, operation.IndexExecutionReceipts(headers[i-1].ID(), receipt.ID())(tx))
+				require.NoError(t, operation.InsertExecutionResult(&receipt.ExecutionResult)(tx))
+				return operation.IndexExecutionResult(headers[i-1].ID(), receipt.ExecutionResult.ID())(tx)
+			}))
+
+			headers = append(headers, &header)
+			collections = append(collections, &collection)
+			receipts = append(receipts, receipt)
+			seals = append(seals, seal)
+		}
+
+		for i := 1; i <= 4; i++ {
+			require.NoError(t, db.Update(PruneFinalizedBlock(headers[i], rootID)))
+		}
+
+		var pruned uint64
+		require.NoError(t, db.View(operation.RetrieveProtocolPrunedHeight(&pruned)))
+		assert.Equal(t, uint64(4), pruned)
+
+		// the pruned blocks and their collections are removed
+		for i := 1; i <= 6; i++ {
+			blockID := headers[i].ID()
+			collection := collections[i]
+
+			var header flow.Header
+			headerErr := db.View(operation.RetrieveHeader(blockID, &header))
+			var heightID flow.Identifier
+			heightErr := db.View(operation.LookupBlockHeight(headers[i].Height, &heightID))
+			var index flow.Index
+			indexErr := db.View(RetrieveIndex(blockID, &index))
+			var light flow.LightCollection
+			collectionErr := db.View(operation.RetrieveCollection(collection.ID(), &light))
+			var transaction flow.TransactionBody
+			transactionErr := db.View(operation.RetrieveTransaction(collection.Transactions[0].ID(), &transaction))
+			var guarantee flow.CollectionGuarantee
+			guaranteeErr := db.View(operation.RetrieveGuarantee(collection.ID(), &guarantee))
+
+			if i > 4 {
+				assert.NoError(t, headerErr)
+				assert.NoError(t, heightErr)
+				assert.NoError(t, indexErr)
+				assert.NoError(t, collectionErr)
+				assert.NoError(t, transactionErr)
+				assert.NoError(t, guaranteeErr)
+				continue
+			}
+			assert.ErrorIs(t, headerErr, storage.ErrNotFound, "block at height %d should be pruned", i)
+			assert.ErrorIs(t, heightErr, storage.ErrNotFound)
+			assert.Error(t, indexErr)
+			assert.ErrorIs(t, collectionErr, storage.ErrNotFound)
+			assert.ErrorIs(t, transactionErr, storage.ErrNotFound)
+			assert.ErrorIs(t, guaranteeErr, storage.ErrNotFound)
+
+			// the height of the pruned block remains indexed by its ID
+			var height uint64
+			require.NoError(t, db.View(operation.LookupPrunedBlockHeight(blockID, &height)))
+			assert.Equal(t, headers[i].Height, height)
+		}
+
+		// the root seal and the latest seal as of the last pruned block are kept
+		var seal flow.Seal
+		assert.NoError(t, db.View(operation.RetrieveSeal(rootSeal.ID(), &seal)))
+		assert.ErrorIs(t, db.View(operation.RetrieveSeal(seals[1].ID(), &seal)), storage.ErrNotFound)
+		assert.NoError(t, db.View(operation.RetrieveSeal(seals[2].ID(), &seal)))
+		var sealID flow.Identifier
+		assert.ErrorIs(t, db.View(operation.LookupBlockSeal(headers[3].ID(), &sealID)), storage.ErrNotFound)
+		require.NoError(t, db.View(operation.LookupBlockSeal(headers[4].ID(), &sealID)))
+		assert.Equal(t, seals[2].ID(), sealID)
+
+		// the receipts and results of the pruned blocks are kept until a block
+		// sealed after them is pruned
+		for i := 1; i <= 5; i++ {
+			receipt := receipts[i+1]
+			var resultID flow.Identifier
+			resultErr := db.View(operation.LookupExecutionResult(headers[i].ID(), &resultID))
+			var meta flow.ExecutionReceiptMeta
+			receiptErr := db.View(operation.RetrieveExecutionReceiptMeta(receipt.ID(), &meta))
+			var result flow.ExecutionResult
+			resultByIDErr := db.View(operation.RetrieveExecutionResult(receipt.ExecutionResult.ID(), &result))
+
+			if i > 1 {
+				assert.NoError(t, resultErr)
+				assert.NoError(t, receiptErr)
+				assert.NoError(t, resultByIDErr)
+				continue
+			}
+			assert.ErrorIs(t, resultErr, storage.ErrNotFound)
+			assert.ErrorIs(t, receiptErr, storage.ErrNotFound)
+			assert.ErrorIs(t, resultByIDErr, storage.ErrNotFound)
+		}
+
+		var heights []uint64
+		var blockIDs []flow.Identifier
+		require.NoError(t, db.View(operation.LookupPrunedBlocks(&heights, &blockIDs)))
+		assert.Equal(t, []uint64{2, 3, 4}, heights)
+	})
+}
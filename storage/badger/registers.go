@@ -0,0 +1,45 @@
+package badger
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// Registers implements the history of register values on top of badger.
+type Registers struct {
+	db *badger.DB
+}
+
+func NewRegisters(db *badger.DB) *Registers {
+	return &Registers{
+		db: db,
+	}
+}
+
+func (r *Registers) BatchStore(header *flow.Header, entries flow.RegisterEntries, batch storage.BatchStorage) error {
+	writeBatch := batch.GetWriter()
+	blockID := header.ID()
+	for _, entry := range entries {
+		err := operation.BatchIndexRegister(entry.Key, header.Height, blockID, entry.Value)(writeBatch)
+		if err != nil {
+			return err
+		}
+		err = operation.BatchIndexRegisterChange(entry.Key, header.Height, blockID)(writeBatch)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Registers) ByIDAtHeight(registerID flow.RegisterID, height uint64) (flow.RegisterValue, error) {
+	var value flow.RegisterValue
+	err := r.db.View(operation.LookupRegisterAtHeight(registerID, height, &value))
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,65 @@
+package badger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// SlashingEvidence implements persistent storage for the evidence of slashable
+// protocol violations. Violations are rare, hence the evidence is not cached.
+type SlashingEvidence struct {
+	db *badger.DB
+}
+
+func NewSlashingEvidence(db *badger.DB) *SlashingEvidence {
+	return &SlashingEvidence{
+		db: db,
+	}
+}
+
+// Store stores the evidence of a violation, unless evidence of the same
+// violation by the offender in the same view on the same chain is stored already.
+func (s *SlashingEvidence) Store(evidence *flow.SlashingEvidence) (bool, error) {
+	err := operation.RetryOnConflict(s.db.Update, operation.InsertSlashingEvidence(evidence))
+	if errors.Is(err, storage.ErrAlreadyExists) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not store slashing evidence: %w", err)
+	}
+	return true, nil
+}
+
+// ByOffender retrieves the evidence of all violations of the given offender.
+func (s *SlashingEvidence) ByOffender(offenderID flow.Identifier) ([]*flow.SlashingEvidence, error) {
+	var evidence []flow.SlashingEvidence
+	err := s.db.View(operation.LookupSlashingEvidenceByOffender(offenderID, &evidence))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve slashing evidence of offender: %w", err)
+	}
+	return evidenceList(evidence), nil
+}
+
+// All retrieves the evidence of all violations.
+func (s *SlashingEvidence) All() ([]*flow.SlashingEvidence, error) {
+	var evidence []flow.SlashingEvidence
+	err := s.db.View(operation.LookupSlashingEvidence(&evidence))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve slashing evidence: %w", err)
+	}
+	return evidenceList(evidence), nil
+}
+
+func evidenceList(evidence []flow.SlashingEvidence) []*flow.SlashingEvidence {
+	result := make([]*flow.SlashingEvidence, 0, len(evidence))
+	for i := range evidence {
+		result = append(result, &evidence[i])
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package badger_test
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestSlashingEvidenceStoreAndRetrieve(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := bstorage.NewSlashingEvidence(db)
+
+		offender := unittest.IdentityFixture(unittest.WithRole(flow.RoleConsensus))
+		doubleVote := unittest.SlashingEvidenceFixture(unittest.WithOffender(offender), unittest.WithViolationView(20))
+		earlier := unittest.SlashingEvidenceFixture(unittest.WithOffender(offender), unittest.WithViolationView(10))
+		other := unittest.SlashingEvidenceFixture()
+
+		for _, evidence := range []*flow.SlashingEvidence{doubleVote, earlier, other} {
+			stored, err := store.Store(evidence)
+			require.NoError(t, err)
+			require.True(t, stored)
+		}
+
+		byOffender, err := store.ByOffender(offender.NodeID)
+		require.NoError(t, err)
+		require.Equal(t, []*flow.SlashingEvidence{earlier, doubleVote}, byOffender)
+
+		all, err := store.All()
+		require.NoError(t, err)
+		require.Len(t, all, 3)
+	})
+}
+
+// TestSlashingEvidenceDeduplication checks that only the first evidence of a
+// violation by an offender in a view on a chain is kept.
+func TestSlashingEvidenceDeduplication(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := bstorage.NewSlashingEvidence(db)
+
+		offender := unittest.IdentityFixture(unittest.WithRole(flow.RoleConsensus))
+		first := unittest.SlashingEvidenceFixture(unittest.WithOffender(offender), unittest.WithViolationView(10))
+		second := unittest.SlashingEvidenceFixture(unittest.WithOffender(offender), unittest.WithViolationView(10))
+		invalidVote := unittest.SlashingEvidenceFixture(unittest.WithOffender(offender), unittest.WithViolationView(10))
+		invalidVote.Violation = flow.SlashingViolationInvalidVote
+		invalidVote.Votes = invalidVote.Votes[:1]
+		// cluster views start over with each epoch
+		otherChain := unittest.SlashingEvidenceFixture(unittest.WithOffender(offender), unittest.WithViolationView(10))
+		otherChain.ChainID = flow.ChainID("cluster-1")
+
+		stored, err := store.Store(first)
+		require.NoError(t, err)
+		require.True(t, stored)
+		stored, err = store.Store(second)
+		require.NoError(t, err)
+		require.False(t, stored)
+		stored, err = store.Store(invalidVote)
+		require.NoError(t, err)
+		require.True(t, stored)
+		stored, err = store.Store(otherChain)
+		require.NoError(t, err)
+		require.True(t, stored)
+
+		byOffender, err := store.ByOffender(offender.NodeID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []*flow.SlashingEvidence{first, invalidVote, otherChain}, byOffender)
+	})
+}
@@ -0,0 +1,65 @@
+package badger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// TransactionJournal implements persistent storage for the transactions which
+// are pending inclusion in a collection. The journal is only read on startup,
+// hence it is not cached.
+type TransactionJournal struct {
+	db *badger.DB
+}
+
+func NewTransactionJournal(db *badger.DB) *TransactionJournal {
+	return &TransactionJournal{
+		db: db,
+	}
+}
+
+// Add journals the transaction for the given epoch.
+func (j *TransactionJournal) Add(epoch uint64, tx *flow.TransactionBody) error {
+	err := operation.RetryOnConflict(j.db.Update, operation.SkipDuplicates(operation.InsertJournaledTransaction(epoch, tx)))
+	if err != nil {
+		return fmt.Errorf("could not journal transaction: %w", err)
+	}
+	return nil
+}
+
+// Remove removes the transaction from the journal of the given epoch.
+func (j *TransactionJournal) Remove(epoch uint64, txID flow.Identifier) error {
+	err := operation.RetryOnConflict(j.db.Update, operation.RemoveJournaledTransaction(epoch, txID))
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not remove journaled transaction: %w", err)
+	}
+	return nil
+}
+
+// Clear removes all transactions from the journal of the given epoch.
+func (j *TransactionJournal) Clear(epoch uint64) error {
+	err := operation.RetryOnConflict(j.db.Update, operation.RemoveJournaledTransactionsByEpoch(epoch))
+	if err != nil {
+		return fmt.Errorf("could not clear transaction journal: %w", err)
+	}
+	return nil
+}
+
+// All retrieves all journaled transactions, indexed by epoch.
+func (j *TransactionJournal) All() (map[uint64][]*flow.TransactionBody, error) {
+	transactions := make(map[uint64][]*flow.TransactionBody)
+	err := j.db.View(operation.LookupJournaledTransactions(transactions))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve journaled transactions: %w", err)
+	}
+	return transactions, nil
+}
@@ -0,0 +1,67 @@
+package badger_test
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestTransactionJournalAddAndRemove(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		journal := bstorage.NewTransactionJournal(db)
+
+		tx1 := unittest.TransactionBodyFixture()
+		tx2 := unittest.TransactionBodyFixture()
+		tx3 := unittest.TransactionBodyFixture()
+
+		require.NoError(t, journal.Add(1, &tx1))
+		require.NoError(t, journal.Add(1, &tx2))
+		require.NoError(t, journal.Add(2, &tx3))
+
+		// adding a journaled transaction again is a no-op
+		require.NoError(t, journal.Add(1, &tx1))
+
+		all, err := journal.All()
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		require.ElementsMatch(t, []*flow.TransactionBody{&tx1, &tx2}, all[1])
+		require.Equal(t, []*flow.TransactionBody{&tx3}, all[2])
+
+		require.NoError(t, journal.Remove(1, tx1.ID()))
+		// removing a transaction which is not journaled is a no-op
+		require.NoError(t, journal.Remove(1, tx1.ID()))
+		require.NoError(t, journal.Remove(1, tx3.ID()))
+
+		all, err = journal.All()
+		require.NoError(t, err)
+		require.Equal(t, []*flow.TransactionBody{&tx2}, all[1])
+		require.Equal(t, []*flow.TransactionBody{&tx3}, all[2])
+	})
+}
+
+// TestTransactionJournalClear checks that clearing the journal of an epoch
+// leaves the journals of other epochs untouched.
+func TestTransactionJournalClear(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		journal := bstorage.NewTransactionJournal(db)
+
+		tx1 := unittest.TransactionBodyFixture()
+		tx2 := unittest.TransactionBodyFixture()
+
+		require.NoError(t, journal.Add(1, &tx1))
+		require.NoError(t, journal.Add(2, &tx2))
+
+		require.NoError(t, journal.Clear(1))
+		// clearing an empty journal is a no-op
+		require.NoError(t, journal.Clear(3))
+
+		all, err := journal.All()
+		require.NoError(t, err)
+		require.Equal(t, map[uint64][]*flow.TransactionBody{2: {&tx2}}, all)
+	})
+}
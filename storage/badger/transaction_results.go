@@ -10,6 +10,7 @@ import (
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/storage/badger/transaction"
 )
 
 type TransactionResults struct {
@@ -99,3 +100,25 @@ func (tr *TransactionResults) ByBlockIDTransactionID(blockID flow.Identifier, tx
 	}
 	return &transactionResult, nil
 }
+
+// RemoveByBlockIDTx removes the transaction results for the given block ID in a
+// transaction, and evicts them from the cache once the transaction succeeded.
+func (tr *TransactionResults) RemoveByBlockIDTx(blockID flow.Identifier) func(*transaction.Tx) error {
+	return func(tx *transaction.Tx) error {
+		var results []flow.TransactionResult
+		err := operation.LookupTransactionResultsByBlockID(blockID, &results)(tx.DBTxn)
+		if err != nil {
+			return fmt.Errorf("could not look up transaction results: %w", err)
+		}
+		err = operation.RemoveTransactionResultsByBlockID(blockID)(tx.DBTxn)
+		if err != nil {
+			return fmt.Errorf("could not remove transaction results: %w", err)
+		}
+		tx.OnSucceed(func() {
+			for _, result := range results {
+				tr.cache.Remove(KeyFromBlockIDTransactionID(blockID, result.TransactionID))
+			}
+		})
+		return nil
+	}
+}
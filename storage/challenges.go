@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ChunkChallenges represents persistent storage for chunk challenges. A stored
+// challenge is unresolved until it is explicitly resolved.
+type ChunkChallenges interface {
+
+	// Store stores a chunk challenge, and indexes it as unresolved. Storing a
+	// challenge which is stored already is a no-op, in particular it does not
+	// re-open a resolved challenge.
+	Store(challenge *flow.ChunkChallenge) error
+
+	// ByID retrieves a chunk challenge by its ID.
+	ByID(challengeID flow.Identifier) (*flow.ChunkChallenge, error)
+
+	// Unresolved retrieves all chunk challenges which are not resolved yet.
+	Unresolved() ([]*flow.ChunkChallenge, error)
+
+	// Resolve marks a chunk challenge as resolved. The challenge itself is kept
+	// as evidence. Returns storage.ErrNotFound if the challenge is not stored
+	// or already resolved.
+	Resolve(challengeID flow.Identifier) error
+}
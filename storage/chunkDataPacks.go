@@ -2,6 +2,7 @@ package storage
 
 import (
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/badger/transaction"
 )
 
 // ChunkDataPacks represents persistent storage for chunk data packs.
@@ -16,6 +17,9 @@ type ChunkDataPacks interface {
 	// Remove removes the chunk data for the given chunk ID, if it exists.
 	Remove(chunkID flow.Identifier) error
 
+	// RemoveTx removes the chunk data for the given chunk ID in a transaction, if it exists.
+	RemoveTx(chunkID flow.Identifier) func(*transaction.Tx) error
+
 	// ByChunkID returns the chunk data for the given a chunk ID.
 	ByChunkID(chunkID flow.Identifier) (*flow.ChunkDataPack, error)
 }
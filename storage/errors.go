@@ -10,6 +10,10 @@ var (
 	// return storage.ErrNotFound for not found error
 	ErrNotFound = errors.New("key not found")
 
+	// ErrPruned is returned by lookups of finalized data which was deleted by
+	// pruning. It is distinct from ErrNotFound, as the data did exist.
+	ErrPruned = errors.New("data pruned")
+
 	ErrAlreadyExists = errors.New("key already exists")
 	ErrDataMismatch  = errors.New("data for key is different")
 )
@@ -1,6 +1,9 @@
 package storage
 
-import "github.com/onflow/flow-go/model/flow"
+import (
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/badger/transaction"
+)
 
 // Events represents persistent storage for events.
 type Events interface {
@@ -16,6 +19,9 @@ type Events interface {
 
 	// ByBlockIDEventType returns the events for the given block ID and event type
 	ByBlockIDEventType(blockID flow.Identifier, eventType flow.EventType) ([]flow.Event, error)
+
+	// RemoveByBlockIDTx removes the events for the given block ID in a transaction
+	RemoveByBlockIDTx(blockID flow.Identifier) func(*transaction.Tx) error
 }
 
 type ServiceEvents interface {
@@ -24,4 +30,7 @@ type ServiceEvents interface {
 
 	// ByBlockID returns the events for the given block ID
 	ByBlockID(blockID flow.Identifier) ([]flow.Event, error)
+
+	// RemoveByBlockIDTx removes the service events for the given block ID in a transaction
+	RemoveByBlockIDTx(blockID flow.Identifier) func(*transaction.Tx) error
 }
@@ -4,6 +4,7 @@ package mock
 
 import (
 	flow "github.com/onflow/flow-go/model/flow"
+	transaction "github.com/onflow/flow-go/storage/badger/transaction"
 	mock "github.com/stretchr/testify/mock"
 
 	storage "github.com/onflow/flow-go/storage"
@@ -78,3 +79,19 @@ func (_m *ChunkDataPacks) Store(c *flow.ChunkDataPack) error {
 
 	return r0
 }
+
+// RemoveTx provides a mock function with given fields: chunkID
+func (_m *ChunkDataPacks) RemoveTx(chunkID flow.Identifier) func(*transaction.Tx) error {
+	ret := _m.Called(chunkID)
+
+	var r0 func(*transaction.Tx) error
+	if rf, ok := ret.Get(0).(func(flow.Identifier) func(*transaction.Tx) error); ok {
+		r0 = rf(chunkID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func(*transaction.Tx) error)
+		}
+	}
+
+	return r0
+}
@@ -4,6 +4,7 @@ package mock
 
 import (
 	flow "github.com/onflow/flow-go/model/flow"
+	transaction "github.com/onflow/flow-go/storage/badger/transaction"
 	mock "github.com/stretchr/testify/mock"
 
 	storage "github.com/onflow/flow-go/storage"
@@ -96,3 +97,19 @@ func (_m *Events) ByBlockIDTransactionID(blockID flow.Identifier, transactionID
 
 	return r0, r1
 }
+
+// RemoveByBlockIDTx provides a mock function with given fields: blockID
+func (_m *Events) RemoveByBlockIDTx(blockID flow.Identifier) func(*transaction.Tx) error {
+	ret := _m.Called(blockID)
+
+	var r0 func(*transaction.Tx) error
+	if rf, ok := ret.Get(0).(func(flow.Identifier) func(*transaction.Tx) error); ok {
+		r0 = rf(blockID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func(*transaction.Tx) error)
+		}
+	}
+
+	return r0
+}
@@ -600,6 +600,20 @@ func (mr *MockEventsMockRecorder) ByBlockIDTransactionID(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ByBlockIDTransactionID", reflect.TypeOf((*MockEvents)(nil).ByBlockIDTransactionID), arg0, arg1)
 }
 
+// RemoveByBlockIDTx mocks base method
+func (m *MockEvents) RemoveByBlockIDTx(arg0 flow.Identifier) func(*transaction.Tx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveByBlockIDTx", arg0)
+	ret0, _ := ret[0].(func(*transaction.Tx) error)
+	return ret0
+}
+
+// RemoveByBlockIDTx indicates an expected call of RemoveByBlockIDTx
+func (mr *MockEventsMockRecorder) RemoveByBlockIDTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveByBlockIDTx", reflect.TypeOf((*MockEvents)(nil).RemoveByBlockIDTx), arg0)
+}
+
 // MockServiceEvents is a mock of ServiceEvents interface
 type MockServiceEvents struct {
 	ctrl     *gomock.Controller
@@ -652,6 +666,20 @@ func (mr *MockServiceEventsMockRecorder) ByBlockID(arg0 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ByBlockID", reflect.TypeOf((*MockServiceEvents)(nil).ByBlockID), arg0)
 }
 
+// RemoveByBlockIDTx mocks base method
+func (m *MockServiceEvents) RemoveByBlockIDTx(arg0 flow.Identifier) func(*transaction.Tx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveByBlockIDTx", arg0)
+	ret0, _ := ret[0].(func(*transaction.Tx) error)
+	return ret0
+}
+
+// RemoveByBlockIDTx indicates an expected call of RemoveByBlockIDTx
+func (mr *MockServiceEventsMockRecorder) RemoveByBlockIDTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveByBlockIDTx", reflect.TypeOf((*MockServiceEvents)(nil).RemoveByBlockIDTx), arg0)
+}
+
 // MockTransactionResults is a mock of TransactionResults interface
 type MockTransactionResults struct {
 	ctrl     *gomock.Controller
@@ -703,3 +731,17 @@ func (mr *MockTransactionResultsMockRecorder) ByBlockIDTransactionID(arg0, arg1
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ByBlockIDTransactionID", reflect.TypeOf((*MockTransactionResults)(nil).ByBlockIDTransactionID), arg0, arg1)
 }
+
+// RemoveByBlockIDTx mocks base method
+func (m *MockTransactionResults) RemoveByBlockIDTx(arg0 flow.Identifier) func(*transaction.Tx) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveByBlockIDTx", arg0)
+	ret0, _ := ret[0].(func(*transaction.Tx) error)
+	return ret0
+}
+
+// RemoveByBlockIDTx indicates an expected call of RemoveByBlockIDTx
+func (mr *MockTransactionResultsMockRecorder) RemoveByBlockIDTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveByBlockIDTx", reflect.TypeOf((*MockTransactionResults)(nil).RemoveByBlockIDTx), arg0)
+}
@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Registers represents persistent storage for the history of register values.
+type Registers interface {
+
+	// BatchStore indexes the register values written by the given block in a given batch.
+	BatchStore(header *flow.Header, entries flow.RegisterEntries, batch BatchStorage) error
+
+	// ByIDAtHeight retrieves the value of a register after executing the finalized
+	// block at the given height. It returns ErrNotFound if no finalized block up
+	// to the given height has written the register.
+	ByIDAtHeight(registerID flow.RegisterID, height uint64) (flow.RegisterValue, error)
+}
@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// SlashingEvidence represents persistent storage for the evidence of slashable
+// protocol violations. Evidence is deduplicated per offender, view, kind of
+// violation and chain, i.e. only the first evidence of a violation is kept.
+type SlashingEvidence interface {
+
+	// Store stores the evidence of a violation. It returns false, if evidence
+	// of the same violation by the offender in the same view on the same chain
+	// is stored already.
+	Store(evidence *flow.SlashingEvidence) (bool, error)
+
+	// ByOffender retrieves the evidence of all violations of the given
+	// offender, ordered by view.
+	ByOffender(offenderID flow.Identifier) ([]*flow.SlashingEvidence, error)
+
+	// All retrieves the evidence of all violations, ordered by offender and view.
+	All() ([]*flow.SlashingEvidence, error)
+}
@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// TransactionJournal represents persistent storage for the transactions a
+// collection node has accepted, but not yet included in a finalized collection.
+// Transactions are journaled per epoch, such that they can be restored into
+// the transaction pool of the respective epoch after a restart.
+type TransactionJournal interface {
+
+	// Add journals the transaction for the given epoch. Adding a transaction
+	// which is journaled already is a no-op.
+	Add(epoch uint64, tx *flow.TransactionBody) error
+
+	// Remove removes the transaction from the journal of the given epoch.
+	// Removing a transaction which is not journaled is a no-op.
+	Remove(epoch uint64, txID flow.Identifier) error
+
+	// Clear removes all transactions from the journal of the given epoch.
+	Clear(epoch uint64) error
+
+	// All retrieves all journaled transactions, indexed by epoch.
+	All() (map[uint64][]*flow.TransactionBody, error)
+}
@@ -1,6 +1,9 @@
 package storage
 
-import "github.com/onflow/flow-go/model/flow"
+import (
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/badger/transaction"
+)
 
 // TransactionResults represents persistent storage for transaction result
 type TransactionResults interface {
@@ -10,4 +13,7 @@ type TransactionResults interface {
 
 	// ByBlockIDTransactionID returns the transaction result for the given block ID and transaction ID
 	ByBlockIDTransactionID(blockID flow.Identifier, transactionID flow.Identifier) (*flow.TransactionResult, error)
+
+	// RemoveByBlockIDTx removes the transaction results for the given block ID in a transaction
+	RemoveByBlockIDTx(blockID flow.Identifier) func(*transaction.Tx) error
 }
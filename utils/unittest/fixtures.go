@@ -757,6 +757,87 @@ func ResultApprovalFixture(opts ...func(*flow.ResultApproval)) *flow.ResultAppro
 	return &approval
 }
 
+// WithChallengedResult sets the challenged execution result of a chunk challenge.
+func WithChallengedResult(result *flow.ExecutionResult) func(*flow.ChunkChallenge) {
+	return func(challenge *flow.ChunkChallenge) {
+		challenge.Body.BlockID = result.BlockID
+		challenge.Body.ExecutionResult = *result
+		challenge.Body.ChunkDataPackID = flow.ChunkDataPackFingerprint(ChunkDataPackFixture(result.Chunks[challenge.Body.ChunkIndex].ID()))
+	}
+}
+
+func ChunkChallengeFixture(opts ...func(*flow.ChunkChallenge)) *flow.ChunkChallenge {
+	result := ExecutionResultFixture()
+	receipt := ExecutionReceiptFixture(WithResult(result))
+
+	challenge := flow.ChunkChallenge{
+		Body: flow.ChunkChallengeBody{
+			ChallengerID:    IdentifierFixture(),
+			BlockID:         result.BlockID,
+			ExecutionResult: *result,
+			ChunkIndex:      0,
+			FaultType:       flow.ChunkFaultNonMatchingFinalState,
+			Fault:           "final state commitment doesn't match",
+			Receipts:        []*flow.ExecutionReceiptMeta{receipt.Meta()},
+			ChunkDataPackID: flow.ChunkDataPackFingerprint(ChunkDataPackFixture(result.Chunks[0].ID())),
+		},
+		ChallengerSignature: SignatureFixture(),
+	}
+
+	for _, apply := range opts {
+		apply(&challenge)
+	}
+
+	return &challenge
+}
+
+func WithOffender(offender *flow.Identity) func(*flow.SlashingEvidence) {
+	return func(evidence *flow.SlashingEvidence) {
+		evidence.OffenderID = offender.NodeID
+		evidence.Offender = offender
+		for _, vote := range evidence.Votes {
+			vote.SignerID = offender.NodeID
+		}
+	}
+}
+
+func WithViolationView(view uint64) func(*flow.SlashingEvidence) {
+	return func(evidence *flow.SlashingEvidence) {
+		evidence.View = view
+		for _, vote := range evidence.Votes {
+			vote.View = view
+		}
+	}
+}
+
+// SlashingEvidenceFixture returns the evidence of a double vote.
+func SlashingEvidenceFixture(opts ...func(*flow.SlashingEvidence)) *flow.SlashingEvidence {
+	offender := IdentityFixture(WithRole(flow.RoleConsensus))
+	view := rand.Uint64()
+
+	evidence := &flow.SlashingEvidence{
+		Violation:  flow.SlashingViolationDoubleVote,
+		ChainID:    flow.Emulator,
+		View:       view,
+		OffenderID: offender.NodeID,
+		Offender:   offender,
+	}
+	for i := 0; i < 2; i++ {
+		evidence.Votes = append(evidence.Votes, &flow.SlashingVote{
+			View:     view,
+			BlockID:  IdentifierFixture(),
+			SignerID: offender.NodeID,
+			SigData:  SignatureFixture(),
+		})
+	}
+
+	for _, apply := range opts {
+		apply(evidence)
+	}
+
+	return evidence
+}
+
 func StateCommitmentFixture() flow.StateCommitment {
 	var state flow.StateCommitment
 	_, _ = crand.Read(state[:])
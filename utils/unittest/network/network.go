@@ -30,6 +30,10 @@ func (c *Conduit) Publish(event interface{}, targetIDs ...flow.Identifier) error
 	panic("Publish called but no callback function was found.")
 }
 
+// ReportInvalidInput ignores reports of invalid input, as the mock network
+// doesn't penalize nodes.
+func (c *Conduit) ReportInvalidInput(flow.Identifier) {}
+
 // Network represents a mock network. The implementation is not concurrency-safe.
 type Network struct {
 	mockmodule.ReadyDoneAwareNetwork